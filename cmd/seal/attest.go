@@ -0,0 +1,130 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"seal/internal/seal"
+)
+
+// exitAttestPending is the exit code of seal attest verify for a proof not yet
+// committed to Bitcoin, like exitStillSealed for open: try again later.
+const exitAttestPending = 3
+
+func handleAttest(args []string) {
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, "error: expected: submit | verify | export")
+		printAttestUsage()
+		os.Exit(1)
+	}
+
+	switch args[0] {
+	case "submit":
+		handleAttestSubmit(args[1:])
+	case "verify":
+		handleAttestVerify(args[1:])
+	case "export":
+		handleAttestExport(args[1:])
+	default:
+		fmt.Fprintf(os.Stderr, "error: unknown attest command: %s\n", args[0])
+		printAttestUsage()
+		os.Exit(1)
+	}
+}
+
+func printAttestUsage() {
+	fmt.Fprintln(os.Stderr, "Usage: seal attest submit <id>")
+	fmt.Fprintln(os.Stderr, "       seal attest verify <id>")
+	fmt.Fprintln(os.Stderr, "       seal attest export --out <path> <id>")
+}
+
+// attestItemArg parses an attest subcommand's flags and returns its one item id.
+func attestItemArg(fs *flag.FlagSet, args []string) string {
+	fs.Usage = func() {
+		printAttestUsage()
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	remaining := fs.Args()
+	if len(remaining) == 0 {
+		fmt.Fprintln(os.Stderr, "error: item id is required")
+		fs.Usage()
+		os.Exit(1)
+	}
+	if len(remaining) > 1 {
+		fmt.Fprintln(os.Stderr, "error: too many arguments")
+		fs.Usage()
+		os.Exit(1)
+	}
+	return remaining[0]
+}
+
+func handleAttestSubmit(args []string) {
+	id := attestItemArg(flag.NewFlagSet("attest submit", flag.ExitOnError), args)
+
+	warnings, err := seal.AttestItem(id)
+	for _, warning := range warnings {
+		fmt.Fprintln(os.Stderr, warning)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+	os.Exit(0)
+}
+
+func handleAttestVerify(args []string) {
+	id := attestItemArg(flag.NewFlagSet("attest verify", flag.ExitOnError), args)
+
+	result, err := seal.VerifyAttestation(id)
+	for _, warning := range result.Warnings {
+		fmt.Fprintln(os.Stderr, warning)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Print(seal.FormatAttestationResult(result))
+	if !result.Confirmed {
+		fmt.Fprintln(os.Stderr, "error: attestation is not confirmed yet; calendars commit to bitcoin within a few hours, try again later")
+		os.Exit(exitAttestPending)
+	}
+	os.Exit(0)
+}
+
+func handleAttestExport(args []string) {
+	exportFlags := flag.NewFlagSet("attest export", flag.ExitOnError)
+	out := exportFlags.String("out", "", "write the OpenTimestamps proof to this new file")
+	id := attestItemArg(exportFlags, args)
+
+	if *out == "" {
+		fmt.Fprintln(os.Stderr, "error: --out is required")
+		os.Exit(1)
+	}
+
+	proof, err := seal.ExportAttestation(id)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+
+	// An existing file is never overwritten
+	file, err := os.OpenFile(*out, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: cannot create %s: %v\n", *out, err)
+		os.Exit(1)
+	}
+	if _, err := file.Write(proof); err != nil {
+		file.Close()
+		fmt.Fprintf(os.Stderr, "error: cannot write %s: %v\n", *out, err)
+		os.Exit(1)
+	}
+	if err := file.Close(); err != nil {
+		fmt.Fprintf(os.Stderr, "error: cannot write %s: %v\n", *out, err)
+		os.Exit(1)
+	}
+	os.Exit(0)
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"seal/internal/seal"
+	"seal/internal/timeauth"
+)
+
+func handleBench(args []string) {
+	benchFlags := flag.NewFlagSet("bench", flag.ExitOnError)
+	items := benchFlags.Int("items", seal.DefaultBenchItems, "number of items to lock and materialize")
+	size := benchFlags.Int("size", seal.DefaultBenchSize, "plaintext bytes per item")
+	workers := benchFlags.Int("workers", seal.DefaultBenchWorkers, "concurrent operations")
+
+	benchFlags.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: seal bench [--items <n>] [--size <bytes>] [--workers <n>]")
+		benchFlags.PrintDefaults()
+	}
+
+	benchFlags.Parse(args)
+
+	if len(benchFlags.Args()) > 0 {
+		fmt.Fprintln(os.Stderr, "error: too many arguments")
+		benchFlags.Usage()
+		os.Exit(1)
+	}
+
+	opts := seal.BenchOptions{Items: *items, Size: *size, Workers: *workers}
+	if err := seal.ValidateBenchOptions(opts); err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+
+	report, err := seal.RunBench(opts, timeauth.NewDefaultAuthority())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Print(seal.FormatBenchReport(report))
+	os.Exit(0)
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"seal/internal/testutil"
+)
+
+func TestAttestCommand_LockVerifyExport(t *testing.T) {
+	binPath := testutil.BuildSealBinary(t)
+	dataDir := t.TempDir()
+	env := append(os.Environ(), "HOME="+t.TempDir(), "XDG_DATA_HOME=", "SEAL_DATA_DIR="+dataDir)
+
+	// A calendar answering every digest with a pending attestation naming itself
+	var submitted atomic.Int32
+	calendar := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/digest" {
+			http.NotFound(w, r)
+			return
+		}
+		submitted.Add(1)
+		uri := "http://" + r.Host
+		payload := append([]byte{byte(len(uri))}, uri...)
+		stamp := append([]byte{0x00, 0x83, 0xdf, 0xe3, 0x0d, 0x2e, 0xf9, 0x0c, 0x8e, byte(len(payload))}, payload...)
+		w.Write(stamp)
+	}))
+	defer calendar.Close()
+	config := `{"attest": {"calendars": ["` + calendar.URL + `"]}}`
+	if err := os.WriteFile(filepath.Join(dataDir, "config.json"), []byte(config), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	lockCmd := exec.Command(binPath, "lock", "--attest", "--until", time.Now().UTC().Add(24*time.Hour).Format(time.RFC3339))
+	lockCmd.Stdin = strings.NewReader("my prediction")
+	lockCmd.Env = env
+	var lockStderr bytes.Buffer
+	lockCmd.Stderr = &lockStderr
+	lockOutput, err := lockCmd.Output()
+	if err != nil {
+		t.Fatalf("seal lock --attest failed: %v\nstderr: %s", err, lockStderr.String())
+	}
+	itemID := strings.TrimSpace(string(lockOutput))
+	if submitted.Load() != 1 || strings.Contains(lockStderr.String(), "attestation failed") {
+		t.Fatalf("expected one submission, got %d\nstderr: %s", submitted.Load(), lockStderr.String())
+	}
+
+	// Not yet in a bitcoin block: exit code 3
+	verifyCmd := exec.Command(binPath, "attest", "verify", itemID)
+	verifyCmd.Env = env
+	var stdout, stderr bytes.Buffer
+	verifyCmd.Stdout = &stdout
+	verifyCmd.Stderr = &stderr
+	var exitErr *exec.ExitError
+	if err := verifyCmd.Run(); !errors.As(err, &exitErr) || exitErr.ExitCode() != 3 {
+		t.Fatalf("expected exit code 3 for a pending attestation, got %v\nstderr: %s", err, stderr.String())
+	}
+	if !strings.HasPrefix(stdout.String(), "pending: ") || !strings.Contains(stdout.String(), "id: "+itemID) {
+		t.Errorf("unexpected output: %s", stdout.String())
+	}
+
+	// The proof is exported as an .ots file, never over an existing one
+	otsPath := filepath.Join(t.TempDir(), "payload.bin.ots")
+	for i, wantErr := range []bool{false, true} {
+		exportCmd := exec.Command(binPath, "attest", "export", "--out", otsPath, itemID)
+		exportCmd.Env = env
+		if err := exportCmd.Run(); (err != nil) != wantErr {
+			t.Errorf("run %d: seal attest export error = %v, want error %v", i, err, wantErr)
+		}
+	}
+	if data, err := os.ReadFile(otsPath); err != nil || !bytes.HasPrefix(data, []byte("\x00OpenTimestamps\x00\x00Proof\x00")) {
+		t.Errorf("unexpected proof file: %v", err)
+	}
+
+	for _, args := range [][]string{
+		{"attest"},
+		{"attest", "submit", itemID}, // already attested
+		{"attest", "export", itemID},
+		{"attest", "unknown", itemID},
+	} {
+		cmd := exec.Command(binPath, args...)
+		cmd.Env = env
+		if err := cmd.Run(); !errors.As(err, &exitErr) || exitErr.ExitCode() != 1 {
+			t.Errorf("%v: expected exit code 1, got %v", args, err)
+		}
+	}
+}
@@ -0,0 +1,37 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"seal/internal/testutil"
+)
+
+func TestBenchCommand_PrintsReport(t *testing.T) {
+	binPath := testutil.BuildSealBinary(t)
+	tmpHome := t.TempDir()
+
+	cmd := exec.Command(binPath, "bench", "--items", "2", "--size", "1024", "--workers", "2")
+	cmd.Env = append(os.Environ(), "HOME="+tmpHome, "XDG_DATA_HOME=")
+
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("seal bench failed: %v\n%s", err, output)
+	}
+
+	for _, want := range []string{"authority rtt:", "lock: 2 items", "materialize: 2 items"} {
+		if !strings.Contains(string(output), want) {
+			t.Errorf("expected %q in output: %s", want, output)
+		}
+	}
+
+	// Nothing is left in the user's store
+	statusCmd := exec.Command(binPath, "status")
+	statusCmd.Env = cmd.Env
+	statusOutput, _ := statusCmd.Output()
+	if strings.Contains(string(statusOutput), "state:") {
+		t.Errorf("bench left items in the store: %s", statusOutput)
+	}
+}
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"seal/internal/testutil"
+)
+
+func TestExportCommand_Public(t *testing.T) {
+	binPath := testutil.BuildSealBinary(t)
+	tmpHome := t.TempDir()
+
+	unlockTime := time.Now().UTC().Add(24 * time.Hour)
+	lockCmd := exec.Command(binPath, "lock", "--until", unlockTime.Format(time.RFC3339))
+	lockCmd.Stdin = strings.NewReader("test data")
+	lockCmd.Env = append(os.Environ(), "HOME="+tmpHome, "XDG_DATA_HOME=")
+
+	var lockStdout bytes.Buffer
+	lockCmd.Stdout = &lockStdout
+	if err := lockCmd.Run(); err != nil {
+		t.Fatalf("seal lock failed: %v", err)
+	}
+
+	itemID := strings.TrimSpace(lockStdout.String())
+
+	exportCmd := exec.Command(binPath, "export", "--public", itemID)
+	exportCmd.Env = append(os.Environ(), "HOME="+tmpHome, "XDG_DATA_HOME=")
+
+	var stdout, stderr bytes.Buffer
+	exportCmd.Stdout = &stdout
+	exportCmd.Stderr = &stderr
+	if err := exportCmd.Run(); err != nil {
+		t.Fatalf("seal export failed: %v\nstderr: %s", err, stderr.String())
+	}
+
+	var commitment map[string]any
+	if err := json.Unmarshal(stdout.Bytes(), &commitment); err != nil {
+		t.Fatalf("export output is not valid JSON: %v\n%s", err, stdout.String())
+	}
+
+	if commitment["id"] != itemID {
+		t.Errorf("expected id %s, got %v", itemID, commitment["id"])
+	}
+	for _, key := range []string{"ciphertext_sha256", "unlock_round", "chain_hash", "created_at"} {
+		if _, ok := commitment[key]; !ok {
+			t.Errorf("commitment should contain %s, got: %s", key, stdout.String())
+		}
+	}
+	if _, ok := commitment["dek_tlock_b64"]; ok {
+		t.Error("commitment must not contain the time-locked key")
+	}
+}
+
+func TestExportCommand_RequiresPublicOrOut(t *testing.T) {
+	binPath := testutil.BuildSealBinary(t)
+
+	cmd := exec.Command(binPath, "export", "00000000-0000-0000-0000-000000000000")
+	cmd.Env = append(os.Environ(), "HOME="+t.TempDir(), "XDG_DATA_HOME=")
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err == nil {
+		t.Fatal("expected export without --public to fail")
+	}
+
+	if !strings.Contains(stderr.String(), "--public or --out is required") {
+		t.Errorf("expected --public error, got: %s", stderr.String())
+	}
+}
+
+func TestExportCommand_BundleMovesItem(t *testing.T) {
+	binPath := testutil.BuildSealBinary(t)
+	sourceEnv := append(os.Environ(), "HOME="+t.TempDir(), "XDG_DATA_HOME=")
+	targetEnv := append(os.Environ(), "HOME="+t.TempDir(), "XDG_DATA_HOME=")
+
+	unlockTime := time.Now().UTC().Add(5 * time.Second).Format(time.RFC3339)
+	lockCmd := exec.Command(binPath, "lock", "--until", unlockTime)
+	lockCmd.Env = sourceEnv
+	lockCmd.Stdin = strings.NewReader("moving data")
+	lockOut, err := lockCmd.Output()
+	if err != nil {
+		t.Fatalf("seal lock failed: %v", err)
+	}
+	itemID := strings.TrimSpace(string(lockOut))
+
+	bundlePath := filepath.Join(t.TempDir(), "item.seal")
+	exportCmd := exec.Command(binPath, "export", "--out", bundlePath, itemID)
+	exportCmd.Env = sourceEnv
+	if output, err := exportCmd.CombinedOutput(); err != nil {
+		t.Fatalf("seal export --out failed: %v\n%s", err, output)
+	}
+
+	importCmd := exec.Command(binPath, "import", bundlePath)
+	importCmd.Env = targetEnv
+	importOut, err := importCmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("seal import failed: %v\n%s", err, importOut)
+	}
+	if !strings.Contains(string(importOut), "imported: "+bundlePath+" -> "+itemID) {
+		t.Errorf("unexpected import output: %s", importOut)
+	}
+
+	// The imported item unlocks on the other machine like the original would
+	openCmd := exec.Command(binPath, "open", itemID)
+	openCmd.Env = append(targetEnv, "SEAL_TESTMODE_DRAND_SKEW=10s")
+	var stdout, stderr bytes.Buffer
+	openCmd.Stdout = &stdout
+	openCmd.Stderr = &stderr
+	if err := openCmd.Run(); err != nil {
+		t.Fatalf("seal open failed: %v\nstderr: %s", err, stderr.String())
+	}
+	if stdout.String() != "moving data" {
+		t.Errorf("opened content = %q", stdout.String())
+	}
+}
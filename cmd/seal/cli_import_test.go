@@ -0,0 +1,103 @@
+package main
+
+import (
+	"bytes"
+	"encoding/base64"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"seal/internal/testutil"
+	"seal/internal/timeauth"
+	"seal/internal/timeauth/drandsim"
+)
+
+func TestImportCommand_ImportsTleFiles(t *testing.T) {
+	binPath := testutil.BuildSealBinary(t)
+	tmpHome := t.TempDir()
+
+	// Same chain the testmode binary simulates: the signing key is fixed
+	server, err := drandsim.Start(drandsim.Config{Period: 3 * time.Second, Genesis: 1677685200})
+	if err != nil {
+		t.Fatalf("failed to start drand simulator: %v", err)
+	}
+	defer server.Close()
+
+	box := &timeauth.RealTimelockBox{BaseURL: server.URL, ChainHash: server.ChainHash}
+	ciphertextB64, err := box.Encrypt([]byte("capsule"), server.LatestRound()+1000)
+	if err != nil {
+		t.Fatalf("tlock encrypt failed: %v", err)
+	}
+	data, _ := base64.StdEncoding.DecodeString(ciphertextB64)
+
+	dir := t.TempDir()
+	for name, content := range map[string][]byte{"a.tle": data, "b.tle": data, "notes.txt": []byte("plain")} {
+		if err := os.WriteFile(filepath.Join(dir, name), content, 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// A dry run reports the plan and stores nothing
+	dryRun := exec.Command(binPath, "import", "--dry-run", "--json", "--dir", dir)
+	dryRun.Env = append(os.Environ(), "HOME="+tmpHome, "XDG_DATA_HOME=")
+	dryRunOutput, _ := dryRun.Output()
+	if !strings.Contains(string(dryRunOutput), `"dry_run": true`) || !strings.Contains(string(dryRunOutput), `"action": "import"`) {
+		t.Errorf("expected a JSON dry-run plan, got: %s", dryRunOutput)
+	}
+	if _, err := os.Stat(filepath.Join(tmpHome, ".local", "share", "seal")); !os.IsNotExist(err) {
+		t.Errorf("dry run created the store: %v", err)
+	}
+
+	cmd := exec.Command(binPath, "import", "--dir", dir)
+	cmd.Env = append(os.Environ(), "HOME="+tmpHome, "XDG_DATA_HOME=")
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	err = cmd.Run()
+
+	// notes.txt is not a tlock file, so the run reports a failure
+	if err == nil {
+		t.Fatal("expected seal import to exit non-zero when a file fails")
+	}
+
+	output := stdout.String()
+	if !strings.Contains(output, "imported: "+filepath.Join(dir, "a.tle")) {
+		t.Errorf("expected a.tle to be imported, got: %s", output)
+	}
+	if !strings.Contains(output, "skipped: "+filepath.Join(dir, "b.tle")+" (duplicate of ") {
+		t.Errorf("expected b.tle to be skipped as a duplicate, got: %s", output)
+	}
+	if !strings.Contains(stderr.String(), "notes.txt: not a tlock ciphertext") {
+		t.Errorf("expected notes.txt to be reported, got: %s", stderr.String())
+	}
+
+	statusCmd := exec.Command(binPath, "status")
+	statusCmd.Env = append(os.Environ(), "HOME="+tmpHome, "XDG_DATA_HOME=")
+	statusOutput, err := statusCmd.Output()
+	if err != nil {
+		t.Fatalf("seal status failed: %v", err)
+	}
+	if strings.Count(string(statusOutput), "state: sealed") != 1 {
+		t.Errorf("expected one sealed item, got: %s", statusOutput)
+	}
+}
+
+func TestImportCommand_RequiresDirOrBundle(t *testing.T) {
+	binPath := testutil.BuildSealBinary(t)
+
+	cmd := exec.Command(binPath, "import")
+	cmd.Env = append(os.Environ(), "HOME="+t.TempDir(), "XDG_DATA_HOME=")
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err == nil {
+		t.Fatal("expected seal import without --dir to fail")
+	}
+	if !strings.Contains(stderr.String(), "--dir or a bundle is required") {
+		t.Errorf("expected --dir error, got: %s", stderr.String())
+	}
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+
+	"seal/internal/testutil"
+)
+
+func TestInspectCommand_ShowsMetadataAndHistory(t *testing.T) {
+	binPath := testutil.BuildSealBinary(t)
+	tmpHome := t.TempDir()
+
+	unlockTime := time.Now().UTC().Add(24 * time.Hour)
+	lockCmd := exec.Command(binPath, "lock", "--until", unlockTime.Format(time.RFC3339))
+	lockCmd.Stdin = strings.NewReader("test data")
+	lockCmd.Env = append(os.Environ(), "HOME="+tmpHome, "XDG_DATA_HOME=")
+
+	var lockStdout bytes.Buffer
+	lockCmd.Stdout = &lockStdout
+	if err := lockCmd.Run(); err != nil {
+		t.Fatalf("seal lock failed: %v", err)
+	}
+
+	itemID := strings.TrimSpace(lockStdout.String())
+
+	cmd := exec.Command(binPath, "inspect", "--history", itemID)
+	cmd.Env = append(os.Environ(), "HOME="+tmpHome, "XDG_DATA_HOME=")
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		t.Fatalf("seal inspect failed: %v\nstderr: %s", err, stderr.String())
+	}
+
+	output := stdout.String()
+	for _, want := range []string{"id: " + itemID, "state: sealed", "time_authority: drand", "history:", " created\n", "target_round: ", "current_round: ", "rounds_remaining: ", "time_remaining: ", "integrity: ok\n"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("expected %q in output, got: %s", want, output)
+		}
+	}
+
+	// Inspection is read-only and must not record a first check
+	if strings.Contains(output, "first_check") {
+		t.Errorf("inspect must not modify history, got: %s", output)
+	}
+}
+
+func TestInspectCommand_UnknownItem(t *testing.T) {
+	binPath := testutil.BuildSealBinary(t)
+
+	cmd := exec.Command(binPath, "inspect", "a1b2c3d4-5e6f-7890-abcd-ef1234567890")
+	cmd.Env = append(os.Environ(), "HOME="+t.TempDir(), "XDG_DATA_HOME=")
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err == nil {
+		t.Fatal("expected non-zero exit for unknown item")
+	}
+
+	if !strings.Contains(stderr.String(), "error: item not found") {
+		t.Errorf("unexpected stderr: %s", stderr.String())
+	}
+}
+
+func TestInspectCommand_ShowsNormalization(t *testing.T) {
+	binPath := testutil.BuildSealBinary(t)
+	tmpHome := t.TempDir()
+
+	lockCmd := exec.Command(binPath, "lock", "--until", "2027-12-31T23:59:59Z", "--strip-newline", "--encoding", "utf8")
+	lockCmd.Stdin = strings.NewReader("secret\n")
+	lockCmd.Env = append(os.Environ(), "HOME="+tmpHome, "XDG_DATA_HOME=")
+
+	var lockStdout bytes.Buffer
+	lockCmd.Stdout = &lockStdout
+	if err := lockCmd.Run(); err != nil {
+		t.Fatalf("seal lock failed: %v", err)
+	}
+
+	cmd := exec.Command(binPath, "inspect", strings.TrimSpace(lockStdout.String()))
+	cmd.Env = append(os.Environ(), "HOME="+tmpHome, "XDG_DATA_HOME=")
+
+	output, err := cmd.Output()
+	if err != nil {
+		t.Fatalf("seal inspect failed: %v", err)
+	}
+	if !strings.Contains(string(output), "normalization: utf8-validated,trailing-newline-removed\n") {
+		t.Errorf("expected normalization in output, got: %s", output)
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"seal/internal/seal"
 	"seal/internal/testutil"
@@ -23,7 +24,8 @@ func newTestDrandAuthority(currentRound uint64) *timeauth.DrandAuthority {
 			"/public/latest": testutil.MakeDrandPublicResponse(currentRound),
 		},
 	}
-	return timeauth.NewDrandAuthorityWithDeps(fakeHTTP, &testutil.FakeTimelockBox{})
+	network := timeauth.DrandNetwork{Name: "quicknet", ChainHash: testutil.DrandChainHash(), Relay: "https://api.drand.sh"}
+	return timeauth.NewDrandAuthorityForNetworkWithDeps(network, fakeHTTP, &testutil.FakeTimelockBox{})
 }
 
 func TestLockCommand_OutputContract_Success(t *testing.T) {
@@ -90,7 +92,19 @@ func TestLockCommand_OutputContract_Error(t *testing.T) {
 			name:    "missing --until flag",
 			args:    []string{"lock"},
 			stdin:   "test",
-			wantErr: "error: --until is required",
+			wantErr: "error: --until or --for is required",
+		},
+		{
+			name:    "both --until and --for",
+			args:    []string{"lock", "--until", "+1d", "--for", "1d"},
+			stdin:   "test",
+			wantErr: "error: --until and --for cannot be used together",
+		},
+		{
+			name:    "invalid duration",
+			args:    []string{"lock", "--for", "3x"},
+			stdin:   "test",
+			wantErr: "error: invalid lock duration",
 		},
 		{
 			name:    "invalid time format",
@@ -139,6 +153,77 @@ func TestLockCommand_OutputContract_Error(t *testing.T) {
 	}
 }
 
+func TestLockCommand_WhitespaceInput_RequiresAllowSmall(t *testing.T) {
+	binPath := testutil.BuildSealBinary(t)
+	tmpHome := t.TempDir()
+	until := "2027-12-31T23:59:59Z"
+
+	cmd := exec.Command(binPath, "lock", "--until", until)
+	cmd.Stdin = strings.NewReader("  \n")
+	cmd.Env = append(os.Environ(), "HOME="+tmpHome, "XDG_DATA_HOME=")
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err == nil {
+		t.Fatal("expected whitespace-only input to be rejected")
+	}
+	if !strings.Contains(stderr.String(), "error: input contains only whitespace") {
+		t.Errorf("unexpected stderr: %q", stderr.String())
+	}
+
+	cmd = exec.Command(binPath, "lock", "--until", until, "--allow-small")
+	cmd.Stdin = strings.NewReader("  \n")
+	cmd.Env = append(os.Environ(), "HOME="+tmpHome, "XDG_DATA_HOME=")
+
+	var stdout bytes.Buffer
+	cmd.Stdout = &stdout
+	if err := cmd.Run(); err != nil {
+		t.Fatalf("--allow-small should seal whitespace-only input: %v", err)
+	}
+	if !testutil.IsUUID(strings.TrimSpace(stdout.String())) {
+		t.Errorf("expected an item ID, got: %q", stdout.String())
+	}
+}
+
+func TestLockCommand_PasswordMode(t *testing.T) {
+	binPath := testutil.BuildSealBinary(t)
+	tmpHome := t.TempDir()
+	until := "2027-12-31T23:59:59Z"
+
+	cmd := exec.Command(binPath, "lock", "--until", until, "--password-mode")
+	cmd.Stdin = strings.NewReader("first line\nsecond line\n")
+	cmd.Env = append(os.Environ(), "HOME="+tmpHome, "XDG_DATA_HOME=")
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err == nil {
+		t.Fatal("expected multi-line input to be rejected in password mode")
+	}
+	if !strings.Contains(stderr.String(), "error: password spans multiple lines") {
+		t.Errorf("unexpected stderr: %q", stderr.String())
+	}
+
+	cmd = exec.Command(binPath, "lock", "--until", until, "--password-mode")
+	cmd.Stdin = strings.NewReader("hunter2\n")
+	cmd.Env = append(os.Environ(), "HOME="+tmpHome, "XDG_DATA_HOME=")
+
+	var stdout bytes.Buffer
+	stderr.Reset()
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		t.Fatalf("seal lock --password-mode failed: %v\nstderr: %s", err, stderr.String())
+	}
+	if !testutil.IsUUID(strings.TrimSpace(stdout.String())) {
+		t.Errorf("expected an item ID, got: %q", stdout.String())
+	}
+	for _, want := range []string{"warning: password is weak", "shell history"} {
+		if !strings.Contains(stderr.String(), want) {
+			t.Errorf("expected stderr to contain %q, got: %q", want, stderr.String())
+		}
+	}
+}
+
 func TestLockCommand_OutputContract_NoExtraOutput(t *testing.T) {
 	// This test ensures there are no warnings, informational messages,
 	// or any other output on success
@@ -540,3 +625,146 @@ func TestLockCommand_DefaultsToDrandAuthority(t *testing.T) {
 		t.Error("dek_tlock_b64 should not be empty for drand authority")
 	}
 }
+
+func TestLockCommand_LargeInputRoundTrip(t *testing.T) {
+	binPath := testutil.BuildSealBinary(t)
+	tmpHome := t.TempDir()
+	env := append(os.Environ(), "HOME="+tmpHome, "XDG_DATA_HOME=")
+
+	// Above the in-memory limit, from a file and from stdin
+	content := bytes.Repeat([]byte("large input\n"), seal.MaxInputSize/12+1000)
+	inputPath := filepath.Join(t.TempDir(), "large.bin")
+	if err := os.WriteFile(inputPath, content, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	unlockTime := time.Now().UTC().Add(5 * time.Second).Format(time.RFC3339)
+	for _, fromStdin := range []bool{false, true} {
+		lockCmd := exec.Command(binPath, "lock", "--until", unlockTime, inputPath)
+		if fromStdin {
+			lockCmd = exec.Command(binPath, "lock", "--until", unlockTime)
+			lockCmd.Stdin = bytes.NewReader(content)
+		}
+		lockCmd.Env = env
+		var lockStdout, lockStderr bytes.Buffer
+		lockCmd.Stdout = &lockStdout
+		lockCmd.Stderr = &lockStderr
+		if err := lockCmd.Run(); err != nil {
+			t.Fatalf("seal lock failed (stdin: %v): %v\nstderr: %s", fromStdin, err, lockStderr.String())
+		}
+		itemID := strings.TrimSpace(lockStdout.String())
+
+		openCmd := exec.Command(binPath, "open", itemID)
+		openCmd.Env = append(env, "SEAL_TESTMODE_DRAND_SKEW=10s")
+		var openStdout, openStderr bytes.Buffer
+		openCmd.Stdout = &openStdout
+		openCmd.Stderr = &openStderr
+		if err := openCmd.Run(); err != nil {
+			t.Fatalf("seal open failed (stdin: %v): %v\nstderr: %s", fromStdin, err, openStderr.String())
+		}
+		if !bytes.Equal(openStdout.Bytes(), content) {
+			t.Errorf("opened content differs from input (stdin: %v): got %d bytes, want %d", fromStdin, openStdout.Len(), len(content))
+		}
+	}
+}
+
+func TestLockCommand_ThresholdAuthorityRoundTrip(t *testing.T) {
+	binPath := testutil.BuildSealBinary(t)
+	tmpHome := t.TempDir()
+	env := append(os.Environ(), "HOME="+tmpHome, "XDG_DATA_HOME=")
+
+	unlockTime := time.Now().UTC().Add(5 * time.Second).Format(time.RFC3339)
+	lockCmd := exec.Command(binPath, "lock", "--until", unlockTime, "--authority", "multi",
+		"--member", "drand:quicknet", "--member", "drand:testnet", "--member", "drand", "--threshold", "2")
+	lockCmd.Env = env
+	lockCmd.Stdin = strings.NewReader("threshold secret")
+	var lockStdout, lockStderr bytes.Buffer
+	lockCmd.Stdout = &lockStdout
+	lockCmd.Stderr = &lockStderr
+	if err := lockCmd.Run(); err != nil {
+		t.Fatalf("seal lock failed: %v\nstderr: %s", err, lockStderr.String())
+	}
+	itemID := strings.TrimSpace(lockStdout.String())
+
+	inspectCmd := exec.Command(binPath, "inspect", itemID)
+	inspectCmd.Env = env
+	inspectOut, err := inspectCmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("seal inspect failed: %v\n%s", err, inspectOut)
+	}
+	for _, want := range []string{"time_authority: multi", "threshold: 2 of 3", "member: drand"} {
+		if !strings.Contains(string(inspectOut), want) {
+			t.Errorf("inspect output missing %q:\n%s", want, inspectOut)
+		}
+	}
+
+	openCmd := exec.Command(binPath, "open", itemID)
+	openCmd.Env = append(env, "SEAL_TESTMODE_DRAND_SKEW=10s")
+	var openStdout, openStderr bytes.Buffer
+	openCmd.Stdout = &openStdout
+	openCmd.Stderr = &openStderr
+	if err := openCmd.Run(); err != nil {
+		t.Fatalf("seal open failed: %v\nstderr: %s", err, openStderr.String())
+	}
+	if openStdout.String() != "threshold secret" {
+		t.Errorf("opened content = %q", openStdout.String())
+	}
+
+	// Members and threshold belong to the multi authority only
+	badCmd := exec.Command(binPath, "lock", "--for", "1h", "--member", "drand:testnet", "--threshold", "1")
+	badCmd.Env = env
+	badCmd.Stdin = strings.NewReader("secret")
+	if out, err := badCmd.CombinedOutput(); err == nil || !strings.Contains(string(out), "takes no members") {
+		t.Errorf("expected drand to refuse members, got %v: %s", err, out)
+	}
+}
+
+func TestLockCommand_StdinJSON(t *testing.T) {
+	binPath := testutil.BuildSealBinary(t)
+	tmpHome, cleanup := testutil.SetupTestEnv(t)
+	defer cleanup()
+
+	records := `{"data": "first"}
+{"data": "second", "for": "2d", "tags": ["n=2"]}
+{"until": "+1d"}
+`
+	cmd := exec.Command(binPath, "lock", "--stdin-json", "--for", "1d")
+	cmd.Stdin = strings.NewReader(records)
+	cmd.Env = append(os.Environ(), "HOME="+tmpHome, "XDG_DATA_HOME=")
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	// One failed record fails the command, after the others are sealed
+	if err := cmd.Run(); err == nil {
+		t.Fatalf("expected failure for a record without data\nstdout: %s", stdout.String())
+	}
+	if !strings.Contains(stderr.String(), "error: 1 records not sealed") {
+		t.Errorf("unexpected stderr: %q", stderr.String())
+	}
+
+	lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("expected a result line per record, got %q", stdout.String())
+	}
+	for i, line := range lines {
+		var result seal.BatchResult
+		if err := json.Unmarshal([]byte(line), &result); err != nil {
+			t.Fatalf("result line is not JSON: %q", line)
+		}
+		if result.Record != i+1 {
+			t.Errorf("line %d is for record %d", i+1, result.Record)
+		}
+		if sealed := i < 2; sealed != testutil.IsUUID(result.ID) || sealed != (result.Error == "") {
+			t.Errorf("unexpected result for record %d: %+v", i+1, result)
+		}
+	}
+
+	// Batch input replaces file and stdin input
+	cmd = exec.Command(binPath, "lock", "--stdin-json", "--for", "1d", "secret.txt")
+	cmd.Env = append(os.Environ(), "HOME="+tmpHome, "XDG_DATA_HOME=")
+	if out, err := cmd.CombinedOutput(); err == nil || !strings.Contains(string(out), "--stdin-json reads every item from stdin records") {
+		t.Errorf("expected --stdin-json with a path to be refused, got %v: %s", err, out)
+	}
+}
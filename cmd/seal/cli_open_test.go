@@ -0,0 +1,331 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"seal/internal/seal"
+	"seal/internal/testutil"
+	"seal/internal/timeauth/drandsim"
+)
+
+func TestOpenCommand_SealedThenUnlocked(t *testing.T) {
+	binPath := testutil.BuildSealBinary(t)
+	tmpHome := t.TempDir()
+	env := append(os.Environ(), "HOME="+tmpHome, "XDG_DATA_HOME=")
+
+	unlockTime := time.Now().UTC().Add(5 * time.Second)
+	lockCmd := exec.Command(binPath, "lock", "--until", unlockTime.Format(time.RFC3339))
+	lockCmd.Stdin = strings.NewReader("open me")
+	lockCmd.Env = env
+
+	var lockStdout bytes.Buffer
+	lockCmd.Stdout = &lockStdout
+	if err := lockCmd.Run(); err != nil {
+		t.Fatalf("seal lock failed: %v", err)
+	}
+	itemID := strings.TrimSpace(lockStdout.String())
+
+	// Before the unlock round: exit code 3 and no content
+	openCmd := exec.Command(binPath, "open", itemID)
+	openCmd.Env = env
+	var sealedStdout, sealedStderr bytes.Buffer
+	openCmd.Stdout = &sealedStdout
+	openCmd.Stderr = &sealedStderr
+	err := openCmd.Run()
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) || exitErr.ExitCode() != 3 {
+		t.Fatalf("expected exit code 3 for a sealed item, got %v\nstderr: %s", err, sealedStderr.String())
+	}
+	if sealedStdout.Len() != 0 || !strings.Contains(sealedStderr.String(), "still sealed") {
+		t.Errorf("unexpected output for sealed item: stdout %q, stderr %q", sealedStdout.String(), sealedStderr.String())
+	}
+
+	// With the simulated beacon past the unlock time, open materializes and prints the content
+	openCmd = exec.Command(binPath, "open", itemID)
+	openCmd.Env = append(env, "SEAL_TESTMODE_DRAND_SKEW=10s")
+	var openStdout, openStderr bytes.Buffer
+	openCmd.Stdout = &openStdout
+	openCmd.Stderr = &openStderr
+	if err := openCmd.Run(); err != nil {
+		t.Fatalf("seal open failed: %v\nstderr: %s", err, openStderr.String())
+	}
+	if openStdout.String() != "open me" {
+		t.Errorf("expected content on stdout, got %q", openStdout.String())
+	}
+
+	// --out writes a new file and never overwrites one
+	outPath := filepath.Join(t.TempDir(), "content.txt")
+	for i, wantErr := range []bool{false, true} {
+		outCmd := exec.Command(binPath, "open", "--out", outPath, itemID)
+		outCmd.Env = env
+		err := outCmd.Run()
+		if (err != nil) != wantErr {
+			t.Errorf("run %d: seal open --out error = %v, want error %v", i, err, wantErr)
+		}
+	}
+	if data, err := os.ReadFile(outPath); err != nil || string(data) != "open me" {
+		t.Errorf("unexpected --out content %q: %v", data, err)
+	}
+}
+
+func TestOpenCommand_Wait(t *testing.T) {
+	binPath := testutil.BuildSealBinary(t)
+	tmpHome := t.TempDir()
+	env := append(os.Environ(), "HOME="+tmpHome, "XDG_DATA_HOME=")
+
+	lockCmd := exec.Command(binPath, "lock", "--for", "5s")
+	lockCmd.Stdin = strings.NewReader("worth the wait")
+	lockCmd.Env = env
+	var lockStdout bytes.Buffer
+	lockCmd.Stdout = &lockStdout
+	if err := lockCmd.Run(); err != nil {
+		t.Fatalf("seal lock failed: %v", err)
+	}
+	itemID := strings.TrimSpace(lockStdout.String())
+
+	for _, args := range [][]string{
+		{"open", "--max-wait", "1m", itemID},
+		{"open", "--wait", "--beacon", "beacon.json", itemID},
+		{"open", "--wait", "--max-wait", "-1m", itemID},
+	} {
+		cmd := exec.Command(binPath, args...)
+		cmd.Env = env
+		var exitErr *exec.ExitError
+		if err := cmd.Run(); !errors.As(err, &exitErr) || exitErr.ExitCode() != 1 {
+			t.Errorf("%v: expected exit code 1, got %v", args, err)
+		}
+	}
+
+	// An item that will not unlock within --max-wait fails at once, as still sealed
+	start := time.Now()
+	waitCmd := exec.Command(binPath, "open", "--wait", "--max-wait", "1s", itemID)
+	waitCmd.Env = env
+	var sealedStderr bytes.Buffer
+	waitCmd.Stderr = &sealedStderr
+	var exitErr *exec.ExitError
+	if err := waitCmd.Run(); !errors.As(err, &exitErr) || exitErr.ExitCode() != 3 {
+		t.Fatalf("expected exit code 3, got %v\nstderr: %s", err, sealedStderr.String())
+	}
+	if elapsed := time.Since(start); elapsed > 3*time.Second || !strings.Contains(sealedStderr.String(), "will not unlock within 1s") {
+		t.Errorf("unexpected failure after %s: %s", elapsed, sealedStderr.String())
+	}
+
+	// Otherwise open waits for the round and prints the content
+	waitCmd = exec.Command(binPath, "open", "--wait", "--max-wait", "1m", itemID)
+	waitCmd.Env = env
+	var waitStdout, waitStderr bytes.Buffer
+	waitCmd.Stdout = &waitStdout
+	waitCmd.Stderr = &waitStderr
+	if err := waitCmd.Run(); err != nil {
+		t.Fatalf("seal open --wait failed: %v\nstderr: %s", err, waitStderr.String())
+	}
+	if waitStdout.String() != "worth the wait" {
+		t.Errorf("expected content on stdout, got %q", waitStdout.String())
+	}
+}
+
+func TestOpenCommand_Beacon(t *testing.T) {
+	binPath := testutil.BuildSealBinary(t)
+	tmpHome := t.TempDir()
+	env := append(os.Environ(), "HOME="+tmpHome, "XDG_DATA_HOME=")
+
+	lockCmd := exec.Command(binPath, "lock", "--for", "1h")
+	lockCmd.Stdin = strings.NewReader("air-gapped")
+	lockCmd.Env = env
+	lockOut, err := lockCmd.Output()
+	if err != nil {
+		t.Fatalf("seal lock failed: %v", err)
+	}
+	itemID := strings.TrimSpace(string(lockOut))
+
+	exportCmd := exec.Command(binPath, "export", "--public", itemID)
+	exportCmd.Env = env
+	exportOut, err := exportCmd.Output()
+	if err != nil {
+		t.Fatalf("seal export --public failed: %v", err)
+	}
+	var commitment seal.PublicCommitment
+	if err := json.Unmarshal(exportOut, &commitment); err != nil {
+		t.Fatalf("invalid commitment: %v", err)
+	}
+
+	// The same simulated chain the test binary serves
+	chain, err := drandsim.NewChain(drandsim.Config{Period: 3 * time.Second, Genesis: 1677685200})
+	if err != nil {
+		t.Fatal(err)
+	}
+	beaconFile := func(round uint64) string {
+		signed, err := chain.BeaconJSON(round)
+		if err != nil {
+			t.Fatal(err)
+		}
+		path := filepath.Join(t.TempDir(), "beacon.json")
+		if err := os.WriteFile(path, append(chain.InfoJSON(), signed...), 0600); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	// A round other than the unlock round is refused without touching the item
+	openCmd := exec.Command(binPath, "open", "--beacon", beaconFile(commitment.UnlockRound-1), itemID)
+	openCmd.Env = env
+	var stderr bytes.Buffer
+	openCmd.Stderr = &stderr
+	if err := openCmd.Run(); err == nil || !strings.Contains(stderr.String(), "but the beacon is for round") {
+		t.Fatalf("expected wrong round to fail, got %v\nstderr: %s", err, stderr.String())
+	}
+
+	// The simulator's key is public, so the test can sign the unlock round before it is due
+	openCmd = exec.Command(binPath, "open", "--beacon", beaconFile(commitment.UnlockRound), itemID)
+	openCmd.Env = env
+	stderr.Reset()
+	openCmd.Stderr = &stderr
+	out, err := openCmd.Output()
+	if err != nil {
+		t.Fatalf("seal open --beacon failed: %v\nstderr: %s", err, stderr.String())
+	}
+	if string(out) != "air-gapped" {
+		t.Errorf("expected content on stdout, got %q", out)
+	}
+}
+
+func TestOpenCommand_Armor(t *testing.T) {
+	binPath := testutil.BuildSealBinary(t)
+	env := append(os.Environ(), "HOME="+t.TempDir(), "XDG_DATA_HOME=")
+
+	lockCmd := exec.Command(binPath, "lock", "--armor", "--until", time.Now().UTC().Add(5*time.Second).Format(time.RFC3339))
+	lockCmd.Stdin = strings.NewReader("armored content")
+	lockCmd.Env = env
+	var armored, lockStderr bytes.Buffer
+	lockCmd.Stdout = &armored
+	lockCmd.Stderr = &lockStderr
+	if err := lockCmd.Run(); err != nil {
+		t.Fatalf("seal lock --armor failed: %v\nstderr: %s", err, lockStderr.String())
+	}
+	if !strings.HasPrefix(armored.String(), "-----BEGIN SEAL ITEM-----") || !strings.Contains(lockStderr.String(), "sealed: ") {
+		t.Fatalf("unexpected output: stdout %q, stderr %q", armored.String(), lockStderr.String())
+	}
+
+	// Opened on another machine, with an empty store
+	otherEnv := append(os.Environ(), "HOME="+t.TempDir(), "XDG_DATA_HOME=")
+	open := func(extraEnv ...string) (string, error) {
+		cmd := exec.Command(binPath, "open", "--armor")
+		cmd.Stdin = strings.NewReader("Forwarded message:\n" + armored.String())
+		cmd.Env = append(otherEnv, extraEnv...)
+		out, err := cmd.Output()
+		return string(out), err
+	}
+
+	var exitErr *exec.ExitError
+	if _, err := open(); !errors.As(err, &exitErr) || exitErr.ExitCode() != 3 {
+		t.Fatalf("expected exit code 3 before the unlock time, got %v", err)
+	}
+	out, err := open("SEAL_TESTMODE_DRAND_SKEW=10s")
+	if err != nil {
+		t.Fatalf("seal open --armor failed: %v", err)
+	}
+	if out != "armored content" {
+		t.Errorf("expected the content, got %q", out)
+	}
+
+	if cmd := exec.Command(binPath, "open", "--armor", "some-id"); cmd.Run() == nil {
+		t.Error("expected --armor with an id to be refused")
+	}
+}
+
+func TestOpenCommand_Identity(t *testing.T) {
+	binPath := testutil.BuildSealBinary(t)
+	env := append(os.Environ(), "HOME="+t.TempDir(), "XDG_DATA_HOME=")
+
+	keygenCmd := exec.Command(binPath, "keygen")
+	keygenCmd.Env = env
+	keygenOut, err := keygenCmd.Output()
+	if err != nil {
+		t.Fatalf("seal keygen failed: %v", err)
+	}
+	_, recipient, _ := strings.Cut(string(keygenOut), "recipient: ")
+	recipient = strings.TrimSpace(recipient)
+
+	lockCmd := exec.Command(binPath, "lock", "--recipient", recipient, "--until", time.Now().UTC().Add(5*time.Second).Format(time.RFC3339))
+	lockCmd.Stdin = strings.NewReader("time and key")
+	lockCmd.Env = env
+	lockOut, err := lockCmd.Output()
+	if err != nil {
+		t.Fatalf("seal lock --recipient failed: %v", err)
+	}
+	itemID := strings.TrimSpace(string(lockOut))
+
+	open := func(args ...string) (string, error) {
+		cmd := exec.Command(binPath, append([]string{"open"}, args...)...)
+		cmd.Env = append(env, "SEAL_TESTMODE_DRAND_SKEW=10s")
+		out, err := cmd.Output()
+		return string(out), err
+	}
+
+	// Without the identity the content stays encrypted to the recipient
+	out, err := open(itemID)
+	if err != nil || strings.Contains(out, "time and key") {
+		t.Fatalf("expected age ciphertext, got %q, %v", out, err)
+	}
+	out, err = open("--identity", "default", itemID)
+	if err != nil || out != "time and key" {
+		t.Errorf("expected the decrypted content, got %q, %v", out, err)
+	}
+	if _, err := open("--identity", "missing", itemID); err == nil {
+		t.Error("expected an unknown identity to fail")
+	}
+}
+
+func TestOpenCommand_Passphrase(t *testing.T) {
+	binPath := testutil.BuildSealBinary(t)
+	tmpDir := t.TempDir()
+	env := append(os.Environ(), "HOME="+tmpDir, "XDG_DATA_HOME=")
+
+	passphraseFile := filepath.Join(tmpDir, "passphrase")
+	if err := os.WriteFile(passphraseFile, []byte("correct horse battery staple\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	lockCmd := exec.Command(binPath, "lock", "--passphrase-file", passphraseFile, "--until", time.Now().UTC().Add(5*time.Second).Format(time.RFC3339))
+	lockCmd.Stdin = strings.NewReader("time and passphrase")
+	lockCmd.Env = env
+	lockOut, err := lockCmd.Output()
+	if err != nil {
+		t.Fatalf("seal lock --passphrase-file failed: %v", err)
+	}
+	itemID := strings.TrimSpace(string(lockOut))
+
+	open := func(args ...string) (string, string, error) {
+		cmd := exec.Command(binPath, append([]string{"open"}, args...)...)
+		cmd.Env = append(env, "SEAL_TESTMODE_DRAND_SKEW=10s")
+		var stderr bytes.Buffer
+		cmd.Stderr = &stderr
+		out, err := cmd.Output()
+		return string(out), stderr.String(), err
+	}
+
+	out, stderr, err := open(itemID)
+	if err == nil || out != "" || !strings.Contains(stderr, "protected by a passphrase") {
+		t.Fatalf("expected open without the passphrase to be refused, got %q, %q, %v", out, stderr, err)
+	}
+	out, _, err = open("--passphrase-file", passphraseFile, itemID)
+	if err != nil || out != "time and passphrase" {
+		t.Errorf("expected the decrypted content, got %q, %v", out, err)
+	}
+
+	wrongFile := filepath.Join(tmpDir, "wrong")
+	if err := os.WriteFile(wrongFile, []byte("wrong horse battery staple\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if out, stderr, err := open("--passphrase-file", wrongFile, itemID); err == nil || out != "" || !strings.Contains(stderr, "wrong passphrase") {
+		t.Errorf("expected a wrong passphrase to be refused, got %q, %q, %v", out, stderr, err)
+	}
+}
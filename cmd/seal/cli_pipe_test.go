@@ -0,0 +1,83 @@
+//go:build unix
+
+package main
+
+import (
+	"bufio"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"seal/internal/testutil"
+)
+
+func TestPipeCommand_SealsWritesAsItems(t *testing.T) {
+	binPath := testutil.BuildSealBinary(t)
+	tmpHome := t.TempDir()
+
+	fifoPath := filepath.Join(t.TempDir(), "drop")
+	if err := syscall.Mkfifo(fifoPath, 0600); err != nil {
+		t.Fatalf("mkfifo failed: %v", err)
+	}
+
+	unlockTime := time.Now().UTC().Add(24 * time.Hour)
+	cmd := exec.Command(binPath, "pipe", "--until", unlockTime.Format(time.RFC3339), "--fifo", fifoPath)
+	cmd.Env = append(os.Environ(), "HOME="+tmpHome, "XDG_DATA_HOME=")
+
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := cmd.Start(); err != nil {
+		t.Fatalf("failed to start seal pipe: %v", err)
+	}
+	defer func() {
+		cmd.Process.Kill()
+		cmd.Wait()
+	}()
+
+	scanner := bufio.NewScanner(stdout)
+	seen := make(map[string]bool)
+
+	for _, payload := range []string{"first", "second"} {
+		f, err := os.OpenFile(fifoPath, os.O_WRONLY, 0)
+		if err != nil {
+			t.Fatalf("failed to open fifo for writing: %v", err)
+		}
+		f.WriteString(payload)
+		f.Close()
+
+		if !scanner.Scan() {
+			t.Fatalf("expected an item ID on stdout: %v", scanner.Err())
+		}
+
+		id := scanner.Text()
+		if !testutil.IsUUID(id) {
+			t.Errorf("stdout line should be a UUID, got: %q", id)
+		}
+		if seen[id] {
+			t.Errorf("duplicate item ID: %s", id)
+		}
+		seen[id] = true
+	}
+}
+
+func TestPipeCommand_RequiresFIFO(t *testing.T) {
+	binPath := testutil.BuildSealBinary(t)
+
+	cmd := exec.Command(binPath, "pipe", "--until", "2027-12-31T23:59:59Z")
+	cmd.Env = append(os.Environ(), "HOME="+t.TempDir(), "XDG_DATA_HOME=")
+
+	output, err := cmd.CombinedOutput()
+	if err == nil {
+		t.Fatal("expected non-zero exit without --fifo")
+	}
+
+	if !strings.Contains(string(output), "error: --fifo is required") {
+		t.Errorf("unexpected output: %s", output)
+	}
+}
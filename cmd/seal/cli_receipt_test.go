@@ -0,0 +1,75 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"seal/internal/testutil"
+)
+
+func TestReceiptCommand_VerifyRoundTrip(t *testing.T) {
+	binPath := testutil.BuildSealBinary(t)
+	tmpHome := t.TempDir()
+	env := append(os.Environ(), "HOME="+tmpHome, "XDG_DATA_HOME=")
+
+	lockCmd := exec.Command(binPath, "lock", "--until", time.Now().UTC().Add(24*time.Hour).Format(time.RFC3339))
+	lockCmd.Stdin = strings.NewReader("my prediction")
+	lockCmd.Env = env
+	lockOutput, err := lockCmd.Output()
+	if err != nil {
+		t.Fatalf("seal lock failed: %v", err)
+	}
+	itemID := strings.TrimSpace(string(lockOutput))
+
+	receiptCmd := exec.Command(binPath, "receipt", itemID)
+	receiptCmd.Env = env
+	receipt, err := receiptCmd.Output()
+	if err != nil {
+		t.Fatalf("seal receipt failed: %v", err)
+	}
+	receiptPath := filepath.Join(t.TempDir(), "receipt.json")
+	if err := os.WriteFile(receiptPath, receipt, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	keyCmd := exec.Command(binPath, "receipt", "--public-key")
+	keyCmd.Env = env
+	key, err := keyCmd.Output()
+	if err != nil {
+		t.Fatalf("seal receipt --public-key failed: %v", err)
+	}
+
+	// A third party verifies without access to the store
+	verifyCmd := exec.Command(binPath, "verify-receipt", "--public-key", strings.TrimSpace(string(key)), receiptPath)
+	verifyCmd.Env = append(os.Environ(), "HOME="+t.TempDir(), "XDG_DATA_HOME=")
+	var stdout, stderr bytes.Buffer
+	verifyCmd.Stdout = &stdout
+	verifyCmd.Stderr = &stderr
+	if err := verifyCmd.Run(); err != nil {
+		t.Fatalf("seal verify-receipt failed: %v\nstderr: %s", err, stderr.String())
+	}
+	if !strings.HasPrefix(stdout.String(), "valid: signed by ") || !strings.Contains(stdout.String(), "id: "+itemID) {
+		t.Errorf("unexpected output: %s", stdout.String())
+	}
+
+	// A modified receipt is rejected
+	tampered := bytes.Replace(receipt, []byte(itemID), []byte("00000000-0000-0000-0000-000000000000"), 1)
+	if err := os.WriteFile(receiptPath, tampered, 0600); err != nil {
+		t.Fatal(err)
+	}
+	verifyCmd = exec.Command(binPath, "verify-receipt", receiptPath)
+	verifyCmd.Env = env
+	stderr.Reset()
+	verifyCmd.Stderr = &stderr
+	if err := verifyCmd.Run(); err == nil {
+		t.Fatal("expected a modified receipt to fail verification")
+	}
+	if !strings.Contains(stderr.String(), "signature is invalid") {
+		t.Errorf("unexpected stderr: %s", stderr.String())
+	}
+}
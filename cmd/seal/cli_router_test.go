@@ -0,0 +1,158 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"seal/internal/testutil"
+)
+
+func TestRouter_UnambiguousPrefix(t *testing.T) {
+	binPath := testutil.BuildSealBinary(t)
+
+	cmd := exec.Command(binPath, "vers", "--json")
+	cmd.Env = append(os.Environ(), "HOME="+t.TempDir(), "XDG_DATA_HOME=")
+
+	output, err := cmd.Output()
+	if err != nil {
+		t.Fatalf("seal vers failed: %v", err)
+	}
+	if !strings.Contains(string(output), `"version"`) {
+		t.Errorf("expected version JSON, got: %s", output)
+	}
+}
+
+func TestRouter_AmbiguousPrefix(t *testing.T) {
+	binPath := testutil.BuildSealBinary(t)
+
+	cmd := exec.Command(binPath, "s")
+	cmd.Env = append(os.Environ(), "HOME="+t.TempDir(), "XDG_DATA_HOME=")
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err == nil {
+		t.Fatal("expected an ambiguous prefix to fail")
+	}
+	if !strings.Contains(stderr.String(), "ambiguous command: s (could be simulate, status)") {
+		t.Errorf("unexpected stderr: %s", stderr.String())
+	}
+}
+
+func TestRouter_PrefixNeverRunsChangingCommands(t *testing.T) {
+	binPath := testutil.BuildSealBinary(t)
+	env := append(os.Environ(), "HOME="+t.TempDir(), "XDG_DATA_HOME=")
+
+	for _, prefix := range []string{"de", "m", "l", "relab", "imp"} {
+		cmd := exec.Command(binPath, prefix)
+		cmd.Env = env
+		var stderr bytes.Buffer
+		cmd.Stderr = &stderr
+		if err := cmd.Run(); err == nil {
+			t.Errorf("seal %s: expected a prefix of a changing command to be refused", prefix)
+		}
+		if !strings.HasPrefix(stderr.String(), "unknown command: "+prefix+" (") || !strings.Contains(stderr.String(), "cannot be abbreviated") {
+			t.Errorf("seal %s: unexpected stderr: %s", prefix, stderr.String())
+		}
+	}
+}
+
+func TestRouter_ConfigAlias(t *testing.T) {
+	binPath := testutil.BuildSealBinary(t)
+	tmpHome := t.TempDir()
+	env := append(os.Environ(), "HOME="+tmpHome, "XDG_DATA_HOME=")
+
+	setCmd := exec.Command(binPath, "config", "set", "alias.v", "version --json")
+	setCmd.Env = env
+	if output, err := setCmd.CombinedOutput(); err != nil {
+		t.Fatalf("seal config set failed: %v\n%s", err, output)
+	}
+
+	// The alias wins over the commands starting with "v"
+	cmd := exec.Command(binPath, "v")
+	cmd.Env = env
+	output, err := cmd.Output()
+	if err != nil {
+		t.Fatalf("seal v failed: %v", err)
+	}
+	if !strings.HasPrefix(strings.TrimSpace(string(output)), "{") {
+		t.Errorf("expected the alias to add --json, got: %s", output)
+	}
+
+	// Built-in commands cannot be shadowed
+	shadowCmd := exec.Command(binPath, "config", "set", "alias.status", "version")
+	shadowCmd.Env = env
+	if err := shadowCmd.Run(); err != nil {
+		t.Fatalf("seal config set failed: %v", err)
+	}
+
+	statusCmd := exec.Command(binPath, "status")
+	statusCmd.Env = env
+	statusOutput, err := statusCmd.Output()
+	if err != nil {
+		t.Fatalf("seal status failed: %v", err)
+	}
+	if strings.Contains(string(statusOutput), "version") {
+		t.Errorf("alias must not replace the status command, got: %s", statusOutput)
+	}
+}
+
+func TestRouter_UnknownCommand(t *testing.T) {
+	binPath := testutil.BuildSealBinary(t)
+
+	cmd := exec.Command(binPath, "frobnicate")
+	cmd.Env = append(os.Environ(), "HOME="+t.TempDir(), "XDG_DATA_HOME=")
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err == nil {
+		t.Fatal("expected an unknown command to fail")
+	}
+	if !strings.HasPrefix(stderr.String(), "unknown command: frobnicate\n") {
+		t.Errorf("unexpected stderr: %s", stderr.String())
+	}
+}
+
+func TestRouter_DataDir(t *testing.T) {
+	binPath := testutil.BuildSealBinary(t)
+	env := append(os.Environ(), "HOME="+t.TempDir(), "XDG_DATA_HOME=", "SEAL_DATA_DIR=")
+	dataDir := t.TempDir()
+
+	lockCmd := exec.Command(binPath, "--data-dir", dataDir, "lock", "--for", "1h")
+	lockCmd.Stdin = strings.NewReader("test data")
+	lockCmd.Env = env
+	out, err := lockCmd.Output()
+	if err != nil {
+		t.Fatalf("seal --data-dir lock failed: %v", err)
+	}
+	itemID := strings.TrimSpace(string(out))
+	if _, err := os.Stat(filepath.Join(dataDir, itemID, "meta.json")); err != nil {
+		t.Errorf("expected the item in the data directory: %v", err)
+	}
+
+	// The default store is untouched; the environment variable selects the same store
+	statusCmd := exec.Command(binPath, "status")
+	statusCmd.Env = env
+	out, _ = statusCmd.Output()
+	if strings.Contains(string(out), itemID) {
+		t.Errorf("item should not be in the default store, got: %s", out)
+	}
+
+	statusCmd = exec.Command(binPath, "status")
+	statusCmd.Env = append(env, "SEAL_DATA_DIR="+dataDir)
+	out, _ = statusCmd.Output()
+	if !strings.Contains(string(out), itemID) {
+		t.Errorf("expected the item with SEAL_DATA_DIR set, got: %s", out)
+	}
+
+	missing := exec.Command(binPath, "--data-dir")
+	missing.Env = env
+	var stderr bytes.Buffer
+	missing.Stderr = &stderr
+	if err := missing.Run(); err == nil || !strings.Contains(stderr.String(), "--data-dir requires a directory") {
+		t.Errorf("expected a missing directory to be refused, got %v: %s", err, stderr.String())
+	}
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+
+	"seal/internal/testutil"
+)
+
+func TestSimulateCommand_ReportsUnlockability(t *testing.T) {
+	binPath := testutil.BuildSealBinary(t)
+	tmpHome := t.TempDir()
+
+	unlockTime := time.Now().UTC().Add(24 * time.Hour)
+	lockCmd := exec.Command(binPath, "lock", "--until", unlockTime.Format(time.RFC3339))
+	lockCmd.Stdin = strings.NewReader("test data")
+	lockCmd.Env = append(os.Environ(), "HOME="+tmpHome, "XDG_DATA_HOME=")
+
+	var lockStdout bytes.Buffer
+	lockCmd.Stdout = &lockStdout
+	if err := lockCmd.Run(); err != nil {
+		t.Fatalf("seal lock failed: %v", err)
+	}
+
+	itemID := strings.TrimSpace(lockStdout.String())
+
+	testCases := []struct {
+		name string
+		at   time.Time
+		want string
+	}{
+		{"before unlock time", unlockTime.Add(-time.Hour), "unlockable: no"},
+		{"after unlock time", unlockTime.Add(time.Hour), "unlockable: yes"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cmd := exec.Command(binPath, "simulate", "--at", tc.at.Format(time.RFC3339), itemID)
+			cmd.Env = append(os.Environ(), "HOME="+tmpHome, "XDG_DATA_HOME=")
+
+			var stdout, stderr bytes.Buffer
+			cmd.Stdout = &stdout
+			cmd.Stderr = &stderr
+
+			if err := cmd.Run(); err != nil {
+				t.Fatalf("seal simulate failed: %v\nstderr: %s", err, stderr.String())
+			}
+
+			if !strings.Contains(stdout.String(), tc.want) {
+				t.Errorf("expected %q in output, got: %s", tc.want, stdout.String())
+			}
+		})
+	}
+
+	// Simulation must never materialize the item
+	statusCmd := exec.Command(binPath, "status")
+	statusCmd.Env = append(os.Environ(), "HOME="+tmpHome, "XDG_DATA_HOME=")
+
+	var statusStdout bytes.Buffer
+	statusCmd.Stdout = &statusStdout
+	if err := statusCmd.Run(); err != nil {
+		t.Fatalf("seal status failed: %v", err)
+	}
+
+	if !strings.Contains(statusStdout.String(), "state: sealed") {
+		t.Errorf("item should remain sealed after simulation, got: %s", statusStdout.String())
+	}
+}
+
+func TestSimulateCommand_Errors(t *testing.T) {
+	binPath := testutil.BuildSealBinary(t)
+
+	testCases := []struct {
+		name    string
+		args    []string
+		wantErr string
+	}{
+		{
+			name:    "missing id",
+			args:    []string{"simulate", "--at", "2027-01-01T00:00:00Z"},
+			wantErr: "error: item id is required",
+		},
+		{
+			name:    "missing --at flag",
+			args:    []string{"simulate", "a1b2c3d4-5e6f-7890-abcd-ef1234567890"},
+			wantErr: "error: --at is required",
+		},
+		{
+			name:    "invalid time format",
+			args:    []string{"simulate", "--at", "tomorrow", "a1b2c3d4-5e6f-7890-abcd-ef1234567890"},
+			wantErr: "error: invalid time format",
+		},
+		{
+			name:    "unknown item",
+			args:    []string{"simulate", "--at", "2027-01-01T00:00:00Z", "a1b2c3d4-5e6f-7890-abcd-ef1234567890"},
+			wantErr: "error: item not found",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tmpHome := t.TempDir()
+
+			cmd := exec.Command(binPath, tc.args...)
+			cmd.Env = append(os.Environ(), "HOME="+tmpHome, "XDG_DATA_HOME=")
+
+			var stderr bytes.Buffer
+			cmd.Stderr = &stderr
+
+			if err := cmd.Run(); err == nil {
+				t.Fatal("expected non-zero exit")
+			}
+
+			if !strings.Contains(stderr.String(), tc.wantErr) {
+				t.Errorf("expected %q in stderr, got: %s", tc.wantErr, stderr.String())
+			}
+		})
+	}
+}
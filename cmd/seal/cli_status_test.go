@@ -2,6 +2,9 @@ package main
 
 import (
 	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -61,9 +64,7 @@ func TestStatusCommand_AfterUnlock_ReportsUnlocked(t *testing.T) {
 	binPath := testutil.BuildSealBinary(t)
 	tmpHome := t.TempDir()
 
-	// Create a sealed item with near-future unlock time (will unlock quickly in test mode)
-	// Use a time 5 seconds in the future - drand rounds every 3 seconds, so this will be
-	// unlockable after 1-2 rounds
+	// Create a sealed item with near-future unlock time
 	unlockTime := time.Now().UTC().Add(5 * time.Second)
 	lockCmd := exec.Command(binPath, "lock", "--until", unlockTime.Format(time.RFC3339))
 	lockCmd.Stdin = strings.NewReader("test data for unlock")
@@ -77,12 +78,9 @@ func TestStatusCommand_AfterUnlock_ReportsUnlocked(t *testing.T) {
 
 	itemID := strings.TrimSpace(lockStdout.String())
 	
-	// Sleep to ensure the unlock time has passed (wait for drand rounds to catch up)
-	time.Sleep(6 * time.Second)
-
-	// Run seal status - should trigger materialization
+	// Run seal status with the simulated beacon ahead of the unlock time - should trigger materialization
 	statusCmd := exec.Command(binPath, "status")
-	statusCmd.Env = append(os.Environ(), "HOME="+tmpHome, "XDG_DATA_HOME=")
+	statusCmd.Env = append(os.Environ(), "HOME="+tmpHome, "XDG_DATA_HOME=", "SEAL_TESTMODE_DRAND_SKEW=10s")
 
 	var statusStdout bytes.Buffer
 	statusCmd.Stdout = &statusStdout
@@ -148,12 +146,9 @@ func TestStatusCommand_NoSpecialMessageOnUnlock(t *testing.T) {
 
 	itemID := strings.TrimSpace(lockStdout.String())
 	
-	// Sleep to ensure unlock
-	time.Sleep(6 * time.Second)
-
-	// Run seal status
+	// Run seal status with the simulated beacon past the unlock time
 	statusCmd := exec.Command(binPath, "status")
-	statusCmd.Env = append(os.Environ(), "HOME="+tmpHome, "XDG_DATA_HOME=")
+	statusCmd.Env = append(os.Environ(), "HOME="+tmpHome, "XDG_DATA_HOME=", "SEAL_TESTMODE_DRAND_SKEW=10s")
 
 	var statusStdout bytes.Buffer
 	statusCmd.Stdout = &statusStdout
@@ -186,3 +181,282 @@ func TestStatusCommand_NoSpecialMessageOnUnlock(t *testing.T) {
 		}
 	}
 }
+
+func TestStatusCommand_PrintsMaterializationSummary(t *testing.T) {
+	binPath := testutil.BuildSealBinary(t)
+	tmpHome := t.TempDir()
+
+	unlockTime := time.Now().UTC().Add(5 * time.Second)
+	lockCmd := exec.Command(binPath, "lock", "--until", unlockTime.Format(time.RFC3339))
+	lockCmd.Stdin = strings.NewReader("test data")
+	lockCmd.Env = append(os.Environ(), "HOME="+tmpHome, "XDG_DATA_HOME=")
+	if err := lockCmd.Run(); err != nil {
+		t.Fatalf("seal lock failed: %v", err)
+	}
+
+	runStatus := func() string {
+		statusCmd := exec.Command(binPath, "status")
+		statusCmd.Env = append(os.Environ(), "HOME="+tmpHome, "XDG_DATA_HOME=", "SEAL_TESTMODE_DRAND_SKEW=10s")
+
+		var stderr bytes.Buffer
+		statusCmd.Stderr = &stderr
+		if err := statusCmd.Run(); err != nil {
+			t.Fatalf("seal status failed: %v\nstderr: %s", err, stderr.String())
+		}
+		return stderr.String()
+	}
+
+	if stderr := runStatus(); stderr != "materialization: 1 checked, 0 not due, 1 unlocked, 0 failed\n" {
+		t.Errorf("unexpected stderr: %q", stderr)
+	}
+
+	// Nothing left to check: no summary
+	if stderr := runStatus(); stderr != "" {
+		t.Errorf("expected no summary once nothing is sealed, got: %q", stderr)
+	}
+}
+
+func TestStatusCommand_NDJSON_OneObjectPerItem(t *testing.T) {
+	binPath := testutil.BuildSealBinary(t)
+	tmpHome := t.TempDir()
+
+	unlockTime := time.Now().UTC().Add(365 * 24 * time.Hour)
+	ids := make(map[string]bool)
+	for i := 0; i < 2; i++ {
+		lockCmd := exec.Command(binPath, "lock", "--until", unlockTime.Format(time.RFC3339))
+		lockCmd.Stdin = strings.NewReader("test data")
+		lockCmd.Env = append(os.Environ(), "HOME="+tmpHome, "XDG_DATA_HOME=")
+
+		var lockStdout bytes.Buffer
+		lockCmd.Stdout = &lockStdout
+		if err := lockCmd.Run(); err != nil {
+			t.Fatalf("seal lock failed: %v", err)
+		}
+		ids[strings.TrimSpace(lockStdout.String())] = true
+	}
+
+	statusCmd := exec.Command(binPath, "status", "--ndjson")
+	statusCmd.Env = append(os.Environ(), "HOME="+tmpHome, "XDG_DATA_HOME=")
+
+	var statusStdout, statusStderr bytes.Buffer
+	statusCmd.Stdout = &statusStdout
+	statusCmd.Stderr = &statusStderr
+	if err := statusCmd.Run(); err != nil {
+		t.Fatalf("seal status --ndjson failed: %v\nstderr: %s", err, statusStderr.String())
+	}
+
+	lines := strings.Split(strings.TrimSpace(statusStdout.String()), "\n")
+	if len(lines) != len(ids) {
+		t.Fatalf("expected %d lines, got %d: %s", len(ids), len(lines), statusStdout.String())
+	}
+
+	for _, line := range lines {
+		var record struct {
+			ID               string `json:"id"`
+			State            string `json:"state"`
+			RemainingSeconds *int64 `json:"remaining_seconds"`
+		}
+		if err := json.Unmarshal([]byte(line), &record); err != nil {
+			t.Fatalf("line is not JSON: %v\n%s", err, line)
+		}
+		if !ids[record.ID] {
+			t.Errorf("unexpected item id %q", record.ID)
+		}
+		if record.State != "sealed" || record.RemainingSeconds == nil {
+			t.Errorf("expected sealed item with remaining time, got: %s", line)
+		}
+	}
+}
+
+func TestStatusCommand_CSV_Inventory(t *testing.T) {
+	binPath := testutil.BuildSealBinary(t)
+	tmpHome := t.TempDir()
+
+	unlockTime := time.Now().UTC().Add(365 * 24 * time.Hour)
+	lockCmd := exec.Command(binPath, "lock", "--until", unlockTime.Format(time.RFC3339))
+	lockCmd.Stdin = strings.NewReader("test data")
+	lockCmd.Env = append(os.Environ(), "HOME="+tmpHome, "XDG_DATA_HOME=")
+
+	var lockStdout bytes.Buffer
+	lockCmd.Stdout = &lockStdout
+	if err := lockCmd.Run(); err != nil {
+		t.Fatalf("seal lock failed: %v", err)
+	}
+	id := strings.TrimSpace(lockStdout.String())
+
+	statusCmd := exec.Command(binPath, "status", "--csv")
+	statusCmd.Env = append(os.Environ(), "HOME="+tmpHome, "XDG_DATA_HOME=")
+
+	var statusStdout, statusStderr bytes.Buffer
+	statusCmd.Stdout = &statusStdout
+	statusCmd.Stderr = &statusStderr
+	if err := statusCmd.Run(); err != nil {
+		t.Fatalf("seal status --csv failed: %v\nstderr: %s", err, statusStderr.String())
+	}
+
+	records, err := csv.NewReader(&statusStdout).ReadAll()
+	if err != nil {
+		t.Fatalf("output is not CSV: %v", err)
+	}
+	if len(records) != 2 {
+		t.Fatalf("expected header and one row, got %d records", len(records))
+	}
+	if records[0][0] != "id" || records[1][0] != id || records[1][1] != "sealed" || records[1][5] != "9" {
+		t.Errorf("unexpected inventory: %q", records)
+	}
+}
+
+func TestStatusCommand_TagFilter(t *testing.T) {
+	binPath := testutil.BuildSealBinary(t)
+	tmpHome := t.TempDir()
+	env := append(os.Environ(), "HOME="+tmpHome, "XDG_DATA_HOME=")
+
+	unlockTime := time.Now().UTC().Add(365 * 24 * time.Hour)
+	lock := func(tag string) string {
+		lockCmd := exec.Command(binPath, "lock", "--until", unlockTime.Format(time.RFC3339), "--tag", tag)
+		lockCmd.Stdin = strings.NewReader("test data")
+		lockCmd.Env = env
+
+		var lockStdout bytes.Buffer
+		lockCmd.Stdout = &lockStdout
+		if err := lockCmd.Run(); err != nil {
+			t.Fatalf("seal lock --tag %s failed: %v", tag, err)
+		}
+		return strings.TrimSpace(lockStdout.String())
+	}
+	alpha := lock("project=alpha")
+	beta := lock("project=beta")
+
+	statusCmd := exec.Command(binPath, "status", "--tag", "project=alpha")
+	statusCmd.Env = env
+
+	var statusStdout, statusStderr bytes.Buffer
+	statusCmd.Stdout = &statusStdout
+	statusCmd.Stderr = &statusStderr
+	if err := statusCmd.Run(); err != nil {
+		t.Fatalf("seal status --tag failed: %v\nstderr: %s", err, statusStderr.String())
+	}
+
+	out := statusStdout.String()
+	if !strings.Contains(out, alpha) || !strings.Contains(out, "tags: project=alpha") {
+		t.Errorf("expected item %s with its tag, got:\n%s", alpha, out)
+	}
+	if strings.Contains(out, beta) {
+		t.Errorf("item %s should be filtered out, got:\n%s", beta, out)
+	}
+}
+
+func TestStatusCommand_SortAndLimit(t *testing.T) {
+	binPath := testutil.BuildSealBinary(t)
+	tmpHome := t.TempDir()
+	env := append(os.Environ(), "HOME="+tmpHome, "XDG_DATA_HOME=")
+
+	lock := func(until string) string {
+		lockCmd := exec.Command(binPath, "lock", "--until", until)
+		lockCmd.Stdin = strings.NewReader("test data")
+		lockCmd.Env = env
+		out, err := lockCmd.Output()
+		if err != nil {
+			t.Fatalf("seal lock --until %s failed: %v", until, err)
+		}
+		return strings.TrimSpace(string(out))
+	}
+	later := lock("+60d")
+	sooner := lock("+30d")
+
+	status := func(args ...string) (string, string, error) {
+		cmd := exec.Command(binPath, append([]string{"status"}, args...)...)
+		cmd.Env = env
+		var stdout, stderr bytes.Buffer
+		cmd.Stdout = &stdout
+		cmd.Stderr = &stderr
+		err := cmd.Run()
+		return stdout.String(), stderr.String(), err
+	}
+
+	out, stderr, err := status("--sort", "unlock_time", "--limit", "1")
+	if err != nil {
+		t.Fatalf("seal status failed: %v\nstderr: %s", err, stderr)
+	}
+	if !strings.Contains(out, sooner) || strings.Contains(out, later) {
+		t.Errorf("expected only the sooner item %s, got:\n%s", sooner, out)
+	}
+
+	out, _, err = status("--after", "+45d", "--ndjson")
+	if err != nil || strings.Count(out, "\n") != 1 || !strings.Contains(out, later) {
+		t.Errorf("expected only the later item as NDJSON, got %v:\n%s", err, out)
+	}
+
+	out, _, _ = status("--state", "unlocked")
+	if strings.TrimSpace(out) != "no items match the filters" {
+		t.Errorf("expected no unlocked items, got:\n%s", out)
+	}
+
+	if _, stderr, err := status("--sort", "unlock_time", "--csv"); err == nil || !strings.Contains(stderr, "--sort cannot be used with --ndjson or --csv") {
+		t.Errorf("expected --sort with --csv to be refused, got %v: %s", err, stderr)
+	}
+	if _, stderr, err := status("--state", "open"); err == nil || !strings.Contains(stderr, "invalid state") {
+		t.Errorf("expected an invalid state to be refused, got %v: %s", err, stderr)
+	}
+	if _, stderr, err := status("--watch", "--ndjson"); err == nil || !strings.Contains(stderr, "--watch cannot be used with --ndjson or --csv") {
+		t.Errorf("expected --watch with --ndjson to be refused, got %v: %s", err, stderr)
+	}
+	if _, stderr, err := status("--interval", "5s"); err == nil || !strings.Contains(stderr, "--interval requires --watch") {
+		t.Errorf("expected --interval without --watch to be refused, got %v: %s", err, stderr)
+	}
+}
+
+func TestStatusCommand_Verify_ExitCodes(t *testing.T) {
+	binPath := testutil.BuildSealBinary(t)
+	dataDir := t.TempDir()
+	env := append(os.Environ(), "HOME="+t.TempDir(), "XDG_DATA_HOME=", "SEAL_DATA_DIR="+dataDir)
+
+	lockCmd := exec.Command(binPath, "lock", "--until", time.Now().UTC().Add(24*time.Hour).Format(time.RFC3339))
+	lockCmd.Stdin = strings.NewReader("monitored data")
+	lockCmd.Env = env
+	out, err := lockCmd.Output()
+	if err != nil {
+		t.Fatalf("seal lock failed: %v", err)
+	}
+	itemID := strings.TrimSpace(string(out))
+
+	status := func(args ...string) (string, int) {
+		cmd := exec.Command(binPath, append([]string{"status"}, args...)...)
+		cmd.Env = env
+		var stderr bytes.Buffer
+		cmd.Stderr = &stderr
+		err := cmd.Run()
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return stderr.String(), exitErr.ExitCode()
+		}
+		if err != nil {
+			t.Fatalf("seal status failed: %v", err)
+		}
+		return stderr.String(), 0
+	}
+
+	// The item's real time-locked key parses and its payload is intact
+	if stderr, code := status("--verify"); code != 0 || !strings.Contains(stderr, "verified 1 items, 0 failed") || strings.Contains(stderr, itemID) {
+		t.Errorf("expected a clean run, got exit code %d\nstderr: %s", code, stderr)
+	}
+
+	if err := os.WriteFile(filepath.Join(dataDir, itemID, "payload.bin"), []byte("flipped bits"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	for _, args := range [][]string{{"--verify"}, {"--verify", "--ndjson"}} {
+		stderr, code := status(args...)
+		if code != 2 || !strings.Contains(stderr, itemID+": failed\n  payload does not match") {
+			t.Errorf("%v: expected exit code 2 for a corrupted payload, got %d\nstderr: %s", args, code, stderr)
+		}
+	}
+
+	// Without --verify the corruption is not looked for
+	if stderr, code := status(); code != 0 {
+		t.Errorf("expected plain status to pass, got exit code %d\nstderr: %s", code, stderr)
+	}
+
+	if stderr, code := status("--watch", "--verify"); code != 1 || !strings.Contains(stderr, "--watch cannot be used with --verify") {
+		t.Errorf("expected --watch with --verify to be refused, got exit code %d\nstderr: %s", code, stderr)
+	}
+}
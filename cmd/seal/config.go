@@ -0,0 +1,50 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"seal/internal/seal"
+)
+
+func handleConfig(args []string) {
+	configFlags := flag.NewFlagSet("config", flag.ExitOnError)
+
+	configFlags.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: seal config set backup-exclusion|require-aad on|off")
+		fmt.Fprintln(os.Stderr, "       seal config set min-input-size <bytes>")
+		fmt.Fprintln(os.Stderr, "       seal config set shred-passes <n>")
+		fmt.Fprintln(os.Stderr, "       seal config set shred-pattern zero|random|alternating")
+		fmt.Fprintln(os.Stderr, "       seal config set alias.<name> \"<command> [args]\"")
+	}
+
+	configFlags.Parse(args)
+
+	remaining := configFlags.Args()
+
+	if len(remaining) != 3 || remaining[0] != "set" {
+		fmt.Fprintln(os.Stderr, "error: expected: set <key> <value>")
+		configFlags.Usage()
+		os.Exit(1)
+	}
+
+	key, value := remaining[1], remaining[2]
+
+	// Print mandatory warning when enabling backup exclusion
+	if key == "backup-exclusion" && value == "on" {
+		fmt.Fprintln(os.Stderr, "warning: backup exclusion markers are hints. backup tools and sync clients that ignore them will still copy the store, and existing backups are not changed.")
+	}
+
+	warnings, err := seal.SetConfigValue(key, value)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+
+	for _, warning := range warnings {
+		fmt.Fprintln(os.Stderr, warning)
+	}
+
+	os.Exit(0)
+}
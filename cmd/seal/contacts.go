@@ -0,0 +1,45 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"seal/internal/seal"
+)
+
+func handleContacts(args []string) {
+	contactsFlags := flag.NewFlagSet("contacts", flag.ExitOnError)
+
+	contactsFlags.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: seal contacts add <name> <recipient>")
+		fmt.Fprintln(os.Stderr, "       seal contacts list")
+	}
+
+	contactsFlags.Parse(args)
+
+	remaining := contactsFlags.Args()
+
+	switch {
+	case len(remaining) == 3 && remaining[0] == "add":
+		if err := seal.AddContact(remaining[1], remaining[2]); err != nil {
+			fmt.Fprintf(os.Stderr, "error: %v\n", err)
+			os.Exit(1)
+		}
+
+	case len(remaining) == 1 && remaining[0] == "list":
+		contacts, err := seal.ListContacts()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "error: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Print(seal.FormatContactList(contacts))
+
+	default:
+		fmt.Fprintln(os.Stderr, "error: expected: add <name> <recipient> | list")
+		contactsFlags.Usage()
+		os.Exit(1)
+	}
+
+	os.Exit(0)
+}
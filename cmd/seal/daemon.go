@@ -0,0 +1,50 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"seal/internal/seal"
+)
+
+func handleDaemon(args []string) {
+	daemonFlags := flag.NewFlagSet("daemon", flag.ExitOnError)
+	interval := daemonFlags.Duration("interval", seal.DefaultDaemonInterval, "longest time between status checks")
+	execProgram := daemonFlags.String("exec", "", "program to run for each item the daemon unlocks")
+	timeout := timeoutFlag(daemonFlags)
+
+	daemonFlags.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: seal daemon [--interval <duration>] [--exec <program>] [--timeout <duration>]")
+		daemonFlags.PrintDefaults()
+	}
+
+	daemonFlags.Parse(args)
+	applyTimeout(*timeout)
+
+	if daemonFlags.NArg() > 0 {
+		fmt.Fprintln(os.Stderr, "error: daemon takes no arguments")
+		daemonFlags.Usage()
+		os.Exit(1)
+	}
+
+	if *interval <= 0 {
+		fmt.Fprintln(os.Stderr, "error: invalid --interval, expected a positive duration such as 15m")
+		os.Exit(1)
+	}
+
+	// Runs until interrupted or a fatal error occurs
+	err := seal.RunDaemon(seal.DaemonRequest{
+		Interval: *interval,
+		Exec:     *execProgram,
+	}, func(item seal.SealedItem) {
+		fmt.Printf("unlocked: %s\n", item.ID)
+	}, func(msg string) {
+		fmt.Fprintln(os.Stderr, msg)
+	})
+
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+}
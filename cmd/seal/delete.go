@@ -0,0 +1,80 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"seal/internal/seal"
+)
+
+func handleDelete(args []string) {
+	deleteFlags := flag.NewFlagSet("delete", flag.ExitOnError)
+	force := deleteFlags.Bool("force", false, "also delete a still-sealed item, or one that fails validation")
+	paranoid := deleteFlags.Bool("paranoid", false, "rename each file to a random name before shredding it")
+	dryRun := deleteFlags.Bool("dry-run", false, "print the files that would be shredded, changing nothing")
+	jsonOutput := deleteFlags.Bool("json", false, "print the --dry-run plan as JSON")
+
+	deleteFlags.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: seal delete [--force] [--paranoid] [--dry-run [--json]] <id>")
+		deleteFlags.PrintDefaults()
+	}
+
+	deleteFlags.Parse(args)
+
+	remaining := deleteFlags.Args()
+
+	if len(remaining) == 0 {
+		fmt.Fprintln(os.Stderr, "error: item id is required")
+		deleteFlags.Usage()
+		os.Exit(1)
+	}
+
+	if len(remaining) > 1 {
+		fmt.Fprintln(os.Stderr, "error: too many arguments")
+		deleteFlags.Usage()
+		os.Exit(1)
+	}
+
+	if *jsonOutput && !*dryRun {
+		fmt.Fprintln(os.Stderr, "error: --json requires --dry-run")
+		os.Exit(1)
+	}
+
+	result, err := seal.Delete(remaining[0], seal.DeleteOptions{Force: *force, Paranoid: *paranoid, DryRun: *dryRun})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+
+	if *dryRun {
+		printPlan(result.Plan, result.Warnings, *jsonOutput)
+		os.Exit(0)
+	}
+
+	// Print mandatory warning: deletion shreds the item files
+	fmt.Fprintln(os.Stderr, "warning: file shredding on modern filesystems is best-effort only. backups, snapshots, wear leveling, and caches may retain data.")
+	for _, warning := range result.Warnings {
+		fmt.Fprintln(os.Stderr, warning)
+	}
+
+	fmt.Printf("deleted: %s\n", result.ID)
+	os.Exit(0)
+}
+
+// printPlan prints what a command did or would do, as text or JSON, and its warnings.
+func printPlan(plan seal.Plan, warnings []string, jsonOutput bool) {
+	for _, warning := range warnings {
+		fmt.Fprintln(os.Stderr, warning)
+	}
+	if !jsonOutput {
+		fmt.Print(seal.FormatPlan(plan))
+		return
+	}
+	output, err := seal.FormatPlanJSON(plan)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Print(output)
+}
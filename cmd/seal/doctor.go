@@ -0,0 +1,38 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"seal/internal/seal"
+)
+
+func handleDoctor(args []string) {
+	doctorFlags := flag.NewFlagSet("doctor", flag.ExitOnError)
+
+	doctorFlags.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: seal doctor")
+		doctorFlags.PrintDefaults()
+	}
+
+	doctorFlags.Parse(args)
+
+	if len(doctorFlags.Args()) > 0 {
+		fmt.Fprintln(os.Stderr, "error: too many arguments")
+		doctorFlags.Usage()
+		os.Exit(1)
+	}
+
+	findings, err := seal.Doctor()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Print(seal.FormatDoctorOutput(findings))
+	if len(findings) > 0 {
+		os.Exit(1)
+	}
+	os.Exit(0)
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"seal/internal/seal"
+)
+
+func handleExport(args []string) {
+	exportFlags := flag.NewFlagSet("export", flag.ExitOnError)
+	public := exportFlags.Bool("public", false, "export only the public commitment (no ciphertext or key)")
+	out := exportFlags.String("out", "", "write the sealed item to a new bundle file, to move it to another machine")
+
+	exportFlags.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: seal export --public <id>")
+		fmt.Fprintln(os.Stderr, "       seal export --out <bundle> <id>")
+		exportFlags.PrintDefaults()
+	}
+
+	exportFlags.Parse(args)
+
+	remaining := exportFlags.Args()
+
+	if len(remaining) == 0 {
+		fmt.Fprintln(os.Stderr, "error: item id is required")
+		exportFlags.Usage()
+		os.Exit(1)
+	}
+
+	if len(remaining) > 1 {
+		fmt.Fprintln(os.Stderr, "error: too many arguments")
+		exportFlags.Usage()
+		os.Exit(1)
+	}
+
+	if *public && *out != "" {
+		fmt.Fprintln(os.Stderr, "error: --public and --out cannot be used together")
+		os.Exit(1)
+	}
+
+	if !*public && *out == "" {
+		fmt.Fprintln(os.Stderr, "error: --public or --out is required")
+		exportFlags.Usage()
+		os.Exit(1)
+	}
+
+	if *out != "" {
+		if err := seal.ExportBundle(remaining[0], *out); err != nil {
+			fmt.Fprintf(os.Stderr, "error: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("exported: %s -> %s\n", remaining[0], *out)
+		os.Exit(0)
+	}
+
+	commitment, err := seal.ExportPublic(remaining[0])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+
+	output, err := seal.FormatPublicCommitmentJSON(commitment)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Print(output)
+	os.Exit(0)
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"seal/internal/seal"
+	"seal/internal/timeauth"
+)
+
+func handleImport(args []string) {
+	importFlags := flag.NewFlagSet("import", flag.ExitOnError)
+	dir := importFlags.String("dir", "", "directory of tlock (tle) files to import")
+	dryRun := importFlags.Bool("dry-run", false, "check every file and print what would be imported, storing nothing")
+	jsonOutput := importFlags.Bool("json", false, "print the --dry-run plan as JSON")
+
+	importFlags.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: seal import [--dry-run [--json]] --dir <dir>")
+		fmt.Fprintln(os.Stderr, "       seal import [--dry-run [--json]] <bundle>")
+		importFlags.PrintDefaults()
+	}
+
+	importFlags.Parse(args)
+
+	remaining := importFlags.Args()
+
+	if len(remaining) > 1 {
+		fmt.Fprintln(os.Stderr, "error: too many arguments")
+		importFlags.Usage()
+		os.Exit(1)
+	}
+
+	if *jsonOutput && !*dryRun {
+		fmt.Fprintln(os.Stderr, "error: --json requires --dry-run")
+		os.Exit(1)
+	}
+	opts := seal.ImportOptions{DryRun: *dryRun}
+
+	if len(remaining) == 1 {
+		if *dir != "" {
+			fmt.Fprintln(os.Stderr, "error: --dir and a bundle cannot be used together")
+			os.Exit(1)
+		}
+		importBundle(remaining[0], opts, *jsonOutput)
+	}
+
+	if *dir == "" {
+		fmt.Fprintln(os.Stderr, "error: --dir or a bundle is required")
+		importFlags.Usage()
+		os.Exit(1)
+	}
+
+	results, err := seal.ImportDir(*dir, timeauth.NewDefaultAuthority(), opts)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+
+	failed := 0
+	for _, result := range results {
+		if opts.DryRun {
+			if result.Status == seal.ImportStatusFailed {
+				failed++
+			}
+			continue
+		}
+		switch result.Status {
+		case seal.ImportStatusImported:
+			fmt.Printf("imported: %s -> %s\n", result.Path, result.ID)
+		case seal.ImportStatusDuplicate:
+			fmt.Printf("skipped: %s (duplicate of %s)\n", result.Path, result.ID)
+		default:
+			failed++
+			fmt.Fprintf(os.Stderr, "error: %s: %v\n", result.Path, result.Err)
+		}
+	}
+
+	if opts.DryRun {
+		printPlan(seal.ImportPlan(results, opts), nil, *jsonOutput)
+	}
+
+	// Every file is attempted; a failure on one does not undo the others
+	if failed > 0 {
+		os.Exit(1)
+	}
+	os.Exit(0)
+}
+
+// importBundle imports a bundle written by seal export --out and exits.
+func importBundle(path string, opts seal.ImportOptions, jsonOutput bool) {
+	result, err := seal.ImportBundle(path, opts)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+
+	if opts.DryRun {
+		plan := seal.Plan{DryRun: true, Changes: []seal.Change{{Action: seal.ChangeImport, Path: path, ItemID: result.ID}}}
+		printPlan(plan, result.Warnings, jsonOutput)
+		os.Exit(0)
+	}
+
+	for _, warning := range result.Warnings {
+		fmt.Fprintln(os.Stderr, warning)
+	}
+	fmt.Printf("imported: %s -> %s\n", path, result.ID)
+	os.Exit(0)
+}
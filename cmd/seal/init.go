@@ -0,0 +1,43 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"seal/internal/seal"
+	"seal/internal/timeauth"
+)
+
+func handleInit(args []string) {
+	initFlags := flag.NewFlagSet("init", flag.ExitOnError)
+	identity := initFlags.String("identity", "", "also generate an identity with this name")
+	timeout := timeoutFlag(initFlags)
+
+	initFlags.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: seal init [--identity <name>] [--timeout <duration>]")
+		initFlags.PrintDefaults()
+	}
+
+	initFlags.Parse(args)
+	applyTimeout(*timeout)
+
+	if len(initFlags.Args()) > 0 {
+		fmt.Fprintln(os.Stderr, "error: too many arguments")
+		initFlags.Usage()
+		os.Exit(1)
+	}
+
+	result, err := seal.Init(*identity, timeauth.NewDefaultAuthority())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+
+	for _, warning := range result.Warnings {
+		fmt.Fprintln(os.Stderr, warning)
+	}
+
+	fmt.Print(seal.FormatInitResult(result))
+	os.Exit(0)
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"seal/internal/output"
+	"seal/internal/seal"
+)
+
+func handleInspect(args []string) {
+	inspectFlags := flag.NewFlagSet("inspect", flag.ExitOnError)
+	history := inspectFlags.Bool("history", false, "show recorded item history")
+	jsonOutput := inspectFlags.Bool("json", false, "print metadata as JSON")
+	color := inspectFlags.String("color", "auto", "color output: auto, always or never")
+
+	inspectFlags.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: seal inspect [--history] [--json] [--color auto|always|never] <id>")
+		inspectFlags.PrintDefaults()
+	}
+
+	inspectFlags.Parse(args)
+
+	remaining := inspectFlags.Args()
+
+	if len(remaining) == 0 {
+		fmt.Fprintln(os.Stderr, "error: item id is required")
+		inspectFlags.Usage()
+		os.Exit(1)
+	}
+
+	if len(remaining) > 1 {
+		fmt.Fprintln(os.Stderr, "error: too many arguments")
+		inspectFlags.Usage()
+		os.Exit(1)
+	}
+
+	colorMode, err := output.ParseColorMode(*color)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+
+	item, err := seal.Inspect(remaining[0])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+
+	if *jsonOutput {
+		data, err := seal.FormatInspectJSON(item)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "error: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Print(data)
+		os.Exit(0)
+	}
+
+	// Round math needs the beacon; metadata is shown regardless
+	var rounds *seal.RoundDetails
+	details, err := seal.InspectRounds(item)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "warning: round details not shown: %v\n", err)
+	} else {
+		rounds = &details
+	}
+
+	problems, err := seal.CheckIntegrity(item.ID)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+
+	style := output.NewStyler(colorMode, os.Stdout)
+	fmt.Print(style.Fields(seal.FormatInspectOutput(item, rounds, *history) + seal.FormatIntegrity(problems)))
+	os.Exit(0)
+}
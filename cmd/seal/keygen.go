@@ -0,0 +1,130 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"seal/internal/seal"
+)
+
+func handleKeygen(args []string) {
+	keygenFlags := flag.NewFlagSet("keygen", flag.ExitOnError)
+	name := keygenFlags.String("name", seal.DefaultIdentityName, "name of the new identity")
+
+	keygenFlags.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: seal keygen [--name <name>]")
+		keygenFlags.PrintDefaults()
+	}
+
+	keygenFlags.Parse(args)
+
+	if len(keygenFlags.Args()) > 0 {
+		fmt.Fprintln(os.Stderr, "error: too many arguments")
+		keygenFlags.Usage()
+		os.Exit(1)
+	}
+
+	identity, err := seal.GenerateIdentity(*name)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+
+	// Mandatory warning: there is no recovery for a lost private key
+	fmt.Fprintf(os.Stderr, "warning: the private key is stored only in %s. if it is lost, content sealed to this identity can never be read. back it up with seal identity export --secret %s.\n", identity.Path, identity.Name)
+
+	fmt.Printf("name: %s\nrecipient: %s\n", identity.Name, identity.Recipient)
+	os.Exit(0)
+}
+
+func handleIdentity(args []string) {
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, "error: expected: list | export <name>")
+		printIdentityUsage()
+		os.Exit(1)
+	}
+
+	switch args[0] {
+	case "list":
+		handleIdentityList(args[1:])
+	case "export":
+		handleIdentityExport(args[1:])
+	default:
+		fmt.Fprintf(os.Stderr, "error: unknown identity command: %s\n", args[0])
+		printIdentityUsage()
+		os.Exit(1)
+	}
+}
+
+func printIdentityUsage() {
+	fmt.Fprintln(os.Stderr, "Usage: seal identity list")
+	fmt.Fprintln(os.Stderr, "       seal identity export [--secret] <name>")
+}
+
+func handleIdentityList(args []string) {
+	listFlags := flag.NewFlagSet("identity list", flag.ExitOnError)
+	listFlags.Usage = printIdentityUsage
+	listFlags.Parse(args)
+
+	if len(listFlags.Args()) > 0 {
+		fmt.Fprintln(os.Stderr, "error: too many arguments")
+		printIdentityUsage()
+		os.Exit(1)
+	}
+
+	identities, err := seal.ListIdentities()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Print(seal.FormatIdentityList(identities))
+	os.Exit(0)
+}
+
+func handleIdentityExport(args []string) {
+	exportFlags := flag.NewFlagSet("identity export", flag.ExitOnError)
+	secret := exportFlags.Bool("secret", false, "print the full identity file, including the private key")
+
+	exportFlags.Usage = func() {
+		printIdentityUsage()
+		exportFlags.PrintDefaults()
+	}
+
+	exportFlags.Parse(args)
+
+	remaining := exportFlags.Args()
+
+	if len(remaining) == 0 {
+		fmt.Fprintln(os.Stderr, "error: identity name is required")
+		exportFlags.Usage()
+		os.Exit(1)
+	}
+
+	if len(remaining) > 1 {
+		fmt.Fprintln(os.Stderr, "error: too many arguments")
+		exportFlags.Usage()
+		os.Exit(1)
+	}
+
+	if !*secret {
+		identity, err := seal.LoadIdentity(remaining[0])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "error: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Println(identity.Recipient)
+		os.Exit(0)
+	}
+
+	data, err := seal.ExportIdentitySecret(remaining[0])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Fprintln(os.Stderr, "warning: this output contains the private key. anyone who has it can read content sealed to this identity.")
+	os.Stdout.Write(data)
+	os.Exit(0)
+}
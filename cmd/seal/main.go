@@ -1,73 +1,321 @@
 package main
 
 import (
+	"encoding/json"
 	"flag"
 	"fmt"
 	"os"
+	"strings"
+	"time"
 
+	"seal/internal/output"
 	"seal/internal/seal"
+	"seal/internal/timeauth"
 )
 
 const usageText = `seal - irreversible time-locked commitment primitive
 
 Usage:
-  seal lock <path> --until <time> [--shred]
-  seal lock --until <time> [--clear-clipboard]  (reads from stdin)
-  seal status
+  seal [--data-dir <dir>] <command> [options]
+  seal init [--identity <name>]
+  seal lock <path> --until <time> | --for <duration> [--shred] [--reveal-to <target>]
+  seal lock --until <time> | --for <duration> [--clear-clipboard]  (reads from stdin)
+  seal lock --from-pass <entry> --until <time> | --for <duration>
+  seal lock --stdin-json [--until <time> | --for <duration>]  (one item per JSON record)
+  seal lock --armor [<path>] --until <time> | --for <duration>  (prints a block to paste)
+  seal status [--ndjson | --csv] [--tag <key=value>]... [--state <state>] [--before <time>]
+              [--after <time>] [--sort <order>] [--limit <n>] [--color <mode>]
+  seal status --watch [--interval <duration>]  (refreshes until interrupted)
+  seal status --verify [--ndjson | --csv]  (also checks every item, for cron and monitoring)
+  seal inspect [--history] [--json] [--color <mode>] <id>
+  seal relabel [--tag <key=value>]... [--untag <key>]... [--note <text>] <id>
+  seal open [--out <path>] [--identity <name|file>] [--passphrase-file <path>] [--beacon <file|json>] <id>
+  seal open --armor [--out <path>] [--beacon <file|json>]  (reads the block from stdin)
+  seal open --wait [--max-wait <duration>] [--out <path>] <id>  (waits for a sealed item to unlock)
+  seal delete [--force] [--paranoid] [--dry-run [--json]] <id>
+  seal simulate --at <time> <id>
+  seal doctor
+  seal verify [<id>]
+  seal export --public <id>
+  seal export --out <bundle> <id>
+  seal receipt <id>
+  seal receipt --public-key
+  seal verify-receipt [--public-key <key>] <file>
+  seal attest submit|verify <id>
+  seal attest export --out <path> <id>
+  seal import [--dry-run [--json]] --dir <dir>
+  seal import [--dry-run [--json]] <bundle>
+  seal pipe --until <time> --fifo <path>
+  seal watch-folder --until-rel <duration> [--shred] <dir>
+  seal daemon [--interval <duration>] [--exec <program>]
+  seal config set backup-exclusion|require-aad on|off
+  seal config set min-input-size <bytes>
+  seal config set shred-passes <n>
+  seal config set shred-pattern zero|random|alternating
+  seal config set alias.<name> "<command> [args]"
+  seal move-store [--shred-old] <new-dir>
+  seal keygen [--name <name>]
+  seal identity list
+  seal identity export [--secret] <name>
+  seal contacts add <name> <recipient>
+  seal contacts list
+  seal bench [--items <n>] [--size <bytes>] [--workers <n>]
+  seal version [--json]
 
 Options:
-  --until <time>         RFC3339 timestamp for unlock time
-  --shred                best-effort file shredding (file input only)
+  --data-dir <dir>       use <dir> as the store instead of the default; same as SEAL_DATA_DIR
+                         (before the command)
+  --identity <name>      init: also generate an identity; open: decrypt an item sealed with
+                         --recipient using this stored identity or age identity file
+  --until <time>         RFC3339 timestamp for unlock time, or +<duration> from now (e.g. +7d)
+  --for <duration>       unlock this long from now: 72h, 3d, 2w, 1mo, 1w2d (lock only)
+  --authority <name>     time authority to seal with (lock only, default drand)
+  --network <name>       drand network: quicknet (default) or testnet (lock only)
+  --chain-hash <hex>     lock to a custom drand chain instead of a named network (lock only)
+  --relay <url>          drand relay to use instead of the network's default (lock only)
+  --member <spec>        threshold member, e.g. drand:quicknet (with --authority multi; repeatable)
+  --threshold <k>        members needed to unlock (with --authority multi)
+  --shred                best-effort file shredding (file input and watch-folder)
+  --shred-passes <n>     overwrite passes for --shred, 1 to 35 (lock only, default from config)
+  --shred-pattern <p>    overwrite pattern for --shred: zero, random or alternating (lock only)
   --clear-clipboard      best-effort clipboard clearing (stdin only)
-
+  --reveal-to <target>   deliver content on unlock (mailto:<address>, pass:<entry>,
+                         k8s:<namespace>/<name>[/<key>])
+  --from-pass <entry>    read input from a pass store entry (lock only)
+  --notify <sinks>       comma-separated notification sinks from config
+  --on-unlock-exec <program>
+                         run once on unlock, with SEAL_ITEM_ID and SEAL_UNSEALED_PATH set (lock only)
+  --on-unlock-webhook <url>
+                         post a JSON event once on unlock (lock only)
+  --stdin                always read input from stdin (lock only)
+  --no-stdin             never read stdin, for cron and services (lock only)
+  --stdin-json           seal each {"data"|"path", "until"|"for", "tags"} record read from
+                         stdin as NDJSON or a JSON array, printing a JSON result line each (lock only)
+  --armor                lock: also print the sealed item as an ASCII-armored block;
+                         open: unlock a block read from stdin without storing it
+  --allow-small          seal whitespace-only or below-minimum input (lock only)
+  --password-mode        seal a single password without its trailing newline (lock only)
+  --strip-newline        remove one trailing newline from stdin or pass input (lock only)
+  --encoding <enc>       stdin or pass input encoding: raw (default) or utf8 (lock only)
+  --recipient <who>      age-encrypt content to a contact or age1... key before sealing
+  --passphrase-file <path>
+                         lock: also require the passphrase on the first line of <path> (e.g.
+                         /dev/fd/3) to read the content after unlock; open: read it from <path>
+  --direct-tlock         time-lock content up to 64 KiB directly, without a data key (lock only)
+  --vault-wrap <key>     also wrap the key with a Vault transit key (e.g. transit/keys/foo)
+  --post-process <steps> steps run on content after unlock (gunzip, untar, age-decrypt:<identity>)
+  --immutable            best-effort immutable attribute on item files
+  --attest               timestamp the ciphertext hash with OpenTimestamps (lock only)
+  --unseal-to <dir>      write unlocked content to <dir>/<id> instead of the store
+  --retain-unsealed <d>  shred unsealed content this long after unlock (e.g. 7d)
+  --ndjson               stream one JSON object per item (status only)
+  --csv                  print an inventory as CSV for spreadsheets (status only)
+  --tag <key=value>      lock: store a tag in metadata; status: only show items with it;
+                         relabel: add or replace it (repeatable)
+  --untag <key>          remove a tag (relabel only, repeatable)
+  --note <text>          one-line description shown by status and inspect (lock and relabel)
+  --state <state>        only show sealed or unlocked items (status only)
+  --before <time>        only show items unlocking before an RFC3339 time or +<duration> (status only)
+  --after <time>         only show items unlocking after an RFC3339 time or +<duration> (status only)
+  --sort <order>         created_at (default) or unlock_time (status only)
+  --limit <n>            show at most n items (status only)
+  --color <mode>         auto (default, honors NO_COLOR), always or never (status and inspect)
+  --history              show recorded item history (inspect only)
+  --json                 print metadata as JSON (inspect and version);
+                         print the plan as JSON (delete and import, with --dry-run)
+  --out <path>           open: write content to a new file instead of stdout;
+                         export: write the sealed item to a new bundle file
+  --beacon <file|json>   unlock offline with drand chain info and the item's round (open only)
+  --wait                 wait for a still-sealed item to unlock, until interrupted (open only)
+  --max-wait <duration>  with --wait, fail at once if the item will not unlock within this long (open only)
+  --force                delete a still-sealed item or one that fails validation (delete only)
+  --paranoid             rename each file to a random name before shredding it (delete only)
+  --dry-run              print what would change without changing anything (delete and import)
+  --at <time>            RFC3339 timestamp to simulate (simulate only)
+  --public               print only the public commitment as JSON (export only)
+  --public-key           print the key receipts are signed with (receipt)
+  --public-key <key>     require receipts signed by this key (verify-receipt)
+  --dir <dir>            directory of tlock files to import (import only)
+  --fifo <path>          named pipe to seal writes from (pipe only)
+  --until-rel <duration> unlock delay for each dropped file (watch-folder only)
+  --interval <duration>  longest time between checks (daemon, default 15m; watch-folder scans, default 2s;
+                         status --watch refreshes, default 10s)
+  --watch                refresh the display until interrupted (status only)
+  --verify               also verify every item; exit 2 on a problem, 3 if the time authority
+                         is unreachable (status only)
+  --exec <program>       run for each item the daemon unlocks, with SEAL_ITEM_ID and
+                         SEAL_UNSEALED_PATH set (daemon only)
+  --timeout <duration>   give up on each time authority request after this long, including
+                         retries (init, lock, status, open and daemon; default 30s)
+  --name <name>          identity name (keygen only, default "default")
+  --secret               include the private key (identity export only)
+  --shred-old            best-effort shredding of the old store (move-store only)
+  --items <n>            items to lock and materialize (bench only, default 20)
+  --size <bytes>         plaintext bytes per item (bench only, default 65536)
+  --workers <n>          concurrent operations (bench only, default 4)
+
+seal init prepares the store, config and time authority explicitly.
 seal lock encrypts data until a specified future time.
 seal status shows information about sealed commitments.
+seal inspect shows the full metadata of one item without changing it.
+seal relabel changes an item's tags and note after it was sealed.
+seal open prints the content of an unlocked item.
+seal delete shreds an item and removes it from the store.
+seal simulate reports whether an item would be unlockable at a given time.
+seal doctor checks the store for items that need attention.
+seal verify checks items against the hashes recorded for them, without unlocking.
+seal export prints an item's public commitment, or bundles the item to move it.
+seal receipt signs an item's public commitment; seal verify-receipt checks one.
+seal attest timestamps an item's ciphertext and checks the proof against bitcoin.
+seal import stores existing tlock (tle) files or a bundle as sealed items.
+seal pipe seals every write to a named pipe as a new item.
+seal watch-folder seals every file dropped into a directory.
+seal daemon keeps running and unlocks items as soon as they are due.
+seal config set changes a setting in config.json.
+seal move-store relocates the store, e.g. to an encrypted volume.
+seal keygen creates an age identity for receiving sealed content.
+seal identity lists stored identities and exports their public keys.
+seal contacts stores recipient public keys under short names.
+seal bench measures lock and materialize throughput on this machine.
+seal version prints build information for bug reports.
+
+status, inspect, verify and version may be abbreviated to an unambiguous prefix
+(seal stat); every other command needs its full name.
 
 No undo. No early unlock. No recovery.`
 
 func main() {
-	if len(os.Args) < 2 {
+	args := applyGlobalFlags(os.Args[1:])
+	if len(args) == 0 {
 		fmt.Fprintln(os.Stderr, usageText)
 		os.Exit(1)
 	}
 
-	command := os.Args[1]
+	name := args[0]
 
-	switch command {
-	case "lock":
-		handleLock(os.Args[2:])
-	case "status":
-		handleStatus(os.Args[2:])
+	switch name {
+	case "--version":
+		handleVersion(args[1:])
 	case "help", "--help", "-h":
 		fmt.Println(usageText)
 		os.Exit(0)
-	default:
-		fmt.Fprintf(os.Stderr, "unknown command: %s\n", command)
+	}
+
+	cmd, args, err := resolveCommand(name, args[1:])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
 		fmt.Fprintln(os.Stderr, usageText)
 		os.Exit(1)
 	}
+
+	// Clean up after writes interrupted by a crash before any command reads the store;
+	// a store that cannot be read is reported by the command itself
+	if removed, err := seal.RecoverStore(); err == nil {
+		for _, path := range removed {
+			fmt.Fprintf(os.Stderr, "warning: removed %s, left by an interrupted write\n", path)
+		}
+	}
+
+	// drand chain info and the latest round seen are kept in the store between runs
+	if dir, err := seal.CacheDir(); err == nil {
+		timeauth.CacheDir = dir
+	}
+
+	cmd.run(args)
+}
+
+// applyGlobalFlags handles the options given before the command name and returns the rest.
+// --data-dir sets SEAL_DATA_DIR, so programs seal runs (hooks, daemon --exec) use the same store.
+func applyGlobalFlags(args []string) []string {
+	for len(args) > 0 {
+		var dir string
+		switch {
+		case args[0] == "--data-dir":
+			if len(args) < 2 {
+				fmt.Fprintln(os.Stderr, "error: --data-dir requires a directory")
+				os.Exit(1)
+			}
+			dir, args = args[1], args[2:]
+		case strings.HasPrefix(args[0], "--data-dir="):
+			dir, args = strings.TrimPrefix(args[0], "--data-dir="), args[1:]
+		default:
+			return args
+		}
+
+		if dir == "" {
+			fmt.Fprintln(os.Stderr, "error: --data-dir requires a directory")
+			os.Exit(1)
+		}
+		os.Setenv(seal.DataDirEnvVar, dir)
+	}
+	return args
 }
 
 func handleLock(args []string) {
 	lockFlags := flag.NewFlagSet("lock", flag.ExitOnError)
-	until := lockFlags.String("until", "", "RFC3339 timestamp for unlock time")
+	until := lockFlags.String("until", "", "RFC3339 timestamp for unlock time, or +<duration> from now (e.g. +7d)")
+	lockFor := lockFlags.String("for", "", "unlock this long from now (e.g. 72h, 3d, 2w, 1mo)")
 	shred := lockFlags.Bool("shred", false, "best-effort file shredding (file input only)")
+	shredPasses := lockFlags.Int("shred-passes", 0, "overwrite passes for --shred (default from config, else 1)")
+	shredPattern := lockFlags.String("shred-pattern", "", "overwrite pattern for --shred: zero, random or alternating (default from config, else zero)")
 	clearClip := lockFlags.Bool("clear-clipboard", false, "best-effort clipboard clearing (stdin only)")
+	revealTo := lockFlags.String("reveal-to", "", "deliver content on unlock (e.g. mailto:alice@example.com, pass:web/example, k8s:prod/db-credentials)")
+	notify := lockFlags.String("notify", "", "comma-separated notification sinks from config")
+	onUnlockExec := lockFlags.String("on-unlock-exec", "", "program to run once when the item unlocks")
+	onUnlockWebhook := lockFlags.String("on-unlock-webhook", "", "URL to post a JSON event to once when the item unlocks")
+	readStdin := lockFlags.Bool("stdin", false, "always read input from stdin")
+	noStdin := lockFlags.Bool("no-stdin", false, "never read stdin (file input only)")
+	recipient := lockFlags.String("recipient", "", "age-encrypt content to a contact name or age1... public key before sealing")
+	passphraseFile := lockFlags.String("passphrase-file", "", "also encrypt content with the passphrase on the first line of this file (e.g. /dev/fd/3)")
+	directTlock := lockFlags.Bool("direct-tlock", false, "time-lock the content itself, without a data key (up to 64 KiB)")
+	vaultWrap := lockFlags.String("vault-wrap", "", "also wrap the data key with a HashiCorp Vault transit key (e.g. transit/keys/foo)")
+	postProcess := lockFlags.String("post-process", "", "comma-separated steps run on content after unlock (gunzip, untar, age-decrypt:<identity>)")
+	immutable := lockFlags.Bool("immutable", false, "best-effort immutable attribute on item files")
+	attest := lockFlags.Bool("attest", false, "timestamp the ciphertext hash with OpenTimestamps (sends the hash to public calendars)")
+	unsealTo := lockFlags.String("unseal-to", "", "directory to write unlocked content to instead of the store")
+	retainUnsealed := lockFlags.String("retain-unsealed", "", "shred unsealed content this long after unlock (e.g. 7d)")
+	allowSmall := lockFlags.Bool("allow-small", false, "seal input that is whitespace-only or below the configured minimum size")
+	stripNewline := lockFlags.Bool("strip-newline", false, "remove one trailing newline from stdin input")
+	encoding := lockFlags.String("encoding", seal.EncodingRaw, "stdin input encoding: utf8 (validated, BOM removed) or raw")
+	var tags repeatedFlag
+	lockFlags.Var(&tags, "tag", "key=value tag stored in metadata (repeatable)")
+	note := lockFlags.String("note", "", "one-line description stored in metadata")
+	fromPass := lockFlags.String("from-pass", "", "read input from a pass (or gopass) store entry")
+	passwordMode := lockFlags.Bool("password-mode", false, "input is a single password: strip the trailing newline and check its strength")
+	authority := lockFlags.String("authority", "", "time authority to seal with (default drand)")
+	network := lockFlags.String("network", "", "drand network: quicknet (default) or testnet")
+	chainHash := lockFlags.String("chain-hash", "", "lock to a custom drand chain, by chain hash")
+	relay := lockFlags.String("relay", "", "drand relay URL instead of the network's default")
+	var members repeatedFlag
+	lockFlags.Var(&members, "member", "threshold member <authority>[:<network>|<chain-hash>][@<relay>] (with --authority multi; repeatable)")
+	threshold := lockFlags.Int("threshold", 0, "members needed to unlock (with --authority multi)")
+	stdinJSON := lockFlags.Bool("stdin-json", false, "seal each JSON record read from stdin (NDJSON or an array)")
+	armor := lockFlags.Bool("armor", false, "print the sealed item as an ASCII-armored block to paste into an email")
+	timeout := timeoutFlag(lockFlags)
 
 	lockFlags.Usage = func() {
-		fmt.Fprintln(os.Stderr, "Usage: seal lock <path> --until <time> [--shred]")
-		fmt.Fprintln(os.Stderr, "       seal lock --until <time> [--clear-clipboard]  (reads from stdin)")
+		fmt.Fprintln(os.Stderr, "Usage: seal lock <path> --until <time> | --for <duration> [--shred]")
+		fmt.Fprintln(os.Stderr, "       seal lock --until <time> | --for <duration> [--clear-clipboard]  (reads from stdin)")
+		fmt.Fprintln(os.Stderr, "       seal lock --stdin-json [--until <time> | --for <duration>]  (one item per JSON record)")
+		fmt.Fprintln(os.Stderr, "       seal lock --armor [<path>] --until <time> | --for <duration>")
 		lockFlags.PrintDefaults()
 	}
 
 	lockFlags.Parse(args)
+	applyTimeout(*timeout)
 
-	if *until == "" {
-		fmt.Fprintln(os.Stderr, "error: --until is required")
+	// In batch mode each record may carry its own unlock time
+	if *until == "" && *lockFor == "" && !*stdinJSON {
+		fmt.Fprintln(os.Stderr, "error: --until or --for is required")
 		lockFlags.Usage()
 		os.Exit(1)
 	}
 
+	if *until != "" && *lockFor != "" {
+		fmt.Fprintln(os.Stderr, "error: --until and --for cannot be used together")
+		os.Exit(1)
+	}
+
 	remaining := lockFlags.Args()
 
 	if len(remaining) > 1 {
@@ -81,18 +329,51 @@ func handleLock(args []string) {
 		inputPath = remaining[0]
 	}
 
+	if *readStdin && *noStdin {
+		fmt.Fprintln(os.Stderr, "error: --stdin and --no-stdin cannot be used together")
+		os.Exit(1)
+	}
+
+	if *stdinJSON && (inputPath != "" || *readStdin || *noStdin || *fromPass != "" || *shred || *clearClip) {
+		fmt.Fprintln(os.Stderr, "error: --stdin-json reads every item from stdin records and cannot be combined with a file path, --stdin, --no-stdin, --from-pass, --shred or --clear-clipboard")
+		os.Exit(1)
+	}
+
+	if *stdinJSON && *armor {
+		fmt.Fprintln(os.Stderr, "error: --armor cannot be used with --stdin-json")
+		os.Exit(1)
+	}
+
+	stdinMode := seal.StdinAuto
+	if *readStdin {
+		stdinMode = seal.StdinRequired
+	} else if *noStdin {
+		stdinMode = seal.StdinNever
+	}
+
 	// Validate --shred usage
 	if *shred && inputPath == "" {
 		fmt.Fprintln(os.Stderr, "error: --shred can only be used with file input")
 		os.Exit(1)
 	}
 
+	if *fromPass != "" && (inputPath != "" || *readStdin) {
+		fmt.Fprintln(os.Stderr, "error: --from-pass cannot be combined with a file path or --stdin")
+		os.Exit(1)
+	}
+
 	// Validate --clear-clipboard usage
-	if *clearClip && inputPath != "" {
+	if *clearClip && (inputPath != "" || *fromPass != "") {
 		fmt.Fprintln(os.Stderr, "error: --clear-clipboard can only be used with stdin input")
 		os.Exit(1)
 	}
 
+	// Validate normalization usage
+	if (*stripNewline || *encoding != seal.EncodingRaw) && inputPath != "" {
+		fmt.Fprintln(os.Stderr, "error: --strip-newline and --encoding can only be used with stdin or --from-pass input")
+		os.Exit(1)
+	}
+
 	// Print mandatory warning if shredding
 	if *shred {
 		fmt.Fprintln(os.Stderr, "warning: file shredding on modern filesystems is best-effort only. backups, snapshots, wear leveling, and caches may retain data.")
@@ -103,14 +384,83 @@ func handleLock(args []string) {
 		fmt.Fprintln(os.Stderr, "warning: clipboard clearing is best-effort; the OS or other apps may retain copies")
 	}
 
-	// Execute lock operation
-	result, err := seal.Lock(seal.LockRequest{
-		InputPath:      inputPath,
-		UnlockTime:     *until,
-		Shred:          *shred,
-		ClearClipboard: *clearClip,
-	})
+	// Print mandatory warning if delivering on unlock
+	if *revealTo != "" {
+		fmt.Fprintln(os.Stderr, "warning: reveal delivery is best-effort and happens only when seal runs after unlock. delivery channels such as email are not confidential.")
+	}
 
+	// Print mandatory warning if hooks run on unlock
+	if *onUnlockExec != "" || *onUnlockWebhook != "" {
+		fmt.Fprintln(os.Stderr, "warning: unlock hooks are best-effort. they run once, only when seal status or seal daemon unlocks the item, and are not retried if they fail.")
+	}
+
+	// Print mandatory warning if protecting item files
+	if *immutable {
+		fmt.Fprintln(os.Stderr, "warning: immutable attributes are best-effort. they guard against accidental changes only, can be cleared by the file owner or an administrator, and are not supported on every filesystem.")
+	}
+
+	// Print mandatory warning if unsealed content will be shredded
+	if *retainUnsealed != "" {
+		fmt.Fprintln(os.Stderr, "warning: unsealed content is shredded only when seal runs after the retention period, and shredding is best-effort. copies made after unlock are not affected.")
+	}
+
+	var passphrase []byte
+	if *passphraseFile != "" {
+		var warnings []string
+		var err error
+		passphrase, warnings, err = seal.ReadPassphraseFile(*passphraseFile)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "error: %v\n", err)
+			os.Exit(1)
+		}
+		for _, warning := range warnings {
+			fmt.Fprintln(os.Stderr, warning)
+		}
+	}
+
+	req := seal.LockRequest{
+		InputPath:       inputPath,
+		UnlockTime:      *until,
+		For:             *lockFor,
+		Shred:           *shred,
+		ShredPasses:     *shredPasses,
+		ShredPattern:    *shredPattern,
+		ClearClipboard:  *clearClip,
+		RevealTo:        *revealTo,
+		Notify:          splitList(*notify),
+		OnUnlockExec:    *onUnlockExec,
+		OnUnlockWebhook: *onUnlockWebhook,
+		RetainUnsealed:  *retainUnsealed,
+		UnsealTo:        *unsealTo,
+		Immutable:       *immutable,
+		PostProcess:     splitList(*postProcess),
+		Recipient:       *recipient,
+		Passphrase:      passphrase,
+		DirectTlock:     *directTlock,
+		VaultWrap:       *vaultWrap,
+		Stdin:           stdinMode,
+		AllowSmall:      *allowSmall,
+		PasswordMode:    *passwordMode,
+		StripNewline:    *stripNewline,
+		Encoding:        *encoding,
+		FromPass:        *fromPass,
+		Tags:            tags,
+		Note:            *note,
+		Authority:       *authority,
+		Network:         *network,
+		ChainHash:       *chainHash,
+		Relay:           *relay,
+		Members:         members,
+		Threshold:       *threshold,
+		Attest:          *attest,
+	}
+
+	if *stdinJSON {
+		lockBatch(req)
+	}
+
+	// Execute lock operation
+	result, err := seal.Lock(req)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "error: %v\n", err)
 		os.Exit(1)
@@ -121,17 +471,67 @@ func handleLock(args []string) {
 		fmt.Fprintln(os.Stderr, warning)
 	}
 
+	if *armor {
+		// The block goes to stdout alone so it can be piped into a mail program
+		if err := seal.ArmorItem(result.ID, os.Stdout); err != nil {
+			fmt.Fprintf(os.Stderr, "error: item %s was sealed but cannot be armored: %v\n", result.ID, err)
+			os.Exit(1)
+		}
+		fmt.Fprintf(os.Stderr, "sealed: %s (also kept in the store)\n", result.ID)
+		os.Exit(0)
+	}
+
 	fmt.Println(result.ID)
 	os.Exit(0)
 }
 
+// lockBatch seals each JSON record on stdin with the options in req, printing
+// one JSON result line per record. Exits 1 if any record was not sealed.
+func lockBatch(req seal.LockRequest) {
+	encoder := json.NewEncoder(os.Stdout)
+	failed := 0
+	err := seal.LockBatch(os.Stdin, req, func(result seal.BatchResult) {
+		if result.Error != "" {
+			failed++
+		}
+		encoder.Encode(result)
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+	if failed > 0 {
+		fmt.Fprintf(os.Stderr, "error: %d records not sealed\n", failed)
+		os.Exit(1)
+	}
+	os.Exit(0)
+}
+
 func handleStatus(args []string) {
 	statusFlags := flag.NewFlagSet("status", flag.ExitOnError)
+	ndjson := statusFlags.Bool("ndjson", false, "stream one JSON object per item")
+	csvOut := statusFlags.Bool("csv", false, "print an inventory as CSV")
+	var tagFilter repeatedFlag
+	statusFlags.Var(&tagFilter, "tag", "only show items with this key=value tag (repeatable)")
+	state := statusFlags.String("state", "", "only show items in this state: sealed or unlocked")
+	before := statusFlags.String("before", "", "only show items unlocking before this RFC3339 time or +<duration>")
+	after := statusFlags.String("after", "", "only show items unlocking after this RFC3339 time or +<duration>")
+	sortBy := statusFlags.String("sort", "", "order items by created_at (default) or unlock_time")
+	limit := statusFlags.Int("limit", 0, "show at most this many items")
+	color := statusFlags.String("color", "auto", "color output: auto, always or never")
+	watch := statusFlags.Bool("watch", false, "refresh the display until interrupted")
+	interval := statusFlags.Duration("interval", defaultWatchInterval, "time between refreshes with --watch")
+	verify := statusFlags.Bool("verify", false, "also verify every item; exit 2 on a problem, 3 if the time authority is unreachable")
+	timeout := timeoutFlag(statusFlags)
 	statusFlags.Usage = func() {
-		fmt.Fprintln(os.Stderr, "Usage: seal status")
+		fmt.Fprintln(os.Stderr, "Usage: seal status [--ndjson | --csv] [--tag key=value]... [--state sealed|unlocked] [--before <time>] [--after <time>]")
+		fmt.Fprintln(os.Stderr, "                   [--sort created_at|unlock_time] [--limit <n>] [--color auto|always|never] [--timeout <duration>]")
+		fmt.Fprintln(os.Stderr, "                   [--watch [--interval <duration>] | --verify]")
+		statusFlags.PrintDefaults()
 	}
 
 	statusFlags.Parse(args)
+	applyTimeout(*timeout)
 
 	if len(statusFlags.Args()) > 0 {
 		fmt.Fprintln(os.Stderr, "error: status takes no arguments")
@@ -139,30 +539,275 @@ func handleStatus(args []string) {
 		os.Exit(1)
 	}
 
+	if *ndjson && *csvOut {
+		fmt.Fprintln(os.Stderr, "error: --ndjson and --csv cannot be combined")
+		os.Exit(1)
+	}
+
+	intervalSet := false
+	statusFlags.Visit(func(f *flag.Flag) {
+		if f.Name == "interval" {
+			intervalSet = true
+		}
+	})
+	switch {
+	case *watch && (*ndjson || *csvOut):
+		fmt.Fprintln(os.Stderr, "error: --watch cannot be used with --ndjson or --csv")
+		os.Exit(1)
+	case *watch && *verify:
+		fmt.Fprintln(os.Stderr, "error: --watch cannot be used with --verify")
+		os.Exit(1)
+	case intervalSet && !*watch:
+		fmt.Fprintln(os.Stderr, "error: --interval requires --watch")
+		os.Exit(1)
+	case *interval <= 0:
+		fmt.Fprintln(os.Stderr, "error: invalid --interval, expected a positive duration such as 10s")
+		os.Exit(1)
+	}
+
+	filter, err := seal.ParseTags(tagFilter)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+	listOpts := seal.ListOptions{State: *state, Tags: filter, Sort: *sortBy, Limit: *limit}
+	if *before != "" {
+		if listOpts.Before, err = seal.ParseListTime(*before); err != nil {
+			fmt.Fprintf(os.Stderr, "error: --before: %v\n", err)
+			os.Exit(1)
+		}
+	}
+	if *after != "" {
+		if listOpts.After, err = seal.ParseListTime(*after); err != nil {
+			fmt.Fprintf(os.Stderr, "error: --after: %v\n", err)
+			os.Exit(1)
+		}
+	}
+	if err := listOpts.Validate(); err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+
+	// Streamed output is in directory order
+	if (*ndjson || *csvOut) && *sortBy != "" {
+		fmt.Fprintln(os.Stderr, "error: --sort cannot be used with --ndjson or --csv, which stream items as they are checked")
+		os.Exit(1)
+	}
+
+	colorMode, err := output.ParseColorMode(*color)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+	errStyle := output.NewStyler(colorMode, os.Stderr)
+
+	if *ndjson {
+		handleStatusNDJSON(errStyle, listOpts, *verify)
+	}
+	if *csvOut {
+		handleStatusCSV(errStyle, listOpts, *verify)
+	}
+	style := output.NewStyler(colorMode, os.Stdout)
+	if *watch {
+		watchStatus(style, errStyle, listOpts, *interval)
+	}
+
 	result, err := seal.GetStatus()
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "error: %v\n", err)
 		os.Exit(1)
 	}
+	result.Items = listOpts.Apply(result.Items)
+
+	printStatusItems(style, result, listOpts)
+	exitStatus(result, errStyle, false, *verify)
+}
+
+// printStatusItems prints the items of a status check that match opts.
+func printStatusItems(style output.Styler, result seal.StatusResult, opts seal.ListOptions) {
+	filtered := opts.State != "" || !opts.Before.IsZero() || !opts.After.IsZero() || len(opts.Tags) > 0
+	if filtered && len(result.Items) == 0 {
+		fmt.Println("no items match the filters")
+	} else {
+		fmt.Print(style.Fields(seal.FormatStatusOutput(result.Items, result.Countdowns)))
+	}
+}
 
+// Exit codes of status --verify, for cron and monitoring. Flag parsing errors
+// also exit 2, but before anything is checked.
+const (
+	exitVerifyProblem     = 2 // an item violates its invariants or fails verification
+	exitVerifyUnreachable = 3 // a time authority could not be reached
+)
+
+// exitStatus reports validation errors, warnings, the materialization summary and
+// materialization failures from a status check on stderr and exits with the matching code.
+// The summary is a JSON line when jsonSummary is set. With verify, every item in
+// the store is verified too, and its problems are reported.
+func exitStatus(result seal.StatusResult, errStyle output.Styler, jsonSummary, verify bool) {
 	// Print validation errors to stderr
 	if result.ValidationFailed {
 		for _, validationErr := range result.ValidationErrors {
-			fmt.Fprintf(os.Stderr, "error: %v\n", validationErr)
+			fmt.Fprintln(os.Stderr, errStyle.Error(validationErr.Error()))
 		}
 	}
 
-	// Print status output
-	output := seal.FormatStatusOutput(result.Items)
-	fmt.Print(output)
+	// Print non-fatal warnings (e.g. failed reveal delivery)
+	for _, warning := range result.Warnings {
+		fmt.Fprintln(os.Stderr, warning)
+	}
+
+	// Show what the passive unlock machinery did, if there was anything to check
+	if result.Summary.Checked > 0 {
+		if jsonSummary {
+			line, err := seal.FormatMaterializationSummaryJSON(result.Summary)
+			if err == nil {
+				fmt.Fprint(os.Stderr, line)
+			}
+		} else {
+			fmt.Fprint(os.Stderr, seal.FormatMaterializationSummary(result.Summary))
+		}
+	}
+
+	if verify {
+		exitVerifiedStatus(result, errStyle)
+	}
 
 	// Exit with error if any validation or materialization failed
 	if result.ValidationFailed || result.MaterializationFailed {
 		if result.MaterializationFailed {
-			fmt.Fprintf(os.Stderr, "error: materialization failed: %v\n", result.FirstError)
+			fmt.Fprintln(os.Stderr, errStyle.Error(fmt.Sprintf("materialization failed: %v", result.FirstError)))
 		}
 		os.Exit(1)
 	}
 
 	os.Exit(0)
 }
+
+// exitVerifiedStatus verifies every item after a status check and exits with the
+// code of the most serious finding: a problem with an item, a materialization or
+// unlock failure, then an unreachable time authority.
+func exitVerifiedStatus(result seal.StatusResult, errStyle output.Styler) {
+	results, err := seal.Verify("")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Fprint(os.Stderr, seal.FormatVerifyProblems(results))
+	if result.MaterializationFailed {
+		fmt.Fprintln(os.Stderr, errStyle.Error(fmt.Sprintf("materialization failed: %v", result.FirstError)))
+	}
+
+	failed := result.ValidationFailed
+	for _, r := range results {
+		if len(r.Problems) > 0 {
+			failed = true
+		}
+	}
+
+	switch {
+	case failed:
+		os.Exit(exitVerifyProblem)
+	case result.MaterializationFailed:
+		os.Exit(1)
+	case result.UnlockFailed:
+		fmt.Fprintln(os.Stderr, errStyle.Error("unlock attempt failed; see last_unlock_error in seal inspect"))
+		os.Exit(1)
+	case result.AuthorityUnreachable:
+		fmt.Fprintln(os.Stderr, errStyle.Error("time authority unreachable"))
+		os.Exit(exitVerifyUnreachable)
+	}
+	os.Exit(0)
+}
+
+// handleStatusNDJSON streams status as one JSON object per line.
+// Each item is printed as soon as it has been processed.
+func handleStatusNDJSON(errStyle output.Styler, opts seal.ListOptions, verify bool) {
+	shown := 0
+	result, err := seal.StreamStatus(func(item seal.SealedItem, countdown *seal.Countdown) error {
+		if !opts.Matches(item) || (opts.Limit > 0 && shown == opts.Limit) {
+			return nil
+		}
+		shown++
+		line, err := seal.FormatStatusNDJSON(item, countdown)
+		if err != nil {
+			return err
+		}
+		_, err = os.Stdout.WriteString(line)
+		return err
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+
+	exitStatus(result, errStyle, true, verify)
+}
+
+// handleStatusCSV prints status as a CSV inventory with a header row.
+// Rows are printed as items are processed, in directory order.
+func handleStatusCSV(errStyle output.Styler, opts seal.ListOptions, verify bool) {
+	header, err := seal.FormatStatusCSVHeader()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+	os.Stdout.WriteString(header)
+
+	shown := 0
+	result, err := seal.StreamStatus(func(item seal.SealedItem, countdown *seal.Countdown) error {
+		if !opts.Matches(item) || (opts.Limit > 0 && shown == opts.Limit) {
+			return nil
+		}
+		shown++
+		line, err := seal.FormatStatusCSV(item, countdown)
+		if err != nil {
+			return err
+		}
+		_, err = os.Stdout.WriteString(line)
+		return err
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+
+	exitStatus(result, errStyle, false, verify)
+}
+
+// timeoutFlag adds --timeout to a command that contacts the time authority.
+func timeoutFlag(fs *flag.FlagSet) *time.Duration {
+	return fs.Duration("timeout", timeauth.NetworkTimeout, "give up on each time authority request after this long, including retries")
+}
+
+// applyTimeout sets the time authority timeout from --timeout, exiting on an invalid value.
+func applyTimeout(timeout time.Duration) {
+	if timeout <= 0 {
+		fmt.Fprintln(os.Stderr, "error: invalid --timeout, expected a positive duration such as 30s")
+		os.Exit(1)
+	}
+	timeauth.NetworkTimeout = timeout
+}
+
+// repeatedFlag collects every value of a flag that may be given more than once.
+type repeatedFlag []string
+
+func (f *repeatedFlag) String() string {
+	return strings.Join(*f, ",")
+}
+
+func (f *repeatedFlag) Set(value string) error {
+	*f = append(*f, value)
+	return nil
+}
+
+// splitList splits a comma-separated flag value, dropping empty entries.
+func splitList(value string) []string {
+	var result []string
+	for _, part := range strings.Split(value, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			result = append(result, part)
+		}
+	}
+	return result
+}
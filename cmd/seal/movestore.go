@@ -0,0 +1,48 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"seal/internal/seal"
+)
+
+func handleMoveStore(args []string) {
+	moveFlags := flag.NewFlagSet("move-store", flag.ExitOnError)
+	shredOld := moveFlags.Bool("shred-old", false, "best-effort shredding of the old store after the move")
+
+	moveFlags.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: seal move-store [--shred-old] <new-dir>")
+		moveFlags.PrintDefaults()
+	}
+
+	moveFlags.Parse(args)
+
+	remaining := moveFlags.Args()
+
+	if len(remaining) == 0 {
+		fmt.Fprintln(os.Stderr, "error: new directory is required")
+		moveFlags.Usage()
+		os.Exit(1)
+	}
+
+	if len(remaining) > 1 {
+		fmt.Fprintln(os.Stderr, "error: too many arguments")
+		moveFlags.Usage()
+		os.Exit(1)
+	}
+
+	result, err := seal.MoveStore(remaining[0], *shredOld)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+
+	for _, warning := range result.Warnings {
+		fmt.Fprintln(os.Stderr, warning)
+	}
+
+	fmt.Printf("moved: %s -> %s (%d files verified)\n", result.From, result.To, result.Files)
+	os.Exit(0)
+}
@@ -0,0 +1,238 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	"seal/internal/seal"
+)
+
+// exitStillSealed is the exit code of seal open for an item that has not unlocked yet.
+// Exit code 2 is taken by flag parsing errors.
+const exitStillSealed = 3
+
+func handleOpen(args []string) {
+	openFlags := flag.NewFlagSet("open", flag.ExitOnError)
+	out := openFlags.String("out", "", "write content to this new file instead of stdout")
+	beacon := openFlags.String("beacon", "", "unlock offline with this drand chain info and round (file, - for stdin, or inline JSON)")
+	armor := openFlags.Bool("armor", false, "unlock an ASCII-armored item read from stdin, without adding it to the store")
+	identity := openFlags.String("identity", "", "decrypt an item sealed with --recipient using this stored identity or age identity file")
+	passphraseFile := openFlags.String("passphrase-file", "", "decrypt an item sealed with --passphrase-file using the passphrase on the first line of this file")
+	wait := openFlags.Bool("wait", false, "if the item is still sealed, wait until it unlocks")
+	maxWait := openFlags.Duration("max-wait", 0, "with --wait, fail at once if the item will not unlock within this long (default no limit)")
+	timeout := timeoutFlag(openFlags)
+
+	openFlags.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: seal open [--out <path>] [--identity <name|file>] [--passphrase-file <path>] [--beacon <file|json>] [--timeout <duration>] <id>")
+		fmt.Fprintln(os.Stderr, "       seal open --wait [--max-wait <duration>] [--out <path>] [--identity <name|file>] [--passphrase-file <path>] [--timeout <duration>] <id>")
+		fmt.Fprintln(os.Stderr, "       seal open --armor [--out <path>] [--identity <name|file>] [--passphrase-file <path>] [--beacon <file|json>] [--timeout <duration>]  (reads the block from stdin)")
+		openFlags.PrintDefaults()
+	}
+
+	openFlags.Parse(args)
+	applyTimeout(*timeout)
+
+	remaining := openFlags.Args()
+
+	maxWaitSet := false
+	openFlags.Visit(func(f *flag.Flag) {
+		if f.Name == "max-wait" {
+			maxWaitSet = true
+		}
+	})
+	switch {
+	case *wait && (*armor || *beacon != ""):
+		fmt.Fprintln(os.Stderr, "error: --wait cannot be used with --armor or --beacon")
+		os.Exit(1)
+	case maxWaitSet && !*wait:
+		fmt.Fprintln(os.Stderr, "error: --max-wait requires --wait")
+		os.Exit(1)
+	case *maxWait < 0:
+		fmt.Fprintln(os.Stderr, "error: invalid --max-wait, expected a positive duration such as 1h")
+		os.Exit(1)
+	}
+
+	var passphrase []byte
+	if *passphraseFile != "" {
+		var err error
+		// Strength was reported at lock time
+		passphrase, _, err = seal.ReadPassphraseFile(*passphraseFile)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "error: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	if *armor {
+		openArmored(remaining, *beacon, *identity, passphrase, *out)
+	}
+
+	if len(remaining) == 0 {
+		fmt.Fprintln(os.Stderr, "error: item id is required")
+		openFlags.Usage()
+		os.Exit(1)
+	}
+
+	if len(remaining) > 1 {
+		fmt.Fprintln(os.Stderr, "error: too many arguments")
+		openFlags.Usage()
+		os.Exit(1)
+	}
+
+	var result seal.OpenResult
+	var err error
+	if *beacon != "" {
+		data, readErr := readBeacon(*beacon)
+		if readErr != nil {
+			fmt.Fprintf(os.Stderr, "error: cannot read beacon: %v\n", readErr)
+			os.Exit(1)
+		}
+		result, err = seal.OpenWithBeacon(remaining[0], data)
+	} else if *wait {
+		result, err = openWait(remaining[0], *maxWait)
+	} else {
+		result, err = seal.Open(remaining[0])
+	}
+	for _, warning := range result.Warnings {
+		fmt.Fprintln(os.Stderr, warning)
+	}
+	if errors.Is(err, seal.ErrStillSealed) {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(exitStillSealed)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+
+	if err := copyContent(result.Item, result.Path, *identity, passphrase, *out); err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+	os.Exit(0)
+}
+
+// openWait opens an item, first waiting for it to unlock if it is still sealed.
+// An interrupt stops the wait; a second one exits at once.
+func openWait(id string, maxWait time.Duration) (seal.OpenResult, error) {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	result, err := seal.OpenWait(ctx, id, maxWait, func(msg string) {
+		fmt.Fprintln(os.Stderr, msg)
+	})
+	if err != nil && ctx.Err() != nil {
+		return result, fmt.Errorf("interrupted while waiting for item %s", id)
+	}
+	return result, err
+}
+
+// openArmored unlocks an armored item read from stdin and writes its content.
+func openArmored(args []string, beacon, identity string, passphrase []byte, out string) {
+	if len(args) > 0 {
+		fmt.Fprintln(os.Stderr, "error: --armor reads the item from stdin and takes no id")
+		os.Exit(1)
+	}
+	if beacon == "-" {
+		fmt.Fprintln(os.Stderr, "error: --beacon - cannot be used with --armor, which reads stdin")
+		os.Exit(1)
+	}
+
+	var beaconData []byte
+	if beacon != "" {
+		var err error
+		beaconData, err = readBeacon(beacon)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "error: cannot read beacon: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	content, err := seal.OpenArmored(os.Stdin, beaconData)
+	if errors.Is(err, seal.ErrStillSealed) {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(exitStillSealed)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+
+	if err := writeContent(content.Item, bytes.NewReader(content.Data), identity, passphrase, out); err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+	os.Exit(0)
+}
+
+// readBeacon reads a --beacon value: inline JSON, - for stdin, or a file path.
+func readBeacon(value string) ([]byte, error) {
+	switch {
+	case strings.HasPrefix(strings.TrimSpace(value), "{"):
+		return []byte(value), nil
+	case value == "-":
+		return io.ReadAll(os.Stdin)
+	default:
+		return os.ReadFile(value)
+	}
+}
+
+// copyContent copies unlocked content to stdout, or to a new file at out.
+func copyContent(item seal.SealedItem, path, identity string, passphrase []byte, out string) error {
+	src, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("cannot read unlocked content: %w", err)
+	}
+	defer src.Close()
+
+	return writeContent(item, src, identity, passphrase, out)
+}
+
+// writeContent copies an item's content to stdout, or to a new file at out.
+// An existing file at out is never overwritten. If identity is set, content
+// sealed for a recipient is decrypted with it first; content sealed with a
+// passphrase is always decrypted with passphrase first.
+func writeContent(item seal.SealedItem, src io.Reader, identity string, passphrase []byte, out string) error {
+	if item.Passphrase != nil {
+		if passphrase == nil {
+			return fmt.Errorf("item %s is protected by a passphrase; use --passphrase-file", item.ID)
+		}
+		decrypted, err := seal.DecryptWithPassphrase(item, src, passphrase)
+		if err != nil {
+			return err
+		}
+		src = bytes.NewReader(decrypted)
+	}
+
+	if identity != "" {
+		decrypted, err := seal.DecryptForIdentity(item, src, identity)
+		if err != nil {
+			return err
+		}
+		src = decrypted
+	}
+
+	if out == "" {
+		_, err := io.Copy(os.Stdout, src)
+		return err
+	}
+
+	dst, err := os.OpenFile(out, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return fmt.Errorf("cannot create output file: %w", err)
+	}
+	if _, err := io.Copy(dst, src); err != nil {
+		dst.Close()
+		os.Remove(out)
+		return fmt.Errorf("cannot write output file: %w", err)
+	}
+	return dst.Close()
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"seal/internal/seal"
+)
+
+func handlePipe(args []string) {
+	pipeFlags := flag.NewFlagSet("pipe", flag.ExitOnError)
+	until := pipeFlags.String("until", "", "RFC3339 timestamp for unlock time")
+	fifo := pipeFlags.String("fifo", "", "path to an existing named pipe")
+
+	pipeFlags.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: seal pipe --until <time> --fifo <path>")
+		pipeFlags.PrintDefaults()
+	}
+
+	pipeFlags.Parse(args)
+
+	if *until == "" {
+		fmt.Fprintln(os.Stderr, "error: --until is required")
+		pipeFlags.Usage()
+		os.Exit(1)
+	}
+
+	if *fifo == "" {
+		fmt.Fprintln(os.Stderr, "error: --fifo is required")
+		pipeFlags.Usage()
+		os.Exit(1)
+	}
+
+	if len(pipeFlags.Args()) > 0 {
+		fmt.Fprintln(os.Stderr, "error: pipe takes no arguments")
+		pipeFlags.Usage()
+		os.Exit(1)
+	}
+
+	// Runs until interrupted or a fatal error occurs
+	err := seal.ServePipe(seal.PipeRequest{
+		FIFOPath:   *fifo,
+		UnlockTime: *until,
+	}, func(id string) {
+		fmt.Println(id)
+	}, func(msg string) {
+		fmt.Fprintln(os.Stderr, msg)
+	})
+
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+}
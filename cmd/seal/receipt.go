@@ -0,0 +1,116 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"seal/internal/seal"
+)
+
+func handleReceipt(args []string) {
+	receiptFlags := flag.NewFlagSet("receipt", flag.ExitOnError)
+	publicKey := receiptFlags.Bool("public-key", false, "print the public key receipts are signed with, to publish ahead of time")
+
+	receiptFlags.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: seal receipt <id>")
+		fmt.Fprintln(os.Stderr, "       seal receipt --public-key")
+		receiptFlags.PrintDefaults()
+	}
+
+	receiptFlags.Parse(args)
+
+	remaining := receiptFlags.Args()
+
+	if *publicKey {
+		if len(remaining) > 0 {
+			fmt.Fprintln(os.Stderr, "error: --public-key takes no item id")
+			os.Exit(1)
+		}
+		key, err := seal.ReceiptPublicKey()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "error: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Println(key)
+		os.Exit(0)
+	}
+
+	if len(remaining) == 0 {
+		fmt.Fprintln(os.Stderr, "error: item id is required")
+		receiptFlags.Usage()
+		os.Exit(1)
+	}
+
+	if len(remaining) > 1 {
+		fmt.Fprintln(os.Stderr, "error: too many arguments")
+		receiptFlags.Usage()
+		os.Exit(1)
+	}
+
+	receipt, err := seal.IssueReceipt(remaining[0])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+
+	output, err := seal.FormatReceiptJSON(receipt)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Print(output)
+	os.Exit(0)
+}
+
+func handleVerifyReceipt(args []string) {
+	verifyFlags := flag.NewFlagSet("verify-receipt", flag.ExitOnError)
+	publicKey := verifyFlags.String("public-key", "", "require the receipt to be signed by this key (from seal receipt --public-key)")
+
+	verifyFlags.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: seal verify-receipt [--public-key <key>] <file>")
+		verifyFlags.PrintDefaults()
+	}
+
+	verifyFlags.Parse(args)
+
+	remaining := verifyFlags.Args()
+
+	if len(remaining) == 0 {
+		fmt.Fprintln(os.Stderr, "error: receipt file is required")
+		verifyFlags.Usage()
+		os.Exit(1)
+	}
+
+	if len(remaining) > 1 {
+		fmt.Fprintln(os.Stderr, "error: too many arguments")
+		verifyFlags.Usage()
+		os.Exit(1)
+	}
+
+	var data []byte
+	var err error
+	if remaining[0] == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(remaining[0])
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: cannot read receipt: %v\n", err)
+		os.Exit(1)
+	}
+
+	receipt, err := seal.VerifyReceipt(data, *publicKey)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Print(seal.FormatReceiptVerification(receipt))
+	if *publicKey == "" {
+		fmt.Fprintln(os.Stderr, "warning: no --public-key given; the signature shows the receipt is intact, not who issued it")
+	}
+	os.Exit(0)
+}
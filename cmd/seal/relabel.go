@@ -0,0 +1,57 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"seal/internal/seal"
+)
+
+func handleRelabel(args []string) {
+	relabelFlags := flag.NewFlagSet("relabel", flag.ExitOnError)
+	var setTags, removeTags repeatedFlag
+	relabelFlags.Var(&setTags, "tag", "add or replace a key=value tag (repeatable)")
+	relabelFlags.Var(&removeTags, "untag", "remove the tag with this key (repeatable)")
+	note := relabelFlags.String("note", "", "replace the note; an empty note removes it")
+
+	relabelFlags.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: seal relabel [--tag key=value]... [--untag key]... [--note <text>] <id>")
+		relabelFlags.PrintDefaults()
+	}
+
+	relabelFlags.Parse(args)
+
+	remaining := relabelFlags.Args()
+
+	if len(remaining) == 0 {
+		fmt.Fprintln(os.Stderr, "error: item id is required")
+		relabelFlags.Usage()
+		os.Exit(1)
+	}
+
+	if len(remaining) > 1 {
+		fmt.Fprintln(os.Stderr, "error: too many arguments")
+		relabelFlags.Usage()
+		os.Exit(1)
+	}
+
+	req := seal.RelabelRequest{SetTags: setTags, RemoveTags: removeTags}
+	relabelFlags.Visit(func(f *flag.Flag) {
+		if f.Name == "note" {
+			req.Note = note
+		}
+	})
+
+	if len(req.SetTags) == 0 && len(req.RemoveTags) == 0 && req.Note == nil {
+		fmt.Fprintln(os.Stderr, "error: --tag, --untag or --note is required")
+		relabelFlags.Usage()
+		os.Exit(1)
+	}
+
+	if _, err := seal.Relabel(remaining[0], req); err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+	os.Exit(0)
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"seal/internal/seal"
+)
+
+// command is a top-level seal command.
+type command struct {
+	name   string
+	run    func(args []string)
+	abbrev bool // may be run by an unambiguous prefix; read-only commands only
+}
+
+// commands lists every top-level command in usage order.
+var commands = []command{
+	{"init", handleInit, false},
+	{"lock", handleLock, false},
+	{"status", handleStatus, true},
+	{"inspect", handleInspect, true},
+	{"relabel", handleRelabel, false},
+	{"open", handleOpen, false},
+	{"delete", handleDelete, false},
+	{"simulate", handleSimulate, false},
+	{"doctor", handleDoctor, false},
+	{"verify", handleVerify, true},
+	{"export", handleExport, false},
+	{"receipt", handleReceipt, false},
+	{"verify-receipt", handleVerifyReceipt, false},
+	{"attest", handleAttest, false},
+	{"import", handleImport, false},
+	{"pipe", handlePipe, false},
+	{"watch-folder", handleWatchFolder, false},
+	{"daemon", handleDaemon, false},
+	{"config", handleConfig, false},
+	{"move-store", handleMoveStore, false},
+	{"keygen", handleKeygen, false},
+	{"identity", handleIdentity, false},
+	{"contacts", handleContacts, false},
+	{"bench", handleBench, false},
+	{"version", handleVersion, true},
+}
+
+// lookupCommand returns the command with exactly this name.
+func lookupCommand(name string) (command, bool) {
+	for _, cmd := range commands {
+		if cmd.name == name {
+			return cmd, true
+		}
+	}
+	return command{}, false
+}
+
+// resolveCommand finds the command to run and its arguments.
+// A command name always wins; then a user-defined alias from config, whose
+// expansion is a command name followed by arguments placed before the given
+// ones; then an unambiguous prefix of a read-only command's name. Commands
+// that change or destroy anything need their full name, so a prefix in a
+// script never runs one, whatever commands are added later.
+// Aliases are read from config only when the name is not a command.
+func resolveCommand(name string, args []string) (command, []string, error) {
+	if cmd, ok := lookupCommand(name); ok {
+		return cmd, args, nil
+	}
+
+	cfg, err := seal.LoadConfig()
+	if err != nil {
+		return command{}, nil, err
+	}
+
+	if expansion, ok := cfg.Aliases[name]; ok {
+		words := strings.Fields(expansion)
+		if len(words) == 0 {
+			return command{}, nil, fmt.Errorf("alias %s is empty", name)
+		}
+		// Aliases expand to commands, never to other aliases or prefixes
+		cmd, ok := lookupCommand(words[0])
+		if !ok {
+			return command{}, nil, fmt.Errorf("alias %s: unknown command: %s", name, words[0])
+		}
+		return cmd, append(words[1:], args...), nil
+	}
+
+	var matches []command
+	for _, cmd := range commands {
+		if strings.HasPrefix(cmd.name, name) {
+			matches = append(matches, cmd)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return command{}, nil, fmt.Errorf("unknown command: %s", name)
+	case 1:
+		if !matches[0].abbrev {
+			return command{}, nil, fmt.Errorf("unknown command: %s (%s cannot be abbreviated)", name, matches[0].name)
+		}
+		return matches[0], args, nil
+	default:
+		names := make([]string, len(matches))
+		for i, cmd := range matches {
+			names[i] = cmd.name
+		}
+		sort.Strings(names)
+		return command{}, nil, fmt.Errorf("ambiguous command: %s (could be %s)", name, strings.Join(names, ", "))
+	}
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"seal/internal/seal"
+)
+
+func handleSimulate(args []string) {
+	simulateFlags := flag.NewFlagSet("simulate", flag.ExitOnError)
+	at := simulateFlags.String("at", "", "RFC3339 timestamp to simulate")
+
+	simulateFlags.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: seal simulate --at <time> <id>")
+		simulateFlags.PrintDefaults()
+	}
+
+	simulateFlags.Parse(args)
+
+	remaining := simulateFlags.Args()
+
+	if len(remaining) == 0 {
+		fmt.Fprintln(os.Stderr, "error: item id is required")
+		simulateFlags.Usage()
+		os.Exit(1)
+	}
+
+	if len(remaining) > 1 {
+		fmt.Fprintln(os.Stderr, "error: too many arguments")
+		simulateFlags.Usage()
+		os.Exit(1)
+	}
+
+	if *at == "" {
+		fmt.Fprintln(os.Stderr, "error: --at is required")
+		simulateFlags.Usage()
+		os.Exit(1)
+	}
+
+	atTime, err := time.Parse(time.RFC3339, *at)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "error: invalid time format, expected RFC3339")
+		os.Exit(1)
+	}
+
+	result, err := seal.Simulate(remaining[0], atTime)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Print(seal.FormatSimulateOutput(result))
+	os.Exit(0)
+}
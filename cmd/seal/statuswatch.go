@@ -0,0 +1,55 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"seal/internal/clock"
+	"seal/internal/output"
+	"seal/internal/seal"
+)
+
+// defaultWatchInterval is the time between refreshes of seal status --watch.
+const defaultWatchInterval = 10 * time.Second
+
+// clearScreen moves the cursor home and clears the terminal.
+const clearScreen = "\033[H\033[2J"
+
+// watchStatus runs a status check every interval and redraws the display,
+// until interrupted. Each check unlocks due items exactly as status does.
+// Problems are printed below the display on each refresh; they do not stop it.
+func watchStatus(style, errStyle output.Styler, opts seal.ListOptions, interval time.Duration) {
+	// Only a terminal is redrawn; otherwise refreshes follow one another
+	terminal := false
+	if info, err := os.Stdout.Stat(); err == nil {
+		terminal = info.Mode()&os.ModeCharDevice != 0
+	}
+
+	for {
+		result, err := seal.GetStatus()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "error: %v\n", err)
+			os.Exit(1)
+		}
+		result.Items = opts.Apply(result.Items)
+
+		if terminal {
+			fmt.Print(clearScreen)
+		}
+		fmt.Printf("refreshed %s, every %s until interrupted\n\n", clock.UTC().Format(time.RFC3339), interval)
+		printStatusItems(style, result, opts)
+
+		for _, validationErr := range result.ValidationErrors {
+			fmt.Fprintln(os.Stderr, errStyle.Error(validationErr.Error()))
+		}
+		for _, warning := range result.Warnings {
+			fmt.Fprintln(os.Stderr, warning)
+		}
+		if result.MaterializationFailed {
+			fmt.Fprintln(os.Stderr, errStyle.Error(fmt.Sprintf("materialization failed: %v", result.FirstError)))
+		}
+
+		time.Sleep(interval)
+	}
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"seal/internal/seal"
+)
+
+func handleVerify(args []string) {
+	verifyFlags := flag.NewFlagSet("verify", flag.ExitOnError)
+
+	verifyFlags.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: seal verify [<id>]")
+		verifyFlags.PrintDefaults()
+	}
+
+	verifyFlags.Parse(args)
+
+	remaining := verifyFlags.Args()
+	if len(remaining) > 1 {
+		fmt.Fprintln(os.Stderr, "error: too many arguments")
+		verifyFlags.Usage()
+		os.Exit(1)
+	}
+
+	var id string
+	if len(remaining) == 1 {
+		id = remaining[0]
+	}
+
+	results, err := seal.Verify(id)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Print(seal.FormatVerifyOutput(results))
+	for _, result := range results {
+		if len(result.Problems) > 0 {
+			os.Exit(1)
+		}
+	}
+	os.Exit(0)
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"seal/internal/seal"
+)
+
+func handleVersion(args []string) {
+	versionFlags := flag.NewFlagSet("version", flag.ExitOnError)
+	jsonOutput := versionFlags.Bool("json", false, "print build information as JSON")
+
+	versionFlags.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: seal version [--json]")
+		versionFlags.PrintDefaults()
+	}
+
+	versionFlags.Parse(args)
+
+	if len(versionFlags.Args()) > 0 {
+		fmt.Fprintln(os.Stderr, "error: too many arguments")
+		versionFlags.Usage()
+		os.Exit(1)
+	}
+
+	info := seal.GetBuildInfo()
+
+	if *jsonOutput {
+		output, err := seal.FormatBuildInfoJSON(info)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "error: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Print(output)
+		os.Exit(0)
+	}
+
+	fmt.Print(seal.FormatBuildInfo(info))
+	os.Exit(0)
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"seal/internal/seal"
+)
+
+func handleWatchFolder(args []string) {
+	watchFlags := flag.NewFlagSet("watch-folder", flag.ExitOnError)
+	untilRel := watchFlags.String("until-rel", "", "unlock duration after each file is sealed (e.g. 24h)")
+	shred := watchFlags.Bool("shred", false, "best-effort shredding of each file after sealing")
+	interval := watchFlags.Duration("interval", seal.DefaultWatchInterval, "how often to scan the folder")
+
+	watchFlags.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: seal watch-folder --until-rel <duration> [--shred] [--interval <duration>] <dir>")
+		watchFlags.PrintDefaults()
+	}
+
+	watchFlags.Parse(args)
+
+	if *untilRel == "" {
+		fmt.Fprintln(os.Stderr, "error: --until-rel is required")
+		watchFlags.Usage()
+		os.Exit(1)
+	}
+
+	unlockAfter, err := time.ParseDuration(*untilRel)
+	if err != nil || unlockAfter <= 0 {
+		fmt.Fprintln(os.Stderr, "error: invalid --until-rel duration, expected a positive duration such as 24h")
+		os.Exit(1)
+	}
+
+	remaining := watchFlags.Args()
+
+	if len(remaining) == 0 {
+		fmt.Fprintln(os.Stderr, "error: directory is required")
+		watchFlags.Usage()
+		os.Exit(1)
+	}
+
+	if len(remaining) > 1 {
+		fmt.Fprintln(os.Stderr, "error: too many arguments")
+		watchFlags.Usage()
+		os.Exit(1)
+	}
+
+	// Print mandatory warning if shredding
+	if *shred {
+		fmt.Fprintln(os.Stderr, "warning: file shredding on modern filesystems is best-effort only. backups, snapshots, wear leveling, and caches may retain data.")
+	}
+
+	// Runs until interrupted or a fatal error occurs
+	err = seal.WatchFolder(seal.WatchFolderRequest{
+		Dir:         remaining[0],
+		UnlockAfter: unlockAfter,
+		Shred:       *shred,
+		Interval:    *interval,
+	}, func(id, path string) {
+		fmt.Printf("%s %s\n", id, path)
+	}, func(msg string) {
+		fmt.Fprintln(os.Stderr, msg)
+	})
+
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,74 @@
+// Package clock is the single source of the current time for Seal.
+//
+// A Reading carries two values with different jobs. Wall is UTC without a
+// monotonic reading: it is what gets stored, and what is compared with stored
+// or parsed times, so a comparison means the same thing whether or not both
+// sides came from this process. The monotonic reading measures elapsed time
+// within one process and is unaffected by clock steps, DST changes and
+// leap-second adjustments.
+package clock
+
+import (
+	"time"
+)
+
+// source is the underlying clock. Replaced with Set in tests.
+var source = time.Now
+
+// Reading is one observation of the clock.
+type Reading struct {
+	Wall time.Time // UTC wall-clock time, safe to store and compare with stored times
+	mono time.Time // raw reading, carrying the monotonic clock
+}
+
+// Now reads the clock.
+func Now() Reading {
+	t := source()
+	return Reading{Wall: t.UTC().Round(0), mono: t}
+}
+
+// UTC returns the current wall-clock time in UTC, for storing and comparing with stored times.
+func UTC() time.Time {
+	return Now().Wall
+}
+
+// Elapsed returns the time since the reading, measured on the monotonic clock.
+func (r Reading) Elapsed() time.Duration {
+	return source().Sub(r.mono)
+}
+
+// Since returns the wall-clock time elapsed since t, a stored or parsed time.
+func Since(t time.Time) time.Duration {
+	return UTC().Sub(t)
+}
+
+// Until returns the wall-clock time remaining until t, a stored or parsed time.
+func Until(t time.Time) time.Duration {
+	return t.Sub(UTC())
+}
+
+// Set replaces the clock until the returned function is called. For tests.
+func Set(now func() time.Time) (restore func()) {
+	prev := source
+	source = now
+	return func() { source = prev }
+}
+
+// ParseRFC3339 parses an RFC3339 timestamp and returns it in UTC.
+// RFC3339 allows a leap second (23:59:60), which Go rejects; it is read as the
+// following second, as Unix time and drand rounds count it.
+func ParseRFC3339(s string) (time.Time, error) {
+	t, err := time.Parse(time.RFC3339, s)
+	if err == nil {
+		return t.UTC(), nil
+	}
+
+	if len(s) > 19 && s[16] == ':' && s[17:19] == "60" {
+		t, leapErr := time.Parse(time.RFC3339, s[:17]+"59"+s[19:])
+		if leapErr == nil {
+			return t.Add(time.Second).UTC(), nil
+		}
+	}
+
+	return time.Time{}, err
+}
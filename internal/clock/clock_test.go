@@ -0,0 +1,104 @@
+package clock
+
+import (
+	"testing"
+	"time"
+	_ "time/tzdata"
+)
+
+func TestNow_WallIsUTCWithoutMonotonic(t *testing.T) {
+	r := Now()
+
+	if r.Wall.Location() != time.UTC {
+		t.Errorf("expected UTC, got %s", r.Wall.Location())
+	}
+	if r.Wall != r.Wall.Round(0) {
+		t.Error("wall reading should not carry a monotonic reading")
+	}
+	if r.Elapsed() < 0 {
+		t.Error("elapsed time should never be negative")
+	}
+}
+
+func TestNow_DSTTransition(t *testing.T) {
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 2026-03-08: local clocks jump from 01:59:30 EST to 03:00:30 EDT, one minute later
+	before := time.Date(2026, 3, 8, 1, 59, 30, 0, ny)
+	after := before.Add(time.Minute)
+	if after.Hour() != 3 {
+		t.Fatalf("test setup: expected the local clock to jump, got %s", after)
+	}
+
+	defer Set(func() time.Time { return before })()
+	first := Now()
+	Set(func() time.Time { return after })
+	second := Now()
+
+	if got := second.Wall.Sub(first.Wall); got != time.Minute {
+		t.Errorf("expected one minute between readings, got %s", got)
+	}
+	if got := second.Wall.Format(time.RFC3339); got != "2026-03-08T07:00:30Z" {
+		t.Errorf("expected UTC wall time, got %s", got)
+	}
+
+	// The fall-back hour repeats locally but not in UTC
+	fallBack := time.Date(2026, 11, 1, 1, 30, 0, 0, ny)
+	Set(func() time.Time { return fallBack })
+	earlier := Now()
+	Set(func() time.Time { return fallBack.Add(time.Hour) })
+	later := Now()
+	if later.Wall.Hour() == earlier.Wall.Hour() || !later.Wall.After(earlier.Wall) {
+		t.Errorf("expected distinct increasing UTC times, got %s and %s", earlier.Wall, later.Wall)
+	}
+}
+
+func TestSinceUntil(t *testing.T) {
+	now := time.Date(2026, 6, 15, 10, 0, 0, 0, time.UTC)
+	defer Set(func() time.Time { return now })()
+
+	stored := time.Date(2026, 6, 15, 9, 0, 0, 0, time.UTC)
+	if got := Since(stored); got != time.Hour {
+		t.Errorf("Since = %s, want 1h", got)
+	}
+	if got := Until(stored.Add(2 * time.Hour)); got != time.Hour {
+		t.Errorf("Until = %s, want 1h", got)
+	}
+}
+
+func TestParseRFC3339(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"2026-06-15T10:00:00Z", "2026-06-15T10:00:00Z"},
+		{"2026-06-15T12:00:00+02:00", "2026-06-15T10:00:00Z"},
+		// Either side of a DST change, expressed with the local offset in effect
+		{"2026-03-08T01:59:59-05:00", "2026-03-08T06:59:59Z"},
+		{"2026-03-08T03:00:00-04:00", "2026-03-08T07:00:00Z"},
+		// Leap seconds are read as the following second
+		{"2016-12-31T23:59:60Z", "2017-01-01T00:00:00Z"},
+		{"2017-01-01T05:29:60+05:30", "2017-01-01T00:00:00Z"},
+		{"2016-12-31T23:59:60.5Z", "2017-01-01T00:00:00.5Z"},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseRFC3339(tt.input)
+		if err != nil {
+			t.Errorf("ParseRFC3339(%q) failed: %v", tt.input, err)
+			continue
+		}
+		if got.Location() != time.UTC || got.Format(time.RFC3339Nano) != tt.want {
+			t.Errorf("ParseRFC3339(%q) = %s, want %s", tt.input, got.Format(time.RFC3339Nano), tt.want)
+		}
+	}
+
+	for _, input := range []string{"", "2016-12-31T23:59:61Z", "2016-12-31T23:60:00Z", "2016-12-31 23:59:60Z", "tomorrow"} {
+		if _, err := ParseRFC3339(input); err == nil {
+			t.Errorf("ParseRFC3339(%q): expected error", input)
+		}
+	}
+}
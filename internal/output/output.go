@@ -0,0 +1,115 @@
+// Package output styles terminal output shared by all seal commands.
+// Color is decoration only: the text is identical with color on or off.
+package output
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// ColorMode selects when output is colored.
+type ColorMode int
+
+const (
+	ColorAuto   ColorMode = iota // color when writing to a terminal, unless NO_COLOR is set
+	ColorAlways                  // always color
+	ColorNever                   // never color
+)
+
+// ParseColorMode parses a --color flag value: auto, always or never.
+func ParseColorMode(value string) (ColorMode, error) {
+	switch value {
+	case "auto", "":
+		return ColorAuto, nil
+	case "always":
+		return ColorAlways, nil
+	case "never":
+		return ColorNever, nil
+	default:
+		return ColorAuto, fmt.Errorf("invalid color mode %q, expected auto, always or never", value)
+	}
+}
+
+// ANSI escape sequences.
+const (
+	ansiReset  = "\x1b[0m"
+	ansiRed    = "\x1b[31m"
+	ansiGreen  = "\x1b[32m"
+	ansiYellow = "\x1b[33m"
+)
+
+// Styler applies colors when enabled. The zero value never colors.
+type Styler struct {
+	enabled bool
+}
+
+// NewStyler returns a Styler for output written to f.
+// In auto mode, color requires a terminal, no NO_COLOR variable (https://no-color.org)
+// and a TERM other than dumb.
+func NewStyler(mode ColorMode, f *os.File) Styler {
+	switch mode {
+	case ColorAlways:
+		return Styler{enabled: true}
+	case ColorNever:
+		return Styler{}
+	}
+
+	if os.Getenv("NO_COLOR") != "" || os.Getenv("TERM") == "dumb" {
+		return Styler{}
+	}
+
+	info, err := f.Stat()
+	if err != nil {
+		return Styler{}
+	}
+	return Styler{enabled: info.Mode()&os.ModeCharDevice != 0}
+}
+
+// Enabled reports whether the styler colors text.
+func (s Styler) Enabled() bool {
+	return s.enabled
+}
+
+func (s Styler) Red(text string) string    { return s.wrap(ansiRed, text) }
+func (s Styler) Green(text string) string  { return s.wrap(ansiGreen, text) }
+func (s Styler) Yellow(text string) string { return s.wrap(ansiYellow, text) }
+
+func (s Styler) wrap(code, text string) string {
+	if !s.enabled || text == "" {
+		return text
+	}
+	return code + text + ansiReset
+}
+
+// Fields colors the values of "state:" lines in key: value output:
+// unlocked green, sealed yellow. Other lines are returned unchanged.
+func (s Styler) Fields(text string) string {
+	if !s.enabled {
+		return text
+	}
+
+	lines := strings.SplitAfter(text, "\n")
+	for i, line := range lines {
+		value, ok := strings.CutPrefix(line, "state: ")
+		if !ok {
+			continue
+		}
+
+		state := strings.TrimSuffix(value, "\n")
+		newline := value[len(state):]
+		switch state {
+		case "unlocked":
+			lines[i] = "state: " + s.Green(state) + newline
+		case "sealed":
+			lines[i] = "state: " + s.Yellow(state) + newline
+		}
+	}
+
+	return strings.Join(lines, "")
+}
+
+// Error formats an error line ("error: ...") with the prefix in red.
+func (s Styler) Error(message string) string {
+	return s.Red("error:") + " " + message
+}
@@ -0,0 +1,76 @@
+package output
+
+import (
+	"os"
+	"testing"
+)
+
+func TestParseColorMode(t *testing.T) {
+	for value, want := range map[string]ColorMode{"auto": ColorAuto, "always": ColorAlways, "never": ColorNever} {
+		got, err := ParseColorMode(value)
+		if err != nil || got != want {
+			t.Errorf("ParseColorMode(%q) = %v, %v; want %v", value, got, err, want)
+		}
+	}
+
+	if _, err := ParseColorMode("sometimes"); err == nil {
+		t.Error("expected invalid mode to be rejected")
+	}
+}
+
+func TestNewStyler_Modes(t *testing.T) {
+	file, err := os.CreateTemp(t.TempDir(), "out")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	if !NewStyler(ColorAlways, file).Enabled() {
+		t.Error("always should color even when not writing to a terminal")
+	}
+	if NewStyler(ColorNever, file).Enabled() {
+		t.Error("never should not color")
+	}
+	if NewStyler(ColorAuto, file).Enabled() {
+		t.Error("auto should not color a regular file")
+	}
+}
+
+func TestNewStyler_AutoHonorsNoColor(t *testing.T) {
+	t.Setenv("NO_COLOR", "1")
+
+	tty, err := os.Open(os.DevNull) // a character device, like a terminal
+	if err != nil {
+		t.Skip("no character device available")
+	}
+	defer tty.Close()
+
+	if NewStyler(ColorAuto, tty).Enabled() {
+		t.Error("auto should not color when NO_COLOR is set")
+	}
+	if !NewStyler(ColorAlways, tty).Enabled() {
+		t.Error("always should override NO_COLOR")
+	}
+}
+
+func TestStyler_Fields(t *testing.T) {
+	text := "id: a\nstate: sealed\n\nid: b\nstate: unlocked\ninput_type: stdin\n"
+
+	if got := (Styler{}).Fields(text); got != text {
+		t.Errorf("disabled styler changed text: %q", got)
+	}
+
+	want := "id: a\nstate: \x1b[33msealed\x1b[0m\n\nid: b\nstate: \x1b[32munlocked\x1b[0m\ninput_type: stdin\n"
+	if got := (Styler{enabled: true}).Fields(text); got != want {
+		t.Errorf("unexpected colored output:\n got: %q\nwant: %q", got, want)
+	}
+}
+
+func TestStyler_Error(t *testing.T) {
+	if got := (Styler{}).Error("item corrupted"); got != "error: item corrupted" {
+		t.Errorf("unexpected plain error line: %q", got)
+	}
+	if got := (Styler{enabled: true}).Error("item corrupted"); got != "\x1b[31merror:\x1b[0m item corrupted" {
+		t.Errorf("unexpected colored error line: %q", got)
+	}
+}
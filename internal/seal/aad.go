@@ -0,0 +1,34 @@
+package seal
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// aadVersion is the additional authenticated data scheme used for new items.
+// Version 0 (absent) marks legacy items encrypted without AAD.
+const aadVersion = 1
+
+// payloadAAD returns the additional authenticated data binding a payload to
+// the item's immutable metadata. Swapping payloads between items, or editing
+// any of these fields in meta.json, makes decryption fail.
+func payloadAAD(id string, targetRound uint64, algorithm string) []byte {
+	return []byte("seal-aad-v1\x00" + id + "\x00" + strconv.FormatUint(targetRound, 10) + "\x00" + algorithm)
+}
+
+// itemAAD returns the additional authenticated data to decrypt an item's payload with.
+// Legacy items without AAD return nil and decrypt as before.
+func itemAAD(item SealedItem) ([]byte, error) {
+	switch item.AADVersion {
+	case 0:
+		return nil, nil
+	case aadVersion:
+		targetRound, err := extractTargetRound(item.KeyRef)
+		if err != nil {
+			return nil, err
+		}
+		return payloadAAD(item.ID, targetRound, item.Algorithm), nil
+	default:
+		return nil, fmt.Errorf("unsupported aad_version %d", item.AADVersion)
+	}
+}
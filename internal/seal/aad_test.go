@@ -0,0 +1,125 @@
+package seal
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"seal/internal/testutil"
+	"seal/internal/timeauth"
+)
+
+func TestMaterialize_SwappedPayload_FailsDecryption(t *testing.T) {
+	_, cleanup := testutil.SetupTestEnv(t)
+	defer cleanup()
+
+	authority := &timeauth.FakeAuthority{DefaultRound: 100, CurrentRound: 200}
+	unlockTime := time.Now().UTC().Add(time.Hour)
+	idA, err := CreateSealedItem(unlockTime, InputSourceStdin, "", []byte("item a"), authority)
+	if err != nil {
+		t.Fatalf("CreateSealedItem failed: %v", err)
+	}
+	idB, err := CreateSealedItem(unlockTime, InputSourceStdin, "", []byte("item b"), authority)
+	if err != nil {
+		t.Fatalf("CreateSealedItem failed: %v", err)
+	}
+
+	itemA, dirA, _ := LoadItem(idA)
+	itemB, dirB, _ := LoadItem(idB)
+	if itemA.AADVersion != aadVersion {
+		t.Fatalf("expected aad_version %d, got %d", aadVersion, itemA.AADVersion)
+	}
+
+	// Move B's payload and all of its crypto metadata into A
+	payloadB, _ := os.ReadFile(filepath.Join(dirB, "payload.bin"))
+	if err := os.WriteFile(filepath.Join(dirA, "payload.bin"), payloadB, 0600); err != nil {
+		t.Fatal(err)
+	}
+	itemA.Nonce = itemB.Nonce
+	itemA.DEKTlockB64 = itemB.DEKTlockB64
+	itemA.PayloadSHA256 = itemB.PayloadSHA256
+
+	result, err := TryMaterialize(itemA, dirA, authority)
+	if err == nil || !strings.Contains(err.Error(), "does not belong to this metadata") {
+		t.Fatalf("expected swapped payload to fail decryption, got: %v", err)
+	}
+	if result.State != StateSealed {
+		t.Errorf("item should stay sealed, got %s", result.State)
+	}
+}
+
+func TestMaterialize_EditedRound_FailsDecryption(t *testing.T) {
+	_, cleanup := testutil.SetupTestEnv(t)
+	defer cleanup()
+
+	authority := &timeauth.FakeAuthority{DefaultRound: 100, CurrentRound: 200}
+	id, err := CreateSealedItem(time.Now().UTC().Add(time.Hour), InputSourceStdin, "", []byte("data"), authority)
+	if err != nil {
+		t.Fatalf("CreateSealedItem failed: %v", err)
+	}
+
+	item, itemDir, _ := LoadItem(id)
+	item.KeyRef = `{"network":"fake","target_round":150}`
+
+	if _, err := TryMaterialize(item, itemDir, authority); err == nil {
+		t.Fatal("expected edited target round to fail decryption")
+	}
+}
+
+func TestMaterialize_LegacyItemWithoutAAD_Decrypts(t *testing.T) {
+	_, cleanup := testutil.SetupTestEnv(t)
+	defer cleanup()
+
+	authority := &timeauth.FakeAuthority{DefaultRound: 100, CurrentRound: 200}
+	id, err := CreateSealedItem(time.Now().UTC().Add(time.Hour), InputSourceStdin, "", []byte("placeholder"), authority)
+	if err != nil {
+		t.Fatalf("CreateSealedItem failed: %v", err)
+	}
+	item, itemDir, _ := LoadItem(id)
+
+	// Rewrite the item as it was stored before payloads were bound to metadata
+	ciphertext, nonceB64, dek, err := EncryptPayload([]byte("legacy secret"))
+	if err != nil {
+		t.Fatalf("EncryptPayload failed: %v", err)
+	}
+	tlock, err := authority.TimeLockEncrypt(dek, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(itemDir, "payload.bin"), ciphertext, 0600); err != nil {
+		t.Fatal(err)
+	}
+	item.AADVersion = 0
+	item.Nonce = nonceB64
+	item.DEKTlockB64 = tlock
+	item.PayloadSHA256 = payloadChecksum(ciphertext)
+	if err := saveMetadata(itemDir, item); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := TryMaterialize(item, itemDir, authority)
+	if err != nil {
+		t.Fatalf("TryMaterialize failed: %v", err)
+	}
+	if result.State != StateUnlocked {
+		t.Fatalf("expected legacy item to unlock, got %s", result.State)
+	}
+
+	data, _ := os.ReadFile(UnsealedPath(result, itemDir))
+	if string(data) != "legacy secret" {
+		t.Errorf("unexpected unsealed content: %q", data)
+	}
+
+	// require-aad refuses legacy items but not bound ones
+	run := &statusRun{cfg: Config{RequireAAD: true}}
+	item.State = StateSealed
+	if err := run.checkAAD(item); err == nil {
+		t.Error("expected require-aad to refuse a legacy item")
+	}
+	item.AADVersion = aadVersion
+	if err := run.checkAAD(item); err != nil {
+		t.Errorf("require-aad refused a bound item: %v", err)
+	}
+}
@@ -0,0 +1,156 @@
+package seal
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"seal/internal/testutil"
+)
+
+// lockAndExtractDir seals dir, unlocks it and returns the item and the extracted tree.
+func lockAndExtractDir(t *testing.T, dir string) (SealedItem, string) {
+	t.Helper()
+
+	result, err := Lock(LockRequest{InputPath: dir, For: "1h", Authority: "registered-fake"})
+	if err != nil {
+		t.Fatalf("Lock failed: %v", err)
+	}
+	item, itemDir, _ := LoadItem(result.ID)
+	item, err = CheckAndTransitionUnlock(item, itemDir)
+	if err != nil || item.State != StateUnlocked {
+		t.Fatalf("expected item to unlock: %s, %v", item.State, err)
+	}
+	item, err = runPendingPostProcess(item, itemDir)
+	if err != nil {
+		t.Fatalf("extraction failed: %v", err)
+	}
+	if len(item.PostProcessResults) != 1 || item.PostProcessResults[0].Status != PostProcessStatusOK {
+		t.Fatalf("unexpected post-processing results: %+v", item.PostProcessResults)
+	}
+	return item, item.PostProcessResults[0].Output
+}
+
+func TestLock_Directory(t *testing.T) {
+	tmpDir, cleanup := testutil.SetupTestEnv(t)
+	defer cleanup()
+	registerFakeAuthority()
+
+	dir := filepath.Join(tmpDir, "project")
+	files := map[string]string{
+		"README.md":         "launch plan",
+		"docs/notes.txt":    "first notes",
+		"docs/deep/key.txt": "secret",
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		os.MkdirAll(filepath.Dir(path), 0700)
+		if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	os.MkdirAll(filepath.Join(dir, "empty"), 0700)
+
+	item, extracted := lockAndExtractDir(t, dir)
+
+	if item.InputType != "directory" || item.OriginalPath != dir {
+		t.Errorf("unexpected input type %q, original path %q", item.InputType, item.OriginalPath)
+	}
+	if item.Archive == nil || *item.Archive != (ArchiveInfo{Format: ArchiveFormatTar, Files: 3, Dirs: 4}) {
+		t.Errorf("unexpected archive info %+v", item.Archive)
+	}
+
+	// The tree is restored under the directory's own name
+	for name, content := range files {
+		got, err := os.ReadFile(filepath.Join(extracted, "project", filepath.FromSlash(name)))
+		if err != nil || string(got) != content {
+			t.Errorf("%s: got %q, %v", name, got, err)
+		}
+	}
+	if info, err := os.Stat(filepath.Join(extracted, "project", "empty")); err != nil || !info.IsDir() {
+		t.Errorf("empty directory not restored: %v", err)
+	}
+
+	// File names are not recorded in metadata
+	_, itemDir, _ := LoadItem(item.ID)
+	meta, _ := os.ReadFile(filepath.Join(itemDir, "meta.json"))
+	if bytes.Contains(meta, []byte("notes.txt")) {
+		t.Error("metadata should not contain file names")
+	}
+
+	if output := FormatInspectOutput(item, nil, false); !strings.Contains(output, "archive: tar (files: 3, directories: 4)") {
+		t.Errorf("inspect should show the archive, got:\n%s", output)
+	}
+}
+
+func TestLock_LargeDirectoryIsStreamed(t *testing.T) {
+	tmpDir, cleanup := testutil.SetupTestEnv(t)
+	defer cleanup()
+	registerFakeAuthority()
+
+	dir := filepath.Join(tmpDir, "large")
+	os.MkdirAll(dir, 0700)
+	content := bytes.Repeat([]byte("0123456789abcdef"), MaxInputSize/16+1)
+	if err := os.WriteFile(filepath.Join(dir, "data.bin"), content, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	_, extracted := lockAndExtractDir(t, dir)
+
+	got, err := os.ReadFile(filepath.Join(extracted, "large", "data.bin"))
+	if err != nil || !bytes.Equal(got, content) {
+		t.Errorf("streamed directory not restored: %d bytes, %v", len(got), err)
+	}
+}
+
+func TestLock_DirectoryRefusals(t *testing.T) {
+	tmpDir, cleanup := testutil.SetupTestEnv(t)
+	defer cleanup()
+	registerFakeAuthority()
+
+	empty := filepath.Join(tmpDir, "empty")
+	os.MkdirAll(filepath.Join(empty, "sub"), 0700)
+
+	withFile := filepath.Join(tmpDir, "with-file")
+	os.MkdirAll(withFile, 0700)
+	os.WriteFile(filepath.Join(withFile, "a.txt"), []byte("a"), 0600)
+
+	testCases := []struct {
+		name    string
+		req     LockRequest
+		wantErr string
+	}{
+		{"no files", LockRequest{InputPath: empty}, "directory has no files"},
+		{"shred", LockRequest{InputPath: withFile, Shred: true}, "--shred is not supported for directory input"},
+		{"post-process", LockRequest{InputPath: withFile, PostProcess: []string{PostProcessGunzip}}, "--post-process is not supported"},
+		{"stdin", LockRequest{InputPath: withFile, Stdin: StdinRequired}, "both a directory and stdin"},
+	}
+	if runtime.GOOS != "windows" {
+		withLink := filepath.Join(tmpDir, "with-link")
+		os.MkdirAll(withLink, 0700)
+		os.Symlink("/etc/passwd", filepath.Join(withLink, "link"))
+		testCases = append(testCases, struct {
+			name    string
+			req     LockRequest
+			wantErr string
+		}{"symlink", LockRequest{InputPath: withLink}, "only regular files and directories are supported"})
+	}
+
+	for _, tc := range testCases {
+		tc.req.For = "1h"
+		tc.req.Authority = "registered-fake"
+		_, err := Lock(tc.req)
+		if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+			t.Errorf("%s: expected error containing %q, got %v", tc.name, tc.wantErr, err)
+		}
+	}
+
+	// Nothing was sealed
+	items, _ := ListSealedItems(ListOptions{})
+	if len(items) != 0 {
+		t.Errorf("expected no items, got %d", len(items))
+	}
+}
@@ -0,0 +1,173 @@
+package seal
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"seal/internal/timeauth"
+)
+
+// An armored item is a bundle encoded as a PEM block, so it can be pasted into
+// an email or a chat. The Item and Unlock-Time headers are for people reading the
+// block; only the bundle inside is trusted.
+const (
+	armorBlockType = "SEAL ITEM"
+
+	// maxArmoredSize caps the armored text read back: a bundle of MaxInputSize
+	// in base64, plus headers and line breaks.
+	maxArmoredSize = MaxInputSize/3*4 + maxBundleMetadata + maxBundleManifest
+)
+
+// ArmorItem writes a sealed item to w as an ASCII-armored block.
+// The item must be sealed and pass CheckIntegrity, as for ExportBundle; it stays in the store.
+// Payloads larger than MaxInputSize are refused: use a bundle file for those.
+func ArmorItem(id string, w io.Writer) error {
+	src, err := loadBundleSource(id)
+	if err != nil {
+		return err
+	}
+	defer src.payload.Close()
+
+	if src.payloadSize > MaxInputSize {
+		return fmt.Errorf("item %s is too large to armor (%d bytes, at most %d); use seal export --out", id, src.payloadSize, MaxInputSize)
+	}
+
+	var bundle bytes.Buffer
+	if err := writeBundle(&bundle, src.manifest, src.metaJSON, src.payload, src.payloadSize); err != nil {
+		return fmt.Errorf("cannot write bundle: %w", err)
+	}
+
+	return pem.Encode(w, &pem.Block{
+		Type: armorBlockType,
+		Headers: map[string]string{
+			"Item":        src.item.ID,
+			"Unlock-Time": src.item.UnlockTime.UTC().Format(time.RFC3339),
+		},
+		Bytes: bundle.Bytes(),
+	})
+}
+
+// ArmoredContent is the unlocked content of an armored item.
+type ArmoredContent struct {
+	Item SealedItem
+	Data []byte
+}
+
+// OpenArmored unlocks an armored item read from r without adding it to the store.
+// Text around the block, such as the rest of an email, is ignored.
+// The content is returned as it was sealed: post-processing steps are not run,
+// and a sealed directory comes back as its tar archive.
+// If beacon is set, the item is unlocked offline with it, as for OpenWithBeacon.
+// Returns an error wrapping ErrStillSealed if the item has not unlocked yet.
+func OpenArmored(r io.Reader, beacon []byte) (ArmoredContent, error) {
+	text, err := io.ReadAll(io.LimitReader(r, maxArmoredSize+1))
+	if err != nil {
+		return ArmoredContent{}, fmt.Errorf("cannot read armored item: %w", err)
+	}
+	if len(text) > maxArmoredSize {
+		return ArmoredContent{}, errors.New("armored item is too large")
+	}
+
+	var block *pem.Block
+	for rest := text; ; {
+		block, rest = pem.Decode(rest)
+		if block == nil || block.Type == armorBlockType {
+			break
+		}
+	}
+	if block == nil {
+		return ArmoredContent{}, errors.New("no armored seal item found (expected a BEGIN SEAL ITEM block)")
+	}
+
+	// The payload is ciphertext; it is unpacked to a private temporary directory
+	// so the bundle checks apply unchanged
+	dir, err := os.MkdirTemp("", "seal-armor-")
+	if err != nil {
+		return ArmoredContent{}, fmt.Errorf("cannot create temporary directory: %w", err)
+	}
+	defer os.RemoveAll(dir)
+
+	item, _, err := readBundle(tar.NewReader(bytes.NewReader(block.Bytes)), dir)
+	if err != nil {
+		return ArmoredContent{}, fmt.Errorf("invalid armored item: %w", err)
+	}
+
+	var authority timeauth.Authority
+	if beacon != nil {
+		authority, err = beaconAuthority(item, beacon)
+		if err != nil {
+			return ArmoredContent{Item: item}, err
+		}
+	} else if authority = authorityForItem(item); authority == nil {
+		return ArmoredContent{Item: item}, fmt.Errorf("item %s: time authority %q is not available in this build", item.ID, item.TimeAuthority)
+	}
+
+	data, err := decryptDetached(item, filepath.Join(dir, "payload.bin"), authority)
+	if err != nil {
+		return ArmoredContent{Item: item}, err
+	}
+	return ArmoredContent{Item: item, Data: data}, nil
+}
+
+// decryptDetached decrypts the payload of an item that is not in the store.
+// Nothing is written: there is no unlock state to record.
+func decryptDetached(item SealedItem, payloadPath string, authority timeauth.Authority) ([]byte, error) {
+	targetRound, err := extractTargetRound(item.KeyRef)
+	if err != nil {
+		return nil, fmt.Errorf("item %s: %w", item.ID, err)
+	}
+	canUnlock, err := authority.CanUnlock(context.Background(), targetRound)
+	if err != nil {
+		return nil, fmt.Errorf("item %s: cannot check the time authority: %w", item.ID, err)
+	}
+	if !canUnlock {
+		return nil, fmt.Errorf("item %s is %w until %s", item.ID, ErrStillSealed, item.UnlockTime.UTC().Format(time.RFC3339))
+	}
+
+	if isTlockFile(item) {
+		ciphertext, err := os.ReadFile(payloadPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read payload: %w", err)
+		}
+		return authority.TimeLockDecrypt(context.Background(), base64.StdEncoding.EncodeToString(ciphertext))
+	}
+
+	dek, err := unlockDEK(item, authority)
+	if err != nil {
+		return nil, fmt.Errorf("item %s: %w", item.ID, err)
+	}
+	defer func() {
+		for i := range dek {
+			dek[i] = 0
+		}
+	}()
+
+	if isStreamed(item) {
+		payload, err := os.Open(payloadPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read payload: %w", err)
+		}
+		defer payload.Close()
+
+		var plaintext bytes.Buffer
+		if _, err := decryptStream(&plaintext, payload, item, dek); err != nil {
+			return nil, err
+		}
+		return plaintext.Bytes(), nil
+	}
+
+	ciphertext, err := os.ReadFile(payloadPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read payload: %w", err)
+	}
+	return decryptPayload(item, nil, ciphertext, dek)
+}
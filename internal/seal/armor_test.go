@@ -0,0 +1,78 @@
+package seal
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"seal/internal/testutil"
+	"seal/internal/timeauth"
+)
+
+func TestArmor_RoundTrip(t *testing.T) {
+	_, cleanup := testutil.SetupTestEnv(t)
+	defer cleanup()
+	registerFakeAuthority()
+
+	authority := &timeauth.FakeAuthority{AuthorityName: "registered-fake", DefaultRound: 100}
+	id, err := CreateSealedItem(time.Now().UTC().Add(time.Hour), InputSourceStdin, "", []byte("armored data"), authority)
+	if err != nil {
+		t.Fatalf("CreateSealedItem failed: %v", err)
+	}
+
+	var armored bytes.Buffer
+	if err := ArmorItem(id, &armored); err != nil {
+		t.Fatalf("ArmorItem failed: %v", err)
+	}
+	text := armored.String()
+	if !strings.HasPrefix(text, "-----BEGIN SEAL ITEM-----\n") || !strings.Contains(text, "Item: "+id+"\n") {
+		t.Errorf("unexpected armor:\n%s", text)
+	}
+	if strings.Contains(text, "armored data") {
+		t.Error("armor must not contain the plaintext")
+	}
+
+	// Pasted into an email: surrounding text is ignored, and the store is not touched
+	email := "Hi,\n\nopen this next week:\n\n" + text + "\nBye\n"
+	content, err := OpenArmored(strings.NewReader(email), nil)
+	if err != nil {
+		t.Fatalf("OpenArmored failed: %v", err)
+	}
+	if string(content.Data) != "armored data" || content.Item.ID != id {
+		t.Errorf("unexpected content %q of %s", content.Data, content.Item.ID)
+	}
+	if item, _, _ := LoadItem(id); item.State != StateSealed {
+		t.Errorf("stored item changed to %s", item.State)
+	}
+
+	if _, err := OpenArmored(strings.NewReader("no block here"), nil); err == nil || !strings.Contains(err.Error(), "no armored seal item found") {
+		t.Errorf("expected a missing block to be reported, got %v", err)
+	}
+	corrupted := strings.Replace(text, "\n", "\nAAAA", 3)
+	if _, err := OpenArmored(strings.NewReader(corrupted), nil); err == nil {
+		t.Error("expected a corrupted block to be refused")
+	}
+}
+
+func TestOpenArmored_StillSealed(t *testing.T) {
+	_, cleanup := testutil.SetupTestEnv(t)
+	defer cleanup()
+	registerFakeAuthority()
+
+	// The registered authority is at round 200
+	authority := &timeauth.FakeAuthority{AuthorityName: "registered-fake", DefaultRound: 300}
+	id, err := CreateSealedItem(time.Now().UTC().Add(time.Hour), InputSourceStdin, "", []byte("not yet"), authority)
+	if err != nil {
+		t.Fatalf("CreateSealedItem failed: %v", err)
+	}
+
+	var armored bytes.Buffer
+	if err := ArmorItem(id, &armored); err != nil {
+		t.Fatalf("ArmorItem failed: %v", err)
+	}
+	if _, err := OpenArmored(&armored, nil); !errors.Is(err, ErrStillSealed) {
+		t.Errorf("expected ErrStillSealed, got %v", err)
+	}
+}
@@ -0,0 +1,171 @@
+package seal
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"seal/internal/clock"
+)
+
+// Store writes are crash-safe: a crash leaves either the old state or the new one,
+// never a half-written file or a half-created item.
+//
+//   - Files that are replaced, such as meta.json and config.json, are written by
+//     writeFileAtomic: a synced temporary file renamed over the old one.
+//   - New items are built by createItemStaged in a staging directory, which is
+//     renamed into the store only once it is complete.
+//
+// What a crash leaves behind is removed by RecoverStore, which every command runs.
+
+// tmpSuffix marks a file written by writeFileAtomic that has not been renamed yet.
+const tmpSuffix = ".tmp"
+
+// Staging directories in the store; names are not UUIDs, so they are never taken for items.
+const (
+	stagingPrefix       = ".staging-"
+	importStagingPrefix = ".import-"
+)
+
+// staleWriteAge is how long a temporary file or staging directory must be left
+// untouched before RecoverStore removes it, so writes still in progress in
+// another seal process are not disturbed.
+const staleWriteAge = time.Hour
+
+// writeFileAtomic replaces path with data: the data is written and synced to a
+// temporary file next to it, renamed over path, and the directory is synced.
+func writeFileAtomic(path string, data []byte, perm os.FileMode) error {
+	tmpPath := path + tmpSuffix
+	if err := writeFileNoFollow(tmpPath, data, perm); err != nil {
+		return err
+	}
+
+	if err := os.Rename(tmpPath, path); err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+
+	// The new file is in place either way; syncing only makes the rename durable sooner
+	syncDir(filepath.Dir(path))
+	return nil
+}
+
+// createItemStaged creates the item directory for id: write fills a staging
+// directory, which is synced and renamed into the store. On failure nothing is
+// left in the store.
+func createItemStaged(baseDir, id string, write func(stagingDir string) error) error {
+	stagingDir := filepath.Join(baseDir, stagingPrefix+id)
+	if err := os.Mkdir(stagingDir, 0700); err != nil {
+		return fmt.Errorf("cannot create item directory: %w", err)
+	}
+
+	if err := write(stagingDir); err != nil {
+		os.RemoveAll(stagingDir)
+		return err
+	}
+	syncDir(stagingDir)
+
+	if err := os.Rename(stagingDir, filepath.Join(baseDir, id)); err != nil {
+		os.RemoveAll(stagingDir)
+		return fmt.Errorf("cannot move item into the store: %w", err)
+	}
+	syncDir(baseDir)
+	return nil
+}
+
+// newImportStagingDir creates a staging directory for an item whose ID is not known yet.
+func newImportStagingDir(baseDir string) (string, error) {
+	suffix := make([]byte, 8)
+	if _, err := rand.Read(suffix); err != nil {
+		return "", err
+	}
+	stagingDir := filepath.Join(baseDir, importStagingPrefix+hex.EncodeToString(suffix))
+	if err := os.Mkdir(stagingDir, 0700); err != nil {
+		return "", fmt.Errorf("cannot create staging directory: %w", err)
+	}
+	return stagingDir, nil
+}
+
+// RecoverStore removes what interrupted writes left in the store: staging
+// directories of items that were never created, and temporary files that were
+// never renamed into place. Only leftovers untouched for staleWriteAge are removed.
+// Interrupted unlocks are recovered per item, see recoverPendingUnseal.
+// Returns the paths removed. A missing store is not an error.
+func RecoverStore() ([]string, error) {
+	baseDir, err := GetSealBaseDir()
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := os.ReadDir(baseDir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("cannot read seal directory: %w", err)
+	}
+
+	var removed []string
+	remove := func(path string, removeFn func(string) error) {
+		if isStale(path) && removeFn(path) == nil {
+			removed = append(removed, path)
+		}
+	}
+
+	for _, entry := range entries {
+		path := filepath.Join(baseDir, entry.Name())
+		switch {
+		case !entry.IsDir():
+			if strings.HasSuffix(entry.Name(), tmpSuffix) {
+				remove(path, os.Remove)
+			}
+		case strings.HasPrefix(entry.Name(), stagingPrefix), strings.HasPrefix(entry.Name(), importStagingPrefix):
+			remove(path, os.RemoveAll)
+		case entry.Name() == cacheDirName:
+			cached, _ := os.ReadDir(path)
+			for _, file := range cached {
+				if !file.IsDir() && strings.HasSuffix(file.Name(), tmpSuffix) {
+					remove(filepath.Join(path, file.Name()), os.Remove)
+				}
+			}
+		default:
+			tmpMeta := filepath.Join(path, "meta.json"+tmpSuffix)
+			if info, err := os.Lstat(tmpMeta); err == nil && info.Mode().IsRegular() {
+				remove(tmpMeta, os.Remove)
+			}
+		}
+	}
+
+	return removed, nil
+}
+
+// isStale reports whether nothing at path, or directly inside it, changed within staleWriteAge.
+func isStale(path string) bool {
+	info, err := os.Lstat(path)
+	if err != nil {
+		return false
+	}
+	latest := info.ModTime()
+
+	if info.IsDir() {
+		entries, err := os.ReadDir(path)
+		if err != nil {
+			return false
+		}
+		for _, entry := range entries {
+			entryInfo, err := entry.Info()
+			if err != nil {
+				return false
+			}
+			if entryInfo.ModTime().After(latest) {
+				latest = entryInfo.ModTime()
+			}
+		}
+	}
+
+	return clock.Since(latest) >= staleWriteAge
+}
@@ -0,0 +1,117 @@
+package seal
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"seal/internal/testutil"
+	"seal/internal/timeauth"
+)
+
+func TestWriteFileAtomic(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "meta.json")
+	if err := os.WriteFile(path, []byte("old"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := writeFileAtomic(path, []byte("new"), 0600); err != nil {
+		t.Fatalf("writeFileAtomic failed: %v", err)
+	}
+	if data, _ := os.ReadFile(path); string(data) != "new" {
+		t.Errorf("unexpected content %q", data)
+	}
+	if _, err := os.Lstat(path + tmpSuffix); !os.IsNotExist(err) {
+		t.Error("temporary file left behind")
+	}
+}
+
+func TestCreateItemStaged_FailureLeavesNothing(t *testing.T) {
+	_, cleanup := testutil.SetupTestEnv(t)
+	defer cleanup()
+
+	// The authority fails after the payload is encrypted, before the item is complete
+	authority := &timeauth.FakeAuthority{DefaultRound: 100, EncryptError: os.ErrDeadlineExceeded}
+	if _, err := CreateSealedItem(time.Now().UTC().Add(time.Hour), InputSourceStdin, "", []byte("data"), authority); err == nil {
+		t.Fatal("expected CreateSealedItem to fail")
+	}
+
+	baseDir, _ := GetSealBaseDir()
+	err := createItemStaged(baseDir, "00000000-0000-0000-0000-000000000001", func(dir string) error {
+		if err := os.WriteFile(filepath.Join(dir, "payload.bin"), []byte("partial"), 0600); err != nil {
+			t.Fatal(err)
+		}
+		return os.ErrClosed
+	})
+	if err == nil {
+		t.Fatal("expected the write error to be returned")
+	}
+
+	entries, _ := os.ReadDir(baseDir)
+	for _, entry := range entries {
+		if entry.IsDir() {
+			t.Errorf("unexpected directory left in the store: %s", entry.Name())
+		}
+	}
+}
+
+func TestRecoverStore(t *testing.T) {
+	_, cleanup := testutil.SetupTestEnv(t)
+	defer cleanup()
+
+	authority := &timeauth.FakeAuthority{DefaultRound: 100}
+	id, err := CreateSealedItem(time.Now().UTC().Add(time.Hour), InputSourceStdin, "", []byte("data"), authority)
+	if err != nil {
+		t.Fatalf("CreateSealedItem failed: %v", err)
+	}
+	baseDir, _ := GetSealBaseDir()
+	itemDir := filepath.Join(baseDir, id)
+
+	// Leftovers of a crash: an item that never finished, a metadata and a config update never renamed
+	staging := filepath.Join(baseDir, stagingPrefix+"00000000-0000-0000-0000-000000000002")
+	importStaging := filepath.Join(baseDir, importStagingPrefix+"0123456789abcdef")
+	tmpMeta := filepath.Join(itemDir, "meta.json"+tmpSuffix)
+	tmpConfig := filepath.Join(baseDir, configFileName+tmpSuffix)
+	for _, dir := range []string{staging, importStaging} {
+		if err := os.Mkdir(dir, 0700); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, "payload.bin"), []byte("partial"), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, path := range []string{tmpMeta, tmpConfig} {
+		if err := os.WriteFile(path, []byte("{"), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Writes that may still be in progress are left alone
+	if removed, err := RecoverStore(); err != nil || len(removed) != 0 {
+		t.Fatalf("expected recent leftovers to be kept, got %v, %v", removed, err)
+	}
+
+	old := time.Now().Add(-2 * staleWriteAge)
+	for _, path := range []string{staging, filepath.Join(staging, "payload.bin"), importStaging, filepath.Join(importStaging, "payload.bin"), tmpMeta, tmpConfig} {
+		if err := os.Chtimes(path, old, old); err != nil {
+			t.Fatal(err)
+		}
+	}
+	removed, err := RecoverStore()
+	if err != nil || len(removed) != 4 {
+		t.Fatalf("expected four leftovers to be removed, got %v, %v", removed, err)
+	}
+	for _, path := range []string{staging, importStaging, tmpMeta, tmpConfig} {
+		if _, err := os.Lstat(path); !os.IsNotExist(err) {
+			t.Errorf("%s was not removed", path)
+		}
+	}
+
+	if item, _, err := LoadItem(id); err != nil || item.ID != id {
+		t.Errorf("the complete item should be untouched: %v", err)
+	}
+	if removed, err := RecoverStore(); err != nil || len(removed) != 0 {
+		t.Errorf("expected nothing left to recover, got %v, %v", removed, err)
+	}
+}
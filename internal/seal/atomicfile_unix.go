@@ -0,0 +1,15 @@
+//go:build !windows
+
+package seal
+
+import "os"
+
+// syncDir flushes a directory, making entries created, renamed or removed in it durable.
+func syncDir(dir string) error {
+	d, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer d.Close()
+	return d.Sync()
+}
@@ -0,0 +1,7 @@
+package seal
+
+// syncDir does nothing: Windows cannot sync directories, and NTFS journals
+// renames and removals itself.
+func syncDir(dir string) error {
+	return nil
+}
@@ -0,0 +1,437 @@
+package seal
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"seal/internal/clock"
+)
+
+// AttestationOpenTimestamps is the only attestation kind in use.
+const AttestationOpenTimestamps = "opentimestamps"
+
+// DefaultAttestCalendars are the public OpenTimestamps calendars attestations are
+// submitted to, the same ones the ots client uses.
+var DefaultAttestCalendars = []string{
+	"https://a.pool.opentimestamps.org",
+	"https://b.pool.opentimestamps.org",
+	"https://a.pool.eternitywall.com",
+	"https://ots.btc.catallaxy.com",
+}
+
+// DefaultBitcoinExplorer is the Esplora API Bitcoin block headers are read from.
+const DefaultBitcoinExplorer = "https://blockstream.info/api"
+
+// maxCalendarResponse caps a calendar's answer; a timestamp is a few hundred bytes.
+const maxCalendarResponse = 10000
+
+// attestHTTPClient is used for calendar and explorer requests. Replaced in tests.
+var attestHTTPClient = &http.Client{Timeout: 30 * time.Second}
+
+// AttestConfig selects the services used by --attest and seal attest.
+type AttestConfig struct {
+	Calendars []string `json:"calendars,omitempty"` // OpenTimestamps calendar URLs (default DefaultAttestCalendars)
+	Explorer  string   `json:"explorer,omitempty"`  // Esplora API for Bitcoin block headers (default DefaultBitcoinExplorer)
+}
+
+// Attestation is a timestamp proof that an item's ciphertext existed.
+type Attestation struct {
+	Kind        string    `json:"kind"` // AttestationOpenTimestamps
+	SubmittedAt time.Time `json:"submitted_at"`
+	Proof       string    `json:"proof"` // base64 OpenTimestamps proof for payload.bin, upgraded by VerifyAttestation
+}
+
+// AttestationResult is the outcome of verifying an item's attestation.
+type AttestationResult struct {
+	ID               string
+	CiphertextSHA256 string
+	SubmittedAt      time.Time
+	Confirmed        bool
+	BlockHeight      uint64    // earliest Bitcoin block committing to the ciphertext
+	BlockTime        time.Time // the ciphertext existed before this time
+	Pending          []string  // calendars yet to commit the proof to Bitcoin, if not confirmed
+	Warnings         []string
+}
+
+// AttestItem timestamps an item's ciphertext with OpenTimestamps: the SHA-256 of
+// payload.bin, blinded with a random nonce, is submitted to every configured
+// calendar, and the calendars' promises to commit it to Bitcoin are stored as the
+// item's attestation. Only the hash leaves the machine. At least one calendar must
+// accept it; the others are reported as warnings. An item is attested once.
+func AttestItem(id string) ([]string, error) {
+	_, itemDir, err := LoadItem(id)
+	if err != nil {
+		return nil, err
+	}
+	lock, err := lockItem(itemDir)
+	if err != nil {
+		return nil, err
+	}
+	defer lock.unlock()
+	item, err := loadMetadata(itemDir)
+	if err != nil {
+		return nil, err
+	}
+	if item.Attestation != nil {
+		return nil, fmt.Errorf("item %s is already attested", item.ID)
+	}
+
+	cfg, err := LoadConfig()
+	if err != nil {
+		return nil, err
+	}
+	calendars := cfg.Attest.Calendars
+	if len(calendars) == 0 {
+		calendars = DefaultAttestCalendars
+	}
+
+	digest, err := payloadDigest(item, itemDir)
+	if err != nil {
+		return nil, err
+	}
+
+	// The nonce keeps calendars from learning the ciphertext hash, as the ots client does
+	nonce := make([]byte, 16)
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	appended := otsOp{tag: otsOpAppend, arg: nonce}
+	appendedMsg, _ := appended.apply(digest)
+	hashed := otsOp{tag: otsOpSHA256}
+	tipMsg, _ := hashed.apply(appendedMsg)
+	tip := &otsTimestamp{msg: tipMsg}
+	hashed.next = tip
+	appended.next = &otsTimestamp{msg: appendedMsg, ops: []otsOp{hashed}}
+	root := &otsTimestamp{msg: digest, ops: []otsOp{appended}}
+
+	var warnings []string
+	for _, calendar := range calendars {
+		stamp, err := submitToCalendar(calendar, tipMsg)
+		if err != nil {
+			warnings = append(warnings, fmt.Sprintf("warning: calendar %s did not accept the attestation: %v", calendar, err))
+			continue
+		}
+		tip.merge(stamp)
+	}
+	if len(tip.attestations) == 0 && len(tip.ops) == 0 {
+		return warnings, errors.New("no calendar accepted the attestation")
+	}
+
+	item.Attestation = &Attestation{
+		Kind:        AttestationOpenTimestamps,
+		SubmittedAt: clock.UTC().Truncate(time.Second),
+		Proof:       base64.StdEncoding.EncodeToString(marshalOTSProof(otsProof{timestamp: root})),
+	}
+	appendHistory(&item, HistoryAttested, AttestationOpenTimestamps)
+	if err := saveMetadata(itemDir, item); err != nil {
+		return warnings, err
+	}
+	return warnings, nil
+}
+
+// VerifyAttestation checks an item's attestation against its ciphertext and
+// against Bitcoin. Calendars are first asked for the Bitcoin part of proofs that
+// are still pending, and the completed proof is stored. A proof committed to a
+// Bitcoin block shows the ciphertext existed before that block's time; the block
+// header is read from the configured explorer and checked against its hash.
+// A proof that does not match the ciphertext or the block is an error.
+func VerifyAttestation(id string) (AttestationResult, error) {
+	_, itemDir, err := LoadItem(id)
+	if err != nil {
+		return AttestationResult{}, err
+	}
+	lock, err := lockItem(itemDir)
+	if err != nil {
+		return AttestationResult{}, err
+	}
+	defer lock.unlock()
+	item, err := loadMetadata(itemDir)
+	if err != nil {
+		return AttestationResult{}, err
+	}
+
+	proof, err := itemProof(item)
+	if err != nil {
+		return AttestationResult{}, err
+	}
+	digest, err := payloadDigest(item, itemDir)
+	if err != nil {
+		return AttestationResult{}, err
+	}
+	if !bytes.Equal(proof.timestamp.msg, digest) {
+		return AttestationResult{}, fmt.Errorf("item %s: attestation is for a different ciphertext (payload.bin was replaced, or the proof was)", item.ID)
+	}
+
+	result := AttestationResult{
+		ID:               item.ID,
+		CiphertextSHA256: hex.EncodeToString(digest),
+		SubmittedAt:      item.Attestation.SubmittedAt,
+	}
+
+	// Ask each calendar for the Bitcoin part of its promise
+	upgraded := false
+	var pending []*otsTimestamp
+	proof.timestamp.walk(func(node *otsTimestamp) {
+		for _, attestation := range node.attestations {
+			if _, ok := attestation.pendingURI(); ok {
+				pending = append(pending, node)
+				break
+			}
+		}
+	})
+	for _, node := range pending {
+		for _, attestation := range node.attestations {
+			uri, ok := attestation.pendingURI()
+			if !ok {
+				continue
+			}
+			if !validOTSCalendarURI(uri) {
+				result.Warnings = append(result.Warnings, fmt.Sprintf("warning: not asking calendar %q, which is not a plain https URL", uri))
+				continue
+			}
+			stamp, found, err := fetchFromCalendar(uri, node.msg)
+			if err != nil {
+				result.Warnings = append(result.Warnings, fmt.Sprintf("warning: calendar %s: %v", uri, err))
+				continue
+			}
+			if found {
+				node.merge(stamp)
+				upgraded = true
+			}
+		}
+	}
+	if upgraded {
+		item.Attestation.Proof = base64.StdEncoding.EncodeToString(marshalOTSProof(proof))
+		if err := saveMetadata(itemDir, item); err != nil {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("warning: failed to store the completed proof for item %s: %v", item.ID, err))
+		}
+	}
+
+	// Check every Bitcoin attestation; the earliest block dates the ciphertext
+	cfg, err := LoadConfig()
+	if err != nil {
+		return AttestationResult{}, err
+	}
+	explorer := cfg.Attest.Explorer
+	if explorer == "" {
+		explorer = DefaultBitcoinExplorer
+	}
+	var verifyErr error
+	proof.timestamp.walk(func(node *otsTimestamp) {
+		for _, attestation := range node.attestations {
+			height, ok := attestation.bitcoinHeight()
+			if !ok || verifyErr != nil {
+				continue
+			}
+			merkleRoot, blockTime, err := bitcoinBlockHeader(explorer, height)
+			if err != nil {
+				verifyErr = fmt.Errorf("cannot read bitcoin block %d: %w", height, err)
+				continue
+			}
+			if !bytes.Equal(node.msg, merkleRoot) {
+				verifyErr = fmt.Errorf("item %s: attestation is invalid: bitcoin block %d does not commit to it", item.ID, height)
+				continue
+			}
+			if !result.Confirmed || height < result.BlockHeight {
+				result.Confirmed = true
+				result.BlockHeight = height
+				result.BlockTime = blockTime
+			}
+		}
+	})
+	if verifyErr != nil {
+		return result, verifyErr
+	}
+
+	if !result.Confirmed {
+		proof.timestamp.walk(func(node *otsTimestamp) {
+			for _, attestation := range node.attestations {
+				if uri, ok := attestation.pendingURI(); ok {
+					result.Pending = append(result.Pending, uri)
+				}
+			}
+		})
+	}
+	return result, nil
+}
+
+// ExportAttestation returns an item's attestation as an .ots file, which the ots
+// client verifies against payload.bin, or against the ciphertext_sha256 of the
+// item's public commitment with ots verify -d.
+func ExportAttestation(id string) ([]byte, error) {
+	item, _, err := LoadItem(id)
+	if err != nil {
+		return nil, err
+	}
+	proof, err := itemProof(item)
+	if err != nil {
+		return nil, err
+	}
+	return marshalOTSProof(proof), nil
+}
+
+// FormatAttestationResult formats a verified attestation for display.
+func FormatAttestationResult(result AttestationResult) string {
+	out := fmt.Sprintf("id: %s\nciphertext_sha256: %s\nsubmitted_at: %s\n",
+		result.ID, result.CiphertextSHA256, result.SubmittedAt.UTC().Format(time.RFC3339))
+	if result.Confirmed {
+		return "confirmed: the ciphertext existed before " + result.BlockTime.UTC().Format(time.RFC3339) + "\n" + out +
+			fmt.Sprintf("bitcoin_block: %d\n", result.BlockHeight)
+	}
+	out = "pending: not yet committed to a bitcoin block\n" + out
+	for _, uri := range result.Pending {
+		out += fmt.Sprintf("pending_calendar: %s\n", uri)
+	}
+	return out
+}
+
+// itemProof decodes an item's attestation.
+func itemProof(item SealedItem) (otsProof, error) {
+	if item.Attestation == nil {
+		return otsProof{}, fmt.Errorf("item %s has no attestation", item.ID)
+	}
+	if item.Attestation.Kind != AttestationOpenTimestamps {
+		return otsProof{}, fmt.Errorf("item %s: unsupported attestation kind %q", item.ID, item.Attestation.Kind)
+	}
+	data, err := base64.StdEncoding.DecodeString(item.Attestation.Proof)
+	if err != nil {
+		return otsProof{}, fmt.Errorf("item %s: invalid attestation proof encoding: %w", item.ID, err)
+	}
+	proof, err := parseOTSProof(data)
+	if err != nil {
+		return otsProof{}, fmt.Errorf("item %s: %w", item.ID, err)
+	}
+	return proof, nil
+}
+
+// payloadDigest returns the SHA-256 of an item's payload.bin, checked against
+// the hash recorded at lock time.
+func payloadDigest(item SealedItem, itemDir string) ([]byte, error) {
+	checksum, err := fileSHA256(filepath.Join(itemDir, "payload.bin"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read payload: %w", err)
+	}
+	if item.PayloadSHA256 != "" && checksum != item.PayloadSHA256 {
+		return nil, fmt.Errorf("item %s: payload checksum mismatch (corrupted)", item.ID)
+	}
+	return hex.DecodeString(checksum)
+}
+
+// submitToCalendar submits a digest to an OpenTimestamps calendar and returns
+// its timestamp for the digest, ending in a pending attestation.
+func submitToCalendar(calendar string, digest []byte) (*otsTimestamp, error) {
+	req, err := http.NewRequest(http.MethodPost, strings.TrimRight(calendar, "/")+"/digest", bytes.NewReader(digest))
+	if err != nil {
+		return nil, err
+	}
+	stamp, _, err := calendarRequest(req, digest)
+	return stamp, err
+}
+
+// fetchFromCalendar asks a calendar for the completed timestamp of a commitment.
+// found is false while the calendar has not committed it to Bitcoin yet.
+func fetchFromCalendar(calendar string, commitment []byte) (stamp *otsTimestamp, found bool, err error) {
+	req, err := http.NewRequest(http.MethodGet, strings.TrimRight(calendar, "/")+"/timestamp/"+hex.EncodeToString(commitment), nil)
+	if err != nil {
+		return nil, false, err
+	}
+	return calendarRequest(req, commitment)
+}
+
+// calendarRequest sends a calendar request and parses the timestamp it returns for msg.
+func calendarRequest(req *http.Request, msg []byte) (*otsTimestamp, bool, error) {
+	req.Header.Set("Accept", "application/vnd.opentimestamps.v1")
+	req.Header.Set("User-Agent", "seal")
+
+	resp, err := attestHTTPClient.Do(req)
+	if err != nil {
+		return nil, false, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound && req.Method == http.MethodGet {
+		return nil, false, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, false, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxCalendarResponse+1))
+	if err != nil {
+		return nil, false, err
+	}
+	if len(body) > maxCalendarResponse {
+		return nil, false, errors.New("response too large")
+	}
+
+	r := bytes.NewReader(body)
+	stamp, err := parseOTSTimestamp(r, msg, otsMaxDepth)
+	if err != nil {
+		return nil, false, fmt.Errorf("invalid timestamp: %w", err)
+	}
+	if r.Len() != 0 {
+		return nil, false, errors.New("invalid timestamp: trailing data")
+	}
+	return stamp, true, nil
+}
+
+// bitcoinBlockHeader reads the header of the Bitcoin block at height from an
+// Esplora API and returns its merkle root, in header byte order, and its time.
+// The explorer is trusted to name the block at a height; the header is checked
+// against the block hash.
+func bitcoinBlockHeader(explorer string, height uint64) ([]byte, time.Time, error) {
+	explorer = strings.TrimRight(explorer, "/")
+	hash, err := explorerGet(explorer + "/block-height/" + strconv.FormatUint(height, 10))
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	headerHex, err := explorerGet(explorer + "/block/" + hash + "/header")
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	header, err := hex.DecodeString(headerHex)
+	if err != nil || len(header) != 80 {
+		return nil, time.Time{}, errors.New("explorer returned an invalid block header")
+	}
+
+	// The block hash is the double SHA-256 of the header, displayed byte-reversed
+	first := sha256.Sum256(header)
+	second := sha256.Sum256(first[:])
+	for i, j := 0, len(second)-1; i < j; i, j = i+1, j-1 {
+		second[i], second[j] = second[j], second[i]
+	}
+	if hex.EncodeToString(second[:]) != strings.ToLower(hash) {
+		return nil, time.Time{}, errors.New("explorer returned a block header that does not match the block hash")
+	}
+
+	blockTime := time.Unix(int64(binary.LittleEndian.Uint32(header[68:72])), 0).UTC()
+	return header[36:68], blockTime, nil
+}
+
+// explorerGet fetches a short text response from a block explorer.
+func explorerGet(url string) (string, error) {
+	resp, err := attestHTTPClient.Get(url)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("explorer returned %s", resp.Status)
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(body)), nil
+}
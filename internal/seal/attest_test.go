@@ -0,0 +1,220 @@
+package seal
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"seal/internal/testutil"
+	"seal/internal/timeauth"
+)
+
+// fakeCalendar is an OpenTimestamps calendar and Esplora explorer in one server.
+// Submitted digests are committed to block 100 once confirmed is set.
+type fakeCalendar struct {
+	server      *httptest.Server
+	mu          sync.Mutex
+	confirmed   bool
+	failing     bool
+	wrongMerkle bool
+	commitment  []byte
+	blockTime   time.Time
+}
+
+func newFakeCalendar(t *testing.T) *fakeCalendar {
+	c := &fakeCalendar{blockTime: time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)}
+	c.server = httptest.NewTLSServer(http.HandlerFunc(c.serve))
+	t.Cleanup(c.server.Close)
+
+	original := attestHTTPClient
+	attestHTTPClient = c.server.Client()
+	t.Cleanup(func() { attestHTTPClient = original })
+
+	if err := SaveConfig(Config{Attest: AttestConfig{Calendars: []string{c.server.URL}, Explorer: c.server.URL + "/api"}}); err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+func (c *fakeCalendar) serve(w http.ResponseWriter, r *http.Request) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	switch {
+	case c.failing:
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+
+	case r.Method == http.MethodPost && r.URL.Path == "/digest":
+		digest, _ := io.ReadAll(r.Body)
+		// prepend -> sha256 -> pending, as a calendar aggregates digests
+		prepend := otsOp{tag: otsOpPrepend, arg: []byte("calendar")}
+		msg, _ := prepend.apply(digest)
+		hash := otsOp{tag: otsOpSHA256}
+		c.commitment, _ = hash.apply(msg)
+		hash.next = &otsTimestamp{msg: c.commitment, attestations: []otsAttestation{newOTSPending(c.server.URL)}}
+		prepend.next = &otsTimestamp{msg: msg, ops: []otsOp{hash}}
+		var buf bytes.Buffer
+		(&otsTimestamp{msg: digest, ops: []otsOp{prepend}}).serialize(&buf)
+		w.Write(buf.Bytes())
+
+	case r.URL.Path == "/timestamp/"+hex.EncodeToString(c.commitment):
+		if !c.confirmed {
+			http.Error(w, "Pending confirmation in Bitcoin blockchain", http.StatusNotFound)
+			return
+		}
+		var buf bytes.Buffer
+		c.upgrade().serialize(&buf)
+		w.Write(buf.Bytes())
+
+	case r.URL.Path == "/api/block-height/100":
+		w.Write([]byte(c.blockHash()))
+
+	case r.URL.Path == "/api/block/"+c.blockHash()+"/header":
+		w.Write([]byte(hex.EncodeToString(c.header())))
+
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// upgrade returns the completed timestamp of the commitment: append -> sha256 -> block 100.
+func (c *fakeCalendar) upgrade() *otsTimestamp {
+	appended := otsOp{tag: otsOpAppend, arg: []byte("block")}
+	msg, _ := appended.apply(c.commitment)
+	hash := otsOp{tag: otsOpSHA256}
+	root, _ := hash.apply(msg)
+	var height bytes.Buffer
+	writeOTSVaruint(&height, 100)
+	hash.next = &otsTimestamp{msg: root, attestations: []otsAttestation{{tag: otsBitcoinTag, payload: height.Bytes()}}}
+	appended.next = &otsTimestamp{msg: msg, ops: []otsOp{hash}}
+	return &otsTimestamp{msg: c.commitment, ops: []otsOp{appended}}
+}
+
+// header returns block 100's header, whose merkle root is the upgraded proof's.
+func (c *fakeCalendar) header() []byte {
+	header := make([]byte, 80)
+	root := sha256.Sum256(append(append([]byte{}, c.commitment...), "block"...))
+	if c.wrongMerkle {
+		root[0] ^= 0xff
+	}
+	copy(header[36:68], root[:])
+	binary.LittleEndian.PutUint32(header[68:72], uint32(c.blockTime.Unix()))
+	return header
+}
+
+func (c *fakeCalendar) blockHash() string {
+	first := sha256.Sum256(c.header())
+	second := sha256.Sum256(first[:])
+	for i, j := 0, len(second)-1; i < j; i, j = i+1, j-1 {
+		second[i], second[j] = second[j], second[i]
+	}
+	return hex.EncodeToString(second[:])
+}
+
+func (c *fakeCalendar) set(fn func()) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	fn()
+}
+
+func TestAttest_SubmitAndVerify(t *testing.T) {
+	_, cleanup := testutil.SetupTestEnv(t)
+	defer cleanup()
+	calendar := newFakeCalendar(t)
+
+	id, err := CreateSealedItem(time.Now().UTC().Add(time.Hour), InputSourceStdin, "", []byte("attested data"), &timeauth.FakeAuthority{DefaultRound: 300})
+	if err != nil {
+		t.Fatalf("CreateSealedItem failed: %v", err)
+	}
+
+	warnings, err := AttestItem(id)
+	if err != nil || len(warnings) != 0 {
+		t.Fatalf("AttestItem failed: %v %v", err, warnings)
+	}
+	item, _, _ := LoadItem(id)
+	if item.Attestation == nil || item.Attestation.Kind != AttestationOpenTimestamps || !hasHistoryEvent(item, HistoryAttested) {
+		t.Fatalf("attestation not recorded: %+v", item.Attestation)
+	}
+	if _, err := AttestItem(id); err == nil || !strings.Contains(err.Error(), "already attested") {
+		t.Errorf("expected a second attestation to be refused, got %v", err)
+	}
+
+	// Until the calendar commits to Bitcoin the proof is pending
+	result, err := VerifyAttestation(id)
+	if err != nil {
+		t.Fatalf("VerifyAttestation failed: %v", err)
+	}
+	if result.Confirmed || len(result.Pending) != 1 || result.Pending[0] != calendar.server.URL || result.CiphertextSHA256 != item.PayloadSHA256 {
+		t.Errorf("expected a pending result, got %+v", result)
+	}
+
+	calendar.set(func() { calendar.confirmed = true })
+	result, err = VerifyAttestation(id)
+	if err != nil {
+		t.Fatalf("VerifyAttestation failed: %v", err)
+	}
+	if !result.Confirmed || result.BlockHeight != 100 || !result.BlockTime.Equal(calendar.blockTime) || len(result.Warnings) != 0 {
+		t.Errorf("expected confirmation in block 100, got %+v", result)
+	}
+	if out := FormatAttestationResult(result); !strings.Contains(out, "existed before 2026-10-16T12:00:00Z") {
+		t.Errorf("unexpected output:\n%s", out)
+	}
+
+	// The completed proof is stored, so it verifies without the calendar
+	data, err := ExportAttestation(id)
+	if err != nil {
+		t.Fatalf("ExportAttestation failed: %v", err)
+	}
+	proof, err := parseOTSProof(data)
+	if err != nil {
+		t.Fatalf("stored proof does not parse: %v", err)
+	}
+	heights := 0
+	proof.timestamp.walk(func(node *otsTimestamp) {
+		for _, attestation := range node.attestations {
+			if _, ok := attestation.bitcoinHeight(); ok {
+				heights++
+			}
+		}
+	})
+	if heights != 1 {
+		t.Errorf("expected the stored proof to be upgraded, found %d bitcoin attestations", heights)
+	}
+
+	// A block that does not commit to the proof is an error, not a pending proof
+	calendar.set(func() { calendar.wrongMerkle = true })
+	if _, err := VerifyAttestation(id); err == nil || !strings.Contains(err.Error(), "does not commit") {
+		t.Errorf("expected a merkle root mismatch, got %v", err)
+	}
+}
+
+func TestAttest_CalendarsUnavailable(t *testing.T) {
+	_, cleanup := testutil.SetupTestEnv(t)
+	defer cleanup()
+	calendar := newFakeCalendar(t)
+	calendar.set(func() { calendar.failing = true })
+
+	id, err := CreateSealedItem(time.Now().UTC().Add(time.Hour), InputSourceStdin, "", []byte("attested data"), &timeauth.FakeAuthority{DefaultRound: 300})
+	if err != nil {
+		t.Fatalf("CreateSealedItem failed: %v", err)
+	}
+
+	warnings, err := AttestItem(id)
+	if err == nil || len(warnings) != 1 {
+		t.Fatalf("expected the attestation to fail with a warning per calendar, got %v %v", err, warnings)
+	}
+	if item, _, _ := LoadItem(id); item.Attestation != nil {
+		t.Errorf("expected no attestation to be recorded, got %+v", item.Attestation)
+	}
+	if _, err := VerifyAttestation(id); err == nil || !strings.Contains(err.Error(), "has no attestation") {
+		t.Errorf("expected a missing attestation to be reported, got %v", err)
+	}
+}
@@ -0,0 +1,50 @@
+package seal
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// Marker files written to the base directory when backup exclusion is on.
+const (
+	cacheDirTagName   = "CACHEDIR.TAG"          // honored by borg, restic, tar --exclude-caches
+	spotlightMarker   = ".metadata_never_index" // honored by macOS Spotlight
+	cacheDirTagHeader = "Signature: 8a477f597d28d172789f06886806bc55\n"
+)
+
+const cacheDirTagContent = cacheDirTagHeader +
+	"# This directory holds sealed and unsealed seal items.\n" +
+	"# It is marked for exclusion by `seal config set backup-exclusion on`.\n" +
+	"# For information about cache directory tags see https://bford.info/cachedir/\n"
+
+// applyBackupExclusion adds or removes backup and indexing exclusion markers on the store.
+// Markers are hints: backup tools and sync clients that do not honor them still copy the store.
+// Returns warnings for markers that could not be applied.
+func applyBackupExclusion(baseDir string, on bool) []string {
+	var warnings []string
+
+	markers := map[string]string{
+		cacheDirTagName: cacheDirTagContent,
+		spotlightMarker: "",
+	}
+
+	for name, content := range markers {
+		path := filepath.Join(baseDir, name)
+		var err error
+		if on {
+			err = writeFileNoFollow(path, []byte(content), 0600)
+		} else if err = os.Remove(path); os.IsNotExist(err) {
+			err = nil
+		}
+		if err != nil {
+			warnings = append(warnings, fmt.Sprintf("warning: could not update %s: %v", name, err))
+		}
+	}
+
+	if err := setTimeMachineExclusion(baseDir, on); err != nil {
+		warnings = append(warnings, fmt.Sprintf("warning: could not update Time Machine exclusion: %v", err))
+	}
+
+	return warnings
+}
@@ -0,0 +1,31 @@
+package seal
+
+import (
+	"errors"
+
+	"golang.org/x/sys/unix"
+)
+
+// timeMachineExcludeAttr is the extended attribute set by `tmutil addexclusion`.
+const timeMachineExcludeAttr = "com.apple.metadata:com_apple_backup_excludeItem"
+
+// timeMachineExcludeValue is the binary plist string "com.apple.backupd".
+var timeMachineExcludeValue = []byte("bplist00_\x10\x11com.apple.backupd\x08" +
+	"\x00\x00\x00\x00\x00\x00\x01\x01" +
+	"\x00\x00\x00\x00\x00\x00\x00\x01" +
+	"\x00\x00\x00\x00\x00\x00\x00\x00" +
+	"\x00\x00\x00\x00\x00\x00\x00\x1c")
+
+// setTimeMachineExclusion marks or unmarks a directory as excluded from Time Machine.
+// The exclusion follows the directory if it is moved.
+func setTimeMachineExclusion(path string, on bool) error {
+	if on {
+		return unix.Setxattr(path, timeMachineExcludeAttr, timeMachineExcludeValue, 0)
+	}
+
+	err := unix.Removexattr(path, timeMachineExcludeAttr)
+	if errors.Is(err, unix.ENOATTR) {
+		return nil
+	}
+	return err
+}
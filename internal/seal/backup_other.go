@@ -0,0 +1,8 @@
+//go:build !darwin
+
+package seal
+
+// setTimeMachineExclusion is a no-op outside macOS.
+func setTimeMachineExclusion(path string, on bool) error {
+	return nil
+}
@@ -0,0 +1,82 @@
+package seal
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"seal/internal/testutil"
+)
+
+func TestSetConfigValue_BackupExclusion(t *testing.T) {
+	_, cleanup := testutil.SetupTestEnv(t)
+	defer cleanup()
+
+	baseDir, err := GetSealBaseDir()
+	if err != nil {
+		t.Fatalf("GetSealBaseDir failed: %v", err)
+	}
+	tagPath := filepath.Join(baseDir, cacheDirTagName)
+
+	if _, err := SetConfigValue("backup-exclusion", "on"); err != nil {
+		t.Fatalf("SetConfigValue on failed: %v", err)
+	}
+
+	cfg, err := LoadConfig()
+	if err != nil || !cfg.BackupExclusion {
+		t.Fatalf("backup_exclusion not persisted: %+v, %v", cfg, err)
+	}
+
+	tag, err := os.ReadFile(tagPath)
+	if err != nil {
+		t.Fatalf("CACHEDIR.TAG not written: %v", err)
+	}
+	if !strings.HasPrefix(string(tag), cacheDirTagHeader) {
+		t.Errorf("CACHEDIR.TAG must start with the standard signature, got: %q", tag)
+	}
+
+	if _, err := SetConfigValue("backup-exclusion", "off"); err != nil {
+		t.Fatalf("SetConfigValue off failed: %v", err)
+	}
+
+	cfg, _ = LoadConfig()
+	if cfg.BackupExclusion {
+		t.Error("backup_exclusion should be off")
+	}
+	for _, name := range []string{cacheDirTagName, spotlightMarker} {
+		if _, err := os.Stat(filepath.Join(baseDir, name)); !os.IsNotExist(err) {
+			t.Errorf("%s should be removed", name)
+		}
+	}
+}
+
+func TestSetConfigValue_PreservesOtherSettings(t *testing.T) {
+	_, cleanup := testutil.SetupTestEnv(t)
+	defer cleanup()
+
+	if err := SaveConfig(Config{SMTP: SMTPConfig{Host: "smtp.example.com"}}); err != nil {
+		t.Fatalf("SaveConfig failed: %v", err)
+	}
+
+	if _, err := SetConfigValue("backup-exclusion", "on"); err != nil {
+		t.Fatalf("SetConfigValue failed: %v", err)
+	}
+
+	cfg, _ := LoadConfig()
+	if cfg.SMTP.Host != "smtp.example.com" {
+		t.Errorf("existing settings should be preserved, got %+v", cfg)
+	}
+}
+
+func TestSetConfigValue_RejectsInvalidInput(t *testing.T) {
+	_, cleanup := testutil.SetupTestEnv(t)
+	defer cleanup()
+
+	if _, err := SetConfigValue("backup-exclusion", "yes"); err == nil {
+		t.Error("expected error for invalid value")
+	}
+	if _, err := SetConfigValue("no-such-key", "on"); err == nil {
+		t.Error("expected error for unknown key")
+	}
+}
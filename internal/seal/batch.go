@@ -0,0 +1,124 @@
+package seal
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// BatchRecord is one item to seal in a batch, see LockBatch.
+// Exactly one of Data and Path is set.
+type BatchRecord struct {
+	Data  *string  `json:"data,omitempty"`  // content to seal
+	Path  string   `json:"path,omitempty"`  // file to seal instead of data
+	Until string   `json:"until,omitempty"` // unlock time, as lock --until
+	For   string   `json:"for,omitempty"`   // unlock delay, as lock --for
+	Tags  []string `json:"tags,omitempty"`  // key=value tags, added to the batch's own
+	Note  string   `json:"note,omitempty"`  // replaces the batch's note
+}
+
+// BatchResult is the outcome of one batch record: the sealed item, or why it was not sealed.
+type BatchResult struct {
+	Record   int      `json:"record"` // 1-based position in the input
+	ID       string   `json:"id,omitempty"`
+	Error    string   `json:"error,omitempty"`
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+// LockBatch seals every record read from r, a stream of JSON objects (NDJSON) or
+// a JSON array of them, in one process. base holds the options shared by all records;
+// a record's until or for replaces the base unlock time. result is called once per
+// record, in order. A record that fails does not stop the batch.
+// Returns an error if the input stops being valid JSON; the records before it are sealed.
+func LockBatch(r io.Reader, base LockRequest, result func(BatchResult)) error {
+	reader := bufio.NewReader(r)
+	decoder := json.NewDecoder(reader)
+
+	// A JSON array is read element by element, like a stream
+	array, err := startsWithArray(reader)
+	if err != nil {
+		return err
+	}
+	if array {
+		if _, err := decoder.Token(); err != nil {
+			return fmt.Errorf("invalid batch input: %w", err)
+		}
+	}
+
+	for n := 1; ; n++ {
+		if array && !decoder.More() {
+			if _, err := decoder.Token(); err != nil {
+				return fmt.Errorf("invalid batch input: %w", err)
+			}
+			return nil
+		}
+
+		var record BatchRecord
+		if err := decoder.Decode(&record); err == io.EOF && !array {
+			return nil
+		} else if err != nil {
+			return fmt.Errorf("invalid batch input at record %d: %w", n, err)
+		}
+
+		res := BatchResult{Record: n}
+		lockResult, err := lockRecord(record, base)
+		if err != nil {
+			res.Error = err.Error()
+		} else {
+			res.ID = lockResult.ID
+			res.Warnings = lockResult.Warnings
+		}
+		result(res)
+	}
+}
+
+// lockRecord seals one batch record with the batch's options.
+func lockRecord(record BatchRecord, base LockRequest) (LockResult, error) {
+	req := base
+	req.Stdin = StdinNever
+	switch {
+	case record.Data != nil && record.Path != "":
+		return LockResult{}, errors.New("record has both data and path")
+	case record.Data != nil:
+		req.Data = []byte(*record.Data)
+	case record.Path != "":
+		req.InputPath = record.Path
+	default:
+		return LockResult{}, errors.New("record has neither data nor path")
+	}
+
+	if record.Until != "" || record.For != "" {
+		req.UnlockTime = record.Until
+		req.For = record.For
+	}
+
+	if record.Note != "" {
+		req.Note = record.Note
+	}
+
+	if len(record.Tags) > 0 {
+		req.Tags = append(append([]string{}, base.Tags...), record.Tags...)
+	}
+
+	return Lock(req)
+}
+
+// startsWithArray reports whether the first non-space byte of r opens a JSON array.
+func startsWithArray(r *bufio.Reader) (bool, error) {
+	for {
+		b, err := r.ReadByte()
+		if err == io.EOF {
+			return false, nil
+		}
+		if err != nil {
+			return false, fmt.Errorf("cannot read batch input: %w", err)
+		}
+		switch b {
+		case ' ', '\t', '\r', '\n':
+			continue
+		}
+		return b == '[', r.UnreadByte()
+	}
+}
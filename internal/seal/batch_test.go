@@ -0,0 +1,113 @@
+package seal
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"seal/internal/testutil"
+)
+
+func TestLockBatch(t *testing.T) {
+	tmpDir, cleanup := testutil.SetupTestEnv(t)
+	defer cleanup()
+	registerFakeAuthority()
+
+	input := filepath.Join(tmpDir, "input.txt")
+	if err := os.WriteFile(input, []byte("from a file"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	later := time.Now().UTC().Add(48 * time.Hour).Truncate(time.Second)
+
+	records := `{"data": "first", "tags": ["n=1"]}
+{"path": "` + input + `", "until": "` + later.Format(time.RFC3339) + `"}
+{"data": "x", "path": "` + input + `"}
+{}
+{"data": "bad time", "until": "tomorrow"}
+`
+	base := LockRequest{For: "1h", Authority: "registered-fake", Tags: []string{"batch=yes"}}
+
+	var results []BatchResult
+	if err := LockBatch(strings.NewReader(records), base, func(r BatchResult) { results = append(results, r) }); err != nil {
+		t.Fatalf("LockBatch failed: %v", err)
+	}
+	if len(results) != 5 {
+		t.Fatalf("expected 5 results, got %d: %+v", len(results), results)
+	}
+	for i, r := range results {
+		if r.Record != i+1 {
+			t.Errorf("result %d has record number %d", i, r.Record)
+		}
+	}
+
+	// A data record takes the batch's unlock time and adds its tags to the batch's
+	first, _, err := LoadItem(results[0].ID)
+	if err != nil {
+		t.Fatalf("record 1 not sealed: %+v, %v", results[0], err)
+	}
+	if first.Tags["batch"] != "yes" || first.Tags["n"] != "1" {
+		t.Errorf("unexpected tags %v", first.Tags)
+	}
+	if until := time.Until(first.UnlockTime); until > time.Hour || until < 50*time.Minute {
+		t.Errorf("expected the batch's unlock time, got %s", first.UnlockTime)
+	}
+
+	// A path record with its own unlock time
+	second, _, err := LoadItem(results[1].ID)
+	if err != nil {
+		t.Fatalf("record 2 not sealed: %+v, %v", results[1], err)
+	}
+	if !second.UnlockTime.Equal(later) || second.InputType != InputSourceFile.String() {
+		t.Errorf("unexpected item %+v", second)
+	}
+	if _, err := os.Stat(input); err != nil {
+		t.Errorf("input file should be kept: %v", err)
+	}
+
+	// Failed records do not stop the batch
+	for i, want := range map[int]string{2: "both data and path", 3: "neither data nor path", 4: "invalid time format"} {
+		if results[i].ID != "" || !strings.Contains(results[i].Error, want) {
+			t.Errorf("record %d: expected error containing %q, got %+v", i+1, want, results[i])
+		}
+	}
+}
+
+func TestLockBatch_Array(t *testing.T) {
+	_, cleanup := testutil.SetupTestEnv(t)
+	defer cleanup()
+	registerFakeAuthority()
+
+	base := LockRequest{For: "1h", Authority: "registered-fake"}
+	var ids []string
+	err := LockBatch(strings.NewReader(` [{"data": "a"}, {"data": "b", "for": "2d"}] `), base, func(r BatchResult) {
+		if r.Error != "" {
+			t.Errorf("record %d failed: %s", r.Record, r.Error)
+		}
+		ids = append(ids, r.ID)
+	})
+	if err != nil {
+		t.Fatalf("LockBatch failed: %v", err)
+	}
+	if len(ids) != 2 {
+		t.Fatalf("expected 2 items, got %v", ids)
+	}
+}
+
+func TestLockBatch_InvalidJSON(t *testing.T) {
+	_, cleanup := testutil.SetupTestEnv(t)
+	defer cleanup()
+	registerFakeAuthority()
+
+	// Records before the invalid one are sealed
+	base := LockRequest{For: "1h", Authority: "registered-fake"}
+	var results []BatchResult
+	err := LockBatch(strings.NewReader("{\"data\": \"a\"}\n{\"data\": \n"), base, func(r BatchResult) { results = append(results, r) })
+	if err == nil || !strings.Contains(err.Error(), "record 2") {
+		t.Errorf("expected an error at record 2, got %v", err)
+	}
+	if len(results) != 1 || results[0].ID == "" {
+		t.Errorf("expected the first record to be sealed, got %+v", results)
+	}
+}
@@ -0,0 +1,257 @@
+package seal
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"seal/internal/clock"
+	"seal/internal/timeauth"
+)
+
+// Benchmark defaults, chosen so a run finishes in seconds on a laptop.
+const (
+	DefaultBenchItems   = 20
+	DefaultBenchSize    = 64 * 1024
+	DefaultBenchWorkers = 4
+	benchRTTSamples     = 5
+)
+
+// BenchOptions configures a benchmark run.
+type BenchOptions struct {
+	Items   int // number of items to lock and materialize
+	Size    int // plaintext bytes per item
+	Workers int // concurrent lock and materialize operations
+}
+
+// BenchReport contains benchmark measurements.
+type BenchReport struct {
+	Options         BenchOptions
+	Authority       string
+	RTTMin          time.Duration // authority latest-round request, fastest
+	RTTMedian       time.Duration
+	EncryptMBPerSec float64 // AES-256-GCM payload encryption alone, single core
+	Lock            BenchPhase
+	Materialize     BenchPhase
+}
+
+// BenchPhase is the throughput of one phase of a benchmark run.
+type BenchPhase struct {
+	Items    int
+	Duration time.Duration
+}
+
+// ItemsPerSec returns the phase throughput in items per second.
+func (p BenchPhase) ItemsPerSec() float64 {
+	return float64(p.Items) / p.Duration.Seconds()
+}
+
+// MBPerSec returns the phase throughput in megabytes of plaintext per second.
+func (p BenchPhase) MBPerSec(size int) float64 {
+	return float64(p.Items) * float64(size) / 1e6 / p.Duration.Seconds()
+}
+
+// ValidateBenchOptions checks benchmark options.
+func ValidateBenchOptions(opts BenchOptions) error {
+	if opts.Items < 1 {
+		return errors.New("items must be at least 1")
+	}
+	if opts.Size < 1 || opts.Size > MaxInputSize {
+		return fmt.Errorf("size must be between 1 and %d bytes", MaxInputSize)
+	}
+	if opts.Workers < 1 {
+		return errors.New("workers must be at least 1")
+	}
+	return nil
+}
+
+// RunBench measures lock and materialize throughput with the given authority.
+// Items are created in a scratch store next to the real one (same volume) and
+// removed afterwards; the user's store is never read or written. Items are locked
+// to a round that has already been published, so they materialize immediately
+// and the time-lock round trip to the authority is part of the measurement.
+func RunBench(opts BenchOptions, authority timeauth.Authority) (BenchReport, error) {
+	if err := ValidateBenchOptions(opts); err != nil {
+		return BenchReport{}, err
+	}
+
+	report := BenchReport{Options: opts, Authority: authority.Name()}
+
+	rtt, err := measureAuthorityRTT(authority)
+	if err != nil {
+		return BenchReport{}, fmt.Errorf("time authority unreachable: %w", err)
+	}
+	report.RTTMin, report.RTTMedian = rtt[0], rtt[len(rtt)/2]
+
+	plaintext := make([]byte, opts.Size)
+	if _, err := rand.Read(plaintext); err != nil {
+		return BenchReport{}, err
+	}
+
+	report.EncryptMBPerSec, err = measureEncrypt(plaintext)
+	if err != nil {
+		return BenchReport{}, err
+	}
+
+	scratch, err := benchScratchDir()
+	if err != nil {
+		return BenchReport{}, err
+	}
+	defer os.RemoveAll(scratch)
+
+	baseDirOverride = scratch
+	defer func() { baseDirOverride = "" }()
+
+	// A minute back is a round that has certainly been published
+	unlockTime := clock.UTC().Add(-time.Minute)
+
+	ids := make([]string, opts.Items)
+	report.Lock, err = benchPhase(opts, func(i int) error {
+		id, err := CreateSealedItem(unlockTime, InputSourceStdin, "", plaintext, authority)
+		ids[i] = id
+		return err
+	})
+	if err != nil {
+		return BenchReport{}, fmt.Errorf("lock failed: %w", err)
+	}
+
+	report.Materialize, err = benchPhase(opts, func(i int) error {
+		item, itemDir, err := LoadItem(ids[i])
+		if err != nil {
+			return err
+		}
+		item, err = TryMaterialize(item, itemDir, authority)
+		if err != nil {
+			return err
+		}
+		if item.State != StateUnlocked {
+			return fmt.Errorf("item did not unlock: %s", item.LastUnlockError)
+		}
+		return nil
+	})
+	if err != nil {
+		return BenchReport{}, fmt.Errorf("materialize failed: %w", err)
+	}
+
+	return report, nil
+}
+
+// measureAuthorityRTT times latest-round requests and returns them sorted.
+func measureAuthorityRTT(authority timeauth.Authority) ([]time.Duration, error) {
+	samples := make([]time.Duration, 0, benchRTTSamples)
+	for range benchRTTSamples {
+		start := clock.Now()
+		if _, err := authority.LatestRound(context.Background()); err != nil {
+			return nil, err
+		}
+		samples = append(samples, start.Elapsed())
+	}
+	sort.Slice(samples, func(i, j int) bool { return samples[i] < samples[j] })
+	return samples, nil
+}
+
+// measureEncrypt returns single-core payload encryption throughput in MB/s.
+// Runs for at least 100ms so small payloads are measured accurately.
+func measureEncrypt(plaintext []byte) (float64, error) {
+	aad := payloadAAD("00000000-0000-0000-0000-000000000000", 1, payloadAlgorithm)
+
+	var total int
+	start := clock.Now()
+	for total == 0 || start.Elapsed() < 100*time.Millisecond {
+		if _, _, _, err := EncryptPayloadWithAAD(plaintext, aad); err != nil {
+			return 0, err
+		}
+		total += len(plaintext)
+	}
+	return float64(total) / 1e6 / start.Elapsed().Seconds(), nil
+}
+
+// benchScratchDir creates a scratch store next to the real one, on the same volume.
+func benchScratchDir() (string, error) {
+	parent := os.TempDir()
+	if baseDir, err := GetSealBaseDir(); err == nil {
+		if info, err := os.Stat(filepath.Dir(baseDir)); err == nil && info.IsDir() {
+			parent = filepath.Dir(baseDir)
+		}
+	}
+
+	dir, err := os.MkdirTemp(parent, "seal-bench-")
+	if err != nil {
+		return "", fmt.Errorf("cannot create scratch store: %w", err)
+	}
+	return dir, nil
+}
+
+// benchPhase runs fn for every item on opts.Workers goroutines and times the whole phase.
+// The first error stops the phase.
+func benchPhase(opts BenchOptions, fn func(i int) error) (BenchPhase, error) {
+	next := make(chan int)
+	errs := make(chan error, opts.Workers)
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+
+	start := clock.Now()
+	for range opts.Workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range next {
+				if err := fn(i); err != nil {
+					errs <- err
+					return
+				}
+			}
+		}()
+	}
+
+	go func() {
+		defer close(next)
+		for i := range opts.Items {
+			select {
+			case next <- i:
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	var firstErr error
+	go func() {
+		wg.Wait()
+		close(errs)
+	}()
+	for err := range errs {
+		if firstErr == nil {
+			firstErr = err
+			close(done)
+		}
+	}
+
+	if firstErr != nil {
+		return BenchPhase{}, firstErr
+	}
+	return BenchPhase{Items: opts.Items, Duration: start.Elapsed()}, nil
+}
+
+// FormatBenchReport formats a benchmark report for display.
+func FormatBenchReport(r BenchReport) string {
+	result := fmt.Sprintf("bench: %d items of %d bytes, %d workers, authority %s\n",
+		r.Options.Items, r.Options.Size, r.Options.Workers, r.Authority)
+	result += fmt.Sprintf("authority rtt: min %s, median %s (%d requests)\n",
+		r.RTTMin.Round(time.Millisecond), r.RTTMedian.Round(time.Millisecond), benchRTTSamples)
+	result += fmt.Sprintf("encrypt: %.1f MB/s (payload only, one core)\n", r.EncryptMBPerSec)
+	result += formatBenchPhase("lock", r.Lock, r.Options.Size)
+	result += formatBenchPhase("materialize", r.Materialize, r.Options.Size)
+	return result
+}
+
+func formatBenchPhase(name string, p BenchPhase, size int) string {
+	return fmt.Sprintf("%s: %d items in %s (%.1f items/s, %.1f MB/s)\n",
+		name, p.Items, p.Duration.Round(time.Millisecond), p.ItemsPerSec(), p.MBPerSec(size))
+}
@@ -0,0 +1,70 @@
+package seal
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"seal/internal/testutil"
+	"seal/internal/timeauth"
+)
+
+func TestRunBench_UsesScratchStore(t *testing.T) {
+	_, cleanup := testutil.SetupTestEnv(t)
+	defer cleanup()
+
+	id, err := CreateSealedItem(time.Now().UTC().Add(time.Hour), InputSourceStdin, "", []byte("mine"), &timeauth.FakeAuthority{DefaultRound: 100})
+	if err != nil {
+		t.Fatal(err)
+	}
+	baseDir, _ := GetSealBaseDir()
+
+	authority := &timeauth.FakeAuthority{DefaultRound: 100, CurrentRound: 200}
+	report, err := RunBench(BenchOptions{Items: 6, Size: 1024, Workers: 3}, authority)
+	if err != nil {
+		t.Fatalf("RunBench failed: %v", err)
+	}
+
+	if report.Lock.Items != 6 || report.Materialize.Items != 6 {
+		t.Errorf("expected 6 items per phase, got %+v %+v", report.Lock, report.Materialize)
+	}
+	if report.EncryptMBPerSec <= 0 || report.Lock.ItemsPerSec() <= 0 {
+		t.Errorf("expected positive throughput, got %+v", report)
+	}
+
+	// The user's store holds only its own item, and no scratch store is left behind
+	entries, _ := os.ReadDir(baseDir)
+	if len(entries) != 1 || entries[0].Name() != id {
+		t.Errorf("bench touched the store: %v", entries)
+	}
+	leftovers, _ := filepath.Glob(filepath.Join(filepath.Dir(baseDir), "seal-bench-*"))
+	if len(leftovers) != 0 {
+		t.Errorf("scratch store not removed: %v", leftovers)
+	}
+	if dir, _ := GetSealBaseDir(); dir != baseDir {
+		t.Errorf("base directory not restored: %s", dir)
+	}
+
+	output := FormatBenchReport(report)
+	for _, want := range []string{"bench: 6 items of 1024 bytes, 3 workers", "authority rtt:", "encrypt:", "lock: 6 items", "materialize: 6 items"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("expected %q in report:\n%s", want, output)
+		}
+	}
+}
+
+func TestRunBench_ReportsFailures(t *testing.T) {
+	_, cleanup := testutil.SetupTestEnv(t)
+	defer cleanup()
+
+	authority := &timeauth.FakeAuthority{DefaultRound: 100, CurrentRound: 200, EncryptError: os.ErrDeadlineExceeded}
+	if _, err := RunBench(BenchOptions{Items: 4, Size: 16, Workers: 2}, authority); err == nil || !strings.Contains(err.Error(), "lock failed") {
+		t.Errorf("expected lock failure, got %v", err)
+	}
+
+	if err := ValidateBenchOptions(BenchOptions{Items: 1, Size: MaxInputSize + 1, Workers: 1}); err == nil {
+		t.Error("expected oversized payload to be rejected")
+	}
+}
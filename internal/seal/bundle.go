@@ -0,0 +1,372 @@
+package seal
+
+import (
+	"archive/tar"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"seal/internal/clock"
+	"seal/internal/timeauth"
+)
+
+// A bundle carries one sealed item between machines in a single file: a tar
+// archive of a manifest, the item's meta.json and its payload.bin, in that order.
+// The time-locked key travels in the metadata (or is the payload of an imported
+// tlock file), so the bundle opens exactly when the original item would.
+const (
+	bundleFormat        = "seal-bundle"
+	bundleFormatVersion = 1
+	bundleManifestName  = "bundle.json"
+	maxBundleManifest   = 64 << 10
+	maxBundleMetadata   = 16 << 20
+
+	// bundleUnlockTimeSlack is how far unlock_time may be from the time its key
+	// reference unlocks at: the target round is published up to one round period
+	// after the requested time, and items locked by older builds up to one before.
+	bundleUnlockTimeSlack = time.Minute
+)
+
+// bundleManifest describes the files of a bundle.
+type bundleManifest struct {
+	Format        string `json:"format"`
+	Version       int    `json:"version"`
+	ID            string `json:"id"`
+	MetaSHA256    string `json:"meta_sha256"`
+	PayloadSHA256 string `json:"payload_sha256"`
+	PayloadSize   int64  `json:"payload_size"`
+}
+
+// ExportBundle writes a sealed item to a new bundle file at outPath.
+// The item must be sealed and pass CheckIntegrity; it stays in the store.
+// An existing file at outPath is never overwritten.
+func ExportBundle(id, outPath string) error {
+	src, err := loadBundleSource(id)
+	if err != nil {
+		return err
+	}
+	defer src.payload.Close()
+
+	out, err := os.OpenFile(outPath, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return fmt.Errorf("cannot create bundle: %w", err)
+	}
+
+	err = writeBundle(out, src.manifest, src.metaJSON, src.payload, src.payloadSize)
+	if err == nil {
+		err = out.Sync()
+	}
+	if closeErr := out.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(outPath)
+		return fmt.Errorf("cannot write bundle: %w", err)
+	}
+	return nil
+}
+
+// bundleSource is a sealed item checked and ready to be written as a bundle.
+// The caller closes payload.
+type bundleSource struct {
+	item        SealedItem
+	manifest    []byte
+	metaJSON    []byte
+	payload     *os.File
+	payloadSize int64
+}
+
+// loadBundleSource loads a sealed item for a bundle, refusing items that are
+// not sealed, can never unlock, or fail CheckIntegrity.
+func loadBundleSource(id string) (bundleSource, error) {
+	item, itemDir, err := LoadItem(id)
+	if err != nil {
+		return bundleSource{}, err
+	}
+	if item.State != StateSealed {
+		return bundleSource{}, fmt.Errorf("item %s is %s; bundles carry sealed items (seal open prints the content)", id, item.State)
+	}
+	if reason := permanentLockReason(item); reason != "" {
+		return bundleSource{}, fmt.Errorf("item %s is permanently locked: %s", id, reason)
+	}
+
+	problems, err := CheckIntegrity(id)
+	if err != nil {
+		return bundleSource{}, err
+	}
+	if len(problems) > 0 {
+		return bundleSource{}, fmt.Errorf("item %s fails its integrity check: %s", id, problems[0])
+	}
+
+	metaJSON, err := os.ReadFile(filepath.Join(itemDir, "meta.json"))
+	if err != nil {
+		return bundleSource{}, fmt.Errorf("failed to read metadata: %w", err)
+	}
+
+	payloadPath := filepath.Join(itemDir, "payload.bin")
+	payloadSHA, err := fileSHA256(payloadPath)
+	if err != nil {
+		return bundleSource{}, fmt.Errorf("failed to read payload: %w", err)
+	}
+	payload, err := os.Open(payloadPath)
+	if err != nil {
+		return bundleSource{}, fmt.Errorf("failed to read payload: %w", err)
+	}
+	info, err := payload.Stat()
+	if err != nil {
+		payload.Close()
+		return bundleSource{}, fmt.Errorf("failed to read payload: %w", err)
+	}
+
+	manifest, err := json.MarshalIndent(bundleManifest{
+		Format:        bundleFormat,
+		Version:       bundleFormatVersion,
+		ID:            item.ID,
+		MetaSHA256:    payloadChecksum(metaJSON),
+		PayloadSHA256: payloadSHA,
+		PayloadSize:   info.Size(),
+	}, "", "  ")
+	if err != nil {
+		payload.Close()
+		return bundleSource{}, fmt.Errorf("cannot marshal bundle manifest: %w", err)
+	}
+
+	return bundleSource{item: item, manifest: manifest, metaJSON: metaJSON, payload: payload, payloadSize: info.Size()}, nil
+}
+
+// writeBundle writes the bundle archive to w.
+func writeBundle(w io.Writer, manifest, metaJSON []byte, payload io.Reader, payloadSize int64) error {
+	tw := tar.NewWriter(w)
+	modTime := clock.UTC()
+
+	for _, file := range []struct {
+		name string
+		data []byte
+	}{{bundleManifestName, manifest}, {"meta.json", metaJSON}} {
+		if err := tw.WriteHeader(&tar.Header{Name: file.name, Mode: 0600, Size: int64(len(file.data)), ModTime: modTime, Typeflag: tar.TypeReg}); err != nil {
+			return err
+		}
+		if _, err := tw.Write(file.data); err != nil {
+			return err
+		}
+	}
+
+	if err := tw.WriteHeader(&tar.Header{Name: "payload.bin", Mode: 0600, Size: payloadSize, ModTime: modTime, Typeflag: tar.TypeReg}); err != nil {
+		return err
+	}
+	if _, err := io.Copy(tw, payload); err != nil {
+		return err
+	}
+
+	return tw.Close()
+}
+
+// ImportBundleResult is an item imported from a bundle.
+type ImportBundleResult struct {
+	ID       string
+	Warnings []string
+}
+
+// ImportBundle validates a bundle and stores its item in a new directory of the store.
+// The item keeps its ID, which its payload is bound to, and its unlock constraints:
+// time authority, key reference and unlock time. The lock-time policy applies as for
+// any new item, judged by the time the key reference unlocks at; a bundle whose
+// unlock_time disagrees with it is refused. Unlock failure state from the other machine
+// is dropped, and so are unseal_to, on_unlock_exec and age-decrypt post-processing,
+// which name paths there. An item already in the store is refused.
+// With DryRun, every check is made but nothing is written to the store.
+func ImportBundle(path string, opts ImportOptions) (ImportBundleResult, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return ImportBundleResult{}, fmt.Errorf("cannot read bundle: %w", err)
+	}
+	defer file.Close()
+
+	baseDir, err := GetSealBaseDir()
+	if err != nil {
+		return ImportBundleResult{}, err
+	}
+
+	// The item is assembled next to the store and moved in once it is complete.
+	// A dry run reads the payload into a temporary directory instead.
+	stagingBase := baseDir
+	if opts.DryRun {
+		stagingBase = os.TempDir()
+	} else if err := os.MkdirAll(baseDir, 0700); err != nil {
+		return ImportBundleResult{}, fmt.Errorf("cannot create seal directory: %w", err)
+	}
+	stagingDir, err := newImportStagingDir(stagingBase)
+	if err != nil {
+		return ImportBundleResult{}, err
+	}
+	defer os.RemoveAll(stagingDir)
+
+	item, warnings, err := readBundle(tar.NewReader(file), stagingDir)
+	if err != nil {
+		return ImportBundleResult{}, fmt.Errorf("invalid bundle %s: %w", path, err)
+	}
+
+	authority := authorityForItem(item)
+	if authority == nil {
+		return ImportBundleResult{}, fmt.Errorf("item %s: time authority %q is not available in this build", item.ID, item.TimeAuthority)
+	}
+
+	// Nothing signs unlock_time, so the policy judges the time the key reference
+	// unlocks at, and a bundle whose unlock_time claims otherwise is refused.
+	unlockAt, err := authority.EarliestUnlockTime(timeauth.KeyReference(item.KeyRef))
+	if err != nil {
+		return ImportBundleResult{}, fmt.Errorf("item %s: cannot derive the unlock time from the key reference: %w", item.ID, err)
+	}
+	if skew := unlockAt.Sub(item.UnlockTime); skew > bundleUnlockTimeSlack || skew < -bundleUnlockTimeSlack {
+		return ImportBundleResult{}, fmt.Errorf("item %s: unlock_time %s does not match its key reference, which unlocks at %s", item.ID, item.UnlockTime.Format(time.RFC3339), unlockAt.Format(time.RFC3339))
+	}
+
+	policy, err := LoadPolicy()
+	if err != nil {
+		return ImportBundleResult{}, err
+	}
+	if err := policy.Check(PolicyRequest{UnlockTime: unlockAt, InputPath: path, Authority: item.TimeAuthority}, clock.UTC()); err != nil {
+		return ImportBundleResult{}, err
+	}
+
+	itemDir := filepath.Join(baseDir, item.ID)
+	if _, err := os.Lstat(itemDir); err == nil {
+		return ImportBundleResult{}, fmt.Errorf("item %s is already in the store", item.ID)
+	}
+	if opts.DryRun {
+		return ImportBundleResult{ID: item.ID, Warnings: warnings}, nil
+	}
+
+	appendHistory(&item, HistoryImported, "bundle "+filepath.Base(path))
+	metaJSON, err := json.MarshalIndent(item, "", "  ")
+	if err != nil {
+		return ImportBundleResult{}, fmt.Errorf("cannot marshal metadata: %w", err)
+	}
+	if err := writeFileNoFollow(filepath.Join(stagingDir, "meta.json"), metaJSON, 0600); err != nil {
+		return ImportBundleResult{}, fmt.Errorf("cannot write metadata: %w", err)
+	}
+
+	syncDir(stagingDir)
+	if err := os.Rename(stagingDir, itemDir); err != nil {
+		return ImportBundleResult{}, fmt.Errorf("cannot move item into the store: %w", err)
+	}
+	syncDir(baseDir)
+
+	if item.Immutable {
+		warnings = append(warnings, protectItemFiles(itemDir)...)
+	}
+
+	return ImportBundleResult{ID: item.ID, Warnings: warnings}, nil
+}
+
+// readBundle reads and checks a bundle's entries, writing the payload into dir.
+// Returns the item metadata as it will be stored, without meta.json written yet.
+func readBundle(tr *tar.Reader, dir string) (SealedItem, []string, error) {
+	manifestJSON, err := readBundleEntry(tr, bundleManifestName, maxBundleManifest)
+	if err != nil {
+		return SealedItem{}, nil, err
+	}
+	var manifest bundleManifest
+	if err := json.Unmarshal(manifestJSON, &manifest); err != nil {
+		return SealedItem{}, nil, fmt.Errorf("invalid manifest: %w", err)
+	}
+	if manifest.Format != bundleFormat {
+		return SealedItem{}, nil, errors.New("not a seal bundle")
+	}
+	if manifest.Version != bundleFormatVersion {
+		return SealedItem{}, nil, fmt.Errorf("unsupported bundle version %d (this build reads version %d)", manifest.Version, bundleFormatVersion)
+	}
+
+	metaJSON, err := readBundleEntry(tr, "meta.json", maxBundleMetadata)
+	if err != nil {
+		return SealedItem{}, nil, err
+	}
+	if payloadChecksum(metaJSON) != manifest.MetaSHA256 {
+		return SealedItem{}, nil, errors.New("metadata does not match the manifest (corrupted)")
+	}
+	var item SealedItem
+	if err := json.Unmarshal(metaJSON, &item); err != nil {
+		return SealedItem{}, nil, fmt.Errorf("invalid metadata: %w", err)
+	}
+	if _, err := uuid.Parse(item.ID); err != nil || item.ID != manifest.ID {
+		return SealedItem{}, nil, fmt.Errorf("invalid item id %q", item.ID)
+	}
+	if item.State != StateSealed {
+		return SealedItem{}, nil, fmt.Errorf("item %s is %s, not sealed", item.ID, item.State)
+	}
+	if reason := permanentLockReason(item); reason != "" {
+		return SealedItem{}, nil, fmt.Errorf("item %s is permanently locked: %s", item.ID, reason)
+	}
+
+	header, err := tr.Next()
+	if err != nil || header.Name != "payload.bin" || header.Typeflag != tar.TypeReg || header.Size != manifest.PayloadSize {
+		return SealedItem{}, nil, errors.New("missing or unexpected payload entry")
+	}
+	hash := sha256.New()
+	err = writeStreamNoFollow(filepath.Join(dir, "payload.bin"), 0600, func(w io.Writer) error {
+		_, err := io.Copy(io.MultiWriter(w, hash), tr)
+		return err
+	})
+	if err != nil {
+		return SealedItem{}, nil, fmt.Errorf("cannot write payload: %w", err)
+	}
+	payloadSHA := hex.EncodeToString(hash.Sum(nil))
+	if payloadSHA != manifest.PayloadSHA256 || (item.PayloadSHA256 != "" && payloadSHA != item.PayloadSHA256) {
+		return SealedItem{}, nil, errors.New("payload does not match its checksum (corrupted)")
+	}
+
+	if _, err := tr.Next(); err != io.EOF {
+		return SealedItem{}, nil, errors.New("unexpected entries after the payload")
+	}
+
+	// State of the other machine that does not apply here
+	var warnings []string
+	if item.UnsealTo != "" {
+		warnings = append(warnings, fmt.Sprintf("warning: unseal_to %s was not carried over; the content unlocks into the store", item.UnsealTo))
+		item.UnsealTo = ""
+	}
+	if item.OnUnlockExec != "" {
+		warnings = append(warnings, fmt.Sprintf("warning: on_unlock_exec %s was not carried over; it names a program on the other machine", item.OnUnlockExec))
+		item.OnUnlockExec = ""
+	}
+	for i, step := range item.PostProcess {
+		if name, _, _ := strings.Cut(step, ":"); name == PostProcessAgeDecrypt {
+			// Later steps work on the decrypted content, so they go too
+			warnings = append(warnings, fmt.Sprintf("warning: post_process steps %s were not carried over; age-decrypt names an identity file on the other machine", strings.Join(item.PostProcess[i:], ",")))
+			item.PostProcess = item.PostProcess[:i]
+			break
+		}
+	}
+	if len(item.PostProcess) == 0 {
+		item.PostProcess = nil
+	}
+	item.UnlockFailures = 0
+	item.LastUnlockError = ""
+	item.NextUnlockAttempt = nil
+
+	return item, warnings, nil
+}
+
+// readBundleEntry reads the next entry, which must be the regular file name of at most limit bytes.
+func readBundleEntry(tr *tar.Reader, name string, limit int64) ([]byte, error) {
+	header, err := tr.Next()
+	if err != nil {
+		return nil, fmt.Errorf("missing %s: %w", name, err)
+	}
+	if header.Name != name || header.Typeflag != tar.TypeReg {
+		return nil, fmt.Errorf("expected %s, found %s", name, header.Name)
+	}
+	if header.Size > limit {
+		return nil, fmt.Errorf("%s is too large", name)
+	}
+	return io.ReadAll(io.LimitReader(tr, limit))
+}
@@ -0,0 +1,211 @@
+package seal
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"seal/internal/testutil"
+	"seal/internal/timeauth"
+)
+
+// createBundleItem creates a sealed item locked with a simulated drand chain, which
+// its key reference records, so bundle import can derive when it unlocks.
+// Test builds resolve every reference to their own simulator, so lock with that.
+func createBundleItem(t *testing.T) SealedItem {
+	t.Helper()
+	var authority timeauth.Authority = timeauth.NewDefaultDrandAuthority()
+	if !timeauth.TestBuild {
+		_, authority = startImportChain(t, 3*time.Second)
+	}
+	id, err := CreateSealedItem(time.Now().UTC().Add(time.Hour), InputSourceStdin, "", []byte("bundled data"), authority)
+	if err != nil {
+		t.Fatalf("CreateSealedItem failed: %v", err)
+	}
+	item, _, _ := LoadItem(id)
+	return item
+}
+
+// exportEditedBundle rewrites the item's metadata with edit and exports it,
+// as someone editing meta.json inside a bundle would; the item leaves the store.
+func exportEditedBundle(t *testing.T, item SealedItem, path string, edit func(*SealedItem)) {
+	t.Helper()
+	_, itemDir, _ := LoadItem(item.ID)
+	edit(&item)
+	metaJSON, _ := json.MarshalIndent(item, "", "  ")
+	if err := os.WriteFile(filepath.Join(itemDir, "meta.json"), metaJSON, 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := ExportBundle(item.ID, path); err != nil {
+		t.Fatalf("ExportBundle failed: %v", err)
+	}
+	if _, err := Delete(item.ID, DeleteOptions{Force: true}); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+}
+
+func TestBundle_RoundTrip(t *testing.T) {
+	tmpDir, cleanup := testutil.SetupTestEnv(t)
+	defer cleanup()
+
+	item := createBundleItem(t)
+	_, itemDir, _ := LoadItem(item.ID)
+	payload, _ := os.ReadFile(filepath.Join(itemDir, "payload.bin"))
+
+	bundlePath := filepath.Join(tmpDir, "item.seal")
+	if err := ExportBundle(item.ID, bundlePath); err != nil {
+		t.Fatalf("ExportBundle failed: %v", err)
+	}
+	if err := ExportBundle(item.ID, bundlePath); err == nil {
+		t.Error("expected an existing bundle file not to be overwritten")
+	}
+
+	if _, err := ImportBundle(bundlePath, ImportOptions{}); err == nil || !strings.Contains(err.Error(), "already in the store") {
+		t.Fatalf("expected the item to be in the store already, got %v", err)
+	}
+
+	// Moved to another store: the same item, with the same unlock constraints
+	if _, err := Delete(item.ID, DeleteOptions{Force: true}); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if result, err := ImportBundle(bundlePath, ImportOptions{DryRun: true}); err != nil || result.ID != item.ID {
+		t.Fatalf("ImportBundle --dry-run: got %s, %v", result.ID, err)
+	}
+	if _, _, err := LoadItem(item.ID); err == nil {
+		t.Fatal("dry run imported the item")
+	}
+	result, err := ImportBundle(bundlePath, ImportOptions{})
+	if err != nil {
+		t.Fatalf("ImportBundle failed: %v", err)
+	}
+	if result.ID != item.ID {
+		t.Errorf("imported as %s, want %s", result.ID, item.ID)
+	}
+
+	imported, itemDir, err := LoadItem(item.ID)
+	if err != nil {
+		t.Fatalf("LoadItem failed: %v", err)
+	}
+	if imported.State != StateSealed || imported.KeyRef != item.KeyRef || imported.DEKTlockB64 != item.DEKTlockB64 || !imported.UnlockTime.Equal(item.UnlockTime) {
+		t.Errorf("unlock constraints changed: %+v", imported)
+	}
+	if last := imported.History[len(imported.History)-1]; last.Event != HistoryImported || last.Detail != "bundle item.seal" {
+		t.Errorf("expected an import history event, got %+v", last)
+	}
+	if got, _ := os.ReadFile(filepath.Join(itemDir, "payload.bin")); !bytes.Equal(got, payload) {
+		t.Error("payload changed in transit")
+	}
+	if problems, err := CheckIntegrity(item.ID); err != nil || len(problems) != 0 {
+		t.Errorf("imported item fails integrity: %v, %v", problems, err)
+	}
+}
+
+func TestBundle_RejectsCorruption(t *testing.T) {
+	tmpDir, cleanup := testutil.SetupTestEnv(t)
+	defer cleanup()
+
+	item := createBundleItem(t)
+	_, itemDir, _ := LoadItem(item.ID)
+	payload, _ := os.ReadFile(filepath.Join(itemDir, "payload.bin"))
+	bundlePath := filepath.Join(tmpDir, "item.seal")
+	if err := ExportBundle(item.ID, bundlePath); err != nil {
+		t.Fatalf("ExportBundle failed: %v", err)
+	}
+	if _, err := Delete(item.ID, DeleteOptions{Force: true}); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+
+	data, _ := os.ReadFile(bundlePath)
+	offset := bytes.Index(data, payload)
+	if offset < 0 {
+		t.Fatal("payload not found in bundle")
+	}
+	data[offset] ^= 0xff
+	corrupted := filepath.Join(tmpDir, "corrupted.seal")
+	if err := os.WriteFile(corrupted, data, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := ImportBundle(corrupted, ImportOptions{}); err == nil || !strings.Contains(err.Error(), "corrupted") {
+		t.Fatalf("expected a corrupted payload to be refused, got %v", err)
+	}
+	baseDir, _ := GetSealBaseDir()
+	entries, _ := os.ReadDir(baseDir)
+	if len(entries) != 0 {
+		t.Errorf("refused import left %d entries in the store", len(entries))
+	}
+
+	if _, err := ImportBundle(filepath.Join(tmpDir, "missing.seal"), ImportOptions{}); err == nil {
+		t.Error("expected an error for a missing bundle")
+	}
+}
+
+func TestExportBundle_RefusesUnlockedItem(t *testing.T) {
+	tmpDir, cleanup := testutil.SetupTestEnv(t)
+	defer cleanup()
+
+	authority := &timeauth.FakeAuthority{DefaultRound: 100, CurrentRound: 200}
+	id, err := CreateSealedItem(time.Now().UTC().Add(time.Hour), InputSourceStdin, "", []byte("data"), authority)
+	if err != nil {
+		t.Fatalf("CreateSealedItem failed: %v", err)
+	}
+	item, itemDir, _ := LoadItem(id)
+	if _, err := TryMaterialize(item, itemDir, authority); err != nil {
+		t.Fatalf("TryMaterialize failed: %v", err)
+	}
+
+	if err := ExportBundle(id, filepath.Join(tmpDir, "item.seal")); err == nil || !strings.Contains(err.Error(), "bundles carry sealed items") {
+		t.Errorf("expected an unlocked item to be refused, got %v", err)
+	}
+}
+
+func TestImportBundle_DerivesUnlockTimeFromKeyReference(t *testing.T) {
+	tmpDir, cleanup := testutil.SetupTestEnv(t)
+	defer cleanup()
+
+	// Pulled inside the policy's horizon, while the key reference stays an hour out
+	item := createBundleItem(t)
+	baseDir, _ := GetSealBaseDir()
+	if err := os.WriteFile(filepath.Join(baseDir, policyFileName), []byte(`{"max_horizon": "30m"}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	bundlePath := filepath.Join(tmpDir, "edited.seal")
+	exportEditedBundle(t, item, bundlePath, func(item *SealedItem) {
+		item.UnlockTime = time.Now().UTC().Add(10 * time.Minute)
+	})
+	if _, err := ImportBundle(bundlePath, ImportOptions{}); err == nil || !strings.Contains(err.Error(), "does not match its key reference") {
+		t.Fatalf("expected an edited unlock_time to be refused, got %v", err)
+	}
+	entries, _ := os.ReadDir(baseDir)
+	if len(entries) != 1 {
+		t.Errorf("refused import left %d entries besides the policy in the store", len(entries)-1)
+	}
+}
+
+func TestImportBundle_DropsAgeDecrypt(t *testing.T) {
+	tmpDir, cleanup := testutil.SetupTestEnv(t)
+	defer cleanup()
+
+	item := createBundleItem(t)
+	bundlePath := filepath.Join(tmpDir, "item.seal")
+	exportEditedBundle(t, item, bundlePath, func(item *SealedItem) {
+		item.PostProcess = []string{"gunzip", "age-decrypt:/home/other/key.txt", "untar"}
+	})
+
+	result, err := ImportBundle(bundlePath, ImportOptions{})
+	if err != nil {
+		t.Fatalf("ImportBundle failed: %v", err)
+	}
+	if len(result.Warnings) != 1 || !strings.Contains(result.Warnings[0], "age-decrypt:/home/other/key.txt,untar were not carried over") {
+		t.Errorf("expected a warning for the dropped steps, got %v", result.Warnings)
+	}
+	imported, _, _ := LoadItem(item.ID)
+	if len(imported.PostProcess) != 1 || imported.PostProcess[0] != "gunzip" {
+		t.Errorf("expected only gunzip to be kept, got %v", imported.PostProcess)
+	}
+}
@@ -0,0 +1,172 @@
+package seal
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// configFileName is the name of the optional configuration file in the base directory.
+const configFileName = "config.json"
+
+// Config contains optional user configuration.
+// All fields are optional; a missing config file is equivalent to an empty Config.
+type Config struct {
+	SMTP            SMTPConfig        `json:"smtp,omitempty"`
+	Notify          []NotifySink      `json:"notify,omitempty"`
+	BackupExclusion bool              `json:"backup_exclusion,omitempty"` // mark the store as excluded from backups and indexing
+	RequireAAD      bool              `json:"require_aad,omitempty"`      // refuse to unlock legacy items whose payload is not bound to metadata
+	Contacts        []Contact         `json:"contacts,omitempty"`         // named recipient public keys, see AddContact
+	MinInputSize    int               `json:"min_input_size,omitempty"`   // lock refuses smaller input without --allow-small (0 = no minimum)
+	Aliases         map[string]string `json:"aliases,omitempty"`          // command aliases, e.g. "st": "status --ndjson"
+	PassCommand     string            `json:"pass_command,omitempty"`     // password store CLI for --from-pass and pass: reveals (default pass)
+	Kubernetes      KubernetesConfig  `json:"kubernetes,omitempty"`       // cluster for k8s: reveals
+	Vault           VaultConfig       `json:"vault,omitempty"`            // server for --vault-wrap
+	Shred           ShredOptions      `json:"shred,omitempty"`            // overwrite policy for every shred, see ShredFile
+	Attest          AttestConfig      `json:"attest,omitempty"`           // timestamp services for --attest
+}
+
+// LoadConfig loads the configuration file from the base directory.
+// Returns an empty Config if no configuration file exists.
+func LoadConfig() (Config, error) {
+	baseDir, err := GetSealBaseDir()
+	if err != nil {
+		return Config{}, err
+	}
+
+	data, err := os.ReadFile(filepath.Join(baseDir, configFileName))
+	if os.IsNotExist(err) {
+		return Config{}, nil
+	}
+	if err != nil {
+		return Config{}, fmt.Errorf("failed to read config: %w", err)
+	}
+
+	var cfg Config
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return Config{}, fmt.Errorf("failed to parse config: %w", err)
+	}
+
+	return cfg, nil
+}
+
+// SaveConfig writes the configuration file to the base directory.
+func SaveConfig(cfg Config) error {
+	baseDir, err := GetSealBaseDir()
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(baseDir, 0700); err != nil {
+		return fmt.Errorf("cannot create seal directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(cfg, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal config: %w", err)
+	}
+
+	if err := writeFileAtomic(filepath.Join(baseDir, configFileName), data, 0600); err != nil {
+		return fmt.Errorf("failed to update config: %w", err)
+	}
+
+	return nil
+}
+
+// SetConfigValue updates a single setting in the configuration file and applies it.
+// Supported keys: backup-exclusion (on|off), require-aad (on|off), min-input-size (bytes, 0 disables),
+// shred-passes (1 to 35), shred-pattern (zero|random|alternating),
+// alias.<name> (a command and its arguments, empty removes the alias).
+// Returns warnings for parts of the setting that could only be applied best-effort.
+func SetConfigValue(key, value string) ([]string, error) {
+	cfg, err := LoadConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	switch key {
+	case "backup-exclusion":
+		on, err := parseOnOff(value)
+		if err != nil {
+			return nil, fmt.Errorf("backup-exclusion: %w", err)
+		}
+		cfg.BackupExclusion = on
+
+		if err := SaveConfig(cfg); err != nil {
+			return nil, err
+		}
+
+		baseDir, err := GetSealBaseDir()
+		if err != nil {
+			return nil, err
+		}
+		return applyBackupExclusion(baseDir, on), nil
+
+	case "require-aad":
+		on, err := parseOnOff(value)
+		if err != nil {
+			return nil, fmt.Errorf("require-aad: %w", err)
+		}
+		cfg.RequireAAD = on
+
+		return nil, SaveConfig(cfg)
+
+	case "min-input-size":
+		size, err := strconv.Atoi(value)
+		if err != nil || size < 0 {
+			return nil, fmt.Errorf("min-input-size: invalid value %q, expected a number of bytes", value)
+		}
+		cfg.MinInputSize = size
+
+		return nil, SaveConfig(cfg)
+
+	case "shred-passes":
+		passes, err := strconv.Atoi(value)
+		if err != nil || passes < 1 {
+			return nil, fmt.Errorf("shred-passes: invalid value %q, expected 1 to %d", value, maxShredPasses)
+		}
+		cfg.Shred.Passes = passes
+		if err := cfg.Shred.Validate(); err != nil {
+			return nil, fmt.Errorf("shred-passes: %w", err)
+		}
+
+		return nil, SaveConfig(cfg)
+
+	case "shred-pattern":
+		cfg.Shred.Pattern = value
+		if err := cfg.Shred.Validate(); err != nil || value == "" {
+			return nil, fmt.Errorf("shred-pattern: invalid value %q, expected zero, random or alternating", value)
+		}
+
+		return nil, SaveConfig(cfg)
+
+	default:
+		if name, ok := strings.CutPrefix(key, "alias."); ok && name != "" {
+			if strings.TrimSpace(value) == "" {
+				delete(cfg.Aliases, name)
+			} else {
+				if cfg.Aliases == nil {
+					cfg.Aliases = make(map[string]string)
+				}
+				cfg.Aliases[name] = value
+			}
+			return nil, SaveConfig(cfg)
+		}
+		return nil, fmt.Errorf("unknown config key: %s", key)
+	}
+}
+
+// parseOnOff parses an on/off setting value.
+func parseOnOff(value string) (bool, error) {
+	switch value {
+	case "on":
+		return true, nil
+	case "off":
+		return false, nil
+	default:
+		return false, fmt.Errorf("invalid value %q, expected on or off", value)
+	}
+}
@@ -0,0 +1,54 @@
+package seal
+
+import (
+	"testing"
+
+	"seal/internal/testutil"
+)
+
+func TestSetConfigValue_MinInputSize(t *testing.T) {
+	_, cleanup := testutil.SetupTestEnv(t)
+	defer cleanup()
+
+	if _, err := SetConfigValue("min-input-size", "16"); err != nil {
+		t.Fatalf("SetConfigValue failed: %v", err)
+	}
+
+	cfg, _ := LoadConfig()
+	if cfg.MinInputSize != 16 {
+		t.Errorf("expected min_input_size 16, got %d", cfg.MinInputSize)
+	}
+
+	for _, value := range []string{"-1", "16kb", ""} {
+		if _, err := SetConfigValue("min-input-size", value); err == nil {
+			t.Errorf("expected error for min-input-size %q", value)
+		}
+	}
+}
+
+func TestSetConfigValue_Alias(t *testing.T) {
+	_, cleanup := testutil.SetupTestEnv(t)
+	defer cleanup()
+
+	if _, err := SetConfigValue("alias.st", "status --ndjson"); err != nil {
+		t.Fatalf("SetConfigValue failed: %v", err)
+	}
+
+	cfg, _ := LoadConfig()
+	if cfg.Aliases["st"] != "status --ndjson" {
+		t.Errorf("expected alias st, got %v", cfg.Aliases)
+	}
+
+	if _, err := SetConfigValue("alias.st", ""); err != nil {
+		t.Fatalf("SetConfigValue failed: %v", err)
+	}
+
+	cfg, _ = LoadConfig()
+	if _, ok := cfg.Aliases["st"]; ok {
+		t.Errorf("expected alias st to be removed, got %v", cfg.Aliases)
+	}
+
+	if _, err := SetConfigValue("alias.", "status"); err == nil {
+		t.Error("expected error for an alias without a name")
+	}
+}
@@ -0,0 +1,184 @@
+package seal
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"filippo.io/age"
+)
+
+// recipientPrefix starts every X25519 age public key.
+const recipientPrefix = "age1"
+
+// Contact is a named age public key stored in config.json.
+type Contact struct {
+	Name      string `json:"name"`
+	Recipient string `json:"recipient"`
+}
+
+// AddContact stores a named recipient public key in the configuration.
+// Adding the same name and key again is a no-op. A name is never silently
+// repointed to a different key, since content sealed to the wrong key is lost.
+func AddContact(name, recipient string) error {
+	if err := validateContactName(name); err != nil {
+		return err
+	}
+	if _, err := age.ParseX25519Recipient(recipient); err != nil {
+		return fmt.Errorf("invalid recipient for contact %s: %w", name, err)
+	}
+
+	cfg, err := LoadConfig()
+	if err != nil {
+		return err
+	}
+
+	if existing, ok := findContact(name, cfg); ok {
+		if existing.Recipient == recipient {
+			return nil
+		}
+		return fmt.Errorf("contact %s already exists with a different key", name)
+	}
+
+	cfg.Contacts = append(cfg.Contacts, Contact{Name: name, Recipient: recipient})
+	return SaveConfig(cfg)
+}
+
+// ListContacts returns all stored contacts, sorted by name.
+func ListContacts() ([]Contact, error) {
+	cfg, err := LoadConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	contacts := append([]Contact{}, cfg.Contacts...)
+	sort.Slice(contacts, func(i, j int) bool {
+		return contacts[i].Name < contacts[j].Name
+	})
+	return contacts, nil
+}
+
+// ResolveRecipient resolves a contact name or an age public key (age1...).
+// A raw key resolves to a Contact with no name.
+func ResolveRecipient(value string, cfg Config) (Contact, error) {
+	if strings.HasPrefix(value, recipientPrefix) {
+		if _, err := age.ParseX25519Recipient(value); err != nil {
+			return Contact{}, fmt.Errorf("invalid recipient: %w", err)
+		}
+		return Contact{Recipient: value}, nil
+	}
+
+	contact, ok := findContact(value, cfg)
+	if !ok {
+		return Contact{}, fmt.Errorf("unknown contact: %s (add it with seal contacts add)", value)
+	}
+	return contact, nil
+}
+
+// FormatContactList formats contacts for display, one per line.
+func FormatContactList(contacts []Contact) string {
+	if len(contacts) == 0 {
+		return "no contacts\n"
+	}
+
+	var result string
+	for _, contact := range contacts {
+		result += fmt.Sprintf("%s  %s\n", contact.Name, contact.Recipient)
+	}
+	return result
+}
+
+func findContact(name string, cfg Config) (Contact, bool) {
+	for _, contact := range cfg.Contacts {
+		if contact.Name == name {
+			return contact, true
+		}
+	}
+	return Contact{}, false
+}
+
+func validateContactName(name string) error {
+	if !namePattern.MatchString(name) || strings.HasPrefix(name, recipientPrefix) {
+		return fmt.Errorf("invalid contact name %q: use lowercase letters, digits, '.', '_' and '-', not starting with %s", name, recipientPrefix)
+	}
+	return nil
+}
+
+// encryptToRecipient encrypts plaintext to an age public key.
+func encryptToRecipient(plaintext []byte, recipient string) ([]byte, error) {
+	r, err := age.ParseX25519Recipient(recipient)
+	if err != nil {
+		return nil, fmt.Errorf("invalid recipient: %w", err)
+	}
+
+	var out bytes.Buffer
+	w, err := age.Encrypt(&out, r)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := w.Write(plaintext); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+
+	return out.Bytes(), nil
+}
+
+// DecryptForIdentity returns the content of an item sealed with a recipient,
+// decrypted with identity: the name of a stored identity (see GenerateIdentity),
+// or the path of an age identity file. content is the unlocked content as Open
+// returns it. The item is then readable only with both the unlock time and the key.
+func DecryptForIdentity(item SealedItem, content io.Reader, identity string) (io.Reader, error) {
+	if item.Recipient == "" {
+		return nil, fmt.Errorf("item %s was not sealed for a recipient", item.ID)
+	}
+
+	identities, err := loadAgeIdentities(identity)
+	if err != nil {
+		return nil, err
+	}
+
+	r, err := age.Decrypt(content, identities...)
+	if err != nil {
+		var noMatch *age.NoIdentityMatchError
+		if errors.As(err, &noMatch) {
+			return nil, fmt.Errorf("item %s is sealed for %s; identity %s does not match", item.ID, item.Recipient, identity)
+		}
+		return nil, fmt.Errorf("item %s: age decryption failed: %w", item.ID, err)
+	}
+	return r, nil
+}
+
+// loadAgeIdentities loads a stored identity by name, or else an age identity file.
+func loadAgeIdentities(identity string) ([]age.Identity, error) {
+	if validateIdentityName(identity) == nil {
+		if data, _, err := readIdentityFile(identity); err == nil {
+			key, err := parseIdentityFile(data)
+			if err != nil {
+				return nil, fmt.Errorf("identity %s: %w", identity, err)
+			}
+			return []age.Identity{key}, nil
+		}
+	}
+
+	file, err := os.Open(identity)
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("identity %s not found (neither a stored identity nor a file)", identity)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("cannot open age identity: %w", err)
+	}
+	defer file.Close()
+
+	identities, err := age.ParseIdentities(file)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse age identity %s: %w", identity, err)
+	}
+	return identities, nil
+}
@@ -0,0 +1,165 @@
+package seal
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"seal/internal/testutil"
+	"seal/internal/timeauth"
+)
+
+func TestAddContact_NeverRepointsName(t *testing.T) {
+	_, cleanup := testutil.SetupTestEnv(t)
+	defer cleanup()
+
+	alice, err := GenerateIdentity("alice-key")
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := GenerateIdentity("other-key")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := AddContact("alice", alice.Recipient); err != nil {
+		t.Fatalf("AddContact failed: %v", err)
+	}
+	if err := AddContact("alice", alice.Recipient); err != nil {
+		t.Errorf("re-adding the same key should be a no-op, got: %v", err)
+	}
+	if err := AddContact("alice", other.Recipient); err == nil || !strings.Contains(err.Error(), "different key") {
+		t.Errorf("expected refusal to repoint contact, got: %v", err)
+	}
+
+	contacts, err := ListContacts()
+	if err != nil {
+		t.Fatalf("ListContacts failed: %v", err)
+	}
+	if len(contacts) != 1 || contacts[0].Recipient != alice.Recipient {
+		t.Errorf("unexpected contacts: %+v", contacts)
+	}
+}
+
+func TestAddContact_RejectsInvalidInput(t *testing.T) {
+	_, cleanup := testutil.SetupTestEnv(t)
+	defer cleanup()
+
+	if err := AddContact("bob", "not-a-key"); err == nil {
+		t.Error("expected invalid recipient to be rejected")
+	}
+	if err := AddContact("age1bob", "age1qqqq"); err == nil {
+		t.Error("expected name that looks like a key to be rejected")
+	}
+}
+
+func TestResolveRecipient(t *testing.T) {
+	_, cleanup := testutil.SetupTestEnv(t)
+	defer cleanup()
+
+	identity, err := GenerateIdentity("key")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := Config{Contacts: []Contact{{Name: "alice", Recipient: identity.Recipient}}}
+
+	contact, err := ResolveRecipient("alice", cfg)
+	if err != nil || contact.Recipient != identity.Recipient || contact.Name != "alice" {
+		t.Errorf("expected contact alice, got %+v, %v", contact, err)
+	}
+
+	contact, err = ResolveRecipient(identity.Recipient, cfg)
+	if err != nil || contact.Recipient != identity.Recipient || contact.Name != "" {
+		t.Errorf("expected raw key to resolve, got %+v, %v", contact, err)
+	}
+
+	if _, err := ResolveRecipient("mallory", cfg); err == nil || !strings.Contains(err.Error(), "unknown contact") {
+		t.Errorf("expected unknown contact error, got: %v", err)
+	}
+}
+
+func TestCreateSealedItem_Recipient_OnlyIdentityCanRead(t *testing.T) {
+	_, cleanup := testutil.SetupTestEnv(t)
+	defer cleanup()
+
+	identity, err := GenerateIdentity("alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	authority := &timeauth.FakeAuthority{DefaultRound: 100, CurrentRound: 200}
+	id, err := CreateSealedItemWithOptions(time.Now().UTC().Add(time.Hour), InputSourceStdin, "", []byte("for alice"), authority, ItemOptions{
+		Recipient: Contact{Name: "alice", Recipient: identity.Recipient},
+	})
+	if err != nil {
+		t.Fatalf("CreateSealedItemWithOptions failed: %v", err)
+	}
+
+	item, itemDir, _ := LoadItem(id)
+	if item.Recipient != identity.Recipient || item.RecipientName != "alice" {
+		t.Errorf("recipient not recorded: %q %q", item.Recipient, item.RecipientName)
+	}
+
+	item, err = TryMaterialize(item, itemDir, authority)
+	if err != nil {
+		t.Fatalf("TryMaterialize failed: %v", err)
+	}
+
+	unsealed, _ := os.ReadFile(UnsealedPath(item, itemDir))
+	if strings.Contains(string(unsealed), "for alice") {
+		t.Fatal("unsealed content should be encrypted to the recipient")
+	}
+
+	output := filepath.Join(t.TempDir(), "decrypted")
+	if err := ageDecryptStep(UnsealedPath(item, itemDir), output, identity.Path); err != nil {
+		t.Fatalf("recipient could not decrypt: %v", err)
+	}
+	data, _ := os.ReadFile(output)
+	if string(data) != "for alice" {
+		t.Errorf("unexpected decrypted content: %q", data)
+	}
+}
+
+func TestDecryptForIdentity(t *testing.T) {
+	_, cleanup := testutil.SetupTestEnv(t)
+	defer cleanup()
+
+	alice, err := GenerateIdentity("alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := GenerateIdentity("bob"); err != nil {
+		t.Fatal(err)
+	}
+	ciphertext, err := encryptToRecipient([]byte("for alice"), alice.Recipient)
+	if err != nil {
+		t.Fatal(err)
+	}
+	item := SealedItem{ID: "a1b2c3d4-5e6f-7890-abcd-ef1234567890", Recipient: alice.Recipient}
+
+	// By stored name or by identity file
+	for _, identity := range []string{"alice", alice.Path} {
+		r, err := DecryptForIdentity(item, bytes.NewReader(ciphertext), identity)
+		if err != nil {
+			t.Fatalf("DecryptForIdentity(%s) failed: %v", identity, err)
+		}
+		if data, _ := io.ReadAll(r); string(data) != "for alice" {
+			t.Errorf("DecryptForIdentity(%s): unexpected content %q", identity, data)
+		}
+	}
+
+	if _, err := DecryptForIdentity(item, bytes.NewReader(ciphertext), "bob"); err == nil || !strings.Contains(err.Error(), "does not match") {
+		t.Errorf("expected another identity to be refused, got %v", err)
+	}
+	if _, err := DecryptForIdentity(item, bytes.NewReader(ciphertext), "carol"); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("expected a missing identity to be reported, got %v", err)
+	}
+	if _, err := DecryptForIdentity(SealedItem{ID: item.ID}, bytes.NewReader(ciphertext), "alice"); err == nil || !strings.Contains(err.Error(), "not sealed for a recipient") {
+		t.Errorf("expected an item without recipient to be refused, got %v", err)
+	}
+}
@@ -0,0 +1,128 @@
+package seal
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"seal/internal/clock"
+	"seal/internal/timeauth"
+)
+
+// Countdown sources identify how remaining time was computed.
+const (
+	CountdownSourceRounds     = "rounds"      // (target_round - current_round) * period
+	CountdownSourceLocalClock = "local_clock" // unlock_time - local time (fallback)
+)
+
+// Countdown describes the time remaining until a sealed item can unlock.
+type Countdown struct {
+	Remaining    time.Duration
+	Source       string
+	UnlockAt     time.Time // when the target round is published, or the recorded unlock time for local_clock
+	TargetRound  uint64    // round the item unlocks at; zero for local_clock
+	CurrentRound uint64    // latest round of the authority; zero for local_clock
+}
+
+// ComputeCountdown calculates the time remaining until an item can unlock.
+// Prefers round math against the authority, which does not depend on the local clock.
+// Falls back to the local clock if the authority is nil or unreachable.
+func ComputeCountdown(item SealedItem, authority timeauth.Authority) Countdown {
+	if authority != nil {
+		if countdown, err := roundCountdown(item, authority); err == nil {
+			return countdown
+		}
+	}
+
+	remaining := clock.Until(item.UnlockTime)
+	if remaining < 0 {
+		remaining = 0
+	}
+
+	return Countdown{Remaining: remaining, Source: CountdownSourceLocalClock, UnlockAt: item.UnlockTime}
+}
+
+// roundCountdown computes remaining time purely from authority rounds.
+// Also reports the authority's earliest unlock time for the item and both rounds.
+func roundCountdown(item SealedItem, authority timeauth.Authority) (Countdown, error) {
+	targetRound, err := extractTargetRound(item.KeyRef)
+	if err != nil {
+		return Countdown{}, err
+	}
+
+	unlockAt, err := authority.EarliestUnlockTime(timeauth.KeyReference(item.KeyRef))
+	if err != nil {
+		return Countdown{}, err
+	}
+
+	currentRound, err := authority.LatestRound(context.Background())
+	if err != nil {
+		return Countdown{}, err
+	}
+
+	countdown := Countdown{Source: CountdownSourceRounds, UnlockAt: unlockAt, TargetRound: targetRound, CurrentRound: currentRound}
+	if currentRound >= targetRound {
+		return countdown, nil
+	}
+
+	// Both times come from the round schedule, so their difference is
+	// (target_round - current_round) * period regardless of the local clock
+	currentTime, err := authority.RoundTime(currentRound)
+	if err != nil {
+		return Countdown{}, err
+	}
+
+	countdown.Remaining = unlockAt.Sub(currentTime)
+	return countdown, nil
+}
+
+// formatRounds describes how far the authority is from an item's target round.
+func formatRounds(countdown Countdown) string {
+	if countdown.CurrentRound >= countdown.TargetRound {
+		return fmt.Sprintf("%d (current %d, reached)", countdown.TargetRound, countdown.CurrentRound)
+	}
+	return fmt.Sprintf("%d (current %d, %d to go)", countdown.TargetRound, countdown.CurrentRound, countdown.TargetRound-countdown.CurrentRound)
+}
+
+// formatUnlockTime formats the time an item unlocks. effective is the
+// authority's earliest unlock time; if it differs from the time requested
+// at lock time, the requested time is shown alongside it.
+// A zero effective time falls back to the requested time.
+func formatUnlockTime(item SealedItem, effective time.Time) string {
+	requested := item.UnlockTime.Format(time.RFC3339)
+	if effective.IsZero() || effective.Equal(item.UnlockTime) {
+		return requested
+	}
+
+	return fmt.Sprintf("%s (requested %s)", effective.UTC().Format(time.RFC3339), requested)
+}
+
+// formatRemaining formats a duration as days, hours, minutes and seconds.
+func formatRemaining(d time.Duration) string {
+	d = d.Truncate(time.Second)
+
+	days := d / (24 * time.Hour)
+	d -= days * 24 * time.Hour
+	hours := d / time.Hour
+	d -= hours * time.Hour
+	minutes := d / time.Minute
+	d -= minutes * time.Minute
+	seconds := d / time.Second
+
+	var parts []string
+	if days > 0 {
+		parts = append(parts, fmt.Sprintf("%dd", days))
+	}
+	if hours > 0 {
+		parts = append(parts, fmt.Sprintf("%dh", hours))
+	}
+	if minutes > 0 {
+		parts = append(parts, fmt.Sprintf("%dm", minutes))
+	}
+	if seconds > 0 || len(parts) == 0 {
+		parts = append(parts, fmt.Sprintf("%ds", seconds))
+	}
+
+	return strings.Join(parts, " ")
+}
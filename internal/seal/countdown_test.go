@@ -0,0 +1,178 @@
+package seal
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"seal/internal/timeauth"
+)
+
+func TestComputeCountdown_UsesRoundMath(t *testing.T) {
+	authority := &timeauth.FakeAuthority{
+		GenesisTime:  time.Date(2023, 3, 1, 13, 0, 0, 0, time.UTC),
+		Period:       3 * time.Second,
+		CurrentRound: 1000,
+	}
+
+	// Local unlock time deliberately disagrees with round math
+	item := SealedItem{
+		ID:         "test-id",
+		State:      StateSealed,
+		UnlockTime: time.Now().UTC().Add(365 * 24 * time.Hour),
+		KeyRef:     `{"network":"fake","target_round":1200}`,
+	}
+
+	countdown := ComputeCountdown(item, authority)
+
+	if countdown.Source != CountdownSourceRounds {
+		t.Errorf("expected source %q, got %q", CountdownSourceRounds, countdown.Source)
+	}
+
+	// (1200 - 1000) * 3s
+	if countdown.Remaining != 600*time.Second {
+		t.Errorf("expected 600s remaining, got %v", countdown.Remaining)
+	}
+
+	// Unlock happens when the target round is published, not at the requested time
+	if want := authority.GenesisTime.Add(1199 * 3 * time.Second); !countdown.UnlockAt.Equal(want) {
+		t.Errorf("expected unlock at %v, got %v", want, countdown.UnlockAt)
+	}
+
+	if countdown.TargetRound != 1200 || countdown.CurrentRound != 1000 {
+		t.Errorf("expected rounds 1200 and 1000, got %d and %d", countdown.TargetRound, countdown.CurrentRound)
+	}
+}
+
+func TestFormatUnlockTime_ShowsRequestedWhenDifferent(t *testing.T) {
+	requested := time.Date(2026, 12, 31, 23, 59, 59, 0, time.UTC)
+	item := SealedItem{UnlockTime: requested}
+
+	if got := formatUnlockTime(item, time.Time{}); got != "2026-12-31T23:59:59Z" {
+		t.Errorf("without an effective time, expected the requested time, got %q", got)
+	}
+
+	if got := formatUnlockTime(item, requested); got != "2026-12-31T23:59:59Z" {
+		t.Errorf("with a matching effective time, expected no annotation, got %q", got)
+	}
+
+	want := "2027-01-01T00:00:01Z (requested 2026-12-31T23:59:59Z)"
+	if got := formatUnlockTime(item, requested.Add(2*time.Second)); got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+}
+
+func TestComputeCountdown_TargetReached(t *testing.T) {
+	authority := &timeauth.FakeAuthority{CurrentRound: 2000}
+
+	item := SealedItem{
+		ID:     "test-id",
+		State:  StateSealed,
+		KeyRef: `{"network":"fake","target_round":1200}`,
+	}
+
+	countdown := ComputeCountdown(item, authority)
+	if countdown.Source != CountdownSourceRounds {
+		t.Errorf("expected source %q, got %q", CountdownSourceRounds, countdown.Source)
+	}
+	if countdown.Remaining != 0 {
+		t.Errorf("expected zero remaining, got %v", countdown.Remaining)
+	}
+}
+
+func TestComputeCountdown_FallsBackToLocalClock(t *testing.T) {
+	unlockTime := time.Now().UTC().Add(2 * time.Hour)
+	item := SealedItem{
+		ID:         "test-id",
+		State:      StateSealed,
+		UnlockTime: unlockTime,
+		KeyRef:     `{"network":"fake","target_round":1200}`,
+	}
+
+	testCases := []struct {
+		name      string
+		authority timeauth.Authority
+	}{
+		{"network failure", &timeauth.FakeAuthority{CanUnlockError: errors.New("network unreachable")}},
+		{"no authority", nil},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			countdown := ComputeCountdown(item, tc.authority)
+
+			if countdown.Source != CountdownSourceLocalClock {
+				t.Errorf("expected source %q, got %q", CountdownSourceLocalClock, countdown.Source)
+			}
+
+			if countdown.Remaining <= time.Hour || countdown.Remaining > 2*time.Hour {
+				t.Errorf("expected roughly 2h remaining, got %v", countdown.Remaining)
+			}
+		})
+	}
+}
+
+func TestFormatRemaining(t *testing.T) {
+	testCases := []struct {
+		d    time.Duration
+		want string
+	}{
+		{0, "0s"},
+		{45 * time.Second, "45s"},
+		{time.Hour, "1h"},
+		{3*24*time.Hour + 4*time.Hour + 5*time.Minute, "3d 4h 5m"},
+		{90*time.Second + 500*time.Millisecond, "1m 30s"},
+	}
+
+	for _, tc := range testCases {
+		if got := formatRemaining(tc.d); got != tc.want {
+			t.Errorf("formatRemaining(%v) = %q, want %q", tc.d, got, tc.want)
+		}
+	}
+}
+
+func TestFormatStatusOutput_LabelsCountdownSource(t *testing.T) {
+	items := []SealedItem{
+		{ID: "sealed-id", State: StateSealed, InputType: "stdin"},
+		{ID: "unlocked-id", State: StateUnlocked, InputType: "stdin"},
+	}
+	countdowns := map[string]Countdown{
+		"sealed-id":   {Remaining: 90 * time.Minute, Source: CountdownSourceRounds},
+		"unlocked-id": {Remaining: time.Hour, Source: CountdownSourceRounds},
+	}
+
+	output := FormatStatusOutput(items, countdowns)
+
+	if !strings.Contains(output, "remaining: 1h 30m (source: rounds)") {
+		t.Errorf("expected countdown line for sealed item, got: %s", output)
+	}
+
+	if strings.Count(output, "remaining:") != 1 {
+		t.Errorf("only sealed items should report remaining time, got: %s", output)
+	}
+}
+
+func TestFormatStatusOutput_ShowsRounds(t *testing.T) {
+	items := []SealedItem{
+		{ID: "due-id", State: StateSealed, InputType: "stdin"},
+		{ID: "reached-id", State: StateSealed, InputType: "stdin"},
+		{ID: "offline-id", State: StateSealed, InputType: "stdin"},
+	}
+	countdowns := map[string]Countdown{
+		"due-id":     {Remaining: 10 * time.Minute, Source: CountdownSourceRounds, TargetRound: 1200, CurrentRound: 1000},
+		"reached-id": {Source: CountdownSourceRounds, TargetRound: 1200, CurrentRound: 1201},
+		"offline-id": {Remaining: time.Hour, Source: CountdownSourceLocalClock},
+	}
+
+	output := FormatStatusOutput(items, countdowns)
+
+	for _, want := range []string{"round: 1200 (current 1000, 200 to go)", "round: 1200 (current 1201, reached)"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("expected %q, got: %s", want, output)
+		}
+	}
+	if strings.Count(output, "round:") != 2 {
+		t.Errorf("a local clock countdown has no rounds to show, got: %s", output)
+	}
+}
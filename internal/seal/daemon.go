@@ -0,0 +1,175 @@
+package seal
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"seal/internal/clock"
+)
+
+// DefaultDaemonInterval is the longest the daemon sleeps between status runs.
+const DefaultDaemonInterval = 15 * time.Minute
+
+const (
+	// daemonRoundSlack is added to an item's unlock time: a beacon publishes a
+	// round shortly after it starts, not at the exact instant.
+	daemonRoundSlack = 2 * time.Second
+	// daemonRetryWait is how long to wait before checking a due item again
+	// that did not unlock, e.g. because its round is late or the authority is unreachable.
+	daemonRetryWait = 30 * time.Second
+)
+
+// ErrDaemonRunning is returned by RunDaemon while another daemon serves the same store.
+var ErrDaemonRunning = errors.New("a seal daemon is already running for this store")
+
+// daemonLockName is the lock file a running daemon holds, among the item locks.
+const daemonLockName = "daemon.lock"
+
+// DaemonRequest contains parameters for running the materialization daemon.
+type DaemonRequest struct {
+	Interval time.Duration // longest sleep between status runs
+	Exec     string        // program run for each item the daemon unlocks (empty = none)
+}
+
+// daemon runs status checks in a loop.
+type daemon struct {
+	exec     string
+	interval time.Duration
+	newRun   func() *statusRun
+}
+
+// RunDaemon materializes items as they become eligible, without anyone running seal status.
+// Each pass performs a full status check: validation, unlock, notifications, reveal delivery,
+// post-processing and retention. Between passes it sleeps until the earliest sealed item
+// is due, but never longer than the interval, so clock changes and new items are picked up.
+// Calls unlocked for every item it unlocks and warn for non-fatal problems.
+// Only one daemon runs per store; returns an error wrapping ErrDaemonRunning at
+// once if another holds the store. Runs until a fatal error occurs.
+func RunDaemon(req DaemonRequest, unlocked func(item SealedItem), warn func(msg string)) error {
+	interval := req.Interval
+	if interval <= 0 {
+		interval = DefaultDaemonInterval
+	}
+
+	lock, err := lockDaemon()
+	if err != nil {
+		return err
+	}
+	defer lock.unlock()
+
+	d := &daemon{exec: req.Exec, interval: interval, newRun: newStatusRun}
+	for {
+		wait, err := d.pass(unlocked, warn)
+		if err != nil {
+			return err
+		}
+		time.Sleep(wait)
+	}
+}
+
+// lockDaemon takes the store's daemon lock without waiting. Like item locks it is
+// an advisory lock the operating system releases when the daemon exits.
+func lockDaemon() (*itemLock, error) {
+	baseDir, err := GetSealBaseDir()
+	if err != nil {
+		return nil, err
+	}
+	locksDir := filepath.Join(baseDir, locksDirName)
+	if err := os.MkdirAll(locksDir, 0700); err != nil {
+		return nil, fmt.Errorf("cannot create lock directory: %w", err)
+	}
+
+	file, err := os.OpenFile(filepath.Join(locksDir, daemonLockName), os.O_RDWR|os.O_CREATE, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("cannot open daemon lock: %w", err)
+	}
+	locked, err := tryLockFile(file)
+	if err != nil {
+		file.Close()
+		return nil, fmt.Errorf("cannot lock the store for the daemon: %w", err)
+	}
+	if !locked {
+		holder := readLockHolder(file)
+		file.Close()
+		return nil, fmt.Errorf("%w (%s)", ErrDaemonRunning, holder)
+	}
+
+	recordLockHolder(file)
+	return &itemLock{file: file}, nil
+}
+
+// pass performs one status check and returns how long to sleep before the next.
+func (d *daemon) pass(unlocked func(item SealedItem), warn func(msg string)) (time.Duration, error) {
+	result, err := getStatus(d.newRun)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, validationErr := range result.ValidationErrors {
+		warn(fmt.Sprintf("warning: %v", validationErr))
+	}
+	for _, warning := range result.Warnings {
+		warn(warning)
+	}
+	if result.MaterializationFailed {
+		warn(fmt.Sprintf("warning: materialization failed: %v", result.FirstError))
+	}
+
+	if len(result.Unlocked) > 0 {
+		baseDir, err := GetSealBaseDir()
+		if err != nil {
+			return 0, err
+		}
+		for _, item := range result.Items {
+			if !slices.Contains(result.Unlocked, item.ID) {
+				continue
+			}
+			unlocked(item)
+			if d.exec != "" {
+				if err := runUnlockHook(d.exec, item, filepath.Join(baseDir, item.ID)); err != nil {
+					warn(fmt.Sprintf("warning: unlock hook failed for item %s: %v", item.ID, err))
+				}
+			}
+		}
+	}
+
+	return nextDaemonWake(result, clock.UTC(), d.interval), nil
+}
+
+// nextDaemonWake returns how long to sleep until the earliest sealed item may unlock,
+// at most interval. Items backing off after a failure are due at their next attempt.
+func nextDaemonWake(result StatusResult, now time.Time, interval time.Duration) time.Duration {
+	wait := interval
+	for _, item := range result.Items {
+		if item.State != StateSealed {
+			continue
+		}
+		countdown, ok := result.Countdowns[item.ID]
+		if !ok {
+			// Permanently locked or failing validation: nothing to wait for
+			continue
+		}
+
+		if due := untilDue(item, countdown, now); due < wait {
+			wait = due
+		}
+	}
+	return wait
+}
+
+// untilDue returns how long until a sealed item should be checked again: just
+// after its round is published, or at its next attempt while it backs off.
+func untilDue(item SealedItem, countdown Countdown, now time.Time) time.Duration {
+	due := countdown.Remaining + daemonRoundSlack
+	if item.NextUnlockAttempt != nil && item.NextUnlockAttempt.After(now.Add(due)) {
+		due = item.NextUnlockAttempt.Sub(now)
+	}
+	if countdown.Remaining <= 0 && due < daemonRetryWait {
+		due = daemonRetryWait
+	}
+	return due
+}
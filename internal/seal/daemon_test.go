@@ -0,0 +1,138 @@
+package seal
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"seal/internal/testutil"
+	"seal/internal/timeauth"
+)
+
+func TestDaemonPass_UnlocksDueItemsAndRunsHook(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("hook is a shell script")
+	}
+	_, cleanup := testutil.SetupTestEnv(t)
+	defer cleanup()
+
+	due := &timeauth.FakeAuthority{DefaultRound: 100, CurrentRound: 200}
+	dueID, err := CreateSealedItem(time.Now().UTC().Add(time.Hour), InputSourceStdin, "", []byte("due"), due)
+	if err != nil {
+		t.Fatalf("CreateSealedItem failed: %v", err)
+	}
+	later := &timeauth.FakeAuthority{AuthorityName: "later", DefaultRound: 300, CurrentRound: 200}
+	if _, err := CreateSealedItem(time.Now().UTC().Add(time.Hour), InputSourceStdin, "", []byte("later"), later); err != nil {
+		t.Fatalf("CreateSealedItem failed: %v", err)
+	}
+
+	hookDir := t.TempDir()
+	hookOut := filepath.Join(hookDir, "out")
+	hook := filepath.Join(hookDir, "hook.sh")
+	script := "#!/bin/sh\necho \"$SEAL_ITEM_ID $SEAL_UNSEALED_PATH\" >> " + hookOut + "\n"
+	if err := os.WriteFile(hook, []byte(script), 0700); err != nil {
+		t.Fatal(err)
+	}
+
+	d := &daemon{exec: hook, interval: time.Hour, newRun: func() *statusRun {
+		run := newStatusRun()
+		run.authorityFor = func(item SealedItem) timeauth.Authority {
+			if item.TimeAuthority == "later" {
+				return later
+			}
+			return due
+		}
+		return run
+	}}
+
+	var unlocked []string
+	var warnings []string
+	wait, err := d.pass(func(item SealedItem) { unlocked = append(unlocked, item.ID) }, func(msg string) { warnings = append(warnings, msg) })
+	if err != nil {
+		t.Fatalf("pass failed: %v", err)
+	}
+	if len(unlocked) != 1 || unlocked[0] != dueID {
+		t.Fatalf("expected only %s to unlock, got %v (warnings: %v)", dueID, unlocked, warnings)
+	}
+
+	// The fake period is 3s: the other item is 100 rounds away, well within the interval
+	if wait <= 0 || wait >= time.Hour {
+		t.Errorf("expected to wake for the next item before the interval, got %s", wait)
+	}
+
+	got, err := os.ReadFile(hookOut)
+	if err != nil {
+		t.Fatalf("hook did not run: %v", err)
+	}
+	item, itemDir, _ := LoadItem(dueID)
+	if want := dueID + " " + UnsealedPath(item, itemDir); strings.TrimSpace(string(got)) != want {
+		t.Errorf("hook saw %q, want %q", got, want)
+	}
+
+	// A second pass unlocks nothing and runs no hook
+	unlocked = nil
+	if _, err := d.pass(func(item SealedItem) { unlocked = append(unlocked, item.ID) }, func(string) {}); err != nil {
+		t.Fatalf("pass failed: %v", err)
+	}
+	if len(unlocked) != 0 {
+		t.Errorf("already unlocked items must not be reported again: %v", unlocked)
+	}
+	if got, _ := os.ReadFile(hookOut); strings.Count(string(got), "\n") != 1 {
+		t.Errorf("hook ran more than once: %q", got)
+	}
+}
+
+func TestNextDaemonWake(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	backoff := now.Add(10 * time.Minute)
+
+	testCases := []struct {
+		name      string
+		remaining time.Duration
+		next      *time.Time
+		want      time.Duration
+	}{
+		{"sleeps until unlock", 5 * time.Minute, nil, 5*time.Minute + daemonRoundSlack},
+		{"capped at the interval", 2 * time.Hour, nil, time.Hour},
+		{"due but still sealed", 0, nil, daemonRetryWait},
+		{"backing off", 0, &backoff, 10 * time.Minute},
+	}
+	for _, tc := range testCases {
+		result := StatusResult{
+			Items:      []SealedItem{{ID: "a", State: StateSealed, NextUnlockAttempt: tc.next}, {ID: "b", State: StateUnlocked}},
+			Countdowns: map[string]Countdown{"a": {Remaining: tc.remaining}},
+		}
+		if got := nextDaemonWake(result, now, time.Hour); got != tc.want {
+			t.Errorf("%s: got %s, want %s", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestRunDaemon_RefusesSecondDaemon(t *testing.T) {
+	_, cleanup := testutil.SetupTestEnv(t)
+	defer cleanup()
+
+	lock, err := lockDaemon()
+	if err != nil {
+		t.Fatalf("lockDaemon failed: %v", err)
+	}
+
+	// Fails at once, before any status pass
+	err = RunDaemon(DaemonRequest{}, func(SealedItem) {}, func(string) {})
+	if !errors.Is(err, ErrDaemonRunning) || !strings.Contains(err.Error(), fmt.Sprintf("seal process %d", os.Getpid())) {
+		t.Fatalf("expected a running daemon to be reported, got %v", err)
+	}
+
+	// Released when the daemon stops
+	lock.unlock()
+	lock, err = lockDaemon()
+	if err != nil {
+		t.Fatalf("lockDaemon after release failed: %v", err)
+	}
+	lock.unlock()
+}
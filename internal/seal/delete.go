@@ -0,0 +1,171 @@
+package seal
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DeleteResult reports a deleted item.
+type DeleteResult struct {
+	ID       string
+	Warnings []string // best-effort shredding problems and content left in place
+	Plan     Plan     // with DryRun, the files that would be shredded
+}
+
+// DeleteOptions controls how an item is deleted.
+type DeleteOptions struct {
+	Force    bool // also delete a still-sealed item, or one that fails validation
+	Paranoid bool // rename each file to a random name before shredding it
+	DryRun   bool // check that the item can be deleted and list its files, changing nothing
+}
+
+// Delete removes an item from the store, shredding its metadata, payload,
+// time-locked key and any unsealed content inside the store (best-effort).
+//
+// A still-sealed item is deleted only with Force: nothing can decrypt its content
+// afterwards, not even once the unlock time has passed. An item that fails state
+// validation, including one whose metadata cannot be read, also needs Force.
+// Content written outside the store with unseal_to belongs to the user and is left in place.
+//
+// With Paranoid, no file is overwritten or removed under its own name, so the
+// directory entries left behind do not tell what the files were.
+func Delete(id string, opts DeleteOptions) (DeleteResult, error) {
+	// Reject anything that is not a UUID so the ID cannot escape the base directory
+	if _, err := uuid.Parse(id); err != nil {
+		return DeleteResult{}, fmt.Errorf("invalid item id: %s", id)
+	}
+
+	baseDir, err := GetSealBaseDir()
+	if err != nil {
+		return DeleteResult{}, err
+	}
+	itemDir := filepath.Join(baseDir, id)
+	if _, err := os.Lstat(itemDir); os.IsNotExist(err) {
+		return DeleteResult{}, fmt.Errorf("item not found: %s", id)
+	}
+
+	lock, err := lockItem(itemDir)
+	if err != nil {
+		return DeleteResult{}, err
+	}
+	defer lock.unlock()
+
+	item, err := loadMetadata(itemDir)
+	if err == nil {
+		err = ValidateItemState(item, itemDir)
+	}
+	if err != nil && !opts.Force {
+		return DeleteResult{}, fmt.Errorf("item %s fails validation (%v); pass --force to delete it anyway", id, err)
+	}
+
+	if item.State == StateSealed && !opts.Force {
+		return DeleteResult{}, fmt.Errorf("item %s is still sealed until %s; deleting it destroys the content for good, pass --force to delete it anyway",
+			id, item.UnlockTime.UTC().Format(time.RFC3339))
+	}
+
+	result := DeleteResult{ID: id}
+	if item.UnsealTo != "" && item.State == StateUnlocked {
+		if _, err := os.Lstat(UnsealedPath(item, itemDir)); err == nil {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("warning: unsealed content at %s is outside the store and is left in place", UnsealedPath(item, itemDir)))
+		}
+	}
+
+	if opts.DryRun {
+		result.Plan = Plan{DryRun: true, Changes: deletePlan(itemDir)}
+		return result, nil
+	}
+
+	// Metadata goes first: the item leaves listings at once, and an interrupted
+	// delete leaves a directory that is skipped until it is deleted again
+	for _, name := range immutableFiles {
+		setImmutable(filepath.Join(itemDir, name), false)
+	}
+	metaPath := filepath.Join(itemDir, "meta.json")
+	if _, err := os.Lstat(metaPath); err == nil {
+		result.Warnings = append(result.Warnings, shredItemFile(metaPath, opts.Paranoid)...)
+		if _, err := os.Lstat(metaPath); err == nil {
+			return result, fmt.Errorf("item %s: could not remove metadata; nothing else was deleted", id)
+		}
+	}
+
+	result.Warnings = append(result.Warnings, shredItemDir(itemDir, opts.Paranoid)...)
+	removeItemLock(itemDir)
+
+	return result, nil
+}
+
+// deletePlan lists the files Delete shreds, in the order it shreds them:
+// metadata first, then everything else under the item directory.
+func deletePlan(itemDir string) []Change {
+	var changes []Change
+	metaPath := filepath.Join(itemDir, "meta.json")
+	if _, err := os.Lstat(metaPath); err == nil {
+		changes = append(changes, Change{Action: ChangeShred, Path: metaPath})
+	}
+
+	filepath.WalkDir(itemDir, func(path string, entry fs.DirEntry, err error) error {
+		if err == nil && !entry.IsDir() && path != metaPath {
+			changes = append(changes, Change{Action: ChangeShred, Path: path})
+		}
+		return nil
+	})
+
+	return changes
+}
+
+// shredItemDir shreds every file under an item directory and removes it (best-effort).
+// Symlinks are removed without touching their targets.
+func shredItemDir(itemDir string, paranoid bool) []string {
+	var warnings []string
+	var dirs []string
+
+	filepath.WalkDir(itemDir, func(path string, entry fs.DirEntry, err error) error {
+		switch {
+		case err != nil:
+			warnings = append(warnings, fmt.Sprintf("warning: could not read %s: %v", path, err))
+		case entry.IsDir():
+			dirs = append(dirs, path)
+		case entry.Type().IsRegular():
+			warnings = append(warnings, shredItemFile(path, paranoid)...)
+		default:
+			os.Remove(path)
+		}
+		return nil
+	})
+
+	// Deepest directories first
+	for i := len(dirs) - 1; i >= 0; i-- {
+		if err := os.Remove(dirs[i]); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			warnings = append(warnings, fmt.Sprintf("warning: could not remove %s: %v", dirs[i], err))
+		}
+	}
+
+	return warnings
+}
+
+// shredItemFile shreds one file of an item, first renaming it to a random name
+// in the same directory if paranoid. A file that cannot be renamed is shredded
+// under its own name.
+func shredItemFile(path string, paranoid bool) []string {
+	if !paranoid {
+		return ShredFile(path)
+	}
+
+	name := make([]byte, 16)
+	if _, err := rand.Read(name); err != nil {
+		return append([]string{fmt.Sprintf("warning: could not rename %s before shredding: %v", path, err)}, ShredFile(path)...)
+	}
+	renamed := filepath.Join(filepath.Dir(path), hex.EncodeToString(name))
+	if err := os.Rename(path, renamed); err != nil {
+		return append([]string{fmt.Sprintf("warning: could not rename %s before shredding: %v", path, err)}, ShredFile(path)...)
+	}
+	return ShredFile(renamed)
+}
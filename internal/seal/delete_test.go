@@ -0,0 +1,158 @@
+package seal
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"seal/internal/testutil"
+	"seal/internal/timeauth"
+)
+
+func TestDelete_SealedItemNeedsForce(t *testing.T) {
+	_, cleanup := testutil.SetupTestEnv(t)
+	defer cleanup()
+
+	authority := &timeauth.FakeAuthority{DefaultRound: 100}
+	id, err := CreateSealedItem(time.Now().UTC().Add(time.Hour), InputSourceStdin, "", []byte("data"), authority)
+	if err != nil {
+		t.Fatalf("CreateSealedItem failed: %v", err)
+	}
+
+	if _, err := Delete(id, DeleteOptions{}); err == nil || !strings.Contains(err.Error(), "still sealed") {
+		t.Fatalf("expected a sealed item to need --force, got %v", err)
+	}
+	if _, _, err := LoadItem(id); err != nil {
+		t.Fatalf("refused delete changed the item: %v", err)
+	}
+
+	if _, err := Delete(id, DeleteOptions{Force: true, Paranoid: true}); err != nil {
+		t.Fatalf("Delete --force --paranoid failed: %v", err)
+	}
+	if _, _, err := LoadItem(id); err == nil || !strings.Contains(err.Error(), "item not found") {
+		t.Errorf("expected the item to be gone, got %v", err)
+	}
+}
+
+func TestDelete_UnlockedItem(t *testing.T) {
+	_, cleanup := testutil.SetupTestEnv(t)
+	defer cleanup()
+
+	authority := &timeauth.FakeAuthority{DefaultRound: 100, CurrentRound: 200}
+	id, err := CreateSealedItem(time.Now().UTC().Add(time.Hour), InputSourceStdin, "", []byte("data"), authority)
+	if err != nil {
+		t.Fatalf("CreateSealedItem failed: %v", err)
+	}
+	item, itemDir, _ := LoadItem(id)
+	if _, err := TryMaterialize(item, itemDir, authority); err != nil {
+		t.Fatalf("TryMaterialize failed: %v", err)
+	}
+
+	result, err := Delete(id, DeleteOptions{})
+	if err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if result.ID != id {
+		t.Errorf("unexpected result %+v", result)
+	}
+	if _, err := os.Stat(itemDir); !os.IsNotExist(err) {
+		t.Errorf("expected the item directory to be removed, got %v", err)
+	}
+
+	if _, err := Delete(id, DeleteOptions{Force: true}); err == nil || !strings.Contains(err.Error(), "item not found") {
+		t.Errorf("expected a deleted item to be not found, got %v", err)
+	}
+	if _, err := Delete("../elsewhere", DeleteOptions{Force: true}); err == nil || !strings.Contains(err.Error(), "invalid item id") {
+		t.Errorf("expected an invalid id to be refused, got %v", err)
+	}
+}
+
+func TestDelete_InvalidItemNeedsForce(t *testing.T) {
+	_, cleanup := testutil.SetupTestEnv(t)
+	defer cleanup()
+
+	authority := &timeauth.FakeAuthority{DefaultRound: 100}
+	id, err := CreateSealedItem(time.Now().UTC().Add(time.Hour), InputSourceStdin, "", []byte("data"), authority)
+	if err != nil {
+		t.Fatalf("CreateSealedItem failed: %v", err)
+	}
+	_, itemDir, _ := LoadItem(id)
+
+	// An interrupted delete leaves a directory without metadata
+	if err := os.Remove(filepath.Join(itemDir, "meta.json")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Delete(id, DeleteOptions{}); err == nil || !strings.Contains(err.Error(), "fails validation") {
+		t.Fatalf("expected an invalid item to need --force, got %v", err)
+	}
+	if _, err := Delete(id, DeleteOptions{Force: true}); err != nil {
+		t.Fatalf("Delete --force failed: %v", err)
+	}
+	if _, err := os.Stat(itemDir); !os.IsNotExist(err) {
+		t.Errorf("expected the item directory to be removed, got %v", err)
+	}
+}
+
+func TestShredItemFile_ParanoidRenamesFirst(t *testing.T) {
+	dir := t.TempDir()
+	target := filepath.Join(dir, "target")
+	if err := os.WriteFile(target, []byte("data"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	// Shredding refuses a symlink and leaves it, so the name it was left under shows
+	link := filepath.Join(dir, "meta.json")
+	if err := os.Symlink(target, link); err != nil {
+		t.Skipf("symlinks not supported: %v", err)
+	}
+
+	if warnings := shredItemFile(link, true); len(warnings) == 0 {
+		t.Error("expected a warning for the symlink")
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, entry := range entries {
+		if entry.Name() == "meta.json" {
+			t.Error("the file kept its name")
+		}
+	}
+	if len(entries) != 2 {
+		t.Errorf("expected the target and the renamed link, got %d entries", len(entries))
+	}
+}
+
+func TestDelete_DryRunChangesNothing(t *testing.T) {
+	_, cleanup := testutil.SetupTestEnv(t)
+	defer cleanup()
+
+	authority := &timeauth.FakeAuthority{DefaultRound: 100}
+	id, err := CreateSealedItem(time.Now().UTC().Add(time.Hour), InputSourceStdin, "", []byte("data"), authority)
+	if err != nil {
+		t.Fatalf("CreateSealedItem failed: %v", err)
+	}
+	_, itemDir, _ := LoadItem(id)
+
+	// A dry run is refused for the same reasons as a delete
+	if _, err := Delete(id, DeleteOptions{DryRun: true}); err == nil || !strings.Contains(err.Error(), "still sealed") {
+		t.Fatalf("expected a sealed item to need --force, got %v", err)
+	}
+
+	result, err := Delete(id, DeleteOptions{Force: true, DryRun: true})
+	if err != nil {
+		t.Fatalf("Delete --dry-run failed: %v", err)
+	}
+	changes := result.Plan.Changes
+	if !result.Plan.DryRun || len(changes) < 2 || changes[0].Path != filepath.Join(itemDir, "meta.json") {
+		t.Fatalf("expected a dry-run plan starting with the metadata, got %+v", result.Plan)
+	}
+	if !strings.Contains(FormatPlan(result.Plan), "would shred: "+filepath.Join(itemDir, "payload.bin")) {
+		t.Errorf("expected the payload in the plan, got:\n%s", FormatPlan(result.Plan))
+	}
+	if _, _, err := LoadItem(id); err != nil {
+		t.Errorf("dry run changed the item: %v", err)
+	}
+}
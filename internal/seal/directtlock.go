@@ -0,0 +1,60 @@
+package seal
+
+import (
+	"encoding/base64"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"seal/internal/timeauth"
+)
+
+// directTlockAlgorithm marks an item sealed with --direct-tlock: its payload.bin is
+// the content time-locked as a whole, like an imported tlock file, with no DEK.
+const directTlockAlgorithm = "tlock-direct"
+
+// MaxDirectTlockSize is the largest content --direct-tlock accepts. It is meant for
+// small secrets; larger content is better served by the DEK construction.
+const MaxDirectTlockSize = 64 * 1024
+
+// createDirectTlockItem stores plaintext time-locked directly by the authority.
+// The payload is the binary tlock ciphertext, so it unlocks, exports and verifies
+// exactly as an imported tlock file does.
+func createDirectTlockItem(baseDir string, unlockTime time.Time, inputType InputSource, originalPath string, plaintext []byte, authority timeauth.Authority, targetRound uint64, opts ItemOptions, passphrase *PassphraseProtection) (string, error) {
+	if len(plaintext) > MaxDirectTlockSize {
+		return "", fmt.Errorf("--direct-tlock is limited to %d bytes of content, got %d", MaxDirectTlockSize, len(plaintext))
+	}
+
+	tlockB64, err := authority.TimeLockEncrypt(plaintext, targetRound)
+	if err != nil {
+		return "", fmt.Errorf("time-lock encryption failed: %w", err)
+	}
+	ciphertext, err := base64.StdEncoding.DecodeString(tlockB64)
+	if err != nil || len(ciphertext) == 0 {
+		return "", fmt.Errorf("time authority %s cannot time-lock content directly", authority.Name())
+	}
+
+	keyRef, err := authority.Lock(unlockTime)
+	if err != nil {
+		return "", fmt.Errorf("failed to create key reference: %w", err)
+	}
+
+	id := uuid.New().String()
+
+	// The tlock ciphertext authenticates itself; there is no payload AAD to bind
+	meta := newItemMetadata(id, unlockTime, inputType, originalPath, authority, keyRef, "", opts)
+	meta.Algorithm = directTlockAlgorithm
+	meta.AADVersion = 0
+	meta.PlaintextSize = int64(len(plaintext))
+	meta.CiphertextSize = int64(len(ciphertext))
+	meta.PayloadSHA256 = payloadChecksum(ciphertext)
+	meta.Passphrase = passphrase
+	appendHistory(&meta, HistoryCreated, "")
+
+	if err := writeNewItem(baseDir, meta, ciphertext); err != nil {
+		return "", err
+	}
+
+	return id, nil
+}
@@ -0,0 +1,95 @@
+package seal
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"seal/internal/testutil"
+	"seal/internal/timeauth"
+)
+
+func TestDirectTlock_UnlocksAndVerifies(t *testing.T) {
+	_, cleanup := testutil.SetupTestEnv(t)
+	defer cleanup()
+
+	_, authority := startImportChain(t, 3*time.Second)
+	id, err := CreateSealedItemWithOptions(time.Now().UTC().Add(-30*time.Second), InputSourceStdin, "", []byte("recovery code"), authority, ItemOptions{DirectTlock: true})
+	if err != nil {
+		t.Fatalf("CreateSealedItemWithOptions failed: %v", err)
+	}
+
+	item, itemDir, _ := LoadItem(id)
+	if item.Algorithm != directTlockAlgorithm || item.DEKTlockB64 != "" || item.Nonce != "" || item.AADVersion != 0 {
+		t.Errorf("unexpected metadata: %+v", item)
+	}
+	if !strings.Contains(FormatInspectOutput(item, nil, false), "aad: none (content time-locked directly)") {
+		t.Error("inspect does not describe the direct time-lock")
+	}
+	payload, _ := os.ReadFile(filepath.Join(itemDir, "payload.bin"))
+	if !bytes.HasPrefix(payload, []byte("age-encryption.org/v1\n")) || bytes.Contains(payload, []byte("recovery code")) {
+		t.Errorf("payload should be a tlock ciphertext: %q", payload)
+	}
+	if problems, err := CheckIntegrity(id); err != nil || len(problems) != 0 {
+		t.Errorf("expected the item to verify, got %v, %v", problems, err)
+	}
+
+	// A key reference that no longer matches the payload's round is reported
+	edited := item
+	edited.KeyRef = fmt.Sprintf(`{"target_round": %d}`, 1)
+	if err := saveMetadata(itemDir, edited); err != nil {
+		t.Fatal(err)
+	}
+	if problems, _ := CheckIntegrity(id); len(problems) != 1 || !strings.Contains(problems[0], "metadata records 1") {
+		t.Errorf("expected a round mismatch, got %v", problems)
+	}
+	if err := saveMetadata(itemDir, item); err != nil {
+		t.Fatal(err)
+	}
+
+	item, err = TryMaterialize(item, itemDir, authority)
+	if err != nil || item.State != StateUnlocked {
+		t.Fatalf("expected the item to unlock, got %s, %v", item.State, err)
+	}
+	data, _ := os.ReadFile(UnsealedPath(item, itemDir))
+	if string(data) != "recovery code" {
+		t.Errorf("unexpected content %q", data)
+	}
+}
+
+func TestDirectTlock_Refusals(t *testing.T) {
+	tmpDir, cleanup := testutil.SetupTestEnv(t)
+	defer cleanup()
+	registerFakeAuthority()
+
+	// The fake authority's ciphertext is not a tlock file
+	fake := &timeauth.FakeAuthority{DefaultRound: 100}
+	if _, err := CreateSealedItemWithOptions(time.Now().UTC().Add(time.Hour), InputSourceStdin, "", []byte("data"), fake, ItemOptions{DirectTlock: true}); err == nil {
+		t.Error("expected an authority without tlock ciphertexts to be refused")
+	}
+
+	_, authority := startImportChain(t, 3*time.Second)
+	if _, err := CreateSealedItemWithOptions(time.Now().UTC().Add(time.Hour), InputSourceStdin, "", make([]byte, MaxDirectTlockSize+1), authority, ItemOptions{DirectTlock: true}); err == nil || !strings.Contains(err.Error(), "limited to") {
+		t.Errorf("expected oversized content to be refused, got %v", err)
+	}
+
+	unlockTime := time.Now().UTC().Add(time.Hour).Format(time.RFC3339)
+	if _, err := Lock(LockRequest{Data: []byte("data"), UnlockTime: unlockTime, Authority: "registered-fake", DirectTlock: true, VaultWrap: "transit/keys/escrow"}); err == nil || !strings.Contains(err.Error(), "--vault-wrap") {
+		t.Errorf("expected --vault-wrap to be refused, got %v", err)
+	}
+
+	dir := filepath.Join(tmpDir, "dir")
+	if err := os.Mkdir(dir, 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "f"), []byte("data"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Lock(LockRequest{InputPath: dir, UnlockTime: unlockTime, Authority: "registered-fake", DirectTlock: true}); err == nil || !strings.Contains(err.Error(), "--direct-tlock") {
+		t.Errorf("expected a directory to be refused, got %v", err)
+	}
+}
@@ -0,0 +1,9 @@
+//go:build !(darwin || freebsd || linux)
+
+package seal
+
+// availableBytes reports that free space is unknown on this platform.
+// Materialization then relies on the write itself failing cleanly.
+func availableBytes(dir string) (available uint64, ok bool) {
+	return 0, false
+}
@@ -0,0 +1,15 @@
+//go:build darwin || freebsd || linux
+
+package seal
+
+import "syscall"
+
+// availableBytes returns the space available to unprivileged users on the
+// filesystem containing dir. ok is false if it cannot be determined.
+func availableBytes(dir string) (available uint64, ok bool) {
+	var stat syscall.Statfs_t
+	if err := syscall.Statfs(dir, &stat); err != nil {
+		return 0, false
+	}
+	return uint64(stat.Bavail) * uint64(stat.Bsize), true
+}
@@ -0,0 +1,119 @@
+package seal
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+)
+
+// DoctorFinding is one problem found by Doctor.
+type DoctorFinding struct {
+	Check   string // name of the check that found it
+	ItemID  string
+	Message string
+}
+
+// doctorCheck inspects one item and returns a message per problem found.
+type doctorCheck struct {
+	name string
+	run  func(item SealedItem, itemDir string) []string
+}
+
+// doctorChecks lists the checks run by Doctor, in output order.
+// New checks are added by registering them here.
+var doctorChecks = []doctorCheck{
+	{"permanently-locked", checkPermanentlyLocked},
+	{"unknown-authority", checkUnknownAuthority},
+	{"unsealed-integrity", checkUnsealedIntegrity},
+}
+
+// checkPermanentlyLocked reports sealed items that can never unlock.
+func checkPermanentlyLocked(item SealedItem, itemDir string) []string {
+	reason := permanentLockReason(item)
+	if reason == "" {
+		return nil
+	}
+	return []string{fmt.Sprintf("permanently locked: %s; the content cannot be recovered", reason)}
+}
+
+// checkUnknownAuthority reports sealed items whose time authority this build does not know.
+func checkUnknownAuthority(item SealedItem, itemDir string) []string {
+	if item.State != StateSealed || permanentLockReason(item) != "" || authorityForItem(item) != nil {
+		return nil
+	}
+	return []string{fmt.Sprintf("unknown time authority %q; status cannot check it for unlock", item.TimeAuthority)}
+}
+
+// checkUnsealedIntegrity re-hashes unlocked content against the hash recorded at unlock,
+// catching silent corruption or tampering of content kept as a record.
+// Content shredded by retention is skipped, and so is missing content outside the
+// store, which may be moved or deleted freely.
+func checkUnsealedIntegrity(item SealedItem, itemDir string) []string {
+	if item.State != StateUnlocked || item.UnsealedSHA256 == "" || item.UnsealedShreddedAt != nil {
+		return nil
+	}
+
+	path := UnsealedPath(item, itemDir)
+	sum, err := fileSHA256(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		if item.UnsealTo != "" {
+			return nil
+		}
+		return []string{fmt.Sprintf("unsealed content missing: %s", path)}
+	}
+	if err != nil {
+		return []string{fmt.Sprintf("cannot verify unsealed content: %v", err)}
+	}
+
+	if sum != item.UnsealedSHA256 {
+		return []string{fmt.Sprintf("unsealed content %s does not match the hash recorded at unlock (corrupted or modified)", path)}
+	}
+	return nil
+}
+
+// fileSHA256 returns the hex SHA-256 of a file, read in a stream.
+func fileSHA256(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// Doctor runs every check over the store and returns the problems found.
+// Read-only: items are never materialized or modified.
+func Doctor() ([]DoctorFinding, error) {
+	var findings []DoctorFinding
+	err := WalkSealedItems(func(item SealedItem, itemDir string) error {
+		for _, check := range doctorChecks {
+			for _, message := range check.run(item, itemDir) {
+				findings = append(findings, DoctorFinding{Check: check.name, ItemID: item.ID, Message: message})
+			}
+		}
+		return nil
+	})
+	return findings, err
+}
+
+// FormatDoctorOutput formats doctor findings for display, one per line.
+func FormatDoctorOutput(findings []DoctorFinding) string {
+	if len(findings) == 0 {
+		return "no problems found\n"
+	}
+
+	result := ""
+	for _, finding := range findings {
+		result += fmt.Sprintf("%s: %s: %s\n", finding.Check, finding.ItemID, finding.Message)
+	}
+	return result
+}
@@ -0,0 +1,169 @@
+package seal
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"seal/internal/testutil"
+	"seal/internal/timeauth"
+)
+
+func TestPermanentLockReason(t *testing.T) {
+	tests := []struct {
+		name string
+		item SealedItem
+		want string
+	}{
+		{"placeholder", SealedItem{State: StateSealed, TimeAuthority: "placeholder"}, "placeholder authority"},
+		{"no time lock", SealedItem{State: StateSealed, TimeAuthority: "drand"}, "no time-locked key"},
+		{"drand", SealedItem{State: StateSealed, TimeAuthority: "drand", DEKTlockB64: "x"}, ""},
+		{"unlocked placeholder", SealedItem{State: StateUnlocked, TimeAuthority: "placeholder"}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := permanentLockReason(tt.item); got != tt.want {
+				t.Errorf("permanentLockReason() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCreateSealedItem_RefusesPlaceholderOutsideTests(t *testing.T) {
+	_, cleanup := testutil.SetupTestEnv(t)
+	defer cleanup()
+
+	defer func(allowed bool) { placeholderItemsAllowed = allowed }(placeholderItemsAllowed)
+	placeholderItemsAllowed = false
+
+	_, err := CreateSealedItem(time.Now().UTC().Add(time.Hour), InputSourceStdin, "", []byte("data"), &timeauth.PlaceholderAuthority{})
+	if err == nil || !strings.Contains(err.Error(), "could never unlock") {
+		t.Fatalf("expected refusal, got %v", err)
+	}
+
+	items, err := ListSealedItems(ListOptions{})
+	if err != nil {
+		t.Fatalf("ListSealedItems failed: %v", err)
+	}
+	if len(items) != 0 {
+		t.Errorf("expected no items after refusal, got %d", len(items))
+	}
+}
+
+func TestFormatOutputs_FlagPlaceholderItems(t *testing.T) {
+	item := SealedItem{
+		ID:            "test-id",
+		State:         StateSealed,
+		UnlockTime:    time.Now().UTC().Add(time.Hour),
+		InputType:     "stdin",
+		TimeAuthority: "placeholder",
+		Algorithm:     "aes-256-gcm",
+	}
+
+	if out := FormatStatusOutput([]SealedItem{item}, nil); !strings.Contains(out, "permanently locked: placeholder authority\n") {
+		t.Errorf("status output missing permanent lock line:\n%s", out)
+	}
+	if out := FormatInspectOutput(item, nil, false); !strings.Contains(out, "permanently locked: placeholder authority (this item can never unlock)") {
+		t.Errorf("inspect output missing permanent lock line:\n%s", out)
+	}
+	line, err := FormatStatusNDJSON(item, nil)
+	if err != nil {
+		t.Fatalf("FormatStatusNDJSON failed: %v", err)
+	}
+	if !strings.Contains(line, `"permanently_locked":"placeholder authority"`) {
+		t.Errorf("ndjson line missing permanently_locked: %s", line)
+	}
+}
+
+func TestDoctor_FindsPermanentlyLockedItems(t *testing.T) {
+	_, cleanup := testutil.SetupTestEnv(t)
+	defer cleanup()
+
+	findings, err := Doctor()
+	if err != nil {
+		t.Fatalf("Doctor failed: %v", err)
+	}
+	if len(findings) != 0 {
+		t.Fatalf("expected no findings for empty store, got %v", findings)
+	}
+	if out := FormatDoctorOutput(findings); out != "no problems found\n" {
+		t.Errorf("unexpected output for no findings: %q", out)
+	}
+
+	id, err := CreateSealedItem(time.Now().UTC().Add(time.Hour), InputSourceStdin, "", []byte("data"), &timeauth.PlaceholderAuthority{})
+	if err != nil {
+		t.Fatalf("CreateSealedItem failed: %v", err)
+	}
+
+	findings, err = Doctor()
+	if err != nil {
+		t.Fatalf("Doctor failed: %v", err)
+	}
+	if len(findings) != 1 || findings[0].ItemID != id || findings[0].Check != "permanently-locked" {
+		t.Fatalf("expected one permanently-locked finding for %s, got %v", id, findings)
+	}
+	if out := FormatDoctorOutput(findings); !strings.HasPrefix(out, "permanently-locked: "+id+": permanently locked: placeholder authority") {
+		t.Errorf("unexpected doctor output: %q", out)
+	}
+}
+
+func TestCheckUnknownAuthority(t *testing.T) {
+	if got := checkUnknownAuthority(SealedItem{State: StateSealed, TimeAuthority: "sundial", DEKTlockB64: "x"}, ""); len(got) != 1 || !strings.Contains(got[0], `"sundial"`) {
+		t.Errorf("expected a finding for an unknown authority, got %v", got)
+	}
+	if got := checkUnknownAuthority(SealedItem{State: StateSealed, TimeAuthority: "drand", DEKTlockB64: "x"}, ""); len(got) != 0 {
+		t.Errorf("expected no finding for drand, got %v", got)
+	}
+	// Placeholder items are reported by the permanently-locked check alone
+	if got := checkUnknownAuthority(SealedItem{State: StateSealed, TimeAuthority: "placeholder"}, ""); len(got) != 0 {
+		t.Errorf("expected no finding for placeholder, got %v", got)
+	}
+}
+
+func TestCheckUnsealedIntegrity(t *testing.T) {
+	_, cleanup := testutil.SetupTestEnv(t)
+	defer cleanup()
+
+	authority := &timeauth.FakeAuthority{DefaultRound: 100, CurrentRound: 200}
+	id, err := CreateSealedItem(time.Now().UTC().Add(time.Hour), InputSourceStdin, "", []byte("record"), authority)
+	if err != nil {
+		t.Fatalf("CreateSealedItem failed: %v", err)
+	}
+	item, itemDir, _ := LoadItem(id)
+	item, err = TryMaterialize(item, itemDir, authority)
+	if err != nil || item.State != StateUnlocked {
+		t.Fatalf("materialization failed: %v (state %s)", err, item.State)
+	}
+	if item.UnsealedSHA256 != payloadChecksum([]byte("record")) {
+		t.Fatalf("unsealed hash not recorded at unlock: %q", item.UnsealedSHA256)
+	}
+
+	if got := checkUnsealedIntegrity(item, itemDir); len(got) != 0 {
+		t.Errorf("intact content should pass, got %v", got)
+	}
+
+	unsealedPath := filepath.Join(itemDir, "unsealed")
+	if err := os.WriteFile(unsealedPath, []byte("tampered"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if got := checkUnsealedIntegrity(item, itemDir); len(got) != 1 || !strings.Contains(got[0], "does not match") {
+		t.Errorf("expected a mismatch finding, got %v", got)
+	}
+
+	if err := os.Remove(unsealedPath); err != nil {
+		t.Fatal(err)
+	}
+	if got := checkUnsealedIntegrity(item, itemDir); len(got) != 1 || !strings.Contains(got[0], "missing") {
+		t.Errorf("expected a missing content finding, got %v", got)
+	}
+
+	// Content shredded by retention is gone on purpose
+	shredded := time.Now().UTC()
+	item.UnsealedShreddedAt = &shredded
+	if got := checkUnsealedIntegrity(item, itemDir); len(got) != 0 {
+		t.Errorf("shredded content should be skipped, got %v", got)
+	}
+}
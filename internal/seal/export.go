@@ -0,0 +1,144 @@
+package seal
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"seal/internal/timeauth"
+)
+
+// PublicCommitmentVersion is the layout of the public commitment written by this build.
+const PublicCommitmentVersion = 1
+
+// PublicCommitment is the part of an item that can be shared without revealing anything:
+// enough for a third party to check that a ciphertext exists and when it opens.
+type PublicCommitment struct {
+	Version          int       `json:"version"`
+	ID               string    `json:"id"`
+	CiphertextSHA256 string    `json:"ciphertext_sha256"` // hex SHA-256 of payload.bin
+	TimeAuthority    string    `json:"time_authority"`
+	UnlockRound      uint64    `json:"unlock_round"`
+	ChainHash        string    `json:"chain_hash"` // drand chain the key is time-locked to
+	CreatedAt        time.Time `json:"created_at"`
+}
+
+// ExportPublic builds the public commitment for an item.
+// The unlock round and chain hash are read from the time-locked key itself,
+// so the commitment states what the ciphertext can actually be opened with.
+// Read-only: the item is never materialized or modified.
+func ExportPublic(id string) (PublicCommitment, error) {
+	item, itemDir, err := LoadItem(id)
+	if err != nil {
+		return PublicCommitment{}, err
+	}
+
+	if !hasTimeLock(item) {
+		return PublicCommitment{}, fmt.Errorf("item %s: no time-locked key, nothing to commit to", item.ID)
+	}
+	if item.TimeAuthority == timeauth.MultiAuthorityName {
+		return PublicCommitment{}, fmt.Errorf("item %s: a threshold item has no single time-locked key to commit to", item.ID)
+	}
+
+	// A tlock file payload is its own time-locked key; any other payload is only
+	// hashed as it is read, since a streamed one can be arbitrarily large
+	payloadPath := filepath.Join(itemDir, "payload.bin")
+	var checksum string
+	var round uint64
+	var chainHash string
+	if isTlockFile(item) {
+		ciphertext, err := os.ReadFile(payloadPath)
+		if err != nil {
+			return PublicCommitment{}, fmt.Errorf("failed to read payload: %w", err)
+		}
+		checksum = payloadChecksum(ciphertext)
+		round, chainHash, err = tlockHeader(ciphertext)
+	} else {
+		checksum, err = fileSHA256(payloadPath)
+		if err != nil {
+			return PublicCommitment{}, fmt.Errorf("failed to read payload: %w", err)
+		}
+		round, chainHash, err = tlockStanza(item.DEKTlockB64)
+	}
+	if err != nil {
+		return PublicCommitment{}, fmt.Errorf("item %s: %w", item.ID, err)
+	}
+
+	targetRound, err := extractTargetRound(item.KeyRef)
+	if err != nil {
+		return PublicCommitment{}, fmt.Errorf("item %s: %w", item.ID, err)
+	}
+	if round != targetRound {
+		return PublicCommitment{}, fmt.Errorf("item %s: time-locked key targets round %d but metadata records %d", item.ID, round, targetRound)
+	}
+
+	if item.PayloadSHA256 != "" && checksum != item.PayloadSHA256 {
+		return PublicCommitment{}, fmt.Errorf("item %s: payload checksum mismatch (corrupted)", item.ID)
+	}
+
+	return PublicCommitment{
+		Version:          PublicCommitmentVersion,
+		ID:               item.ID,
+		CiphertextSHA256: checksum,
+		TimeAuthority:    item.TimeAuthority,
+		UnlockRound:      round,
+		ChainHash:        chainHash,
+		CreatedAt:        item.CreatedAt,
+	}, nil
+}
+
+// FormatPublicCommitmentJSON formats a public commitment as indented JSON.
+func FormatPublicCommitmentJSON(commitment PublicCommitment) (string, error) {
+	data, err := json.MarshalIndent(commitment, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("cannot marshal commitment: %w", err)
+	}
+	return string(data) + "\n", nil
+}
+
+// tlockStanza reads the round and chain hash from the tlock recipient
+// stanza ("-> tlock <round> <chain hash>") in a time-locked key's age header.
+func tlockStanza(dekTlockB64 string) (uint64, string, error) {
+	data, err := base64.StdEncoding.DecodeString(dekTlockB64)
+	if err != nil {
+		return 0, "", fmt.Errorf("time-locked key is not a tlock ciphertext")
+	}
+
+	return tlockHeader(data)
+}
+
+// tlockHeader reads the round and chain hash from the tlock recipient stanza
+// in the age header of a binary tlock ciphertext.
+func tlockHeader(data []byte) (uint64, string, error) {
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	if !scanner.Scan() || scanner.Text() != "age-encryption.org/v1" {
+		return 0, "", fmt.Errorf("not a tlock ciphertext")
+	}
+
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.HasPrefix(line, "---") {
+			break
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) != 4 || fields[0] != "->" || fields[1] != "tlock" {
+			continue
+		}
+
+		round, err := strconv.ParseUint(fields[2], 10, 64)
+		if err != nil {
+			return 0, "", fmt.Errorf("invalid round in tlock stanza: %s", fields[2])
+		}
+		return round, fields[3], nil
+	}
+
+	return 0, "", fmt.Errorf("no tlock stanza in age header")
+}
@@ -0,0 +1,155 @@
+package seal
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"seal/internal/testutil"
+	"seal/internal/timeauth"
+	"seal/internal/timeauth/drandsim"
+)
+
+// createTlockItem creates an item whose key is time-locked with real tlock
+// against a local drand simulator. Returns the item, its directory and the chain hash.
+func createTlockItem(t *testing.T, targetRound uint64) (SealedItem, string, string) {
+	t.Helper()
+
+	authority := &timeauth.FakeAuthority{DefaultRound: targetRound}
+	id, err := CreateSealedItem(time.Now().UTC().Add(time.Hour), InputSourceStdin, "", []byte("secret"), authority)
+	if err != nil {
+		t.Fatalf("CreateSealedItem failed: %v", err)
+	}
+
+	server, err := drandsim.Start(drandsim.Config{Period: 3 * time.Second, Genesis: 1677685200})
+	if err != nil {
+		t.Fatalf("failed to start drand simulator: %v", err)
+	}
+	defer server.Close()
+
+	box := &timeauth.RealTimelockBox{BaseURL: server.URL, ChainHash: server.ChainHash}
+	dekTlock, err := box.Encrypt([]byte("0123456789abcdef0123456789abcdef"), targetRound)
+	if err != nil {
+		t.Fatalf("tlock encrypt failed: %v", err)
+	}
+
+	item, itemDir, err := LoadItem(id)
+	if err != nil {
+		t.Fatalf("LoadItem failed: %v", err)
+	}
+	item.DEKTlockB64 = dekTlock
+	if err := saveMetadata(itemDir, item); err != nil {
+		t.Fatalf("saveMetadata failed: %v", err)
+	}
+
+	return item, itemDir, server.ChainHash
+}
+
+func TestExportPublic_ReadsCommitmentFromKey(t *testing.T) {
+	_, cleanup := testutil.SetupTestEnv(t)
+	defer cleanup()
+
+	item, _, chainHash := createTlockItem(t, 12345)
+
+	commitment, err := ExportPublic(item.ID)
+	if err != nil {
+		t.Fatalf("ExportPublic failed: %v", err)
+	}
+
+	if commitment.ID != item.ID {
+		t.Errorf("expected id %s, got %s", item.ID, commitment.ID)
+	}
+	if commitment.UnlockRound != 12345 {
+		t.Errorf("expected unlock round 12345, got %d", commitment.UnlockRound)
+	}
+	if commitment.ChainHash != chainHash {
+		t.Errorf("expected chain hash %s, got %s", chainHash, commitment.ChainHash)
+	}
+	if commitment.CiphertextSHA256 != item.PayloadSHA256 {
+		t.Errorf("expected ciphertext hash %s, got %s", item.PayloadSHA256, commitment.CiphertextSHA256)
+	}
+	if !commitment.CreatedAt.Equal(item.CreatedAt) {
+		t.Errorf("expected created_at %v, got %v", item.CreatedAt, commitment.CreatedAt)
+	}
+}
+
+func TestExportPublic_OmitsSecrets(t *testing.T) {
+	_, cleanup := testutil.SetupTestEnv(t)
+	defer cleanup()
+
+	item, _, _ := createTlockItem(t, 100)
+
+	commitment, err := ExportPublic(item.ID)
+	if err != nil {
+		t.Fatalf("ExportPublic failed: %v", err)
+	}
+
+	output, err := FormatPublicCommitmentJSON(commitment)
+	if err != nil {
+		t.Fatalf("FormatPublicCommitmentJSON failed: %v", err)
+	}
+
+	var fields map[string]any
+	if err := json.Unmarshal([]byte(output), &fields); err != nil {
+		t.Fatalf("output is not valid JSON: %v", err)
+	}
+
+	for _, key := range []string{"dek_tlock_b64", "nonce", "original_path", "recipient"} {
+		if _, ok := fields[key]; ok {
+			t.Errorf("public commitment must not contain %s", key)
+		}
+	}
+	if strings.Contains(output, item.DEKTlockB64) || strings.Contains(output, item.Nonce) {
+		t.Error("public commitment must not contain key material")
+	}
+}
+
+func TestExportPublic_RoundMismatch(t *testing.T) {
+	_, cleanup := testutil.SetupTestEnv(t)
+	defer cleanup()
+
+	item, itemDir, _ := createTlockItem(t, 100)
+	item.KeyRef = `{"network":"quicknet","target_round":200}`
+	if err := saveMetadata(itemDir, item); err != nil {
+		t.Fatalf("saveMetadata failed: %v", err)
+	}
+
+	_, err := ExportPublic(item.ID)
+	if err == nil || !strings.Contains(err.Error(), "targets round 100") {
+		t.Errorf("expected round mismatch error, got: %v", err)
+	}
+}
+
+func TestExportPublic_CorruptedPayload(t *testing.T) {
+	_, cleanup := testutil.SetupTestEnv(t)
+	defer cleanup()
+
+	item, itemDir, _ := createTlockItem(t, 100)
+	if err := os.WriteFile(filepath.Join(itemDir, "payload.bin"), []byte("tampered"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := ExportPublic(item.ID)
+	if err == nil || !strings.Contains(err.Error(), "checksum mismatch") {
+		t.Errorf("expected checksum mismatch error, got: %v", err)
+	}
+}
+
+func TestExportPublic_NotTlockKey(t *testing.T) {
+	_, cleanup := testutil.SetupTestEnv(t)
+	defer cleanup()
+
+	authority := &timeauth.FakeAuthority{DefaultRound: 100}
+	id, err := CreateSealedItem(time.Now().UTC().Add(time.Hour), InputSourceStdin, "", []byte("secret"), authority)
+	if err != nil {
+		t.Fatalf("CreateSealedItem failed: %v", err)
+	}
+
+	_, err = ExportPublic(id)
+	if err == nil || !strings.Contains(err.Error(), "not a tlock ciphertext") {
+		t.Errorf("expected non-tlock key error, got: %v", err)
+	}
+}
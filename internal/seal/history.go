@@ -0,0 +1,68 @@
+package seal
+
+import (
+	"slices"
+	"time"
+
+	"seal/internal/clock"
+)
+
+// MaxHistoryEntries caps the per-item history stored in metadata.
+// Oldest entries are dropped first, except first_check, which is kept.
+const MaxHistoryEntries = 32
+
+// History events recorded in item metadata.
+const (
+	HistoryCreated          = "created"
+	HistoryFirstCheck       = "first_check"
+	HistoryUnlocked         = "unlocked"
+	HistoryValidationFailed = "validation_failed"
+	HistoryUnsealedShredded = "unsealed_shredded"
+	HistoryImported         = "imported"
+	HistoryRelabeled        = "relabeled"
+	HistoryAttested         = "attested"
+)
+
+// HistoryEntry is a single timestamped event in an item's history.
+type HistoryEntry struct {
+	Time   time.Time `json:"time"`
+	Event  string    `json:"event"`
+	Detail string    `json:"detail,omitempty"`
+}
+
+// appendHistory records an event on the item, enforcing MaxHistoryEntries.
+// The first check is recorded only while it is missing, so dropping it would
+// record a new one on every later check.
+func appendHistory(item *SealedItem, event, detail string) {
+	item.History = append(item.History, HistoryEntry{
+		Time:   clock.UTC(),
+		Event:  event,
+		Detail: detail,
+	})
+
+	for len(item.History) > MaxHistoryEntries {
+		oldest := slices.IndexFunc(item.History, func(entry HistoryEntry) bool { return entry.Event != HistoryFirstCheck })
+		if oldest < 0 {
+			break
+		}
+		item.History = slices.Delete(item.History, oldest, oldest+1)
+	}
+}
+
+// hasHistoryEvent reports whether the item's history contains an event.
+func hasHistoryEvent(item SealedItem, event string) bool {
+	for _, entry := range item.History {
+		if entry.Event == event {
+			return true
+		}
+	}
+	return false
+}
+
+// lastHistoryEntry returns the most recent history entry, if any.
+func lastHistoryEntry(item SealedItem) (HistoryEntry, bool) {
+	if len(item.History) == 0 {
+		return HistoryEntry{}, false
+	}
+	return item.History[len(item.History)-1], true
+}
@@ -0,0 +1,224 @@
+package seal
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"seal/internal/testutil"
+	"seal/internal/timeauth"
+)
+
+func TestAppendHistory_Capped(t *testing.T) {
+	var item SealedItem
+	for i := 0; i < MaxHistoryEntries+5; i++ {
+		appendHistory(&item, "event", fmt.Sprintf("%d", i))
+	}
+
+	if len(item.History) != MaxHistoryEntries {
+		t.Fatalf("expected %d entries, got %d", MaxHistoryEntries, len(item.History))
+	}
+
+	// Oldest entries are dropped first
+	if item.History[0].Detail != "5" {
+		t.Errorf("expected oldest retained entry to be 5, got %s", item.History[0].Detail)
+	}
+	if item.History[len(item.History)-1].Detail != fmt.Sprintf("%d", MaxHistoryEntries+4) {
+		t.Errorf("unexpected newest entry: %+v", item.History[len(item.History)-1])
+	}
+}
+
+func TestAppendHistory_KeepsFirstCheck(t *testing.T) {
+	var item SealedItem
+	appendHistory(&item, HistoryCreated, "")
+	appendHistory(&item, HistoryFirstCheck, "")
+	for i := 0; i < MaxHistoryEntries; i++ {
+		appendHistory(&item, HistoryRelabeled, fmt.Sprintf("%d", i))
+	}
+
+	if len(item.History) != MaxHistoryEntries {
+		t.Fatalf("expected %d entries, got %d", MaxHistoryEntries, len(item.History))
+	}
+	if item.History[0].Event != HistoryFirstCheck || !hasHistoryEvent(item, HistoryFirstCheck) {
+		t.Errorf("expected first_check to be kept as the oldest entry, got %+v", item.History[0])
+	}
+	if item.History[1].Detail != "1" {
+		t.Errorf("expected the oldest other entries to be dropped, got %+v", item.History[1])
+	}
+}
+
+func TestCreateSealedItem_RecordsCreatedHistory(t *testing.T) {
+	_, cleanup := testutil.SetupTestEnv(t)
+	defer cleanup()
+
+	id, err := CreateSealedItem(time.Now().UTC().Add(time.Hour), InputSourceStdin, "", []byte("data"), &timeauth.PlaceholderAuthority{})
+	if err != nil {
+		t.Fatalf("CreateSealedItem failed: %v", err)
+	}
+
+	item, _, err := LoadItem(id)
+	if err != nil {
+		t.Fatalf("LoadItem failed: %v", err)
+	}
+
+	if len(item.History) != 1 || item.History[0].Event != HistoryCreated {
+		t.Errorf("expected created history entry, got %+v", item.History)
+	}
+}
+
+func TestGetStatus_RecordsFirstCheckOnce(t *testing.T) {
+	_, cleanup := testutil.SetupTestEnv(t)
+	defer cleanup()
+
+	id, err := CreateSealedItem(time.Now().UTC().Add(time.Hour), InputSourceStdin, "", []byte("data"), &timeauth.PlaceholderAuthority{})
+	if err != nil {
+		t.Fatalf("CreateSealedItem failed: %v", err)
+	}
+
+	for i := 0; i < 3; i++ {
+		if _, err := GetStatus(); err != nil {
+			t.Fatalf("GetStatus failed: %v", err)
+		}
+	}
+
+	item, _, err := LoadItem(id)
+	if err != nil {
+		t.Fatalf("LoadItem failed: %v", err)
+	}
+
+	count := 0
+	for _, entry := range item.History {
+		if entry.Event == HistoryFirstCheck {
+			count++
+		}
+	}
+	if count != 1 {
+		t.Errorf("expected exactly one first_check entry, got %d: %+v", count, item.History)
+	}
+}
+
+func TestGetStatus_RecordsValidationFailure(t *testing.T) {
+	_, cleanup := testutil.SetupTestEnv(t)
+	defer cleanup()
+
+	id, err := CreateSealedItem(time.Now().UTC().Add(time.Hour), InputSourceStdin, "", []byte("data"), &timeauth.PlaceholderAuthority{})
+	if err != nil {
+		t.Fatalf("CreateSealedItem failed: %v", err)
+	}
+
+	// Corrupt the item: sealed with an unsealed file present
+	_, itemDir, _ := LoadItem(id)
+	if err := os.WriteFile(filepath.Join(itemDir, "unsealed"), []byte("x"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	// Repeated identical failures are recorded once
+	GetStatus()
+	GetStatus()
+
+	item, _, err := LoadItem(id)
+	if err != nil {
+		t.Fatalf("LoadItem failed: %v", err)
+	}
+
+	count := 0
+	for _, entry := range item.History {
+		if entry.Event == HistoryValidationFailed {
+			count++
+			if !strings.Contains(entry.Detail, "corrupted") {
+				t.Errorf("unexpected detail: %s", entry.Detail)
+			}
+		}
+	}
+	if count != 1 {
+		t.Errorf("expected one validation_failed entry, got %d: %+v", count, item.History)
+	}
+}
+
+func TestTryMaterialize_RecordsUnlockedHistory(t *testing.T) {
+	_, cleanup := testutil.SetupTestEnv(t)
+	defer cleanup()
+
+	authority := &timeauth.FakeAuthority{DefaultRound: 100, CurrentRound: 200}
+	id, err := CreateSealedItem(time.Now().UTC().Add(time.Hour), InputSourceStdin, "", []byte("data"), authority)
+	if err != nil {
+		t.Fatalf("CreateSealedItem failed: %v", err)
+	}
+
+	item, itemDir, _ := LoadItem(id)
+	updated, err := TryMaterialize(item, itemDir, authority)
+	if err != nil {
+		t.Fatalf("TryMaterialize failed: %v", err)
+	}
+
+	last, ok := lastHistoryEntry(updated)
+	if !ok || last.Event != HistoryUnlocked || last.Detail != "round 100" {
+		t.Errorf("expected unlocked history entry, got %+v", updated.History)
+	}
+}
+
+func TestTryMaterialize_RecordsUnlockedAtAndRound(t *testing.T) {
+	_, cleanup := testutil.SetupTestEnv(t)
+	defer cleanup()
+
+	authority := &timeauth.FakeAuthority{DefaultRound: 100, CurrentRound: 200}
+	id, err := CreateSealedItem(time.Now().UTC().Add(time.Hour), InputSourceStdin, "", []byte("data"), authority)
+	if err != nil {
+		t.Fatalf("CreateSealedItem failed: %v", err)
+	}
+
+	item, itemDir, _ := LoadItem(id)
+	if item.UnlockedAt != nil || item.UnlockRound != 0 {
+		t.Fatalf("sealed item should have no unlock record, got %+v", item)
+	}
+
+	before := time.Now().UTC()
+	if _, err := TryMaterialize(item, itemDir, authority); err != nil {
+		t.Fatalf("TryMaterialize failed: %v", err)
+	}
+
+	persisted, _, err := LoadItem(id)
+	if err != nil {
+		t.Fatalf("LoadItem failed: %v", err)
+	}
+
+	if persisted.UnlockedAt == nil || persisted.UnlockedAt.Before(before.Add(-time.Second)) {
+		t.Errorf("unlocked_at should be recorded, got %v", persisted.UnlockedAt)
+	}
+	if persisted.UnlockRound != 100 {
+		t.Errorf("unlock_round should be the target round 100, got %d", persisted.UnlockRound)
+	}
+
+	output := FormatInspectOutput(persisted, nil, false)
+	if !strings.Contains(output, "unlock_round: 100") || !strings.Contains(output, "unlocked_at: ") {
+		t.Errorf("inspect should show unlock record, got: %s", output)
+	}
+
+	jsonOutput, err := FormatInspectJSON(persisted)
+	if err != nil {
+		t.Fatalf("FormatInspectJSON failed: %v", err)
+	}
+	if !strings.Contains(jsonOutput, `"unlock_round": 100`) || !strings.Contains(jsonOutput, `"unlocked_at": "`) {
+		t.Errorf("JSON should include unlock record, got: %s", jsonOutput)
+	}
+}
+
+func TestFormatInspectOutput_History(t *testing.T) {
+	item := SealedItem{ID: "test-id", State: StateSealed, TimeAuthority: "drand", Algorithm: "aes-256-gcm"}
+	appendHistory(&item, HistoryCreated, "")
+	appendHistory(&item, HistoryValidationFailed, errors.New("bad").Error())
+
+	without := FormatInspectOutput(item, nil, false)
+	if strings.Contains(without, "history:") {
+		t.Errorf("history should be hidden by default, got: %s", without)
+	}
+
+	with := FormatInspectOutput(item, nil, true)
+	if !strings.Contains(with, "history:") || !strings.Contains(with, " created\n") || !strings.Contains(with, " validation_failed: bad\n") {
+		t.Errorf("expected history lines, got: %s", with)
+	}
+}
@@ -0,0 +1,114 @@
+package seal
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// UnlockHookEvent is the JSON body posted to an item's unlock webhook.
+// Like notifications, it never contains unlocked content.
+type UnlockHookEvent struct {
+	Event        string    `json:"event"` // always NotifyEventUnlocked
+	ID           string    `json:"id"`
+	UnlockTime   time.Time `json:"unlock_time"`
+	UnsealedPath string    `json:"unsealed_path"`
+}
+
+// ResolveUnlockExec validates an unlock hook program and returns it as an absolute path.
+// Hooks run from status or the daemon, whose working directory is not the one the item was locked in.
+func ResolveUnlockExec(program string) (string, error) {
+	abs, err := filepath.Abs(program)
+	if err != nil {
+		return "", fmt.Errorf("cannot resolve unlock hook: %w", err)
+	}
+
+	info, err := os.Stat(abs)
+	if err != nil {
+		return "", fmt.Errorf("cannot use unlock hook: %w", err)
+	}
+	if !info.Mode().IsRegular() {
+		return "", fmt.Errorf("unlock hook is not a regular file: %s", abs)
+	}
+
+	return abs, nil
+}
+
+// ValidateUnlockWebhook checks an unlock webhook URL.
+func ValidateUnlockWebhook(rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+		return fmt.Errorf("invalid unlock webhook %q: expected an http or https URL", rawURL)
+	}
+	return nil
+}
+
+// runItemUnlockHooks runs the unlock hooks declared on a newly unlocked item.
+// Hooks are best-effort and run once; returns one warning per failed hook.
+func runItemUnlockHooks(item SealedItem, itemDir string) []string {
+	var warnings []string
+
+	if item.OnUnlockExec != "" {
+		if err := runUnlockHook(item.OnUnlockExec, item, itemDir); err != nil {
+			warnings = append(warnings, fmt.Sprintf("warning: unlock hook failed for item %s: %v", item.ID, err))
+		}
+	}
+
+	if item.OnUnlockWebhook != "" {
+		event := UnlockHookEvent{
+			Event:        NotifyEventUnlocked,
+			ID:           item.ID,
+			UnlockTime:   item.UnlockTime,
+			UnsealedPath: UnsealedPath(item, itemDir),
+		}
+		if err := postJSON(http.MethodPost, item.OnUnlockWebhook, event, nil); err != nil {
+			warnings = append(warnings, fmt.Sprintf("warning: unlock webhook failed for item %s: %v", item.ID, err))
+		}
+	}
+
+	return warnings
+}
+
+// unlockHookTimeout bounds how long an unlock hook may run.
+const unlockHookTimeout = 5 * time.Minute
+
+// runUnlockHook runs program for an unlocked item, without a shell and with no arguments.
+// The item is described in the environment: SEAL_ITEM_ID, SEAL_UNSEALED_PATH and
+// SEAL_UNLOCK_TIME. The content itself is never passed; the program reads it from the path.
+func runUnlockHook(program string, item SealedItem, itemDir string) error {
+	if program == "" {
+		return errors.New("no hook program")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), unlockHookTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, program)
+	cmd.Env = append(os.Environ(),
+		"SEAL_ITEM_ID="+item.ID,
+		"SEAL_UNSEALED_PATH="+UnsealedPath(item, itemDir),
+		"SEAL_UNLOCK_TIME="+item.UnlockTime.UTC().Format(time.RFC3339),
+	)
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() != nil {
+			return fmt.Errorf("%s: timed out after %s", program, unlockHookTimeout)
+		}
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return fmt.Errorf("%s: %s", program, msg)
+		}
+		return fmt.Errorf("%s: %w", program, err)
+	}
+	return nil
+}
@@ -0,0 +1,129 @@
+package seal
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"seal/internal/testutil"
+	"seal/internal/timeauth"
+)
+
+func TestStatusRun_RunsItemUnlockHooksOnce(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("hook is a shell script")
+	}
+	_, cleanup := testutil.SetupTestEnv(t)
+	defer cleanup()
+
+	server, captured := newCaptureServer(t, http.StatusOK)
+
+	hookDir := t.TempDir()
+	hookOut := filepath.Join(hookDir, "out")
+	hook := filepath.Join(hookDir, "hook.sh")
+	if err := os.WriteFile(hook, []byte("#!/bin/sh\necho \"$SEAL_ITEM_ID $SEAL_UNSEALED_PATH\" >> "+hookOut+"\n"), 0700); err != nil {
+		t.Fatal(err)
+	}
+
+	authority := &timeauth.FakeAuthority{DefaultRound: 100, CurrentRound: 200}
+	id, err := CreateSealedItemWithOptions(time.Now().UTC().Add(time.Hour), InputSourceStdin, "", []byte("prediction"), authority,
+		ItemOptions{OnUnlockExec: hook, OnUnlockWebhook: server.URL + "/unlocked"})
+	if err != nil {
+		t.Fatalf("CreateSealedItemWithOptions failed: %v", err)
+	}
+
+	for i := 0; i < 2; i++ {
+		run := newStatusRun()
+		run.authorityFor = func(SealedItem) timeauth.Authority { return authority }
+		item, itemDir, _ := LoadItem(id)
+		run.check(item, itemDir)
+		if warnings := run.result().Warnings; len(warnings) != 0 {
+			t.Fatalf("unexpected warnings: %v", warnings)
+		}
+	}
+
+	item, itemDir, _ := LoadItem(id)
+	got, err := os.ReadFile(hookOut)
+	if err != nil {
+		t.Fatalf("hook did not run: %v", err)
+	}
+	if want := id + " " + UnsealedPath(item, itemDir) + "\n"; string(got) != want {
+		t.Errorf("hook output = %q, want %q", got, want)
+	}
+
+	if len(*captured) != 1 {
+		t.Fatalf("expected one webhook request, got %d", len(*captured))
+	}
+	body := (*captured)[0].body
+	if body["event"] != NotifyEventUnlocked || body["id"] != id || body["unsealed_path"] != UnsealedPath(item, itemDir) {
+		t.Errorf("unexpected webhook body: %v", body)
+	}
+}
+
+func TestRunItemUnlockHooks_FailuresAreWarnings(t *testing.T) {
+	server, _ := newCaptureServer(t, http.StatusInternalServerError)
+	item := SealedItem{
+		ID:              "test-id",
+		State:           StateUnlocked,
+		OnUnlockExec:    filepath.Join(t.TempDir(), "missing"),
+		OnUnlockWebhook: server.URL,
+	}
+
+	warnings := runItemUnlockHooks(item, t.TempDir())
+	if len(warnings) != 2 || !strings.Contains(warnings[0], "unlock hook failed") || !strings.Contains(warnings[1], "unlock webhook failed") {
+		t.Errorf("expected a warning per failed hook, got %v", warnings)
+	}
+}
+
+func TestLock_ValidatesUnlockHooks(t *testing.T) {
+	_, cleanup := testutil.SetupTestEnv(t)
+	defer cleanup()
+	registerFakeAuthority()
+
+	hook := filepath.Join(t.TempDir(), "hook.sh")
+	if err := os.WriteFile(hook, []byte("#!/bin/sh\n"), 0700); err != nil {
+		t.Fatal(err)
+	}
+	input := filepath.Join(t.TempDir(), "input.txt")
+	if err := os.WriteFile(input, []byte("prediction"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		name    string
+		exec    string
+		webhook string
+		wantErr string
+	}{
+		{"missing program", filepath.Join(t.TempDir(), "missing"), "", "cannot use unlock hook"},
+		{"directory", t.TempDir(), "", "not a regular file"},
+		{"not http", "", "ftp://example.com/hook", "expected an http or https URL"},
+		{"no host", "", "https:///hook", "expected an http or https URL"},
+	}
+	for _, tc := range testCases {
+		_, err := Lock(LockRequest{InputPath: input, For: "1h", Authority: "registered-fake", OnUnlockExec: tc.exec, OnUnlockWebhook: tc.webhook})
+		if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+			t.Errorf("%s: expected error containing %q, got %v", tc.name, tc.wantErr, err)
+		}
+	}
+
+	// A relative program is stored as an absolute path
+	wd, _ := os.Getwd()
+	defer os.Chdir(wd)
+	os.Chdir(filepath.Dir(hook))
+	result, err := Lock(LockRequest{InputPath: input, For: "1h", Authority: "registered-fake", OnUnlockExec: "hook.sh", OnUnlockWebhook: "https://example.com/hook"})
+	if err != nil {
+		t.Fatalf("Lock failed: %v", err)
+	}
+	item, _, _ := LoadItem(result.ID)
+	if !filepath.IsAbs(item.OnUnlockExec) || filepath.Base(item.OnUnlockExec) != "hook.sh" {
+		t.Errorf("unexpected on_unlock_exec %q", item.OnUnlockExec)
+	}
+	if item.OnUnlockWebhook != "https://example.com/hook" {
+		t.Errorf("unexpected on_unlock_webhook %q", item.OnUnlockWebhook)
+	}
+}
@@ -0,0 +1,208 @@
+package seal
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"filippo.io/age"
+
+	"seal/internal/clock"
+)
+
+// identitiesDirName is the directory in the base directory holding recipient identities.
+const identitiesDirName = "identities"
+
+// identityFileExt is the extension of identity files (age-keygen format).
+const identityFileExt = ".age"
+
+// DefaultIdentityName is used by seal keygen when no name is given.
+const DefaultIdentityName = "default"
+
+var namePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9._-]*$`)
+
+// Identity is an X25519 age identity stored in the identities directory.
+type Identity struct {
+	Name      string
+	Recipient string // public key (age1...), safe to share with senders
+	Path      string // identity file, usable with age -i and age-decrypt:<path>
+}
+
+// GenerateIdentity creates a new X25519 age identity under the given name.
+// Existing identities are never overwritten: losing a private key makes
+// content sealed to it unreadable forever.
+func GenerateIdentity(name string) (Identity, error) {
+	if err := validateIdentityName(name); err != nil {
+		return Identity{}, err
+	}
+
+	dir, err := identitiesDir()
+	if err != nil {
+		return Identity{}, err
+	}
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return Identity{}, fmt.Errorf("cannot create identities directory: %w", err)
+	}
+	if err := checkItemDir(dir); err != nil {
+		return Identity{}, err
+	}
+
+	path := filepath.Join(dir, name+identityFileExt)
+	if _, err := os.Lstat(path); err == nil {
+		return Identity{}, fmt.Errorf("identity %s already exists", name)
+	}
+
+	key, err := age.GenerateX25519Identity()
+	if err != nil {
+		return Identity{}, fmt.Errorf("failed to generate identity: %w", err)
+	}
+
+	recipient := key.Recipient().String()
+	content := fmt.Sprintf("# created: %s\n# public key: %s\n%s\n",
+		clock.UTC().Format(time.RFC3339), recipient, key.String())
+
+	if err := writeFileNoFollow(path, []byte(content), 0600); err != nil {
+		return Identity{}, fmt.Errorf("cannot write identity: %w", err)
+	}
+
+	return Identity{Name: name, Recipient: recipient, Path: path}, nil
+}
+
+// ListIdentities returns all stored identities, sorted by name.
+// Files that do not hold a valid identity are skipped.
+func ListIdentities() ([]Identity, error) {
+	dir, err := identitiesDir()
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return []Identity{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("cannot read identities directory: %w", err)
+	}
+
+	identities := []Identity{}
+	for _, entry := range entries {
+		name, ok := strings.CutSuffix(entry.Name(), identityFileExt)
+		if !ok || !entry.Type().IsRegular() {
+			continue
+		}
+
+		identity, err := LoadIdentity(name)
+		if err != nil {
+			continue
+		}
+		identities = append(identities, identity)
+	}
+
+	sort.Slice(identities, func(i, j int) bool {
+		return identities[i].Name < identities[j].Name
+	})
+
+	return identities, nil
+}
+
+// LoadIdentity loads a stored identity by name.
+func LoadIdentity(name string) (Identity, error) {
+	data, path, err := readIdentityFile(name)
+	if err != nil {
+		return Identity{}, err
+	}
+
+	key, err := parseIdentityFile(data)
+	if err != nil {
+		return Identity{}, fmt.Errorf("identity %s: %w", name, err)
+	}
+
+	return Identity{Name: name, Recipient: key.Recipient().String(), Path: path}, nil
+}
+
+// ExportIdentitySecret returns the full identity file, including the private key.
+func ExportIdentitySecret(name string) ([]byte, error) {
+	data, _, err := readIdentityFile(name)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := parseIdentityFile(data); err != nil {
+		return nil, fmt.Errorf("identity %s: %w", name, err)
+	}
+
+	return data, nil
+}
+
+// FormatIdentityList formats identities for display, one per line.
+func FormatIdentityList(identities []Identity) string {
+	if len(identities) == 0 {
+		return "no identities\n"
+	}
+
+	var result string
+	for _, identity := range identities {
+		result += fmt.Sprintf("%s  %s\n", identity.Name, identity.Recipient)
+	}
+	return result
+}
+
+func identitiesDir() (string, error) {
+	baseDir, err := GetSealBaseDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(baseDir, identitiesDirName), nil
+}
+
+func validateIdentityName(name string) error {
+	if !namePattern.MatchString(name) {
+		return fmt.Errorf("invalid identity name %q: use lowercase letters, digits, '.', '_' and '-'", name)
+	}
+	return nil
+}
+
+func readIdentityFile(name string) ([]byte, string, error) {
+	if err := validateIdentityName(name); err != nil {
+		return nil, "", err
+	}
+
+	dir, err := identitiesDir()
+	if err != nil {
+		return nil, "", err
+	}
+
+	path := filepath.Join(dir, name+identityFileExt)
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, "", fmt.Errorf("identity %s not found", name)
+	}
+	if err != nil {
+		return nil, "", fmt.Errorf("cannot read identity %s: %w", name, err)
+	}
+
+	return data, path, nil
+}
+
+// parseIdentityFile parses an age-keygen style file holding one X25519 identity.
+func parseIdentityFile(data []byte) (*age.X25519Identity, error) {
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		key, err := age.ParseX25519Identity(line)
+		if err != nil {
+			return nil, fmt.Errorf("invalid identity file: %w", err)
+		}
+		return key, nil
+	}
+	return nil, fmt.Errorf("invalid identity file: no identity found")
+}
@@ -0,0 +1,122 @@
+package seal
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"filippo.io/age"
+
+	"seal/internal/testutil"
+)
+
+func TestGenerateIdentity_ListAndExport(t *testing.T) {
+	_, cleanup := testutil.SetupTestEnv(t)
+	defer cleanup()
+
+	identity, err := GenerateIdentity("alice")
+	if err != nil {
+		t.Fatalf("GenerateIdentity failed: %v", err)
+	}
+	if !strings.HasPrefix(identity.Recipient, "age1") {
+		t.Errorf("expected age recipient, got %q", identity.Recipient)
+	}
+
+	info, err := os.Stat(identity.Path)
+	if err != nil {
+		t.Fatalf("identity file missing: %v", err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("identity file should be private, got %v", info.Mode().Perm())
+	}
+
+	identities, err := ListIdentities()
+	if err != nil {
+		t.Fatalf("ListIdentities failed: %v", err)
+	}
+	if len(identities) != 1 || identities[0].Name != "alice" || identities[0].Recipient != identity.Recipient {
+		t.Errorf("unexpected identities: %+v", identities)
+	}
+
+	secret, err := ExportIdentitySecret("alice")
+	if err != nil {
+		t.Fatalf("ExportIdentitySecret failed: %v", err)
+	}
+	if !bytes.Contains(secret, []byte("AGE-SECRET-KEY-1")) {
+		t.Errorf("exported identity lacks the private key: %s", secret)
+	}
+}
+
+func TestGenerateIdentity_NeverOverwrites(t *testing.T) {
+	_, cleanup := testutil.SetupTestEnv(t)
+	defer cleanup()
+
+	first, err := GenerateIdentity(DefaultIdentityName)
+	if err != nil {
+		t.Fatalf("GenerateIdentity failed: %v", err)
+	}
+
+	if _, err := GenerateIdentity(DefaultIdentityName); err == nil || !strings.Contains(err.Error(), "already exists") {
+		t.Fatalf("expected already exists error, got: %v", err)
+	}
+
+	loaded, err := LoadIdentity(DefaultIdentityName)
+	if err != nil {
+		t.Fatalf("LoadIdentity failed: %v", err)
+	}
+	if loaded.Recipient != first.Recipient {
+		t.Error("existing identity was replaced")
+	}
+}
+
+func TestGenerateIdentity_RejectsInvalidNames(t *testing.T) {
+	_, cleanup := testutil.SetupTestEnv(t)
+	defer cleanup()
+
+	for _, name := range []string{"", "../escape", "Alice", "a/b", ".hidden"} {
+		if _, err := GenerateIdentity(name); err == nil {
+			t.Errorf("expected name %q to be rejected", name)
+		}
+	}
+}
+
+func TestGeneratedIdentity_DecryptsWithAgeDecryptStep(t *testing.T) {
+	_, cleanup := testutil.SetupTestEnv(t)
+	defer cleanup()
+
+	identity, err := GenerateIdentity("bob")
+	if err != nil {
+		t.Fatalf("GenerateIdentity failed: %v", err)
+	}
+
+	recipient, err := age.ParseX25519Recipient(identity.Recipient)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var encrypted bytes.Buffer
+	w, err := age.Encrypt(&encrypted, recipient)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.Write([]byte("for bob"))
+	w.Close()
+
+	dir := t.TempDir()
+	input := filepath.Join(dir, "input")
+	output := filepath.Join(dir, "output")
+	if err := os.WriteFile(input, encrypted.Bytes(), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := ageDecryptStep(input, output, identity.Path); err != nil {
+		t.Fatalf("age-decrypt with generated identity failed: %v", err)
+	}
+
+	data, _ := os.ReadFile(output)
+	if string(data) != "for bob" {
+		t.Errorf("unexpected decrypted content: %q", data)
+	}
+}
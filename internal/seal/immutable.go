@@ -0,0 +1,23 @@
+package seal
+
+import (
+	"fmt"
+	"path/filepath"
+)
+
+// immutableFiles are the item files protected when an item is locked with --immutable.
+// The unsealed file is never protected: it belongs to the user after unlock.
+var immutableFiles = []string{"meta.json", "payload.bin"}
+
+// protectItemFiles sets the platform immutable attribute on an item's metadata and payload.
+// This guards against accidental modification by other tools and sync clients;
+// it is not a security boundary. Returns warnings for files that could not be protected.
+func protectItemFiles(itemDir string) []string {
+	var warnings []string
+	for _, name := range immutableFiles {
+		if err := setImmutable(filepath.Join(itemDir, name), true); err != nil {
+			warnings = append(warnings, fmt.Sprintf("warning: could not set immutable attribute on %s: %v", name, err))
+		}
+	}
+	return warnings
+}
@@ -0,0 +1,20 @@
+package seal
+
+import "golang.org/x/sys/unix"
+
+// setImmutable sets or clears the user immutable flag (chflags uchg).
+func setImmutable(path string, on bool) error {
+	var stat unix.Stat_t
+	if err := unix.Stat(path, &stat); err != nil {
+		return err
+	}
+
+	flags := stat.Flags
+	if on {
+		flags |= unix.UF_IMMUTABLE
+	} else {
+		flags &^= unix.UF_IMMUTABLE
+	}
+
+	return unix.Chflags(path, int(flags))
+}
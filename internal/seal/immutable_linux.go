@@ -0,0 +1,34 @@
+package seal
+
+import (
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+// fsImmutableFL is FS_IMMUTABLE_FL from linux/fs.h.
+const fsImmutableFL = 0x00000010
+
+// setImmutable sets or clears the immutable inode flag (chattr +i).
+// Setting it requires CAP_LINUX_IMMUTABLE and filesystem support.
+func setImmutable(path string, on bool) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	fd := int(file.Fd())
+	flags, err := unix.IoctlGetUint32(fd, unix.FS_IOC_GETFLAGS)
+	if err != nil {
+		return err
+	}
+
+	if on {
+		flags |= fsImmutableFL
+	} else {
+		flags &^= fsImmutableFL
+	}
+
+	return unix.IoctlSetPointerInt(fd, unix.FS_IOC_SETFLAGS, int(flags))
+}
@@ -0,0 +1,10 @@
+//go:build !darwin && !linux && !windows
+
+package seal
+
+import "errors"
+
+// setImmutable is not supported on this platform.
+func setImmutable(path string, on bool) error {
+	return errors.New("not supported on this platform")
+}
@@ -0,0 +1,47 @@
+package seal
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"seal/internal/testutil"
+	"seal/internal/timeauth"
+)
+
+func TestSaveMetadata_ImmutableItemStaysUpdatable(t *testing.T) {
+	_, cleanup := testutil.SetupTestEnv(t)
+	defer cleanup()
+
+	authority := &timeauth.FakeAuthority{DefaultRound: 100, CurrentRound: 200}
+	id, err := CreateSealedItemWithOptions(time.Now().UTC().Add(time.Hour), InputSourceStdin, "", []byte("data"), authority, ItemOptions{
+		Immutable: true,
+	})
+	if err != nil {
+		t.Fatalf("CreateSealedItemWithOptions failed: %v", err)
+	}
+
+	item, itemDir, _ := LoadItem(id)
+	if !item.Immutable {
+		t.Fatal("immutable flag not recorded")
+	}
+
+	// Protection may be unsupported here (warnings); either way files must be released for cleanup
+	protectItemFiles(itemDir)
+	t.Cleanup(func() {
+		for _, name := range immutableFiles {
+			setImmutable(filepath.Join(itemDir, name), false)
+		}
+	})
+
+	// Legitimate state transitions must still be able to update metadata
+	item, err = TryMaterialize(item, itemDir, authority)
+	if err != nil {
+		t.Fatalf("TryMaterialize failed on immutable item: %v", err)
+	}
+
+	persisted, _, _ := LoadItem(id)
+	if persisted.State != StateUnlocked {
+		t.Errorf("expected unlocked state to be persisted, got %s", persisted.State)
+	}
+}
@@ -0,0 +1,11 @@
+package seal
+
+import "os"
+
+// setImmutable sets or clears the read-only file attribute.
+func setImmutable(path string, on bool) error {
+	if on {
+		return os.Chmod(path, 0400)
+	}
+	return os.Chmod(path, 0600)
+}
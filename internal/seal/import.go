@@ -0,0 +1,241 @@
+package seal
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"filippo.io/age/armor"
+	"github.com/google/uuid"
+
+	"seal/internal/clock"
+	"seal/internal/timeauth"
+)
+
+// tlockFileAlgorithm marks an item whose payload.bin is an imported tlock (tle)
+// file: the whole file is time-locked, there is no separate DEK or nonce.
+const tlockFileAlgorithm = "tlock"
+
+// Import outcomes reported per file.
+const (
+	ImportStatusImported  = "imported"
+	ImportStatusDuplicate = "duplicate"
+	ImportStatusFailed    = "failed"
+)
+
+// ImportResult is the outcome of importing one file.
+type ImportResult struct {
+	Path   string
+	Status string // one of the ImportStatus constants
+	ID     string // new item, or the existing item a duplicate matches
+	Err    error  // set when Status is ImportStatusFailed
+}
+
+// ImportOptions controls an import.
+type ImportOptions struct {
+	DryRun bool // check every file and report the outcome, storing nothing
+}
+
+// ImportDir imports every regular file in dir as a sealed item.
+// Files must be tlock (tle) ciphertexts, binary or armored, time-locked to the
+// authority's drand chain. The file is stored in binary form and opens when its
+// round is published. Files whose content is already in the store, or that appear
+// twice in dir, are skipped. Subdirectories are not scanned.
+// Returns one result per file, in name order. With DryRun, a file that would be
+// imported is reported as imported with no ID.
+func ImportDir(dir string, authority timeauth.Authority, opts ImportOptions) ([]ImportResult, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read import directory: %w", err)
+	}
+
+	policy, err := LoadPolicy()
+	if err != nil {
+		return nil, err
+	}
+
+	// Content hash of every stored payload, so re-running an import is harmless
+	known := make(map[string]string)
+	err = WalkSealedItems(func(item SealedItem, itemDir string) error {
+		if item.PayloadSHA256 != "" {
+			known[item.PayloadSHA256] = item.ID
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var results []ImportResult
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+
+		path := filepath.Join(dir, entry.Name())
+		if !entry.Type().IsRegular() {
+			results = append(results, ImportResult{Path: path, Status: ImportStatusFailed, Err: fmt.Errorf("not a regular file")})
+			continue
+		}
+
+		results = append(results, importFile(path, authority, policy, known, opts.DryRun))
+	}
+
+	return results, nil
+}
+
+// importFile imports a single tlock file, recording its hash in known on success.
+// With dryRun, it stops short of storing the file.
+func importFile(path string, authority timeauth.Authority, policy *Policy, known map[string]string, dryRun bool) ImportResult {
+	result := ImportResult{Path: path, Status: ImportStatusFailed}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		result.Err = err
+		return result
+	}
+	if info.Size() > MaxInputSize {
+		result.Err = fmt.Errorf("file exceeds maximum size of %d bytes", MaxInputSize)
+		return result
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		result.Err = err
+		return result
+	}
+
+	// Armored and binary copies of the same file are stored, and deduplicated, alike
+	data, err = dearmorTlock(data)
+	if err != nil {
+		result.Err = err
+		return result
+	}
+
+	checksum := payloadChecksum(data)
+	if id, ok := known[checksum]; ok {
+		result.Status = ImportStatusDuplicate
+		result.ID = id
+		return result
+	}
+
+	round, chainHash, err := tlockHeader(data)
+	if err != nil {
+		result.Err = err
+		return result
+	}
+
+	// A file locked to another chain would never open here
+	if drand, ok := authority.(*timeauth.DrandAuthority); ok && chainHash != drand.ChainHash {
+		result.Err = fmt.Errorf("time-locked to drand chain %s, not %s", chainHash, drand.ChainHash)
+		return result
+	}
+
+	keyRef := strconv.FormatUint(round, 10)
+	unlockTime, err := authority.EarliestUnlockTime(timeauth.KeyReference(keyRef))
+	if err != nil {
+		result.Err = fmt.Errorf("failed to calculate unlock time: %w", err)
+		return result
+	}
+
+	if err := policy.Check(PolicyRequest{UnlockTime: unlockTime, InputPath: path, Authority: authority.Name()}, clock.UTC()); err != nil {
+		result.Err = err
+		return result
+	}
+
+	var id string
+	if !dryRun {
+		id, err = createImportedItem(data, path, keyRef, unlockTime, authority.Name())
+		if err != nil {
+			result.Err = err
+			return result
+		}
+	}
+
+	known[checksum] = id
+	result.Status = ImportStatusImported
+	result.ID = id
+	return result
+}
+
+// createImportedItem stores a tlock file as a new sealed item.
+func createImportedItem(data []byte, originalPath, keyRef string, unlockTime time.Time, authorityName string) (string, error) {
+	baseDir, err := GetSealBaseDir()
+	if err != nil {
+		return "", err
+	}
+
+	if err := os.MkdirAll(baseDir, 0700); err != nil {
+		return "", fmt.Errorf("cannot create seal directory: %w", err)
+	}
+
+	id := uuid.New().String()
+	meta := SealedItem{
+		ID:            id,
+		State:         StateSealed,
+		UnlockTime:    unlockTime.UTC(),
+		InputType:     InputSourceImport.String(),
+		OriginalPath:  originalPath,
+		TimeAuthority: authorityName,
+		CreatedAt:     clock.UTC(),
+		Algorithm:     tlockFileAlgorithm,
+		KeyRef:        keyRef,
+		SealVersion:   GetBuildInfo().Version,
+		SchemaVersion: MetadataSchemaVersion,
+		PayloadSHA256: payloadChecksum(data),
+	}
+	appendHistory(&meta, HistoryCreated, "")
+
+	if err := writeNewItem(baseDir, meta, data); err != nil {
+		return "", err
+	}
+
+	return id, nil
+}
+
+// isTlockFile reports whether an item's payload is a tlock file: imported, or
+// sealed with --direct-tlock.
+func isTlockFile(item SealedItem) bool {
+	return item.Algorithm == tlockFileAlgorithm || item.Algorithm == directTlockAlgorithm
+}
+
+// hasTimeLock reports whether an item has anything time-locked that can ever open.
+func hasTimeLock(item SealedItem) bool {
+	return item.DEKTlockB64 != "" || isTlockFile(item)
+}
+
+// dearmorTlock returns the binary form of a tlock file, decoding ASCII armor if present.
+func dearmorTlock(data []byte) ([]byte, error) {
+	if !bytes.HasPrefix(bytes.TrimLeft(data, " \t\r\n"), []byte(armor.Header)) {
+		return data, nil
+	}
+
+	decoded, err := io.ReadAll(armor.NewReader(bytes.NewReader(data)))
+	if err != nil {
+		return nil, fmt.Errorf("invalid armor: %w", err)
+	}
+	return decoded, nil
+}
+
+// ImportPlan lists the outcome of an import as planned changes.
+func ImportPlan(results []ImportResult, opts ImportOptions) Plan {
+	plan := Plan{DryRun: opts.DryRun}
+	for _, result := range results {
+		change := Change{Action: ChangeImport, Path: result.Path, ItemID: result.ID}
+		switch result.Status {
+		case ImportStatusDuplicate:
+			change = Change{Action: ChangeSkip, Path: result.Path, Detail: "duplicate"}
+			if result.ID != "" {
+				change.Detail = "duplicate of " + result.ID
+			}
+		case ImportStatusFailed:
+			change = Change{Action: ChangeSkip, Path: result.Path, Detail: result.Err.Error()}
+		}
+		plan.Changes = append(plan.Changes, change)
+	}
+	return plan
+}
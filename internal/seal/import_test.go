@@ -0,0 +1,253 @@
+package seal
+
+import (
+	"bytes"
+	"encoding/base64"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"filippo.io/age/armor"
+
+	"seal/internal/testutil"
+	"seal/internal/timeauth"
+	"seal/internal/timeauth/drandsim"
+)
+
+// startImportChain starts a drand simulator and returns an authority bound to it.
+func startImportChain(t *testing.T, period time.Duration) (*drandsim.Server, *timeauth.DrandAuthority) {
+	t.Helper()
+
+	server, err := drandsim.Start(drandsim.Config{Period: period, Genesis: time.Now().Add(-time.Hour).Unix()})
+	if err != nil {
+		t.Fatalf("failed to start drand simulator: %v", err)
+	}
+	t.Cleanup(func() { server.Close() })
+
+	authority := &timeauth.DrandAuthority{
+		NetworkName: "drandsim",
+		BaseURL:     server.URL + "/" + server.ChainHash,
+		ChainHash:   server.ChainHash,
+		HTTPClient:  http.DefaultClient,
+		Timelock:    &timeauth.RealTimelockBox{BaseURL: server.URL, ChainHash: server.ChainHash},
+	}
+
+	return server, authority
+}
+
+// tleFile time-locks content to a round, as the tle tool would write it.
+func tleFile(t *testing.T, server *drandsim.Server, content []byte, round uint64) []byte {
+	t.Helper()
+
+	box := &timeauth.RealTimelockBox{BaseURL: server.URL, ChainHash: server.ChainHash}
+	ciphertextB64, err := box.Encrypt(content, round)
+	if err != nil {
+		t.Fatalf("tlock encrypt failed: %v", err)
+	}
+
+	data, err := base64.StdEncoding.DecodeString(ciphertextB64)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func armorTle(t *testing.T, data []byte) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	w := armor.NewWriter(&buf)
+	if _, err := w.Write(data); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestImportDir_ImportsAndMaterializes(t *testing.T) {
+	_, cleanup := testutil.SetupTestEnv(t)
+	defer cleanup()
+
+	server, authority := startImportChain(t, 3*time.Second)
+	round := server.LatestRound() - 10
+
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "letter.tle"), armorTle(t, tleFile(t, server, []byte("hello"), round)), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	results, err := ImportDir(dir, authority, ImportOptions{})
+	if err != nil {
+		t.Fatalf("ImportDir failed: %v", err)
+	}
+	if len(results) != 1 || results[0].Status != ImportStatusImported {
+		t.Fatalf("expected one imported file, got %+v", results)
+	}
+
+	item, itemDir, err := LoadItem(results[0].ID)
+	if err != nil {
+		t.Fatalf("LoadItem failed: %v", err)
+	}
+	if item.State != StateSealed || item.InputType != "import" {
+		t.Errorf("expected sealed import item, got state %s input type %s", item.State, item.InputType)
+	}
+	if targetRound, _ := extractTargetRound(item.KeyRef); targetRound != round {
+		t.Errorf("expected target round %d, got %d", round, targetRound)
+	}
+
+	item, err = TryMaterialize(item, itemDir, authority)
+	if err != nil {
+		t.Fatalf("TryMaterialize failed: %v", err)
+	}
+	if item.State != StateUnlocked {
+		t.Fatalf("expected unlocked item, got %s", item.State)
+	}
+
+	content, err := os.ReadFile(UnsealedPath(item, itemDir))
+	if err != nil {
+		t.Fatalf("failed to read unsealed content: %v", err)
+	}
+	if string(content) != "hello" {
+		t.Errorf("got %q, want %q", content, "hello")
+	}
+}
+
+func TestImportDir_SkipsDuplicates(t *testing.T) {
+	_, cleanup := testutil.SetupTestEnv(t)
+	defer cleanup()
+
+	server, authority := startImportChain(t, 3*time.Second)
+	data := tleFile(t, server, []byte("hello"), server.LatestRound()+100)
+
+	dir := t.TempDir()
+	files := map[string][]byte{
+		"a.tle":     data,
+		"b.tle":     data,
+		"a.tle.asc": armorTle(t, data),
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), content, 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	results, err := ImportDir(dir, authority, ImportOptions{})
+	if err != nil {
+		t.Fatalf("ImportDir failed: %v", err)
+	}
+
+	var imported string
+	duplicates := 0
+	for _, result := range results {
+		switch result.Status {
+		case ImportStatusImported:
+			imported = result.ID
+		case ImportStatusDuplicate:
+			duplicates++
+		default:
+			t.Errorf("unexpected failure for %s: %v", result.Path, result.Err)
+		}
+	}
+	if imported == "" || duplicates != 2 {
+		t.Fatalf("expected one import and two duplicates, got %+v", results)
+	}
+
+	// Importing the same directory again changes nothing
+	results, err = ImportDir(dir, authority, ImportOptions{})
+	if err != nil {
+		t.Fatalf("second ImportDir failed: %v", err)
+	}
+	for _, result := range results {
+		if result.Status != ImportStatusDuplicate || result.ID != imported {
+			t.Errorf("expected %s to be a duplicate of %s, got %+v", result.Path, imported, result)
+		}
+	}
+}
+
+func TestImportDir_DryRun(t *testing.T) {
+	_, cleanup := testutil.SetupTestEnv(t)
+	defer cleanup()
+
+	server, authority := startImportChain(t, 3*time.Second)
+	data := tleFile(t, server, []byte("hello"), server.LatestRound()+100)
+
+	dir := t.TempDir()
+	for _, name := range []string{"a.tle", "b.tle"} {
+		if err := os.WriteFile(filepath.Join(dir, name), data, 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	opts := ImportOptions{DryRun: true}
+	results, err := ImportDir(dir, authority, opts)
+	if err != nil {
+		t.Fatalf("ImportDir failed: %v", err)
+	}
+	plan := ImportPlan(results, opts)
+	want := "would import: " + filepath.Join(dir, "a.tle") + "\nwould skip: " + filepath.Join(dir, "b.tle") + " (duplicate)\n"
+	if got := FormatPlan(plan); got != want {
+		t.Errorf("got plan:\n%s\nwant:\n%s", got, want)
+	}
+
+	items, err := ListSealedItems(ListOptions{})
+	if err != nil {
+		t.Fatalf("ListSealedItems failed: %v", err)
+	}
+	if len(items) != 0 {
+		t.Errorf("dry run stored %d items", len(items))
+	}
+}
+
+func TestImportDir_ReportsFailuresPerFile(t *testing.T) {
+	_, cleanup := testutil.SetupTestEnv(t)
+	defer cleanup()
+
+	server, authority := startImportChain(t, 3*time.Second)
+	other, _ := startImportChain(t, 2*time.Second)
+
+	dir := t.TempDir()
+	files := map[string][]byte{
+		"good.tle":  tleFile(t, server, []byte("hello"), server.LatestRound()+100),
+		"notes.txt": []byte("not a capsule"),
+		"other.tle": tleFile(t, other, []byte("hello"), other.LatestRound()+100),
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), content, 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.Mkdir(filepath.Join(dir, "nested"), 0700); err != nil {
+		t.Fatal(err)
+	}
+
+	results, err := ImportDir(dir, authority, ImportOptions{})
+	if err != nil {
+		t.Fatalf("ImportDir failed: %v", err)
+	}
+	if len(results) != 3 {
+		t.Fatalf("expected three results (directories skipped), got %+v", results)
+	}
+
+	want := map[string]string{
+		"good.tle":  "",
+		"notes.txt": "not a tlock ciphertext",
+		"other.tle": "time-locked to drand chain",
+	}
+	for _, result := range results {
+		expected := want[filepath.Base(result.Path)]
+		if expected == "" {
+			if result.Status != ImportStatusImported {
+				t.Errorf("expected %s to be imported, got %+v", result.Path, result)
+			}
+			continue
+		}
+		if result.Status != ImportStatusFailed || result.Err == nil || !strings.Contains(result.Err.Error(), expected) {
+			t.Errorf("expected %s to fail with %q, got %+v", result.Path, expected, result)
+		}
+	}
+}
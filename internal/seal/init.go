@@ -0,0 +1,149 @@
+package seal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+
+	"seal/internal/timeauth"
+)
+
+// InitResult reports what Init did, one line per step, plus best-effort warnings.
+type InitResult struct {
+	BaseDir  string
+	Steps    []string
+	Warnings []string
+}
+
+// Init prepares the store explicitly: it creates the base directory with owner-only
+// permissions, writes a default config, checks that the time authority serves the
+// chain pinned in this build, and optionally generates an identity.
+// Every step keeps what already exists, so running Init again is harmless.
+// Other commands still create what they need on first use; Init is never required.
+func Init(identityName string, authority timeauth.Authority) (InitResult, error) {
+	baseDir, err := GetSealBaseDir()
+	if err != nil {
+		return InitResult{}, err
+	}
+	result := InitResult{BaseDir: baseDir}
+
+	step, err := initBaseDir(baseDir)
+	if err != nil {
+		return result, err
+	}
+	result.Steps = append(result.Steps, step)
+
+	step, err = initConfig(baseDir)
+	if err != nil {
+		return result, err
+	}
+	result.Steps = append(result.Steps, step)
+
+	// An unreachable authority is not fatal: lock will need it, but status works offline
+	step, err = verifyPinnedChain(authority)
+	var mismatch *timeauth.ChainMismatchError
+	if errors.As(err, &mismatch) {
+		return result, err
+	}
+	if err != nil {
+		result.Warnings = append(result.Warnings, fmt.Sprintf("warning: could not verify the time authority: %v", err))
+	} else {
+		result.Steps = append(result.Steps, step)
+	}
+
+	if identityName != "" {
+		step, err = initIdentity(identityName)
+		if err != nil {
+			return result, err
+		}
+		result.Steps = append(result.Steps, step)
+	}
+
+	return result, nil
+}
+
+// initBaseDir creates the base directory, or tightens its permissions to owner-only.
+func initBaseDir(baseDir string) (string, error) {
+	info, err := os.Lstat(baseDir)
+	if errors.Is(err, fs.ErrNotExist) {
+		if err := os.MkdirAll(baseDir, 0700); err != nil {
+			return "", fmt.Errorf("cannot create seal directory: %w", err)
+		}
+		return fmt.Sprintf("store: created %s", baseDir), nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("cannot stat seal directory: %w", err)
+	}
+	if !info.IsDir() {
+		return "", fmt.Errorf("%s exists and is not a directory", baseDir)
+	}
+
+	// Windows does not map permission bits to ACLs
+	if runtime.GOOS != "windows" && info.Mode().Perm() != 0700 {
+		if err := os.Chmod(baseDir, 0700); err != nil {
+			return "", fmt.Errorf("cannot restrict seal directory permissions: %w", err)
+		}
+		return fmt.Sprintf("store: %s exists, permissions tightened from %o to 700", baseDir, info.Mode().Perm()), nil
+	}
+	return fmt.Sprintf("store: %s exists", baseDir), nil
+}
+
+// initConfig writes an empty default config unless one exists.
+// An existing config is validated but never rewritten.
+func initConfig(baseDir string) (string, error) {
+	path := filepath.Join(baseDir, configFileName)
+	if _, err := os.Lstat(path); err == nil {
+		if _, err := LoadConfig(); err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("config: %s exists, kept", path), nil
+	}
+
+	if err := SaveConfig(Config{}); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("config: created %s with defaults", path), nil
+}
+
+// verifyPinnedChain fetches the drand chain info and checks it against the pinned chain hash.
+// Other authorities have nothing to verify.
+func verifyPinnedChain(authority timeauth.Authority) (string, error) {
+	drand, ok := authority.(*timeauth.DrandAuthority)
+	if !ok {
+		return fmt.Sprintf("time authority: %s", authority.Name()), nil
+	}
+
+	// FetchInfo fails with a ChainMismatchError if the served chain is not the pinned one
+	info, err := drand.FetchInfo(context.Background())
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("time authority: drand %s chain %s verified (period %ds)", drand.NetworkName, info.Hash, info.Period), nil
+}
+
+// initIdentity generates an identity under name, keeping one that already exists.
+func initIdentity(name string) (string, error) {
+	if identity, err := LoadIdentity(name); err == nil {
+		return fmt.Sprintf("identity: %s exists, kept (%s)", name, identity.Recipient), nil
+	}
+
+	identity, err := GenerateIdentity(name)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("identity: created %s (%s)", name, identity.Recipient), nil
+}
+
+// FormatInitResult formats the summary printed by seal init.
+func FormatInitResult(result InitResult) string {
+	output := ""
+	for _, step := range result.Steps {
+		output += step + "\n"
+	}
+	return output
+}
@@ -0,0 +1,108 @@
+package seal
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"seal/internal/testutil"
+	"seal/internal/timeauth"
+)
+
+func TestInit_CreatesStoreAndIsRepeatable(t *testing.T) {
+	_, cleanup := testutil.SetupTestEnv(t)
+	defer cleanup()
+
+	_, authority := startImportChain(t, 3*time.Second)
+
+	result, err := Init("default", authority)
+	if err != nil {
+		t.Fatalf("Init failed: %v", err)
+	}
+	if len(result.Warnings) != 0 {
+		t.Errorf("unexpected warnings: %v", result.Warnings)
+	}
+
+	info, err := os.Stat(result.BaseDir)
+	if err != nil || !info.IsDir() {
+		t.Fatalf("store not created: %v", err)
+	}
+	if runtime.GOOS != "windows" && info.Mode().Perm() != 0700 {
+		t.Errorf("store permissions = %o, want 700", info.Mode().Perm())
+	}
+	if _, err := os.Stat(filepath.Join(result.BaseDir, configFileName)); err != nil {
+		t.Errorf("config not written: %v", err)
+	}
+	identity, err := LoadIdentity("default")
+	if err != nil {
+		t.Fatalf("identity not generated: %v", err)
+	}
+
+	out := FormatInitResult(result)
+	for _, want := range []string{"store: created", "config: created", "chain " + authority.ChainHash + " verified", "identity: created default"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("summary missing %q:\n%s", want, out)
+		}
+	}
+
+	// A second run keeps everything, including the identity's key
+	result, err = Init("default", authority)
+	if err != nil {
+		t.Fatalf("second Init failed: %v", err)
+	}
+	out = FormatInitResult(result)
+	if !strings.Contains(out, "config: ") || !strings.Contains(out, "exists, kept") {
+		t.Errorf("second run should keep existing files:\n%s", out)
+	}
+	again, err := LoadIdentity("default")
+	if err != nil || again.Recipient != identity.Recipient {
+		t.Errorf("identity changed on second run: %v", err)
+	}
+}
+
+func TestInit_TightensPermissions(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("permission bits are not enforced on Windows")
+	}
+	_, cleanup := testutil.SetupTestEnv(t)
+	defer cleanup()
+
+	baseDir, err := GetSealBaseDir()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(baseDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	os.Chmod(baseDir, 0755)
+
+	result, err := Init("", &timeauth.PlaceholderAuthority{})
+	if err != nil {
+		t.Fatalf("Init failed: %v", err)
+	}
+	if !strings.Contains(FormatInitResult(result), "tightened from 755 to 700") {
+		t.Errorf("expected permissions to be tightened:\n%s", FormatInitResult(result))
+	}
+	if info, _ := os.Stat(baseDir); info.Mode().Perm() != 0700 {
+		t.Errorf("store permissions = %o, want 700", info.Mode().Perm())
+	}
+}
+
+func TestVerifyPinnedChain_Mismatch(t *testing.T) {
+	_, authority := startImportChain(t, 3*time.Second)
+	authority.ChainHash = strings.Repeat("0", 64)
+
+	_, err := verifyPinnedChain(authority)
+	if err == nil || !strings.Contains(err.Error(), "pins") {
+		t.Fatalf("expected chain mismatch, got %v", err)
+	}
+
+	_, cleanup := testutil.SetupTestEnv(t)
+	defer cleanup()
+	if _, err := Init("", authority); err == nil {
+		t.Error("Init must fail when the served chain does not match the pinned one")
+	}
+}
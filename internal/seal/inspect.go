@@ -0,0 +1,303 @@
+package seal
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"seal/internal/clock"
+	"seal/internal/timeauth"
+)
+
+// Inspect loads a single item for detailed display.
+// Inspection is read-only: it never materializes or modifies the item.
+func Inspect(id string) (SealedItem, error) {
+	item, _, err := LoadItem(id)
+	return item, err
+}
+
+// CheckIntegrity verifies an item against what was recorded about it: the state
+// invariants, the payload against payload_sha256, and unlocked content against
+// the hash recorded at unlock. Returns one message per problem found.
+// Read-only, like Inspect.
+func CheckIntegrity(id string) ([]string, error) {
+	item, itemDir, err := LoadItem(id)
+	if err != nil {
+		return nil, err
+	}
+
+	return checkItemIntegrity(item, itemDir), nil
+}
+
+// checkItemIntegrity runs the checks of CheckIntegrity on a loaded item.
+func checkItemIntegrity(item SealedItem, itemDir string) []string {
+	var problems []string
+	if err := ValidateItemState(item, itemDir); err != nil {
+		problems = append(problems, err.Error())
+	}
+
+	if item.PayloadSHA256 != "" {
+		sum, err := fileSHA256(filepath.Join(itemDir, "payload.bin"))
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("cannot verify payload: %v", err))
+		} else if sum != item.PayloadSHA256 {
+			problems = append(problems, "payload does not match payload_sha256 (corrupted or modified)")
+		}
+	}
+
+	// A tlock file payload names its own round, which must be the one recorded
+	if isTlockFile(item) && item.State == StateSealed {
+		if problem := checkTlockPayloadRound(item, itemDir); problem != "" {
+			problems = append(problems, problem)
+		}
+	}
+
+	return append(problems, checkUnsealedIntegrity(item, itemDir)...)
+}
+
+// checkTlockPayloadRound compares the round in a drand tlock payload with the item's key reference.
+// Payloads of other authorities have no header to compare.
+func checkTlockPayloadRound(item SealedItem, itemDir string) string {
+	data, err := os.ReadFile(filepath.Join(itemDir, "payload.bin"))
+	if err != nil {
+		return fmt.Sprintf("cannot verify payload: %v", err)
+	}
+	round, _, err := tlockHeader(data)
+	if err != nil {
+		return ""
+	}
+	targetRound, err := extractTargetRound(item.KeyRef)
+	if err != nil {
+		return err.Error()
+	}
+	if round != targetRound {
+		return fmt.Sprintf("payload is time-locked to round %d but metadata records %d", round, targetRound)
+	}
+	return ""
+}
+
+// FormatIntegrity formats the result of CheckIntegrity for inspect output.
+func FormatIntegrity(problems []string) string {
+	if len(problems) == 0 {
+		return "integrity: ok\n"
+	}
+
+	result := "integrity: failed\n"
+	for _, problem := range problems {
+		result += fmt.Sprintf("  %s\n", problem)
+	}
+	return result
+}
+
+// FormatInspectJSON formats an item's metadata as indented JSON.
+func FormatInspectJSON(item SealedItem) (string, error) {
+	data, err := json.MarshalIndent(item, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("cannot marshal metadata: %w", err)
+	}
+	return string(data) + "\n", nil
+}
+
+// FormatInspectOutput formats an item's metadata for display.
+// Round details are included if given. If showHistory is set, the recorded history is appended.
+func FormatInspectOutput(item SealedItem, rounds *RoundDetails, showHistory bool) string {
+	var effective time.Time
+	if rounds != nil {
+		effective = rounds.RoundTime
+	}
+
+	result := fmt.Sprintf("id: %s\nstate: %s\nunlock_time: %s\ncreated_at: %s\ninput_type: %s\n",
+		item.ID,
+		item.State,
+		formatUnlockTime(item, effective),
+		item.CreatedAt.Format(time.RFC3339),
+		item.InputType)
+
+	// Time remaining by the authority's rounds, or by the local clock without them
+	if item.State == StateSealed && permanentLockReason(item) == "" {
+		if rounds != nil {
+			result += fmt.Sprintf("time_remaining: %s\n", formatRemaining(time.Duration(rounds.RoundsRemaining)*rounds.Period))
+		} else {
+			result += fmt.Sprintf("time_remaining: %s (local clock)\n", formatRemaining(max(item.UnlockTime.Sub(clock.UTC()), 0)))
+		}
+	}
+
+	if item.OriginalPath != "" {
+		result += fmt.Sprintf("original_path: %s\n", item.OriginalPath)
+	}
+
+	if item.Archive != nil {
+		result += fmt.Sprintf("archive: %s\n", formatArchive(*item.Archive))
+	}
+
+	result += fmt.Sprintf("time_authority: %s\nalgorithm: %s\n", item.TimeAuthority, item.Algorithm)
+
+	if item.TimeAuthority == "drand" {
+		if network, ok := timeauth.DrandNetworkOf(timeauth.KeyReference(item.KeyRef)); ok {
+			result += fmt.Sprintf("drand_network: %s\n", network.Name)
+			if network.ChainHash != "" {
+				result += fmt.Sprintf("drand_chain_hash: %s\n", network.ChainHash)
+			}
+			if network.Relay != "" {
+				result += fmt.Sprintf("drand_relay: %s\n", network.Relay)
+			}
+		}
+	}
+
+	if item.TimeAuthority == timeauth.MultiAuthorityName {
+		if multiRef, ok := timeauth.MultiKeyReferenceOf(timeauth.KeyReference(item.KeyRef)); ok {
+			result += fmt.Sprintf("threshold: %d of %d\n", multiRef.Threshold, len(multiRef.Members))
+			for _, member := range multiRef.Members {
+				result += fmt.Sprintf("member: %s\n", describeMember(member))
+			}
+		}
+	}
+
+	if reason := permanentLockReason(item); reason != "" {
+		result += fmt.Sprintf("permanently locked: %s (this item can never unlock)\n", reason)
+	}
+
+	if len(item.Tags) > 0 {
+		result += fmt.Sprintf("tags: %s\n", formatTags(item.Tags))
+	}
+
+	if item.Note != "" {
+		result += fmt.Sprintf("note: %s\n", item.Note)
+	}
+
+	sealVersion := item.SealVersion
+	if sealVersion == "" {
+		sealVersion = "unknown (not recorded)"
+	}
+	result += fmt.Sprintf("seal_version: %s\nschema_version: %d\n", sealVersion, item.SchemaVersion)
+
+	if len(item.Normalization) > 0 {
+		result += fmt.Sprintf("normalization: %s\n", strings.Join(item.Normalization, ","))
+	}
+
+	if item.CiphertextSize > 0 {
+		result += fmt.Sprintf("plaintext_size: %d\nciphertext_size: %d\n", item.PlaintextSize, item.CiphertextSize)
+	}
+
+	if item.PayloadSHA256 != "" {
+		result += fmt.Sprintf("payload_sha256: %s\n", item.PayloadSHA256)
+	}
+
+	if a := item.Attestation; a != nil {
+		result += fmt.Sprintf("attestation: %s (submitted %s)\n", a.Kind, a.SubmittedAt.Format(time.RFC3339))
+	}
+
+	if item.AADVersion > 0 {
+		result += fmt.Sprintf("aad: v%d (bound to id, target round, algorithm)\n", item.AADVersion)
+	} else if item.Algorithm == directTlockAlgorithm {
+		result += "aad: none (content time-locked directly)\n"
+	} else if isTlockFile(item) {
+		result += "aad: none (imported tlock file)\n"
+	} else {
+		result += "aad: none (legacy item)\n"
+	}
+
+	if item.Immutable {
+		result += "immutable: yes\n"
+	}
+
+	if item.Recipient != "" {
+		if item.RecipientName != "" {
+			result += fmt.Sprintf("recipient: %s (%s)\n", item.RecipientName, item.Recipient)
+		} else {
+			result += fmt.Sprintf("recipient: %s\n", item.Recipient)
+		}
+	}
+
+	if p := item.Passphrase; p != nil {
+		result += fmt.Sprintf("passphrase: %s (t=%d, m=%d KiB, p=%d)\n", p.KDF, p.Time, p.MemoryKiB, p.Threads)
+	}
+
+	if item.VaultWrap != "" {
+		result += fmt.Sprintf("vault_wrap: %s\n", item.VaultWrap)
+	}
+
+	if item.UnsealTo != "" {
+		result += fmt.Sprintf("unseal_to: %s\n", item.UnsealTo)
+	}
+
+	if item.UnlockFailures > 0 {
+		result += fmt.Sprintf("unlock_failures: %d\nlast_unlock_error: %s\n", item.UnlockFailures, item.LastUnlockError)
+		if item.NextUnlockAttempt != nil {
+			result += fmt.Sprintf("next_unlock_attempt: %s\n", item.NextUnlockAttempt.Format(time.RFC3339))
+		}
+	}
+
+	if item.UnlockedAt != nil {
+		result += fmt.Sprintf("unlocked_at: %s\nunlock_round: %d\n", item.UnlockedAt.Format(time.RFC3339), item.UnlockRound)
+	}
+
+	if item.UnsealedSHA256 != "" {
+		result += fmt.Sprintf("unsealed_sha256: %s\n", item.UnsealedSHA256)
+	}
+
+	if len(item.PostProcess) > 0 {
+		result += fmt.Sprintf("post_process: %s\n", strings.Join(item.PostProcess, ","))
+		for _, step := range item.PostProcessResults {
+			line := fmt.Sprintf("  %s %s", step.Step, step.Status)
+			if step.Error != "" {
+				line += ": " + step.Error
+			}
+			result += line + "\n"
+		}
+	}
+
+	if item.RetainUnsealed != "" {
+		result += fmt.Sprintf("retain_unsealed: %s\n", item.RetainUnsealed)
+		if item.UnsealedShreddedAt != nil {
+			result += fmt.Sprintf("unsealed_shredded_at: %s\n", item.UnsealedShreddedAt.Format(time.RFC3339))
+		}
+	}
+
+	if item.RevealTo != "" {
+		result += fmt.Sprintf("reveal_to: %s\n", item.RevealTo)
+		if item.RevealStatus != "" {
+			result += fmt.Sprintf("reveal_status: %s\n", item.RevealStatus)
+		}
+	}
+
+	if item.OnUnlockExec != "" {
+		result += fmt.Sprintf("on_unlock_exec: %s\n", item.OnUnlockExec)
+	}
+	if item.OnUnlockWebhook != "" {
+		result += fmt.Sprintf("on_unlock_webhook: %s\n", item.OnUnlockWebhook)
+	}
+
+	if rounds != nil {
+		result += FormatRoundDetails(*rounds)
+	}
+
+	if showHistory {
+		result += "history:\n"
+		for _, entry := range item.History {
+			line := fmt.Sprintf("  %s %s", entry.Time.Format(time.RFC3339), entry.Event)
+			if entry.Detail != "" {
+				line += ": " + entry.Detail
+			}
+			result += line + "\n"
+		}
+	}
+
+	return result
+}
+
+// describeMember formats a threshold item member, with its network for drand.
+func describeMember(member timeauth.MemberKeyReference) string {
+	if member.Authority == "drand" {
+		if network, ok := timeauth.DrandNetworkOf(member.KeyRef); ok {
+			if network.Name == timeauth.CustomDrandNetwork {
+				return fmt.Sprintf("drand %s (chain %s)", network.Name, network.ChainHash)
+			}
+			return "drand " + network.Name
+		}
+	}
+	return member.Authority
+}
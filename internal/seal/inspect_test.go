@@ -0,0 +1,66 @@
+package seal
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"seal/internal/testutil"
+	"seal/internal/timeauth"
+)
+
+func TestCheckIntegrity(t *testing.T) {
+	_, cleanup := testutil.SetupTestEnv(t)
+	defer cleanup()
+
+	authority := &timeauth.FakeAuthority{DefaultRound: 100}
+	id, err := CreateSealedItem(time.Now().UTC().Add(time.Hour), InputSourceStdin, "", []byte("data"), authority)
+	if err != nil {
+		t.Fatalf("CreateSealedItem failed: %v", err)
+	}
+
+	problems, err := CheckIntegrity(id)
+	if err != nil || len(problems) != 0 {
+		t.Fatalf("expected a fresh item to be intact, got %v, %v", problems, err)
+	}
+	if out := FormatIntegrity(problems); out != "integrity: ok\n" {
+		t.Errorf("unexpected output %q", out)
+	}
+
+	_, itemDir, _ := LoadItem(id)
+	if err := os.WriteFile(filepath.Join(itemDir, "payload.bin"), []byte("tampered"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	problems, err = CheckIntegrity(id)
+	if err != nil || len(problems) != 1 || !strings.Contains(problems[0], "does not match payload_sha256") {
+		t.Fatalf("expected a payload mismatch, got %v, %v", problems, err)
+	}
+	if out := FormatIntegrity(problems); !strings.HasPrefix(out, "integrity: failed\n  payload does not match") {
+		t.Errorf("unexpected output %q", out)
+	}
+
+	if _, err := CheckIntegrity("a1b2c3d4-5e6f-7890-abcd-ef1234567890"); err == nil {
+		t.Error("expected an error for an unknown item")
+	}
+}
+
+func TestFormatInspectOutput_TimeRemaining(t *testing.T) {
+	item := SealedItem{ID: "x", State: StateSealed, UnlockTime: time.Now().UTC().Add(48 * time.Hour), DEKTlockB64: "key"}
+
+	// By the authority's rounds when they are known
+	rounds := &RoundDetails{Period: 3 * time.Second, RoundsRemaining: 20}
+	if out := FormatInspectOutput(item, rounds, false); !strings.Contains(out, "time_remaining: 1m\n") {
+		t.Errorf("expected time remaining from rounds, got: %s", out)
+	}
+
+	if out := FormatInspectOutput(item, nil, false); !strings.Contains(out, "time_remaining: 1d 23h") || !strings.Contains(out, "(local clock)") {
+		t.Errorf("expected time remaining by the local clock, got: %s", out)
+	}
+
+	item.State = StateUnlocked
+	if out := FormatInspectOutput(item, rounds, false); strings.Contains(out, "time_remaining") {
+		t.Errorf("unlocked items have no time remaining, got: %s", out)
+	}
+}
@@ -3,7 +3,6 @@ package seal
 import (
 	"fmt"
 	"os"
-	"path/filepath"
 )
 
 // State invariants:
@@ -14,6 +13,10 @@ import (
 //
 // If state == StateUnlocked:
 //     unsealed file MUST exist (or unsealed.pending if recovery incomplete)
+//     unless unsealed_shredded_at is recorded (retention period elapsed)
+//     or the content was written outside the store (unseal_to), where the user may move it
+//
+// The unsealed file is at UnsealedPath (inside the item directory by default).
 //
 // These invariants apply to every sealed item directory.
 
@@ -22,8 +25,12 @@ import (
 // It NEVER attempts automatic repair and NEVER mutates disk.
 // Note: unsealed.pending files are handled by recovery logic, not validation.
 func ValidateItemState(item SealedItem, itemDir string) error {
-	unsealedPath := filepath.Join(itemDir, "unsealed")
-	pendingPath := filepath.Join(itemDir, "unsealed.pending")
+	if err := checkItemDir(itemDir); err != nil {
+		return fmt.Errorf("item %s: %w", item.ID, err)
+	}
+
+	unsealedPath := UnsealedPath(item, itemDir)
+	pendingPath := unsealedPath + ".pending"
 	
 	_, unsealedErr := os.Stat(unsealedPath)
 	unsealedExists := unsealedErr == nil
@@ -42,7 +49,9 @@ func ValidateItemState(item SealedItem, itemDir string) error {
 
 	case StateUnlocked:
 		// Invariant: unsealed file must exist (or pending if recovery incomplete)
-		if !unsealedExists && !pendingExists {
+		// Content shredded by a retention policy is intentionally absent
+		// Content outside the store is the user's to move or delete
+		if !unsealedExists && !pendingExists && item.UnsealedShreddedAt == nil && item.UnsealTo == "" {
 			if os.IsNotExist(unsealedErr) {
 				return fmt.Errorf("item %s: state is unlocked but unsealed file missing (corrupted)", item.ID)
 			}
@@ -0,0 +1,66 @@
+package seal
+
+import (
+	"encoding/csv"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// inventoryColumns are the columns of a CSV inventory, in order.
+var inventoryColumns = []string{"id", "state", "created_at", "unlock_time", "time_authority", "plaintext_size", "ciphertext_size"}
+
+// FormatStatusCSVHeader returns the header row of a CSV inventory.
+func FormatStatusCSVHeader() (string, error) {
+	return formatCSVRecord(inventoryColumns)
+}
+
+// FormatStatusCSV formats one item as a CSV inventory row.
+// unlock_time is the effective unlock time when the countdown knows it, like status.
+// Sizes are empty for items created before they were recorded.
+func FormatStatusCSV(item SealedItem, countdown *Countdown) (string, error) {
+	unlockTime := item.UnlockTime
+	if countdown != nil && !countdown.UnlockAt.IsZero() {
+		unlockTime = countdown.UnlockAt
+	}
+
+	record := []string{
+		item.ID,
+		item.State,
+		item.CreatedAt.UTC().Format(time.RFC3339),
+		unlockTime.UTC().Format(time.RFC3339),
+		item.TimeAuthority,
+		formatRecordedSize(item.PlaintextSize, item.CiphertextSize),
+		formatRecordedSize(item.CiphertextSize, item.CiphertextSize),
+	}
+
+	line, err := formatCSVRecord(record)
+	if err != nil {
+		return "", fmt.Errorf("cannot format inventory for item %s: %w", item.ID, err)
+	}
+	return line, nil
+}
+
+// formatRecordedSize formats size, or "" if sizes were not recorded for the item.
+// A recorded ciphertext is never empty, so its size tells recorded from zero.
+func formatRecordedSize(size, ciphertextSize int64) string {
+	if ciphertextSize == 0 {
+		return ""
+	}
+	return strconv.FormatInt(size, 10)
+}
+
+// formatCSVRecord encodes one CSV record with its trailing newline.
+func formatCSVRecord(record []string) (string, error) {
+	var b strings.Builder
+	w := csv.NewWriter(&b)
+	if err := w.Write(record); err != nil {
+		return "", err
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
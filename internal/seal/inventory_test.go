@@ -0,0 +1,53 @@
+package seal
+
+import (
+	"encoding/csv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFormatStatusCSV(t *testing.T) {
+	header, err := FormatStatusCSVHeader()
+	if err != nil {
+		t.Fatalf("FormatStatusCSVHeader failed: %v", err)
+	}
+	if header != "id,state,created_at,unlock_time,time_authority,plaintext_size,ciphertext_size\n" {
+		t.Errorf("unexpected header: %q", header)
+	}
+
+	created := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	requested := time.Date(2026, 12, 31, 23, 59, 59, 0, time.UTC)
+	effective := time.Date(2027, 1, 1, 0, 0, 2, 0, time.UTC)
+	item := SealedItem{
+		ID:             "test-id",
+		State:          StateSealed,
+		CreatedAt:      created,
+		UnlockTime:     requested,
+		TimeAuthority:  "drand",
+		PlaintextSize:  14,
+		CiphertextSize: 30,
+	}
+
+	line, err := FormatStatusCSV(item, &Countdown{UnlockAt: effective})
+	if err != nil {
+		t.Fatalf("FormatStatusCSV failed: %v", err)
+	}
+	if line != "test-id,sealed,2026-01-02T03:04:05Z,2027-01-01T00:00:02Z,drand,14,30\n" {
+		t.Errorf("unexpected row: %q", line)
+	}
+
+	// Without a countdown the requested time is used; unrecorded sizes are empty
+	item.PlaintextSize, item.CiphertextSize = 0, 0
+	line, err = FormatStatusCSV(item, nil)
+	if err != nil {
+		t.Fatalf("FormatStatusCSV failed: %v", err)
+	}
+	record, err := csv.NewReader(strings.NewReader(line)).Read()
+	if err != nil {
+		t.Fatalf("row is not valid CSV: %v", err)
+	}
+	if record[3] != "2026-12-31T23:59:59Z" || record[5] != "" || record[6] != "" {
+		t.Errorf("unexpected record: %q", record)
+	}
+}
@@ -0,0 +1,165 @@
+package seal
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"seal/internal/clock"
+)
+
+// Concurrent seal processes, such as the daemon and status from a terminal,
+// serialize their changes to an item with an advisory lock per item: flock on
+// Unix, LockFileEx on Windows. The operating system releases the lock when its
+// holder exits, however it exits, so a crashed process never leaves an item locked.
+//
+// Lock files live in a directory of their own, so item directories hold only
+// item files and can be deleted while locked.
+
+// ErrItemBusy is returned for an item another seal process is working on.
+var ErrItemBusy = errors.New("busy")
+
+// locksDirName is the directory of lock files in the store; it is not a UUID, so never taken for an item.
+const locksDirName = ".locks"
+
+// itemLockWait is how long to wait for a busy item before giving up.
+// Holders keep an item only while they change its state, e.g. for one unlock
+// attempt; post-unlock actions, such as hooks and reveal delivery, run under
+// an action lock of their own (see tryLockItemActions).
+var itemLockWait = 10 * time.Second
+
+// itemLockPoll is how often a busy item is tried again.
+const itemLockPoll = 50 * time.Millisecond
+
+// itemLock is a held lock on an item.
+type itemLock struct {
+	file *os.File
+}
+
+// lockItem takes the lock on the item in itemDir, waiting up to itemLockWait.
+// Returns an error wrapping ErrItemBusy if another process holds it.
+func lockItem(itemDir string) (*itemLock, error) {
+	path := itemLockPath(itemDir)
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, fmt.Errorf("cannot create lock directory: %w", err)
+	}
+
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("cannot open item lock: %w", err)
+	}
+
+	start := clock.Now()
+	for {
+		locked, err := tryLockFile(file)
+		if err != nil {
+			file.Close()
+			return nil, fmt.Errorf("cannot lock item: %w", err)
+		}
+		if locked {
+			break
+		}
+		if start.Elapsed() >= itemLockWait {
+			holder := readLockHolder(file)
+			file.Close()
+			return nil, fmt.Errorf("item %s is %w: %s", filepath.Base(itemDir), ErrItemBusy, holder)
+		}
+		time.Sleep(itemLockPoll)
+	}
+
+	recordLockHolder(file)
+	return &itemLock{file: file}, nil
+}
+
+// unlock releases the lock. Releasing it again does nothing.
+func (l *itemLock) unlock() {
+	if l.file == nil {
+		return
+	}
+	unlockFile(l.file)
+	l.file.Close()
+	l.file = nil
+}
+
+// tryLockItemActions takes the lock on running the post-unlock actions of the
+// item in itemDir, without waiting. Reports false if another process holds it
+// or the lock cannot be taken; the actions are retried on a later run.
+func tryLockItemActions(itemDir string) (*itemLock, bool) {
+	path := itemActionsLockPath(itemDir)
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, false
+	}
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0600)
+	if err != nil {
+		return nil, false
+	}
+	if locked, err := tryLockFile(file); err != nil || !locked {
+		file.Close()
+		return nil, false
+	}
+	return &itemLock{file: file}, true
+}
+
+// updateMetadata applies update to the item's current metadata and saves it,
+// holding the item's lock. Work done without the lock records its outcome this
+// way, so changes other processes made meanwhile are kept.
+func updateMetadata(itemDir string, update func(item *SealedItem)) (SealedItem, error) {
+	lock, err := lockItem(itemDir)
+	if err != nil {
+		return SealedItem{}, err
+	}
+	defer lock.unlock()
+
+	item, err := loadMetadata(itemDir)
+	if err != nil {
+		return SealedItem{}, err
+	}
+	update(&item)
+	return item, saveMetadata(itemDir, item)
+}
+
+// itemLockPath returns the lock file of the item in itemDir.
+func itemLockPath(itemDir string) string {
+	return filepath.Join(filepath.Dir(itemDir), locksDirName, filepath.Base(itemDir)+".lock")
+}
+
+// itemActionsLockPath returns the post-unlock action lock file of the item in itemDir.
+func itemActionsLockPath(itemDir string) string {
+	return filepath.Join(filepath.Dir(itemDir), locksDirName, filepath.Base(itemDir)+".actions.lock")
+}
+
+// removeItemLock removes the lock files of a deleted item, and the lock directory
+// once it is empty. A process waiting on it finds the item gone once it gets the lock.
+func removeItemLock(itemDir string) {
+	path := itemLockPath(itemDir)
+	os.Remove(path)
+	os.Remove(itemActionsLockPath(itemDir))
+	os.Remove(filepath.Dir(path))
+}
+
+// recordLockHolder writes this process into a held lock file,
+// for the busy message of processes that have to wait.
+func recordLockHolder(file *os.File) {
+	holder := fmt.Sprintf("%d %s\n", os.Getpid(), clock.UTC().Format(time.RFC3339))
+	if err := file.Truncate(0); err == nil {
+		file.WriteAt([]byte(holder), 0)
+	}
+}
+
+// readLockHolder describes the process holding a lock, as it recorded itself.
+func readLockHolder(file *os.File) string {
+	data := make([]byte, 64)
+	n, _ := file.ReadAt(data, 0)
+	fields := strings.Fields(string(data[:n]))
+	if len(fields) != 2 {
+		return "another seal process is working on it"
+	}
+	if _, err := strconv.Atoi(fields[0]); err != nil {
+		return "another seal process is working on it"
+	}
+	return fmt.Sprintf("seal process %s has been working on it since %s", fields[0], fields[1])
+}
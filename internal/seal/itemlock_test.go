@@ -0,0 +1,79 @@
+package seal
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"seal/internal/testutil"
+	"seal/internal/timeauth"
+)
+
+func TestLockItem_Busy(t *testing.T) {
+	_, cleanup := testutil.SetupTestEnv(t)
+	defer cleanup()
+	registerFakeAuthority()
+
+	defer func(wait time.Duration) { itemLockWait = wait }(itemLockWait)
+	itemLockWait = 100 * time.Millisecond
+
+	authority := &timeauth.FakeAuthority{AuthorityName: "registered-fake", DefaultRound: 100}
+	id, err := CreateSealedItem(time.Now().UTC().Add(time.Hour), InputSourceStdin, "", []byte("data"), authority)
+	if err != nil {
+		t.Fatalf("CreateSealedItem failed: %v", err)
+	}
+	_, itemDir, _ := LoadItem(id)
+
+	lock, err := lockItem(itemDir)
+	if err != nil {
+		t.Fatalf("lockItem failed: %v", err)
+	}
+
+	_, err = lockItem(itemDir)
+	if !errors.Is(err, ErrItemBusy) || !strings.Contains(err.Error(), fmt.Sprintf("seal process %d", os.Getpid())) {
+		t.Errorf("expected a busy error naming this process, got %v", err)
+	}
+	if _, err := Open(id); !errors.Is(err, ErrItemBusy) {
+		t.Errorf("expected Open of a busy item to fail, got %v", err)
+	}
+
+	// Status skips the busy item and leaves it sealed
+	result, err := GetStatus()
+	if err != nil {
+		t.Fatalf("GetStatus failed: %v", err)
+	}
+	if len(result.Warnings) == 0 || !strings.Contains(result.Warnings[0], "busy") {
+		t.Errorf("expected a busy warning, got %v", result.Warnings)
+	}
+	if item, _, _ := LoadItem(id); item.State != StateSealed {
+		t.Errorf("busy item changed to %s", item.State)
+	}
+
+	lock.unlock()
+	if _, err := Open(id); err != nil {
+		t.Errorf("expected the released item to open, got %v", err)
+	}
+}
+
+func TestDelete_RemovesItemLock(t *testing.T) {
+	_, cleanup := testutil.SetupTestEnv(t)
+	defer cleanup()
+	registerFakeAuthority()
+
+	authority := &timeauth.FakeAuthority{AuthorityName: "registered-fake", DefaultRound: 100}
+	id, err := CreateSealedItem(time.Now().UTC().Add(time.Hour), InputSourceStdin, "", []byte("data"), authority)
+	if err != nil {
+		t.Fatalf("CreateSealedItem failed: %v", err)
+	}
+	_, itemDir, _ := LoadItem(id)
+
+	if _, err := Delete(id, DeleteOptions{Force: true}); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if _, err := os.Lstat(itemLockPath(itemDir)); !os.IsNotExist(err) {
+		t.Error("lock file left behind")
+	}
+}
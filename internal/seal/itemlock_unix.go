@@ -0,0 +1,25 @@
+//go:build unix
+
+package seal
+
+import (
+	"errors"
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+// tryLockFile takes an exclusive flock on file without blocking.
+// Reports false if another process holds it.
+func tryLockFile(file *os.File) (bool, error) {
+	err := unix.Flock(int(file.Fd()), unix.LOCK_EX|unix.LOCK_NB)
+	if errors.Is(err, unix.EWOULDBLOCK) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// unlockFile releases a lock taken by tryLockFile.
+func unlockFile(file *os.File) {
+	unix.Flock(int(file.Fd()), unix.LOCK_UN)
+}
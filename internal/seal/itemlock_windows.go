@@ -0,0 +1,25 @@
+package seal
+
+import (
+	"errors"
+	"os"
+
+	"golang.org/x/sys/windows"
+)
+
+// tryLockFile takes an exclusive lock on the first byte of file without blocking.
+// Reports false if another process holds it.
+func tryLockFile(file *os.File) (bool, error) {
+	var overlapped windows.Overlapped
+	err := windows.LockFileEx(windows.Handle(file.Fd()), windows.LOCKFILE_EXCLUSIVE_LOCK|windows.LOCKFILE_FAIL_IMMEDIATELY, 0, 1, 0, &overlapped)
+	if errors.Is(err, windows.ERROR_LOCK_VIOLATION) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// unlockFile releases a lock taken by tryLockFile.
+func unlockFile(file *os.File) {
+	var overlapped windows.Overlapped
+	windows.UnlockFileEx(windows.Handle(file.Fd()), 0, 1, 0, &overlapped)
+}
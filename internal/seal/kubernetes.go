@@ -0,0 +1,123 @@
+package seal
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// defaultKubectlCommand is the Kubernetes CLI used when config names none.
+const defaultKubectlCommand = "kubectl"
+
+// kubectlFieldManager owns the fields Seal writes with server-side apply.
+const kubectlFieldManager = "seal"
+
+// defaultSecretKey is the Secret data key used when the reveal target names none.
+const defaultSecretKey = "value"
+
+// runKubectl runs the Kubernetes CLI. Replaced in tests.
+var runKubectl = runExternal
+
+var (
+	// kubernetesNamePattern matches namespace and Secret names (RFC 1123 subdomains).
+	kubernetesNamePattern = regexp.MustCompile(`^[a-z0-9]([-a-z0-9.]{0,251}[a-z0-9])?$`)
+	// secretKeyPattern matches Secret data keys.
+	secretKeyPattern = regexp.MustCompile(`^[-._a-zA-Z0-9]{1,253}$`)
+)
+
+// KubernetesConfig selects the cluster that k8s: reveal targets are written to.
+// Empty fields use kubectl's own defaults (KUBECONFIG, current context).
+type KubernetesConfig struct {
+	Kubeconfig string `json:"kubeconfig,omitempty"`
+	Context    string `json:"context,omitempty"`
+	Command    string `json:"command,omitempty"` // kubectl-compatible CLI (default kubectl)
+}
+
+// kubernetesSecretRef names a key in a Kubernetes Secret.
+type kubernetesSecretRef struct {
+	Namespace string
+	Name      string
+	Key       string
+}
+
+// parseKubernetesSecretRef parses a k8s: reveal address: <namespace>/<name>[/<key>].
+func parseKubernetesSecretRef(address string) (kubernetesSecretRef, error) {
+	parts := strings.Split(address, "/")
+	if len(parts) < 2 || len(parts) > 3 {
+		return kubernetesSecretRef{}, fmt.Errorf("invalid k8s target %q, expected <namespace>/<name>[/<key>]", address)
+	}
+
+	ref := kubernetesSecretRef{Namespace: parts[0], Name: parts[1], Key: defaultSecretKey}
+	if len(parts) == 3 {
+		ref.Key = parts[2]
+	}
+
+	if len(ref.Namespace) > 63 || !kubernetesNamePattern.MatchString(ref.Namespace) {
+		return kubernetesSecretRef{}, fmt.Errorf("invalid k8s namespace: %s", ref.Namespace)
+	}
+	if !kubernetesNamePattern.MatchString(ref.Name) {
+		return kubernetesSecretRef{}, fmt.Errorf("invalid k8s secret name: %s", ref.Name)
+	}
+	if !secretKeyPattern.MatchString(ref.Key) || ref.Key == "." || ref.Key == ".." {
+		return kubernetesSecretRef{}, fmt.Errorf("invalid k8s secret key: %s", ref.Key)
+	}
+
+	return ref, nil
+}
+
+// kubectlArgs returns the arguments that select the configured cluster.
+func kubectlArgs(cfg KubernetesConfig) []string {
+	var args []string
+	if cfg.Kubeconfig != "" {
+		args = append(args, "--kubeconfig", cfg.Kubeconfig)
+	}
+	if cfg.Context != "" {
+		args = append(args, "--context", cfg.Context)
+	}
+	return args
+}
+
+// deliverKubernetes writes unlocked content into a Kubernetes Secret with kubectl apply.
+// The Secret is created if missing; an existing Secret is updated in place, and the
+// key Seal manages is replaced. The manifest is passed on stdin. The apply is
+// server-side: a client-side apply would copy the whole Secret, content included,
+// into its last-applied-configuration annotation.
+func deliverKubernetes(item SealedItem, itemDir string, address string, cfg KubernetesConfig) error {
+	ref, err := parseKubernetesSecretRef(address)
+	if err != nil {
+		return err
+	}
+
+	content, err := os.ReadFile(UnsealedPath(item, itemDir))
+	if err != nil {
+		return fmt.Errorf("cannot read unsealed content: %w", err)
+	}
+
+	manifest, err := json.Marshal(map[string]any{
+		"apiVersion": "v1",
+		"kind":       "Secret",
+		"type":       "Opaque",
+		"metadata": map[string]any{
+			"name":      ref.Name,
+			"namespace": ref.Namespace,
+			"labels":    map[string]string{"app.kubernetes.io/managed-by": "seal"},
+		},
+		"data": map[string][]byte{ref.Key: content}, // []byte marshals as base64, as Secret data expects
+	})
+	if err != nil {
+		return fmt.Errorf("failed to build secret manifest: %w", err)
+	}
+
+	command := cfg.Command
+	if command == "" {
+		command = defaultKubectlCommand
+	}
+
+	args := append(kubectlArgs(cfg), "apply", "--server-side", "--field-manager", kubectlFieldManager, "--namespace", ref.Namespace, "-f", "-")
+	if _, err := runKubectl(command, args, manifest); err != nil {
+		return fmt.Errorf("cannot write k8s secret %s/%s: %w", ref.Namespace, ref.Name, err)
+	}
+	return nil
+}
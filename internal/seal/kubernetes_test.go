@@ -0,0 +1,115 @@
+package seal
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// stubKubectl replaces the Kubernetes CLI for the duration of a test.
+func stubKubectl(t *testing.T, err error) *[]passCall {
+	t.Helper()
+	var calls []passCall
+	orig := runKubectl
+	runKubectl = func(name string, args []string, stdin []byte) ([]byte, error) {
+		calls = append(calls, passCall{name, args, string(stdin)})
+		return nil, err
+	}
+	t.Cleanup(func() { runKubectl = orig })
+	return &calls
+}
+
+func TestParseKubernetesSecretRef(t *testing.T) {
+	tests := []struct {
+		address string
+		want    kubernetesSecretRef
+		wantErr bool
+	}{
+		{"prod/db-credentials", kubernetesSecretRef{"prod", "db-credentials", "value"}, false},
+		{"prod/db-credentials/password", kubernetesSecretRef{"prod", "db-credentials", "password"}, false},
+		{"prod/db.credentials/.env", kubernetesSecretRef{"prod", "db.credentials", ".env"}, false},
+		{"prod", kubernetesSecretRef{}, true},
+		{"prod/db/password/extra", kubernetesSecretRef{}, true},
+		{"Prod/db", kubernetesSecretRef{}, true},
+		{"prod/-db", kubernetesSecretRef{}, true},
+		{"prod/db/", kubernetesSecretRef{}, true},
+		{"prod/db/..", kubernetesSecretRef{}, true},
+		{"prod/db/pass word", kubernetesSecretRef{}, true},
+	}
+
+	for _, tt := range tests {
+		got, err := parseKubernetesSecretRef(tt.address)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseKubernetesSecretRef(%q) error = %v, wantErr %v", tt.address, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("parseKubernetesSecretRef(%q) = %+v, want %+v", tt.address, got, tt.want)
+		}
+	}
+}
+
+func TestDeliverPendingReveal_Kubernetes(t *testing.T) {
+	item, itemDir := newUnlockedRevealItem(t, "s3cr3t")
+	item.RevealTo = "k8s:prod/db-credentials/password"
+
+	calls := stubKubectl(t, nil)
+
+	cfg := Config{Kubernetes: KubernetesConfig{Kubeconfig: "/etc/seal/kubeconfig", Context: "prod"}}
+	updated, err := deliverPendingReveal(item, itemDir, cfg)
+	if err != nil {
+		t.Fatalf("deliverPendingReveal failed: %v", err)
+	}
+	if updated.RevealStatus != RevealStatusDelivered {
+		t.Errorf("expected delivered, got %s", updated.RevealStatus)
+	}
+
+	if len(*calls) != 1 {
+		t.Fatalf("expected one kubectl call, got %+v", *calls)
+	}
+	call := (*calls)[0]
+	wantArgs := []string{"--kubeconfig", "/etc/seal/kubeconfig", "--context", "prod", "apply", "--server-side", "--field-manager", "seal", "--namespace", "prod", "-f", "-"}
+	if call.name != "kubectl" || !reflect.DeepEqual(call.args, wantArgs) {
+		t.Errorf("got %s %v, want kubectl %v", call.name, call.args, wantArgs)
+	}
+	for _, arg := range call.args {
+		if strings.Contains(arg, "s3cr3t") {
+			t.Errorf("content must not be passed as an argument: %v", call.args)
+		}
+	}
+
+	var manifest struct {
+		Kind     string `json:"kind"`
+		Metadata struct {
+			Name      string `json:"name"`
+			Namespace string `json:"namespace"`
+		} `json:"metadata"`
+		Data map[string][]byte `json:"data"`
+	}
+	if err := json.Unmarshal([]byte(call.stdin), &manifest); err != nil {
+		t.Fatalf("stdin is not a JSON manifest: %v", err)
+	}
+	if manifest.Kind != "Secret" || manifest.Metadata.Namespace != "prod" || manifest.Metadata.Name != "db-credentials" {
+		t.Errorf("unexpected manifest: %s", call.stdin)
+	}
+	if string(manifest.Data["password"]) != "s3cr3t" {
+		t.Errorf("got secret data %q, want %q", manifest.Data["password"], "s3cr3t")
+	}
+}
+
+func TestDeliverPendingReveal_KubernetesFailureIsRecorded(t *testing.T) {
+	item, itemDir := newUnlockedRevealItem(t, "s3cr3t")
+	item.RevealTo = "k8s:prod/db-credentials"
+
+	stubKubectl(t, errors.New("kubectl: the server has asked for the client to provide credentials"))
+
+	updated, err := deliverPendingReveal(item, itemDir, Config{})
+	if err == nil {
+		t.Fatal("expected delivery error")
+	}
+	if updated.RevealStatus != RevealStatusFailed || !strings.Contains(updated.RevealError, "prod/db-credentials") {
+		t.Errorf("expected recorded failure, got status %s error %q", updated.RevealStatus, updated.RevealError)
+	}
+}
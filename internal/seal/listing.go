@@ -2,50 +2,247 @@ package seal
 
 import (
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"seal/internal/clock"
 )
 
-// ListSealedItems returns all sealed items, sorted by creation time (oldest first).
-func ListSealedItems() ([]SealedItem, error) {
-	baseDir, err := GetSealBaseDir()
+// walkBatchSize is the number of directory entries read at a time by WalkSealedItems.
+const walkBatchSize = 256
+
+// Sort orders for ListOptions.
+const (
+	SortCreatedAt  = "created_at"  // oldest first (default)
+	SortUnlockTime = "unlock_time" // soonest first
+)
+
+// ListOptions selects and orders the items returned by ListSealedItems.
+// The zero value lists every item, oldest first.
+type ListOptions struct {
+	State  string            // only items in this state (StateSealed or StateUnlocked); empty for all
+	Before time.Time         // only items whose unlock time is before this (zero = no bound)
+	After  time.Time         // only items whose unlock time is after this (zero = no bound)
+	Tags   map[string]string // only items carrying these tags, see MatchesTags
+	Sort   string            // SortCreatedAt (default) or SortUnlockTime
+	Limit  int               // at most this many items, after sorting (0 = no limit)
+}
+
+// Validate checks the state and sort order.
+func (o ListOptions) Validate() error {
+	switch o.State {
+	case "", StateSealed, StateUnlocked:
+	default:
+		return fmt.Errorf("invalid state %q, expected %s or %s", o.State, StateSealed, StateUnlocked)
+	}
+	switch o.Sort {
+	case "", SortCreatedAt, SortUnlockTime:
+	default:
+		return fmt.Errorf("invalid sort order %q, expected %s or %s", o.Sort, SortCreatedAt, SortUnlockTime)
+	}
+	if o.Limit < 0 {
+		return fmt.Errorf("invalid limit %d", o.Limit)
+	}
+	return nil
+}
+
+// Matches reports whether an item passes the filters. Sorting and the limit
+// apply to a whole listing, see Apply.
+func (o ListOptions) Matches(item SealedItem) bool {
+	if o.State != "" && item.State != o.State {
+		return false
+	}
+	if !o.Before.IsZero() && !item.UnlockTime.Before(o.Before) {
+		return false
+	}
+	if !o.After.IsZero() && !item.UnlockTime.After(o.After) {
+		return false
+	}
+	return MatchesTags(item, o.Tags)
+}
+
+// Apply filters, sorts and limits items, see ListOptions.
+// Items with equal sort keys are ordered by ID, so the result is stable across runs.
+func (o ListOptions) Apply(items []SealedItem) []SealedItem {
+	matched := make([]SealedItem, 0, len(items))
+	for _, item := range items {
+		if o.Matches(item) {
+			matched = append(matched, item)
+		}
+	}
+
+	key := func(item SealedItem) time.Time { return item.CreatedAt }
+	if o.Sort == SortUnlockTime {
+		key = func(item SealedItem) time.Time { return item.UnlockTime }
+	}
+	sort.Slice(matched, func(i, j int) bool {
+		ki, kj := key(matched[i]), key(matched[j])
+		if !ki.Equal(kj) {
+			return ki.Before(kj)
+		}
+		return matched[i].ID < matched[j].ID
+	})
+
+	if o.Limit > 0 && len(matched) > o.Limit {
+		matched = matched[:o.Limit]
+	}
+	return matched
+}
+
+// ParseListTime parses a --before or --after bound: an RFC3339 timestamp,
+// or a duration from now with a leading + (e.g. +7d), see ParseLockDuration.
+func ParseListTime(s string) (time.Time, error) {
+	if rel, ok := strings.CutPrefix(s, "+"); ok {
+		return unlockAfter(rel)
+	}
+	t, err := clock.ParseRFC3339(s)
 	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid time %q, expected RFC3339 or +<duration> (e.g. +7d)", s)
+	}
+	return t, nil
+}
+
+// ListSealedItems returns the items selected by opts, sorted by creation time
+// (oldest first) unless opts says otherwise.
+func ListSealedItems(opts ListOptions) ([]SealedItem, error) {
+	if err := opts.Validate(); err != nil {
 		return nil, err
 	}
 
-	// Check if base directory exists
-	if _, err := os.Stat(baseDir); os.IsNotExist(err) {
-		return []SealedItem{}, nil // No items yet
+	var items []SealedItem
+	err := WalkSealedItems(func(item SealedItem, itemDir string) error {
+		// ListSealedItems is read-only: return persisted state without materialization
+		// Recovery of pending transactions happens in status flow (write-enabled)
+		if opts.Matches(item) {
+			items = append(items, item)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return opts.Apply(items), nil
+}
+
+// WalkSealedItems calls fn for each item in the store, in directory order.
+// The directory is read in batches, so memory use does not grow with the store.
+// Invalid items are skipped. An error from fn stops the walk and is returned.
+func WalkSealedItems(fn func(item SealedItem, itemDir string) error) error {
+	return walkStoreDirs(func(itemDir string) error {
+		item, err := loadMetadata(itemDir)
+		if err != nil {
+			// Skip invalid items
+			return nil
+		}
+		return fn(item, itemDir)
+	})
+}
+
+// walkStoreDirs calls fn for each directory in the store, in directory order,
+// reading the store in batches. An error from fn stops the walk and is returned.
+func walkStoreDirs(fn func(itemDir string) error) error {
+	baseDir, err := GetSealBaseDir()
+	if err != nil {
+		return err
+	}
+
+	dir, err := os.Open(baseDir)
+	if os.IsNotExist(err) {
+		return nil // No items yet
+	}
+	if err != nil {
+		return fmt.Errorf("cannot read seal directory: %w", err)
+	}
+	defer dir.Close()
+
+	for {
+		entries, err := dir.ReadDir(walkBatchSize)
+		for _, entry := range entries {
+			if !entry.IsDir() {
+				continue
+			}
+
+			if err := fn(filepath.Join(baseDir, entry.Name())); err != nil {
+				return err
+			}
+		}
+
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("cannot read seal directory: %w", err)
+		}
 	}
+}
 
+// ItemPage is one page of a paginated listing.
+type ItemPage struct {
+	Items     []SealedItem
+	NextToken string // pass to ListSealedItemsPage for the next page; empty on the last page
+}
+
+// ListSealedItemsPage returns up to pageSize items following the continuation
+// token (empty for the first page). Items are ordered by ID so pages stay
+// stable while new items are added. Only the page's metadata is loaded.
+// Tokens are opaque to callers.
+func ListSealedItemsPage(token string, pageSize int) (ItemPage, error) {
+	if pageSize <= 0 {
+		return ItemPage{}, fmt.Errorf("page size must be positive")
+	}
+
+	if token != "" {
+		if _, err := uuid.Parse(token); err != nil {
+			return ItemPage{}, fmt.Errorf("invalid page token: %s", token)
+		}
+	}
+
+	baseDir, err := GetSealBaseDir()
+	if err != nil {
+		return ItemPage{}, err
+	}
+
+	// Entries are sorted by name, i.e. by item ID
 	entries, err := os.ReadDir(baseDir)
+	if os.IsNotExist(err) {
+		return ItemPage{Items: []SealedItem{}}, nil // No items yet
+	}
 	if err != nil {
-		return nil, fmt.Errorf("cannot read seal directory: %w", err)
+		return ItemPage{}, fmt.Errorf("cannot read seal directory: %w", err)
 	}
 
-	var items []SealedItem
-	for _, entry := range entries {
+	start := sort.Search(len(entries), func(i int) bool {
+		return entries[i].Name() > token
+	})
+
+	page := ItemPage{Items: []SealedItem{}}
+	var lastName string
+	for _, entry := range entries[start:] {
 		if !entry.IsDir() {
 			continue
 		}
 
-		itemDir := filepath.Join(baseDir, entry.Name())
-		item, err := loadMetadata(itemDir)
+		item, err := loadMetadata(filepath.Join(baseDir, entry.Name()))
 		if err != nil {
 			// Skip invalid items
 			continue
 		}
 
-		// ListSealedItems is read-only: return persisted state without materialization
-		// Recovery of pending transactions happens in status flow (write-enabled)
-		items = append(items, item)
-	}
+		// A further valid item exists, so this page is not the last
+		if len(page.Items) == pageSize {
+			page.NextToken = lastName
+			break
+		}
 
-	// Sort by creation time (oldest first)
-	sort.Slice(items, func(i, j int) bool {
-		return items[i].CreatedAt.Before(items[j].CreatedAt)
-	})
+		page.Items = append(page.Items, item)
+		lastName = entry.Name()
+	}
 
-	return items, nil
+	return page, nil
 }
@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"seal/internal/testutil"
+	"seal/internal/timeauth"
 )
 
 func TestListSealedItems_Empty(t *testing.T) {
@@ -17,7 +18,7 @@ func TestListSealedItems_Empty(t *testing.T) {
 
 	_ = tmpHome
 
-	items, err := ListSealedItems()
+	items, err := ListSealedItems(ListOptions{})
 	if err != nil {
 		t.Fatalf("expected no error, got: %v", err)
 	}
@@ -53,7 +54,7 @@ func TestListSealedItems_MultipleSorted(t *testing.T) {
 		time.Sleep(10 * time.Millisecond) // Ensure distinct timestamps
 	}
 
-	items, err := ListSealedItems()
+	items, err := ListSealedItems(ListOptions{})
 	if err != nil {
 		t.Fatalf("listSealedItems failed: %v", err)
 	}
@@ -100,7 +101,7 @@ func TestListingDoesNotMaterialize(t *testing.T) {
 	unsealedPath := filepath.Join(itemDir, "unsealed")
 
 	// Call ListSealedItems (read-only operation)
-	items, err := ListSealedItems()
+	items, err := ListSealedItems(ListOptions{})
 	if err != nil {
 		t.Fatalf("ListSealedItems failed: %v", err)
 	}
@@ -129,3 +130,150 @@ func TestListingDoesNotMaterialize(t *testing.T) {
 		t.Error("metadata should still show sealed state")
 	}
 }
+
+func TestListSealedItemsPage_WalksAllItems(t *testing.T) {
+	_, cleanup := testutil.SetupTestEnv(t)
+	defer cleanup()
+
+	authority := &timeauth.FakeAuthority{DefaultRound: 100}
+	created := make(map[string]bool)
+	for i := 0; i < 5; i++ {
+		id, err := CreateSealedItem(time.Now().UTC().Add(time.Hour), InputSourceStdin, "", []byte("data"), authority)
+		if err != nil {
+			t.Fatalf("CreateSealedItem failed: %v", err)
+		}
+		created[id] = true
+	}
+
+	seen := make(map[string]bool)
+	var pages int
+	token := ""
+	for {
+		page, err := ListSealedItemsPage(token, 2)
+		if err != nil {
+			t.Fatalf("ListSealedItemsPage failed: %v", err)
+		}
+		pages++
+
+		if len(page.Items) > 2 {
+			t.Fatalf("page exceeds page size: %d items", len(page.Items))
+		}
+		for _, item := range page.Items {
+			if seen[item.ID] {
+				t.Errorf("item %s returned twice", item.ID)
+			}
+			seen[item.ID] = true
+		}
+
+		if page.NextToken == "" {
+			break
+		}
+		token = page.NextToken
+	}
+
+	if pages != 3 {
+		t.Errorf("expected 3 pages for 5 items, got %d", pages)
+	}
+	for id := range created {
+		if !seen[id] {
+			t.Errorf("item %s missing from pages", id)
+		}
+	}
+}
+
+func TestListSealedItemsPage_InvalidArguments(t *testing.T) {
+	_, cleanup := testutil.SetupTestEnv(t)
+	defer cleanup()
+
+	if _, err := ListSealedItemsPage("", 0); err == nil {
+		t.Error("expected error for zero page size")
+	}
+	if _, err := ListSealedItemsPage("../escape", 10); err == nil {
+		t.Error("expected error for malformed token")
+	}
+
+	page, err := ListSealedItemsPage("", 10)
+	if err != nil {
+		t.Fatalf("empty store should list without error: %v", err)
+	}
+	if len(page.Items) != 0 || page.NextToken != "" {
+		t.Errorf("expected empty last page, got %+v", page)
+	}
+}
+
+func TestListOptions_Apply(t *testing.T) {
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	items := []SealedItem{
+		{ID: "a", State: StateSealed, CreatedAt: base, UnlockTime: base.Add(72 * time.Hour), Tags: map[string]string{"team": "ops"}},
+		{ID: "b", State: StateUnlocked, CreatedAt: base.Add(time.Hour), UnlockTime: base.Add(2 * time.Hour)},
+		{ID: "c", State: StateSealed, CreatedAt: base.Add(2 * time.Hour), UnlockTime: base.Add(24 * time.Hour), Tags: map[string]string{"team": "ops"}},
+		{ID: "d", State: StateSealed, CreatedAt: base.Add(2 * time.Hour), UnlockTime: base.Add(48 * time.Hour)},
+	}
+
+	ids := func(items []SealedItem) string {
+		var out []string
+		for _, item := range items {
+			out = append(out, item.ID)
+		}
+		return strings.Join(out, ",")
+	}
+
+	testCases := []struct {
+		name string
+		opts ListOptions
+		want string
+	}{
+		{"default", ListOptions{}, "a,b,c,d"},
+		{"state", ListOptions{State: StateSealed}, "a,c,d"},
+		{"before", ListOptions{Before: base.Add(48 * time.Hour)}, "b,c"},
+		{"after", ListOptions{After: base.Add(24 * time.Hour)}, "a,d"},
+		{"tags", ListOptions{Tags: map[string]string{"team": "ops"}}, "a,c"},
+		{"sort by unlock time", ListOptions{Sort: SortUnlockTime}, "b,c,d,a"},
+		{"limit after sorting", ListOptions{State: StateSealed, Sort: SortUnlockTime, Limit: 2}, "c,d"},
+	}
+	for _, tc := range testCases {
+		if got := ids(tc.opts.Apply(items)); got != tc.want {
+			t.Errorf("%s: got %s, want %s", tc.name, got, tc.want)
+		}
+	}
+
+	for _, opts := range []ListOptions{{State: "open"}, {Sort: "size"}, {Limit: -1}} {
+		if err := opts.Validate(); err == nil {
+			t.Errorf("expected %+v to be invalid", opts)
+		}
+	}
+}
+
+func TestListSealedItems_Options(t *testing.T) {
+	_, cleanup := testutil.SetupTestEnv(t)
+	defer cleanup()
+
+	authority := &timeauth.FakeAuthority{DefaultRound: 100}
+	now := time.Now().UTC()
+	soon, _ := CreateSealedItem(now.Add(time.Hour), InputSourceStdin, "", []byte("soon"), authority)
+	later, _ := CreateSealedItem(now.Add(48*time.Hour), InputSourceStdin, "", []byte("later"), authority)
+
+	items, err := ListSealedItems(ListOptions{After: now.Add(24 * time.Hour)})
+	if err != nil || len(items) != 1 || items[0].ID != later {
+		t.Errorf("expected only the later item, got %v, %v", items, err)
+	}
+	items, err = ListSealedItems(ListOptions{Sort: SortUnlockTime, Limit: 1})
+	if err != nil || len(items) != 1 || items[0].ID != soon {
+		t.Errorf("expected only the sooner item, got %v, %v", items, err)
+	}
+	if _, err := ListSealedItems(ListOptions{State: "open"}); err == nil {
+		t.Error("expected an invalid state to be refused")
+	}
+}
+
+func TestParseListTime(t *testing.T) {
+	if got, err := ParseListTime("2020-01-01T00:00:00Z"); err != nil || !got.Equal(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("past timestamps are valid bounds, got %v, %v", got, err)
+	}
+	if got, err := ParseListTime("+7d"); err != nil || got.Sub(time.Now()) < 6*24*time.Hour {
+		t.Errorf("expected a time a week from now, got %v, %v", got, err)
+	}
+	if _, err := ParseListTime("next week"); err == nil {
+		t.Error("expected an invalid time to be refused")
+	}
+}
@@ -4,33 +4,111 @@ import (
 	"context"
 	"crypto/aes"
 	"crypto/cipher"
+	"crypto/sha256"
 	"encoding/base64"
-	"encoding/json"
+	"encoding/hex"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
-	"strconv"
+	"time"
 
+	"seal/internal/clock"
 	"seal/internal/timeauth"
 )
 
 // extractTargetRound parses the target round from a key reference.
 // Supports both legacy drand JSON format and simple numeric format.
 func extractTargetRound(keyRef string) (uint64, error) {
-	// Try parsing as simple number first
-	if round, err := strconv.ParseUint(keyRef, 10, 64); err == nil {
-		return round, nil
+	return timeauth.TargetRound(timeauth.KeyReference(keyRef))
+}
+
+// UnsealedPath returns where an item's decrypted content is materialized:
+// <itemDir>/unsealed, or <unseal_to>/<id> if a destination was given at lock time.
+// The pending file of the two-phase commit lives next to it, so the final
+// rename never crosses filesystems.
+func UnsealedPath(item SealedItem, itemDir string) string {
+	if item.UnsealTo != "" {
+		return filepath.Join(item.UnsealTo, item.ID)
 	}
+	return filepath.Join(itemDir, "unsealed")
+}
+
+// payloadChecksum returns the hex-encoded SHA-256 of an encrypted payload.
+func payloadChecksum(ciphertext []byte) string {
+	sum := sha256.Sum256(ciphertext)
+	return hex.EncodeToString(sum[:])
+}
 
-	// Try parsing as drand JSON format
-	var drandRef struct {
-		TargetRound uint64 `json:"target_round"`
+// Backoff bounds for retrying failed unlock attempts.
+const (
+	unlockBackoffBase = 30 * time.Second
+	unlockBackoffMax  = time.Hour
+)
+
+// unlockBackoff returns the delay before the next attempt after the given
+// number of consecutive failures: 30s, 1m, 2m, ... capped at one hour.
+func unlockBackoff(failures int) time.Duration {
+	backoff := unlockBackoffBase
+	for i := 1; i < failures && backoff < unlockBackoffMax; i++ {
+		backoff *= 2
 	}
-	if err := json.Unmarshal([]byte(keyRef), &drandRef); err != nil {
-		return 0, fmt.Errorf("failed to parse key reference: %w", err)
+	return min(backoff, unlockBackoffMax)
+}
+
+// recordUnlockFailure records a failed unlock attempt and schedules the next one.
+// Persisting the backoff is best-effort: if it cannot be saved, the next run retries immediately.
+// A cancelled caller is not a failure of the authority and is not recorded.
+// The returned error carries the cause for the status run; TryMaterialize drops it.
+func recordUnlockFailure(item SealedItem, itemDir string, cause error) (SealedItem, error) {
+	if errors.Is(cause, context.Canceled) {
+		return item, nil
 	}
+	item.UnlockFailures++
+	item.LastUnlockError = cause.Error()
+	next := clock.UTC().Add(unlockBackoff(item.UnlockFailures))
+	item.NextUnlockAttempt = &next
+
+	saveMetadata(itemDir, item)
+	return item, &unlockFailure{cause}
+}
+
+// unlockFailure is an unlock attempt recorded on the item to be retried after backoff.
+type unlockFailure struct {
+	cause error
+}
+
+func (e *unlockFailure) Error() string { return e.cause.Error() }
+func (e *unlockFailure) Unwrap() error { return e.cause }
+
+// stuckCommitAge is how long a committed pending file may fail to finalize
+// before status stops retrying quietly and asks for manual repair.
+const stuckCommitAge = time.Hour
+
+// stuckCommitError reports an unlocked item whose pending file keeps failing
+// to be renamed into place. The content is intact in the pending file.
+type stuckCommitError struct {
+	id           string
+	pendingPath  string
+	unsealedPath string
+	since        time.Time
+	err          error
+}
 
-	return drandRef.TargetRound, nil
+func (e *stuckCommitError) Error() string {
+	return fmt.Sprintf("item %s: unlocked content has been stuck in %s since %s: %v",
+		e.id, e.pendingPath, e.since.UTC().Format(time.RFC3339), e.err)
+}
+
+func (e *stuckCommitError) Unwrap() error {
+	return e.err
+}
+
+// remediation describes how to finish the commit by hand.
+func (e *stuckCommitError) remediation() string {
+	return fmt.Sprintf("check permissions and free space in %s, or move %s to %s by hand",
+		filepath.Dir(e.unsealedPath), e.pendingPath, e.unsealedPath)
 }
 
 // recoverPendingUnseal handles incomplete unseal transactions.
@@ -38,15 +116,21 @@ func extractTargetRound(keyRef string) (uint64, error) {
 //   - If state=unlocked: complete the transaction (rename pending → unsealed)
 //   - If state=sealed: abort the transaction (remove pending)
 func recoverPendingUnseal(item SealedItem, itemDir string) error {
-	pendingPath := filepath.Join(itemDir, "unsealed.pending")
-	unsealedPath := filepath.Join(itemDir, "unsealed")
+	unsealedPath := UnsealedPath(item, itemDir)
+	pendingPath := unsealedPath + ".pending"
 
 	// Check if pending file exists
-	if _, err := os.Stat(pendingPath); os.IsNotExist(err) {
+	pendingInfo, err := os.Lstat(pendingPath)
+	if os.IsNotExist(err) {
 		// No pending transaction
 		return nil
 	}
 
+	// Seal only ever writes a regular pending file; never move anything else into place
+	if err == nil && !pendingInfo.Mode().IsRegular() {
+		return fmt.Errorf("pending file %s is not a regular file (possible symlink attack)", pendingPath)
+	}
+
 	switch item.State {
 	case StateUnlocked:
 		// Transaction was committed but rename didn't complete
@@ -57,6 +141,17 @@ func recoverPendingUnseal(item SealedItem, itemDir string) error {
 				os.Remove(pendingPath)
 				return nil
 			}
+
+			// A pending file that has outlived many runs will not finalize on its own
+			if pendingInfo != nil && clock.Since(pendingInfo.ModTime()) >= stuckCommitAge {
+				return &stuckCommitError{
+					id:           item.ID,
+					pendingPath:  pendingPath,
+					unsealedPath: unsealedPath,
+					since:        pendingInfo.ModTime(),
+					err:          err,
+				}
+			}
 			return fmt.Errorf("failed to recover pending unseal: %w", err)
 		}
 		return nil
@@ -77,9 +172,26 @@ func recoverPendingUnseal(item SealedItem, itemDir string) error {
 // Materialization is passive - it only occurs when Seal code executes (e.g., seal status).
 // Returns the item (potentially with updated state) and any error.
 //
-// Decrypted data is written to: <itemDir>/unsealed
+// Decrypted data is written to UnsealedPath: <itemDir>/unsealed by default.
 // This path must not exist while the item is in StateSealed state.
+//
+// A failed unlock attempt is recorded on the item and is not an error.
 func TryMaterialize(item SealedItem, itemDir string, authority timeauth.Authority) (SealedItem, error) {
+	item, err := materialize(item, itemDir, authority)
+	var failed *unlockFailure
+	if errors.As(err, &failed) {
+		return item, nil
+	}
+	return item, err
+}
+
+// materialize is TryMaterialize, returning a recorded unlock failure as *unlockFailure.
+func materialize(item SealedItem, itemDir string, authority timeauth.Authority) (SealedItem, error) {
+	// Refuse to write anything through a symlinked item directory
+	if err := checkItemDir(itemDir); err != nil {
+		return item, err
+	}
+
 	// Recover any incomplete transactions first
 	if err := recoverPendingUnseal(item, itemDir); err != nil {
 		return item, fmt.Errorf("failed to recover pending transaction: %w", err)
@@ -90,8 +202,8 @@ func TryMaterialize(item SealedItem, itemDir string, authority timeauth.Authorit
 		return item, nil
 	}
 
-	// Verify tlock-encrypted DEK exists
-	if item.DEKTlockB64 == "" {
+	// Verify tlock-encrypted DEK (or tlock file payload) exists
+	if !hasTimeLock(item) {
 		// No encrypted DEK - this authority doesn't support time-lock encryption
 		return item, nil
 	}
@@ -104,11 +216,16 @@ func TryMaterialize(item SealedItem, itemDir string, authority timeauth.Authorit
 		return item, nil
 	}
 
+	// Back off after recent failures so a flaky network is not hit on every run
+	if item.NextUnlockAttempt != nil && clock.UTC().Before(*item.NextUnlockAttempt) {
+		return item, nil
+	}
+
 	// Check if the target round has been reached
 	canUnlock, err := authority.CanUnlock(context.Background(), targetRound)
 	if err != nil {
-		// Network failure - do not unlock
-		return item, nil
+		// Network failure - do not unlock, retry after backoff
+		return recordUnlockFailure(item, itemDir, err)
 	}
 
 	if !canUnlock {
@@ -116,48 +233,125 @@ func TryMaterialize(item SealedItem, itemDir string, authority timeauth.Authorit
 		return item, nil
 	}
 
-	// Decrypt DEK using time-lock decryption (fetches randomness for target round)
-	dek, err := authority.TimeLockDecrypt(context.Background(), item.DEKTlockB64)
+	// Streamed payloads are decrypted chunk by chunk, never held in memory
+	if isStreamed(item) {
+		return materializeStreamed(item, itemDir, authority, targetRound)
+	}
+
+	// Read encrypted payload and verify it before fetching beacon randomness,
+	// so a corrupt payload is reported as such rather than as a decryption failure
+	// The ciphertext and plaintext buffers are held within the process-wide memory budget
+	payloadPath := filepath.Join(itemDir, "payload.bin")
+	info, err := os.Stat(payloadPath)
 	if err != nil {
-		// Decryption failure (too early or network error) - do not unlock
-		return item, nil
+		return item, fmt.Errorf("failed to read payload: %w", err)
 	}
-	defer func() {
+	defer payloadBudget.release(payloadBudget.acquire(2 * info.Size()))
+
+	ciphertext, err := readPayload(payloadPath, getPayloadBuffer(int(info.Size())), info.Size())
+	if err != nil {
+		return item, fmt.Errorf("failed to read payload: %w", err)
+	}
+	defer putPayloadBuffer(ciphertext)
+
+	if item.PayloadSHA256 != "" && payloadChecksum(ciphertext) != item.PayloadSHA256 {
+		return item, fmt.Errorf("item %s: payload checksum mismatch (corrupted)", item.ID)
+	}
+
+	var plaintext []byte
+	if isTlockFile(item) {
+		// A tlock file decrypts directly to its content
+		plaintext, err = authority.TimeLockDecrypt(context.Background(), base64.StdEncoding.EncodeToString(ciphertext))
+		if err != nil {
+			return recordUnlockFailure(item, itemDir, err)
+		}
+	} else {
+		dek, err := unlockDEK(item, authority)
+		if err != nil {
+			// Decryption failure (too early or network error) - do not unlock, retry after backoff
+			return recordUnlockFailure(item, itemDir, err)
+		}
+
+		plaintext, err = decryptPayload(item, getPayloadBuffer(len(ciphertext)), ciphertext, dek)
+		if err == nil {
+			defer putPayloadBuffer(plaintext)
+		}
+
 		// Zero out DEK from memory
 		for i := range dek {
 			dek[i] = 0
 		}
-	}()
 
-	// Read encrypted payload
-	payloadPath := filepath.Join(itemDir, "payload.bin")
-	ciphertext, err := os.ReadFile(payloadPath)
-	if err != nil {
-		return item, fmt.Errorf("failed to read payload: %w", err)
+		if err != nil {
+			return item, err
+		}
 	}
 
-	// Decode nonce
-	nonce, err := base64.StdEncoding.DecodeString(item.Nonce)
-	if err != nil {
-		return item, fmt.Errorf("failed to decode nonce: %w", err)
+	return commitUnsealed(item, itemDir, targetRound, int64(len(plaintext)), func(pendingPath string) (string, error) {
+		return payloadChecksum(plaintext), writeFileNoFollow(pendingPath, plaintext, 0600)
+	})
+}
+
+// materializeStreamed decrypts a streamed payload into the unsealed file chunk by chunk.
+func materializeStreamed(item SealedItem, itemDir string, authority timeauth.Authority, targetRound uint64) (SealedItem, error) {
+	// Verify the payload before fetching beacon randomness, as for in-memory payloads
+	payloadPath := filepath.Join(itemDir, "payload.bin")
+	if item.PayloadSHA256 != "" {
+		sum, err := fileSHA256(payloadPath)
+		if err != nil {
+			return item, fmt.Errorf("failed to read payload: %w", err)
+		}
+		if sum != item.PayloadSHA256 {
+			return item, fmt.Errorf("item %s: payload checksum mismatch (corrupted)", item.ID)
+		}
 	}
 
-	// Decrypt payload
-	block, err := aes.NewCipher(dek)
+	dek, err := unlockDEK(item, authority)
 	if err != nil {
-		return item, fmt.Errorf("failed to create cipher: %w", err)
+		// Decryption failure (too early or network error) - do not unlock, retry after backoff
+		return recordUnlockFailure(item, itemDir, err)
 	}
+	defer func() {
+		for i := range dek {
+			dek[i] = 0
+		}
+	}()
 
-	gcm, err := cipher.NewGCM(block)
+	return commitUnsealed(item, itemDir, targetRound, item.PlaintextSize, func(pendingPath string) (string, error) {
+		payload, err := os.Open(payloadPath)
+		if err != nil {
+			return "", fmt.Errorf("failed to read payload: %w", err)
+		}
+		defer payload.Close()
+
+		var sums streamSums
+		err = writeStreamNoFollow(pendingPath, 0600, func(w io.Writer) error {
+			var err error
+			sums, err = decryptStream(w, payload, item, dek)
+			return err
+		})
+		return sums.checksum, err
+	})
+}
+
+// unlockDEK recovers an item's DEK: time-lock decryption (fetches randomness for the
+// target round), then Vault unwrapping if the key is also wrapped by a transit key.
+func unlockDEK(item SealedItem, authority timeauth.Authority) ([]byte, error) {
+	dek, err := authority.TimeLockDecrypt(context.Background(), item.DEKTlockB64)
 	if err != nil {
-		return item, fmt.Errorf("failed to create GCM: %w", err)
+		return nil, err
 	}
 
-	plaintext, err := gcm.Open(nil, nonce, ciphertext, nil)
-	if err != nil {
-		return item, fmt.Errorf("failed to decrypt payload: %w", err)
+	// The time-locked key is itself wrapped by Vault; no access means retry after backoff
+	if item.VaultWrap != "" {
+		return unwrapVaultDEK(item, dek)
 	}
+	return dek, nil
+}
 
+// commitUnsealed writes unlocked content with writePending and commits the unlock.
+// writePending creates the pending file and returns the SHA-256 of the content it wrote.
+func commitUnsealed(item SealedItem, itemDir string, targetRound uint64, size int64, writePending func(pendingPath string) (string, error)) (SealedItem, error) {
 	// Two-phase commit protocol for crash-safety:
 	// Phase 1: Write unsealed data with .pending suffix (not yet committed)
 	// Phase 2: Update metadata to unlocked, then rename .pending to final name
@@ -167,35 +361,44 @@ func TryMaterialize(item SealedItem, itemDir string, authority timeauth.Authorit
 	// - If crash after metadata update: .pending exists and state=unlocked (will be recovered)
 	// - If crash after rename: unsealed exists and state=unlocked (fully committed)
 
-	unsealedPath := filepath.Join(itemDir, "unsealed")
+	unsealedPath := UnsealedPath(item, itemDir)
 	pendingPath := unsealedPath + ".pending"
 
-	// Phase 1: Write unsealed data to pending location
-	if err := os.WriteFile(pendingPath, plaintext, 0600); err != nil {
-		return item, fmt.Errorf("failed to write unsealed data: %w", err)
+	// Never overwrite a file the user placed at an external destination
+	if item.UnsealTo != "" {
+		if _, err := os.Lstat(unsealedPath); err == nil {
+			return item, fmt.Errorf("destination already exists: %s", unsealedPath)
+		}
 	}
 
-	// Sync pending file to disk
-	pendingFile, err := os.OpenFile(pendingPath, os.O_RDONLY, 0)
-	if err != nil {
-		os.Remove(pendingPath)
-		return item, fmt.Errorf("failed to open unsealed data for sync: %w", err)
+	// Refuse up front rather than partially writing onto a full or read-only volume
+	if err := checkMaterializeTarget(filepath.Dir(unsealedPath), size); err != nil {
+		return item, err
 	}
-	if err := pendingFile.Sync(); err != nil {
-		pendingFile.Close()
-		os.Remove(pendingPath)
-		return item, fmt.Errorf("failed to sync unsealed data: %w", err)
+
+	// Phase 1: Write unsealed data to pending location and sync it to disk
+	// Never follows a symlink and never leaves a partial pending file behind
+	unsealedSHA256, err := writePending(pendingPath)
+	if err != nil {
+		return item, fmt.Errorf("failed to write unsealed data: %w", err)
 	}
-	pendingFile.Close()
 
 	// Phase 2: Commit transaction
 	// First, update metadata to unlocked (this is the commit point)
+	sealedItem := item
+	unlockedAt := clock.UTC()
 	item.State = StateUnlocked
+	item.UnlockedAt = &unlockedAt
+	item.UnlockRound = targetRound
+	item.UnsealedSHA256 = unsealedSHA256
+	item.UnlockFailures = 0
+	item.LastUnlockError = ""
+	item.NextUnlockAttempt = nil
+	appendHistory(&item, HistoryUnlocked, fmt.Sprintf("round %d", targetRound))
 	if err := saveMetadata(itemDir, item); err != nil {
 		// If metadata update fails, remove pending file and stay sealed
 		os.Remove(pendingPath)
-		item.State = StateSealed
-		return item, err
+		return sealedItem, err
 	}
 
 	// Then, atomically rename pending to final location
@@ -221,13 +424,97 @@ func CheckAndTransitionUnlock(item SealedItem, itemDir string) (SealedItem, erro
 	}
 
 	// Get authority based on item metadata
-	var authority timeauth.Authority
-	if item.TimeAuthority == "drand" {
-		authority = timeauth.NewDefaultAuthority()
-	} else {
+	authority := authorityForItem(item)
+	if authority == nil {
 		// Placeholder or unknown authority - no materialization
 		return item, nil
 	}
 
 	return TryMaterialize(item, itemDir, authority)
 }
+
+// authorityForItem returns the registered time authority recorded in item metadata,
+// set up from the item's key reference (a drand item unlocks on its recorded network).
+// Returns nil for placeholder or unknown authorities, which never unlock.
+func authorityForItem(item SealedItem) timeauth.Authority {
+	return timeauth.AuthorityForRef(item.TimeAuthority, timeauth.KeyReference(item.KeyRef))
+}
+
+// permanentLockReason explains why a sealed item can never unlock, or returns "" if it can.
+// Such items are a data-loss trap: nothing Seal does will ever open them.
+func permanentLockReason(item SealedItem) string {
+	if item.State != StateSealed {
+		return ""
+	}
+	switch {
+	case item.TimeAuthority == "placeholder":
+		return "placeholder authority"
+	case !hasTimeLock(item):
+		return "no time-locked key"
+	}
+	return ""
+}
+
+// unwrapVaultDEK unwraps a Vault-wrapped DEK using the Vault server from config.
+func unwrapVaultDEK(item SealedItem, wrapped []byte) ([]byte, error) {
+	cfg, err := LoadConfig()
+	if err != nil {
+		return nil, err
+	}
+	return vaultUnwrapDEK(item.VaultWrap, wrapped, cfg.Vault)
+}
+
+// readPayload reads a payload file of the given size into buf, reusing its capacity.
+// A file that changed size since it was measured is an error.
+func readPayload(path string, buf []byte, size int64) ([]byte, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	if int64(cap(buf)) < size {
+		buf = make([]byte, 0, size)
+	}
+	buf = buf[:size]
+	if _, err := io.ReadFull(f, buf); err != nil {
+		return nil, err
+	}
+	if n, _ := f.Read(make([]byte, 1)); n != 0 {
+		return nil, errors.New("payload changed while reading")
+	}
+	return buf, nil
+}
+
+// decryptPayload decrypts an item's AES-256-GCM payload with its DEK, appending the plaintext to dst.
+func decryptPayload(item SealedItem, dst, ciphertext, dek []byte) ([]byte, error) {
+	nonce, err := base64.StdEncoding.DecodeString(item.Nonce)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode nonce: %w", err)
+	}
+
+	block, err := aes.NewCipher(dek)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create GCM: %w", err)
+	}
+
+	aad, err := itemAAD(item)
+	if err != nil {
+		return nil, fmt.Errorf("item %s: %w", item.ID, err)
+	}
+
+	plaintext, err := gcm.Open(dst, nonce, ciphertext, aad)
+	if err != nil {
+		if aad != nil {
+			return nil, fmt.Errorf("item %s: failed to decrypt payload: payload does not belong to this metadata (swapped or edited): %w", item.ID, err)
+		}
+		return nil, fmt.Errorf("failed to decrypt payload: %w", err)
+	}
+
+	return plaintext, nil
+}
@@ -2,11 +2,14 @@ package seal
 
 import (
 	"bytes"
+	"errors"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
+	"seal/internal/clock"
 	"seal/internal/testutil"
 	"seal/internal/timeauth"
 )
@@ -87,7 +90,7 @@ func TestUnsealedPath_NeverCreated(t *testing.T) {
 	}
 
 	// List items (which calls checkAndTransitionUnlock)
-	items, err := ListSealedItems()
+	items, err := ListSealedItems(ListOptions{})
 	if err != nil {
 		t.Fatalf("listSealedItems failed: %v", err)
 	}
@@ -300,3 +303,270 @@ func TestMaterialize_AtomicCommit(t *testing.T) {
 		t.Error("unsealed data should match original plaintext")
 	}
 }
+
+func TestMaterialize_UnsealTo_WritesOutsideStore(t *testing.T) {
+	_, cleanup := testutil.SetupTestEnv(t)
+	defer cleanup()
+
+	destDir := t.TempDir()
+	unsealTo, err := ResolveUnsealTo(destDir)
+	if err != nil {
+		t.Fatalf("ResolveUnsealTo failed: %v", err)
+	}
+
+	authority := &timeauth.FakeAuthority{DefaultRound: 100, CurrentRound: 200}
+	id, err := CreateSealedItemWithOptions(time.Now().UTC().Add(time.Hour), InputSourceStdin, "", []byte("project data"), authority, ItemOptions{
+		UnsealTo: unsealTo,
+	})
+	if err != nil {
+		t.Fatalf("CreateSealedItemWithOptions failed: %v", err)
+	}
+
+	item, itemDir, _ := LoadItem(id)
+	item, err = TryMaterialize(item, itemDir, authority)
+	if err != nil {
+		t.Fatalf("TryMaterialize failed: %v", err)
+	}
+	if item.State != StateUnlocked {
+		t.Fatalf("expected unlocked, got %s", item.State)
+	}
+
+	destPath := filepath.Join(destDir, id)
+	data, err := os.ReadFile(destPath)
+	if err != nil {
+		t.Fatalf("unsealed data not written to destination: %v", err)
+	}
+	if !bytes.Equal(data, []byte("project data")) {
+		t.Errorf("unexpected unsealed data: %q", data)
+	}
+
+	if _, err := os.Stat(filepath.Join(itemDir, "unsealed")); !os.IsNotExist(err) {
+		t.Error("unsealed file should not be written inside the store")
+	}
+	if _, err := os.Stat(destPath + ".pending"); !os.IsNotExist(err) {
+		t.Error("pending file should not remain at destination")
+	}
+
+	// The user may move content out of the destination without corrupting the item
+	os.Remove(destPath)
+	if err := ValidateItemState(item, itemDir); err != nil {
+		t.Errorf("moved external content should not be corruption: %v", err)
+	}
+}
+
+func TestMaterialize_UnsealTo_RefusesToOverwrite(t *testing.T) {
+	_, cleanup := testutil.SetupTestEnv(t)
+	defer cleanup()
+
+	destDir := t.TempDir()
+	authority := &timeauth.FakeAuthority{DefaultRound: 100, CurrentRound: 200}
+	id, err := CreateSealedItemWithOptions(time.Now().UTC().Add(time.Hour), InputSourceStdin, "", []byte("data"), authority, ItemOptions{
+		UnsealTo: destDir,
+	})
+	if err != nil {
+		t.Fatalf("CreateSealedItemWithOptions failed: %v", err)
+	}
+
+	destPath := filepath.Join(destDir, id)
+	if err := os.WriteFile(destPath, []byte("user file"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	item, itemDir, _ := LoadItem(id)
+	result, err := TryMaterialize(item, itemDir, authority)
+	if err == nil {
+		t.Fatal("expected error when destination exists")
+	}
+	if result.State != StateSealed {
+		t.Errorf("item should stay sealed, got %s", result.State)
+	}
+
+	data, _ := os.ReadFile(destPath)
+	if string(data) != "user file" {
+		t.Errorf("existing destination file was modified: %q", data)
+	}
+}
+
+func TestResolveUnsealTo_RejectsMissingAndFiles(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	if _, err := ResolveUnsealTo(filepath.Join(tmpDir, "missing")); err == nil {
+		t.Error("expected error for missing directory")
+	}
+
+	filePath := filepath.Join(tmpDir, "file")
+	os.WriteFile(filePath, []byte("x"), 0600)
+	if _, err := ResolveUnsealTo(filePath); err == nil {
+		t.Error("expected error for regular file")
+	}
+}
+
+func TestMaterialize_CorruptPayload_ReportedBeforeDecryption(t *testing.T) {
+	_, cleanup := testutil.SetupTestEnv(t)
+	defer cleanup()
+
+	authority := &timeauth.FakeAuthority{DefaultRound: 100, CurrentRound: 200}
+	id, err := CreateSealedItem(time.Now().UTC().Add(time.Hour), InputSourceStdin, "", []byte("data"), authority)
+	if err != nil {
+		t.Fatalf("CreateSealedItem failed: %v", err)
+	}
+
+	item, itemDir, _ := LoadItem(id)
+	if len(item.PayloadSHA256) != 64 {
+		t.Fatalf("payload checksum not recorded, got %q", item.PayloadSHA256)
+	}
+
+	payloadPath := filepath.Join(itemDir, "payload.bin")
+	payload, _ := os.ReadFile(payloadPath)
+	payload[0] ^= 0xff
+	if err := os.WriteFile(payloadPath, payload, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	// A decryption failure is silently retried; corruption must surface before it
+	authority.DecryptError = os.ErrDeadlineExceeded
+	result, err := TryMaterialize(item, itemDir, authority)
+	if err == nil || !strings.Contains(err.Error(), "checksum mismatch") {
+		t.Fatalf("expected checksum mismatch error, got: %v", err)
+	}
+	if result.State != StateSealed {
+		t.Errorf("item should stay sealed, got %s", result.State)
+	}
+}
+
+func TestMaterialize_NetworkFailure_BacksOff(t *testing.T) {
+	_, cleanup := testutil.SetupTestEnv(t)
+	defer cleanup()
+
+	authority := &timeauth.FakeAuthority{DefaultRound: 100, CurrentRound: 200}
+	id, err := CreateSealedItem(time.Now().UTC().Add(time.Hour), InputSourceStdin, "", []byte("data"), authority)
+	if err != nil {
+		t.Fatalf("CreateSealedItem failed: %v", err)
+	}
+
+	item, itemDir, _ := LoadItem(id)
+
+	authority.CanUnlockError = errors.New("relay unreachable")
+	item, err = TryMaterialize(item, itemDir, authority)
+	if err != nil {
+		t.Fatalf("network failure should not be a materialization error: %v", err)
+	}
+
+	persisted, _, _ := LoadItem(id)
+	if persisted.UnlockFailures != 1 || persisted.LastUnlockError != "relay unreachable" || persisted.NextUnlockAttempt == nil {
+		t.Fatalf("failure not persisted: %+v", persisted)
+	}
+
+	// Within the backoff window the authority is not contacted, even once it recovers
+	authority.CanUnlockError = nil
+	item, _ = TryMaterialize(persisted, itemDir, authority)
+	if item.State != StateSealed {
+		t.Fatal("item should not be retried during backoff")
+	}
+
+	// After the window the attempt is made and success clears the backoff state
+	past := time.Now().UTC().Add(-time.Second)
+	item.NextUnlockAttempt = &past
+	item, err = TryMaterialize(item, itemDir, authority)
+	if err != nil || item.State != StateUnlocked {
+		t.Fatalf("expected unlock after backoff, got %s: %v", item.State, err)
+	}
+
+	persisted, _, _ = LoadItem(id)
+	if persisted.UnlockFailures != 0 || persisted.NextUnlockAttempt != nil || persisted.LastUnlockError != "" {
+		t.Errorf("backoff state should be cleared on success: %+v", persisted)
+	}
+}
+
+func TestUnlockBackoff_DoublesUpToCap(t *testing.T) {
+	cases := map[int]time.Duration{
+		1:  30 * time.Second,
+		2:  time.Minute,
+		3:  2 * time.Minute,
+		8:  time.Hour,
+		50: time.Hour,
+	}
+	for failures, want := range cases {
+		if got := unlockBackoff(failures); got != want {
+			t.Errorf("unlockBackoff(%d) = %v, want %v", failures, got, want)
+		}
+	}
+}
+
+func TestMaterialize_BackoffFollowsClock(t *testing.T) {
+	_, cleanup := testutil.SetupTestEnv(t)
+	defer cleanup()
+
+	now := time.Date(2026, 3, 8, 6, 59, 45, 0, time.UTC) // 15s before US Eastern clocks spring forward
+	defer clock.Set(func() time.Time { return now })()
+
+	authority := &timeauth.FakeAuthority{DefaultRound: 100, CurrentRound: 200, CanUnlockError: errors.New("relay unreachable")}
+	id, err := CreateSealedItem(now.Add(time.Hour), InputSourceStdin, "", []byte("data"), authority)
+	if err != nil {
+		t.Fatalf("CreateSealedItem failed: %v", err)
+	}
+	item, itemDir, _ := LoadItem(id)
+
+	item, _ = TryMaterialize(item, itemDir, authority)
+	if want := now.Add(unlockBackoff(1)); item.NextUnlockAttempt == nil || !item.NextUnlockAttempt.Equal(want) {
+		t.Fatalf("expected next attempt at %s, got %v", want, item.NextUnlockAttempt)
+	}
+
+	// Backoff is measured in UTC, so the local clock change does not shorten it
+	authority.CanUnlockError = nil
+	now = now.Add(29 * time.Second)
+	item, _ = TryMaterialize(item, itemDir, authority)
+	if item.State != StateSealed {
+		t.Fatal("item should not be retried during backoff")
+	}
+
+	now = now.Add(time.Second)
+	item, err = TryMaterialize(item, itemDir, authority)
+	if err != nil || item.State != StateUnlocked {
+		t.Fatalf("expected unlock once the backoff elapsed, got %s: %v", item.State, err)
+	}
+	if !item.UnlockedAt.Equal(now) || !item.History[len(item.History)-1].Time.Equal(now) {
+		t.Errorf("expected unlock recorded at %s, got %s", now, item.UnlockedAt)
+	}
+}
+
+func TestCheckAndTransitionUnlock_UsesRecordedNetwork(t *testing.T) {
+	if timeauth.TestBuild {
+		t.Skip("test builds resolve every drand network to the simulator")
+	}
+	_, cleanup := testutil.SetupTestEnv(t)
+	defer cleanup()
+
+	// The simulator is neither quicknet nor reachable at its relay: only the
+	// network recorded at lock time can unlock the item
+	server, authority := startImportChain(t, time.Second)
+	id, err := CreateSealedItem(time.Now().UTC().Add(time.Second), InputSourceStdin, "", []byte("custom chain"), authority)
+	if err != nil {
+		t.Fatalf("CreateSealedItem failed: %v", err)
+	}
+	item, itemDir, _ := LoadItem(id)
+
+	network, ok := timeauth.DrandNetworkOf(timeauth.KeyReference(item.KeyRef))
+	if !ok || network.ChainHash != server.ChainHash || network.Relay != server.URL {
+		t.Fatalf("key reference does not record the simulator network: %s", item.KeyRef)
+	}
+	if output := FormatInspectOutput(item, nil, false); !strings.Contains(output, "drand_chain_hash: "+server.ChainHash+"\n") {
+		t.Errorf("inspect does not show the recorded chain:\n%s", output)
+	}
+
+	targetRound, _ := extractTargetRound(item.KeyRef)
+	for deadline := time.Now().Add(5 * time.Second); server.LatestRound() < targetRound; {
+		if time.Now().After(deadline) {
+			t.Fatal("simulator did not reach the target round")
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+
+	item, err = CheckAndTransitionUnlock(item, itemDir)
+	if err != nil {
+		t.Fatalf("CheckAndTransitionUnlock failed: %v", err)
+	}
+	if item.State != StateUnlocked {
+		t.Fatalf("expected item to unlock on its recorded network, got %s (%s)", item.State, item.LastUnlockError)
+	}
+}
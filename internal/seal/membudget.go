@@ -0,0 +1,95 @@
+package seal
+
+import (
+	"errors"
+	"sync"
+)
+
+// DefaultMemoryBudget bounds the payload buffers held at once by concurrent
+// lock and materialize operations in one process.
+const DefaultMemoryBudget = 256 << 20 // 256MB
+
+// maxPooledBuffer is the largest buffer kept for reuse: a maximum-size payload plus the GCM tag.
+const maxPooledBuffer = MaxInputSize + 16
+
+// payloadBudget is shared by every lock and materialize operation in the process.
+var payloadBudget = newMemoryBudget(DefaultMemoryBudget)
+
+// memoryBudget is a byte-weighted semaphore. Operations reserve the memory they
+// will hold before allocating it and wait while the budget is exhausted.
+type memoryBudget struct {
+	mu    sync.Mutex
+	cond  *sync.Cond
+	limit int64
+	used  int64
+}
+
+func newMemoryBudget(limit int64) *memoryBudget {
+	b := &memoryBudget{limit: limit}
+	b.cond = sync.NewCond(&b.mu)
+	return b
+}
+
+// acquire reserves n bytes, waiting until they fit. A request larger than the
+// whole budget is reduced to the budget, so it runs alone instead of never.
+// Returns the amount reserved, to be passed to release.
+func (b *memoryBudget) acquire(n int64) int64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	n = min(n, b.limit)
+	for b.used+n > b.limit {
+		b.cond.Wait()
+	}
+	b.used += n
+	return n
+}
+
+// release returns a reservation made by acquire.
+func (b *memoryBudget) release(n int64) {
+	b.mu.Lock()
+	b.used -= n
+	b.mu.Unlock()
+	b.cond.Broadcast()
+}
+
+// setLimit changes the budget. Waiting operations are re-checked against the new limit.
+func (b *memoryBudget) setLimit(limit int64) {
+	b.mu.Lock()
+	b.limit = limit
+	b.mu.Unlock()
+	b.cond.Broadcast()
+}
+
+// SetMemoryBudget sets the bytes of payload buffers that concurrent lock and
+// materialize operations may hold at once (DefaultMemoryBudget unless set).
+func SetMemoryBudget(bytes int64) error {
+	if bytes <= 0 {
+		return errors.New("memory budget must be positive")
+	}
+	payloadBudget.setLimit(bytes)
+	return nil
+}
+
+// payloadBufferPool recycles payload-sized buffers between operations.
+var payloadBufferPool sync.Pool
+
+// getPayloadBuffer returns an empty buffer with capacity for at least n bytes.
+func getPayloadBuffer(n int) []byte {
+	if p, ok := payloadBufferPool.Get().(*[]byte); ok && cap(*p) >= n {
+		return (*p)[:0]
+	}
+	return make([]byte, 0, n)
+}
+
+// putPayloadBuffer zeroes a buffer and returns it to the pool.
+// Buffers may have held plaintext, so they are cleared before reuse.
+func putPayloadBuffer(buf []byte) {
+	if cap(buf) > maxPooledBuffer {
+		return
+	}
+	buf = buf[:cap(buf)]
+	clear(buf)
+	buf = buf[:0]
+	payloadBufferPool.Put(&buf)
+}
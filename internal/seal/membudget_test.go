@@ -0,0 +1,134 @@
+package seal
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"seal/internal/testutil"
+	"seal/internal/timeauth"
+)
+
+func TestMemoryBudget_WaitsForRelease(t *testing.T) {
+	b := newMemoryBudget(100)
+
+	first := b.acquire(60)
+
+	acquired := make(chan int64)
+	go func() { acquired <- b.acquire(60) }()
+
+	select {
+	case <-acquired:
+		t.Fatal("second reservation should wait while the budget is exhausted")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	b.release(first)
+	select {
+	case n := <-acquired:
+		if n != 60 {
+			t.Errorf("expected 60 bytes reserved, got %d", n)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("second reservation did not proceed after release")
+	}
+}
+
+func TestMemoryBudget_OversizedRequestRunsAlone(t *testing.T) {
+	b := newMemoryBudget(100)
+
+	if n := b.acquire(500); n != 100 {
+		t.Fatalf("expected reservation clamped to 100, got %d", n)
+	}
+	b.release(100)
+
+	// Raising the limit lets a waiting reservation through
+	held := b.acquire(100)
+	done := make(chan struct{})
+	go func() {
+		b.release(b.acquire(50))
+		close(done)
+	}()
+	b.setLimit(150)
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("reservation did not proceed after the limit was raised")
+	}
+	b.release(held)
+}
+
+func TestPayloadBufferPool_ClearsBuffers(t *testing.T) {
+	buf := append(getPayloadBuffer(16), "plaintext secret"...)
+	putPayloadBuffer(buf)
+
+	reused := getPayloadBuffer(16)
+	if len(reused) != 0 || cap(reused) < 16 {
+		t.Fatalf("expected empty buffer with capacity 16, got len %d cap %d", len(reused), cap(reused))
+	}
+	for _, c := range reused[:cap(reused)] {
+		if c != 0 {
+			t.Fatal("pooled buffer was not cleared")
+		}
+	}
+}
+
+// budgetAuthority records how many materializations hold the budget at once.
+type budgetAuthority struct {
+	*timeauth.FakeAuthority
+	active, peak atomic.Int32
+}
+
+func (a *budgetAuthority) TimeLockDecrypt(ctx context.Context, ciphertextB64 string) ([]byte, error) {
+	n := a.active.Add(1)
+	defer a.active.Add(-1)
+	for {
+		peak := a.peak.Load()
+		if n <= peak || a.peak.CompareAndSwap(peak, n) {
+			break
+		}
+	}
+	time.Sleep(20 * time.Millisecond)
+	return a.FakeAuthority.TimeLockDecrypt(ctx, ciphertextB64)
+}
+
+func TestTryMaterialize_RespectsMemoryBudget(t *testing.T) {
+	_, cleanup := testutil.SetupTestEnv(t)
+	defer cleanup()
+
+	authority := &budgetAuthority{FakeAuthority: &timeauth.FakeAuthority{DefaultRound: 100, CurrentRound: 200}}
+
+	var ids []string
+	for range 4 {
+		id, err := CreateSealedItem(time.Now().UTC().Add(-time.Minute), InputSourceStdin, "", make([]byte, 1000), authority)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, id)
+	}
+
+	// Each materialization holds two buffers of ~1KB: only two fit in 4500 bytes
+	if err := SetMemoryBudget(4500); err != nil {
+		t.Fatal(err)
+	}
+	defer SetMemoryBudget(DefaultMemoryBudget)
+
+	var wg sync.WaitGroup
+	for _, id := range ids {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			item, itemDir, _ := LoadItem(id)
+			if item, err := TryMaterialize(item, itemDir, authority); err != nil || item.State != StateUnlocked {
+				t.Errorf("TryMaterialize(%s): state %s, err %v", id, item.State, err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if peak := authority.peak.Load(); peak > 2 {
+		t.Errorf("expected at most 2 concurrent materializations, got %d", peak)
+	}
+}
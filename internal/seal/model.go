@@ -3,7 +3,7 @@ package seal
 import "time"
 
 const (
-	MaxInputSize = 10 * 1024 * 1024 // 10MB
+	MaxInputSize = 10 * 1024 * 1024 // 10MB held in memory; larger lock input is streamed, see CreateStreamedItem
 )
 
 // State constants for sealed items
@@ -17,15 +17,33 @@ type InputSource int
 const (
 	InputSourceFile InputSource = iota
 	InputSourceStdin
+	InputSourcePipe
+	InputSourceImport
+	InputSourcePass
+	InputSourceDirectory
 )
 
 func (i InputSource) String() string {
-	if i == InputSourceFile {
+	switch i {
+	case InputSourceFile:
 		return "file"
+	case InputSourcePipe:
+		return "pipe"
+	case InputSourceImport:
+		return "import"
+	case InputSourcePass:
+		return "pass"
+	case InputSourceDirectory:
+		return "directory"
+	default:
+		return "stdin"
 	}
-	return "stdin"
 }
 
+// MetadataSchemaVersion is the meta.json layout written by this build.
+// Items without schema_version predate it and are treated as version 0.
+const MetadataSchemaVersion = 1
+
 // KeyReference is an opaque reference to a time-locked encryption key.
 type KeyReference string
 
@@ -40,8 +58,88 @@ type SealedItem struct {
 	CreatedAt     time.Time `json:"created_at"`
 	Algorithm     string    `json:"algorithm"`
 	Nonce         string    `json:"nonce"`
-	KeyRef        string    `json:"key_ref"`
-	DEKTlockB64   string    `json:"dek_tlock_b64,omitempty"` // tlock-encrypted DEK (base64)
+	KeyRef        string    `json:"key_ref"`                 // authority key reference; a threshold item lists one per member
+	DEKTlockB64   string    `json:"dek_tlock_b64,omitempty"` // tlock-encrypted DEK (base64); a threshold item holds the time-locked shares
+
+	// Recorded at creation (absent for items created before they were tracked)
+	SealVersion   string `json:"seal_version,omitempty"`   // version of the seal build that created the item
+	SchemaVersion int    `json:"schema_version,omitempty"` // meta.json layout, see MetadataSchemaVersion
+
+	// Normalization applied to the input before sealing (optional), see NormalizeInput
+	Normalization []string `json:"normalization,omitempty"`
+
+	// Sizes recorded at lock time (absent for items created before they were tracked)
+	PlaintextSize  int64 `json:"plaintext_size,omitempty"`
+	CiphertextSize int64 `json:"ciphertext_size,omitempty"` // size of payload.bin, including GCM tags
+
+	PayloadSHA256 string `json:"payload_sha256,omitempty"` // hex SHA-256 of payload.bin, verified before decryption
+
+	// Timestamp proof that payload.bin existed, see AttestItem (optional)
+	Attestation *Attestation `json:"attestation,omitempty"`
+
+	// Payload is authenticated together with id, target round and algorithm (0 = legacy item without AAD)
+	AADVersion int `json:"aad_version,omitempty"`
+
+	// Backoff state after failed unlock attempts (cleared on success)
+	UnlockFailures    int        `json:"unlock_failures,omitempty"`
+	LastUnlockError   string     `json:"last_unlock_error,omitempty"`
+	NextUnlockAttempt *time.Time `json:"next_unlock_attempt,omitempty"`
+
+	// Recorded when materialization commits
+	UnlockedAt  *time.Time `json:"unlocked_at,omitempty"`
+	UnlockRound uint64     `json:"unlock_round,omitempty"` // beacon round used for decryption
+
+	// Hex SHA-256 of the unsealed content as written, checked by seal doctor (absent for items unlocked before it was recorded)
+	UnsealedSHA256 string `json:"unsealed_sha256,omitempty"`
+
+	// meta.json and payload.bin carry the platform immutable attribute (optional)
+	Immutable bool `json:"immutable,omitempty"`
+
+	// Materialization destination outside the store (optional, absolute directory)
+	UnsealTo string `json:"unseal_to,omitempty"`
+
+	// A directory sealed as an archive, extracted by an untar post-processing step (optional)
+	Archive *ArchiveInfo `json:"archive,omitempty"`
+
+	// Post-processing steps run on unsealed content after unlock (optional)
+	PostProcess        []string            `json:"post_process,omitempty"`
+	PostProcessResults []PostProcessResult `json:"post_process_results,omitempty"`
+
+	// Forget-after-read (optional): unsealed content is shredded this long after unlock
+	RetainUnsealed     string     `json:"retain_unsealed,omitempty"` // Go duration, e.g. 168h0m0s
+	UnsealedShreddedAt *time.Time `json:"unsealed_shredded_at,omitempty"`
+
+	// DEK is additionally wrapped by this Vault transit key before time-locking (optional)
+	VaultWrap string `json:"vault_wrap,omitempty"` // e.g. transit/keys/foo
+
+	// Content is age-encrypted to this public key before sealing (optional)
+	Recipient     string `json:"recipient,omitempty"`
+	RecipientName string `json:"recipient_name,omitempty"` // contact name used at lock time
+
+	// Content was encrypted with a passphrase before sealing (optional), see ReadPassphraseFile
+	Passphrase *PassphraseProtection `json:"passphrase,omitempty"`
+
+	// Reveal delivery (optional)
+	RevealTo          string     `json:"reveal_to,omitempty"`     // e.g. mailto:alice@example.com
+	RevealStatus      string     `json:"reveal_status,omitempty"` // delivered or failed
+	RevealError       string     `json:"reveal_error,omitempty"`
+	RevealAttemptedAt *time.Time `json:"reveal_attempted_at,omitempty"`
+
+	// Notification sinks named at lock time (in addition to global sinks)
+	Notify []string `json:"notify,omitempty"`
+
+	// Unlock hooks run once when the item unlocks (optional)
+	OnUnlockExec    string `json:"on_unlock_exec,omitempty"`    // absolute program path, see ResolveUnlockExec
+	OnUnlockWebhook string `json:"on_unlock_webhook,omitempty"` // receives an UnlockHookEvent
+
+	// Freeform key=value tags for correlating with external systems (optional), see ParseTags
+	Tags map[string]string `json:"tags,omitempty"`
+
+	// Freeform description shown by status and inspect (optional), see ValidateNote
+	Note string `json:"note,omitempty"`
+
+	// Bounded event history, see MaxHistoryEntries
+	History []HistoryEntry `json:"history,omitempty"`
 }
 
 // DrandKeyReference contains drand-specific information for time-locked keys.
@@ -0,0 +1,394 @@
+package seal
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// storeLocationFile is the pointer, kept in the default base directory,
+// to a store that was relocated with MoveStore.
+const storeLocationFile = "location"
+
+// readStoreLocation returns the relocated store recorded in the default base directory,
+// or "" if the store was never moved.
+func readStoreLocation(home string) (string, error) {
+	data, err := os.ReadFile(filepath.Join(home, storeLocationFile))
+	if os.IsNotExist(err) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("cannot read store location: %w", err)
+	}
+
+	location := strings.TrimSpace(string(data))
+	if !filepath.IsAbs(location) {
+		return "", fmt.Errorf("invalid store location in %s: %q", filepath.Join(home, storeLocationFile), location)
+	}
+	return location, nil
+}
+
+// MoveStoreResult describes a completed store relocation.
+type MoveStoreResult struct {
+	From     string
+	To       string
+	Files    int // files copied and verified
+	Warnings []string
+}
+
+// MoveStore relocates the whole store to newDir.
+// Every file is copied and its SHA-256 compared with the original before the
+// pointer in the default base directory is switched (an atomic rename), so an
+// interrupted move leaves the store where it was. newDir must not exist or be empty.
+// The store must be idle: a running daemon refuses the move with ErrDaemonRunning,
+// and every item is locked from the copy through the switch. Lock files and the
+// authority cache are not copied. The old copy is left in place unless shredOld
+// is set; shredding it is best-effort.
+func MoveStore(newDir string, shredOld bool) (MoveStoreResult, error) {
+	// The pointer would be ignored while the environment names the store
+	if os.Getenv(DataDirEnvVar) != "" {
+		return MoveStoreResult{}, fmt.Errorf("the store location is set by %s (or --data-dir); copy the store and change that instead", DataDirEnvVar)
+	}
+
+	home, err := defaultSealBaseDir()
+	if err != nil {
+		return MoveStoreResult{}, err
+	}
+	from, err := GetSealBaseDir()
+	if err != nil {
+		return MoveStoreResult{}, err
+	}
+	to, err := filepath.Abs(newDir)
+	if err != nil {
+		return MoveStoreResult{}, fmt.Errorf("cannot resolve destination: %w", err)
+	}
+
+	if to == from {
+		return MoveStoreResult{}, fmt.Errorf("store is already at %s", from)
+	}
+	if isWithinDir(to, from) || isWithinDir(from, to) {
+		return MoveStoreResult{}, fmt.Errorf("cannot move store between %s and %s: one contains the other", from, to)
+	}
+	if info, err := os.Stat(from); err != nil || !info.IsDir() {
+		return MoveStoreResult{}, fmt.Errorf("no store at %s", from)
+	}
+
+	// Nothing may change the old store once it is copied, or the change is lost at the switch
+	locks, err := lockStore(from)
+	if err != nil {
+		return MoveStoreResult{}, fmt.Errorf("store not moved: %w", err)
+	}
+	defer locks.release()
+
+	// Moving back to the default directory is allowed; it holds only the pointer
+	created, err := prepareMoveDestination(to, to == home)
+	if err != nil {
+		return MoveStoreResult{}, err
+	}
+
+	files, copied, err := copyStore(from, to)
+	if err == nil {
+		err = locks.checkUnchanged(from)
+	}
+	if err != nil {
+		// Nothing was switched: remove the partial copy and leave the store in place
+		if created {
+			os.RemoveAll(to)
+		} else {
+			for _, name := range copied {
+				os.RemoveAll(filepath.Join(to, name))
+			}
+		}
+		return MoveStoreResult{}, fmt.Errorf("store not moved: %w", err)
+	}
+
+	if err := switchStoreLocation(home, to); err != nil {
+		return MoveStoreResult{}, fmt.Errorf("store copied to %s but not switched: %w", to, err)
+	}
+	// Processes waiting on the old store find their items gone, or shredded
+	locks.release()
+
+	result := MoveStoreResult{From: from, To: to, Files: files}
+
+	// Copies do not carry the immutable attribute; set it again where it was requested
+	err = WalkSealedItems(func(item SealedItem, itemDir string) error {
+		if item.Immutable {
+			result.Warnings = append(result.Warnings, protectItemFiles(itemDir)...)
+		}
+		return nil
+	})
+	if err != nil {
+		result.Warnings = append(result.Warnings, fmt.Sprintf("warning: could not restore immutable attributes: %v", err))
+	}
+
+	if shredOld {
+		result.Warnings = append(result.Warnings, shredOldStore(from, from == home)...)
+	} else {
+		result.Warnings = append(result.Warnings, fmt.Sprintf("warning: the old store at %s was left in place and still contains every item, including unlocked content (use --shred-old to remove it)", from))
+	}
+
+	return result, nil
+}
+
+// storeLock holds the daemon lock and every item lock of a store.
+type storeLock struct {
+	daemon *itemLock
+	items  map[string]*itemLock // by item directory name
+}
+
+// lockStore takes the daemon lock of the store at dir without waiting, then the lock
+// of each item in it, waiting for busy items as any other seal process does.
+func lockStore(dir string) (*storeLock, error) {
+	daemon, err := lockDaemon()
+	if err != nil {
+		return nil, err
+	}
+	locks := &storeLock{daemon: daemon, items: make(map[string]*itemLock)}
+
+	names, err := storeItemNames(dir)
+	if err != nil {
+		locks.release()
+		return nil, err
+	}
+	for _, name := range names {
+		lock, err := lockItem(filepath.Join(dir, name))
+		if err != nil {
+			locks.release()
+			return nil, err
+		}
+		locks.items[name] = lock
+	}
+	return locks, nil
+}
+
+// checkUnchanged refuses a store that gained items after it was locked, e.g.
+// sealed by watch-folder or imported; they were not locked, so maybe not copied.
+func (l *storeLock) checkUnchanged(dir string) error {
+	names, err := storeItemNames(dir)
+	if err != nil {
+		return err
+	}
+	for _, name := range names {
+		if _, ok := l.items[name]; !ok {
+			return fmt.Errorf("item %s was added during the move; move the store again", name)
+		}
+	}
+	return nil
+}
+
+// release releases every lock still held.
+func (l *storeLock) release() {
+	for name, lock := range l.items {
+		lock.unlock()
+		delete(l.items, name)
+	}
+	if l.daemon != nil {
+		l.daemon.unlock()
+		l.daemon = nil
+	}
+}
+
+// storeItemNames returns the names of the item directories in the store at dir.
+func storeItemNames(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read seal directory: %w", err)
+	}
+
+	var names []string
+	for _, entry := range entries {
+		if _, err := uuid.Parse(entry.Name()); err == nil && entry.IsDir() {
+			names = append(names, entry.Name())
+		}
+	}
+	return names, nil
+}
+
+// isWithinDir reports whether path is dir or inside it.
+func isWithinDir(path, dir string) bool {
+	rel, err := filepath.Rel(dir, path)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// prepareMoveDestination checks that dir is missing or empty and creates it.
+// Reports whether the directory was created. allowPointer permits the
+// store location pointer, when moving back to the default directory.
+func prepareMoveDestination(dir string, allowPointer bool) (bool, error) {
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		if err := os.MkdirAll(dir, 0700); err != nil {
+			return false, fmt.Errorf("cannot create destination: %w", err)
+		}
+		return true, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("cannot use destination: %w", err)
+	}
+
+	for _, entry := range entries {
+		if allowPointer && entry.Name() == storeLocationFile {
+			continue
+		}
+		return false, fmt.Errorf("destination is not empty: %s", dir)
+	}
+	return false, nil
+}
+
+// copyStore copies every file under from to to and verifies each copy.
+// Lock files belong to the processes of the old store, and the authority
+// cache is rebuilt on demand, so neither is copied.
+// Returns the number of files and the top-level names that were created.
+func copyStore(from, to string) (int, []string, error) {
+	files := 0
+	var created []string
+
+	err := filepath.WalkDir(from, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(from, path)
+		if err != nil {
+			return err
+		}
+		if rel == "." {
+			return nil
+		}
+		if !strings.ContainsRune(rel, filepath.Separator) {
+			if d.IsDir() && (rel == locksDirName || rel == cacheDirName) {
+				return filepath.SkipDir
+			}
+			created = append(created, rel)
+		}
+
+		target := filepath.Join(to, rel)
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case d.IsDir():
+			return os.Mkdir(target, info.Mode().Perm())
+		case d.Type().IsRegular():
+			if err := copyVerified(path, target, info.Mode().Perm()); err != nil {
+				return err
+			}
+			files++
+			return nil
+		default:
+			return fmt.Errorf("%s: not a regular file", path)
+		}
+	})
+
+	return files, created, err
+}
+
+// copyVerified copies a file, syncs it, and compares the SHA-256 of the copy read back from disk.
+func copyVerified(src, dst string, perm os.FileMode) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_EXCL, perm)
+	if err != nil {
+		return err
+	}
+
+	srcHash := sha256.New()
+	if _, err := io.Copy(io.MultiWriter(out, srcHash), in); err != nil {
+		out.Close()
+		return fmt.Errorf("cannot copy %s: %w", src, err)
+	}
+	if err := out.Sync(); err != nil {
+		out.Close()
+		return fmt.Errorf("cannot sync %s: %w", dst, err)
+	}
+	if err := out.Close(); err != nil {
+		return err
+	}
+
+	copied, err := os.Open(dst)
+	if err != nil {
+		return err
+	}
+	defer copied.Close()
+
+	dstHash := sha256.New()
+	if _, err := io.Copy(dstHash, copied); err != nil {
+		return fmt.Errorf("cannot verify %s: %w", dst, err)
+	}
+	if !bytes.Equal(srcHash.Sum(nil), dstHash.Sum(nil)) {
+		return fmt.Errorf("%s: copy does not match the original", dst)
+	}
+
+	return nil
+}
+
+// switchStoreLocation points the default base directory at dir.
+// Moving back to the default directory removes the pointer.
+func switchStoreLocation(home, dir string) error {
+	pointerPath := filepath.Join(home, storeLocationFile)
+	if dir == home {
+		if err := os.Remove(pointerPath); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return nil
+	}
+
+	if err := os.MkdirAll(home, 0700); err != nil {
+		return err
+	}
+
+	return writeFileAtomic(pointerPath, []byte(dir+"\n"), 0600)
+}
+
+// shredOldStore shreds every file of the old store and removes its directories (best-effort).
+// The default directory itself is kept when it holds the new pointer.
+func shredOldStore(dir string, keepPointer bool) []string {
+	var warnings []string
+	var dirs []string
+
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			dirs = append(dirs, path)
+			return nil
+		}
+		if keepPointer && path == filepath.Join(dir, storeLocationFile) {
+			return nil
+		}
+
+		// Protected item files cannot be overwritten until the attribute is cleared
+		setImmutable(path, false)
+		warnings = append(warnings, ShredFile(path)...)
+		return nil
+	})
+	if err != nil {
+		warnings = append(warnings, fmt.Sprintf("warning: could not walk old store: %v", err))
+	}
+
+	// Deepest directories first
+	for i := len(dirs) - 1; i >= 0; i-- {
+		if keepPointer && dirs[i] == dir {
+			continue
+		}
+		if err := os.Remove(dirs[i]); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			warnings = append(warnings, fmt.Sprintf("warning: could not remove %s: %v", dirs[i], err))
+		}
+	}
+
+	return warnings
+}
@@ -0,0 +1,243 @@
+package seal
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"seal/internal/testutil"
+	"seal/internal/timeauth"
+)
+
+// createMoveStoreItem creates a sealed item in the current store.
+func createMoveStoreItem(t *testing.T) string {
+	t.Helper()
+	authority := &timeauth.FakeAuthority{DefaultRound: 100, CurrentRound: 50}
+	id, err := CreateSealedItem(time.Now().UTC().Add(time.Hour), InputSourceStdin, "", []byte("moving"), authority)
+	if err != nil {
+		t.Fatalf("CreateSealedItem failed: %v", err)
+	}
+	return id
+}
+
+func TestMoveStore_RelocatesAndSwitches(t *testing.T) {
+	_, cleanup := testutil.SetupTestEnv(t)
+	defer cleanup()
+
+	id := createMoveStoreItem(t)
+	if err := SaveConfig(Config{MinInputSize: 8}); err != nil {
+		t.Fatal(err)
+	}
+
+	home, _ := GetSealBaseDir()
+	newDir := filepath.Join(t.TempDir(), "encrypted", "seal")
+
+	result, err := MoveStore(newDir, false)
+	if err != nil {
+		t.Fatalf("MoveStore failed: %v", err)
+	}
+	if result.From != home || result.To != newDir || result.Files != 3 {
+		t.Errorf("unexpected result: %+v", result)
+	}
+	if len(result.Warnings) != 1 || !strings.Contains(result.Warnings[0], "left in place") {
+		t.Errorf("expected a warning about the old store, got %v", result.Warnings)
+	}
+
+	if baseDir, _ := GetSealBaseDir(); baseDir != newDir {
+		t.Fatalf("expected store at %s, got %s", newDir, baseDir)
+	}
+	if _, itemDir, err := LoadItem(id); err != nil || filepath.Dir(itemDir) != newDir {
+		t.Errorf("expected item in the new store, got %s (%v)", itemDir, err)
+	}
+	if cfg, _ := LoadConfig(); cfg.MinInputSize != 8 {
+		t.Error("config did not move with the store")
+	}
+
+	// Without --shred-old the original stays untouched
+	if _, err := os.Stat(filepath.Join(home, id, "payload.bin")); err != nil {
+		t.Errorf("expected old copy to remain: %v", err)
+	}
+}
+
+func TestMoveStore_BackToDefaultWithShred(t *testing.T) {
+	_, cleanup := testutil.SetupTestEnv(t)
+	defer cleanup()
+
+	id := createMoveStoreItem(t)
+	home, _ := GetSealBaseDir()
+	newDir := filepath.Join(t.TempDir(), "seal")
+
+	if _, err := MoveStore(newDir, true); err != nil {
+		t.Fatalf("MoveStore failed: %v", err)
+	}
+
+	// Only the pointer remains in the default directory
+	entries, _ := os.ReadDir(home)
+	if len(entries) != 1 || entries[0].Name() != storeLocationFile {
+		t.Errorf("expected only the pointer in %s, got %v", home, entries)
+	}
+
+	result, err := MoveStore(home, true)
+	if err != nil {
+		t.Fatalf("MoveStore back failed: %v", err)
+	}
+	if len(result.Warnings) != 0 {
+		t.Errorf("unexpected warnings: %v", result.Warnings)
+	}
+
+	if baseDir, _ := GetSealBaseDir(); baseDir != home {
+		t.Fatalf("expected store back at %s, got %s", home, baseDir)
+	}
+	if _, err := os.Stat(filepath.Join(home, storeLocationFile)); !os.IsNotExist(err) {
+		t.Error("expected pointer to be removed")
+	}
+	if _, err := os.Stat(newDir); !os.IsNotExist(err) {
+		t.Errorf("expected old store to be removed, got %v", err)
+	}
+	if _, _, err := LoadItem(id); err != nil {
+		t.Errorf("LoadItem failed after moving back: %v", err)
+	}
+}
+
+func TestMoveStore_Refusals(t *testing.T) {
+	_, cleanup := testutil.SetupTestEnv(t)
+	defer cleanup()
+
+	createMoveStoreItem(t)
+	home, _ := GetSealBaseDir()
+
+	occupied := t.TempDir()
+	if err := os.WriteFile(filepath.Join(occupied, "other"), []byte("x"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		dir  string
+		want string
+	}{
+		{home, "already at"},
+		{filepath.Join(home, "nested"), "one contains the other"},
+		{filepath.Dir(home), "one contains the other"},
+		{occupied, "not empty"},
+	}
+
+	for _, tt := range tests {
+		if _, err := MoveStore(tt.dir, false); err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("MoveStore(%s): expected error containing %q, got %v", tt.dir, tt.want, err)
+		}
+		if baseDir, _ := GetSealBaseDir(); baseDir != home {
+			t.Fatalf("store moved after a refused move: %s", baseDir)
+		}
+	}
+}
+
+func TestMoveStore_RequiresIdleStore(t *testing.T) {
+	_, cleanup := testutil.SetupTestEnv(t)
+	defer cleanup()
+
+	defer func(wait time.Duration) { itemLockWait = wait }(itemLockWait)
+	itemLockWait = 100 * time.Millisecond
+
+	id := createMoveStoreItem(t)
+	home, _ := GetSealBaseDir()
+	newDir := filepath.Join(t.TempDir(), "seal")
+
+	daemon, err := lockDaemon()
+	if err != nil {
+		t.Fatalf("lockDaemon failed: %v", err)
+	}
+	if _, err := MoveStore(newDir, true); !errors.Is(err, ErrDaemonRunning) {
+		t.Errorf("expected a running daemon to refuse the move, got %v", err)
+	}
+	daemon.unlock()
+
+	item, err := lockItem(filepath.Join(home, id))
+	if err != nil {
+		t.Fatalf("lockItem failed: %v", err)
+	}
+	if _, err := MoveStore(newDir, true); !errors.Is(err, ErrItemBusy) {
+		t.Errorf("expected a busy item to refuse the move, got %v", err)
+	}
+	item.unlock()
+
+	if baseDir, _ := GetSealBaseDir(); baseDir != home {
+		t.Fatalf("store moved after a refused move: %s", baseDir)
+	}
+
+	// Lock files and the authority cache stay behind
+	cacheDir, _ := CacheDir()
+	if err := os.MkdirAll(cacheDir, 0700); err != nil {
+		t.Fatal(err)
+	}
+	os.WriteFile(filepath.Join(cacheDir, "chain.json"), []byte("{}"), 0600)
+	if _, err := MoveStore(newDir, false); err != nil {
+		t.Fatalf("MoveStore failed: %v", err)
+	}
+	for _, name := range []string{locksDirName, cacheDirName} {
+		if _, err := os.Stat(filepath.Join(newDir, name)); !os.IsNotExist(err) {
+			t.Errorf("expected %s not to be copied, got %v", name, err)
+		}
+	}
+}
+
+func TestStoreLock_RefusesItemsAddedWhileLocked(t *testing.T) {
+	_, cleanup := testutil.SetupTestEnv(t)
+	defer cleanup()
+
+	createMoveStoreItem(t)
+	home, _ := GetSealBaseDir()
+	locks, err := lockStore(home)
+	if err != nil {
+		t.Fatalf("lockStore failed: %v", err)
+	}
+	defer locks.release()
+
+	if err := locks.checkUnchanged(home); err != nil {
+		t.Errorf("unchanged store refused: %v", err)
+	}
+	added := createMoveStoreItem(t)
+	if err := locks.checkUnchanged(home); err == nil || !strings.Contains(err.Error(), added) {
+		t.Errorf("expected the added item to be named, got %v", err)
+	}
+}
+
+func TestMoveStore_RefusedWithDataDirEnv(t *testing.T) {
+	_, cleanup := testutil.SetupTestEnv(t)
+	defer cleanup()
+
+	t.Setenv(DataDirEnvVar, t.TempDir())
+	if _, err := MoveStore(t.TempDir(), false); err == nil || !strings.Contains(err.Error(), DataDirEnvVar) {
+		t.Errorf("expected a refusal naming %s, got %v", DataDirEnvVar, err)
+	}
+}
+
+func TestMoveStore_FailedCopyLeavesStoreInPlace(t *testing.T) {
+	_, cleanup := testutil.SetupTestEnv(t)
+	defer cleanup()
+
+	id := createMoveStoreItem(t)
+	home, _ := GetSealBaseDir()
+
+	// A symlink in the store cannot be copied safely
+	if err := os.Symlink("/etc/passwd", filepath.Join(home, id, "link")); err != nil {
+		t.Skip("symlinks not supported")
+	}
+
+	newDir := filepath.Join(t.TempDir(), "seal")
+	if _, err := MoveStore(newDir, true); err == nil || !strings.Contains(err.Error(), "not a regular file") {
+		t.Fatalf("expected copy failure, got %v", err)
+	}
+
+	if baseDir, _ := GetSealBaseDir(); baseDir != home {
+		t.Errorf("expected store to stay at %s, got %s", home, baseDir)
+	}
+	if _, err := os.Stat(newDir); !os.IsNotExist(err) {
+		t.Errorf("expected partial copy to be removed, got %v", err)
+	}
+	if _, _, err := LoadItem(id); err != nil {
+		t.Errorf("item lost after failed move: %v", err)
+	}
+}
@@ -0,0 +1,78 @@
+package seal
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"unicode/utf8"
+)
+
+// Input encodings accepted by lock.
+const (
+	EncodingRaw  = "raw"  // bytes are sealed exactly as read (default)
+	EncodingUTF8 = "utf8" // input must be valid UTF-8; a leading byte order mark is removed
+)
+
+// Normalization steps recorded in metadata, so the revealed content is unambiguous.
+const (
+	NormalizedUTF8    = "utf8-validated"
+	NormalizedBOM     = "utf8-bom-removed"
+	NormalizedNewline = "trailing-newline-removed"
+)
+
+// utf8BOM is the UTF-8 encoded byte order mark some editors prepend.
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+// ValidateEncoding checks an input encoding name. Empty means raw.
+func ValidateEncoding(encoding string) error {
+	switch encoding {
+	case "", EncodingRaw, EncodingUTF8:
+		return nil
+	default:
+		return fmt.Errorf("invalid encoding %q (use utf8 or raw)", encoding)
+	}
+}
+
+// NormalizeInput applies the requested normalization to input.
+// Returns the normalized data and the steps that were applied, in order.
+func NormalizeInput(data []byte, encoding string, stripNewline bool) ([]byte, []string, error) {
+	if err := ValidateEncoding(encoding); err != nil {
+		return nil, nil, err
+	}
+
+	var steps []string
+
+	if encoding == EncodingUTF8 {
+		if !utf8.Valid(data) {
+			return nil, nil, errors.New("input is not valid UTF-8 (use --encoding raw to seal it as is)")
+		}
+		steps = append(steps, NormalizedUTF8)
+
+		if bytes.HasPrefix(data, utf8BOM) {
+			data = data[len(utf8BOM):]
+			steps = append(steps, NormalizedBOM)
+		}
+	}
+
+	if stripNewline {
+		var stripped bool
+		data, stripped = stripTrailingNewline(data)
+		if stripped {
+			steps = append(steps, NormalizedNewline)
+		}
+	}
+
+	if len(data) == 0 {
+		return nil, nil, errors.New("input is empty after normalization")
+	}
+
+	return data, steps, nil
+}
+
+// stripTrailingNewline removes one trailing newline (LF or CRLF), as added by echo or an editor.
+func stripTrailingNewline(data []byte) ([]byte, bool) {
+	if !bytes.HasSuffix(data, []byte("\n")) {
+		return data, false
+	}
+	return bytes.TrimSuffix(data[:len(data)-1], []byte("\r")), true
+}
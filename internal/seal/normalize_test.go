@@ -0,0 +1,55 @@
+package seal
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestNormalizeInput(t *testing.T) {
+	tests := []struct {
+		name         string
+		input        string
+		encoding     string
+		stripNewline bool
+		want         string
+		steps        []string
+	}{
+		{"raw keeps bytes", "secret\n", EncodingRaw, false, "secret\n", nil},
+		{"strip LF", "secret\n", EncodingRaw, true, "secret", []string{NormalizedNewline}},
+		{"strip CRLF", "secret\r\n", "", true, "secret", []string{NormalizedNewline}},
+		{"strip only one newline", "secret\n\n", EncodingRaw, true, "secret\n", []string{NormalizedNewline}},
+		{"nothing to strip", "secret", EncodingRaw, true, "secret", nil},
+		{"utf8 validated", "grüße\n", EncodingUTF8, false, "grüße\n", []string{NormalizedUTF8}},
+		{"utf8 BOM removed", "\xEF\xBB\xBFsecret\n", EncodingUTF8, true, "secret", []string{NormalizedUTF8, NormalizedBOM, NormalizedNewline}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, steps, err := NormalizeInput([]byte(tt.input), tt.encoding, tt.stripNewline)
+			if err != nil {
+				t.Fatalf("NormalizeInput failed: %v", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+			if !reflect.DeepEqual(steps, tt.steps) {
+				t.Errorf("got steps %v, want %v", steps, tt.steps)
+			}
+		})
+	}
+}
+
+func TestNormalizeInput_Errors(t *testing.T) {
+	if _, _, err := NormalizeInput([]byte("\xff\xfe"), EncodingUTF8, false); err == nil || !strings.Contains(err.Error(), "not valid UTF-8") {
+		t.Errorf("expected invalid UTF-8 error, got %v", err)
+	}
+
+	if _, _, err := NormalizeInput([]byte("\n"), EncodingRaw, true); err == nil || !strings.Contains(err.Error(), "empty after normalization") {
+		t.Errorf("expected empty input error, got %v", err)
+	}
+
+	if _, _, err := NormalizeInput([]byte("secret"), "latin1", false); err == nil || !strings.Contains(err.Error(), "invalid encoding") {
+		t.Errorf("expected invalid encoding error, got %v", err)
+	}
+}
@@ -0,0 +1,243 @@
+package seal
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// Notification events.
+const (
+	NotifyEventUnlocked     = "unlocked"
+	NotifyEventUnlockFailed = "unlock_failed"
+)
+
+// Notification sink types.
+const (
+	NotifySinkWebhook = "webhook"
+	NotifySinkSlack   = "slack"
+	NotifySinkMatrix  = "matrix"
+)
+
+// defaultNotifyTemplate is used when a sink does not configure its own template.
+const defaultNotifyTemplate = `seal item {{.ID}} {{.Event}}{{if .Error}}: {{.Error}}{{end}}`
+
+// NotifySink configures a notification destination.
+// Global sinks receive events for every item; other sinks only for items that name them.
+type NotifySink struct {
+	Name     string `json:"name"`
+	Type     string `json:"type"` // webhook, slack, or matrix
+	URL      string `json:"url"`  // webhook/slack URL, or matrix homeserver base URL
+	Global   bool   `json:"global,omitempty"`
+	Template string `json:"template,omitempty"` // text/template over NotifyEvent
+
+	// Matrix only
+	Room  string `json:"room,omitempty"`
+	Token string `json:"token,omitempty"`
+}
+
+// NotifyEvent describes something that happened to an item.
+// Never contains unlocked content.
+type NotifyEvent struct {
+	Event      string    `json:"event"`
+	ID         string    `json:"id"`
+	UnlockTime time.Time `json:"unlock_time"`
+	Error      string    `json:"error,omitempty"`
+	Message    string    `json:"message"`
+}
+
+// notifier delivers a rendered event to one sink.
+type notifier interface {
+	Notify(event NotifyEvent) error
+}
+
+// notifierTypes maps sink types to constructors.
+// New sink types are added by registering a constructor here.
+var notifierTypes = map[string]func(sink NotifySink) notifier{
+	NotifySinkWebhook: func(sink NotifySink) notifier { return &webhookNotifier{url: sink.URL} },
+	NotifySinkSlack:   func(sink NotifySink) notifier { return &slackNotifier{url: sink.URL} },
+	NotifySinkMatrix: func(sink NotifySink) notifier {
+		return &matrixNotifier{homeserver: sink.URL, room: sink.Room, token: sink.Token}
+	},
+}
+
+// notifyHTTPClient is used by all HTTP-based sinks.
+var notifyHTTPClient = &http.Client{Timeout: 10 * time.Second}
+
+// ValidateNotifySinks checks that every named sink exists in the configuration.
+func ValidateNotifySinks(names []string, cfg Config) error {
+	for _, name := range names {
+		if _, ok := findNotifySink(name, cfg); !ok {
+			return fmt.Errorf("unknown notification sink: %s", name)
+		}
+	}
+	return nil
+}
+
+func findNotifySink(name string, cfg Config) (NotifySink, bool) {
+	for _, sink := range cfg.Notify {
+		if sink.Name == name {
+			return sink, true
+		}
+	}
+	return NotifySink{}, false
+}
+
+// sinksForItem returns the global sinks plus the sinks named by the item.
+func sinksForItem(item SealedItem, cfg Config) []NotifySink {
+	var sinks []NotifySink
+	for _, sink := range cfg.Notify {
+		if sink.Global {
+			sinks = append(sinks, sink)
+			continue
+		}
+		for _, name := range item.Notify {
+			if sink.Name == name {
+				sinks = append(sinks, sink)
+				break
+			}
+		}
+	}
+	return sinks
+}
+
+// notifyItemEvent sends an event to every sink that applies to the item.
+// Notification is best-effort; returns one warning per failed sink.
+func notifyItemEvent(item SealedItem, event string, eventErr error, cfg Config) []string {
+	var warnings []string
+
+	for _, sink := range sinksForItem(item, cfg) {
+		ev := NotifyEvent{
+			Event:      event,
+			ID:         item.ID,
+			UnlockTime: item.UnlockTime,
+		}
+		if eventErr != nil {
+			ev.Error = eventErr.Error()
+		}
+
+		if err := sendNotification(sink, ev); err != nil {
+			warnings = append(warnings, fmt.Sprintf("warning: notification %q failed for item %s: %v", sink.Name, item.ID, err))
+		}
+	}
+
+	return warnings
+}
+
+// sendNotification renders the event message and delivers it to a sink.
+func sendNotification(sink NotifySink, ev NotifyEvent) error {
+	newNotifier, ok := notifierTypes[sink.Type]
+	if !ok {
+		return fmt.Errorf("unknown sink type: %s", sink.Type)
+	}
+
+	message, err := renderNotifyTemplate(sink.Template, ev)
+	if err != nil {
+		return err
+	}
+	ev.Message = message
+
+	return newNotifier(sink).Notify(ev)
+}
+
+// renderNotifyTemplate renders a message template over an event.
+func renderNotifyTemplate(tmpl string, ev NotifyEvent) (string, error) {
+	if tmpl == "" {
+		tmpl = defaultNotifyTemplate
+	}
+
+	t, err := template.New("notify").Parse(tmpl)
+	if err != nil {
+		return "", fmt.Errorf("invalid notification template: %w", err)
+	}
+
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, ev); err != nil {
+		return "", fmt.Errorf("failed to render notification template: %w", err)
+	}
+
+	return buf.String(), nil
+}
+
+// postJSON sends a JSON body and treats any non-2xx status as failure.
+func postJSON(method, url string, body interface{}, header http.Header) error {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("failed to marshal notification: %w", err)
+	}
+
+	req, err := http.NewRequest(method, url, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for key, values := range header {
+		for _, v := range values {
+			req.Header.Add(key, v)
+		}
+	}
+
+	resp, err := notifyHTTPClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("notification request failed: %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+// webhookNotifier posts the full event as JSON.
+type webhookNotifier struct {
+	url string
+}
+
+func (w *webhookNotifier) Notify(ev NotifyEvent) error {
+	return postJSON(http.MethodPost, w.url, ev, nil)
+}
+
+// slackNotifier posts the message to a Slack incoming webhook.
+type slackNotifier struct {
+	url string
+}
+
+func (s *slackNotifier) Notify(ev NotifyEvent) error {
+	return postJSON(http.MethodPost, s.url, map[string]string{"text": ev.Message}, nil)
+}
+
+// matrixNotifier sends the message to a Matrix room via the client-server API.
+type matrixNotifier struct {
+	homeserver string
+	room       string
+	token      string
+}
+
+func (m *matrixNotifier) Notify(ev NotifyEvent) error {
+	if m.room == "" || m.token == "" {
+		return fmt.Errorf("matrix sink requires room and token")
+	}
+
+	// Transaction IDs make retries idempotent on the homeserver
+	txn := make([]byte, 8)
+	if _, err := rand.Read(txn); err != nil {
+		return fmt.Errorf("failed to generate transaction id: %w", err)
+	}
+
+	endpoint := fmt.Sprintf("%s/_matrix/client/v3/rooms/%s/send/m.room.message/%s",
+		strings.TrimRight(m.homeserver, "/"), url.PathEscape(m.room), hex.EncodeToString(txn))
+
+	header := http.Header{}
+	header.Set("Authorization", "Bearer "+m.token)
+
+	return postJSON(http.MethodPut, endpoint, map[string]string{"msgtype": "m.text", "body": ev.Message}, header)
+}
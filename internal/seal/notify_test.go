@@ -0,0 +1,136 @@
+package seal
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+type capturedRequest struct {
+	method string
+	path   string
+	auth   string
+	body   map[string]interface{}
+}
+
+func newCaptureServer(t *testing.T, status int) (*httptest.Server, *[]capturedRequest) {
+	t.Helper()
+	var captured []capturedRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		var body map[string]interface{}
+		json.Unmarshal(data, &body)
+		captured = append(captured, capturedRequest{
+			method: r.Method,
+			path:   r.URL.Path,
+			auth:   r.Header.Get("Authorization"),
+			body:   body,
+		})
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(server.Close)
+	return server, &captured
+}
+
+func TestNotifyItemEvent_SinkTypes(t *testing.T) {
+	server, captured := newCaptureServer(t, http.StatusOK)
+
+	cfg := Config{Notify: []NotifySink{
+		{Name: "hook", Type: NotifySinkWebhook, URL: server.URL + "/hook", Global: true},
+		{Name: "slack", Type: NotifySinkSlack, URL: server.URL + "/slack", Global: true},
+		{Name: "matrix", Type: NotifySinkMatrix, URL: server.URL, Room: "!room:example.org", Token: "secret-token", Global: true},
+	}}
+
+	item := SealedItem{ID: "test-id", State: StateUnlocked, UnlockTime: time.Now().UTC()}
+
+	warnings := notifyItemEvent(item, NotifyEventUnlocked, nil, cfg)
+	if len(warnings) != 0 {
+		t.Fatalf("unexpected warnings: %v", warnings)
+	}
+
+	if len(*captured) != 3 {
+		t.Fatalf("expected 3 requests, got %d", len(*captured))
+	}
+
+	webhook := (*captured)[0]
+	if webhook.method != http.MethodPost || webhook.body["event"] != NotifyEventUnlocked || webhook.body["id"] != "test-id" {
+		t.Errorf("unexpected webhook request: %+v", webhook)
+	}
+
+	slack := (*captured)[1]
+	if slack.body["text"] != "seal item test-id unlocked" {
+		t.Errorf("unexpected slack payload: %+v", slack.body)
+	}
+
+	matrix := (*captured)[2]
+	if matrix.method != http.MethodPut || !strings.HasPrefix(matrix.path, "/_matrix/client/v3/rooms/!room:example.org/send/m.room.message/") {
+		t.Errorf("unexpected matrix request: %+v", matrix)
+	}
+	if matrix.auth != "Bearer secret-token" || matrix.body["msgtype"] != "m.text" {
+		t.Errorf("unexpected matrix auth or payload: %+v", matrix)
+	}
+}
+
+func TestNotifyItemEvent_PerItemSinks(t *testing.T) {
+	server, captured := newCaptureServer(t, http.StatusOK)
+
+	cfg := Config{Notify: []NotifySink{
+		{Name: "team", Type: NotifySinkWebhook, URL: server.URL + "/team"},
+		{Name: "other", Type: NotifySinkWebhook, URL: server.URL + "/other"},
+	}}
+
+	item := SealedItem{ID: "test-id", Notify: []string{"team"}}
+	notifyItemEvent(item, NotifyEventUnlocked, nil, cfg)
+
+	if len(*captured) != 1 || (*captured)[0].path != "/team" {
+		t.Errorf("only the named sink should be notified, got: %+v", *captured)
+	}
+
+	// Items that name no sinks get only global sinks (none here)
+	*captured = nil
+	notifyItemEvent(SealedItem{ID: "other-id"}, NotifyEventUnlocked, nil, cfg)
+	if len(*captured) != 0 {
+		t.Errorf("no sinks should be notified, got: %+v", *captured)
+	}
+}
+
+func TestNotifyItemEvent_TemplateAndFailure(t *testing.T) {
+	server, captured := newCaptureServer(t, http.StatusInternalServerError)
+
+	cfg := Config{Notify: []NotifySink{
+		{Name: "slack", Type: NotifySinkSlack, URL: server.URL, Global: true, Template: "[{{.Event}}] {{.ID}} ({{.Error}})"},
+	}}
+
+	item := SealedItem{ID: "test-id"}
+	warnings := notifyItemEvent(item, NotifyEventUnlockFailed, errors.New("payload corrupt"), cfg)
+
+	if len((*captured)) != 1 || (*captured)[0].body["text"] != "[unlock_failed] test-id (payload corrupt)" {
+		t.Errorf("unexpected payload: %+v", *captured)
+	}
+
+	if len(warnings) != 1 || !strings.Contains(warnings[0], "notification \"slack\" failed") {
+		t.Errorf("expected one warning for failed sink, got: %v", warnings)
+	}
+}
+
+func TestRenderNotifyTemplate_Invalid(t *testing.T) {
+	if _, err := renderNotifyTemplate("{{.Missing", NotifyEvent{}); err == nil {
+		t.Error("expected error for invalid template")
+	}
+}
+
+func TestValidateNotifySinks(t *testing.T) {
+	cfg := Config{Notify: []NotifySink{{Name: "team", Type: NotifySinkWebhook}}}
+
+	if err := ValidateNotifySinks([]string{"team"}, cfg); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if err := ValidateNotifySinks([]string{"missing"}, cfg); err == nil {
+		t.Error("expected error for unknown sink")
+	}
+}
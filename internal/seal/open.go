@@ -0,0 +1,209 @@
+package seal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"seal/internal/clock"
+	"seal/internal/timeauth"
+)
+
+// ErrStillSealed is returned by Open for an item that has not unlocked yet.
+var ErrStillSealed = errors.New("still sealed")
+
+// OpenResult is an unlocked item ready to be read.
+type OpenResult struct {
+	Item      SealedItem
+	Path      string     // unlocked content, see UnsealedPath
+	Warnings  []string   // non-fatal problems from the unlock check, e.g. authority unreachable
+	Countdown *Countdown // time remaining, for an item that is still sealed
+}
+
+// Open returns where an item's unlocked content can be read.
+// A sealed item is checked and materialized first, exactly as status would do it,
+// including reveal delivery and post-processing. The content is verified against
+// the hash recorded at unlock before it is handed out.
+// Returns an error wrapping ErrStillSealed if the item has not unlocked yet.
+func Open(id string) (OpenResult, error) {
+	return openItem(context.Background(), id, nil)
+}
+
+// OpenContext is Open with requests to the time authority bound to ctx.
+func OpenContext(ctx context.Context, id string) (OpenResult, error) {
+	return openItem(ctx, id, nil)
+}
+
+// OpenWithBeacon is Open without network access, for air-gapped machines.
+// A sealed item is unlocked with a drand round fetched elsewhere: beacon holds the
+// chain info and the item's unlock round, verified as described at timeauth.NewBeaconAuthority.
+func OpenWithBeacon(id string, beacon []byte) (OpenResult, error) {
+	return openItem(context.Background(), id, beacon)
+}
+
+// sleepContext sleeps for d, returning early with the context's error if ctx is done.
+// Tests replace it so that waiting takes no time.
+var sleepContext = func(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// OpenWait is OpenContext for an item that may still be sealed: it sleeps until
+// the item's round is due, computed from the round schedule as the daemon does,
+// and checks again until the item opens. Cancelling ctx stops the wait.
+// With maxWait above zero, an item that is not expected to unlock within maxWait
+// of the call fails at once with an error wrapping ErrStillSealed, rather than
+// after waiting. Calls warn for problems reported by checks that did not open it.
+func OpenWait(ctx context.Context, id string, maxWait time.Duration, warn func(msg string)) (OpenResult, error) {
+	start := clock.Now()
+	for {
+		result, err := openItem(ctx, id, nil)
+		if !errors.Is(err, ErrStillSealed) || result.Countdown == nil {
+			return result, err
+		}
+
+		wait := untilDue(result.Item, *result.Countdown, clock.UTC())
+		if maxWait > 0 {
+			left := maxWait - start.Elapsed()
+			if left <= 0 || result.Countdown.Remaining > left {
+				return result, fmt.Errorf("%w; it will not unlock within %s", err, maxWait)
+			}
+			// Check once more at the deadline, in case the round is published early enough
+			wait = min(wait, left)
+		}
+
+		for _, warning := range result.Warnings {
+			warn(warning)
+		}
+		if err := sleepContext(ctx, wait); err != nil {
+			return OpenResult{Item: result.Item}, err
+		}
+	}
+}
+
+// openItem opens an item, unlocking it with the supplied beacon if there is one.
+func openItem(ctx context.Context, id string, beacon []byte) (OpenResult, error) {
+	if err := ctx.Err(); err != nil {
+		return OpenResult{}, err
+	}
+	_, itemDir, err := LoadItem(id)
+	if err != nil {
+		return OpenResult{}, err
+	}
+
+	// Hold the item while it is checked, so a concurrent status or daemon
+	// does not unlock it at the same time
+	lock, err := lockItem(itemDir)
+	if err != nil {
+		return OpenResult{}, err
+	}
+	defer lock.unlock()
+	item, err := loadMetadata(itemDir)
+	if err != nil {
+		return OpenResult{}, err
+	}
+
+	run := newStatusRun()
+	run.authorityFor = func(item SealedItem) timeauth.Authority {
+		if authority := authorityForItem(item); authority != nil {
+			return timeauth.WithContext(ctx, authority)
+		}
+		return nil
+	}
+	if beacon != nil && item.State == StateSealed {
+		authority, err := beaconAuthority(item, beacon)
+		if err != nil {
+			return OpenResult{Item: item}, err
+		}
+		run.authorityFor = func(SealedItem) timeauth.Authority { return authority }
+
+		// Backoff protects the network from retries; a supplied beacon does not use it
+		item.NextUnlockAttempt = nil
+	}
+
+	checked := run.checkLocked(item, itemDir)
+	item = checked.item
+	if err := ctx.Err(); err != nil {
+		return OpenResult{Item: item}, err
+	}
+
+	// Post-unlock actions run without the lock, as in status, but before the
+	// content is handed out; it is verified under the lock again afterwards
+	if checked.valid && (checked.unlocked || postUnlockPending(item)) {
+		lock.unlock()
+		run.runPostUnlock(checked, itemDir)
+		if lock, err = lockItem(itemDir); err != nil {
+			return OpenResult{Item: item}, err
+		}
+		defer lock.unlock()
+		if item, err = loadMetadata(itemDir); err != nil {
+			return OpenResult{}, err
+		}
+	}
+
+	status := run.result()
+	result := OpenResult{Item: item, Warnings: status.Warnings}
+
+	if status.ValidationFailed {
+		return result, status.ValidationErrors[0]
+	}
+
+	if item.State == StateSealed {
+		if reason := permanentLockReason(item); reason != "" {
+			return OpenResult{Item: item}, fmt.Errorf("item %s is permanently locked: %s; it can never be opened", item.ID, reason)
+		}
+		if status.MaterializationFailed {
+			return result, status.FirstError
+		}
+
+		unlockAt := item.UnlockTime
+		if checked.countdown != nil && !checked.countdown.UnlockAt.IsZero() {
+			unlockAt = checked.countdown.UnlockAt
+		}
+		result.Countdown = checked.countdown
+		return result, fmt.Errorf("item %s is %w until %s", item.ID, ErrStillSealed, unlockAt.UTC().Format(time.RFC3339))
+	}
+
+	if item.UnsealedShreddedAt != nil {
+		return result, fmt.Errorf("item %s: unsealed content was shredded at %s (retain-unsealed)", item.ID, item.UnsealedShreddedAt.Format(time.RFC3339))
+	}
+
+	result.Path = UnsealedPath(item, itemDir)
+	if item.UnsealedSHA256 != "" {
+		sum, err := fileSHA256(result.Path)
+		if err != nil {
+			return result, fmt.Errorf("item %s: cannot read unsealed content: %w", item.ID, err)
+		}
+		if sum != item.UnsealedSHA256 {
+			return result, fmt.Errorf("item %s: unsealed content does not match the hash recorded at unlock (corrupted or modified)", item.ID)
+		}
+	}
+
+	return result, nil
+}
+
+// beaconAuthority verifies a supplied beacon for an item. The beacon must be for
+// the item's unlock round exactly, since that round's signature is the decryption key.
+func beaconAuthority(item SealedItem, beacon []byte) (timeauth.Authority, error) {
+	authority, err := timeauth.NewBeaconAuthority(timeauth.KeyReference(item.KeyRef), beacon)
+	if err != nil {
+		return nil, fmt.Errorf("item %s: %w", item.ID, err)
+	}
+
+	targetRound, err := extractTargetRound(item.KeyRef)
+	if err != nil {
+		return nil, fmt.Errorf("item %s: %w", item.ID, err)
+	}
+	if authority.Round() != targetRound {
+		return nil, fmt.Errorf("item %s unlocks at drand round %d, but the beacon is for round %d", item.ID, targetRound, authority.Round())
+	}
+
+	return authority, nil
+}
@@ -0,0 +1,144 @@
+package seal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"seal/internal/testutil"
+	"seal/internal/timeauth"
+)
+
+func TestOpen_StillSealed(t *testing.T) {
+	_, cleanup := testutil.SetupTestEnv(t)
+	defer cleanup()
+
+	// Placeholder items never unlock and are reported as such
+	id, err := CreateSealedItem(time.Now().UTC().Add(time.Hour), InputSourceStdin, "", []byte("data"), &timeauth.PlaceholderAuthority{})
+	if err != nil {
+		t.Fatalf("CreateSealedItem failed: %v", err)
+	}
+	_, err = Open(id)
+	if err == nil || errors.Is(err, ErrStillSealed) || !strings.Contains(err.Error(), "permanently locked") {
+		t.Errorf("expected permanent lock error, got %v", err)
+	}
+
+	if _, err := Open("not-a-uuid"); err == nil {
+		t.Error("expected error for invalid id")
+	}
+}
+
+func TestOpen_UnlockedContent(t *testing.T) {
+	_, cleanup := testutil.SetupTestEnv(t)
+	defer cleanup()
+
+	authority := &timeauth.FakeAuthority{DefaultRound: 100, CurrentRound: 200}
+	id, err := CreateSealedItem(time.Now().UTC().Add(time.Hour), InputSourceStdin, "", []byte("opened"), authority)
+	if err != nil {
+		t.Fatalf("CreateSealedItem failed: %v", err)
+	}
+	item, itemDir, _ := LoadItem(id)
+	if _, err := TryMaterialize(item, itemDir, authority); err != nil {
+		t.Fatalf("TryMaterialize failed: %v", err)
+	}
+
+	result, err := Open(id)
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	data, err := os.ReadFile(result.Path)
+	if err != nil || string(data) != "opened" {
+		t.Errorf("unexpected content %q: %v", data, err)
+	}
+
+	// Modified content is not handed out
+	if err := os.WriteFile(filepath.Join(itemDir, "unsealed"), []byte("changed"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Open(id); err == nil || !strings.Contains(err.Error(), "does not match") {
+		t.Errorf("expected hash mismatch, got %v", err)
+	}
+}
+
+func TestOpenContext_Cancelled(t *testing.T) {
+	_, cleanup := testutil.SetupTestEnv(t)
+	defer cleanup()
+	registerFakeAuthority()
+
+	authority := &timeauth.FakeAuthority{AuthorityName: "registered-fake", DefaultRound: 100}
+	id, err := CreateSealedItem(time.Now().UTC().Add(time.Hour), InputSourceStdin, "", []byte("data"), authority)
+	if err != nil {
+		t.Fatalf("CreateSealedItem failed: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := OpenContext(ctx, id); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+
+	// A cancelled request is not an authority failure and does not delay the next attempt
+	item, itemDir, _ := LoadItem(id)
+	item, _ = recordUnlockFailure(item, itemDir, fmt.Errorf("fetch round: %w", context.Canceled))
+	if item.UnlockFailures != 0 || item.NextUnlockAttempt != nil {
+		t.Errorf("cancellation recorded as a failure: %+v", item)
+	}
+
+	if result, err := OpenContext(context.Background(), id); err != nil || result.Item.State != StateUnlocked {
+		t.Errorf("expected the item to open, got %v", err)
+	}
+}
+
+func TestOpenWait(t *testing.T) {
+	_, cleanup := testutil.SetupTestEnv(t)
+	defer cleanup()
+	registerFakeAuthority()
+
+	var waits []time.Duration
+	originalSleep := sleepContext
+	defer func() { sleepContext = originalSleep }()
+
+	// The registered authority is at round 200; round 201 is due one period later
+	id, err := CreateSealedItem(time.Now().UTC().Add(time.Hour), InputSourceStdin, "", []byte("waited"), &timeauth.FakeAuthority{AuthorityName: "registered-fake", DefaultRound: 201})
+	if err != nil {
+		t.Fatalf("CreateSealedItem failed: %v", err)
+	}
+	sleepContext = func(ctx context.Context, d time.Duration) error {
+		waits = append(waits, d)
+		// The round is published while waiting
+		item, itemDir, _ := LoadItem(id)
+		_, err := TryMaterialize(item, itemDir, &timeauth.FakeAuthority{CurrentRound: 201})
+		return err
+	}
+	result, err := OpenWait(context.Background(), id, time.Minute, func(string) {})
+	if err != nil {
+		t.Fatalf("OpenWait failed: %v", err)
+	}
+	if data, _ := os.ReadFile(result.Path); string(data) != "waited" {
+		t.Errorf("unexpected content %q", data)
+	}
+	if want := 3*time.Second + daemonRoundSlack; len(waits) != 1 || waits[0] != want {
+		t.Errorf("expected one wait of %s, got %v", want, waits)
+	}
+
+	// An item due after the maximum wait fails without waiting
+	waits = nil
+	later, err := CreateSealedItem(time.Now().UTC().Add(time.Hour), InputSourceStdin, "", []byte("later"), &timeauth.FakeAuthority{AuthorityName: "registered-fake", DefaultRound: 300})
+	if err != nil {
+		t.Fatalf("CreateSealedItem failed: %v", err)
+	}
+	if _, err := OpenWait(context.Background(), later, time.Minute, func(string) {}); !errors.Is(err, ErrStillSealed) || len(waits) != 0 {
+		t.Errorf("expected ErrStillSealed without waiting, got %v after %v", err, waits)
+	}
+
+	// Cancelling stops the wait
+	sleepContext = func(ctx context.Context, d time.Duration) error { return context.Canceled }
+	if _, err := OpenWait(context.Background(), later, 0, func(string) {}); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+}
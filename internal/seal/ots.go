@@ -0,0 +1,350 @@
+package seal
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// OpenTimestamps proof format, as written by the ots client (.ots files).
+// A proof is a tree: each node holds a message, attestations made of it, and
+// operations leading from it to further messages.
+const (
+	otsMajorVersion   = 1
+	otsMaxMsgLength   = 4096 // longest message or operation argument
+	otsMaxPayloadSize = 8192 // longest attestation payload
+	otsMaxURILength   = 1000 // longest pending attestation URI
+	otsMaxDepth       = 256  // deepest operation chain
+)
+
+var otsHeaderMagic = []byte("\x00OpenTimestamps\x00\x00Proof\x00\xbf\x89\xe2\xe8\x84\xe8\x92\x94")
+
+// Operation tags. Calendars and Bitcoin merkle paths only use sha256, append
+// and prepend; the RIPEMD-160 and Keccak operations are not supported.
+const (
+	otsOpSHA1    = 0x02
+	otsOpSHA256  = 0x08
+	otsOpAppend  = 0xf0
+	otsOpPrepend = 0xf1
+	otsOpReverse = 0xf2
+	otsOpHexlify = 0xf3
+)
+
+// Attestation tags.
+var (
+	otsPendingTag = [8]byte{0x83, 0xdf, 0xe3, 0x0d, 0x2e, 0xf9, 0x0c, 0x8e}
+	otsBitcoinTag = [8]byte{0x05, 0x88, 0x96, 0x0d, 0x73, 0xd7, 0x19, 0x01}
+)
+
+// otsTimestamp is one node of a proof.
+type otsTimestamp struct {
+	msg          []byte
+	attestations []otsAttestation
+	ops          []otsOp
+}
+
+// otsOp is an operation from a node's message to the next node.
+type otsOp struct {
+	tag  byte
+	arg  []byte // append and prepend only
+	next *otsTimestamp
+}
+
+// otsAttestation states that a node's message was committed somewhere. The
+// payload is kept as read, so attestations this build does not know survive.
+type otsAttestation struct {
+	tag     [8]byte
+	payload []byte
+}
+
+// otsProof is a detached timestamp: proof that data with a given SHA-256 existed.
+type otsProof struct {
+	timestamp *otsTimestamp // msg is the SHA-256 digest of the data
+}
+
+// apply runs an operation on a message.
+func (op otsOp) apply(msg []byte) ([]byte, error) {
+	var result []byte
+	switch op.tag {
+	case otsOpSHA1:
+		sum := sha1.Sum(msg)
+		result = sum[:]
+	case otsOpSHA256:
+		sum := sha256.Sum256(msg)
+		result = sum[:]
+	case otsOpAppend:
+		result = append(append([]byte{}, msg...), op.arg...)
+	case otsOpPrepend:
+		result = append(append([]byte{}, op.arg...), msg...)
+	case otsOpReverse:
+		result = make([]byte, len(msg))
+		for i, b := range msg {
+			result[len(msg)-1-i] = b
+		}
+	case otsOpHexlify:
+		result = []byte(hex.EncodeToString(msg))
+	default:
+		return nil, fmt.Errorf("unsupported operation 0x%02x", op.tag)
+	}
+	if len(result) > otsMaxMsgLength {
+		return nil, errors.New("operation result too long")
+	}
+	return result, nil
+}
+
+// otsOpHasArg reports whether an operation tag takes an argument.
+func otsOpHasArg(tag byte) bool {
+	return tag == otsOpAppend || tag == otsOpPrepend
+}
+
+// newOTSPending returns a pending attestation: the calendar at uri will commit the message later.
+func newOTSPending(uri string) otsAttestation {
+	var payload bytes.Buffer
+	writeOTSVarbytes(&payload, []byte(uri))
+	return otsAttestation{tag: otsPendingTag, payload: payload.Bytes()}
+}
+
+// pendingURI returns the calendar URI of a pending attestation.
+func (a otsAttestation) pendingURI() (string, bool) {
+	if a.tag != otsPendingTag {
+		return "", false
+	}
+	uri, err := readOTSVarbytes(bytes.NewReader(a.payload), otsMaxURILength)
+	if err != nil {
+		return "", false
+	}
+	return string(uri), true
+}
+
+// bitcoinHeight returns the block height of a Bitcoin block header attestation.
+func (a otsAttestation) bitcoinHeight() (uint64, bool) {
+	if a.tag != otsBitcoinTag {
+		return 0, false
+	}
+	height, err := readOTSVaruint(bytes.NewReader(a.payload))
+	if err != nil {
+		return 0, false
+	}
+	return height, true
+}
+
+// walk calls fn for every node of the tree, parents first.
+func (t *otsTimestamp) walk(fn func(node *otsTimestamp)) {
+	fn(t)
+	for _, op := range t.ops {
+		op.next.walk(fn)
+	}
+}
+
+// merge adds the attestations and operations of other, a timestamp of the same message.
+func (t *otsTimestamp) merge(other *otsTimestamp) {
+	for _, attestation := range other.attestations {
+		known := false
+		for _, existing := range t.attestations {
+			if existing.tag == attestation.tag && bytes.Equal(existing.payload, attestation.payload) {
+				known = true
+				break
+			}
+		}
+		if !known {
+			t.attestations = append(t.attestations, attestation)
+		}
+	}
+	for _, op := range other.ops {
+		merged := false
+		for _, existing := range t.ops {
+			if existing.tag == op.tag && bytes.Equal(existing.arg, op.arg) {
+				existing.next.merge(op.next)
+				merged = true
+				break
+			}
+		}
+		if !merged {
+			t.ops = append(t.ops, op)
+		}
+	}
+}
+
+// marshalOTSProof serializes a proof as an .ots file.
+func marshalOTSProof(proof otsProof) []byte {
+	var buf bytes.Buffer
+	buf.Write(otsHeaderMagic)
+	writeOTSVaruint(&buf, otsMajorVersion)
+	buf.WriteByte(otsOpSHA256)
+	buf.Write(proof.timestamp.msg)
+	proof.timestamp.serialize(&buf)
+	return buf.Bytes()
+}
+
+// serialize writes a node: every attestation and operation but the last is
+// preceded by 0xff; an attestation is introduced by 0x00.
+func (t *otsTimestamp) serialize(buf *bytes.Buffer) {
+	count := len(t.attestations) + len(t.ops)
+	i := 0
+	for _, attestation := range t.attestations {
+		if i++; i < count {
+			buf.WriteByte(0xff)
+		}
+		buf.WriteByte(0x00)
+		buf.Write(attestation.tag[:])
+		writeOTSVarbytes(buf, attestation.payload)
+	}
+	for _, op := range t.ops {
+		if i++; i < count {
+			buf.WriteByte(0xff)
+		}
+		buf.WriteByte(op.tag)
+		if otsOpHasArg(op.tag) {
+			writeOTSVarbytes(buf, op.arg)
+		}
+		op.next.serialize(buf)
+	}
+}
+
+// parseOTSProof parses an .ots file for SHA-256 hashed data.
+func parseOTSProof(data []byte) (otsProof, error) {
+	r := bytes.NewReader(data)
+	magic := make([]byte, len(otsHeaderMagic))
+	if _, err := io.ReadFull(r, magic); err != nil || !bytes.Equal(magic, otsHeaderMagic) {
+		return otsProof{}, errors.New("not an OpenTimestamps proof")
+	}
+	version, err := readOTSVaruint(r)
+	if err != nil {
+		return otsProof{}, fmt.Errorf("invalid OpenTimestamps proof: %w", err)
+	}
+	if version != otsMajorVersion {
+		return otsProof{}, fmt.Errorf("unsupported OpenTimestamps proof version %d", version)
+	}
+	if tag, err := r.ReadByte(); err != nil || tag != otsOpSHA256 {
+		return otsProof{}, errors.New("OpenTimestamps proof is not for SHA-256 hashed data")
+	}
+	digest := make([]byte, sha256.Size)
+	if _, err := io.ReadFull(r, digest); err != nil {
+		return otsProof{}, errors.New("invalid OpenTimestamps proof: truncated digest")
+	}
+
+	timestamp, err := parseOTSTimestamp(r, digest, otsMaxDepth)
+	if err != nil {
+		return otsProof{}, fmt.Errorf("invalid OpenTimestamps proof: %w", err)
+	}
+	if r.Len() != 0 {
+		return otsProof{}, errors.New("invalid OpenTimestamps proof: trailing data")
+	}
+	return otsProof{timestamp: timestamp}, nil
+}
+
+// parseOTSTimestamp reads a serialized node for msg, as returned by calendars.
+func parseOTSTimestamp(r *bytes.Reader, msg []byte, depth int) (*otsTimestamp, error) {
+	if depth == 0 {
+		return nil, errors.New("operations nested too deeply")
+	}
+	t := &otsTimestamp{msg: msg}
+
+	readItem := func(tag byte) error {
+		if tag == 0x00 {
+			var attestation otsAttestation
+			if _, err := io.ReadFull(r, attestation.tag[:]); err != nil {
+				return errors.New("truncated attestation")
+			}
+			payload, err := readOTSVarbytes(r, otsMaxPayloadSize)
+			if err != nil {
+				return err
+			}
+			attestation.payload = payload
+			t.attestations = append(t.attestations, attestation)
+			return nil
+		}
+
+		op := otsOp{tag: tag}
+		if otsOpHasArg(tag) {
+			arg, err := readOTSVarbytes(r, otsMaxMsgLength)
+			if err != nil {
+				return err
+			}
+			op.arg = arg
+		}
+		result, err := op.apply(msg)
+		if err != nil {
+			return err
+		}
+		if op.next, err = parseOTSTimestamp(r, result, depth-1); err != nil {
+			return err
+		}
+		t.ops = append(t.ops, op)
+		return nil
+	}
+
+	for {
+		tag, err := r.ReadByte()
+		if err != nil {
+			return nil, errors.New("truncated timestamp")
+		}
+		if tag != 0xff {
+			return t, readItem(tag)
+		}
+		if tag, err = r.ReadByte(); err != nil {
+			return nil, errors.New("truncated timestamp")
+		}
+		if err := readItem(tag); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// writeOTSVaruint writes an unsigned LEB128 integer.
+func writeOTSVaruint(buf *bytes.Buffer, value uint64) {
+	var tmp [binary.MaxVarintLen64]byte
+	buf.Write(tmp[:binary.PutUvarint(tmp[:], value)])
+}
+
+// readOTSVaruint reads an unsigned LEB128 integer.
+func readOTSVaruint(r io.ByteReader) (uint64, error) {
+	value, err := binary.ReadUvarint(r)
+	if err != nil {
+		return 0, errors.New("invalid integer")
+	}
+	return value, nil
+}
+
+// writeOTSVarbytes writes a length-prefixed byte string.
+func writeOTSVarbytes(buf *bytes.Buffer, data []byte) {
+	writeOTSVaruint(buf, uint64(len(data)))
+	buf.Write(data)
+}
+
+// readOTSVarbytes reads a length-prefixed byte string of at most max bytes.
+func readOTSVarbytes(r *bytes.Reader, max int) ([]byte, error) {
+	length, err := readOTSVaruint(r)
+	if err != nil {
+		return nil, err
+	}
+	if length > uint64(max) || length > uint64(r.Len()) {
+		return nil, errors.New("invalid length")
+	}
+	data := make([]byte, length)
+	if _, err := io.ReadFull(r, data); err != nil {
+		return nil, errors.New("truncated data")
+	}
+	return data, nil
+}
+
+// validOTSCalendarURI reports whether a pending attestation names a calendar
+// that may be asked for an upgrade: an https URL of plain characters, as the ots
+// client requires, so a proof cannot direct requests anywhere else.
+func validOTSCalendarURI(uri string) bool {
+	if !strings.HasPrefix(uri, "https://") {
+		return false
+	}
+	for _, c := range uri {
+		if !strings.ContainsRune("abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789-._/:", c) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,86 @@
+package seal
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"strings"
+	"testing"
+)
+
+func TestOTSProof_RoundTrip(t *testing.T) {
+	digest := sha256.Sum256([]byte("payload"))
+	// Two calendars under one node, one of them already upgraded
+	commitment := sha256.Sum256(digest[:])
+	root := (&fakeCalendar{commitment: commitment[:]}).upgrade()
+	root.attestations = []otsAttestation{newOTSPending("https://a.example"), newOTSPending("https://b.example")}
+	hashed := otsOp{tag: otsOpSHA256, next: root}
+	proof := otsProof{timestamp: &otsTimestamp{msg: digest[:], ops: []otsOp{hashed}}}
+
+	data := marshalOTSProof(proof)
+	if !bytes.HasPrefix(data, otsHeaderMagic) {
+		t.Fatalf("missing header magic")
+	}
+	parsed, err := parseOTSProof(data)
+	if err != nil {
+		t.Fatalf("parseOTSProof failed: %v", err)
+	}
+	if !bytes.Equal(marshalOTSProof(parsed), data) {
+		t.Error("proof changed in a round trip")
+	}
+
+	var uris []string
+	var heights []uint64
+	parsed.timestamp.walk(func(node *otsTimestamp) {
+		for _, attestation := range node.attestations {
+			if uri, ok := attestation.pendingURI(); ok {
+				uris = append(uris, uri)
+			}
+			if height, ok := attestation.bitcoinHeight(); ok {
+				heights = append(heights, height)
+			}
+		}
+	})
+	if strings.Join(uris, ",") != "https://a.example,https://b.example" || len(heights) != 1 || heights[0] != 100 {
+		t.Errorf("unexpected attestations: %v %v", uris, heights)
+	}
+
+	// Merging a timestamp already in the proof changes nothing
+	parsed.timestamp.merge(proof.timestamp)
+	if !bytes.Equal(marshalOTSProof(parsed), data) {
+		t.Error("merging a known timestamp changed the proof")
+	}
+}
+
+func TestParseOTSProof_Invalid(t *testing.T) {
+	digest := sha256.Sum256([]byte("payload"))
+	valid := marshalOTSProof(otsProof{timestamp: &otsTimestamp{msg: digest[:], attestations: []otsAttestation{newOTSPending("https://a.example")}}})
+
+	unsupported := append([]byte{}, valid[:len(otsHeaderMagic)+2+sha256.Size]...)
+	unsupported = append(unsupported, 0x67, 0x00) // keccak256
+
+	for name, data := range map[string][]byte{
+		"empty":       nil,
+		"bad magic":   append([]byte("x"), valid[1:]...),
+		"truncated":   valid[:len(valid)-3],
+		"trailing":    append(append([]byte{}, valid...), 0x00),
+		"unsupported": unsupported,
+	} {
+		if _, err := parseOTSProof(data); err == nil {
+			t.Errorf("%s: expected the proof to be refused", name)
+		}
+	}
+}
+
+func TestValidOTSCalendarURI(t *testing.T) {
+	for uri, want := range map[string]bool{
+		"https://alice.btc.calendar.opentimestamps.org": true,
+		"https://127.0.0.1:8443":                        true,
+		"http://alice.btc.calendar.opentimestamps.org":  false,
+		"https://evil.example/?q=1":                     false,
+		"https://evil.example/\n":                       false,
+	} {
+		if got := validOTSCalendarURI(uri); got != want {
+			t.Errorf("validOTSCalendarURI(%q) = %v, want %v", uri, got, want)
+		}
+	}
+}
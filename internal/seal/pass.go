@@ -0,0 +1,95 @@
+package seal
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// defaultPassCommand is the password store CLI used when config names none.
+// gopass accepts the same show and insert arguments.
+const defaultPassCommand = "pass"
+
+// runPass runs the password store CLI. Replaced in tests.
+var runPass = runExternal
+
+// runExternal runs an external CLI with stdin and returns its output.
+// Secrets are passed on stdin, never as arguments, so they do not show up in the process list.
+func runExternal(name string, args []string, stdin []byte) ([]byte, error) {
+	cmd := exec.Command(name, args...)
+	cmd.Stdin = bytes.NewReader(stdin)
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	output, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("%s: %s", name, msg)
+		}
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	return output, nil
+}
+
+// passCommand returns the pass-compatible CLI configured for this store.
+func passCommand(cfg Config) string {
+	if cfg.PassCommand != "" {
+		return cfg.PassCommand
+	}
+	return defaultPassCommand
+}
+
+// ValidatePassEntry checks a password store entry name.
+// Entries are relative paths inside the store, never options or parent references.
+func ValidatePassEntry(entry string) error {
+	if entry == "" {
+		return errors.New("pass entry is empty")
+	}
+	if strings.HasPrefix(entry, "-") || strings.HasPrefix(entry, "/") {
+		return fmt.Errorf("invalid pass entry: %s", entry)
+	}
+	for _, part := range strings.Split(entry, "/") {
+		if part == "" || part == "." || part == ".." {
+			return fmt.Errorf("invalid pass entry: %s", entry)
+		}
+	}
+	return nil
+}
+
+// ReadPassEntry reads a secret from the password store, exactly as `pass show` prints it.
+func ReadPassEntry(entry string, cfg Config) ([]byte, error) {
+	if err := ValidatePassEntry(entry); err != nil {
+		return nil, err
+	}
+
+	data, err := runPass(passCommand(cfg), []string{"show", entry}, nil)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read pass entry %s: %w", entry, err)
+	}
+
+	if len(data) == 0 {
+		return nil, errors.New("input is empty")
+	}
+	if len(data) > MaxInputSize {
+		return nil, fmt.Errorf("input exceeds maximum size of %d bytes", MaxInputSize)
+	}
+
+	return data, nil
+}
+
+// deliverPass writes unlocked content into a password store entry, replacing it.
+func deliverPass(item SealedItem, itemDir string, entry string, cfg Config) error {
+	content, err := os.ReadFile(UnsealedPath(item, itemDir))
+	if err != nil {
+		return fmt.Errorf("cannot read unsealed content: %w", err)
+	}
+
+	if _, err := runPass(passCommand(cfg), []string{"insert", "--multiline", "--force", entry}, content); err != nil {
+		return fmt.Errorf("cannot write pass entry %s: %w", entry, err)
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+package seal
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// passCall records one invocation of the password store CLI.
+type passCall struct {
+	name  string
+	args  []string
+	stdin string
+}
+
+// stubPass replaces the password store CLI for the duration of a test.
+func stubPass(t *testing.T, output string, err error) *[]passCall {
+	t.Helper()
+	var calls []passCall
+	orig := runPass
+	runPass = func(name string, args []string, stdin []byte) ([]byte, error) {
+		calls = append(calls, passCall{name, args, string(stdin)})
+		return []byte(output), err
+	}
+	t.Cleanup(func() { runPass = orig })
+	return &calls
+}
+
+func TestReadPassEntry(t *testing.T) {
+	calls := stubPass(t, "hunter2\n", nil)
+
+	data, err := ReadPassEntry("web/example", Config{PassCommand: "gopass"})
+	if err != nil {
+		t.Fatalf("ReadPassEntry failed: %v", err)
+	}
+	if string(data) != "hunter2\n" {
+		t.Errorf("got %q, want the entry exactly as shown", data)
+	}
+
+	want := []passCall{{"gopass", []string{"show", "web/example"}, ""}}
+	if !reflect.DeepEqual(*calls, want) {
+		t.Errorf("got calls %+v, want %+v", *calls, want)
+	}
+}
+
+func TestReadPassEntry_Errors(t *testing.T) {
+	stubPass(t, "", errors.New("pass: web/missing is not in the password store"))
+	if _, err := ReadPassEntry("web/missing", Config{}); err == nil || !strings.Contains(err.Error(), "not in the password store") {
+		t.Errorf("expected store error, got %v", err)
+	}
+
+	calls := stubPass(t, "", nil)
+	for _, entry := range []string{"", "-c", "/etc/passwd", "web/../../x", "web//example"} {
+		if _, err := ReadPassEntry(entry, Config{}); err == nil {
+			t.Errorf("expected error for entry %q", entry)
+		}
+	}
+	if len(*calls) != 0 {
+		t.Errorf("invalid entries must not reach the store, got %+v", *calls)
+	}
+}
+
+func TestDeliverPendingReveal_Pass(t *testing.T) {
+	item, itemDir := newUnlockedRevealItem(t, "new credential")
+	item.RevealTo = "pass:web/example"
+
+	calls := stubPass(t, "", nil)
+
+	updated, err := deliverPendingReveal(item, itemDir, Config{})
+	if err != nil {
+		t.Fatalf("deliverPendingReveal failed: %v", err)
+	}
+	if updated.RevealStatus != RevealStatusDelivered {
+		t.Errorf("expected delivered, got %s", updated.RevealStatus)
+	}
+
+	want := []passCall{{"pass", []string{"insert", "--multiline", "--force", "web/example"}, "new credential"}}
+	if !reflect.DeepEqual(*calls, want) {
+		t.Errorf("got calls %+v, want %+v", *calls, want)
+	}
+}
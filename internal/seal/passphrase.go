@@ -0,0 +1,157 @@
+package seal
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"unicode/utf8"
+
+	"golang.org/x/crypto/argon2"
+)
+
+// PassphraseKDFArgon2id is the only passphrase key derivation in use.
+const PassphraseKDFArgon2id = "argon2id"
+
+// Argon2id parameters for new items (RFC 9106, second recommended option).
+// Items record their own parameters, so these can be raised later.
+const (
+	argon2Time      = 3
+	argon2MemoryKiB = 64 * 1024
+	argon2Threads   = 4
+	argon2SaltSize  = 16
+)
+
+// Parameters above these limits are refused when opening, so metadata cannot
+// make seal allocate or compute without bound.
+const (
+	maxArgon2Time      = 16
+	maxArgon2MemoryKiB = 1024 * 1024
+)
+
+// maxPassphraseSize caps a passphrase file; a passphrase is one line.
+const maxPassphraseSize = 4096
+
+// PassphraseProtection records how an item's content was encrypted with a passphrase.
+// The salt and nonce are not secret.
+type PassphraseProtection struct {
+	KDF       string `json:"kdf"`
+	Salt      string `json:"salt"` // base64
+	Time      uint32 `json:"time"`
+	MemoryKiB uint32 `json:"memory_kib"`
+	Threads   uint8  `json:"threads"`
+	Nonce     string `json:"nonce"` // base64 AES-256-GCM nonce
+}
+
+// ReadPassphraseFile reads a passphrase from the first line of a file, which may
+// be a pipe such as /dev/fd/3. Seal never prompts for one.
+// One trailing newline is removed; the passphrase must be a single non-empty line.
+// A weak passphrase is reported as a warning.
+func ReadPassphraseFile(path string) ([]byte, []string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("cannot read passphrase file: %w", err)
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(io.LimitReader(file, maxPassphraseSize+1))
+	if err != nil {
+		return nil, nil, fmt.Errorf("cannot read passphrase file: %w", err)
+	}
+	if len(data) > maxPassphraseSize {
+		return nil, nil, errors.New("passphrase file is too large")
+	}
+
+	passphrase, _ := stripTrailingNewline(data)
+	switch {
+	case len(passphrase) == 0:
+		return nil, nil, errors.New("passphrase is empty")
+	case bytes.ContainsAny(passphrase, "\r\n"):
+		return nil, nil, errors.New("passphrase spans multiple lines")
+	}
+
+	var warnings []string
+	if utf8.Valid(passphrase) {
+		if bits := EstimatePasswordBits(string(passphrase)); bits < weakPasswordBits {
+			warnings = append(warnings, fmt.Sprintf("warning: passphrase is weak (estimated %.0f bits of entropy); once the item unlocks, its content can be guessed offline", bits))
+		}
+	}
+	return passphrase, warnings, nil
+}
+
+// encryptWithPassphrase encrypts plaintext with a key derived from passphrase by Argon2id.
+func encryptWithPassphrase(plaintext, passphrase []byte) ([]byte, *PassphraseProtection, error) {
+	salt := make([]byte, argon2SaltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, nil, err
+	}
+	protection := &PassphraseProtection{
+		KDF:       PassphraseKDFArgon2id,
+		Salt:      base64.StdEncoding.EncodeToString(salt),
+		Time:      argon2Time,
+		MemoryKiB: argon2MemoryKiB,
+		Threads:   argon2Threads,
+	}
+
+	key := argon2.IDKey(passphrase, salt, protection.Time, protection.MemoryKiB, protection.Threads, 32)
+	defer clear(key)
+
+	gcm, err := newPayloadGCM(key)
+	if err != nil {
+		return nil, nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, nil, err
+	}
+	protection.Nonce = base64.StdEncoding.EncodeToString(nonce)
+
+	return gcm.Seal(nil, nonce, plaintext, nil), protection, nil
+}
+
+// DecryptWithPassphrase returns the content of an item sealed with a passphrase.
+// content is the unlocked content as Open returns it.
+func DecryptWithPassphrase(item SealedItem, content io.Reader, passphrase []byte) ([]byte, error) {
+	p := item.Passphrase
+	if p == nil {
+		return nil, fmt.Errorf("item %s was not sealed with a passphrase", item.ID)
+	}
+	if p.KDF != PassphraseKDFArgon2id {
+		return nil, fmt.Errorf("item %s: unsupported passphrase kdf %q", item.ID, p.KDF)
+	}
+	if p.Time == 0 || p.Time > maxArgon2Time || p.MemoryKiB == 0 || p.MemoryKiB > maxArgon2MemoryKiB || p.Threads == 0 {
+		return nil, fmt.Errorf("item %s: invalid argon2id parameters in metadata", item.ID)
+	}
+	salt, err := base64.StdEncoding.DecodeString(p.Salt)
+	if err != nil {
+		return nil, fmt.Errorf("item %s: invalid passphrase salt: %w", item.ID, err)
+	}
+	nonce, err := base64.StdEncoding.DecodeString(p.Nonce)
+	if err != nil {
+		return nil, fmt.Errorf("item %s: invalid passphrase nonce: %w", item.ID, err)
+	}
+
+	ciphertext, err := io.ReadAll(io.LimitReader(content, MaxInputSize+1024))
+	if err != nil {
+		return nil, fmt.Errorf("cannot read unlocked content: %w", err)
+	}
+
+	key := argon2.IDKey(passphrase, salt, p.Time, p.MemoryKiB, p.Threads, 32)
+	defer clear(key)
+
+	gcm, err := newPayloadGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(nonce) != gcm.NonceSize() {
+		return nil, fmt.Errorf("item %s: invalid passphrase nonce", item.ID)
+	}
+	plaintext, err := gcm.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return nil, fmt.Errorf("item %s: wrong passphrase or corrupted content", item.ID)
+	}
+	return plaintext, nil
+}
@@ -0,0 +1,97 @@
+package seal
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"seal/internal/testutil"
+)
+
+func TestLock_Passphrase(t *testing.T) {
+	_, cleanup := testutil.SetupTestEnv(t)
+	defer cleanup()
+	registerFakeAuthority()
+
+	unlockTime := time.Now().UTC().Add(time.Hour).Format(time.RFC3339)
+	passphrase := []byte("correct horse battery staple")
+	result, err := Lock(LockRequest{Data: []byte("time and passphrase"), UnlockTime: unlockTime, Authority: "registered-fake", Stdin: StdinNever, Passphrase: passphrase})
+	if err != nil {
+		t.Fatalf("Lock failed: %v", err)
+	}
+
+	item, _, _ := LoadItem(result.ID)
+	if item.Passphrase == nil || item.Passphrase.KDF != PassphraseKDFArgon2id || item.Passphrase.Salt == "" {
+		t.Fatalf("passphrase protection not recorded: %+v", item.Passphrase)
+	}
+	if !strings.Contains(FormatInspectOutput(item, nil, false), "passphrase: argon2id (t=3, m=65536 KiB, p=4)") {
+		t.Errorf("inspect does not show the passphrase:\n%s", FormatInspectOutput(item, nil, false))
+	}
+
+	// The item unlocks on time, but its content stays encrypted
+	opened, err := Open(result.ID)
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	content, _ := os.ReadFile(opened.Path)
+	if bytes.Contains(content, []byte("time and passphrase")) {
+		t.Fatal("unlocked content should be encrypted with the passphrase")
+	}
+
+	data, err := DecryptWithPassphrase(opened.Item, bytes.NewReader(content), passphrase)
+	if err != nil || string(data) != "time and passphrase" {
+		t.Errorf("expected the content, got %q, %v", data, err)
+	}
+	if _, err := DecryptWithPassphrase(opened.Item, bytes.NewReader(content), []byte("wrong horse battery staple")); err == nil || !strings.Contains(err.Error(), "wrong passphrase") {
+		t.Errorf("expected a wrong passphrase to be refused, got %v", err)
+	}
+
+	for name, req := range map[string]LockRequest{
+		"recipient":    {Data: []byte("data"), UnlockTime: unlockTime, Stdin: StdinNever, Passphrase: passphrase, Recipient: "age1ql3z7hjy54pw3hyww5ayyfg7zqgvc7w3j2elw8zmrj2kg5sfn9aqmcac8p"},
+		"post-process": {Data: []byte("data"), UnlockTime: unlockTime, Stdin: StdinNever, Passphrase: passphrase, PostProcess: []string{"gunzip"}},
+		"empty":        {Data: []byte("data"), UnlockTime: unlockTime, Stdin: StdinNever, Passphrase: []byte{}},
+	} {
+		if _, err := Lock(req); err == nil {
+			t.Errorf("expected %s to be refused", name)
+		}
+	}
+}
+
+func TestDecryptWithPassphrase_RefusesUnboundedParameters(t *testing.T) {
+	item := SealedItem{ID: "x", Passphrase: &PassphraseProtection{KDF: PassphraseKDFArgon2id, Time: 1, MemoryKiB: 4 * 1024 * 1024, Threads: 1}}
+	if _, err := DecryptWithPassphrase(item, strings.NewReader(""), []byte("p")); err == nil || !strings.Contains(err.Error(), "invalid argon2id parameters") {
+		t.Errorf("expected excessive memory to be refused, got %v", err)
+	}
+}
+
+func TestReadPassphraseFile(t *testing.T) {
+	dir := t.TempDir()
+	write := func(content string) string {
+		path := filepath.Join(dir, "passphrase")
+		if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	passphrase, warnings, err := ReadPassphraseFile(write("correct horse battery staple\n"))
+	if err != nil || string(passphrase) != "correct horse battery staple" || len(warnings) != 0 {
+		t.Errorf("unexpected result %q, %v, %v", passphrase, warnings, err)
+	}
+
+	if _, warnings, err := ReadPassphraseFile(write("hunter2\n")); err != nil || len(warnings) != 1 || !strings.Contains(warnings[0], "weak") {
+		t.Errorf("expected a weak passphrase warning, got %v, %v", warnings, err)
+	}
+
+	for _, content := range []string{"", "\n", "two\nlines\n"} {
+		if _, _, err := ReadPassphraseFile(write(content)); err == nil {
+			t.Errorf("expected %q to be refused", content)
+		}
+	}
+	if _, _, err := ReadPassphraseFile(filepath.Join(dir, "missing")); err == nil {
+		t.Error("expected a missing file to be refused")
+	}
+}
@@ -0,0 +1,122 @@
+package seal
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// weakPasswordBits is the estimated entropy below which a sealed password is reported as weak.
+const weakPasswordBits = 50
+
+// commonPasswordWords are fragments that attackers try first. A password
+// containing one gains almost nothing from it.
+var commonPasswordWords = []string{
+	"password", "passw0rd", "qwerty", "asdf", "zxcv", "letmein", "welcome",
+	"admin", "login", "iloveyou", "monkey", "dragon", "master", "secret",
+	"abc123", "123456", "111111", "000000",
+}
+
+// NormalizePassword prepares input for password mode.
+// One trailing newline (LF or CRLF), as added by echo or an editor, is removed.
+// Input that cannot be a single password is rejected: empty, spanning several
+// lines, containing control characters, or not valid UTF-8.
+// Leading or trailing spaces are kept but reported as a warning.
+func NormalizePassword(data []byte) ([]byte, []string, error) {
+	password, _ := stripTrailingNewline(data)
+
+	if len(password) == 0 {
+		return nil, nil, errors.New("password is empty")
+	}
+	if !utf8.Valid(password) {
+		return nil, nil, errors.New("password is not valid UTF-8")
+	}
+	if bytes.ContainsAny(password, "\r\n") {
+		return nil, nil, errors.New("password spans multiple lines")
+	}
+	for _, r := range string(password) {
+		if unicode.IsControl(r) {
+			return nil, nil, fmt.Errorf("password contains control character %U", r)
+		}
+	}
+
+	var warnings []string
+	if trimmed := bytes.TrimSpace(password); len(trimmed) != len(password) {
+		warnings = append(warnings, "warning: password has leading or trailing spaces; they are sealed as part of it")
+	}
+
+	if bits := EstimatePasswordBits(string(password)); bits < weakPasswordBits {
+		warnings = append(warnings, fmt.Sprintf("warning: password is weak (estimated %.0f bits of entropy); a sealed password cannot be changed", bits))
+	}
+
+	return password, warnings, nil
+}
+
+// EstimatePasswordBits returns a rough entropy estimate in the spirit of zxcvbn.
+// Each character is worth log2 of the character classes in use, except that
+// repeats and ascending or descending runs (aaa, abc, 321) count as one bit,
+// and common password fragments count as a single guess among a small dictionary.
+func EstimatePasswordBits(password string) float64 {
+	lower := strings.ToLower(password)
+	for _, word := range commonPasswordWords {
+		lower = strings.ReplaceAll(lower, word, "\x00")
+	}
+
+	runes := []rune(lower)
+	perChar := math.Log2(float64(passwordPoolSize(password)))
+
+	bits := 0.0
+	for i, r := range runes {
+		switch {
+		case r == 0:
+			bits += 10 // one guess among ~1000 common fragments
+		case i > 0 && (r == runes[i-1] || r == runes[i-1]+1 || r == runes[i-1]-1):
+			bits++
+		default:
+			bits += perChar
+		}
+	}
+
+	return bits
+}
+
+// passwordPoolSize returns the size of the character classes a password draws from.
+func passwordPoolSize(password string) int {
+	var lower, upper, digit, symbol, other bool
+	for _, r := range password {
+		switch {
+		case r >= 'a' && r <= 'z':
+			lower = true
+		case r >= 'A' && r <= 'Z':
+			upper = true
+		case r >= '0' && r <= '9':
+			digit = true
+		case r < utf8.RuneSelf:
+			symbol = true
+		default:
+			other = true
+		}
+	}
+
+	pool := 0
+	if lower {
+		pool += 26
+	}
+	if upper {
+		pool += 26
+	}
+	if digit {
+		pool += 10
+	}
+	if symbol {
+		pool += 33
+	}
+	if other {
+		pool += 100
+	}
+	return max(pool, 2)
+}
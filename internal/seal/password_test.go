@@ -0,0 +1,81 @@
+package seal
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNormalizePassword_StripsOneTrailingNewline(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"correct horse battery staple\n", "correct horse battery staple"},
+		{"correct horse battery staple\r\n", "correct horse battery staple"},
+		{"correct horse battery staple", "correct horse battery staple"},
+	}
+
+	for _, tt := range tests {
+		got, _, err := NormalizePassword([]byte(tt.input))
+		if err != nil {
+			t.Errorf("NormalizePassword(%q) failed: %v", tt.input, err)
+			continue
+		}
+		if string(got) != tt.want {
+			t.Errorf("NormalizePassword(%q) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestNormalizePassword_RejectsNonPasswords(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"\n", "empty"},
+		{"first\nsecond\n", "multiple lines"},
+		{"tab\tinside", "control character"},
+		{"\xff\xfe", "UTF-8"},
+	}
+
+	for _, tt := range tests {
+		_, _, err := NormalizePassword([]byte(tt.input))
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("NormalizePassword(%q): expected error containing %q, got %v", tt.input, tt.want, err)
+		}
+	}
+}
+
+func TestNormalizePassword_Warnings(t *testing.T) {
+	_, warnings, err := NormalizePassword([]byte("password123\n"))
+	if err != nil {
+		t.Fatalf("NormalizePassword failed: %v", err)
+	}
+	if len(warnings) != 1 || !strings.Contains(warnings[0], "weak") {
+		t.Errorf("expected a weak password warning, got %v", warnings)
+	}
+
+	_, warnings, err = NormalizePassword([]byte(" vK9#mQ2$xL7@pR4!wT6 \n"))
+	if err != nil {
+		t.Fatalf("NormalizePassword failed: %v", err)
+	}
+	if len(warnings) != 1 || !strings.Contains(warnings[0], "spaces") {
+		t.Errorf("expected only a spaces warning, got %v", warnings)
+	}
+}
+
+func TestEstimatePasswordBits(t *testing.T) {
+	weak := []string{"password", "123456789", "aaaaaaaaaaaa", "abcdefghijkl", "Qwerty2024"}
+	for _, password := range weak {
+		if bits := EstimatePasswordBits(password); bits >= weakPasswordBits {
+			t.Errorf("%q: expected weak estimate, got %.1f bits", password, bits)
+		}
+	}
+
+	strong := []string{"vK9#mQ2$xL7@pR4!", "correct horse battery staple"}
+	for _, password := range strong {
+		if bits := EstimatePasswordBits(password); bits < weakPasswordBits {
+			t.Errorf("%q: expected strong estimate, got %.1f bits", password, bits)
+		}
+	}
+}
@@ -0,0 +1,124 @@
+package seal
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"seal/internal/clock"
+	"seal/internal/timeauth"
+)
+
+// errEmptyWrite indicates a writer connected and disconnected without sending data.
+var errEmptyWrite = errors.New("empty write")
+
+// PipeRequest contains parameters for sealing writes to a named pipe.
+type PipeRequest struct {
+	FIFOPath   string
+	UnlockTime string
+}
+
+// ServePipe listens on a named pipe and seals each write as a new item.
+// A write is everything received between a writer opening the pipe and EOF.
+// Calls sealed with the ID of each new item and warn for writes that were rejected.
+// Runs until a fatal error occurs (e.g. the unlock time is no longer in the future).
+func ServePipe(req PipeRequest, sealed func(id string), warn func(msg string)) error {
+	unlockTime, err := ParseUnlockTime(req.UnlockTime)
+	if err != nil {
+		return err
+	}
+
+	info, err := os.Stat(req.FIFOPath)
+	if err != nil {
+		return fmt.Errorf("cannot stat fifo: %w", err)
+	}
+
+	if info.Mode()&os.ModeNamedPipe == 0 {
+		return fmt.Errorf("not a named pipe: %s", req.FIFOPath)
+	}
+
+	authority := timeauth.NewDefaultAuthority()
+
+	// Every write shares the unlock time, so the policy is checked once
+	policy, err := LoadPolicy()
+	if err != nil {
+		return err
+	}
+	if err := policy.Check(PolicyRequest{UnlockTime: unlockTime, Authority: authority.Name()}, clock.UTC()); err != nil {
+		return err
+	}
+
+	cfg, err := LoadConfig()
+	if err != nil {
+		return err
+	}
+
+	for {
+		id, err := sealPipeWrite(req.FIFOPath, unlockTime, authority, cfg.MinInputSize)
+		if errors.Is(err, errEmptyWrite) {
+			continue
+		}
+
+		var rejected *rejectedWriteError
+		if errors.As(err, &rejected) {
+			warn(fmt.Sprintf("warning: write rejected: %v", rejected.err))
+			continue
+		}
+
+		if err != nil {
+			return err
+		}
+
+		sealed(id)
+	}
+}
+
+// rejectedWriteError marks a single write that could not be sealed.
+// The pipe keeps serving after a rejected write.
+type rejectedWriteError struct {
+	err error
+}
+
+func (e *rejectedWriteError) Error() string {
+	return e.err.Error()
+}
+
+// sealPipeWrite waits for one writer on the pipe and seals what it wrote.
+// Writes that are only whitespace or smaller than minSize bytes are rejected.
+func sealPipeWrite(fifoPath string, unlockTime time.Time, authority timeauth.Authority, minSize int) (string, error) {
+	// Opening a FIFO for reading blocks until a writer connects
+	file, err := os.Open(fifoPath)
+	if err != nil {
+		return "", fmt.Errorf("cannot open fifo: %w", err)
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(io.LimitReader(file, MaxInputSize+1))
+	if err != nil {
+		return "", fmt.Errorf("cannot read fifo: %w", err)
+	}
+
+	if len(data) > MaxInputSize {
+		// Drain the rest of the write so the writer is not blocked
+		io.Copy(io.Discard, file)
+		return "", &rejectedWriteError{fmt.Errorf("input exceeds maximum size of %d bytes", MaxInputSize)}
+	}
+
+	if len(data) == 0 {
+		return "", errEmptyWrite
+	}
+
+	// An accidental write would otherwise stay sealed until the unlock time
+	if err := checkInputContent(data, minSize); err != nil {
+		return "", &rejectedWriteError{err}
+	}
+
+	// The unlock time was validated at startup but the pipe may outlive it
+	if !unlockTime.After(clock.UTC()) {
+		return "", errors.New("unlock time must be in the future")
+	}
+
+	return CreateSealedItem(unlockTime, InputSourcePipe, fifoPath, data, authority)
+}
@@ -0,0 +1,154 @@
+//go:build unix
+
+package seal
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"seal/internal/testutil"
+	"seal/internal/timeauth"
+)
+
+func makeTestFIFO(t *testing.T) string {
+	t.Helper()
+	fifoPath := filepath.Join(t.TempDir(), "drop")
+	if err := syscall.Mkfifo(fifoPath, 0600); err != nil {
+		t.Fatalf("mkfifo failed: %v", err)
+	}
+	return fifoPath
+}
+
+func writeToFIFO(t *testing.T, fifoPath string, data []byte) {
+	t.Helper()
+	go func() {
+		// Opening for write blocks until the reader connects
+		f, err := os.OpenFile(fifoPath, os.O_WRONLY, 0)
+		if err != nil {
+			return
+		}
+		f.Write(data)
+		f.Close()
+	}()
+}
+
+func TestSealPipeWrite_SealsEachWrite(t *testing.T) {
+	tmpHome, cleanup := testutil.SetupTestEnv(t)
+	defer cleanup()
+
+	fifoPath := makeTestFIFO(t)
+	authority := &timeauth.FakeAuthority{DefaultRound: 1000}
+	unlockTime := time.Now().UTC().Add(time.Hour)
+
+	writeToFIFO(t, fifoPath, []byte("first drop"))
+	id, err := sealPipeWrite(fifoPath, unlockTime, authority, 0)
+	if err != nil {
+		t.Fatalf("sealPipeWrite failed: %v", err)
+	}
+
+	item, _, err := LoadItem(id)
+	if err != nil {
+		t.Fatalf("LoadItem failed: %v", err)
+	}
+
+	if item.InputType != "pipe" {
+		t.Errorf("expected input type 'pipe', got %s", item.InputType)
+	}
+
+	if item.State != StateSealed {
+		t.Errorf("expected sealed state, got %s", item.State)
+	}
+
+	// Payload must not contain plaintext
+	payload, err := os.ReadFile(filepath.Join(tmpHome, ".local", "share", "seal", id, "payload.bin"))
+	if err == nil && bytes.Contains(payload, []byte("first drop")) {
+		t.Error("payload should not contain plaintext")
+	}
+
+	// A second writer produces a second, distinct item
+	writeToFIFO(t, fifoPath, []byte("second drop"))
+	id2, err := sealPipeWrite(fifoPath, unlockTime, authority, 0)
+	if err != nil {
+		t.Fatalf("second sealPipeWrite failed: %v", err)
+	}
+
+	if id2 == id {
+		t.Error("each write should produce a new item")
+	}
+}
+
+func TestSealPipeWrite_EmptyWrite(t *testing.T) {
+	_, cleanup := testutil.SetupTestEnv(t)
+	defer cleanup()
+
+	fifoPath := makeTestFIFO(t)
+
+	writeToFIFO(t, fifoPath, nil)
+	_, err := sealPipeWrite(fifoPath, time.Now().UTC().Add(time.Hour), &timeauth.FakeAuthority{}, 0)
+	if err != errEmptyWrite {
+		t.Errorf("expected errEmptyWrite, got: %v", err)
+	}
+}
+
+func TestSealPipeWrite_RejectsAccidentalWrites(t *testing.T) {
+	_, cleanup := testutil.SetupTestEnv(t)
+	defer cleanup()
+
+	fifoPath := makeTestFIFO(t)
+	unlockTime := time.Now().UTC().Add(time.Hour)
+
+	for _, tc := range []struct {
+		data    string
+		minSize int
+		wantErr string
+	}{
+		{" \n\t\n", 0, "only whitespace"},
+		{"short", 16, "below the configured minimum of 16"},
+	} {
+		writeToFIFO(t, fifoPath, []byte(tc.data))
+		_, err := sealPipeWrite(fifoPath, unlockTime, &timeauth.FakeAuthority{DefaultRound: 1000}, tc.minSize)
+		var rejected *rejectedWriteError
+		if !errors.As(err, &rejected) || !strings.Contains(err.Error(), tc.wantErr) {
+			t.Errorf("%q: expected a rejected write containing %q, got %v", tc.data, tc.wantErr, err)
+		}
+	}
+
+	if items, _ := ListSealedItems(ListOptions{}); len(items) != 0 {
+		t.Errorf("rejected writes must not be sealed, got %d items", len(items))
+	}
+}
+
+func TestSealPipeWrite_UnlockTimePassed(t *testing.T) {
+	_, cleanup := testutil.SetupTestEnv(t)
+	defer cleanup()
+
+	fifoPath := makeTestFIFO(t)
+
+	writeToFIFO(t, fifoPath, []byte("late drop"))
+	_, err := sealPipeWrite(fifoPath, time.Now().UTC().Add(-time.Second), &timeauth.FakeAuthority{}, 0)
+	if err == nil || !strings.Contains(err.Error(), "unlock time must be in the future") {
+		t.Errorf("expected unlock time error, got: %v", err)
+	}
+}
+
+func TestServePipe_RejectsRegularFile(t *testing.T) {
+	regular := filepath.Join(t.TempDir(), "regular")
+	if err := os.WriteFile(regular, []byte("x"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	err := ServePipe(PipeRequest{
+		FIFOPath:   regular,
+		UnlockTime: time.Now().UTC().Add(time.Hour).Format(time.RFC3339),
+	}, func(string) {}, func(string) {})
+
+	if err == nil || !strings.Contains(err.Error(), "not a named pipe") {
+		t.Errorf("expected not a named pipe error, got: %v", err)
+	}
+}
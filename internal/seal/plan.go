@@ -0,0 +1,59 @@
+package seal
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Planned change actions.
+const (
+	ChangeShred  = "shred"  // a file of the store is shredded and removed
+	ChangeImport = "import" // a file is stored as a new item
+	ChangeSkip   = "skip"   // a file is left out, see Detail
+)
+
+// Change is one thing a command that changes the store does, or would do.
+type Change struct {
+	Action string `json:"action"` // one of the Change constants
+	Path   string `json:"path"`
+	ItemID string `json:"item_id,omitempty"`
+	Detail string `json:"detail,omitempty"` // e.g. why a file is skipped
+}
+
+// Plan lists the changes of one command. With DryRun, none of them was made.
+type Plan struct {
+	DryRun  bool     `json:"dry_run"`
+	Changes []Change `json:"changes"`
+}
+
+// FormatPlan formats a plan as one line per change, e.g. "would shred: <path>".
+func FormatPlan(plan Plan) string {
+	var result string
+	for _, change := range plan.Changes {
+		action := change.Action
+		if plan.DryRun {
+			action = "would " + action
+		}
+		result += fmt.Sprintf("%s: %s", action, change.Path)
+		if change.ItemID != "" {
+			result += " -> " + change.ItemID
+		}
+		if change.Detail != "" {
+			result += " (" + change.Detail + ")"
+		}
+		result += "\n"
+	}
+	return result
+}
+
+// FormatPlanJSON formats a plan as indented JSON.
+func FormatPlanJSON(plan Plan) (string, error) {
+	if plan.Changes == nil {
+		plan.Changes = []Change{}
+	}
+	data, err := json.MarshalIndent(plan, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("cannot marshal plan: %w", err)
+	}
+	return string(data) + "\n", nil
+}
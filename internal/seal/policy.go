@@ -0,0 +1,148 @@
+package seal
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+// policyFileName is the optional lock-time policy file in the base directory.
+const policyFileName = "policy.json"
+
+// policyEnvVar overrides the policy file location, e.g. for an admin-managed path.
+const policyEnvVar = "SEAL_POLICY"
+
+// Policy restricts what may be locked. It is evaluated before any item is created.
+// Unknown fields are rejected, so a rule Seal does not understand is never silently ignored.
+type Policy struct {
+	MaxHorizon         string   `json:"max_horizon,omitempty"`         // longest allowed lock, e.g. 365d or 720h
+	ForbiddenPaths     []string `json:"forbidden_paths,omitempty"`     // filepath.Match patterns for input files and their parent directories
+	ShredExtensions    []string `json:"shred_extensions,omitempty"`    // input file extensions that must be locked with --shred, e.g. .pem
+	AllowedAuthorities []string `json:"allowed_authorities,omitempty"` // time authorities items may use, e.g. drand
+}
+
+// PolicyRequest describes a lock to be checked against a policy.
+type PolicyRequest struct {
+	UnlockTime time.Time
+	InputPath  string // empty for stdin
+	Shred      bool
+	Authority  string
+}
+
+// LoadPolicy loads the lock-time policy from $SEAL_POLICY or policy.json in the base directory.
+// Returns nil if no policy is configured.
+func LoadPolicy() (*Policy, error) {
+	path := os.Getenv(policyEnvVar)
+	if path == "" {
+		baseDir, err := GetSealBaseDir()
+		if err != nil {
+			return nil, err
+		}
+		path = filepath.Join(baseDir, policyFileName)
+	}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) && os.Getenv(policyEnvVar) == "" {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read policy: %w", err)
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+
+	var policy Policy
+	if err := decoder.Decode(&policy); err != nil {
+		return nil, fmt.Errorf("failed to parse policy %s: %w", path, err)
+	}
+
+	if policy.MaxHorizon != "" {
+		if _, err := ParseRetention(policy.MaxHorizon); err != nil {
+			return nil, fmt.Errorf("invalid policy max_horizon: %w", err)
+		}
+	}
+	for _, pattern := range policy.ForbiddenPaths {
+		if _, err := filepath.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid policy forbidden_paths pattern %q: %w", pattern, err)
+		}
+	}
+
+	return &policy, nil
+}
+
+// Check reports the first rule the request violates. A nil policy allows everything.
+func (p *Policy) Check(req PolicyRequest, now time.Time) error {
+	if p == nil {
+		return nil
+	}
+
+	if p.MaxHorizon != "" {
+		horizon, _ := ParseRetention(p.MaxHorizon)
+		if req.UnlockTime.Sub(now) > horizon {
+			return fmt.Errorf("policy violation: unlock time is beyond the maximum horizon of %s", p.MaxHorizon)
+		}
+	}
+
+	if len(p.AllowedAuthorities) > 0 && !slices.Contains(p.AllowedAuthorities, req.Authority) {
+		return fmt.Errorf("policy violation: time authority %q is not allowed", req.Authority)
+	}
+
+	if req.InputPath == "" {
+		return nil
+	}
+
+	abs, err := filepath.Abs(req.InputPath)
+	if err != nil {
+		return fmt.Errorf("cannot resolve input path: %w", err)
+	}
+
+	// A symlink must not lead around the rules, so they apply to the path as given
+	// and to the path it resolves to
+	paths := []string{abs}
+	if resolved := resolveSymlinks(abs); resolved != abs {
+		paths = append(paths, resolved)
+	}
+
+	for _, path := range paths {
+		for dir := path; ; dir = filepath.Dir(dir) {
+			for _, pattern := range p.ForbiddenPaths {
+				if matched, _ := filepath.Match(pattern, dir); matched {
+					return fmt.Errorf("policy violation: input path %s is forbidden (%s)", path, pattern)
+				}
+			}
+			if filepath.Dir(dir) == dir {
+				break
+			}
+		}
+	}
+
+	for _, path := range paths {
+		ext := strings.ToLower(filepath.Ext(path))
+		if !req.Shred && ext != "" && slices.ContainsFunc(p.ShredExtensions, func(e string) bool { return strings.EqualFold(e, ext) }) {
+			return fmt.Errorf("policy violation: %s files must be locked with --shred", ext)
+		}
+	}
+
+	return nil
+}
+
+// resolveSymlinks resolves every symlink in an absolute path. Components that do
+// not exist are kept as they are, under their resolved parent.
+func resolveSymlinks(abs string) string {
+	rest := ""
+	for dir := abs; ; dir = filepath.Dir(dir) {
+		if resolved, err := filepath.EvalSymlinks(dir); err == nil {
+			return filepath.Join(resolved, rest)
+		}
+		if filepath.Dir(dir) == dir {
+			return abs
+		}
+		rest = filepath.Join(filepath.Base(dir), rest)
+	}
+}
@@ -0,0 +1,127 @@
+package seal
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"seal/internal/testutil"
+)
+
+func TestPolicyCheck_Rules(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	policy := &Policy{
+		MaxHorizon:         "30d",
+		ForbiddenPaths:     []string{"/home/*/.ssh"},
+		ShredExtensions:    []string{".pem"},
+		AllowedAuthorities: []string{"drand"},
+	}
+
+	tests := []struct {
+		name    string
+		req     PolicyRequest
+		wantErr string
+	}{
+		{"allowed", PolicyRequest{UnlockTime: now.Add(24 * time.Hour), InputPath: "/tmp/notes.txt", Authority: "drand"}, ""},
+		{"beyond horizon", PolicyRequest{UnlockTime: now.Add(31 * 24 * time.Hour), Authority: "drand"}, "maximum horizon"},
+		{"authority", PolicyRequest{UnlockTime: now.Add(time.Hour), Authority: "placeholder"}, "not allowed"},
+		{"forbidden directory", PolicyRequest{UnlockTime: now.Add(time.Hour), InputPath: "/home/alice/.ssh/id_ed25519", Authority: "drand"}, "forbidden"},
+		{"shred required", PolicyRequest{UnlockTime: now.Add(time.Hour), InputPath: "/tmp/key.PEM", Authority: "drand"}, "--shred"},
+		{"shred given", PolicyRequest{UnlockTime: now.Add(time.Hour), InputPath: "/tmp/key.pem", Shred: true, Authority: "drand"}, ""},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := policy.Check(tc.req, now)
+			if tc.wantErr == "" {
+				if err != nil {
+					t.Errorf("expected no violation, got: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Errorf("expected violation containing %q, got: %v", tc.wantErr, err)
+			}
+		})
+	}
+
+	var none *Policy
+	if err := none.Check(PolicyRequest{UnlockTime: now.Add(100 * 365 * 24 * time.Hour)}, now); err != nil {
+		t.Errorf("nil policy should allow everything, got: %v", err)
+	}
+}
+
+func TestPolicyCheck_FollowsSymlinks(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	dir, err := filepath.EvalSymlinks(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	secret := filepath.Join(dir, "secret")
+	if err := os.Mkdir(secret, 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(secret, "key"), []byte("key"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	// A link to the file, and a link to its directory
+	if err := os.Symlink(filepath.Join(secret, "key"), filepath.Join(dir, "notes.txt")); err != nil {
+		t.Skipf("symlinks not supported: %v", err)
+	}
+	if err := os.Symlink(secret, filepath.Join(dir, "public")); err != nil {
+		t.Fatal(err)
+	}
+
+	policy := &Policy{ForbiddenPaths: []string{secret}}
+	for _, input := range []string{"notes.txt", filepath.Join("public", "key")} {
+		req := PolicyRequest{UnlockTime: now.Add(time.Hour), InputPath: filepath.Join(dir, input), Authority: "drand"}
+		if err := policy.Check(req, now); err == nil || !strings.Contains(err.Error(), "forbidden") {
+			t.Errorf("%s: expected the symlink target to be forbidden, got: %v", input, err)
+		}
+	}
+}
+
+func TestLoadPolicy_RejectsUnknownRules(t *testing.T) {
+	policyPath := filepath.Join(t.TempDir(), "policy.json")
+	if err := os.WriteFile(policyPath, []byte(`{"max_horizon": "30d", "required_labels": ["team"]}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv(policyEnvVar, policyPath)
+
+	if _, err := LoadPolicy(); err == nil || !strings.Contains(err.Error(), "required_labels") {
+		t.Fatalf("expected unknown rule to be rejected, got: %v", err)
+	}
+}
+
+func TestLoadPolicy_ExplicitPathMustExist(t *testing.T) {
+	t.Setenv(policyEnvVar, filepath.Join(t.TempDir(), "missing.json"))
+
+	if _, err := LoadPolicy(); err == nil {
+		t.Fatal("expected missing admin policy to be an error")
+	}
+}
+
+func TestLock_PolicyViolation_CreatesNothing(t *testing.T) {
+	_, cleanup := testutil.SetupTestEnv(t)
+	defer cleanup()
+
+	baseDir, _ := GetSealBaseDir()
+	if err := os.MkdirAll(baseDir, 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(baseDir, policyFileName), []byte(`{"max_horizon": "7d"}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := Lock(LockRequest{UnlockTime: time.Now().UTC().Add(30 * 24 * time.Hour).Format(time.RFC3339)})
+	if err == nil || !strings.Contains(err.Error(), "policy violation") {
+		t.Fatalf("expected policy violation, got: %v", err)
+	}
+
+	items, _ := ListSealedItems(ListOptions{})
+	if len(items) != 0 {
+		t.Errorf("no item should be created, got %d", len(items))
+	}
+}
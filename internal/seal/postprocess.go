@@ -0,0 +1,300 @@
+package seal
+
+import (
+	"archive/tar"
+	"bufio"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"filippo.io/age"
+	"filippo.io/age/armor"
+)
+
+// Post-processing step names. A step is declared as name or name:arg.
+const (
+	PostProcessGunzip     = "gunzip"      // decompress gzip
+	PostProcessUntar      = "untar"       // extract a tar archive into a directory (last step only)
+	PostProcessAgeDecrypt = "age-decrypt" // decrypt with an age identity file: age-decrypt:<path>
+)
+
+// Post-processing outcomes recorded per step.
+const (
+	PostProcessStatusOK     = "ok"
+	PostProcessStatusFailed = "failed"
+)
+
+// maxPostProcessOutput caps the data a single step may produce,
+// guarding against decompression bombs.
+const maxPostProcessOutput = 1 << 30 // 1 GiB
+
+// PostProcessResult records the outcome of one post-processing step.
+type PostProcessResult struct {
+	Step   string `json:"step"`
+	Status string `json:"status"`
+	Output string `json:"output,omitempty"` // file or directory written by the step
+	Error  string `json:"error,omitempty"`
+}
+
+// postProcessor transforms the input file into output. arg is the text after the step name.
+type postProcessor struct {
+	run      func(input, output, arg string) error
+	needsArg bool
+	terminal bool // produces a directory, so no step may follow
+}
+
+var postProcessors = map[string]postProcessor{
+	PostProcessGunzip:     {run: gunzipStep},
+	PostProcessUntar:      {run: untarStep, terminal: true},
+	PostProcessAgeDecrypt: {run: ageDecryptStep, needsArg: true},
+}
+
+// ValidatePostProcessSteps checks that every step is known, has an argument
+// if it needs one, and that directory-producing steps come last.
+func ValidatePostProcessSteps(steps []string) error {
+	for i, step := range steps {
+		name, arg, _ := strings.Cut(step, ":")
+
+		processor, ok := postProcessors[name]
+		if !ok {
+			return fmt.Errorf("unknown post-processing step: %s", name)
+		}
+		if processor.needsArg && arg == "" {
+			return fmt.Errorf("post-processing step %s requires an argument (%s:<value>)", name, name)
+		}
+		if processor.terminal && i != len(steps)-1 {
+			return fmt.Errorf("post-processing step %s must be the last step", name)
+		}
+	}
+	return nil
+}
+
+// ResolvePostProcessSteps validates steps and returns them with the identity
+// file of age-decrypt as an absolute path. Steps run from status or the daemon,
+// whose working directory is not the one the item was locked in.
+func ResolvePostProcessSteps(steps []string) ([]string, error) {
+	if err := ValidatePostProcessSteps(steps); err != nil {
+		return nil, err
+	}
+
+	resolved := slices.Clone(steps)
+	for i, step := range resolved {
+		name, arg, _ := strings.Cut(step, ":")
+		if name != PostProcessAgeDecrypt {
+			continue
+		}
+
+		abs, err := filepath.Abs(arg)
+		if err != nil {
+			return nil, fmt.Errorf("cannot resolve age identity file: %w", err)
+		}
+		info, err := os.Stat(abs)
+		if err != nil {
+			return nil, fmt.Errorf("cannot use age identity file: %w", err)
+		}
+		if !info.Mode().IsRegular() {
+			return nil, fmt.Errorf("age identity file is not a regular file: %s", abs)
+		}
+		resolved[i] = name + ":" + abs
+	}
+	return resolved, nil
+}
+
+// postProcessDir returns the directory holding post-processing outputs for an item.
+func postProcessDir(item SealedItem, itemDir string) string {
+	return UnsealedPath(item, itemDir) + ".processed"
+}
+
+// runPendingPostProcess runs an unlocked item's declared post-processing steps in order.
+// Steps run once: results, including the first failure, are recorded in metadata.
+// Returns the updated item and the failure, if any.
+func runPendingPostProcess(item SealedItem, itemDir string) (SealedItem, error) {
+	if len(item.PostProcess) == 0 || item.State != StateUnlocked || len(item.PostProcessResults) > 0 {
+		return item, nil
+	}
+
+	results, stepErr := runPostProcessSteps(item.PostProcess, UnsealedPath(item, itemDir), postProcessDir(item, itemDir))
+
+	// Steps can take a while, so the results are recorded on the current metadata
+	updated, err := updateMetadata(itemDir, func(item *SealedItem) { item.PostProcessResults = results })
+	if err != nil {
+		item.PostProcessResults = results
+		return item, fmt.Errorf("failed to record post-processing results: %w", err)
+	}
+
+	return updated, stepErr
+}
+
+// runPostProcessSteps runs steps in order, writing outputs into outDir.
+// Each step reads the previous step's output, starting from the unsealed
+// content, which is never modified. The first failure stops the pipeline.
+func runPostProcessSteps(steps []string, unsealedPath, outDir string) ([]PostProcessResult, error) {
+	fail := func(step string, err error) ([]PostProcessResult, error) {
+		return []PostProcessResult{{Step: step, Status: PostProcessStatusFailed, Error: err.Error()}}, err
+	}
+
+	if _, err := os.Stat(unsealedPath); err != nil {
+		return fail(steps[0], fmt.Errorf("unsealed content not available: %w", err))
+	}
+	if err := os.MkdirAll(outDir, 0700); err != nil {
+		return fail(steps[0], fmt.Errorf("cannot create post-processing directory: %w", err))
+	}
+	if err := checkItemDir(outDir); err != nil {
+		return fail(steps[0], err)
+	}
+
+	var results []PostProcessResult
+	input := unsealedPath
+	for i, step := range steps {
+		name, arg, _ := strings.Cut(step, ":")
+		output := filepath.Join(outDir, fmt.Sprintf("%d-%s", i+1, name))
+
+		var err error
+		if processor, ok := postProcessors[name]; ok {
+			err = processor.run(input, output, arg)
+		} else {
+			err = fmt.Errorf("unknown post-processing step: %s", name)
+		}
+
+		if err != nil {
+			results = append(results, PostProcessResult{Step: step, Status: PostProcessStatusFailed, Error: err.Error()})
+			return results, fmt.Errorf("step %s: %w", name, err)
+		}
+
+		results = append(results, PostProcessResult{Step: step, Status: PostProcessStatusOK, Output: output})
+		input = output
+	}
+
+	return results, nil
+}
+
+// writeLimited copies r into a new file at path, failing if it exceeds maxPostProcessOutput.
+func writeLimited(path string, r io.Reader) error {
+	out, err := createFile(path, 0600)
+	if err != nil {
+		return err
+	}
+
+	n, err := io.Copy(out, io.LimitReader(r, maxPostProcessOutput+1))
+	if closeErr := out.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil && n > maxPostProcessOutput {
+		err = fmt.Errorf("output exceeds %d bytes", maxPostProcessOutput)
+	}
+	if err != nil {
+		os.Remove(path)
+		return err
+	}
+
+	return nil
+}
+
+func gunzipStep(input, output, _ string) error {
+	in, err := os.Open(input)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	zr, err := gzip.NewReader(in)
+	if err != nil {
+		return fmt.Errorf("not gzip data: %w", err)
+	}
+	defer zr.Close()
+
+	return writeLimited(output, zr)
+}
+
+func untarStep(input, output, _ string) error {
+	in, err := os.Open(input)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	if err := os.MkdirAll(output, 0700); err != nil {
+		return err
+	}
+
+	var total int64
+	tr := tar.NewReader(in)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("invalid tar archive: %w", err)
+		}
+
+		// Entries must stay inside the output directory
+		name := filepath.FromSlash(hdr.Name)
+		if !filepath.IsLocal(name) {
+			return fmt.Errorf("unsafe path in archive: %s", hdr.Name)
+		}
+		target := filepath.Join(output, name)
+
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, 0700); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			total += hdr.Size
+			if total > maxPostProcessOutput {
+				return fmt.Errorf("archive exceeds %d bytes", maxPostProcessOutput)
+			}
+			if err := os.MkdirAll(filepath.Dir(target), 0700); err != nil {
+				return err
+			}
+			if err := writeLimited(target, tr); err != nil {
+				return err
+			}
+		default:
+			// Links and special files could point outside the output directory
+			return fmt.Errorf("unsupported entry in archive: %s", hdr.Name)
+		}
+	}
+}
+
+func ageDecryptStep(input, output, identityPath string) error {
+	identityFile, err := os.Open(identityPath)
+	if err != nil {
+		return fmt.Errorf("cannot open age identity: %w", err)
+	}
+	identities, err := age.ParseIdentities(identityFile)
+	identityFile.Close()
+	if err != nil {
+		return fmt.Errorf("cannot parse age identity: %w", err)
+	}
+
+	in, err := os.Open(input)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	// Accept both binary and ASCII-armored age files
+	br := bufio.NewReader(in)
+	var src io.Reader = br
+	if header, _ := br.Peek(len(armor.Header)); string(header) == armor.Header {
+		src = armor.NewReader(br)
+	}
+
+	dr, err := age.Decrypt(src, identities...)
+	if err != nil {
+		var noMatch *age.NoIdentityMatchError
+		if errors.As(err, &noMatch) {
+			return errors.New("no age identity matches this content")
+		}
+		return fmt.Errorf("age decryption failed: %w", err)
+	}
+
+	return writeLimited(output, dr)
+}
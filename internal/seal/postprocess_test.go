@@ -0,0 +1,209 @@
+package seal
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"filippo.io/age"
+
+	"seal/internal/testutil"
+	"seal/internal/timeauth"
+)
+
+// unlockWithPostProcess seals plaintext with the given steps and materializes it.
+func unlockWithPostProcess(t *testing.T, plaintext []byte, steps []string) (SealedItem, string) {
+	t.Helper()
+
+	authority := &timeauth.FakeAuthority{DefaultRound: 100, CurrentRound: 200}
+	id, err := CreateSealedItemWithOptions(time.Now().UTC().Add(time.Hour), InputSourceStdin, "", plaintext, authority, ItemOptions{
+		PostProcess: steps,
+	})
+	if err != nil {
+		t.Fatalf("CreateSealedItemWithOptions failed: %v", err)
+	}
+
+	item, itemDir, _ := LoadItem(id)
+	item, err = TryMaterialize(item, itemDir, authority)
+	if err != nil {
+		t.Fatalf("TryMaterialize failed: %v", err)
+	}
+	return item, itemDir
+}
+
+func TestPostProcess_GunzipThenUntar(t *testing.T) {
+	_, cleanup := testutil.SetupTestEnv(t)
+	defer cleanup()
+
+	var archive bytes.Buffer
+	zw := gzip.NewWriter(&archive)
+	tw := tar.NewWriter(zw)
+	content := []byte("launch plan")
+	tw.WriteHeader(&tar.Header{Name: "docs/plan.txt", Mode: 0600, Size: int64(len(content)), Typeflag: tar.TypeReg})
+	tw.Write(content)
+	tw.Close()
+	zw.Close()
+
+	item, itemDir := unlockWithPostProcess(t, archive.Bytes(), []string{PostProcessGunzip, PostProcessUntar})
+
+	item, err := runPendingPostProcess(item, itemDir)
+	if err != nil {
+		t.Fatalf("runPendingPostProcess failed: %v", err)
+	}
+
+	if len(item.PostProcessResults) != 2 {
+		t.Fatalf("expected 2 step results, got %+v", item.PostProcessResults)
+	}
+	for _, result := range item.PostProcessResults {
+		if result.Status != PostProcessStatusOK {
+			t.Errorf("step %s: expected ok, got %+v", result.Step, result)
+		}
+	}
+
+	extracted, err := os.ReadFile(filepath.Join(item.PostProcessResults[1].Output, "docs", "plan.txt"))
+	if err != nil || !bytes.Equal(extracted, content) {
+		t.Errorf("extracted content mismatch: %q, %v", extracted, err)
+	}
+
+	// The unsealed content itself is left untouched
+	unsealed, _ := os.ReadFile(UnsealedPath(item, itemDir))
+	if !bytes.Equal(unsealed, archive.Bytes()) {
+		t.Error("unsealed content should not be modified by post-processing")
+	}
+
+	// Results are persisted and steps do not run again
+	persisted, _, _ := LoadItem(item.ID)
+	if len(persisted.PostProcessResults) != 2 {
+		t.Errorf("results not persisted: %+v", persisted.PostProcessResults)
+	}
+}
+
+func TestPostProcess_AgeDecrypt(t *testing.T) {
+	_, cleanup := testutil.SetupTestEnv(t)
+	defer cleanup()
+
+	identity, err := age.GenerateX25519Identity()
+	if err != nil {
+		t.Fatal(err)
+	}
+	identityPath := filepath.Join(t.TempDir(), "key.txt")
+	os.WriteFile(identityPath, []byte(identity.String()+"\n"), 0600)
+
+	var encrypted bytes.Buffer
+	w, err := age.Encrypt(&encrypted, identity.Recipient())
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.Write([]byte("for alice"))
+	w.Close()
+
+	item, itemDir := unlockWithPostProcess(t, encrypted.Bytes(), []string{PostProcessAgeDecrypt + ":" + identityPath})
+
+	item, err = runPendingPostProcess(item, itemDir)
+	if err != nil {
+		t.Fatalf("runPendingPostProcess failed: %v", err)
+	}
+
+	decrypted, _ := os.ReadFile(item.PostProcessResults[0].Output)
+	if string(decrypted) != "for alice" {
+		t.Errorf("unexpected decrypted content: %q", decrypted)
+	}
+}
+
+func TestPostProcess_FailureRecordedAndStops(t *testing.T) {
+	_, cleanup := testutil.SetupTestEnv(t)
+	defer cleanup()
+
+	item, itemDir := unlockWithPostProcess(t, []byte("not compressed"), []string{PostProcessGunzip, PostProcessUntar})
+
+	item, err := runPendingPostProcess(item, itemDir)
+	if err == nil {
+		t.Fatal("expected gunzip failure")
+	}
+
+	if len(item.PostProcessResults) != 1 {
+		t.Fatalf("pipeline should stop at the failing step, got %+v", item.PostProcessResults)
+	}
+	result := item.PostProcessResults[0]
+	if result.Status != PostProcessStatusFailed || !strings.Contains(result.Error, "not gzip data") {
+		t.Errorf("unexpected failure record: %+v", result)
+	}
+
+	// A recorded failure is not retried
+	if _, err := runPendingPostProcess(item, itemDir); err != nil {
+		t.Errorf("post-processing should not run again: %v", err)
+	}
+}
+
+func TestUntarStep_RejectsEscapingPaths(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	var archive bytes.Buffer
+	tw := tar.NewWriter(&archive)
+	tw.WriteHeader(&tar.Header{Name: "../escape.txt", Mode: 0600, Size: 1, Typeflag: tar.TypeReg})
+	tw.Write([]byte("x"))
+	tw.Close()
+
+	input := filepath.Join(tmpDir, "archive.tar")
+	os.WriteFile(input, archive.Bytes(), 0600)
+
+	err := untarStep(input, filepath.Join(tmpDir, "out"), "")
+	if err == nil || !strings.Contains(err.Error(), "unsafe path") {
+		t.Fatalf("expected unsafe path error, got: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(tmpDir, "escape.txt")); !os.IsNotExist(err) {
+		t.Error("archive entry escaped the output directory")
+	}
+}
+
+func TestValidatePostProcessSteps(t *testing.T) {
+	valid := [][]string{
+		nil,
+		{PostProcessGunzip},
+		{PostProcessAgeDecrypt + ":/keys/id.txt", PostProcessGunzip, PostProcessUntar},
+	}
+	for _, steps := range valid {
+		if err := ValidatePostProcessSteps(steps); err != nil {
+			t.Errorf("ValidatePostProcessSteps(%v) failed: %v", steps, err)
+		}
+	}
+
+	invalid := [][]string{
+		{"unzip"},
+		{PostProcessAgeDecrypt},
+		{PostProcessUntar, PostProcessGunzip},
+	}
+	for _, steps := range invalid {
+		if err := ValidatePostProcessSteps(steps); err == nil {
+			t.Errorf("ValidatePostProcessSteps(%v) should fail", steps)
+		}
+	}
+}
+
+func TestResolvePostProcessSteps_AbsoluteIdentityFile(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "id.txt"), []byte("identity"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	t.Chdir(dir)
+
+	steps, err := ResolvePostProcessSteps([]string{PostProcessAgeDecrypt + ":id.txt", PostProcessGunzip})
+	if err != nil {
+		t.Fatalf("ResolvePostProcessSteps failed: %v", err)
+	}
+	want := PostProcessAgeDecrypt + ":" + filepath.Join(dir, "id.txt")
+	if len(steps) != 2 || steps[0] != want || steps[1] != PostProcessGunzip {
+		t.Errorf("expected %s then gunzip, got %v", want, steps)
+	}
+
+	for _, arg := range []string{"missing.txt", "."} {
+		if _, err := ResolvePostProcessSteps([]string{PostProcessAgeDecrypt + ":" + arg}); err == nil {
+			t.Errorf("expected identity file %q to be refused", arg)
+		}
+	}
+}
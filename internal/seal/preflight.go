@@ -0,0 +1,37 @@
+package seal
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"syscall"
+)
+
+// materializeHeadroom is extra free space required beyond the plaintext,
+// covering the metadata rewrite that commits materialization.
+const materializeHeadroom = 64 << 10
+
+// freeSpace reports available bytes for a directory. Replaced in tests.
+var freeSpace = availableBytes
+
+// checkMaterializeTarget verifies that dir is writable and has room for size
+// bytes of unsealed data before anything is written.
+// Errors are recoverable: the item stays sealed and the next run retries.
+func checkMaterializeTarget(dir string, size int64) error {
+	probe, err := os.CreateTemp(dir, ".seal-write-check-*")
+	if err != nil {
+		if errors.Is(err, syscall.EROFS) {
+			return fmt.Errorf("cannot materialize into %s: read-only filesystem", dir)
+		}
+		return fmt.Errorf("cannot materialize into %s: directory is not writable: %w", dir, err)
+	}
+	probe.Close()
+	os.Remove(probe.Name())
+
+	need := uint64(size) + materializeHeadroom
+	if available, ok := freeSpace(dir); ok && available < need {
+		return fmt.Errorf("cannot materialize into %s: insufficient disk space (need %d bytes, %d available)", dir, need, available)
+	}
+
+	return nil
+}
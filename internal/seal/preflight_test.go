@@ -0,0 +1,89 @@
+package seal
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"seal/internal/testutil"
+	"seal/internal/timeauth"
+)
+
+func TestMaterialize_InsufficientSpace_StaysSealed(t *testing.T) {
+	_, cleanup := testutil.SetupTestEnv(t)
+	defer cleanup()
+
+	original := freeSpace
+	freeSpace = func(dir string) (uint64, bool) { return 1024, true }
+	defer func() { freeSpace = original }()
+
+	authority := &timeauth.FakeAuthority{DefaultRound: 100, CurrentRound: 200}
+	id, err := CreateSealedItem(time.Now().UTC().Add(time.Hour), InputSourceStdin, "", []byte("data"), authority)
+	if err != nil {
+		t.Fatalf("CreateSealedItem failed: %v", err)
+	}
+
+	item, itemDir, _ := LoadItem(id)
+	result, err := TryMaterialize(item, itemDir, authority)
+	if err == nil || !strings.Contains(err.Error(), "insufficient disk space") {
+		t.Fatalf("expected insufficient disk space error, got: %v", err)
+	}
+	if result.State != StateSealed {
+		t.Errorf("item should stay sealed, got %s", result.State)
+	}
+
+	assertNoUnsealedLeftovers(t, itemDir)
+
+	// Recoverable: succeeds once space is available
+	freeSpace = original
+	result, err = TryMaterialize(result, itemDir, authority)
+	if err != nil || result.State != StateUnlocked {
+		t.Fatalf("expected materialization to succeed on retry, got %s: %v", result.State, err)
+	}
+}
+
+func TestMaterialize_ReadOnlyDestination_StaysSealed(t *testing.T) {
+	if os.Geteuid() == 0 {
+		t.Skip("permission checks do not apply to root")
+	}
+
+	_, cleanup := testutil.SetupTestEnv(t)
+	defer cleanup()
+
+	destDir := t.TempDir()
+	authority := &timeauth.FakeAuthority{DefaultRound: 100, CurrentRound: 200}
+	id, err := CreateSealedItemWithOptions(time.Now().UTC().Add(time.Hour), InputSourceStdin, "", []byte("data"), authority, ItemOptions{
+		UnsealTo: destDir,
+	})
+	if err != nil {
+		t.Fatalf("CreateSealedItemWithOptions failed: %v", err)
+	}
+
+	os.Chmod(destDir, 0500)
+	defer os.Chmod(destDir, 0700)
+
+	item, itemDir, _ := LoadItem(id)
+	result, err := TryMaterialize(item, itemDir, authority)
+	if err == nil || !strings.Contains(err.Error(), "not writable") {
+		t.Fatalf("expected not writable error, got: %v", err)
+	}
+	if result.State != StateSealed {
+		t.Errorf("item should stay sealed, got %s", result.State)
+	}
+
+	entries, _ := os.ReadDir(destDir)
+	if len(entries) != 0 {
+		t.Errorf("destination should be untouched, found %d entries", len(entries))
+	}
+}
+
+func assertNoUnsealedLeftovers(t *testing.T, itemDir string) {
+	t.Helper()
+	for _, name := range []string{"unsealed", "unsealed.pending"} {
+		if _, err := os.Stat(filepath.Join(itemDir, name)); !os.IsNotExist(err) {
+			t.Errorf("%s should not exist", name)
+		}
+	}
+}
@@ -0,0 +1,204 @@
+package seal
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"seal/internal/clock"
+)
+
+// ReceiptVersion is the layout of receipts written by this build.
+const ReceiptVersion = 1
+
+// receiptKeyFile is the Ed25519 signing key for receipts, in the identities directory.
+// It is created on first use and never overwritten.
+const receiptKeyFile = "receipt.ed25519"
+
+// receiptDomain separates receipt signatures from anything else signed with the key.
+const receiptDomain = "seal-receipt-v1\n"
+
+// Receipt is a public commitment signed with the store's receipt key.
+// The signature proves who issued it and that the commitment is unchanged.
+// IssuedAt is the issuer's own claim; when the receipt was issued is established
+// by when a third party received it, e.g. before the unlock round was published.
+type Receipt struct {
+	Version    int              `json:"version"`
+	Commitment PublicCommitment `json:"commitment"`
+	IssuedAt   time.Time        `json:"issued_at"`
+	PublicKey  string           `json:"public_key"` // base64 Ed25519 public key of the issuer
+	Signature  string           `json:"signature"`  // base64 Ed25519 signature, see receiptMessage
+}
+
+// IssueReceipt signs the public commitment of an item with the receipt key,
+// creating the key on first use. Read-only for the item, like ExportPublic.
+func IssueReceipt(id string) (Receipt, error) {
+	commitment, err := ExportPublic(id)
+	if err != nil {
+		return Receipt{}, err
+	}
+
+	key, err := loadReceiptKey()
+	if err != nil {
+		return Receipt{}, err
+	}
+
+	receipt := Receipt{
+		Version:    ReceiptVersion,
+		Commitment: commitment,
+		IssuedAt:   clock.UTC().Truncate(time.Second),
+		PublicKey:  base64.StdEncoding.EncodeToString(key.Public().(ed25519.PublicKey)),
+	}
+	message, err := receiptMessage(receipt)
+	if err != nil {
+		return Receipt{}, err
+	}
+	receipt.Signature = base64.StdEncoding.EncodeToString(ed25519.Sign(key, message))
+
+	return receipt, nil
+}
+
+// VerifyReceipt parses a receipt and checks its signature.
+// If publicKey is set, the receipt must also be signed by that key; without it
+// the signature only shows the receipt is intact, since anyone can sign with a key of their own.
+func VerifyReceipt(data []byte, publicKey string) (Receipt, error) {
+	var receipt Receipt
+	if err := json.Unmarshal(data, &receipt); err != nil {
+		return Receipt{}, fmt.Errorf("invalid receipt: %w", err)
+	}
+	if receipt.Version != ReceiptVersion {
+		return Receipt{}, fmt.Errorf("unsupported receipt version %d (this build reads version %d)", receipt.Version, ReceiptVersion)
+	}
+
+	key, err := base64.StdEncoding.DecodeString(receipt.PublicKey)
+	if err != nil || len(key) != ed25519.PublicKeySize {
+		return Receipt{}, errors.New("invalid receipt: malformed public key")
+	}
+	signature, err := base64.StdEncoding.DecodeString(receipt.Signature)
+	if err != nil || len(signature) != ed25519.SignatureSize {
+		return Receipt{}, errors.New("invalid receipt: malformed signature")
+	}
+
+	if publicKey != "" && publicKey != receipt.PublicKey {
+		return Receipt{}, fmt.Errorf("receipt is signed by %s, not by the expected key", receipt.PublicKey)
+	}
+
+	message, err := receiptMessage(receipt)
+	if err != nil {
+		return Receipt{}, err
+	}
+	if !ed25519.Verify(key, message, signature) {
+		return Receipt{}, errors.New("receipt signature is invalid (modified or forged)")
+	}
+
+	return receipt, nil
+}
+
+// ReceiptPublicKey returns the public key receipts from this store are signed with,
+// creating the key on first use, so it can be published before any receipt.
+func ReceiptPublicKey() (string, error) {
+	key, err := loadReceiptKey()
+	if err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(key.Public().(ed25519.PublicKey)), nil
+}
+
+// receiptMessage returns the bytes a receipt's signature covers: the domain
+// followed by the JSON of every field but the signature.
+func receiptMessage(receipt Receipt) ([]byte, error) {
+	receipt.Signature = ""
+	data, err := json.Marshal(receipt)
+	if err != nil {
+		return nil, fmt.Errorf("cannot marshal receipt: %w", err)
+	}
+	return append([]byte(receiptDomain), data...), nil
+}
+
+// FormatReceiptJSON formats a receipt as indented JSON.
+func FormatReceiptJSON(receipt Receipt) (string, error) {
+	data, err := json.MarshalIndent(receipt, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("cannot marshal receipt: %w", err)
+	}
+	return string(data) + "\n", nil
+}
+
+// FormatReceiptVerification formats a verified receipt for display.
+func FormatReceiptVerification(receipt Receipt) string {
+	c := receipt.Commitment
+	return fmt.Sprintf("valid: signed by %s\nid: %s\nciphertext_sha256: %s\ntime_authority: %s\nunlock_round: %d\nchain_hash: %s\ncreated_at: %s\nissued_at: %s (issuer's claim)\n",
+		receipt.PublicKey,
+		c.ID,
+		c.CiphertextSHA256,
+		c.TimeAuthority,
+		c.UnlockRound,
+		c.ChainHash,
+		c.CreatedAt.UTC().Format(time.RFC3339),
+		receipt.IssuedAt.UTC().Format(time.RFC3339))
+}
+
+// loadReceiptKey reads the receipt signing key, generating it if there is none.
+func loadReceiptKey() (ed25519.PrivateKey, error) {
+	dir, err := identitiesDir()
+	if err != nil {
+		return nil, err
+	}
+	path := filepath.Join(dir, receiptKeyFile)
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return generateReceiptKey(dir, path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("cannot read receipt key: %w", err)
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		seed, err := base64.StdEncoding.DecodeString(line)
+		if err != nil || len(seed) != ed25519.SeedSize {
+			return nil, fmt.Errorf("invalid receipt key file %s", path)
+		}
+		return ed25519.NewKeyFromSeed(seed), nil
+	}
+	return nil, fmt.Errorf("invalid receipt key file %s: no key found", path)
+}
+
+// generateReceiptKey creates the receipt signing key at path.
+func generateReceiptKey(dir, path string) (ed25519.PrivateKey, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("cannot create identities directory: %w", err)
+	}
+	if err := checkItemDir(dir); err != nil {
+		return nil, err
+	}
+
+	public, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate receipt key: %w", err)
+	}
+
+	content := fmt.Sprintf("# created: %s\n# public key: %s\n%s\n",
+		clock.UTC().Format(time.RFC3339),
+		base64.StdEncoding.EncodeToString(public),
+		base64.StdEncoding.EncodeToString(key.Seed()))
+
+	if err := writeFileNoFollow(path, []byte(content), 0600); err != nil {
+		return nil, fmt.Errorf("cannot write receipt key: %w", err)
+	}
+	return key, nil
+}
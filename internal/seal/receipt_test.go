@@ -0,0 +1,66 @@
+package seal
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"seal/internal/testutil"
+)
+
+func TestReceipt_IssueAndVerify(t *testing.T) {
+	_, cleanup := testutil.SetupTestEnv(t)
+	defer cleanup()
+
+	item, _, chainHash := createTlockItem(t, 12345)
+
+	receipt, err := IssueReceipt(item.ID)
+	if err != nil {
+		t.Fatalf("IssueReceipt failed: %v", err)
+	}
+	if receipt.Commitment.ID != item.ID || receipt.Commitment.UnlockRound != 12345 || receipt.Commitment.ChainHash != chainHash {
+		t.Errorf("unexpected commitment: %+v", receipt.Commitment)
+	}
+
+	publicKey, err := ReceiptPublicKey()
+	if err != nil || publicKey != receipt.PublicKey {
+		t.Fatalf("ReceiptPublicKey = %q, %v; receipt signed by %q", publicKey, err, receipt.PublicKey)
+	}
+
+	data, err := FormatReceiptJSON(receipt)
+	if err != nil {
+		t.Fatalf("FormatReceiptJSON failed: %v", err)
+	}
+	verified, err := VerifyReceipt([]byte(data), publicKey)
+	if err != nil {
+		t.Fatalf("VerifyReceipt failed: %v", err)
+	}
+	if !strings.Contains(FormatReceiptVerification(verified), "unlock_round: 12345") {
+		t.Errorf("unexpected verification output: %s", FormatReceiptVerification(verified))
+	}
+
+	// Any change to the commitment breaks the signature
+	tampered := strings.Replace(data, `"unlock_round": 12345`, `"unlock_round": 12344`, 1)
+	if _, err := VerifyReceipt([]byte(tampered), ""); err == nil || !strings.Contains(err.Error(), "signature is invalid") {
+		t.Errorf("expected a tampered receipt to fail, got %v", err)
+	}
+
+	// A receipt re-signed with another key is valid on its own, but not for the expected key
+	dir, _ := identitiesDir()
+	if err := os.Remove(filepath.Join(dir, receiptKeyFile)); err != nil {
+		t.Fatalf("cannot remove receipt key: %v", err)
+	}
+	forged, err := IssueReceipt(item.ID)
+	if err != nil {
+		t.Fatalf("IssueReceipt failed: %v", err)
+	}
+	forgedJSON, _ := json.Marshal(forged)
+	if _, err := VerifyReceipt(forgedJSON, ""); err != nil {
+		t.Errorf("a receipt from another key should verify without --public-key: %v", err)
+	}
+	if _, err := VerifyReceipt(forgedJSON, publicKey); err == nil || !strings.Contains(err.Error(), "not by the expected key") {
+		t.Errorf("expected a receipt from another key to be refused, got %v", err)
+	}
+}
@@ -0,0 +1,85 @@
+package seal
+
+import (
+	"fmt"
+	"maps"
+	"strings"
+)
+
+// RelabelRequest describes changes to an item's tags and note.
+type RelabelRequest struct {
+	SetTags    []string // key=value tags to add or replace, see ParseTags
+	RemoveTags []string // tag keys to remove
+	Note       *string  // replaces the note if set; an empty note removes it
+}
+
+// Relabel changes an item's tags and note after it was sealed.
+// Only these descriptive fields are written: the payload, the time-locked key and
+// the unlock time are never touched, and neither is the public commitment.
+// The change is recorded in the item's history.
+func Relabel(id string, req RelabelRequest) (SealedItem, error) {
+	_, itemDir, err := LoadItem(id)
+	if err != nil {
+		return SealedItem{}, err
+	}
+	lock, err := lockItem(itemDir)
+	if err != nil {
+		return SealedItem{}, err
+	}
+	defer lock.unlock()
+	item, err := loadMetadata(itemDir)
+	if err != nil {
+		return SealedItem{}, err
+	}
+
+	set, err := ParseTags(req.SetTags)
+	if err != nil {
+		return SealedItem{}, err
+	}
+	for _, key := range req.RemoveTags {
+		if err := validateTagKey(key); err != nil {
+			return SealedItem{}, err
+		}
+		if _, ok := set[key]; ok {
+			return SealedItem{}, fmt.Errorf("tag %s is both set and removed", key)
+		}
+	}
+	if req.Note != nil {
+		if err := ValidateNote(*req.Note); err != nil {
+			return SealedItem{}, err
+		}
+	}
+
+	tags := make(map[string]string, len(item.Tags)+len(set))
+	for key, value := range item.Tags {
+		tags[key] = value
+	}
+	for key, value := range set {
+		tags[key] = value
+	}
+	for _, key := range req.RemoveTags {
+		delete(tags, key)
+	}
+
+	var changed []string
+	if !maps.Equal(tags, item.Tags) {
+		changed = append(changed, "tags")
+		item.Tags = tags
+		if len(tags) == 0 {
+			item.Tags = nil
+		}
+	}
+	if req.Note != nil && *req.Note != item.Note {
+		changed = append(changed, "note")
+		item.Note = *req.Note
+	}
+	if len(changed) == 0 {
+		return item, nil
+	}
+
+	appendHistory(&item, HistoryRelabeled, strings.Join(changed, ","))
+	if err := saveMetadata(itemDir, item); err != nil {
+		return SealedItem{}, err
+	}
+	return item, nil
+}
@@ -0,0 +1,106 @@
+package seal
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"seal/internal/testutil"
+)
+
+func TestRelabel(t *testing.T) {
+	tmpDir, cleanup := testutil.SetupTestEnv(t)
+	defer cleanup()
+	registerFakeAuthority()
+
+	input := filepath.Join(tmpDir, "input.txt")
+	if err := os.WriteFile(input, []byte("prediction"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	result, err := Lock(LockRequest{InputPath: input, For: "1h", Authority: "registered-fake", Tags: []string{"label=draft", "team=ops"}, Note: "first take"})
+	if err != nil {
+		t.Fatalf("Lock failed: %v", err)
+	}
+	before, itemDir, _ := LoadItem(result.ID)
+	if before.Note != "first take" {
+		t.Fatalf("note not stored: %q", before.Note)
+	}
+	if output := FormatStatusOutput([]SealedItem{before}, nil); !strings.Contains(output, "note: first take\n") {
+		t.Errorf("status should show the note, got:\n%s", output)
+	}
+	payload, _ := os.ReadFile(filepath.Join(itemDir, "payload.bin"))
+
+	note := "final answer"
+	item, err := Relabel(result.ID, RelabelRequest{SetTags: []string{"label=final", "ticket=OPS-1"}, RemoveTags: []string{"team"}, Note: &note})
+	if err != nil {
+		t.Fatalf("Relabel failed: %v", err)
+	}
+
+	persisted, _, _ := LoadItem(result.ID)
+	if formatTags(persisted.Tags) != "label=final, ticket=OPS-1" || persisted.Note != "final answer" {
+		t.Errorf("unexpected tags %v and note %q", persisted.Tags, persisted.Note)
+	}
+	if entry, _ := lastHistoryEntry(persisted); entry.Event != HistoryRelabeled || entry.Detail != "tags,note" {
+		t.Errorf("expected a relabeled history entry, got %+v", entry)
+	}
+
+	// Nothing but the descriptive fields changes
+	if item.KeyRef != before.KeyRef || item.DEKTlockB64 != before.DEKTlockB64 || !item.UnlockTime.Equal(before.UnlockTime) || item.PayloadSHA256 != before.PayloadSHA256 {
+		t.Error("relabel must not change crypto material or the unlock time")
+	}
+	if after, _ := os.ReadFile(filepath.Join(itemDir, "payload.bin")); string(after) != string(payload) {
+		t.Error("relabel must not touch the payload")
+	}
+
+	// An unchanged relabel records nothing; an empty note and the last tag removed clear them
+	if _, err := Relabel(result.ID, RelabelRequest{SetTags: []string{"label=final"}}); err != nil {
+		t.Fatalf("Relabel failed: %v", err)
+	}
+	unchanged, _, _ := LoadItem(result.ID)
+	if len(unchanged.History) != len(persisted.History) {
+		t.Error("an unchanged relabel should not be recorded")
+	}
+	empty := ""
+	cleared, err := Relabel(result.ID, RelabelRequest{RemoveTags: []string{"label", "ticket"}, Note: &empty})
+	if err != nil || cleared.Tags != nil || cleared.Note != "" {
+		t.Errorf("expected tags and note to be cleared, got %v, %q, %v", cleared.Tags, cleared.Note, err)
+	}
+}
+
+func TestRelabel_Validation(t *testing.T) {
+	_, cleanup := testutil.SetupTestEnv(t)
+	defer cleanup()
+	registerFakeAuthority()
+
+	result, err := Lock(LockRequest{Data: []byte("prediction"), For: "1h", Authority: "registered-fake"})
+	if err != nil {
+		t.Fatalf("Lock failed: %v", err)
+	}
+
+	multiline := "line one\nline two"
+	long := strings.Repeat("x", maxNoteLength+1)
+	testCases := []struct {
+		name    string
+		req     RelabelRequest
+		wantErr string
+	}{
+		{"bad tag", RelabelRequest{SetTags: []string{"novalue"}}, "expected key=value"},
+		{"bad key", RelabelRequest{RemoveTags: []string{"a b"}}, "may only contain"},
+		{"set and removed", RelabelRequest{SetTags: []string{"a=1"}, RemoveTags: []string{"a"}}, "both set and removed"},
+		{"multiline note", RelabelRequest{Note: &multiline}, "control characters"},
+		{"long note", RelabelRequest{Note: &long}, "longer than"},
+	}
+	for _, tc := range testCases {
+		if _, err := Relabel(result.ID, tc.req); err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+			t.Errorf("%s: expected error containing %q, got %v", tc.name, tc.wantErr, err)
+		}
+	}
+
+	if _, err := Lock(LockRequest{Data: []byte("prediction"), For: "1h", Authority: "registered-fake", Note: multiline}); err == nil {
+		t.Error("expected lock to refuse a multi-line note")
+	}
+	if _, err := Relabel("a1b2c3d4-5e6f-7890-abcd-ef1234567890", RelabelRequest{Note: &multiline}); err == nil {
+		t.Error("expected an error for an unknown item")
+	}
+}
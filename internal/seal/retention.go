@@ -0,0 +1,113 @@
+package seal
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ParseRetention parses a retention period for unsealed content.
+// Accepts a whole number of days (e.g. 7d) or a Go duration (e.g. 36h).
+// The period must be positive.
+func ParseRetention(s string) (time.Duration, error) {
+	var d time.Duration
+
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil {
+			return 0, fmt.Errorf("invalid retention period %q, expected e.g. 7d or 36h", s)
+		}
+		d = time.Duration(n) * 24 * time.Hour
+	} else {
+		parsed, err := time.ParseDuration(s)
+		if err != nil {
+			return 0, fmt.Errorf("invalid retention period %q, expected e.g. 7d or 36h", s)
+		}
+		d = parsed
+	}
+
+	if d <= 0 {
+		return 0, fmt.Errorf("retention period must be positive")
+	}
+
+	return d, nil
+}
+
+// enforceRetention shreds an item's unsealed content once its declared
+// retention period after materialization has elapsed.
+// Shredding is recorded in metadata before the file is removed, so an
+// interrupted run is completed on the next one rather than reported as corruption.
+// Returns the updated item and any best-effort shredding warnings.
+func enforceRetention(item SealedItem, itemDir string, now time.Time) (SealedItem, []string, error) {
+	if item.State != StateUnlocked || item.RetainUnsealed == "" || item.UnlockedAt == nil {
+		return item, nil, nil
+	}
+
+	retain, err := time.ParseDuration(item.RetainUnsealed)
+	if err != nil {
+		return item, nil, fmt.Errorf("invalid retain_unsealed %q: %w", item.RetainUnsealed, err)
+	}
+
+	if now.Before(item.UnlockedAt.Add(retain)) {
+		return item, nil, nil
+	}
+
+	// Reveal delivery and post-processing read the content after the item's lock
+	// is released. It is kept until post-processing has run once, and until the
+	// reveal is delivered: a failed delivery is retried on every run.
+	if item.UnsealedShreddedAt == nil {
+		if item.RevealTo != "" && item.RevealStatus == RevealStatusFailed {
+			return item, []string{fmt.Sprintf("warning: item %s: retention period has passed, but unsealed content is kept until delivery to %s succeeds", item.ID, item.RevealTo)}, nil
+		}
+		if postUnlockPending(item) {
+			return item, nil, nil
+		}
+	}
+
+	if item.UnsealedShreddedAt == nil {
+		shreddedAt := now.UTC()
+		item.UnsealedShreddedAt = &shreddedAt
+		appendHistory(&item, HistoryUnsealedShredded, "retain_unsealed "+item.RetainUnsealed)
+		if err := saveMetadata(itemDir, item); err != nil {
+			return item, nil, fmt.Errorf("failed to record shredding: %w", err)
+		}
+	}
+
+	var warnings []string
+
+	unsealedPath := UnsealedPath(item, itemDir)
+	if _, err := os.Stat(unsealedPath); err == nil {
+		warnings = append(warnings, ShredFile(unsealedPath)...)
+	}
+
+	// Post-processing outputs are derived from the unsealed content
+	warnings = append(warnings, shredTree(postProcessDir(item, itemDir))...)
+
+	return item, warnings, nil
+}
+
+// shredTree shreds every regular file under dir and removes the directory.
+// A missing directory is not an error.
+func shredTree(dir string) []string {
+	if _, err := os.Stat(dir); os.IsNotExist(err) {
+		return nil
+	}
+
+	var warnings []string
+	filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err == nil && entry.Type().IsRegular() {
+			warnings = append(warnings, ShredFile(path)...)
+		}
+		return nil
+	})
+
+	if err := os.RemoveAll(dir); err != nil {
+		warnings = append(warnings, fmt.Sprintf("warning: failed to remove %s: %v", dir, err))
+	}
+
+	return warnings
+}
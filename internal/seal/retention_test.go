@@ -0,0 +1,156 @@
+package seal
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"seal/internal/testutil"
+	"seal/internal/timeauth"
+)
+
+func TestParseRetention(t *testing.T) {
+	valid := map[string]time.Duration{
+		"7d":  7 * 24 * time.Hour,
+		"1d":  24 * time.Hour,
+		"36h": 36 * time.Hour,
+		"90m": 90 * time.Minute,
+	}
+	for input, want := range valid {
+		got, err := ParseRetention(input)
+		if err != nil {
+			t.Errorf("ParseRetention(%q) failed: %v", input, err)
+			continue
+		}
+		if got != want {
+			t.Errorf("ParseRetention(%q) = %v, want %v", input, got, want)
+		}
+	}
+
+	for _, input := range []string{"", "0d", "-1d", "1.5d", "week", "0s"} {
+		if _, err := ParseRetention(input); err == nil {
+			t.Errorf("ParseRetention(%q) should fail", input)
+		}
+	}
+}
+
+func TestEnforceRetention_ShredsAfterPeriod(t *testing.T) {
+	_, cleanup := testutil.SetupTestEnv(t)
+	defer cleanup()
+
+	authority := &timeauth.FakeAuthority{DefaultRound: 100, CurrentRound: 200}
+	id, err := CreateSealedItemWithOptions(time.Now().UTC().Add(time.Hour), InputSourceStdin, "", []byte("secret"), authority, ItemOptions{
+		RetainUnsealed: 24 * time.Hour,
+	})
+	if err != nil {
+		t.Fatalf("CreateSealedItemWithOptions failed: %v", err)
+	}
+
+	item, itemDir, _ := LoadItem(id)
+	if item.RetainUnsealed != "24h0m0s" {
+		t.Fatalf("retain_unsealed not recorded, got %q", item.RetainUnsealed)
+	}
+
+	item, err = TryMaterialize(item, itemDir, authority)
+	if err != nil {
+		t.Fatalf("TryMaterialize failed: %v", err)
+	}
+
+	unsealedPath := filepath.Join(itemDir, "unsealed")
+
+	// Within the retention period nothing happens
+	item, _, err = enforceRetention(item, itemDir, time.Now().Add(23*time.Hour))
+	if err != nil {
+		t.Fatalf("enforceRetention failed: %v", err)
+	}
+	if item.UnsealedShreddedAt != nil {
+		t.Fatal("unsealed content shredded before retention period elapsed")
+	}
+	if _, err := os.Stat(unsealedPath); err != nil {
+		t.Fatalf("unsealed file should still exist: %v", err)
+	}
+
+	// After the retention period the content is shredded and recorded
+	item, _, err = enforceRetention(item, itemDir, time.Now().Add(25*time.Hour))
+	if err != nil {
+		t.Fatalf("enforceRetention failed: %v", err)
+	}
+	if _, err := os.Stat(unsealedPath); !os.IsNotExist(err) {
+		t.Error("unsealed file should be removed after retention period")
+	}
+
+	persisted, _, _ := LoadItem(id)
+	if persisted.UnsealedShreddedAt == nil || !hasHistoryEvent(persisted, HistoryUnsealedShredded) {
+		t.Errorf("shredding not recorded in metadata: %+v", persisted)
+	}
+
+	// Shredded content is intentionally absent, not corruption
+	if err := ValidateItemState(persisted, itemDir); err != nil {
+		t.Errorf("validation should accept shredded item: %v", err)
+	}
+}
+
+func TestEnforceRetention_CompletesInterruptedShred(t *testing.T) {
+	itemDir := t.TempDir()
+	unlockedAt := time.Now().UTC().Add(-48 * time.Hour)
+	shreddedAt := time.Now().UTC().Add(-time.Hour)
+
+	item := SealedItem{
+		ID:                 "test-id",
+		State:              StateUnlocked,
+		RetainUnsealed:     "24h0m0s",
+		UnlockedAt:         &unlockedAt,
+		UnsealedShreddedAt: &shreddedAt,
+	}
+	unsealedPath := filepath.Join(itemDir, "unsealed")
+	if err := os.WriteFile(unsealedPath, []byte("secret"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, _, err := enforceRetention(item, itemDir, time.Now()); err != nil {
+		t.Fatalf("enforceRetention failed: %v", err)
+	}
+	if _, err := os.Stat(unsealedPath); !os.IsNotExist(err) {
+		t.Error("leftover unsealed file should be shredded")
+	}
+}
+
+func TestEnforceRetention_WaitsForRevealDelivery(t *testing.T) {
+	itemDir := t.TempDir()
+	unlockedAt := time.Now().UTC().Add(-48 * time.Hour)
+
+	item := SealedItem{
+		ID:             "test-id",
+		State:          StateUnlocked,
+		RetainUnsealed: "24h0m0s",
+		UnlockedAt:     &unlockedAt,
+		RevealTo:       "mailto:alice@example.com",
+		RevealStatus:   RevealStatusFailed,
+	}
+	unsealedPath := filepath.Join(itemDir, "unsealed")
+	if err := os.WriteFile(unsealedPath, []byte("secret"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	// A failed delivery is retried, so the content it needs is kept, with a warning
+	item, warnings, err := enforceRetention(item, itemDir, time.Now())
+	if err != nil {
+		t.Fatalf("enforceRetention failed: %v", err)
+	}
+	if len(warnings) != 1 || !strings.Contains(warnings[0], "kept until delivery") {
+		t.Errorf("expected a warning that the content is kept, got %v", warnings)
+	}
+	if _, err := os.Stat(unsealedPath); err != nil {
+		t.Fatalf("unsealed content shredded before delivery: %v", err)
+	}
+
+	item.RevealStatus = RevealStatusDelivered
+	if _, _, err := enforceRetention(item, itemDir, time.Now()); err != nil {
+		t.Fatalf("enforceRetention failed: %v", err)
+	}
+	if _, err := os.Stat(unsealedPath); !os.IsNotExist(err) {
+		t.Error("unsealed content should be shredded once delivered")
+	}
+}
@@ -0,0 +1,95 @@
+package seal
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"seal/internal/clock"
+)
+
+// Reveal delivery states recorded in metadata.
+const (
+	RevealStatusDelivered = "delivered"
+	RevealStatusFailed    = "failed"
+)
+
+// ParseRevealTarget validates a reveal target and returns its scheme and address.
+// Supported targets: mailto:<address>, pass:<entry>, k8s:<namespace>/<name>[/<key>]
+func ParseRevealTarget(target string) (scheme, address string, err error) {
+	scheme, address, ok := strings.Cut(target, ":")
+	if !ok || address == "" {
+		return "", "", fmt.Errorf("invalid reveal target %q, expected <scheme>:<address>", target)
+	}
+
+	switch scheme {
+	case "mailto":
+		if !strings.Contains(address, "@") {
+			return "", "", fmt.Errorf("invalid mailto address: %s", address)
+		}
+	case "pass":
+		if err := ValidatePassEntry(address); err != nil {
+			return "", "", err
+		}
+	case "k8s":
+		if _, err := parseKubernetesSecretRef(address); err != nil {
+			return "", "", err
+		}
+	default:
+		return "", "", fmt.Errorf("unsupported reveal target scheme: %s", scheme)
+	}
+
+	return scheme, address, nil
+}
+
+// deliverPendingReveal delivers unlocked content to the item's reveal target.
+// Delivery is attempted for unlocked items whose reveal has not been delivered yet,
+// so a failed delivery is retried on the next run.
+// The outcome is recorded in metadata. Returns the updated item and a delivery error.
+func deliverPendingReveal(item SealedItem, itemDir string, cfg Config) (SealedItem, error) {
+	if item.RevealTo == "" || item.State != StateUnlocked || item.RevealStatus == RevealStatusDelivered {
+		return item, nil
+	}
+
+	deliverErr := deliverReveal(item, itemDir, cfg)
+
+	now := clock.UTC()
+	record := func(item *SealedItem) {
+		item.RevealAttemptedAt = &now
+		if deliverErr != nil {
+			item.RevealStatus = RevealStatusFailed
+			item.RevealError = deliverErr.Error()
+		} else {
+			item.RevealStatus = RevealStatusDelivered
+			item.RevealError = ""
+		}
+	}
+
+	// Delivery can take a while, so the outcome is recorded on the current metadata
+	updated, err := updateMetadata(itemDir, record)
+	if err != nil {
+		record(&item)
+		return item, fmt.Errorf("failed to record reveal status: %w", err)
+	}
+
+	return updated, deliverErr
+}
+
+// deliverReveal dispatches to the backend for the item's reveal target scheme.
+func deliverReveal(item SealedItem, itemDir string, cfg Config) error {
+	scheme, address, err := ParseRevealTarget(item.RevealTo)
+	if err != nil {
+		return err
+	}
+
+	switch scheme {
+	case "mailto":
+		return deliverMail(item, itemDir, address, cfg.SMTP)
+	case "pass":
+		return deliverPass(item, itemDir, address, cfg)
+	case "k8s":
+		return deliverKubernetes(item, itemDir, address, cfg.Kubernetes)
+	default:
+		return errors.New("unsupported reveal target")
+	}
+}
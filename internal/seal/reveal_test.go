@@ -0,0 +1,237 @@
+package seal
+
+import (
+	"errors"
+	"net/smtp"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"seal/internal/testutil"
+)
+
+func TestParseRevealTarget(t *testing.T) {
+	testCases := []struct {
+		target  string
+		wantErr bool
+	}{
+		{"mailto:alice@example.com", false},
+		{"mailto:", true},
+		{"mailto:not-an-address", true},
+		{"alice@example.com", true},
+		{"ftp:example.com", true},
+		{"pass:web/example", false},
+		{"pass:", true},
+		{"pass:--help", true},
+		{"pass:../outside", true},
+		{"k8s:prod/db-credentials", false},
+		{"k8s:prod/db-credentials/password", false},
+		{"k8s:prod", true},
+	}
+
+	for _, tc := range testCases {
+		_, _, err := ParseRevealTarget(tc.target)
+		if (err != nil) != tc.wantErr {
+			t.Errorf("ParseRevealTarget(%q) error = %v, wantErr %v", tc.target, err, tc.wantErr)
+		}
+	}
+}
+
+// stubSendMail replaces the SMTP transport for the duration of a test.
+func stubSendMail(t *testing.T, fn func(addr string, a smtp.Auth, from string, to []string, msg []byte) error) {
+	t.Helper()
+	orig := sendMail
+	sendMail = fn
+	t.Cleanup(func() { sendMail = orig })
+}
+
+func newUnlockedRevealItem(t *testing.T, plaintext string) (SealedItem, string) {
+	t.Helper()
+	itemDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(itemDir, "unsealed"), []byte(plaintext), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	item := SealedItem{
+		ID:         "a1b2c3d4-5e6f-7890-abcd-ef1234567890",
+		State:      StateUnlocked,
+		UnlockTime: time.Now().UTC().Add(-time.Hour),
+		RevealTo:   "mailto:alice@example.com",
+	}
+	if err := saveMetadata(itemDir, item); err != nil {
+		t.Fatal(err)
+	}
+
+	return item, itemDir
+}
+
+func TestDeliverPendingReveal_RecordsDelivery(t *testing.T) {
+	item, itemDir := newUnlockedRevealItem(t, "revealed prediction")
+
+	var sentTo []string
+	var sentMsg string
+	stubSendMail(t, func(addr string, a smtp.Auth, from string, to []string, msg []byte) error {
+		if addr != "smtp.example.com:587" {
+			t.Errorf("unexpected smtp address: %s", addr)
+		}
+		sentTo = to
+		sentMsg = string(msg)
+		return nil
+	})
+
+	cfg := Config{SMTP: SMTPConfig{Host: "smtp.example.com", From: "seal@example.com"}}
+
+	updated, err := deliverPendingReveal(item, itemDir, cfg)
+	if err != nil {
+		t.Fatalf("deliverPendingReveal failed: %v", err)
+	}
+
+	if len(sentTo) != 1 || sentTo[0] != "alice@example.com" {
+		t.Errorf("unexpected recipients: %v", sentTo)
+	}
+
+	// Default content mode is a notification without the plaintext
+	if strings.Contains(sentMsg, "revealed prediction") {
+		t.Error("notify mode must not include the plaintext")
+	}
+
+	if updated.RevealStatus != RevealStatusDelivered || updated.RevealAttemptedAt == nil {
+		t.Errorf("delivery should be recorded, got %+v", updated)
+	}
+
+	persisted, err := loadMetadata(itemDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if persisted.RevealStatus != RevealStatusDelivered {
+		t.Errorf("delivery status should be persisted, got %q", persisted.RevealStatus)
+	}
+
+	// Delivered reveals are never sent twice
+	stubSendMail(t, func(string, smtp.Auth, string, []string, []byte) error {
+		t.Error("delivered reveal should not be sent again")
+		return nil
+	})
+	if _, err := deliverPendingReveal(updated, itemDir, cfg); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestDeliverPendingReveal_RecordsFailure(t *testing.T) {
+	item, itemDir := newUnlockedRevealItem(t, "data")
+
+	stubSendMail(t, func(string, smtp.Auth, string, []string, []byte) error {
+		return errors.New("connection refused")
+	})
+
+	cfg := Config{SMTP: SMTPConfig{Host: "smtp.example.com", From: "seal@example.com"}}
+
+	updated, err := deliverPendingReveal(item, itemDir, cfg)
+	if err == nil {
+		t.Fatal("expected delivery error")
+	}
+
+	if updated.RevealStatus != RevealStatusFailed || !strings.Contains(updated.RevealError, "connection refused") {
+		t.Errorf("failure should be recorded, got %+v", updated)
+	}
+
+	// Item state is unaffected by delivery failure
+	if updated.State != StateUnlocked {
+		t.Errorf("state should remain unlocked, got %s", updated.State)
+	}
+}
+
+func TestDeliverPendingReveal_SkipsSealedItems(t *testing.T) {
+	stubSendMail(t, func(string, smtp.Auth, string, []string, []byte) error {
+		t.Error("sealed items must never be delivered")
+		return nil
+	})
+
+	item := SealedItem{ID: "test-id", State: StateSealed, RevealTo: "mailto:alice@example.com"}
+	updated, err := deliverPendingReveal(item, t.TempDir(), Config{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if updated.RevealStatus != "" {
+		t.Errorf("no delivery should be recorded, got %q", updated.RevealStatus)
+	}
+}
+
+func TestDeliverMail_NotConfigured(t *testing.T) {
+	item, itemDir := newUnlockedRevealItem(t, "data")
+
+	_, err := deliverPendingReveal(item, itemDir, Config{})
+	if err == nil || !strings.Contains(err.Error(), "smtp is not configured") {
+		t.Errorf("expected configuration error, got: %v", err)
+	}
+}
+
+func TestBuildRevealMessage_ContentModes(t *testing.T) {
+	item, itemDir := newUnlockedRevealItem(t, "the secret")
+	unsealedPath := filepath.Join(itemDir, "unsealed")
+
+	// base64 of "the secret"
+	encoded := "dGhlIHNlY3JldA=="
+
+	testCases := []struct {
+		content     string
+		wantEncoded bool
+		wantPart    string
+	}{
+		{SMTPContentNotify, false, unsealedPath},
+		{SMTPContentPlaintext, true, "Content-Transfer-Encoding: base64"},
+		{SMTPContentAttachment, true, "Content-Disposition: attachment"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.content, func(t *testing.T) {
+			msg, err := buildRevealMessage(item, unsealedPath, "seal@example.com", "alice@example.com", tc.content)
+			if err != nil {
+				t.Fatalf("buildRevealMessage failed: %v", err)
+			}
+
+			if strings.Contains(string(msg), encoded) != tc.wantEncoded {
+				t.Errorf("content inclusion mismatch for mode %s: %s", tc.content, msg)
+			}
+			if !strings.Contains(string(msg), tc.wantPart) {
+				t.Errorf("expected %q in message: %s", tc.wantPart, msg)
+			}
+		})
+	}
+
+	if _, err := buildRevealMessage(item, unsealedPath, "a", "b", "everything"); err == nil {
+		t.Error("expected error for unknown content mode")
+	}
+}
+
+func TestLoadConfig_MissingAndInvalid(t *testing.T) {
+	_, cleanup := testutil.SetupTestEnv(t)
+	defer cleanup()
+
+	cfg, err := LoadConfig()
+	if err != nil {
+		t.Fatalf("missing config should not error: %v", err)
+	}
+	if cfg.SMTP.Host != "" {
+		t.Errorf("expected empty config, got %+v", cfg)
+	}
+
+	baseDir, _ := GetSealBaseDir()
+	os.MkdirAll(baseDir, 0700)
+	os.WriteFile(filepath.Join(baseDir, "config.json"), []byte(`{"smtp":{"host":"mail.example.com","port":2525}}`), 0600)
+
+	cfg, err = LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+	if cfg.SMTP.Host != "mail.example.com" || cfg.SMTP.Port != 2525 {
+		t.Errorf("unexpected config: %+v", cfg)
+	}
+
+	os.WriteFile(filepath.Join(baseDir, "config.json"), []byte("{not json"), 0600)
+	if _, err := LoadConfig(); err == nil {
+		t.Error("expected error for invalid config")
+	}
+}
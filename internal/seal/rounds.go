@@ -0,0 +1,84 @@
+package seal
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"seal/internal/timeauth"
+)
+
+// RoundDetails holds the intermediate values of an item's round math,
+// so the time-lock parameters can be checked against the beacon's published schedule.
+type RoundDetails struct {
+	GenesisTime     time.Time     // round 1
+	Period          time.Duration // time between rounds
+	TargetRound     uint64        // round whose signature decrypts the key
+	RoundTime       time.Time     // genesis + (target_round - 1) * period
+	CurrentRound    uint64        // latest round published by the beacon
+	RoundsRemaining uint64        // target_round - current_round, 0 once reached
+}
+
+// InspectRounds computes round details for an item using its recorded authority.
+// Fetches the latest round from the beacon; no state is changed.
+func InspectRounds(item SealedItem) (RoundDetails, error) {
+	authority := authorityForItem(item)
+	if authority == nil {
+		return RoundDetails{}, fmt.Errorf("item %s: authority %q has no round schedule", item.ID, item.TimeAuthority)
+	}
+
+	return InspectRoundsWithAuthority(item, authority)
+}
+
+// InspectRoundsWithAuthority computes round details using the given authority.
+func InspectRoundsWithAuthority(item SealedItem, authority timeauth.Authority) (RoundDetails, error) {
+	targetRound, err := extractTargetRound(item.KeyRef)
+	if err != nil {
+		return RoundDetails{}, fmt.Errorf("item %s: %w", item.ID, err)
+	}
+
+	genesis, err := authority.RoundTime(1)
+	if err != nil {
+		return RoundDetails{}, fmt.Errorf("failed to calculate round schedule: %w", err)
+	}
+
+	secondRound, err := authority.RoundTime(2)
+	if err != nil {
+		return RoundDetails{}, fmt.Errorf("failed to calculate round schedule: %w", err)
+	}
+
+	roundTime, err := authority.EarliestUnlockTime(timeauth.KeyReference(item.KeyRef))
+	if err != nil {
+		return RoundDetails{}, fmt.Errorf("failed to calculate round time: %w", err)
+	}
+
+	currentRound, err := authority.LatestRound(context.Background())
+	if err != nil {
+		return RoundDetails{}, err
+	}
+
+	var remaining uint64
+	if currentRound < targetRound {
+		remaining = targetRound - currentRound
+	}
+
+	return RoundDetails{
+		GenesisTime:     genesis,
+		Period:          secondRound.Sub(genesis),
+		TargetRound:     targetRound,
+		RoundTime:       roundTime,
+		CurrentRound:    currentRound,
+		RoundsRemaining: remaining,
+	}, nil
+}
+
+// FormatRoundDetails formats round details for display.
+func FormatRoundDetails(details RoundDetails) string {
+	return fmt.Sprintf("genesis_time: %s\nperiod: %s\ntarget_round: %d\nround_time: %s\ncurrent_round: %d\nrounds_remaining: %d\n",
+		details.GenesisTime.Format(time.RFC3339),
+		details.Period,
+		details.TargetRound,
+		details.RoundTime.Format(time.RFC3339),
+		details.CurrentRound,
+		details.RoundsRemaining)
+}
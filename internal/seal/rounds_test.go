@@ -0,0 +1,60 @@
+package seal
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"seal/internal/timeauth"
+)
+
+func TestInspectRoundsWithAuthority_ShowsRoundMath(t *testing.T) {
+	genesis := time.Date(2023, 3, 1, 15, 40, 0, 0, time.UTC)
+	authority := &timeauth.FakeAuthority{
+		GenesisTime:  genesis,
+		Period:       3 * time.Second,
+		CurrentRound: 1000,
+	}
+	item := SealedItem{ID: "test-id", KeyRef: "1200"}
+
+	details, err := InspectRoundsWithAuthority(item, authority)
+	if err != nil {
+		t.Fatalf("InspectRoundsWithAuthority failed: %v", err)
+	}
+
+	if !details.GenesisTime.Equal(genesis) || details.Period != 3*time.Second {
+		t.Errorf("unexpected schedule: genesis %v, period %v", details.GenesisTime, details.Period)
+	}
+	if details.TargetRound != 1200 || details.CurrentRound != 1000 || details.RoundsRemaining != 200 {
+		t.Errorf("unexpected rounds: %+v", details)
+	}
+	if want := genesis.Add(1199 * 3 * time.Second); !details.RoundTime.Equal(want) {
+		t.Errorf("round_time: expected %v, got %v", want, details.RoundTime)
+	}
+
+	output := FormatRoundDetails(details)
+	for _, want := range []string{"period: 3s\n", "target_round: 1200\n", "current_round: 1000\n", "rounds_remaining: 200\n"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("expected %q in output, got: %s", want, output)
+		}
+	}
+}
+
+func TestInspectRoundsWithAuthority_ReachedTarget(t *testing.T) {
+	authority := &timeauth.FakeAuthority{CurrentRound: 1500}
+	details, err := InspectRoundsWithAuthority(SealedItem{ID: "test-id", KeyRef: "1200"}, authority)
+	if err != nil {
+		t.Fatalf("InspectRoundsWithAuthority failed: %v", err)
+	}
+	if details.RoundsRemaining != 0 {
+		t.Errorf("rounds_remaining should be 0 once the target is reached, got %d", details.RoundsRemaining)
+	}
+}
+
+func TestInspectRoundsWithAuthority_BeaconUnreachable(t *testing.T) {
+	authority := &timeauth.FakeAuthority{CanUnlockError: errors.New("network unreachable")}
+	if _, err := InspectRoundsWithAuthority(SealedItem{ID: "test-id", KeyRef: "1200"}, authority); err == nil {
+		t.Error("expected error when the latest round cannot be fetched")
+	}
+}
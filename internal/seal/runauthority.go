@@ -0,0 +1,67 @@
+package seal
+
+import (
+	"context"
+
+	"seal/internal/timeauth"
+)
+
+// runAuthority remembers a time authority's round answers for the rest of one run.
+// An item is checked for unlock and then for its countdown, and a store holds many
+// items; without this each of those is a separate beacon fetch. Rounds are ordered,
+// so every answer also settles the rounds on one side of it: the run ends up with
+// one latest round fetch per authority instance (per drand network), plus one for
+// each item that may have become due while the run went on.
+// Errors are never remembered, so a failed fetch is retried by the next caller.
+type runAuthority struct {
+	timeauth.Authority
+
+	latest      uint64
+	hasLatest   bool
+	published   uint64 // every round up to this one is published
+	unpublished uint64 // no round from this one on is published yet; zero if none is known
+}
+
+func newRunAuthority(authority timeauth.Authority) *runAuthority {
+	return &runAuthority{Authority: authority}
+}
+
+// LatestRound returns the first round fetched in this run.
+func (a *runAuthority) LatestRound(ctx context.Context) (uint64, error) {
+	if a.hasLatest {
+		return a.latest, nil
+	}
+
+	round, err := a.Authority.LatestRound(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	a.latest, a.hasLatest = round, true
+	a.published = max(a.published, round)
+	return round, nil
+}
+
+// CanUnlock answers from memory when it can: rounds up to one known to be
+// published are published, and rounds from one seen unpublished are not, for
+// the rest of the run.
+func (a *runAuthority) CanUnlock(ctx context.Context, targetRound uint64) (bool, error) {
+	if targetRound <= a.published {
+		return true, nil
+	}
+	if a.unpublished != 0 && targetRound >= a.unpublished {
+		return false, nil
+	}
+
+	canUnlock, err := a.Authority.CanUnlock(ctx, targetRound)
+	if err != nil {
+		return false, err
+	}
+
+	if canUnlock {
+		a.published = targetRound
+	} else {
+		a.unpublished = targetRound
+	}
+	return canUnlock, nil
+}
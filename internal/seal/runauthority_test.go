@@ -0,0 +1,89 @@
+package seal
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"seal/internal/timeauth"
+)
+
+// countingAuthority counts round queries reaching the underlying authority.
+type countingAuthority struct {
+	*timeauth.FakeAuthority
+	latestCalls    int
+	canUnlockCalls int
+}
+
+func (c *countingAuthority) LatestRound(ctx context.Context) (uint64, error) {
+	c.latestCalls++
+	return c.FakeAuthority.LatestRound(ctx)
+}
+
+func (c *countingAuthority) CanUnlock(ctx context.Context, targetRound uint64) (bool, error) {
+	c.canUnlockCalls++
+	return c.FakeAuthority.CanUnlock(ctx, targetRound)
+}
+
+func TestRunAuthority_RemembersAnswers(t *testing.T) {
+	inner := &countingAuthority{FakeAuthority: &timeauth.FakeAuthority{CurrentRound: 200}}
+	authority := newRunAuthority(inner)
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		if round, err := authority.LatestRound(ctx); err != nil || round != 200 {
+			t.Fatalf("LatestRound = %d, %v", round, err)
+		}
+	}
+	if inner.latestCalls != 1 {
+		t.Errorf("expected one latest round fetch, got %d", inner.latestCalls)
+	}
+
+	// Rounds up to the latest known round need no fetch
+	if ok, _ := authority.CanUnlock(ctx, 150); !ok {
+		t.Error("round 150 should be unlockable")
+	}
+	if inner.canUnlockCalls != 0 {
+		t.Errorf("expected no fetch for a published round, got %d", inner.canUnlockCalls)
+	}
+
+	// A round not yet published is asked about once
+	for i := 0; i < 3; i++ {
+		if ok, _ := authority.CanUnlock(ctx, 300); ok {
+			t.Error("round 300 should not be unlockable")
+		}
+	}
+	if inner.canUnlockCalls != 1 {
+		t.Errorf("expected one fetch for an unpublished round, got %d", inner.canUnlockCalls)
+	}
+
+	// Later rounds are settled by that answer
+	for _, round := range []uint64{301, 400} {
+		if ok, _ := authority.CanUnlock(ctx, round); ok {
+			t.Errorf("round %d should not be unlockable", round)
+		}
+	}
+	if inner.canUnlockCalls != 1 {
+		t.Errorf("expected no fetch for rounds after an unpublished one, got %d", inner.canUnlockCalls-1)
+	}
+}
+
+func TestRunAuthority_DoesNotRememberErrors(t *testing.T) {
+	inner := &countingAuthority{FakeAuthority: &timeauth.FakeAuthority{CurrentRound: 200, CanUnlockError: errors.New("offline")}}
+	authority := newRunAuthority(inner)
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		if _, err := authority.CanUnlock(ctx, 300); err == nil {
+			t.Fatal("expected error")
+		}
+	}
+	if inner.canUnlockCalls != 2 {
+		t.Errorf("errors must not be remembered, got %d fetches", inner.canUnlockCalls)
+	}
+
+	inner.CanUnlockError = nil
+	if ok, err := authority.CanUnlock(ctx, 300); err != nil || ok {
+		t.Errorf("CanUnlock = %v, %v after recovery", ok, err)
+	}
+}
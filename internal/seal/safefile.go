@@ -0,0 +1,92 @@
+package seal
+
+import (
+	"fmt"
+	"io"
+	"os"
+)
+
+// Item directories may be shared or synced, so a symlink planted inside one
+// could redirect seal's writes elsewhere. Every write inside an item directory
+// goes through createFile, which never follows a symlink at the final path.
+
+// createFile creates path for writing without following symlinks.
+// An existing regular file is replaced; anything else at path is refused.
+func createFile(path string, perm os.FileMode) (*os.File, error) {
+	info, err := os.Lstat(path)
+	if err == nil {
+		if !info.Mode().IsRegular() {
+			return nil, fmt.Errorf("refusing to write %s: not a regular file", path)
+		}
+		if err := os.Remove(path); err != nil {
+			return nil, err
+		}
+	} else if !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	// O_EXCL fails if anything, including a symlink, appears at path in the meantime
+	return os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, perm)
+}
+
+// writeFileNoFollow writes data to path via createFile and syncs it to disk.
+// On failure no partial file is left behind.
+func writeFileNoFollow(path string, data []byte, perm os.FileMode) error {
+	file, err := createFile(path, perm)
+	if err != nil {
+		return err
+	}
+
+	_, err = file.Write(data)
+	if err == nil {
+		err = file.Sync()
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(path)
+		return err
+	}
+
+	return nil
+}
+
+// writeStreamNoFollow creates path via createFile, lets write fill it, and syncs it to disk.
+// On failure no partial file is left behind.
+func writeStreamNoFollow(path string, perm os.FileMode, write func(w io.Writer) error) error {
+	file, err := createFile(path, perm)
+	if err != nil {
+		return err
+	}
+
+	err = write(file)
+	if err == nil {
+		err = file.Sync()
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(path)
+		return err
+	}
+
+	return nil
+}
+
+// checkItemDir verifies that an item directory is a real directory and not a symlink.
+// A missing directory is not reported here; operations on it fail on their own.
+func checkItemDir(itemDir string) error {
+	info, err := os.Lstat(itemDir)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("cannot inspect item directory: %w", err)
+	}
+	if info.Mode()&os.ModeSymlink != 0 || !info.IsDir() {
+		return fmt.Errorf("item directory %s is not a real directory (possible symlink attack)", itemDir)
+	}
+	return nil
+}
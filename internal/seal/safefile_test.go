@@ -0,0 +1,126 @@
+package seal
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"seal/internal/testutil"
+	"seal/internal/timeauth"
+)
+
+func TestWriteFileNoFollow_RefusesSymlink(t *testing.T) {
+	dir := t.TempDir()
+	victim := filepath.Join(dir, "victim")
+	if err := os.WriteFile(victim, []byte("original"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	link := filepath.Join(dir, "meta.json.tmp")
+	if err := os.Symlink(victim, link); err != nil {
+		t.Fatal(err)
+	}
+
+	err := writeFileNoFollow(link, []byte("attacker"), 0600)
+	if err == nil || !strings.Contains(err.Error(), "not a regular file") {
+		t.Fatalf("expected refusal to write through symlink, got: %v", err)
+	}
+
+	data, _ := os.ReadFile(victim)
+	if string(data) != "original" {
+		t.Errorf("symlink target was modified: %q", data)
+	}
+}
+
+func TestWriteFileNoFollow_ReplacesRegularFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "file")
+	if err := os.WriteFile(path, []byte("old"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := writeFileNoFollow(path, []byte("new"), 0600); err != nil {
+		t.Fatalf("writeFileNoFollow failed: %v", err)
+	}
+
+	data, _ := os.ReadFile(path)
+	if string(data) != "new" {
+		t.Errorf("expected new content, got %q", data)
+	}
+}
+
+func TestMaterialize_SymlinkedPendingFile_NotFollowed(t *testing.T) {
+	_, cleanup := testutil.SetupTestEnv(t)
+	defer cleanup()
+
+	authority := &timeauth.FakeAuthority{DefaultRound: 100, CurrentRound: 200}
+	id, err := CreateSealedItem(time.Now().UTC().Add(time.Hour), InputSourceStdin, "", []byte("secret"), authority)
+	if err != nil {
+		t.Fatalf("CreateSealedItem failed: %v", err)
+	}
+
+	item, itemDir, _ := LoadItem(id)
+
+	victim := filepath.Join(t.TempDir(), "victim")
+	if err := os.WriteFile(victim, []byte("original"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(victim, filepath.Join(itemDir, "unsealed.pending")); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := TryMaterialize(item, itemDir, authority); err == nil {
+		t.Fatal("expected materialization to refuse a symlinked pending file")
+	}
+
+	data, _ := os.ReadFile(victim)
+	if string(data) != "original" {
+		t.Errorf("symlink target was modified: %q", data)
+	}
+}
+
+func TestMaterialize_SymlinkedItemDir_Rejected(t *testing.T) {
+	_, cleanup := testutil.SetupTestEnv(t)
+	defer cleanup()
+
+	authority := &timeauth.FakeAuthority{DefaultRound: 100, CurrentRound: 200}
+	id, err := CreateSealedItem(time.Now().UTC().Add(time.Hour), InputSourceStdin, "", []byte("secret"), authority)
+	if err != nil {
+		t.Fatalf("CreateSealedItem failed: %v", err)
+	}
+
+	item, itemDir, _ := LoadItem(id)
+
+	link := filepath.Join(t.TempDir(), "linked-item")
+	if err := os.Symlink(itemDir, link); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = TryMaterialize(item, link, authority)
+	if err == nil || !strings.Contains(err.Error(), "not a real directory") {
+		t.Fatalf("expected symlinked item directory to be rejected, got: %v", err)
+	}
+}
+
+func TestShredFile_RefusesSymlink(t *testing.T) {
+	dir := t.TempDir()
+	victim := filepath.Join(dir, "victim")
+	if err := os.WriteFile(victim, []byte("original"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	link := filepath.Join(dir, "link")
+	if err := os.Symlink(victim, link); err != nil {
+		t.Fatal(err)
+	}
+
+	warnings := ShredFile(link)
+	if len(warnings) == 0 {
+		t.Error("expected a warning when shredding a symlink")
+	}
+
+	data, _ := os.ReadFile(victim)
+	if string(data) != "original" {
+		t.Errorf("symlink target was modified: %q", data)
+	}
+}
@@ -1,6 +1,8 @@
 package seal
 
 import (
+	"bufio"
+	"bytes"
 	"crypto/aes"
 	"crypto/cipher"
 	"crypto/rand"
@@ -13,57 +15,68 @@ import (
 	"os/exec"
 	"path/filepath"
 	"runtime"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
+	"seal/internal/clock"
 	"seal/internal/timeauth"
 )
 
 // ParseUnlockTime parses and validates an unlock timestamp.
-// Accepts only RFC3339 format.
+// Accepts RFC3339 format, where a leap second is read as the following second,
+// or a lock duration from now with a leading + (e.g. +7d), see ParseLockDuration.
 // Rejects past timestamps.
 // Returns time normalized to UTC.
 func ParseUnlockTime(s string) (time.Time, error) {
-	t, err := time.Parse(time.RFC3339, s)
-	if err != nil {
-		return time.Time{}, fmt.Errorf("invalid time format, expected RFC3339")
+	if rel, ok := strings.CutPrefix(s, "+"); ok {
+		return unlockAfter(rel)
 	}
 
-	t = t.UTC()
-	now := time.Now().UTC()
+	t, err := clock.ParseRFC3339(s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid time format, expected RFC3339 or +<duration> (e.g. +7d)")
+	}
 
-	if !t.After(now) {
+	if !t.After(clock.UTC()) {
 		return time.Time{}, fmt.Errorf("unlock time must be in the future")
 	}
 
 	return t, nil
 }
 
+// StdinMode controls whether ReadInput may read stdin.
+type StdinMode int
+
+const (
+	// StdinAuto reads the file if a path is given, otherwise stdin if it is not a terminal or /dev/null.
+	StdinAuto StdinMode = iota
+	// StdinRequired always reads stdin; a file path is an error.
+	StdinRequired
+	// StdinNever never touches stdin; a file path is required.
+	StdinNever
+)
+
 // ReadInput reads input from either a file path or stdin.
 // Enforces maximum size limit.
 // Returns data, source type, and error.
 func ReadInput(path string) ([]byte, InputSource, error) {
-	stdinStat, err := os.Stdin.Stat()
-	if err != nil {
-		return nil, 0, fmt.Errorf("cannot stat stdin: %w", err)
-	}
-
-	stdinHasData := (stdinStat.Mode() & os.ModeCharDevice) == 0
-
-	// Case: both file path and stdin
-	if path != "" && stdinHasData {
-		return nil, 0, errors.New("cannot read from both file and stdin")
-	}
+	return ReadInputWithMode(path, StdinAuto)
+}
 
-	// Case: neither file path nor stdin
-	if path == "" && !stdinHasData {
-		return nil, 0, errors.New("no input provided (use file path or pipe to stdin)")
+// ReadInputWithMode reads input like ReadInput, with explicit control over stdin.
+// A file path always takes precedence in StdinAuto mode: under cron or systemd,
+// stdin is often an empty pipe or /dev/null that was never meant as input.
+func ReadInputWithMode(path string, mode StdinMode) ([]byte, InputSource, error) {
+	useStdin, err := inputIsStdin(path, mode)
+	if err != nil {
+		return nil, 0, err
 	}
 
 	var data []byte
 	var source InputSource
 
-	if path != "" {
+	if !useStdin {
 		// Read from file
 		source = InputSourceFile
 		file, err := os.Open(path)
@@ -110,10 +123,91 @@ func ReadInput(path string) ([]byte, InputSource, error) {
 	return data, source, nil
 }
 
+// inputIsStdin decides whether input is read from stdin or from the file at path.
+func inputIsStdin(path string, mode StdinMode) (bool, error) {
+	switch mode {
+	case StdinRequired:
+		if path != "" {
+			return false, errors.New("cannot read from both file and stdin")
+		}
+		return true, nil
+	case StdinNever:
+		if path == "" {
+			return false, errors.New("no input provided (a file path is required when stdin is disabled)")
+		}
+		return false, nil
+	default:
+		if path != "" {
+			return false, nil
+		}
+		present, err := stdinPresent()
+		if err != nil {
+			return false, err
+		}
+		if !present {
+			return false, errors.New("no input provided (use file path or pipe to stdin)")
+		}
+		return true, nil
+	}
+}
+
+// CheckInputContent rejects input that is almost certainly a mistake:
+// content that is only whitespace, or shorter than minSize bytes (0 = no minimum).
+// Sealing cannot be undone, so lock asks for an explicit override instead.
+func CheckInputContent(data []byte, minSize int) error {
+	if err := checkInputContent(data, minSize); err != nil {
+		return fmt.Errorf("%w (use --allow-small to seal it anyway)", err)
+	}
+	return nil
+}
+
+// checkInputContent is CheckInputContent for callers without an override,
+// such as pipe and watch-folder, which reject the input instead.
+func checkInputContent(data []byte, minSize int) error {
+	if len(bytes.TrimSpace(data)) == 0 {
+		return errors.New("input contains only whitespace")
+	}
+
+	if minSize > 0 && len(data) < minSize {
+		return fmt.Errorf("input is %d bytes, below the configured minimum of %d", len(data), minSize)
+	}
+
+	return nil
+}
+
+// stdinPresent reports whether stdin may carry input: terminals and /dev/null never do.
+func stdinPresent() (bool, error) {
+	stdinStat, err := os.Stdin.Stat()
+	if err != nil {
+		return false, fmt.Errorf("cannot stat stdin: %w", err)
+	}
+
+	if stdinStat.Mode()&os.ModeCharDevice != 0 {
+		return false, nil
+	}
+
+	if devNull, err := os.Stat(os.DevNull); err == nil && os.SameFile(stdinStat, devNull) {
+		return false, nil
+	}
+
+	return true, nil
+}
+
 // EncryptPayload encrypts plaintext using AES-256-GCM with a fresh DEK.
 // Returns ciphertext, nonce (base64), and the unwrapped DEK.
 // The DEK must be wrapped before storage.
 func EncryptPayload(plaintext []byte) (ciphertext []byte, nonceB64 string, dek []byte, err error) {
+	return EncryptPayloadWithAAD(plaintext, nil)
+}
+
+// EncryptPayloadWithAAD encrypts plaintext like EncryptPayload, authenticating aad alongside it.
+// The same aad must be supplied to decrypt.
+func EncryptPayloadWithAAD(plaintext, aad []byte) (ciphertext []byte, nonceB64 string, dek []byte, err error) {
+	return encryptPayloadTo(nil, plaintext, aad)
+}
+
+// encryptPayloadTo encrypts like EncryptPayloadWithAAD, appending the ciphertext to dst.
+func encryptPayloadTo(dst, plaintext, aad []byte) (ciphertext []byte, nonceB64 string, dek []byte, err error) {
 	// Generate random 32-byte DEK for AES-256
 	dek = make([]byte, 32)
 	if _, err := io.ReadFull(rand.Reader, dek); err != nil {
@@ -139,7 +233,7 @@ func EncryptPayload(plaintext []byte) (ciphertext []byte, nonceB64 string, dek [
 	}
 
 	// Encrypt plaintext
-	ciphertext = gcm.Seal(nil, nonce, plaintext, nil)
+	ciphertext = gcm.Seal(dst, nonce, plaintext, aad)
 
 	// Encode nonce as base64 for storage
 	nonceB64 = base64.StdEncoding.EncodeToString(nonce)
@@ -147,62 +241,6 @@ func EncryptPayload(plaintext []byte) (ciphertext []byte, nonceB64 string, dek [
 	return ciphertext, nonceB64, dek, nil
 }
 
-// ShredFile performs best-effort file shredding.
-// Overwrites the file with zeroes, syncs, and removes it.
-// Returns a slice of warnings encountered (does not fail on errors).
-func ShredFile(path string) []string {
-	var warnings []string
-
-	// Open file for writing
-	file, err := os.OpenFile(path, os.O_WRONLY, 0)
-	if err != nil {
-		warnings = append(warnings, fmt.Sprintf("warning: failed to open file for shredding: %v", err))
-		return warnings
-	}
-	defer file.Close()
-
-	// Get file size
-	info, err := file.Stat()
-	if err != nil {
-		warnings = append(warnings, fmt.Sprintf("warning: failed to stat file for shredding: %v", err))
-		return warnings
-	}
-
-	size := info.Size()
-
-	// Overwrite with zeroes (single pass)
-	zeroes := make([]byte, 4096) // Use 4KB buffer for efficiency
-	var written int64
-	for written < size {
-		toWrite := int64(len(zeroes))
-		if written+toWrite > size {
-			toWrite = size - written
-		}
-
-		n, err := file.Write(zeroes[:toWrite])
-		if err != nil {
-			warnings = append(warnings, fmt.Sprintf("warning: failed to overwrite file during shredding: %v", err))
-			return warnings
-		}
-		written += int64(n)
-	}
-
-	// Sync to disk
-	if err := file.Sync(); err != nil {
-		warnings = append(warnings, fmt.Sprintf("warning: failed to sync file during shredding: %v", err))
-	}
-
-	file.Close()
-
-	// Remove file
-	if err := os.Remove(path); err != nil {
-		warnings = append(warnings, fmt.Sprintf("warning: failed to remove file after shredding: %v", err))
-		return warnings
-	}
-
-	return warnings
-}
-
 // ClearClipboard performs best-effort clipboard clearing.
 // Overwrites the system clipboard with an empty string.
 // Returns a slice of warnings encountered (does not fail on errors).
@@ -245,11 +283,40 @@ func ClearClipboard() []string {
 	return warnings
 }
 
+// payloadAlgorithm is the payload cipher recorded in metadata.
+const payloadAlgorithm = "aes-256-gcm"
+
+// ItemOptions contains optional metadata recorded on a new sealed item.
+type ItemOptions struct {
+	RevealTo        string        // reveal target, see ParseRevealTarget
+	Notify          []string      // notification sink names from config
+	OnUnlockExec    string        // absolute program run on unlock, see ResolveUnlockExec
+	OnUnlockWebhook string        // URL posted to on unlock, see ValidateUnlockWebhook
+	RetainUnsealed  time.Duration // shred unsealed content this long after unlock (0 = keep)
+	UnsealTo        string        // absolute directory to materialize into, see ResolveUnsealTo
+	Immutable       bool          // set the immutable attribute on meta.json and payload.bin
+	PostProcess     []string      // post-processing steps, see ValidatePostProcessSteps
+	Recipient       Contact       // age-encrypt content to this recipient before sealing, see ResolveRecipient
+	Passphrase      []byte        // encrypt content with this passphrase before sealing, see ReadPassphraseFile
+	Normalization   []string      // normalization steps applied to the input, see NormalizeInput
+	VaultWrap       string        // Vault transit key that also wraps the DEK, see ParseVaultTransitKey
+	Vault           VaultConfig   // Vault server for VaultWrap
+	Tags            map[string]string
+	Note            string
+	Archive         *ArchiveInfo // the input is an archived directory, see readDirectoryInput
+	DirectTlock     bool         // time-lock the content itself instead of a DEK, see MaxDirectTlockSize
+}
+
 // CreateSealedItem creates a new sealed item on disk.
 // Encrypts the payload using AES-256-GCM with a fresh DEK.
 // Uses the provided time authority to generate a key reference.
 // Returns the item ID and error.
 func CreateSealedItem(unlockTime time.Time, inputType InputSource, originalPath string, plaintext []byte, authority timeauth.Authority) (string, error) {
+	return CreateSealedItemWithOptions(unlockTime, inputType, originalPath, plaintext, authority, ItemOptions{})
+}
+
+// CreateSealedItemWithOptions creates a new sealed item on disk with optional metadata.
+func CreateSealedItemWithOptions(unlockTime time.Time, inputType InputSource, originalPath string, plaintext []byte, authority timeauth.Authority, opts ItemOptions) (string, error) {
 	baseDir, err := GetSealBaseDir()
 	if err != nil {
 		return "", err
@@ -260,17 +327,22 @@ func CreateSealedItem(unlockTime time.Time, inputType InputSource, originalPath
 		return "", fmt.Errorf("cannot create seal directory: %w", err)
 	}
 
-	// Encrypt payload (returns DEK for wrapping)
-	ciphertext, nonceB64, dek, err := EncryptPayload(plaintext)
-	if err != nil {
-		return "", fmt.Errorf("encryption failed: %w", err)
+	// Only the recipient's identity can read the content once it unlocks
+	if opts.Recipient.Recipient != "" {
+		plaintext, err = encryptToRecipient(plaintext, opts.Recipient.Recipient)
+		if err != nil {
+			return "", fmt.Errorf("recipient encryption failed: %w", err)
+		}
 	}
-	defer func() {
-		// Zero out DEK from memory after use
-		for i := range dek {
-			dek[i] = 0
+
+	// Reading the content once it unlocks also requires the passphrase
+	var passphrase *PassphraseProtection
+	if opts.Passphrase != nil {
+		plaintext, passphrase, err = encryptWithPassphrase(plaintext, opts.Passphrase)
+		if err != nil {
+			return "", fmt.Errorf("passphrase encryption failed: %w", err)
 		}
-	}()
+	}
 
 	// Calculate target round for unlock time
 	targetRound, err := authority.RoundAt(unlockTime)
@@ -278,19 +350,33 @@ func CreateSealedItem(unlockTime time.Time, inputType InputSource, originalPath
 		return "", fmt.Errorf("failed to calculate target round: %w", err)
 	}
 
-	// Time-lock encrypt the DEK to the target round
-	tlockB64, err := authority.TimeLockEncrypt(dek, targetRound)
-	if err != nil {
-		return "", fmt.Errorf("failed to time-lock encrypt DEK: %w", err)
+	if opts.DirectTlock {
+		return createDirectTlockItem(baseDir, unlockTime, inputType, originalPath, plaintext, authority, targetRound, opts, passphrase)
 	}
 
 	// Generate UUID for this sealed item
 	id := uuid.New().String()
-	itemDir := filepath.Join(baseDir, id)
 
-	// Create item directory
-	if err := os.Mkdir(itemDir, 0700); err != nil {
-		return "", fmt.Errorf("cannot create item directory: %w", err)
+	// Hold the ciphertext buffer within the process-wide memory budget
+	ciphertextSize := len(plaintext) + 16 // GCM tag
+	defer payloadBudget.release(payloadBudget.acquire(int64(ciphertextSize)))
+
+	// Encrypt payload bound to the item's identity (returns DEK for wrapping)
+	ciphertext, nonceB64, dek, err := encryptPayloadTo(getPayloadBuffer(ciphertextSize), plaintext, payloadAAD(id, targetRound, payloadAlgorithm))
+	if err != nil {
+		return "", fmt.Errorf("encryption failed: %w", err)
+	}
+	defer putPayloadBuffer(ciphertext)
+	defer func() {
+		// Zero out DEK from memory after use
+		for i := range dek {
+			dek[i] = 0
+		}
+	}()
+
+	tlockB64, err := timeLockDEK(dek, targetRound, authority, opts)
+	if err != nil {
+		return "", err
 	}
 
 	// Create key reference for metadata (authority-specific format preserved via Lock method)
@@ -300,46 +386,166 @@ func CreateSealedItem(unlockTime time.Time, inputType InputSource, originalPath
 	}
 
 	// Create metadata
-	meta := SealedItem{
-		ID:            id,
-		State:         StateSealed,
-		UnlockTime:    unlockTime.UTC(),
-		InputType:     inputType.String(),
-		OriginalPath:  originalPath,
-		TimeAuthority: authority.Name(),
-		CreatedAt:     time.Now().UTC(),
-		Algorithm:     "aes-256-gcm",
-		Nonce:         nonceB64,
-		KeyRef:        string(keyRef),
-		DEKTlockB64:   tlockB64,
-	}
-
-	// Write metadata
-	metaPath := filepath.Join(itemDir, "meta.json")
+	meta := newItemMetadata(id, unlockTime, inputType, originalPath, authority, keyRef, tlockB64, opts)
+	meta.Algorithm = payloadAlgorithm
+	meta.Nonce = nonceB64
+	meta.PlaintextSize = int64(len(plaintext))
+	meta.CiphertextSize = int64(len(ciphertext))
+	meta.PayloadSHA256 = payloadChecksum(ciphertext)
+	meta.Passphrase = passphrase
+	appendHistory(&meta, HistoryCreated, "")
+
+	// Write encrypted payload (ciphertext only, nonce is in metadata)
+	if err := writeNewItem(baseDir, meta, ciphertext); err != nil {
+		return "", err
+	}
+
+	return id, nil
+}
+
+// writeNewItem creates an item directory holding meta and its payload, see createItemStaged.
+func writeNewItem(baseDir string, meta SealedItem, payload []byte) error {
 	metaJSON, err := json.MarshalIndent(meta, "", "  ")
 	if err != nil {
-		return "", fmt.Errorf("cannot marshal metadata: %w", err)
+		return fmt.Errorf("cannot marshal metadata: %w", err)
 	}
 
-	if err := os.WriteFile(metaPath, metaJSON, 0600); err != nil {
-		return "", fmt.Errorf("cannot write metadata: %w", err)
+	return createItemStaged(baseDir, meta.ID, func(dir string) error {
+		if err := writeFileNoFollow(filepath.Join(dir, "meta.json"), metaJSON, 0600); err != nil {
+			return fmt.Errorf("cannot write metadata: %w", err)
+		}
+		if err := writeFileNoFollow(filepath.Join(dir, "payload.bin"), payload, 0600); err != nil {
+			return fmt.Errorf("cannot write payload: %w", err)
+		}
+		return nil
+	})
+}
+
+// timeLockDEK prepares a DEK for storage: wrapped by the Vault transit key if one was
+// requested, then time-locked to the target round.
+func timeLockDEK(dek []byte, targetRound uint64, authority timeauth.Authority, opts ItemOptions) (string, error) {
+	// Unlocking then also requires access to the Vault transit key
+	lockedKey := dek
+	if opts.VaultWrap != "" {
+		var err error
+		lockedKey, err = vaultWrapDEK(opts.VaultWrap, dek, opts.Vault)
+		if err != nil {
+			return "", err
+		}
 	}
 
-	// Write encrypted payload (ciphertext only, nonce is in metadata)
-	payloadPath := filepath.Join(itemDir, "payload.bin")
-	if err := os.WriteFile(payloadPath, ciphertext, 0600); err != nil {
-		return "", fmt.Errorf("cannot write payload: %w", err)
+	// Time-lock encrypt the DEK to the target round
+	tlockB64, err := authority.TimeLockEncrypt(lockedKey, targetRound)
+	if err != nil {
+		return "", fmt.Errorf("failed to time-lock encrypt DEK: %w", err)
 	}
 
-	return id, nil
+	// An item without a time-locked key could never unlock; only tests may create one
+	if tlockB64 == "" && !placeholderItemsAllowed {
+		return "", fmt.Errorf("time authority %s cannot time-lock the key; the item could never unlock", authority.Name())
+	}
+
+	return tlockB64, nil
+}
+
+// newItemMetadata returns the metadata of a new sealed item.
+// The payload fields (algorithm, nonce, sizes, checksum) are filled in by the caller.
+func newItemMetadata(id string, unlockTime time.Time, inputType InputSource, originalPath string, authority timeauth.Authority, keyRef timeauth.KeyReference, tlockB64 string, opts ItemOptions) SealedItem {
+	meta := SealedItem{
+		ID:              id,
+		State:           StateSealed,
+		UnlockTime:      unlockTime.UTC(),
+		InputType:       inputType.String(),
+		OriginalPath:    originalPath,
+		TimeAuthority:   authority.Name(),
+		CreatedAt:       clock.UTC(),
+		KeyRef:          string(keyRef),
+		DEKTlockB64:     tlockB64,
+		SealVersion:     GetBuildInfo().Version,
+		SchemaVersion:   MetadataSchemaVersion,
+		AADVersion:      aadVersion,
+		RevealTo:        opts.RevealTo,
+		Notify:          opts.Notify,
+		OnUnlockExec:    opts.OnUnlockExec,
+		OnUnlockWebhook: opts.OnUnlockWebhook,
+		UnsealTo:        opts.UnsealTo,
+		Immutable:       opts.Immutable,
+		PostProcess:     opts.PostProcess,
+		Recipient:       opts.Recipient.Recipient,
+		RecipientName:   opts.Recipient.Name,
+		Normalization:   opts.Normalization,
+		VaultWrap:       opts.VaultWrap,
+		Tags:            opts.Tags,
+		Note:            opts.Note,
+		Archive:         opts.Archive,
+	}
+	if opts.RetainUnsealed > 0 {
+		meta.RetainUnsealed = opts.RetainUnsealed.String()
+	}
+	return meta
+}
+
+// placeholderItemsAllowed is whether items without a time-locked key may be created:
+// only in test builds, where the placeholder authority stands in for drand.
+// Tests of this package set it in testhelpers_test.go.
+var placeholderItemsAllowed = timeauth.TestBuild
+
+// ResolveUnsealTo validates a materialization destination and returns it as an absolute path.
+// The directory must exist when the item is locked. Writability is checked at unlock time.
+func ResolveUnsealTo(dir string) (string, error) {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return "", fmt.Errorf("cannot resolve unseal destination: %w", err)
+	}
+
+	info, err := os.Stat(abs)
+	if err != nil {
+		return "", fmt.Errorf("cannot use unseal destination: %w", err)
+	}
+	if !info.IsDir() {
+		return "", fmt.Errorf("unseal destination is not a directory: %s", abs)
+	}
+
+	return abs, nil
 }
 
 // LockRequest contains parameters for locking content.
 type LockRequest struct {
-	InputPath      string
-	UnlockTime     string
-	Shred          bool
-	ClearClipboard bool
+	InputPath       string
+	Data            []byte // input given directly instead of a path or stdin, e.g. by LockBatch
+	UnlockTime      string // RFC3339 or +<duration>, see ParseUnlockTime
+	For             string // lock duration from now instead of UnlockTime, see ParseLockDuration
+	Shred           bool
+	ShredPasses     int    // overwrite passes for Shred, overriding the configured policy (0 = configured)
+	ShredPattern    string // overwrite pattern for Shred, overriding the configured policy (empty = configured)
+	ClearClipboard  bool
+	RevealTo        string
+	Notify          []string
+	OnUnlockExec    string // program run when the item unlocks, see ResolveUnlockExec
+	OnUnlockWebhook string // URL posted to when the item unlocks, see ValidateUnlockWebhook
+	RetainUnsealed  string // e.g. 7d, see ParseRetention
+	UnsealTo        string // directory to materialize into instead of the store
+	Immutable       bool
+	PostProcess     []string
+	Recipient       string // contact name or age public key (age1...)
+	Passphrase      []byte // also required to read the content after unlock, see ReadPassphraseFile
+	DirectTlock     bool   // time-lock small content directly, without a DEK, see MaxDirectTlockSize
+	Stdin           StdinMode
+	AllowSmall      bool     // seal whitespace-only or below-minimum input, see CheckInputContent
+	PasswordMode    bool     // input is a single password, see NormalizePassword
+	StripNewline    bool     // remove one trailing newline from the input
+	Encoding        string   // EncodingRaw (default) or EncodingUTF8
+	FromPass        string   // read input from this password store entry, see ReadPassEntry
+	VaultWrap       string   // Vault transit key that also wraps the DEK, e.g. transit/keys/foo
+	Tags            []string // key=value pairs, see ParseTags
+	Note            string   // freeform description, see ValidateNote
+	Authority       string   // registered time authority name, see timeauth.NewAuthority (default drand)
+	Network         string   // authority network name, e.g. a drand network, see timeauth.ResolveDrandNetwork
+	ChainHash       string   // custom chain hash instead of a network name
+	Relay           string   // relay URL instead of the network's default
+	Members         []string // threshold authority members, see timeauth.ParseMemberSpec
+	Threshold       int      // members needed to unlock a threshold item
+	Attest          bool     // timestamp the ciphertext with OpenTimestamps, see AttestItem
 }
 
 // LockResult contains the result of a lock operation.
@@ -351,31 +557,320 @@ type LockResult struct {
 // Lock encrypts and seals content until a future time.
 func Lock(req LockRequest) (LockResult, error) {
 	// Parse unlock time
-	unlockTime, err := ParseUnlockTime(req.UnlockTime)
+	unlockTime, err := ParseUnlockSpec(req.UnlockTime, req.For)
+	if err != nil {
+		return LockResult{}, err
+	}
+
+	// Create time authority from the registry (default authority unless one was selected)
+	authority, err := timeauth.NewAuthority(req.Authority, timeauth.Options{
+		Network:   req.Network,
+		ChainHash: req.ChainHash,
+		Relay:     req.Relay,
+		Members:   req.Members,
+		Threshold: req.Threshold,
+	})
 	if err != nil {
 		return LockResult{}, err
 	}
 
-	// Read input data
-	inputData, inputSrc, err := ReadInput(req.InputPath)
+	// Enforce the lock-time policy before anything else is done
+	policy, err := LoadPolicy()
+	if err != nil {
+		return LockResult{}, err
+	}
+	if err := policy.Check(PolicyRequest{
+		UnlockTime: unlockTime,
+		InputPath:  req.InputPath,
+		Shred:      req.Shred,
+		Authority:  authority.Name(),
+	}, clock.UTC()); err != nil {
+		return LockResult{}, err
+	}
+
+	// Validate shredding options before reading input
+	shredOpts := ShredOptions{Passes: req.ShredPasses, Pattern: req.ShredPattern}
+	if shredOpts != (ShredOptions{}) {
+		if !req.Shred {
+			return LockResult{}, errors.New("--shred-passes and --shred-pattern require --shred")
+		}
+		if err := shredOpts.Validate(); err != nil {
+			return LockResult{}, err
+		}
+	}
+
+	// Validate reveal target before reading input
+	if req.RevealTo != "" {
+		if _, _, err := ParseRevealTarget(req.RevealTo); err != nil {
+			return LockResult{}, err
+		}
+	}
+
+	// Validate notification sinks and resolve the recipient against the configuration
+	var recipient Contact
+	if len(req.Notify) > 0 || req.Recipient != "" {
+		cfg, err := LoadConfig()
+		if err != nil {
+			return LockResult{}, err
+		}
+		if err := ValidateNotifySinks(req.Notify, cfg); err != nil {
+			return LockResult{}, err
+		}
+		if req.Recipient != "" {
+			recipient, err = ResolveRecipient(req.Recipient, cfg)
+			if err != nil {
+				return LockResult{}, err
+			}
+		}
+	}
+
+	var onUnlockExec string
+	if req.OnUnlockExec != "" {
+		onUnlockExec, err = ResolveUnlockExec(req.OnUnlockExec)
+		if err != nil {
+			return LockResult{}, err
+		}
+	}
+	if req.OnUnlockWebhook != "" {
+		if err := ValidateUnlockWebhook(req.OnUnlockWebhook); err != nil {
+			return LockResult{}, err
+		}
+	}
+
+	var retain time.Duration
+	if req.RetainUnsealed != "" {
+		retain, err = ParseRetention(req.RetainUnsealed)
+		if err != nil {
+			return LockResult{}, err
+		}
+	}
+
+	resolvedPostProcess, err := ResolvePostProcessSteps(req.PostProcess)
 	if err != nil {
 		return LockResult{}, err
 	}
 
+	// Both would act on the content, which stays encrypted until opened with the passphrase
+	if req.Passphrase != nil && (req.Recipient != "" || len(req.PostProcess) > 0) {
+		return LockResult{}, errors.New("a passphrase cannot be combined with --recipient or --post-process")
+	}
+	if req.Passphrase != nil && len(req.Passphrase) == 0 {
+		return LockResult{}, errors.New("passphrase is empty")
+	}
+
+	// Without a DEK there is nothing to wrap or split
+	if req.DirectTlock && req.VaultWrap != "" {
+		return LockResult{}, errors.New("--direct-tlock cannot be combined with --vault-wrap")
+	}
+	if req.DirectTlock && authority.Name() == timeauth.MultiAuthorityName {
+		return LockResult{}, errors.New("--direct-tlock is not supported for threshold items")
+	}
+
+	if err := ValidateEncoding(req.Encoding); err != nil {
+		return LockResult{}, err
+	}
+
+	tags, err := ParseTags(req.Tags)
+	if err != nil {
+		return LockResult{}, err
+	}
+	if err := ValidateNote(req.Note); err != nil {
+		return LockResult{}, err
+	}
+
+	var vault VaultConfig
+	if req.VaultWrap != "" {
+		if _, err := ParseVaultTransitKey(req.VaultWrap); err != nil {
+			return LockResult{}, err
+		}
+		cfg, err := LoadConfig()
+		if err != nil {
+			return LockResult{}, err
+		}
+		vault = cfg.Vault
+	}
+
+	var unsealTo string
+	if req.UnsealTo != "" {
+		unsealTo, err = ResolveUnsealTo(req.UnsealTo)
+		if err != nil {
+			return LockResult{}, err
+		}
+	}
+
+	// Read input data; input larger than MaxInputSize is streamed instead
+	var inputData []byte
+	var inputStream io.ReadCloser
+	var inputSrc InputSource
+	var archive *ArchiveInfo
+	postProcess := resolvedPostProcess
+	originalPath := req.InputPath
+	if req.FromPass != "" {
+		if req.InputPath != "" || req.Stdin == StdinRequired {
+			return LockResult{}, errors.New("cannot read from both a pass entry and another input")
+		}
+		cfg, err := LoadConfig()
+		if err != nil {
+			return LockResult{}, err
+		}
+		inputData, err = ReadPassEntry(req.FromPass, cfg)
+		if err != nil {
+			return LockResult{}, err
+		}
+		inputSrc = InputSourcePass
+		originalPath = "pass:" + req.FromPass
+	} else if req.Data != nil {
+		if req.InputPath != "" {
+			return LockResult{}, errors.New("cannot read from both given data and a file")
+		}
+		if len(req.Data) == 0 {
+			return LockResult{}, errors.New("input is empty")
+		}
+		inputData = req.Data
+		inputSrc = InputSourceStdin
+	} else if isDirectoryInput(req.InputPath) {
+		if err := checkDirectoryLock(req); err != nil {
+			return LockResult{}, err
+		}
+		var info ArchiveInfo
+		inputData, inputStream, info, err = readDirectoryInput(req.InputPath)
+		if err != nil {
+			return LockResult{}, err
+		}
+		archive = &info
+		inputSrc = InputSourceDirectory
+		postProcess = []string{PostProcessUntar}
+	} else {
+		inputData, inputStream, inputSrc, err = readLockInput(req.InputPath, req.Stdin)
+		if err != nil {
+			return LockResult{}, err
+		}
+	}
+
+	if inputStream != nil {
+		defer inputStream.Close()
+		if err := checkStreamedLock(req); err != nil {
+			return LockResult{}, err
+		}
+	}
+
+	// Shredding a time-locked file could destroy the only transportable copy of sealed content
+	if req.Shred && req.InputPath != "" {
+		if inputStream != nil {
+			// The header is enough to recognize one
+			head := bufio.NewReaderSize(inputStream, streamChunkSize)
+			peeked, err := head.Peek(streamChunkSize)
+			if err != nil {
+				return LockResult{}, fmt.Errorf("cannot read file: %w", err)
+			}
+			if err := checkShredSafe(req.InputPath, peeked); err != nil {
+				return LockResult{}, err
+			}
+			inputStream = struct {
+				io.Reader
+				io.Closer
+			}{head, inputStream}
+		} else if err := checkShredSafe(req.InputPath, inputData); err != nil {
+			return LockResult{}, err
+		}
+	}
+
 	var warnings []string
 
-	// Create time authority (default authority via factory)
-	authority := timeauth.NewDefaultAuthority()
+	// Streamed input is sealed byte for byte, see checkStreamedLock
+	var normalization []string
+	if inputStream == nil {
+		// Password mode strips the trailing newline itself
+		inputData, normalization, err = NormalizeInput(inputData, req.Encoding, req.StripNewline && !req.PasswordMode)
+		if err != nil {
+			return LockResult{}, err
+		}
+
+		if req.PasswordMode {
+			password, passwordWarnings, err := NormalizePassword(inputData)
+			if err != nil {
+				return LockResult{}, err
+			}
+			if len(password) != len(inputData) {
+				normalization = append(normalization, NormalizedNewline)
+			}
+			inputData = password
+			warnings = append(warnings, passwordWarnings...)
+
+			// echo and here-strings leave the password in shell history
+			if inputSrc == InputSourceStdin {
+				warnings = append(warnings, "warning: a password piped with echo or typed on the command line may remain in your shell history")
+			}
+		}
+	}
+
+	// Streamed input is far above any minimum size
+	if !req.AllowSmall && inputStream == nil {
+		cfg, err := LoadConfig()
+		if err != nil {
+			return LockResult{}, err
+		}
+		if err := CheckInputContent(inputData, cfg.MinInputSize); err != nil {
+			return LockResult{}, err
+		}
+	}
 
 	// Create sealed item with encrypted payload
-	id, err := CreateSealedItem(unlockTime, inputSrc, req.InputPath, inputData, authority)
+	opts := ItemOptions{
+		RevealTo:        req.RevealTo,
+		Notify:          req.Notify,
+		OnUnlockExec:    onUnlockExec,
+		OnUnlockWebhook: req.OnUnlockWebhook,
+		RetainUnsealed:  retain,
+		UnsealTo:        unsealTo,
+		Immutable:       req.Immutable,
+		PostProcess:     postProcess,
+		Recipient:       recipient,
+		Passphrase:      req.Passphrase,
+		Normalization:   normalization,
+		VaultWrap:       req.VaultWrap,
+		Vault:           vault,
+		DirectTlock:     req.DirectTlock,
+		Tags:            tags,
+		Note:            req.Note,
+		Archive:         archive,
+	}
+	var id string
+	if inputStream != nil {
+		id, err = CreateStreamedItem(unlockTime, inputSrc, originalPath, inputStream, authority, opts)
+	} else {
+		id, err = CreateSealedItemWithOptions(unlockTime, inputSrc, originalPath, inputData, authority, opts)
+	}
 	if err != nil {
 		return LockResult{}, err
 	}
 
+	// Deleting the transit key or losing access to Vault makes the item unrecoverable
+	if req.VaultWrap != "" {
+		warnings = append(warnings, fmt.Sprintf("warning: unlocking also requires access to vault transit key %s; if it is deleted or access is lost, the item can never be unlocked", req.VaultWrap))
+	}
+
+	// Timestamp the ciphertext (best-effort: the item is sealed either way)
+	if req.Attest {
+		attestWarnings, err := AttestItem(id)
+		warnings = append(warnings, attestWarnings...)
+		if err != nil {
+			warnings = append(warnings, fmt.Sprintf("warning: attestation failed: %v; the item is sealed without one, run seal attest submit %s to retry", err, id))
+		}
+	}
+
+	// Protect item files against accidental modification (best-effort)
+	if req.Immutable {
+		_, itemDir, err := LoadItem(id)
+		if err != nil {
+			return LockResult{}, err
+		}
+		warnings = append(warnings, protectItemFiles(itemDir)...)
+	}
+
 	// Shred original file if requested (best-effort, after successful sealing)
 	if req.Shred && req.InputPath != "" {
-		warnings = append(warnings, ShredFile(req.InputPath)...)
+		warnings = append(warnings, ShredFileWithOptions(req.InputPath, shredOpts)...)
 	}
 
 	// Clear clipboard if requested (best-effort, after successful sealing)
@@ -9,9 +9,11 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
+	"seal/internal/clock"
 	"seal/internal/testutil"
 	"seal/internal/timeauth"
 )
@@ -77,7 +79,7 @@ func TestParseUnlockTime_InvalidFormat(t *testing.T) {
 			if err == nil {
 				t.Errorf("expected error for input %q, got nil", tc.input)
 			}
-			if err.Error() != "invalid time format, expected RFC3339" {
+			if err.Error() != "invalid time format, expected RFC3339 or +<duration> (e.g. +7d)" {
 				t.Errorf("unexpected error message: %v", err)
 			}
 		})
@@ -107,6 +109,22 @@ func TestParseUnlockTime_PastTimestamp(t *testing.T) {
 	}
 }
 
+func TestParseUnlockTime_LeapSecond(t *testing.T) {
+	defer clock.Set(func() time.Time { return time.Date(2016, 12, 31, 12, 0, 0, 0, time.UTC) })()
+
+	result, err := ParseUnlockTime("2016-12-31T23:59:60Z")
+	if err != nil {
+		t.Fatalf("expected leap second to be accepted, got: %v", err)
+	}
+	if want := time.Date(2017, 1, 1, 0, 0, 0, 0, time.UTC); !result.Equal(want) {
+		t.Errorf("expected %s, got %s", want, result)
+	}
+
+	if _, err := ParseUnlockTime("2016-12-31T23:59:61Z"); err == nil {
+		t.Error("expected second 61 to be rejected")
+	}
+}
+
 func TestParseUnlockTime_EdgeCaseCloseToNow(t *testing.T) {
 	// Time very close to now but still in the future (1 second ahead)
 	future := time.Now().UTC().Add(1 * time.Second)
@@ -238,7 +256,7 @@ func TestReadInput_StdinValid(t *testing.T) {
 	}
 }
 
-func TestReadInput_BothFileAndStdin(t *testing.T) {
+func TestReadInput_FileTakesPrecedenceOverStdin(t *testing.T) {
 	// Save original stdin
 	oldStdin := os.Stdin
 	defer func() { os.Stdin = oldStdin }()
@@ -251,22 +269,29 @@ func TestReadInput_BothFileAndStdin(t *testing.T) {
 		t.Fatalf("failed to create test file: %v", err)
 	}
 
-	// Create a pipe to simulate stdin with data
+	// A pipe on stdin, as under cron, is not treated as input when a path is given
 	r, w, err := os.Pipe()
 	if err != nil {
 		t.Fatalf("failed to create pipe: %v", err)
 	}
-
-	go func() {
-		w.Write([]byte("stdin content"))
-		w.Close()
-	}()
+	defer w.Close()
 
 	os.Stdin = r
 
-	_, _, err = ReadInput(testFile)
+	data, source, err := ReadInput(testFile)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if string(data) != "file content" || source != InputSourceFile {
+		t.Errorf("expected file content from file, got %q from %v", data, source)
+	}
+}
+
+func TestReadInputWithMode_StdinRequired_RejectsFile(t *testing.T) {
+	_, _, err := ReadInputWithMode(filepath.Join(t.TempDir(), "test.txt"), StdinRequired)
 	if err == nil {
-		t.Fatal("expected error when both file and stdin provided, got nil")
+		t.Fatal("expected error when both file and stdin requested, got nil")
 	}
 
 	if err.Error() != "cannot read from both file and stdin" {
@@ -274,6 +299,43 @@ func TestReadInput_BothFileAndStdin(t *testing.T) {
 	}
 }
 
+func TestReadInputWithMode_StdinNever_RequiresFile(t *testing.T) {
+	// Save original stdin
+	oldStdin := os.Stdin
+	defer func() { os.Stdin = oldStdin }()
+
+	// Stdin is never read, even if it would block forever
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("failed to create pipe: %v", err)
+	}
+	defer w.Close()
+	os.Stdin = r
+
+	_, _, err = ReadInputWithMode("", StdinNever)
+	if err == nil || !strings.Contains(err.Error(), "no input provided") {
+		t.Errorf("expected no input error, got: %v", err)
+	}
+}
+
+func TestReadInput_DevNullStdin_TreatedAsAbsent(t *testing.T) {
+	// Save original stdin
+	oldStdin := os.Stdin
+	defer func() { os.Stdin = oldStdin }()
+
+	devNull, err := os.Open(os.DevNull)
+	if err != nil {
+		t.Fatalf("cannot open %s: %v", os.DevNull, err)
+	}
+	defer devNull.Close()
+	os.Stdin = devNull
+
+	_, _, err = ReadInput("")
+	if err == nil || !strings.Contains(err.Error(), "no input provided") {
+		t.Errorf("expected no input error, got: %v", err)
+	}
+}
+
 func TestReadInput_NeitherFileNorStdin(t *testing.T) {
 	// Save original stdin
 	oldStdin := os.Stdin
@@ -338,6 +400,36 @@ func TestReadInput_EmptyStdin(t *testing.T) {
 	}
 }
 
+func TestCheckInputContent(t *testing.T) {
+	testCases := []struct {
+		name    string
+		data    string
+		minSize int
+		wantErr string
+	}{
+		{"whitespace only", " \n\t\r\n", 0, "only whitespace"},
+		{"single newline", "\n", 0, "only whitespace"},
+		{"below minimum", "abc\n", 8, "below the configured minimum of 8"},
+		{"at minimum", "abcdefg\n", 8, ""},
+		{"no minimum", "a", 0, ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := CheckInputContent([]byte(tc.data), tc.minSize)
+			if tc.wantErr == "" {
+				if err != nil {
+					t.Errorf("expected no error, got: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Errorf("expected error containing %q, got: %v", tc.wantErr, err)
+			}
+		})
+	}
+}
+
 func TestReadInput_ExceedsMaxSize_File(t *testing.T) {
 	// Create temporary test file that exceeds max size
 	tmpDir := t.TempDir()
@@ -807,3 +899,83 @@ func TestCreateSealedItem_DrandAuthority_UsesTlock(t *testing.T) {
 		t.Error("dek.bin should NOT exist (security fix)")
 	}
 }
+
+func TestCreateSealedItem_RecordsSizes(t *testing.T) {
+	_, cleanup := testutil.SetupTestEnv(t)
+	defer cleanup()
+
+	plaintext := []byte("twelve bytes")
+	id, err := CreateSealedItem(time.Now().UTC().Add(time.Hour), InputSourceStdin, "", plaintext, &timeauth.FakeAuthority{DefaultRound: 100})
+	if err != nil {
+		t.Fatalf("CreateSealedItem failed: %v", err)
+	}
+
+	item, itemDir, err := LoadItem(id)
+	if err != nil {
+		t.Fatalf("LoadItem failed: %v", err)
+	}
+
+	if item.PlaintextSize != int64(len(plaintext)) {
+		t.Errorf("plaintext_size: expected %d, got %d", len(plaintext), item.PlaintextSize)
+	}
+
+	info, err := os.Stat(filepath.Join(itemDir, "payload.bin"))
+	if err != nil {
+		t.Fatalf("cannot stat payload: %v", err)
+	}
+	if item.CiphertextSize != info.Size() {
+		t.Errorf("ciphertext_size: expected %d (payload.bin), got %d", info.Size(), item.CiphertextSize)
+	}
+
+	if output := FormatInspectOutput(item, nil, false); !strings.Contains(output, "plaintext_size: 12\n") {
+		t.Errorf("inspect should show sizes, got: %s", output)
+	}
+}
+
+// registerTestAuthority registers a fake authority once per test binary.
+var registerTestAuthority sync.Once
+
+// registerFakeAuthority makes a fake authority selectable as "registered-fake".
+// Its items are due at once.
+func registerFakeAuthority() {
+	registerTestAuthority.Do(func() {
+		fake := &timeauth.FakeAuthority{AuthorityName: "registered-fake", DefaultRound: 100, CurrentRound: 200}
+		timeauth.Register("registered-fake", timeauth.Registration{
+			New:    func(timeauth.Options) (timeauth.Authority, error) { return fake, nil },
+			ForRef: func(timeauth.KeyReference) timeauth.Authority { return fake },
+		})
+	})
+}
+
+func TestLock_RegisteredAuthority(t *testing.T) {
+	tmpDir, cleanup := testutil.SetupTestEnv(t)
+	defer cleanup()
+
+	// A registered authority is selectable by name, and unlocks the items it sealed
+	registerFakeAuthority()
+
+	input := filepath.Join(tmpDir, "input.txt")
+	if err := os.WriteFile(input, []byte("registered"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	unlockTime := time.Now().UTC().Add(time.Hour).Format(time.RFC3339)
+
+	result, err := Lock(LockRequest{InputPath: input, UnlockTime: unlockTime, Authority: "registered-fake"})
+	if err != nil {
+		t.Fatalf("Lock failed: %v", err)
+	}
+	item, itemDir, _ := LoadItem(result.ID)
+	if item.TimeAuthority != "registered-fake" {
+		t.Fatalf("expected registered-fake authority in metadata, got %s", item.TimeAuthority)
+	}
+
+	item, err = CheckAndTransitionUnlock(item, itemDir)
+	if err != nil || item.State != StateUnlocked {
+		t.Errorf("expected item to unlock through its registered authority: %s, %v", item.State, err)
+	}
+
+	_, err = Lock(LockRequest{InputPath: input, UnlockTime: unlockTime, Authority: "nts"})
+	if err == nil || !strings.Contains(err.Error(), "unknown time authority") {
+		t.Errorf("expected unknown authority error, got %v", err)
+	}
+}
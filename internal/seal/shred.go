@@ -0,0 +1,187 @@
+package seal
+
+import (
+	"crypto/rand"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// Shred patterns: what each overwrite pass writes.
+const (
+	ShredPatternZero        = "zero"        // zero bytes
+	ShredPatternRandom      = "random"      // random bytes
+	ShredPatternAlternating = "alternating" // 0x55 and 0xAA on alternate passes
+)
+
+// maxShredPasses bounds --shred-passes; more passes add time, not safety.
+const maxShredPasses = 35
+
+// shredBufferSize is the size of each overwrite write.
+const shredBufferSize = 64 * 1024
+
+// ShredOptions is a shredding policy. The zero value is one pass of zeroes.
+// The policy in config.json applies to every shred unless lock overrides it.
+type ShredOptions struct {
+	Passes  int    `json:"passes,omitempty"`  // overwrite passes (default 1)
+	Pattern string `json:"pattern,omitempty"` // ShredPatternZero (default), ShredPatternRandom or ShredPatternAlternating
+}
+
+// Validate checks that the options name a known pattern and a sensible pass count.
+func (o ShredOptions) Validate() error {
+	if o.Passes < 0 || o.Passes > maxShredPasses {
+		return fmt.Errorf("invalid shred passes %d, expected 1 to %d", o.Passes, maxShredPasses)
+	}
+	switch o.Pattern {
+	case "", ShredPatternZero, ShredPatternRandom, ShredPatternAlternating:
+		return nil
+	default:
+		return fmt.Errorf("invalid shred pattern %q, expected zero, random or alternating", o.Pattern)
+	}
+}
+
+// withDefaults fills unset fields from base, then from the built-in default.
+func (o ShredOptions) withDefaults(base ShredOptions) ShredOptions {
+	if o.Passes == 0 {
+		o.Passes = base.Passes
+	}
+	if o.Pattern == "" {
+		o.Pattern = base.Pattern
+	}
+	if o.Passes == 0 {
+		o.Passes = 1
+	}
+	if o.Pattern == "" {
+		o.Pattern = ShredPatternZero
+	}
+	return o
+}
+
+// configuredShredOptions returns the shredding policy from config.json.
+// An unreadable config falls back to the default, with a warning.
+func configuredShredOptions() (ShredOptions, []string) {
+	cfg, err := LoadConfig()
+	if err != nil {
+		return ShredOptions{}.withDefaults(ShredOptions{}), []string{fmt.Sprintf("warning: using the default shred policy: %v", err)}
+	}
+	if err := cfg.Shred.Validate(); err != nil {
+		return ShredOptions{}.withDefaults(ShredOptions{}), []string{fmt.Sprintf("warning: using the default shred policy: config: %v", err)}
+	}
+	return cfg.Shred.withDefaults(ShredOptions{}), nil
+}
+
+// ShredFile performs best-effort file shredding with the configured policy.
+// Returns a slice of warnings encountered (does not fail on errors).
+func ShredFile(path string) []string {
+	opts, warnings := configuredShredOptions()
+	return append(warnings, shredFile(path, opts)...)
+}
+
+// ShredFileWithOptions is ShredFile with options overriding the configured policy.
+func ShredFileWithOptions(path string, opts ShredOptions) []string {
+	base, warnings := configuredShredOptions()
+	return append(warnings, shredFile(path, opts.withDefaults(base))...)
+}
+
+// shredFile overwrites a regular file's data with each pass of opts, syncing
+// after every pass, then removes it and syncs its directory so the removal is durable.
+// Holes in sparse files are left as holes: writing them would only allocate new blocks.
+func shredFile(path string, opts ShredOptions) []string {
+	var warnings []string
+
+	// Never shred through a symlink: that would overwrite the target but remove only the link
+	info, err := os.Lstat(path)
+	if err != nil {
+		warnings = append(warnings, fmt.Sprintf("warning: failed to open file for shredding: %v", err))
+		return warnings
+	}
+	if info.Mode()&os.ModeSymlink != 0 {
+		warnings = append(warnings, fmt.Sprintf("warning: refusing to shred symlink %s", path))
+		return warnings
+	}
+	if !info.Mode().IsRegular() {
+		warnings = append(warnings, fmt.Sprintf("warning: refusing to shred %s: not a regular file", path))
+		return warnings
+	}
+
+	file, err := os.OpenFile(path, os.O_WRONLY, 0)
+	if err != nil {
+		warnings = append(warnings, fmt.Sprintf("warning: failed to open file for shredding: %v", err))
+		return warnings
+	}
+	defer file.Close()
+
+	// The path may have been replaced, e.g. by a symlink, since it was checked
+	opened, err := file.Stat()
+	if err != nil {
+		warnings = append(warnings, fmt.Sprintf("warning: failed to stat file for shredding: %v", err))
+		return warnings
+	}
+	if !os.SameFile(info, opened) {
+		warnings = append(warnings, fmt.Sprintf("warning: refusing to shred %s: file changed while shredding", path))
+		return warnings
+	}
+
+	extents := dataExtents(file, opened.Size())
+	buf := make([]byte, shredBufferSize)
+	for pass := 0; pass < opts.Passes; pass++ {
+		if err := overwriteExtents(file, extents, buf, opts.Pattern, pass); err != nil {
+			warnings = append(warnings, fmt.Sprintf("warning: failed to overwrite file during shredding: %v", err))
+			return warnings
+		}
+		if err := file.Sync(); err != nil {
+			warnings = append(warnings, fmt.Sprintf("warning: failed to sync file during shredding: %v", err))
+		}
+	}
+
+	file.Close()
+
+	// Remove file
+	if err := os.Remove(path); err != nil {
+		warnings = append(warnings, fmt.Sprintf("warning: failed to remove file after shredding: %v", err))
+		return warnings
+	}
+	if err := syncDir(filepath.Dir(path)); err != nil {
+		warnings = append(warnings, fmt.Sprintf("warning: failed to sync directory after shredding: %v", err))
+	}
+
+	return warnings
+}
+
+// extent is a byte range [start, end) of a file.
+type extent struct {
+	start, end int64
+}
+
+// overwriteExtents writes one pass of pattern over every extent.
+func overwriteExtents(file *os.File, extents []extent, buf []byte, pattern string, pass int) error {
+	switch pattern {
+	case ShredPatternAlternating:
+		fill := byte(0x55)
+		if pass%2 == 1 {
+			fill = 0xAA
+		}
+		for i := range buf {
+			buf[i] = fill
+		}
+	case ShredPatternZero:
+		clear(buf)
+	}
+
+	for _, e := range extents {
+		for offset := e.start; offset < e.end; {
+			chunk := buf[:min(int64(len(buf)), e.end-offset)]
+			if pattern == ShredPatternRandom {
+				if _, err := rand.Read(chunk); err != nil {
+					return err
+				}
+			}
+			n, err := file.WriteAt(chunk, offset)
+			if err != nil {
+				return err
+			}
+			offset += int64(n)
+		}
+	}
+	return nil
+}
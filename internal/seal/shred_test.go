@@ -0,0 +1,146 @@
+package seal
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"seal/internal/testutil"
+)
+
+func TestOverwriteExtents_Patterns(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "file")
+	if err := os.WriteFile(path, bytes.Repeat([]byte("s"), 100), 0600); err != nil {
+		t.Fatal(err)
+	}
+	file, err := os.OpenFile(path, os.O_RDWR, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	extents := []extent{{10, 20}, {50, 100}}
+	buf := make([]byte, 8)
+	for _, tc := range []struct {
+		pattern string
+		pass    int
+		want    byte
+	}{
+		{ShredPatternAlternating, 0, 0x55},
+		{ShredPatternAlternating, 1, 0xAA},
+		{ShredPatternZero, 0, 0},
+	} {
+		if err := overwriteExtents(file, extents, buf, tc.pattern, tc.pass); err != nil {
+			t.Fatal(err)
+		}
+		data, _ := os.ReadFile(path)
+		if data[9] != 's' || data[20] != 's' || data[49] != 's' {
+			t.Errorf("%s: bytes outside the extents were overwritten", tc.pattern)
+		}
+		if !bytes.Equal(data[10:20], bytes.Repeat([]byte{tc.want}, 10)) || !bytes.Equal(data[50:], bytes.Repeat([]byte{tc.want}, 50)) {
+			t.Errorf("%s pass %d: unexpected content %x", tc.pattern, tc.pass, data)
+		}
+	}
+
+	if err := overwriteExtents(file, extents, buf, ShredPatternRandom, 0); err != nil {
+		t.Fatal(err)
+	}
+	data, _ := os.ReadFile(path)
+	if bytes.Equal(data[50:], make([]byte, 50)) {
+		t.Error("random pattern left zeroes")
+	}
+}
+
+func TestShredFileWithOptions(t *testing.T) {
+	_, cleanup := testutil.SetupTestEnv(t)
+	defer cleanup()
+	dir := t.TempDir()
+
+	path := filepath.Join(dir, "secret")
+	if err := os.WriteFile(path, bytes.Repeat([]byte("secret"), 20000), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if warnings := ShredFileWithOptions(path, ShredOptions{Passes: 3, Pattern: ShredPatternRandom}); len(warnings) != 0 {
+		t.Errorf("expected no warnings, got %v", warnings)
+	}
+	if _, err := os.Lstat(path); !os.IsNotExist(err) {
+		t.Error("file should be removed after shredding")
+	}
+
+	// Anything but a regular file is left alone
+	sub := filepath.Join(dir, "sub")
+	if err := os.Mkdir(sub, 0700); err != nil {
+		t.Fatal(err)
+	}
+	if warnings := ShredFile(sub); len(warnings) != 1 || !strings.Contains(warnings[0], "not a regular file") {
+		t.Errorf("expected a directory to be refused, got %v", warnings)
+	}
+	if _, err := os.Stat(sub); err != nil {
+		t.Errorf("directory should be left in place: %v", err)
+	}
+}
+
+func TestSetConfigValue_Shred(t *testing.T) {
+	_, cleanup := testutil.SetupTestEnv(t)
+	defer cleanup()
+
+	for key, value := range map[string]string{"shred-passes": "3", "shred-pattern": "alternating"} {
+		if _, err := SetConfigValue(key, value); err != nil {
+			t.Fatalf("SetConfigValue(%s) failed: %v", key, err)
+		}
+	}
+	opts, warnings := configuredShredOptions()
+	if opts != (ShredOptions{Passes: 3, Pattern: ShredPatternAlternating}) || len(warnings) != 0 {
+		t.Errorf("unexpected policy %+v, %v", opts, warnings)
+	}
+	if got := (ShredOptions{Pattern: ShredPatternRandom}).withDefaults(opts); got != (ShredOptions{Passes: 3, Pattern: ShredPatternRandom}) {
+		t.Errorf("lock options should override the configured pattern only, got %+v", got)
+	}
+
+	for key, value := range map[string]string{"shred-passes": "0", "shred-pattern": "gutmann"} {
+		if _, err := SetConfigValue(key, value); err == nil {
+			t.Errorf("expected %s %s to be refused", key, value)
+		}
+	}
+	if _, err := SetConfigValue("shred-passes", "36"); err == nil {
+		t.Error("expected too many passes to be refused")
+	}
+}
+
+func TestLock_ShredOptions(t *testing.T) {
+	tmpDir, cleanup := testutil.SetupTestEnv(t)
+	defer cleanup()
+	registerFakeAuthority()
+
+	input := filepath.Join(tmpDir, "input.txt")
+	if err := os.WriteFile(input, []byte("shred me twice"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	unlockTime := time.Now().UTC().Add(time.Hour).Format(time.RFC3339)
+
+	for _, req := range []LockRequest{
+		{InputPath: input, UnlockTime: unlockTime, Authority: "registered-fake", ShredPasses: 2},
+		{InputPath: input, UnlockTime: unlockTime, Authority: "registered-fake", Shred: true, ShredPattern: "dod"},
+	} {
+		if _, err := Lock(req); err == nil {
+			t.Errorf("expected %+v to be refused", req)
+		}
+	}
+	if _, err := os.Stat(input); err != nil {
+		t.Fatalf("input should be untouched after a refused lock: %v", err)
+	}
+
+	result, err := Lock(LockRequest{InputPath: input, UnlockTime: unlockTime, Authority: "registered-fake", Shred: true, ShredPasses: 2, ShredPattern: ShredPatternRandom})
+	if err != nil {
+		t.Fatalf("Lock failed: %v", err)
+	}
+	if len(result.Warnings) != 0 {
+		t.Errorf("unexpected warnings: %v", result.Warnings)
+	}
+	if _, err := os.Stat(input); !os.IsNotExist(err) {
+		t.Error("input should be shredded")
+	}
+}
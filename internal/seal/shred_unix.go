@@ -0,0 +1,40 @@
+//go:build darwin || freebsd || linux
+
+package seal
+
+import (
+	"errors"
+	"io"
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+// dataExtents returns the ranges of a file that hold data, skipping holes.
+// If the filesystem cannot report holes, the whole file is one extent.
+func dataExtents(file *os.File, size int64) []extent {
+	whole := []extent{{0, size}}
+
+	var extents []extent
+	for offset := int64(0); offset < size; {
+		start, err := file.Seek(offset, unix.SEEK_DATA)
+		if errors.Is(err, unix.ENXIO) {
+			break // only a hole remains
+		}
+		if err != nil {
+			return whole
+		}
+		end, err := file.Seek(start, unix.SEEK_HOLE)
+		if err != nil {
+			return whole
+		}
+		end = min(end, size)
+		if end <= start {
+			return whole
+		}
+		extents = append(extents, extent{start, end})
+		offset = end
+	}
+	file.Seek(0, io.SeekStart)
+	return extents
+}
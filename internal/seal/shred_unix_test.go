@@ -0,0 +1,43 @@
+//go:build darwin || freebsd || linux
+
+package seal
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDataExtents_SkipsHoles(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sparse")
+	file, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	// 1 MiB of data, an 8 MiB hole, then 1 MiB of data
+	const mib = 1 << 20
+	data := make([]byte, mib)
+	for i := range data {
+		data[i] = 's'
+	}
+	if _, err := file.WriteAt(data, 0); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := file.WriteAt(data, 9*mib); err != nil {
+		t.Fatal(err)
+	}
+
+	extents := dataExtents(file, 10*mib)
+	if len(extents) == 1 && extents[0] == (extent{0, 10 * mib}) {
+		t.Skip("filesystem does not report holes")
+	}
+	var covered int64
+	for _, e := range extents {
+		covered += e.end - e.start
+	}
+	if covered < 2*mib || covered >= 10*mib {
+		t.Errorf("expected only the data to be covered, got %v", extents)
+	}
+}
@@ -0,0 +1,37 @@
+package seal
+
+import "fmt"
+
+// checkShredSafe refuses to shred a time-locked (tlock) ciphertext, binary or armored.
+// Such a file is a transportable sealed copy in its own right: shredding it on the
+// assumption that the store holds the content elsewhere has destroyed the only copy
+// that could travel. Any other content is safe to shred and returns nil.
+func checkShredSafe(path string, data []byte) error {
+	binary, err := dearmorTlock(data)
+	if err != nil {
+		return fmt.Errorf("refusing to shred %s: it looks like an armored time-locked file (%v); lock it without --shred", path, err)
+	}
+
+	round, _, err := tlockHeader(binary)
+	if err != nil {
+		return nil
+	}
+
+	// The store may track the same ciphertext from an earlier import
+	checksum := payloadChecksum(binary)
+	var importedAs string
+	err = WalkSealedItems(func(item SealedItem, itemDir string) error {
+		if importedAs == "" && item.PayloadSHA256 == checksum {
+			importedAs = item.ID
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if importedAs != "" {
+		return fmt.Errorf("refusing to shred %s: it is a time-locked file for round %d, also imported as item %s, and may be its only copy outside this store; lock it without --shred", path, round, importedAs)
+	}
+	return fmt.Errorf("refusing to shred %s: it is a time-locked file for round %d and may be the only copy of that sealed content; use seal import to track it, or lock it without --shred", path, round)
+}
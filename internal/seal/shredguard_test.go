@@ -0,0 +1,76 @@
+package seal
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"seal/internal/testutil"
+)
+
+func TestCheckShredSafe(t *testing.T) {
+	_, cleanup := testutil.SetupTestEnv(t)
+	defer cleanup()
+
+	server, authority := startImportChain(t, 3*time.Second)
+	tle := tleFile(t, server, []byte("hello"), server.LatestRound()+100)
+
+	if err := checkShredSafe("notes.txt", []byte("plain text")); err != nil {
+		t.Errorf("plain content should be safe to shred, got: %v", err)
+	}
+
+	for name, data := range map[string][]byte{"binary": tle, "armored": armorTle(t, tle)} {
+		err := checkShredSafe("letter.tle", data)
+		if err == nil || !strings.Contains(err.Error(), "seal import") {
+			t.Errorf("%s: expected refusal suggesting import, got: %v", name, err)
+		}
+	}
+
+	// Once imported, the refusal names the tracking item
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "letter.tle"), tle, 0600); err != nil {
+		t.Fatal(err)
+	}
+	results, err := ImportDir(dir, authority, ImportOptions{})
+	if err != nil || len(results) != 1 || results[0].Status != ImportStatusImported {
+		t.Fatalf("import failed: %v %+v", err, results)
+	}
+
+	err = checkShredSafe("letter.tle", armorTle(t, tle))
+	if err == nil || !strings.Contains(err.Error(), results[0].ID) {
+		t.Errorf("expected refusal naming item %s, got: %v", results[0].ID, err)
+	}
+}
+
+func TestLock_RefusesToShredTimeLockedFile(t *testing.T) {
+	_, cleanup := testutil.SetupTestEnv(t)
+	defer cleanup()
+
+	server, _ := startImportChain(t, 3*time.Second)
+	path := filepath.Join(t.TempDir(), "letter.tle")
+	if err := os.WriteFile(path, armorTle(t, tleFile(t, server, []byte("hello"), server.LatestRound()+100)), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := Lock(LockRequest{
+		InputPath:  path,
+		UnlockTime: time.Now().UTC().Add(time.Hour).Format(time.RFC3339),
+		Shred:      true,
+	})
+	if err == nil || !strings.Contains(err.Error(), "refusing to shred") {
+		t.Fatalf("expected refusal, got: %v", err)
+	}
+
+	if _, err := os.Stat(path); err != nil {
+		t.Errorf("time-locked file must be left in place: %v", err)
+	}
+	items, err := ListSealedItems(ListOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(items) != 0 {
+		t.Errorf("nothing should be sealed when the shred is refused, got %d items", len(items))
+	}
+}
@@ -0,0 +1,75 @@
+package seal
+
+import (
+	"fmt"
+	"time"
+
+	"seal/internal/timeauth"
+)
+
+// SimulateResult reports whether an item would be unlockable at a hypothetical time.
+type SimulateResult struct {
+	ID          string
+	At          time.Time
+	TargetRound uint64
+	RoundTime   time.Time // wall-clock time at which the target round is reached
+	Unlockable  bool
+}
+
+// Simulate reports whether an item would be unlockable at the given time.
+// Uses only round math - no beacon is fetched and no state is changed.
+func Simulate(id string, at time.Time) (SimulateResult, error) {
+	item, _, err := LoadItem(id)
+	if err != nil {
+		return SimulateResult{}, err
+	}
+
+	authority := authorityForItem(item)
+	if authority == nil {
+		return SimulateResult{}, fmt.Errorf("item %s: authority %q can never unlock", item.ID, item.TimeAuthority)
+	}
+
+	return SimulateWithAuthority(item, at, authority)
+}
+
+// SimulateWithAuthority performs the round math for Simulate using the given authority.
+func SimulateWithAuthority(item SealedItem, at time.Time, authority timeauth.Authority) (SimulateResult, error) {
+	if !hasTimeLock(item) {
+		return SimulateResult{}, fmt.Errorf("item %s: no time-locked key, item can never unlock", item.ID)
+	}
+
+	targetRound, err := extractTargetRound(item.KeyRef)
+	if err != nil {
+		return SimulateResult{}, fmt.Errorf("item %s: %w", item.ID, err)
+	}
+
+	roundTime, err := authority.EarliestUnlockTime(timeauth.KeyReference(item.KeyRef))
+	if err != nil {
+		return SimulateResult{}, fmt.Errorf("failed to calculate round time: %w", err)
+	}
+
+	at = at.UTC()
+
+	return SimulateResult{
+		ID:          item.ID,
+		At:          at,
+		TargetRound: targetRound,
+		RoundTime:   roundTime,
+		Unlockable:  !at.Before(roundTime),
+	}, nil
+}
+
+// FormatSimulateOutput formats a simulation result for display.
+func FormatSimulateOutput(result SimulateResult) string {
+	unlockable := "no"
+	if result.Unlockable {
+		unlockable = "yes"
+	}
+
+	return fmt.Sprintf("id: %s\nat: %s\ntarget_round: %d\nround_time: %s\nunlockable: %s\n",
+		result.ID,
+		result.At.Format(time.RFC3339),
+		result.TargetRound,
+		result.RoundTime.Format(time.RFC3339),
+		unlockable)
+}
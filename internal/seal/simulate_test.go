@@ -0,0 +1,121 @@
+package seal
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"seal/internal/testutil"
+	"seal/internal/timeauth"
+)
+
+func TestSimulateWithAuthority_RoundBoundary(t *testing.T) {
+	genesis := time.Date(2023, 3, 1, 13, 0, 0, 0, time.UTC)
+	authority := &timeauth.FakeAuthority{
+		GenesisTime: genesis,
+		Period:      3 * time.Second,
+	}
+
+	item := SealedItem{
+		ID:          "test-id",
+		State:       StateSealed,
+		KeyRef:      `{"network":"fake","target_round":1000}`,
+		DEKTlockB64: "FAKE_TLOCK:AAAA",
+	}
+
+	// Round 1 is published at genesis
+	roundTime := genesis.Add(999 * 3 * time.Second)
+
+	testCases := []struct {
+		name       string
+		at         time.Time
+		unlockable bool
+	}{
+		{"one second before round", roundTime.Add(-time.Second), false},
+		{"exactly at round", roundTime, true},
+		{"long after round", roundTime.Add(24 * time.Hour), true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := SimulateWithAuthority(item, tc.at, authority)
+			if err != nil {
+				t.Fatalf("SimulateWithAuthority failed: %v", err)
+			}
+
+			if result.TargetRound != 1000 {
+				t.Errorf("expected target round 1000, got %d", result.TargetRound)
+			}
+
+			if !result.RoundTime.Equal(roundTime) {
+				t.Errorf("expected round time %v, got %v", roundTime, result.RoundTime)
+			}
+
+			if result.Unlockable != tc.unlockable {
+				t.Errorf("expected unlockable=%v, got %v", tc.unlockable, result.Unlockable)
+			}
+		})
+	}
+}
+
+func TestSimulateWithAuthority_NoTimeLockedKey(t *testing.T) {
+	item := SealedItem{
+		ID:     "test-id",
+		State:  StateSealed,
+		KeyRef: "placeholder-key-ref",
+	}
+
+	_, err := SimulateWithAuthority(item, time.Now(), &timeauth.FakeAuthority{})
+	if err == nil {
+		t.Fatal("expected error for item without time-locked key")
+	}
+}
+
+func TestSimulate_DoesNotMutateItem(t *testing.T) {
+	_, cleanup := testutil.SetupTestEnv(t)
+	defer cleanup()
+
+	// Placeholder items can be created offline; Simulate must refuse them
+	id, err := CreateSealedItem(time.Now().UTC().Add(time.Hour), InputSourceStdin, "", []byte("data"), &timeauth.PlaceholderAuthority{})
+	if err != nil {
+		t.Fatalf("CreateSealedItem failed: %v", err)
+	}
+
+	_, err = Simulate(id, time.Now().UTC().Add(48*time.Hour))
+	if err == nil {
+		t.Fatal("expected error for placeholder item")
+	}
+	if !strings.Contains(err.Error(), "can never unlock") {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	item, _, err := LoadItem(id)
+	if err != nil {
+		t.Fatalf("LoadItem failed: %v", err)
+	}
+	if item.State != StateSealed {
+		t.Errorf("state should remain sealed, got %s", item.State)
+	}
+}
+
+func TestLoadItem_RejectsInvalidID(t *testing.T) {
+	_, cleanup := testutil.SetupTestEnv(t)
+	defer cleanup()
+
+	testCases := []string{"", "../etc", "not-a-uuid"}
+	for _, id := range testCases {
+		if _, _, err := LoadItem(id); err == nil {
+			t.Errorf("expected error for id %q", id)
+		}
+	}
+}
+
+func TestLoadItem_NotFound(t *testing.T) {
+	_, cleanup := testutil.SetupTestEnv(t)
+	defer cleanup()
+
+	_, _, err := LoadItem("a1b2c3d4-5e6f-7890-abcd-ef1234567890")
+	if err == nil || !strings.Contains(err.Error(), "item not found") {
+		t.Errorf("expected item not found error, got: %v", err)
+	}
+}
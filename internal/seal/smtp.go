@@ -0,0 +1,138 @@
+package seal
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"os"
+	"strconv"
+	"time"
+
+	"seal/internal/clock"
+)
+
+// SMTP content modes control how much of the unlocked item is sent.
+const (
+	SMTPContentNotify     = "notify"     // notification with instructions only (default)
+	SMTPContentPlaintext  = "plaintext"  // unlocked content in the message body
+	SMTPContentAttachment = "attachment" // unlocked content as an attachment
+)
+
+// SMTPConfig configures email delivery of reveal content.
+// The password may be supplied via SEAL_SMTP_PASSWORD instead of the config file.
+type SMTPConfig struct {
+	Host     string `json:"host,omitempty"`
+	Port     int    `json:"port,omitempty"`
+	Username string `json:"username,omitempty"`
+	Password string `json:"password,omitempty"`
+	From     string `json:"from,omitempty"`
+	Content  string `json:"content,omitempty"`
+}
+
+// sendMail is the SMTP transport. Replaced in tests.
+var sendMail = smtp.SendMail
+
+// deliverMail emails an unlocked item to the given address.
+func deliverMail(item SealedItem, itemDir string, to string, cfg SMTPConfig) error {
+	if cfg.Host == "" || cfg.From == "" {
+		return errors.New("smtp is not configured (smtp.host and smtp.from are required)")
+	}
+
+	port := cfg.Port
+	if port == 0 {
+		port = 587
+	}
+
+	password := cfg.Password
+	if envPassword := os.Getenv("SEAL_SMTP_PASSWORD"); envPassword != "" {
+		password = envPassword
+	}
+
+	var auth smtp.Auth
+	if cfg.Username != "" {
+		auth = smtp.PlainAuth("", cfg.Username, password, cfg.Host)
+	}
+
+	unsealedPath := UnsealedPath(item, itemDir)
+
+	msg, err := buildRevealMessage(item, unsealedPath, cfg.From, to, cfg.Content)
+	if err != nil {
+		return err
+	}
+
+	addr := net.JoinHostPort(cfg.Host, strconv.Itoa(port))
+	if err := sendMail(addr, auth, cfg.From, []string{to}, msg); err != nil {
+		return fmt.Errorf("smtp delivery failed: %w", err)
+	}
+
+	return nil
+}
+
+// buildRevealMessage builds the RFC 5322 message for a reveal.
+func buildRevealMessage(item SealedItem, unsealedPath, from, to, content string) ([]byte, error) {
+	if content == "" {
+		content = SMTPContentNotify
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "From: %s\r\n", from)
+	fmt.Fprintf(&buf, "To: %s\r\n", to)
+	fmt.Fprintf(&buf, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", "seal item "+item.ID+" unlocked"))
+	fmt.Fprintf(&buf, "Date: %s\r\n", clock.UTC().Format(time.RFC1123Z))
+	buf.WriteString("MIME-Version: 1.0\r\n")
+
+	summary := fmt.Sprintf("Seal item %s reached its unlock time (%s).\r\n",
+		item.ID, item.UnlockTime.Format(time.RFC3339))
+
+	switch content {
+	case SMTPContentNotify:
+		buf.WriteString("Content-Type: text/plain; charset=utf-8\r\n\r\n")
+		buf.WriteString(summary)
+		fmt.Fprintf(&buf, "The unlocked content is stored at: %s\r\n", unsealedPath)
+
+	case SMTPContentPlaintext:
+		data, err := os.ReadFile(unsealedPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read unsealed data: %w", err)
+		}
+		buf.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+		buf.WriteString("Content-Transfer-Encoding: base64\r\n\r\n")
+		writeBase64Lines(&buf, data)
+
+	case SMTPContentAttachment:
+		data, err := os.ReadFile(unsealedPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read unsealed data: %w", err)
+		}
+		boundary := "seal-" + item.ID
+		fmt.Fprintf(&buf, "Content-Type: multipart/mixed; boundary=%q\r\n\r\n", boundary)
+		fmt.Fprintf(&buf, "--%s\r\n", boundary)
+		buf.WriteString("Content-Type: text/plain; charset=utf-8\r\n\r\n")
+		buf.WriteString(summary)
+		fmt.Fprintf(&buf, "\r\n--%s\r\n", boundary)
+		buf.WriteString("Content-Type: application/octet-stream\r\n")
+		buf.WriteString("Content-Transfer-Encoding: base64\r\n")
+		fmt.Fprintf(&buf, "Content-Disposition: attachment; filename=%q\r\n\r\n", item.ID+".unsealed")
+		writeBase64Lines(&buf, data)
+		fmt.Fprintf(&buf, "--%s--\r\n", boundary)
+
+	default:
+		return nil, fmt.Errorf("unknown smtp content mode: %s", content)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// writeBase64Lines writes base64 data wrapped at 76 characters per RFC 2045.
+func writeBase64Lines(buf *bytes.Buffer, data []byte) {
+	encoded := base64.StdEncoding.EncodeToString(data)
+	for len(encoded) > 76 {
+		buf.WriteString(encoded[:76] + "\r\n")
+		encoded = encoded[76:]
+	}
+	buf.WriteString(encoded + "\r\n")
+}
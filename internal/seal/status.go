@@ -1,22 +1,65 @@
 package seal
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"path/filepath"
+	"time"
+
+	"seal/internal/clock"
+	"seal/internal/timeauth"
 )
 
 // StatusResult contains the results of a status check.
 type StatusResult struct {
-	Items                  []SealedItem
-	MaterializationFailed  bool
-	FirstError             error
-	ValidationFailed       bool
-	ValidationErrors       []error
+	Items                 []SealedItem
+	MaterializationFailed bool
+	FirstError            error
+	ValidationFailed      bool
+	ValidationErrors      []error
+	Countdowns            map[string]Countdown // keyed by item ID, sealed items only
+	Warnings              []string             // non-fatal problems, e.g. failed reveal delivery
+	Summary               MaterializationSummary
+	Unlocked              []string // IDs of items unlocked during this run
+	AuthorityUnreachable  bool     // a time authority could not be reached, by its probe or while unlocking
+	UnlockFailed          bool     // an unlock attempt failed for a reason other than the network
+}
+
+// MaterializationSummary counts what the passive unlock machinery did in one run.
+type MaterializationSummary struct {
+	Checked  int `json:"checked"`  // sealed items considered for unlock
+	NotDue   int `json:"not_due"`  // still sealed: round not reached, backing off, or authority unreachable
+	Unlocked int `json:"unlocked"` // unlocked during this run
+	Failed   int `json:"failed"`   // unlock attempted and failed (retried on a later run)
+}
+
+// FormatMaterializationSummary formats a summary as a single line.
+func FormatMaterializationSummary(summary MaterializationSummary) string {
+	return fmt.Sprintf("materialization: %d checked, %d not due, %d unlocked, %d failed\n",
+		summary.Checked, summary.NotDue, summary.Unlocked, summary.Failed)
+}
+
+// FormatMaterializationSummaryJSON formats a summary as a single JSON line.
+func FormatMaterializationSummaryJSON(summary MaterializationSummary) (string, error) {
+	data, err := json.Marshal(struct {
+		Materialization MaterializationSummary `json:"materialization"`
+	}{summary})
+	if err != nil {
+		return "", fmt.Errorf("cannot marshal summary: %w", err)
+	}
+	return string(data) + "\n", nil
 }
 
 // GetStatus retrieves all sealed items and attempts materialization.
 func GetStatus() (StatusResult, error) {
-	items, err := ListSealedItems()
+	return getStatus(newStatusRun)
+}
+
+// getStatus is GetStatus with the run created by newRun, once there are items to check.
+func getStatus(newRun func() *statusRun) (StatusResult, error) {
+	items, err := ListSealedItems(ListOptions{})
 	if err != nil {
 		return StatusResult{}, err
 	}
@@ -30,63 +73,456 @@ func GetStatus() (StatusResult, error) {
 		return StatusResult{}, err
 	}
 
-	// Track materialization and validation errors
-	var materializationFailed bool
-	var firstError error
-	var validationFailed bool
-	var validationErrors []error
+	run := newRun()
+	countdowns := make(map[string]Countdown)
 
 	// Validate and materialize each item
 	for i := range items {
 		itemDir := filepath.Join(baseDir, items[i].ID)
-		
-		// Validate item state invariants after loading
-		if err := ValidateItemState(items[i], itemDir); err != nil {
-			validationFailed = true
-			validationErrors = append(validationErrors, err)
-			// Continue processing other items
-			continue
+
+		var countdown *Countdown
+		items[i], countdown = run.check(items[i], itemDir)
+		if countdown != nil {
+			countdowns[items[i].ID] = *countdown
 		}
-		
-		// Attempt materialization (idempotent - no-op if already unlocked)
-		// CheckAndTransitionUnlock handles metadata persistence via saveMetadata
-		updatedItem, err := CheckAndTransitionUnlock(items[i], itemDir)
-		if err != nil {
-			// Track error but continue processing other items
-			if !materializationFailed {
-				firstError = err
-				materializationFailed = true
-			}
-			// Item remains in its current state (sealed)
+	}
+
+	result := run.result()
+	result.Items = items
+	result.Countdowns = countdowns
+	return result, nil
+}
+
+// StreamStatus performs the same checks as GetStatus but hands each item to emit
+// as soon as it has been processed, without buffering the listing.
+// Items are visited in directory order, not sorted by creation time.
+// countdown is nil unless the item is still sealed.
+// The returned result carries errors and warnings but no items or countdowns.
+// An error from emit stops the walk and is returned.
+func StreamStatus(emit func(item SealedItem, countdown *Countdown) error) (StatusResult, error) {
+	var run *statusRun
+
+	err := WalkSealedItems(func(item SealedItem, itemDir string) error {
+		// Config is loaded lazily so an empty store behaves like GetStatus
+		if run == nil {
+			run = newStatusRun()
+		}
+		return emit(run.check(item, itemDir))
+	})
+	if err != nil {
+		return StatusResult{}, err
+	}
+
+	if run == nil {
+		return StatusResult{}, nil
+	}
+	return run.result(), nil
+}
+
+// statusRun accumulates errors and warnings across the items of one status check.
+type statusRun struct {
+	cfg                   Config
+	materializationFailed bool
+	firstError            error
+	validationFailed      bool
+	validationErrors      []error
+	warnings              []string
+	summary               MaterializationSummary
+	unlocked              []string
+	unlockFailed          bool // an unlock attempt failed for a reason other than the network
+	unlockUnreachable     bool // an unlock attempt failed to reach its time authority
+
+	// Each time authority is probed once per run. Sealed items of an unreachable one
+	// are reported with local clock countdowns instead of failing one by one; items
+	// of other authorities are checked as usual.
+	authorityFor func(item SealedItem) timeauth.Authority
+	authorities  map[string]timeauth.Authority // by timeauth.InstanceID, reused across items
+	offline      map[string]*offlineAuthority  // probe failures, by the same key
+	offlineKeys  []string                      // keys of offline, in the order they failed
+}
+
+// offlineAuthority is a time authority that failed its probe in a status run.
+type offlineAuthority struct {
+	err     error
+	skipped int // sealed items not checked for unlock
+}
+
+func newStatusRun() *statusRun {
+	run := &statusRun{
+		authorityFor: authorityForItem,
+		authorities:  make(map[string]timeauth.Authority),
+		offline:      make(map[string]*offlineAuthority),
+	}
+
+	// A broken config must not prevent status from reporting or materializing
+	cfg, err := LoadConfig()
+	if err != nil {
+		run.warnings = append(run.warnings, fmt.Sprintf("warning: %v", err))
+	}
+	run.cfg = cfg
+
+	return run
+}
+
+// check validates and materializes a single item and runs its post-unlock actions.
+// Returns the updated item and, for items that are still sealed, the remaining time.
+// The item's lock is held while its state changes, not while the actions run;
+// an item another process is working on is skipped.
+func (r *statusRun) check(item SealedItem, itemDir string) (SealedItem, *Countdown) {
+	lock, err := lockItem(itemDir)
+	if err != nil {
+		r.warnings = append(r.warnings, fmt.Sprintf("warning: %v; skipped", err))
+		return item, nil
+	}
+
+	// The holder may have changed the item while this run waited
+	reloaded, err := loadMetadata(itemDir)
+	if err != nil {
+		lock.unlock()
+		r.warnings = append(r.warnings, fmt.Sprintf("warning: item %s: %v; skipped", item.ID, err))
+		return item, nil
+	}
+	checked := r.checkLocked(reloaded, itemDir)
+	lock.unlock()
+
+	if !checked.valid {
+		return checked.item, checked.countdown
+	}
+	return r.runPostUnlock(checked, itemDir), checked.countdown
+}
+
+// itemCheck is the outcome of checking an item under its lock.
+type itemCheck struct {
+	item      SealedItem
+	countdown *Countdown // for items that are still sealed
+	valid     bool       // passed validation, so its post-unlock actions may run
+	unlocked  bool       // unlocked by this check
+}
+
+// checkLocked validates and materializes an item whose lock the caller holds,
+// and enforces retention. The post-unlock actions are left to runPostUnlock,
+// which the caller runs once it has released the lock.
+func (r *statusRun) checkLocked(item SealedItem, itemDir string) itemCheck {
+	// Validate item state invariants after loading
+	if err := ValidateItemState(item, itemDir); err != nil {
+		r.validationFailed = true
+		r.validationErrors = append(r.validationErrors, err)
+		recordValidationFailure(item, itemDir, err)
+		return itemCheck{item: item}
+	}
+
+	// Record the first time Seal checked this item
+	if !hasHistoryEvent(item, HistoryFirstCheck) {
+		appendHistory(&item, HistoryFirstCheck, "")
+		if err := saveMetadata(itemDir, item); err != nil {
+			r.warnings = append(r.warnings, fmt.Sprintf("warning: failed to record history for item %s: %v", item.ID, err))
+		}
+	}
+
+	// Attempt materialization (idempotent - no-op if already unlocked)
+	// materialize handles metadata persistence via saveMetadata
+	wasSealed := item.State == StateSealed
+	authority := r.authority(item)
+	updatedItem := item
+	err := r.checkAAD(item)
+	if err == nil && authority != nil {
+		updatedItem, err = materialize(item, itemDir, authority)
+	} else if err == nil && item.State == StateUnlocked {
+		// Finish a commit interrupted between the commit point and the final rename
+		err = recoverPendingUnseal(item, itemDir)
+	}
+	// A recorded unlock failure is retried after backoff, not a materialization failure
+	var failed *unlockFailure
+	if errors.As(err, &failed) {
+		if timeauth.IsNetworkError(failed.cause) {
+			r.unlockUnreachable = true
 		} else {
-			// Update to post-materialization state
-			items[i] = updatedItem
+			r.unlockFailed = true
+		}
+		err = nil
+	}
+	r.countAttempt(wasSealed, item, updatedItem, err)
+
+	// A commit that has failed to finalize for a long time needs a person, not another retry
+	var stuck *stuckCommitError
+	if errors.As(err, &stuck) {
+		r.validationFailed = true
+		r.validationErrors = append(r.validationErrors, fmt.Errorf("%w (%s)", stuck, stuck.remediation()))
+		return itemCheck{item: item}
+	}
+
+	checked := itemCheck{valid: true}
+	if err != nil {
+		// Track error but continue processing other items
+		if !r.materializationFailed {
+			r.firstError = err
+			r.materializationFailed = true
+		}
+		// Item remains in its current state (sealed)
+		r.warnings = append(r.warnings, notifyItemEvent(item, NotifyEventUnlockFailed, err, r.cfg)...)
+	} else {
+		// Update to post-materialization state
+		item = updatedItem
+		if wasSealed && item.State == StateUnlocked {
+			r.unlocked = append(r.unlocked, item.ID)
+			checked.unlocked = true
+		}
+	}
+
+	// Shred unsealed content whose retention period has elapsed (best-effort)
+	item, shredWarnings, err := enforceRetention(item, itemDir, clock.UTC())
+	if err != nil {
+		r.warnings = append(r.warnings, fmt.Sprintf("warning: retention enforcement failed for item %s: %v", item.ID, err))
+	}
+	r.warnings = append(r.warnings, shredWarnings...)
+	checked.item = item
+
+	// An item that can never unlock has no remaining time to report
+	if reason := permanentLockReason(item); reason != "" {
+		r.warnings = append(r.warnings, fmt.Sprintf("warning: item %s is permanently locked: %s; it can never unlock", item.ID, reason))
+		return checked
+	}
+
+	// Report remaining time for items that are still sealed
+	// (local clock only when the authority is unreachable)
+	if item.State == StateSealed {
+		countdown := ComputeCountdown(item, authority)
+		checked.countdown = &countdown
+	}
+
+	return checked
+}
+
+// runPostUnlock runs the actions that follow an unlock: the unlocked notification
+// and unlock hooks for an item this run unlocked, then pending reveal delivery and
+// post-processing. These call other programs and services and can take minutes,
+// so they run without the item's lock, under an action lock that keeps two
+// processes from running them at once; their outcome is recorded with
+// updateMetadata. Returns the item as last recorded.
+func (r *statusRun) runPostUnlock(checked itemCheck, itemDir string) SealedItem {
+	item := checked.item
+	if checked.unlocked {
+		r.warnings = append(r.warnings, notifyItemEvent(item, NotifyEventUnlocked, nil, r.cfg)...)
+		r.warnings = append(r.warnings, runItemUnlockHooks(item, itemDir)...)
+	}
+	if !postUnlockPending(item) {
+		return item
+	}
+
+	// Another process running them reports their outcome itself
+	lock, ok := tryLockItemActions(itemDir)
+	if !ok {
+		return item
+	}
+	defer lock.unlock()
+
+	// They may have run while this process waited for the item
+	current, err := loadMetadata(itemDir)
+	if err != nil {
+		r.warnings = append(r.warnings, fmt.Sprintf("warning: item %s: %v", item.ID, err))
+		return item
+	}
+	item = current
+
+	// Deliver unlocked content to the reveal target (best-effort, retried next run)
+	item, err = deliverPendingReveal(item, itemDir, r.cfg)
+	if err != nil {
+		r.warnings = append(r.warnings, fmt.Sprintf("warning: reveal delivery failed for item %s: %v", item.ID, err))
+	}
+
+	// Run declared post-processing steps once on newly unlocked content
+	item, err = runPendingPostProcess(item, itemDir)
+	if err != nil {
+		r.warnings = append(r.warnings, fmt.Sprintf("warning: post-processing failed for item %s: %v", item.ID, err))
+	}
+
+	return item
+}
+
+// postUnlockPending reports whether an unlocked item has reveal delivery
+// or post-processing left to run.
+func postUnlockPending(item SealedItem) bool {
+	if item.State != StateUnlocked {
+		return false
+	}
+	revealPending := item.RevealTo != "" && item.RevealStatus != RevealStatusDelivered
+	postProcessPending := len(item.PostProcess) > 0 && len(item.PostProcessResults) == 0
+	return revealPending || postProcessPending
+}
+
+// countAttempt adds the outcome of one materialization attempt to the run summary.
+func (r *statusRun) countAttempt(wasSealed bool, before, after SealedItem, err error) {
+	if !wasSealed {
+		return
+	}
+
+	r.summary.Checked++
+	switch {
+	case err != nil, after.UnlockFailures > before.UnlockFailures:
+		r.summary.Failed++
+	case after.State == StateUnlocked:
+		r.summary.Unlocked++
+	default:
+		r.summary.NotDue++
+	}
+}
+
+// authority returns the time authority for a sealed item, or nil if the item
+// never unlocks or the authority is unreachable in this run.
+func (r *statusRun) authority(item SealedItem) timeauth.Authority {
+	if item.State != StateSealed {
+		return nil
+	}
+
+	// Items of one authority can be on different networks, so reuse is per instance
+	authority := r.authorityFor(item)
+	key := item.TimeAuthority
+	if authority != nil {
+		key = timeauth.InstanceID(authority)
+	}
+
+	if cached, ok := r.authorities[key]; ok {
+		authority = cached
+	} else {
+		if authority != nil {
+			authority = newRunAuthority(authority)
+
+			// Probe once: an unreachable authority is one condition, not a failure per item
+			if _, err := authority.LatestRound(context.Background()); err != nil {
+				r.offline[key] = &offlineAuthority{err: err}
+				r.offlineKeys = append(r.offlineKeys, key)
+			}
 		}
+		r.authorities[key] = authority
+	}
+
+	if offline, ok := r.offline[key]; ok {
+		offline.skipped++
+		return nil
+	}
+	return authority
+}
+
+// checkAAD refuses to unlock legacy items without metadata binding when require-aad is on.
+// Imported tlock files have no payload cipher of their own to bind.
+func (r *statusRun) checkAAD(item SealedItem) error {
+	if r.cfg.RequireAAD && item.State == StateSealed && item.AADVersion == 0 && !isTlockFile(item) {
+		return fmt.Errorf("item %s: payload is not bound to its metadata (legacy item) and require-aad is on", item.ID)
+	}
+	return nil
+}
+
+func (r *statusRun) result() StatusResult {
+	for _, key := range r.offlineKeys {
+		offline := r.offline[key]
+		r.warnings = append(r.warnings, fmt.Sprintf("warning: time authority %s unreachable, %d sealed item(s) not checked for unlock; remaining time uses the local clock: %v", key, offline.skipped, offline.err))
 	}
 
 	return StatusResult{
-		Items:                 items,
-		MaterializationFailed: materializationFailed,
-		FirstError:            firstError,
-		ValidationFailed:      validationFailed,
-		ValidationErrors:      validationErrors,
-	}, nil
+		MaterializationFailed: r.materializationFailed,
+		FirstError:            r.firstError,
+		ValidationFailed:      r.validationFailed,
+		ValidationErrors:      r.validationErrors,
+		Warnings:              r.warnings,
+		Summary:               r.summary,
+		Unlocked:              r.unlocked,
+		AuthorityUnreachable:  len(r.offline) > 0 || r.unlockUnreachable,
+		UnlockFailed:          r.unlockFailed,
+	}
+}
+
+// recordValidationFailure appends a validation failure to the item's history.
+// Repeated identical failures are recorded once. This is best-effort: it only
+// touches meta.json and never repairs the inconsistency that was detected.
+func recordValidationFailure(item SealedItem, itemDir string, validationErr error) {
+	if last, ok := lastHistoryEntry(item); ok && last.Event == HistoryValidationFailed && last.Detail == validationErr.Error() {
+		return
+	}
+
+	appendHistory(&item, HistoryValidationFailed, validationErr.Error())
+	saveMetadata(itemDir, item)
 }
 
 // FormatStatusOutput formats status items for display.
-func FormatStatusOutput(items []SealedItem) string {
+// Sealed items with a countdown also report remaining time and its source.
+func FormatStatusOutput(items []SealedItem, countdowns map[string]Countdown) string {
 	if len(items) == 0 {
 		return "no sealed items"
 	}
 
 	result := ""
 	for _, item := range items {
-		result += fmt.Sprintf("id: %s\nstate: %s\nunlock_time: %s\ninput_type: %s\n\n",
+		// Sealed items show when they actually unlock, per the authority
+		var effective time.Time
+		if countdown, ok := countdowns[item.ID]; ok && item.State == StateSealed {
+			effective = countdown.UnlockAt
+		}
+
+		result += fmt.Sprintf("id: %s\nstate: %s\nunlock_time: %s\ninput_type: %s\n",
 			item.ID,
 			item.State,
-			item.UnlockTime.Format("2006-01-02T15:04:05Z07:00"),
+			formatUnlockTime(item, effective),
 			item.InputType)
+
+		if len(item.Tags) > 0 {
+			result += fmt.Sprintf("tags: %s\n", formatTags(item.Tags))
+		}
+
+		if item.Note != "" {
+			result += fmt.Sprintf("note: %s\n", item.Note)
+		}
+
+		if item.CiphertextSize > 0 {
+			result += fmt.Sprintf("size: %d bytes (ciphertext: %d bytes)\n", item.PlaintextSize, item.CiphertextSize)
+		}
+
+		if countdown, ok := countdowns[item.ID]; ok && item.State == StateSealed {
+			result += fmt.Sprintf("remaining: %s (source: %s)\n", formatRemaining(countdown.Remaining), countdown.Source)
+			if countdown.TargetRound > 0 {
+				result += fmt.Sprintf("round: %s\n", formatRounds(countdown))
+			}
+		}
+
+		if reason := permanentLockReason(item); reason != "" {
+			result += fmt.Sprintf("permanently locked: %s\n", reason)
+		}
+
+		result += "\n"
 	}
 
 	return result
 }
+
+// statusJSONLine is one NDJSON status record: the item's metadata plus, for
+// sealed items, the remaining time.
+type statusJSONLine struct {
+	SealedItem
+	RemainingSeconds    *int64     `json:"remaining_seconds,omitempty"`
+	RemainingSource     string     `json:"remaining_source,omitempty"`
+	EffectiveUnlockTime *time.Time `json:"effective_unlock_time,omitempty"` // when the target round is published
+	TargetRound         uint64     `json:"target_round,omitempty"`
+	CurrentRound        uint64     `json:"current_round,omitempty"`      // latest round of the authority
+	PermanentlyLocked   string     `json:"permanently_locked,omitempty"` // why the item can never unlock
+}
+
+// FormatStatusNDJSON formats one item as a single line of JSON.
+func FormatStatusNDJSON(item SealedItem, countdown *Countdown) (string, error) {
+	line := statusJSONLine{SealedItem: item, PermanentlyLocked: permanentLockReason(item)}
+	if countdown != nil {
+		seconds := int64(countdown.Remaining / time.Second)
+		line.RemainingSeconds = &seconds
+		line.RemainingSource = countdown.Source
+		line.TargetRound = countdown.TargetRound
+		line.CurrentRound = countdown.CurrentRound
+		if !countdown.UnlockAt.IsZero() {
+			unlockAt := countdown.UnlockAt.UTC()
+			line.EffectiveUnlockTime = &unlockAt
+		}
+	}
+
+	data, err := json.Marshal(line)
+	if err != nil {
+		return "", fmt.Errorf("cannot marshal status for item %s: %w", item.ID, err)
+	}
+	return string(data) + "\n", nil
+}
@@ -2,8 +2,12 @@ package seal
 
 import (
 	"encoding/json"
+	"errors"
+	"net"
+	"net/smtp"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
@@ -119,3 +123,370 @@ func TestPlaceholderSealedItems_NeverMaterialize(t *testing.T) {
 		t.Errorf("metadata state should be sealed, got %s", meta.State)
 	}
 }
+
+func TestStreamStatus_MatchesGetStatus(t *testing.T) {
+	_, cleanup := testutil.SetupTestEnv(t)
+	defer cleanup()
+
+	authority := &timeauth.FakeAuthority{DefaultRound: 100}
+	for i := 0; i < 3; i++ {
+		if _, err := CreateSealedItem(time.Now().UTC().Add(time.Hour), InputSourceStdin, "", []byte("data"), authority); err != nil {
+			t.Fatalf("CreateSealedItem failed: %v", err)
+		}
+	}
+
+	seen := make(map[string]bool)
+	_, err := StreamStatus(func(item SealedItem, countdown *Countdown) error {
+		seen[item.ID] = true
+		if item.State == StateSealed && countdown == nil {
+			t.Errorf("sealed item %s should have a countdown", item.ID)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("StreamStatus failed: %v", err)
+	}
+
+	result, err := GetStatus()
+	if err != nil {
+		t.Fatalf("GetStatus failed: %v", err)
+	}
+	if len(seen) != len(result.Items) {
+		t.Fatalf("streamed %d items, GetStatus returned %d", len(seen), len(result.Items))
+	}
+	for _, item := range result.Items {
+		if !seen[item.ID] {
+			t.Errorf("item %s not streamed", item.ID)
+		}
+	}
+}
+
+func TestFormatStatusNDJSON_SingleLine(t *testing.T) {
+	item := SealedItem{ID: "test-id", State: StateSealed}
+	countdown := &Countdown{Remaining: 90 * time.Second, Source: CountdownSourceRounds}
+
+	line, err := FormatStatusNDJSON(item, countdown)
+	if err != nil {
+		t.Fatalf("FormatStatusNDJSON failed: %v", err)
+	}
+	if strings.Count(line, "\n") != 1 || !strings.HasSuffix(line, "\n") {
+		t.Errorf("expected exactly one trailing newline, got: %q", line)
+	}
+
+	var decoded map[string]any
+	if err := json.Unmarshal([]byte(line), &decoded); err != nil {
+		t.Fatalf("not valid JSON: %v", err)
+	}
+	if decoded["id"] != "test-id" || decoded["remaining_seconds"] != float64(90) || decoded["remaining_source"] != "rounds" {
+		t.Errorf("unexpected record: %v", decoded)
+	}
+
+	// Items that are not sealed carry no remaining time
+	line, _ = FormatStatusNDJSON(SealedItem{ID: "test-id", State: StateUnlocked}, nil)
+	if strings.Contains(line, "remaining") {
+		t.Errorf("unlocked item should not report remaining time, got: %s", line)
+	}
+}
+
+func TestStatusRun_Offline_SingleWarningNoFailures(t *testing.T) {
+	_, cleanup := testutil.SetupTestEnv(t)
+	defer cleanup()
+
+	authority := &timeauth.FakeAuthority{DefaultRound: 100, CurrentRound: 200}
+	var ids []string
+	for i := 0; i < 3; i++ {
+		id, err := CreateSealedItem(time.Now().UTC().Add(time.Hour), InputSourceStdin, "", []byte("data"), authority)
+		if err != nil {
+			t.Fatalf("CreateSealedItem failed: %v", err)
+		}
+		ids = append(ids, id)
+	}
+
+	// All relays unreachable
+	authority.CanUnlockError = errors.New("dial tcp: network is unreachable")
+
+	run := newStatusRun()
+	run.authorityFor = func(SealedItem) timeauth.Authority { return authority }
+
+	for _, id := range ids {
+		item, itemDir, _ := LoadItem(id)
+		checked, countdown := run.check(item, itemDir)
+		if checked.State != StateSealed {
+			t.Errorf("item %s should stay sealed, got %s", id, checked.State)
+		}
+		if countdown == nil || countdown.Source != CountdownSourceLocalClock {
+			t.Errorf("item %s should report a local clock countdown, got %+v", id, countdown)
+		}
+
+		persisted, _, _ := LoadItem(id)
+		if persisted.UnlockFailures != 0 {
+			t.Errorf("item %s: offline is not a per-item unlock failure", id)
+		}
+	}
+
+	result := run.result()
+	if result.MaterializationFailed {
+		t.Errorf("offline must not fail status: %v", result.FirstError)
+	}
+	if len(result.Warnings) != 1 || !strings.Contains(result.Warnings[0], "unreachable, 3 sealed item(s)") {
+		t.Errorf("expected a single offline warning, got: %v", result.Warnings)
+	}
+	if !result.AuthorityUnreachable {
+		t.Error("expected the run to report the authority unreachable")
+	}
+}
+
+func TestStatusRun_Offline_OnlySkipsThatAuthority(t *testing.T) {
+	_, cleanup := testutil.SetupTestEnv(t)
+	defer cleanup()
+
+	down := &timeauth.FakeAuthority{AuthorityName: "down", DefaultRound: 100, CurrentRound: 200}
+	up := &timeauth.FakeAuthority{AuthorityName: "up", DefaultRound: 100, CurrentRound: 200}
+	downID, err := CreateSealedItem(time.Now().UTC().Add(time.Hour), InputSourceStdin, "", []byte("data"), down)
+	if err != nil {
+		t.Fatalf("CreateSealedItem failed: %v", err)
+	}
+	upID, err := CreateSealedItem(time.Now().UTC().Add(time.Hour), InputSourceStdin, "", []byte("data"), up)
+	if err != nil {
+		t.Fatalf("CreateSealedItem failed: %v", err)
+	}
+	down.CanUnlockError = errors.New("dial tcp: network is unreachable")
+
+	run := newStatusRun()
+	run.authorityFor = func(item SealedItem) timeauth.Authority {
+		if item.TimeAuthority == "down" {
+			return down
+		}
+		return up
+	}
+
+	// The unreachable authority is probed first, as directory order may have it
+	for _, id := range []string{downID, upID} {
+		item, itemDir, _ := LoadItem(id)
+		run.check(item, itemDir)
+	}
+
+	if item, _, _ := LoadItem(downID); item.State != StateSealed {
+		t.Errorf("item of the unreachable authority should stay sealed, got %s", item.State)
+	}
+	if item, _, _ := LoadItem(upID); item.State != StateUnlocked {
+		t.Errorf("item of the reachable authority should unlock, got %s", item.State)
+	}
+
+	result := run.result()
+	if len(result.Warnings) != 1 || !strings.Contains(result.Warnings[0], "time authority down unreachable, 1 sealed item(s)") {
+		t.Errorf("expected an offline warning for the unreachable authority only, got: %v", result.Warnings)
+	}
+	if !result.AuthorityUnreachable {
+		t.Error("expected the run to report an authority unreachable")
+	}
+}
+
+func TestStatusRun_PostUnlockActionsLeaveItemFree(t *testing.T) {
+	_, cleanup := testutil.SetupTestEnv(t)
+	defer cleanup()
+
+	defer func(wait time.Duration) { itemLockWait = wait }(itemLockWait)
+	itemLockWait = 100 * time.Millisecond
+
+	authority := &timeauth.FakeAuthority{DefaultRound: 100, CurrentRound: 200}
+	id, err := CreateSealedItemWithOptions(time.Now().UTC().Add(time.Hour), InputSourceStdin, "", []byte("data"), authority,
+		ItemOptions{RevealTo: "mailto:alice@example.com"})
+	if err != nil {
+		t.Fatalf("CreateSealedItemWithOptions failed: %v", err)
+	}
+
+	// Delivery takes long; meanwhile the item can be relabelled, and the change is kept
+	note := "relabelled during delivery"
+	stubSendMail(t, func(string, smtp.Auth, string, []string, []byte) error {
+		if _, err := Relabel(id, RelabelRequest{Note: &note}); err != nil {
+			t.Errorf("item busy during reveal delivery: %v", err)
+		}
+		return nil
+	})
+
+	run := newStatusRun()
+	run.cfg.SMTP = SMTPConfig{Host: "smtp.example.com", From: "seal@example.com"}
+	run.authorityFor = func(SealedItem) timeauth.Authority { return authority }
+	item, itemDir, _ := LoadItem(id)
+	if checked, _ := run.check(item, itemDir); checked.RevealStatus != RevealStatusDelivered {
+		t.Fatalf("expected the reveal to be delivered, got %q", checked.RevealStatus)
+	}
+
+	persisted, _, _ := LoadItem(id)
+	if persisted.State != StateUnlocked || persisted.RevealStatus != RevealStatusDelivered || persisted.Note != note {
+		t.Errorf("expected unlock, delivery and note to be recorded, got %s, %q, %q", persisted.State, persisted.RevealStatus, persisted.Note)
+	}
+}
+
+func TestStatusRun_Online_MaterializesDueItems(t *testing.T) {
+	_, cleanup := testutil.SetupTestEnv(t)
+	defer cleanup()
+
+	authority := &timeauth.FakeAuthority{DefaultRound: 100, CurrentRound: 200}
+	id, err := CreateSealedItem(time.Now().UTC().Add(time.Hour), InputSourceStdin, "", []byte("data"), authority)
+	if err != nil {
+		t.Fatalf("CreateSealedItem failed: %v", err)
+	}
+
+	run := newStatusRun()
+	run.authorityFor = func(SealedItem) timeauth.Authority { return authority }
+
+	item, itemDir, _ := LoadItem(id)
+	checked, countdown := run.check(item, itemDir)
+	if checked.State != StateUnlocked || countdown != nil {
+		t.Errorf("expected due item to unlock, got %s", checked.State)
+	}
+
+	result := run.result()
+	if len(result.Warnings) != 0 {
+		t.Errorf("unexpected warnings: %v", result.Warnings)
+	}
+	if result.AuthorityUnreachable {
+		t.Error("a reachable authority was reported unreachable")
+	}
+}
+
+func TestStatusRun_UnlockFailure_NetworkIsUnreachable(t *testing.T) {
+	_, cleanup := testutil.SetupTestEnv(t)
+	defer cleanup()
+
+	// The round is reached, so the probe passes; fetching its beacon then fails
+	refused := &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}
+	offline := &timeauth.FakeAuthority{AuthorityName: "offline", DefaultRound: 100, CurrentRound: 200, DecryptError: refused}
+	broken := &timeauth.FakeAuthority{AuthorityName: "broken", DefaultRound: 100, CurrentRound: 200, DecryptError: errors.New("invalid beacon signature")}
+
+	for _, tt := range []struct {
+		authority   *timeauth.FakeAuthority
+		unreachable bool
+	}{
+		{offline, true},
+		{broken, false},
+	} {
+		id, err := CreateSealedItem(time.Now().UTC().Add(time.Hour), InputSourceStdin, "", []byte("data"), tt.authority)
+		if err != nil {
+			t.Fatalf("CreateSealedItem failed: %v", err)
+		}
+
+		run := newStatusRun()
+		run.authorityFor = func(SealedItem) timeauth.Authority { return tt.authority }
+		item, itemDir, _ := LoadItem(id)
+		if checked, _ := run.check(item, itemDir); checked.UnlockFailures != 1 {
+			t.Errorf("%s: expected the failure to be recorded for backoff, got %d", tt.authority.AuthorityName, checked.UnlockFailures)
+		}
+
+		result := run.result()
+		if result.MaterializationFailed {
+			t.Errorf("%s: a recorded unlock failure is not a materialization failure: %v", tt.authority.AuthorityName, result.FirstError)
+		}
+		if result.AuthorityUnreachable != tt.unreachable || result.UnlockFailed == tt.unreachable {
+			t.Errorf("%s: got unreachable %v, unlock failed %v", tt.authority.AuthorityName, result.AuthorityUnreachable, result.UnlockFailed)
+		}
+	}
+}
+
+func TestStatusRun_Summary(t *testing.T) {
+	_, cleanup := testutil.SetupTestEnv(t)
+	defer cleanup()
+
+	create := func(round uint64) SealedItem {
+		authority := &timeauth.FakeAuthority{DefaultRound: round}
+		id, err := CreateSealedItem(time.Now().UTC().Add(time.Hour), InputSourceStdin, "", []byte("data"), authority)
+		if err != nil {
+			t.Fatalf("CreateSealedItem failed: %v", err)
+		}
+		item, _, _ := LoadItem(id)
+		return item
+	}
+
+	due := create(100)
+	notDue := create(300)
+	broken := create(150)
+
+	authority := &timeauth.FakeAuthority{CurrentRound: 200}
+	// Authorities are cached per instance; the failing one is its own instance
+	failing := &timeauth.FakeAuthority{AuthorityName: "failing", CurrentRound: 200, DecryptError: errors.New("beacon fetch failed")}
+
+	run := newStatusRun()
+	run.authorityFor = func(item SealedItem) timeauth.Authority {
+		if item.ID == broken.ID {
+			return failing
+		}
+		return authority
+	}
+
+	baseDir, _ := GetSealBaseDir()
+	for _, item := range []SealedItem{due, notDue, broken} {
+		run.check(item, filepath.Join(baseDir, item.ID))
+	}
+
+	// Already unlocked items are not counted again
+	unlocked, _, _ := LoadItem(due.ID)
+	run.check(unlocked, filepath.Join(baseDir, due.ID))
+
+	want := MaterializationSummary{Checked: 3, NotDue: 1, Unlocked: 1, Failed: 1}
+	if got := run.result().Summary; got != want {
+		t.Errorf("got summary %+v, want %+v", got, want)
+	}
+
+	if line := FormatMaterializationSummary(want); line != "materialization: 3 checked, 1 not due, 1 unlocked, 1 failed\n" {
+		t.Errorf("unexpected summary line: %q", line)
+	}
+}
+
+func TestStatusRun_EscalatesStuckCommit(t *testing.T) {
+	if os.Geteuid() == 0 {
+		t.Skip("permission checks do not apply to root")
+	}
+
+	_, cleanup := testutil.SetupTestEnv(t)
+	defer cleanup()
+
+	authority := &timeauth.FakeAuthority{DefaultRound: 100, CurrentRound: 200}
+	id, err := CreateSealedItem(time.Now().UTC().Add(time.Hour), InputSourceStdin, "", []byte("data"), authority)
+	if err != nil {
+		t.Fatalf("CreateSealedItem failed: %v", err)
+	}
+
+	item, itemDir, _ := LoadItem(id)
+	item, err = TryMaterialize(item, itemDir, authority)
+	if err != nil || item.State != StateUnlocked {
+		t.Fatalf("TryMaterialize failed: %v", err)
+	}
+
+	// Committed, but the final rename never happened and cannot happen now
+	unsealedPath := UnsealedPath(item, itemDir)
+	pendingPath := unsealedPath + ".pending"
+	if err := os.Rename(unsealedPath, pendingPath); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chmod(itemDir, 0500); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chmod(itemDir, 0700)
+
+	check := func() StatusResult {
+		run := newStatusRun()
+		run.authorityFor = func(SealedItem) timeauth.Authority { return authority }
+		run.check(item, itemDir)
+		return run.result()
+	}
+
+	// A recent pending file is retried quietly
+	if result := check(); !result.MaterializationFailed || result.ValidationFailed {
+		t.Errorf("expected an ordinary materialization failure, got %+v", result)
+	}
+
+	old := time.Now().Add(-2 * stuckCommitAge)
+	if err := os.Chtimes(pendingPath, old, old); err != nil {
+		t.Fatal(err)
+	}
+
+	result := check()
+	if !result.ValidationFailed || len(result.ValidationErrors) != 1 {
+		t.Fatalf("expected the stuck commit to be escalated, got %+v", result)
+	}
+	if msg := result.ValidationErrors[0].Error(); !strings.Contains(msg, "stuck in "+pendingPath) || !strings.Contains(msg, "by hand") {
+		t.Errorf("expected remediation in error, got: %s", msg)
+	}
+}
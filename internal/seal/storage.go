@@ -7,10 +7,67 @@ import (
 	"os"
 	"path/filepath"
 	"runtime"
+
+	"github.com/google/uuid"
 )
 
-// GetSealBaseDir returns the OS-appropriate base directory for Seal data.
+// baseDirOverride replaces the base directory while set, so a benchmark
+// never touches the user's store. Set only by RunBench.
+var baseDirOverride string
+
+// DataDirEnvVar names a store directory to use instead of the default one.
+// seal --data-dir sets it for the command and anything the command runs.
+const DataDirEnvVar = "SEAL_DATA_DIR"
+
+// GetSealBaseDir returns the base directory for Seal data.
+// This is $SEAL_DATA_DIR if set, otherwise the OS-appropriate default unless the
+// store was relocated with MoveStore, in which case the default directory holds
+// a pointer to the new location.
 func GetSealBaseDir() (string, error) {
+	if baseDirOverride != "" {
+		return baseDirOverride, nil
+	}
+
+	if dataDir := os.Getenv(DataDirEnvVar); dataDir != "" {
+		abs, err := filepath.Abs(dataDir)
+		if err != nil {
+			return "", fmt.Errorf("invalid %s: %w", DataDirEnvVar, err)
+		}
+		return abs, nil
+	}
+
+	home, err := defaultSealBaseDir()
+	if err != nil {
+		return "", err
+	}
+
+	location, err := readStoreLocation(home)
+	if err != nil {
+		return "", err
+	}
+	if location != "" {
+		return location, nil
+	}
+
+	return home, nil
+}
+
+// cacheDirName is the store directory for data cached from time authorities;
+// it is not a UUID, so never taken for an item.
+const cacheDirName = ".cache"
+
+// CacheDir returns the directory in the store where data fetched from time
+// authorities, such as drand chain info, is kept between runs.
+func CacheDir() (string, error) {
+	baseDir, err := GetSealBaseDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(baseDir, cacheDirName), nil
+}
+
+// defaultSealBaseDir returns the OS-appropriate default base directory for Seal data.
+func defaultSealBaseDir() (string, error) {
 	var baseDir string
 
 	switch runtime.GOOS {
@@ -44,6 +101,32 @@ func GetSealBaseDir() (string, error) {
 	return baseDir, nil
 }
 
+// LoadItem loads the metadata for a single item by ID.
+// Returns the item and its directory.
+func LoadItem(id string) (SealedItem, string, error) {
+	// Reject anything that is not a UUID so the ID cannot escape the base directory
+	if _, err := uuid.Parse(id); err != nil {
+		return SealedItem{}, "", fmt.Errorf("invalid item id: %s", id)
+	}
+
+	baseDir, err := GetSealBaseDir()
+	if err != nil {
+		return SealedItem{}, "", err
+	}
+
+	itemDir := filepath.Join(baseDir, id)
+	if _, err := os.Stat(itemDir); os.IsNotExist(err) {
+		return SealedItem{}, "", fmt.Errorf("item not found: %s", id)
+	}
+
+	item, err := loadMetadata(itemDir)
+	if err != nil {
+		return SealedItem{}, "", err
+	}
+
+	return item, itemDir, nil
+}
+
 // loadMetadata loads and parses the metadata file for an item.
 func loadMetadata(itemDir string) (SealedItem, error) {
 	metaPath := filepath.Join(itemDir, "meta.json")
@@ -60,7 +143,7 @@ func loadMetadata(itemDir string) (SealedItem, error) {
 	return item, nil
 }
 
-// saveMetadata saves the metadata file for an item atomically.
+// saveMetadata saves the metadata file for an item atomically, see writeFileAtomic.
 func saveMetadata(itemDir string, item SealedItem) error {
 	metaPath := filepath.Join(itemDir, "meta.json")
 	metaJSON, err := json.MarshalIndent(item, "", "  ")
@@ -68,15 +151,22 @@ func saveMetadata(itemDir string, item SealedItem) error {
 		return fmt.Errorf("failed to marshal metadata: %w", err)
 	}
 
-	tmpMetaPath := metaPath + ".tmp"
-	if err := os.WriteFile(tmpMetaPath, metaJSON, 0600); err != nil {
-		return fmt.Errorf("failed to write metadata: %w", err)
+	// Immutable metadata cannot be replaced; lift the attribute for this update only
+	if item.Immutable {
+		setImmutable(metaPath, false)
 	}
 
-	if err := os.Rename(tmpMetaPath, metaPath); err != nil {
-		os.Remove(tmpMetaPath)
+	if err := writeFileAtomic(metaPath, metaJSON, 0600); err != nil {
+		if item.Immutable {
+			setImmutable(metaPath, true)
+		}
 		return fmt.Errorf("failed to update metadata: %w", err)
 	}
 
+	// Best-effort: the attribute was already reported at lock time if unsupported
+	if item.Immutable {
+		setImmutable(metaPath, true)
+	}
+
 	return nil
 }
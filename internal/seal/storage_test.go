@@ -133,7 +133,7 @@ func TestCreateSealedItem_And_List(t *testing.T) {
 	}
 
 	// List sealed items
-	items, err := ListSealedItems()
+	items, err := ListSealedItems(ListOptions{})
 	if err != nil {
 		t.Fatalf("listSealedItems failed: %v", err)
 	}
@@ -259,3 +259,27 @@ func TestMetadata_IncludesCryptoFields(t *testing.T) {
 		t.Errorf("nonce should be valid base64: %v", err)
 	}
 }
+
+func TestGetSealBaseDir_DataDirEnv(t *testing.T) {
+	_, cleanup := testutil.SetupTestEnv(t)
+	defer cleanup()
+
+	dir := t.TempDir()
+	t.Setenv(DataDirEnvVar, dir)
+
+	baseDir, err := GetSealBaseDir()
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if baseDir != dir {
+		t.Errorf("expected %s, got %s", dir, baseDir)
+	}
+
+	// A relative path is resolved against the working directory
+	t.Chdir(dir)
+	t.Setenv(DataDirEnvVar, "store")
+	baseDir, _ = GetSealBaseDir()
+	if baseDir != filepath.Join(dir, "store") {
+		t.Errorf("expected %s, got %s", filepath.Join(dir, "store"), baseDir)
+	}
+}
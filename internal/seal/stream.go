@@ -0,0 +1,354 @@
+package seal
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/google/uuid"
+
+	"seal/internal/timeauth"
+)
+
+// Streamed payloads are split into chunks that are sealed one at a time, so
+// inputs of any size are encrypted and decrypted in constant memory.
+//
+// Each chunk is AES-256-GCM sealed with the item's AAD and the nonce
+//
+//	prefix (7 random bytes) || chunk counter (4 bytes, big endian) || last (1 byte)
+//
+// where last is 1 for the final chunk and 0 otherwise. The counter stops chunks
+// from being reordered or dropped, and the last flag stops truncation at a chunk
+// boundary. The prefix is stored base64-encoded as the item's nonce.
+const (
+	streamAlgorithm   = "aes-256-gcm-stream"
+	streamChunkSize   = 64 * 1024
+	streamPrefixSize  = 7
+	streamOverhead    = 16 // GCM tag per chunk
+	streamMaxChunks   = math.MaxUint32
+	streamSealedChunk = streamChunkSize + streamOverhead
+)
+
+// isStreamed reports whether an item's payload uses the chunked stream format.
+func isStreamed(item SealedItem) bool {
+	return item.Algorithm == streamAlgorithm
+}
+
+// streamNonce returns the nonce of chunk n.
+func streamNonce(prefix []byte, n uint64, last bool) []byte {
+	nonce := make([]byte, 12)
+	copy(nonce, prefix)
+	binary.BigEndian.PutUint32(nonce[streamPrefixSize:], uint32(n))
+	if last {
+		nonce[11] = 1
+	}
+	return nonce
+}
+
+// streamSums counts and hashes what passed through a stream operation.
+type streamSums struct {
+	plaintextSize  int64
+	ciphertextSize int64
+	checksum       string // hex SHA-256 of the side that was written
+}
+
+// encryptStream reads plaintext from src until EOF and writes the sealed chunks to dst.
+// The checksum covers the ciphertext written.
+func encryptStream(dst io.Writer, src io.Reader, dek, prefix, aad []byte) (streamSums, error) {
+	gcm, err := newPayloadGCM(dek)
+	if err != nil {
+		return streamSums{}, err
+	}
+
+	hash := sha256.New()
+	out := io.MultiWriter(dst, hash)
+	var sums streamSums
+
+	// Reading one chunk ahead tells whether the current chunk is the last
+	chunk := make([]byte, streamChunkSize)
+	next := make([]byte, streamChunkSize)
+	sealed := make([]byte, 0, streamSealedChunk)
+
+	n, err := io.ReadFull(src, chunk)
+	for counter := uint64(0); ; counter++ {
+		if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+			return streamSums{}, fmt.Errorf("cannot read input: %w", err)
+		}
+		last := err != nil
+
+		var m int
+		var nextErr error
+		if !last {
+			m, nextErr = io.ReadFull(src, next)
+			if nextErr == io.EOF {
+				last = true
+			}
+		}
+
+		if counter > streamMaxChunks {
+			return streamSums{}, errors.New("input too large for the stream format")
+		}
+		sealed = gcm.Seal(sealed[:0], streamNonce(prefix, counter, last), chunk[:n], aad)
+		if _, err := out.Write(sealed); err != nil {
+			return streamSums{}, fmt.Errorf("cannot write payload: %w", err)
+		}
+		sums.plaintextSize += int64(n)
+		sums.ciphertextSize += int64(len(sealed))
+
+		if last {
+			break
+		}
+		chunk, next = next, chunk
+		n, err = m, nextErr
+	}
+
+	sums.checksum = hex.EncodeToString(hash.Sum(nil))
+	return sums, nil
+}
+
+// decryptStream reads an item's sealed chunks from src and writes the plaintext to dst.
+// Fails if any chunk does not authenticate, or if the stream is truncated or extended.
+// The checksum covers the plaintext written.
+func decryptStream(dst io.Writer, src io.Reader, item SealedItem, dek []byte) (streamSums, error) {
+	prefix, err := base64.StdEncoding.DecodeString(item.Nonce)
+	if err != nil || len(prefix) != streamPrefixSize {
+		return streamSums{}, fmt.Errorf("item %s: invalid stream nonce", item.ID)
+	}
+
+	gcm, err := newPayloadGCM(dek)
+	if err != nil {
+		return streamSums{}, err
+	}
+
+	aad, err := itemAAD(item)
+	if err != nil {
+		return streamSums{}, fmt.Errorf("item %s: %w", item.ID, err)
+	}
+
+	hash := sha256.New()
+	out := io.MultiWriter(dst, hash)
+	var sums streamSums
+
+	chunk := make([]byte, streamSealedChunk)
+	next := make([]byte, streamSealedChunk)
+	plain := make([]byte, 0, streamChunkSize)
+
+	n, err := io.ReadFull(src, chunk)
+	for counter := uint64(0); ; counter++ {
+		if err == io.EOF {
+			return streamSums{}, fmt.Errorf("item %s: payload is truncated", item.ID)
+		}
+		if err != nil && err != io.ErrUnexpectedEOF {
+			return streamSums{}, fmt.Errorf("failed to read payload: %w", err)
+		}
+		last := err != nil
+
+		var m int
+		var nextErr error
+		if !last {
+			m, nextErr = io.ReadFull(src, next)
+			if nextErr == io.EOF {
+				last = true
+			}
+		}
+
+		if counter > streamMaxChunks {
+			return streamSums{}, fmt.Errorf("item %s: payload has too many chunks", item.ID)
+		}
+		plain, err = gcm.Open(plain[:0], streamNonce(prefix, counter, last), chunk[:n], aad)
+		if err != nil {
+			return streamSums{}, fmt.Errorf("item %s: failed to decrypt payload chunk %d: payload does not belong to this metadata, or was truncated or edited: %w", item.ID, counter, err)
+		}
+		if _, err := out.Write(plain); err != nil {
+			return streamSums{}, fmt.Errorf("failed to write unsealed data: %w", err)
+		}
+		sums.plaintextSize += int64(len(plain))
+		sums.ciphertextSize += int64(n)
+
+		if last {
+			break
+		}
+		chunk, next = next, chunk
+		n, err = m, nextErr
+	}
+
+	sums.checksum = hex.EncodeToString(hash.Sum(nil))
+	return sums, nil
+}
+
+// newPayloadGCM returns AES-256-GCM keyed with dek.
+func newPayloadGCM(dek []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(dek)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create GCM: %w", err)
+	}
+	return gcm, nil
+}
+
+// CreateStreamedItem creates a new sealed item from src, read until EOF, in constant memory.
+// The payload uses the chunked stream format; there is no size limit beyond disk space.
+// Recipient and passphrase encryption and direct time-locking are not supported for streamed input.
+func CreateStreamedItem(unlockTime time.Time, inputType InputSource, originalPath string, src io.Reader, authority timeauth.Authority, opts ItemOptions) (string, error) {
+	if opts.Recipient.Recipient != "" {
+		return "", errors.New("sealing for a recipient is not supported for streamed input")
+	}
+	if opts.Passphrase != nil {
+		return "", errors.New("sealing with a passphrase is not supported for streamed input")
+	}
+	if opts.DirectTlock {
+		return "", errors.New("--direct-tlock is not supported for streamed input")
+	}
+
+	baseDir, err := GetSealBaseDir()
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(baseDir, 0700); err != nil {
+		return "", fmt.Errorf("cannot create seal directory: %w", err)
+	}
+
+	targetRound, err := authority.RoundAt(unlockTime)
+	if err != nil {
+		return "", fmt.Errorf("failed to calculate target round: %w", err)
+	}
+
+	id := uuid.New().String()
+
+	dek := make([]byte, 32)
+	if _, err := io.ReadFull(rand.Reader, dek); err != nil {
+		return "", fmt.Errorf("failed to generate DEK: %w", err)
+	}
+	defer func() {
+		// Zero out DEK from memory after use
+		for i := range dek {
+			dek[i] = 0
+		}
+	}()
+
+	prefix := make([]byte, streamPrefixSize)
+	if _, err := io.ReadFull(rand.Reader, prefix); err != nil {
+		return "", fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	tlockB64, err := timeLockDEK(dek, targetRound, authority, opts)
+	if err != nil {
+		return "", err
+	}
+
+	keyRef, err := authority.Lock(unlockTime)
+	if err != nil {
+		return "", fmt.Errorf("failed to create key reference: %w", err)
+	}
+
+	// The payload is written before the metadata: sizes and checksum are only known
+	// once the input has been read
+	err = createItemStaged(baseDir, id, func(dir string) error {
+		var sums streamSums
+		err := writeStreamNoFollow(filepath.Join(dir, "payload.bin"), 0600, func(w io.Writer) error {
+			var err error
+			sums, err = encryptStream(w, src, dek, prefix, payloadAAD(id, targetRound, streamAlgorithm))
+			return err
+		})
+		if err != nil {
+			return err
+		}
+
+		meta := newItemMetadata(id, unlockTime, inputType, originalPath, authority, keyRef, tlockB64, opts)
+		meta.Algorithm = streamAlgorithm
+		meta.Nonce = base64.StdEncoding.EncodeToString(prefix)
+		meta.PlaintextSize = sums.plaintextSize
+		meta.CiphertextSize = sums.ciphertextSize
+		meta.PayloadSHA256 = sums.checksum
+		appendHistory(&meta, HistoryCreated, "")
+
+		if err := saveMetadata(dir, meta); err != nil {
+			return fmt.Errorf("cannot write metadata: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return id, nil
+}
+
+// readLockInput reads input like ReadInputWithMode, except that input larger than
+// MaxInputSize is not an error: it is returned as stream, to be sealed with
+// CreateStreamedItem, and data is nil. The caller closes stream.
+func readLockInput(path string, mode StdinMode) (data []byte, stream io.ReadCloser, source InputSource, err error) {
+	useStdin, err := inputIsStdin(path, mode)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+
+	if !useStdin {
+		file, err := os.Open(path)
+		if err != nil {
+			return nil, nil, 0, fmt.Errorf("cannot open file: %w", err)
+		}
+		info, err := file.Stat()
+		if err != nil {
+			file.Close()
+			return nil, nil, 0, fmt.Errorf("cannot stat file: %w", err)
+		}
+		if info.Size() > MaxInputSize {
+			return nil, file, InputSourceFile, nil
+		}
+		file.Close()
+
+		data, source, err := ReadInputWithMode(path, StdinNever)
+		return data, nil, source, err
+	}
+
+	// Stdin has no size up front: what was read past the limit is replayed into the stream
+	data, err = io.ReadAll(io.LimitReader(os.Stdin, MaxInputSize+1))
+	if err != nil {
+		return nil, nil, 0, fmt.Errorf("cannot read stdin: %w", err)
+	}
+	if len(data) == 0 {
+		return nil, nil, 0, errors.New("input is empty")
+	}
+	if len(data) <= MaxInputSize {
+		return data, nil, InputSourceStdin, nil
+	}
+	return nil, io.NopCloser(io.MultiReader(bytes.NewReader(data), os.Stdin)), InputSourceStdin, nil
+}
+
+// checkStreamedLock rejects lock options that need the whole input in memory.
+func checkStreamedLock(req LockRequest) error {
+	var option string
+	switch {
+	case req.PasswordMode:
+		option = "--password-mode"
+	case req.StripNewline:
+		option = "--strip-newline"
+	case req.Encoding == EncodingUTF8:
+		option = "--encoding utf8"
+	case req.Recipient != "":
+		option = "--recipient"
+	case req.Passphrase != nil:
+		option = "--passphrase-file"
+	case req.DirectTlock:
+		option = "--direct-tlock"
+	default:
+		return nil
+	}
+	return fmt.Errorf("%s is not supported for input larger than %d bytes", option, MaxInputSize)
+}
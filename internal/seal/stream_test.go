@@ -0,0 +1,199 @@
+package seal
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"seal/internal/testutil"
+	"seal/internal/timeauth"
+)
+
+// sealStream encrypts plaintext in the stream format and returns the item describing it.
+func sealStream(t *testing.T, plaintext []byte) (SealedItem, []byte, []byte) {
+	t.Helper()
+	dek := make([]byte, 32)
+	prefix := make([]byte, streamPrefixSize)
+	rand.Read(dek)
+	rand.Read(prefix)
+
+	item := SealedItem{
+		ID:         "00000000-0000-0000-0000-000000000001",
+		Algorithm:  streamAlgorithm,
+		Nonce:      base64.StdEncoding.EncodeToString(prefix),
+		KeyRef:     "100",
+		AADVersion: aadVersion,
+	}
+	aad, err := itemAAD(item)
+	if err != nil {
+		t.Fatalf("itemAAD failed: %v", err)
+	}
+
+	var sealed bytes.Buffer
+	sums, err := encryptStream(&sealed, bytes.NewReader(plaintext), dek, prefix, aad)
+	if err != nil {
+		t.Fatalf("encryptStream failed: %v", err)
+	}
+	if sums.plaintextSize != int64(len(plaintext)) || sums.ciphertextSize != int64(sealed.Len()) {
+		t.Errorf("unexpected sizes %d/%d for %d bytes sealed to %d", sums.plaintextSize, sums.ciphertextSize, len(plaintext), sealed.Len())
+	}
+	if sums.checksum != payloadChecksum(sealed.Bytes()) {
+		t.Error("checksum does not cover the ciphertext")
+	}
+	return item, sealed.Bytes(), dek
+}
+
+func TestStream_RoundTrip(t *testing.T) {
+	for _, size := range []int{0, 1, streamChunkSize - 1, streamChunkSize, streamChunkSize + 1, 3*streamChunkSize + 100} {
+		plaintext := make([]byte, size)
+		rand.Read(plaintext)
+
+		item, sealed, dek := sealStream(t, plaintext)
+		chunks := size/streamChunkSize + 1
+		if size > 0 && size%streamChunkSize == 0 {
+			chunks--
+		}
+		if len(sealed) != size+chunks*streamOverhead {
+			t.Errorf("size %d: sealed to %d bytes, want %d chunks", size, len(sealed), chunks)
+		}
+
+		var out bytes.Buffer
+		sums, err := decryptStream(&out, bytes.NewReader(sealed), item, dek)
+		if err != nil {
+			t.Fatalf("size %d: decryptStream failed: %v", size, err)
+		}
+		if !bytes.Equal(out.Bytes(), plaintext) {
+			t.Errorf("size %d: round trip mismatch", size)
+		}
+		if sums.checksum != payloadChecksum(plaintext) {
+			t.Errorf("size %d: checksum does not cover the plaintext", size)
+		}
+	}
+}
+
+func TestStream_DetectsTampering(t *testing.T) {
+	plaintext := make([]byte, 3*streamChunkSize+100)
+	rand.Read(plaintext)
+	item, sealed, dek := sealStream(t, plaintext)
+
+	flipped := bytes.Clone(sealed)
+	flipped[streamSealedChunk+10] ^= 1
+
+	// Swap the first two chunks
+	reordered := bytes.Clone(sealed)
+	copy(reordered, sealed[streamSealedChunk:2*streamSealedChunk])
+	copy(reordered[streamSealedChunk:], sealed[:streamSealedChunk])
+
+	otherItem := item
+	otherItem.ID = "00000000-0000-0000-0000-000000000002"
+
+	cases := []struct {
+		name    string
+		payload []byte
+		item    SealedItem
+	}{
+		{"flipped bit", flipped, item},
+		{"reordered chunks", reordered, item},
+		{"truncated at chunk boundary", sealed[:2*streamSealedChunk], item},
+		{"truncated mid-chunk", sealed[:len(sealed)-5], item},
+		{"empty", nil, item},
+		{"extended", append(bytes.Clone(sealed), sealed[:streamSealedChunk]...), item},
+		{"other item", sealed, otherItem},
+	}
+	for _, tc := range cases {
+		if _, err := decryptStream(&bytes.Buffer{}, bytes.NewReader(tc.payload), tc.item, dek); err == nil {
+			t.Errorf("%s: expected decryption to fail", tc.name)
+		}
+	}
+}
+
+func TestCreateStreamedItem_Materializes(t *testing.T) {
+	_, cleanup := testutil.SetupTestEnv(t)
+	defer cleanup()
+
+	plaintext := make([]byte, 2*streamChunkSize+7)
+	rand.Read(plaintext)
+
+	authority := &timeauth.FakeAuthority{DefaultRound: 100, CurrentRound: 200}
+	id, err := CreateStreamedItem(time.Now().UTC().Add(time.Hour), InputSourceFile, "/tmp/big.bin", bytes.NewReader(plaintext), authority, ItemOptions{})
+	if err != nil {
+		t.Fatalf("CreateStreamedItem failed: %v", err)
+	}
+
+	item, itemDir, err := LoadItem(id)
+	if err != nil {
+		t.Fatalf("LoadItem failed: %v", err)
+	}
+	if item.Algorithm != streamAlgorithm || item.PlaintextSize != int64(len(plaintext)) {
+		t.Errorf("unexpected metadata: algorithm %q, plaintext size %d", item.Algorithm, item.PlaintextSize)
+	}
+	if err := ValidateItemState(item, itemDir); err != nil {
+		t.Errorf("streamed item fails validation: %v", err)
+	}
+
+	item, err = TryMaterialize(item, itemDir, authority)
+	if err != nil {
+		t.Fatalf("TryMaterialize failed: %v", err)
+	}
+	if item.State != StateUnlocked || item.UnsealedSHA256 != payloadChecksum(plaintext) {
+		t.Errorf("unexpected state %q, unsealed checksum %q", item.State, item.UnsealedSHA256)
+	}
+	unsealed, err := os.ReadFile(UnsealedPath(item, itemDir))
+	if err != nil || !bytes.Equal(unsealed, plaintext) {
+		t.Errorf("unsealed content does not match input: %v", err)
+	}
+}
+
+func TestCreateStreamedItem_CorruptPayloadStaysSealed(t *testing.T) {
+	_, cleanup := testutil.SetupTestEnv(t)
+	defer cleanup()
+
+	authority := &timeauth.FakeAuthority{DefaultRound: 100, CurrentRound: 200}
+	id, err := CreateStreamedItem(time.Now().UTC().Add(time.Hour), InputSourceStdin, "", strings.NewReader("streamed"), authority, ItemOptions{})
+	if err != nil {
+		t.Fatalf("CreateStreamedItem failed: %v", err)
+	}
+	item, itemDir, _ := LoadItem(id)
+
+	if err := os.WriteFile(filepath.Join(itemDir, "payload.bin"), []byte("garbage"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	item, err = TryMaterialize(item, itemDir, authority)
+	if err == nil || !strings.Contains(err.Error(), "checksum mismatch") {
+		t.Errorf("expected checksum mismatch, got %v", err)
+	}
+	if item.State != StateSealed {
+		t.Errorf("corrupt item changed state to %q", item.State)
+	}
+	if _, err := os.Stat(UnsealedPath(item, itemDir) + ".pending"); !os.IsNotExist(err) {
+		t.Error("pending file left behind")
+	}
+}
+
+func TestLock_LargeInputRefusesInMemoryOptions(t *testing.T) {
+	tmpDir, cleanup := testutil.SetupTestEnv(t)
+	defer cleanup()
+
+	input := filepath.Join(tmpDir, "large.bin")
+	if err := os.WriteFile(input, make([]byte, MaxInputSize+1), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	unlockTime := time.Now().UTC().Add(time.Hour).Format(time.RFC3339)
+	for option, req := range map[string]LockRequest{
+		"--password-mode":   {InputPath: input, UnlockTime: unlockTime, PasswordMode: true},
+		"--strip-newline":   {InputPath: input, UnlockTime: unlockTime, StripNewline: true},
+		"--encoding utf8":   {InputPath: input, UnlockTime: unlockTime, Encoding: EncodingUTF8},
+		"--passphrase-file": {InputPath: input, UnlockTime: unlockTime, Passphrase: []byte("correct horse battery staple")},
+	} {
+		_, err := Lock(req)
+		if err == nil || !strings.Contains(err.Error(), option) {
+			t.Errorf("expected %s to be refused for large input, got %v", option, err)
+		}
+	}
+}
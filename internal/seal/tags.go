@@ -0,0 +1,115 @@
+package seal
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Tag limits keep meta.json small and status output on one line per tag set.
+const (
+	maxTagKeyLength   = 64
+	maxTagValueLength = 256
+	maxNoteLength     = 1024
+)
+
+// ParseTags parses key=value tag arguments into a map.
+// Keys are letters, digits, '.', '_' and '-'; values may be empty but must not
+// contain control characters. A key may appear only once.
+func ParseTags(args []string) (map[string]string, error) {
+	if len(args) == 0 {
+		return nil, nil
+	}
+
+	tags := make(map[string]string, len(args))
+	for _, arg := range args {
+		key, value, ok := strings.Cut(arg, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid tag %q, expected key=value", arg)
+		}
+		if err := validateTagKey(key); err != nil {
+			return nil, err
+		}
+		if len(value) > maxTagValueLength {
+			return nil, fmt.Errorf("tag %s: value longer than %d bytes", key, maxTagValueLength)
+		}
+		if strings.IndexFunc(value, unicode.IsControl) >= 0 {
+			return nil, fmt.Errorf("tag %s: value contains control characters", key)
+		}
+		if _, dup := tags[key]; dup {
+			return nil, fmt.Errorf("tag %s given more than once", key)
+		}
+		tags[key] = value
+	}
+	return tags, nil
+}
+
+// validateTagKey checks that a tag key is non-empty and uses only allowed characters.
+func validateTagKey(key string) error {
+	if key == "" {
+		return fmt.Errorf("tag key must not be empty")
+	}
+	if len(key) > maxTagKeyLength {
+		return fmt.Errorf("tag key %q longer than %d bytes", key, maxTagKeyLength)
+	}
+	for _, r := range key {
+		if !(r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r) || r == '.' || r == '_' || r == '-')) {
+			return fmt.Errorf("tag key %q may only contain letters, digits, '.', '_' and '-'", key)
+		}
+	}
+	return nil
+}
+
+// ValidateNote checks an item note: one line of at most maxNoteLength bytes,
+// without control characters. An empty note is valid.
+func ValidateNote(note string) error {
+	if len(note) > maxNoteLength {
+		return fmt.Errorf("note longer than %d bytes", maxNoteLength)
+	}
+	if strings.IndexFunc(note, unicode.IsControl) >= 0 {
+		return fmt.Errorf("note contains control characters")
+	}
+	return nil
+}
+
+// MatchesTags reports whether an item carries every tag in filter with the same value.
+// An empty filter matches every item.
+func MatchesTags(item SealedItem, filter map[string]string) bool {
+	for key, value := range filter {
+		if got, ok := item.Tags[key]; !ok || got != value {
+			return false
+		}
+	}
+	return true
+}
+
+// FilterItemsByTags returns the items that match filter, see MatchesTags.
+func FilterItemsByTags(items []SealedItem, filter map[string]string) []SealedItem {
+	if len(filter) == 0 {
+		return items
+	}
+
+	var matched []SealedItem
+	for _, item := range items {
+		if MatchesTags(item, filter) {
+			matched = append(matched, item)
+		}
+	}
+	return matched
+}
+
+// formatTags formats tags as key=value pairs sorted by key, e.g. "case=42, project=alpha".
+func formatTags(tags map[string]string) string {
+	keys := make([]string, 0, len(tags))
+	for key := range tags {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	pairs := make([]string, len(keys))
+	for i, key := range keys {
+		pairs[i] = key + "=" + tags[key]
+	}
+	return strings.Join(pairs, ", ")
+}
@@ -0,0 +1,94 @@
+package seal
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"seal/internal/testutil"
+	"seal/internal/timeauth"
+)
+
+func TestParseTags(t *testing.T) {
+	tags, err := ParseTags([]string{"project=alpha", "ticket=OPS-1234", "note=a=b", "empty="})
+	if err != nil {
+		t.Fatalf("ParseTags failed: %v", err)
+	}
+	want := map[string]string{"project": "alpha", "ticket": "OPS-1234", "note": "a=b", "empty": ""}
+	if len(tags) != len(want) {
+		t.Fatalf("got %v, want %v", tags, want)
+	}
+	for key, value := range want {
+		if tags[key] != value {
+			t.Errorf("tag %s = %q, want %q", key, tags[key], value)
+		}
+	}
+
+	if tags, err := ParseTags(nil); err != nil || tags != nil {
+		t.Errorf("no tags should parse to nil, got %v, %v", tags, err)
+	}
+
+	invalid := map[string][]string{
+		"no equals":     {"project"},
+		"empty key":     {"=alpha"},
+		"bad key":       {"pro ject=alpha"},
+		"non-ascii key": {"projé=alpha"},
+		"control value": {"project=al\npha"},
+		"duplicate":     {"project=alpha", "project=beta"},
+		"long key":      {strings.Repeat("k", maxTagKeyLength+1) + "=v"},
+		"long value":    {"k=" + strings.Repeat("v", maxTagValueLength+1)},
+	}
+	for name, args := range invalid {
+		if _, err := ParseTags(args); err == nil {
+			t.Errorf("%s: expected error for %q", name, args)
+		}
+	}
+}
+
+func TestFilterItemsByTags(t *testing.T) {
+	items := []SealedItem{
+		{ID: "a", Tags: map[string]string{"project": "alpha", "case": "42"}},
+		{ID: "b", Tags: map[string]string{"project": "beta"}},
+		{ID: "c"},
+	}
+
+	if got := FilterItemsByTags(items, nil); len(got) != 3 {
+		t.Errorf("empty filter should match all items, got %d", len(got))
+	}
+	if got := FilterItemsByTags(items, map[string]string{"project": "alpha"}); len(got) != 1 || got[0].ID != "a" {
+		t.Errorf("expected only item a, got %v", got)
+	}
+	if got := FilterItemsByTags(items, map[string]string{"project": "alpha", "case": "43"}); len(got) != 0 {
+		t.Errorf("every filter tag must match, got %v", got)
+	}
+}
+
+func TestCreateSealedItem_StoresTags(t *testing.T) {
+	_, cleanup := testutil.SetupTestEnv(t)
+	defer cleanup()
+
+	tags := map[string]string{"project": "alpha", "case": "42"}
+	id, err := CreateSealedItemWithOptions(time.Now().UTC().Add(time.Hour), InputSourceStdin, "", []byte("data"), &timeauth.PlaceholderAuthority{}, ItemOptions{Tags: tags})
+	if err != nil {
+		t.Fatalf("CreateSealedItemWithOptions failed: %v", err)
+	}
+
+	item, _, err := LoadItem(id)
+	if err != nil {
+		t.Fatalf("LoadItem failed: %v", err)
+	}
+	if item.Tags["project"] != "alpha" || item.Tags["case"] != "42" {
+		t.Errorf("tags not stored: %v", item.Tags)
+	}
+
+	if out := FormatInspectOutput(item, nil, false); !strings.Contains(out, "tags: case=42, project=alpha\n") {
+		t.Errorf("inspect output missing sorted tags:\n%s", out)
+	}
+	json, err := FormatInspectJSON(item)
+	if err != nil {
+		t.Fatalf("FormatInspectJSON failed: %v", err)
+	}
+	if !strings.Contains(json, `"tags"`) {
+		t.Errorf("JSON output missing tags: %s", json)
+	}
+}
@@ -7,6 +7,11 @@ import (
 	"seal/internal/timeauth"
 )
 
+func init() {
+	// Many tests lock items to the placeholder authority
+	placeholderItemsAllowed = true
+}
+
 // newTestDrandAuthority creates a test drand authority.
 // This is duplicated in seal tests to avoid import cycles.
 func newTestDrandAuthority(currentRound uint64) *timeauth.DrandAuthority {
@@ -16,5 +21,6 @@ func newTestDrandAuthority(currentRound uint64) *timeauth.DrandAuthority {
 			"/public/latest": testutil.MakeDrandPublicResponse(currentRound),
 		},
 	}
-	return timeauth.NewDrandAuthorityWithDeps(fakeHTTP, &testutil.FakeTimelockBox{})
+	network := timeauth.DrandNetwork{Name: "quicknet", ChainHash: testutil.DrandChainHash(), Relay: "https://api.drand.sh"}
+	return timeauth.NewDrandAuthorityForNetworkWithDeps(network, fakeHTTP, &testutil.FakeTimelockBox{})
 }
@@ -0,0 +1,112 @@
+package seal
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"seal/internal/clock"
+)
+
+// lockDurationUnits are the fixed-length units of a lock duration.
+// Days and weeks are exact multiples of 24 hours: unlock times are UTC, so DST never applies.
+// Months (mo) have no fixed length and are handled by addMonths.
+var lockDurationUnits = map[string]time.Duration{
+	"s": time.Second,
+	"m": time.Minute,
+	"h": time.Hour,
+	"d": 24 * time.Hour,
+	"w": 7 * 24 * time.Hour,
+}
+
+// ParseUnlockSpec resolves lock's --until and --for into an absolute UTC unlock time.
+// until is parsed by ParseUnlockTime; forDuration is a lock duration from now, see
+// ParseLockDuration. Exactly one of them must be given.
+func ParseUnlockSpec(until, forDuration string) (time.Time, error) {
+	switch {
+	case until != "" && forDuration != "":
+		return time.Time{}, errors.New("--until and --for cannot be used together")
+	case forDuration != "":
+		return unlockAfter(forDuration)
+	default:
+		return ParseUnlockTime(until)
+	}
+}
+
+// unlockAfter returns the unlock time a lock duration from now.
+func unlockAfter(s string) (time.Time, error) {
+	now := clock.UTC()
+	t, err := ParseLockDuration(s, now)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return t.Truncate(time.Second), nil
+}
+
+// ParseLockDuration adds a lock duration such as 72h, 3d, 2w, 1mo or 1w2d to from.
+// A duration is one or more whole numbers, each followed by a unit: s, m (minutes),
+// h, d, w or mo (calendar months; the day is clamped to the end of a shorter month).
+// Months are applied first, then the fixed-length units.
+func ParseLockDuration(s string, from time.Time) (time.Time, error) {
+	invalid := fmt.Errorf("invalid lock duration %q, expected e.g. 72h, 3d, 2w or 1mo", s)
+	if s == "" {
+		return time.Time{}, invalid
+	}
+
+	var months int64
+	var fixed time.Duration
+	for rest := s; rest != ""; {
+		digits := len(rest) - len(strings.TrimLeft(rest, "0123456789"))
+		unitLen := len(rest[digits:]) - len(strings.TrimLeft(rest[digits:], "abcdefghijklmnopqrstuvwxyz"))
+		if digits == 0 || unitLen == 0 {
+			return time.Time{}, invalid
+		}
+		n, err := strconv.ParseInt(rest[:digits], 10, 64)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("lock duration %q is too long", s)
+		}
+		unit := rest[digits : digits+unitLen]
+		rest = rest[digits+unitLen:]
+
+		if unit == "mo" {
+			if n > math.MaxInt32-months {
+				return time.Time{}, fmt.Errorf("lock duration %q is too long", s)
+			}
+			months += n
+			continue
+		}
+		size, ok := lockDurationUnits[unit]
+		if !ok {
+			return time.Time{}, fmt.Errorf("invalid lock duration %q: unknown unit %q (use s, m, h, d, w or mo)", s, unit)
+		}
+		if n > (math.MaxInt64-int64(fixed))/int64(size) {
+			return time.Time{}, fmt.Errorf("lock duration %q is too long", s)
+		}
+		fixed += time.Duration(n) * size
+	}
+
+	if months == 0 && fixed == 0 {
+		return time.Time{}, errors.New("lock duration must be positive")
+	}
+
+	t := addMonths(from.UTC(), int(months)).Add(fixed)
+	if t.Year() > 9999 || t.Before(from) {
+		return time.Time{}, fmt.Errorf("lock duration %q is too long", s)
+	}
+	return t, nil
+}
+
+// addMonths adds calendar months to t. The day of month is clamped to the last day
+// of the resulting month, so one month after January 31 is the end of February.
+func addMonths(t time.Time, months int) time.Time {
+	if months == 0 {
+		return t
+	}
+	year, month, day := t.Date()
+	first := time.Date(year, month+time.Month(months), 1, t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), time.UTC)
+	last := first.AddDate(0, 1, -1).Day()
+	return first.AddDate(0, 0, min(day, last)-1)
+}
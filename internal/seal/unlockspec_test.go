@@ -0,0 +1,99 @@
+package seal
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"seal/internal/clock"
+)
+
+func TestParseLockDuration(t *testing.T) {
+	from := time.Date(2026, 1, 31, 10, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		input string
+		want  time.Time
+	}{
+		{"72h", from.Add(72 * time.Hour)},
+		{"90m", from.Add(90 * time.Minute)},
+		{"3d", time.Date(2026, 2, 3, 10, 0, 0, 0, time.UTC)},
+		{"2w", time.Date(2026, 2, 14, 10, 0, 0, 0, time.UTC)},
+		{"1w2d12h", time.Date(2026, 2, 9, 22, 0, 0, 0, time.UTC)},
+		// One month after January 31 is the end of February
+		{"1mo", time.Date(2026, 2, 28, 10, 0, 0, 0, time.UTC)},
+		{"13mo", time.Date(2027, 2, 28, 10, 0, 0, 0, time.UTC)},
+		{"1mo1d", time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tc := range testCases {
+		got, err := ParseLockDuration(tc.input, from)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", tc.input, err)
+			continue
+		}
+		if !got.Equal(tc.want) {
+			t.Errorf("%s: got %s, want %s", tc.input, got, tc.want)
+		}
+	}
+}
+
+func TestParseLockDuration_Invalid(t *testing.T) {
+	from := time.Date(2026, 1, 31, 10, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		input   string
+		wantErr string
+	}{
+		{"", "invalid lock duration"},
+		{"7", "invalid lock duration"},
+		{"d", "invalid lock duration"},
+		{"1.5h", "invalid lock duration"},
+		{"-3d", "invalid lock duration"},
+		{"3 d", "invalid lock duration"},
+		{"3D", "invalid lock duration"},
+		{"5min", "unknown unit"},
+		{"0d", "must be positive"},
+		{"0h0mo", "must be positive"},
+		{"99999999999999999999h", "too long"},
+		{"3000000h", "too long"},
+		{"100000w", "too long"},
+		{"100000mo", "too long"},
+	}
+
+	for _, tc := range testCases {
+		_, err := ParseLockDuration(tc.input, from)
+		if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+			t.Errorf("%q: expected error containing %q, got %v", tc.input, tc.wantErr, err)
+		}
+	}
+}
+
+func TestParseUnlockSpec(t *testing.T) {
+	now := time.Date(2026, 3, 1, 8, 30, 15, 500, time.UTC)
+	defer clock.Set(func() time.Time { return now })()
+
+	want := time.Date(2026, 3, 8, 8, 30, 15, 0, time.UTC)
+
+	// --for and relative --until resolve to the same absolute time, in whole seconds
+	for _, spec := range [][2]string{{"", "1w"}, {"+1w", ""}, {"+7d", ""}} {
+		got, err := ParseUnlockSpec(spec[0], spec[1])
+		if err != nil {
+			t.Fatalf("%v: unexpected error: %v", spec, err)
+		}
+		if !got.Equal(want) {
+			t.Errorf("%v: got %s, want %s", spec, got, want)
+		}
+	}
+
+	if got, err := ParseUnlockSpec("2026-06-15T10:00:00Z", ""); err != nil || !got.Equal(time.Date(2026, 6, 15, 10, 0, 0, 0, time.UTC)) {
+		t.Errorf("absolute --until: got %s, %v", got, err)
+	}
+
+	if _, err := ParseUnlockSpec("+1d", "1d"); err == nil || !strings.Contains(err.Error(), "cannot be used together") {
+		t.Errorf("expected --until and --for to conflict, got %v", err)
+	}
+	if _, err := ParseUnlockSpec("+", ""); err == nil || !strings.Contains(err.Error(), "invalid lock duration") {
+		t.Errorf("expected error for empty relative time, got %v", err)
+	}
+}
@@ -0,0 +1,154 @@
+package seal
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// VaultConfig selects the HashiCorp Vault server used for --vault-wrap.
+// Empty fields fall back to the standard VAULT_ADDR and VAULT_NAMESPACE environment
+// variables. The token is only read from VAULT_TOKEN and never stored.
+type VaultConfig struct {
+	Address   string `json:"address,omitempty"`
+	Namespace string `json:"namespace,omitempty"`
+}
+
+// vaultHTTPClient is used for all Vault requests. Replaced in tests.
+var vaultHTTPClient = &http.Client{Timeout: 30 * time.Second}
+
+// vaultTransitKey names a transit key, written as <mount>/keys/<name>.
+type vaultTransitKey struct {
+	Mount string
+	Name  string
+}
+
+// ParseVaultTransitKey parses a transit key path such as transit/keys/foo.
+func ParseVaultTransitKey(path string) (vaultTransitKey, error) {
+	mount, name, ok := strings.Cut(strings.Trim(path, "/"), "/keys/")
+	if !ok || mount == "" || name == "" || strings.Contains(name, "/") {
+		return vaultTransitKey{}, fmt.Errorf("invalid vault transit key %q, expected <mount>/keys/<name>", path)
+	}
+	for _, part := range strings.Split(mount, "/") {
+		if part == "" || part == "." || part == ".." {
+			return vaultTransitKey{}, fmt.Errorf("invalid vault transit key %q, expected <mount>/keys/<name>", path)
+		}
+	}
+	if name == "." || name == ".." {
+		return vaultTransitKey{}, fmt.Errorf("invalid vault transit key %q, expected <mount>/keys/<name>", path)
+	}
+	return vaultTransitKey{Mount: mount, Name: name}, nil
+}
+
+// vaultWrapDEK encrypts a DEK with a Vault transit key.
+// Returns the Vault ciphertext (vault:v1:...) as bytes, ready to be time-locked.
+func vaultWrapDEK(keyPath string, dek []byte, cfg VaultConfig) ([]byte, error) {
+	key, err := ParseVaultTransitKey(keyPath)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp struct {
+		Data struct {
+			Ciphertext string `json:"ciphertext"`
+		} `json:"data"`
+	}
+	body := map[string]string{"plaintext": base64.StdEncoding.EncodeToString(dek)}
+	if err := vaultRequest(cfg, key.Mount+"/encrypt/"+key.Name, body, &resp); err != nil {
+		return nil, fmt.Errorf("vault wrap with %s failed: %w", keyPath, err)
+	}
+	if resp.Data.Ciphertext == "" {
+		return nil, fmt.Errorf("vault wrap with %s failed: empty ciphertext", keyPath)
+	}
+
+	return []byte(resp.Data.Ciphertext), nil
+}
+
+// vaultUnwrapDEK decrypts a DEK wrapped by vaultWrapDEK.
+func vaultUnwrapDEK(keyPath string, wrapped []byte, cfg VaultConfig) ([]byte, error) {
+	key, err := ParseVaultTransitKey(keyPath)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp struct {
+		Data struct {
+			Plaintext string `json:"plaintext"`
+		} `json:"data"`
+	}
+	body := map[string]string{"ciphertext": string(wrapped)}
+	if err := vaultRequest(cfg, key.Mount+"/decrypt/"+key.Name, body, &resp); err != nil {
+		return nil, fmt.Errorf("vault unwrap with %s failed: %w", keyPath, err)
+	}
+
+	dek, err := base64.StdEncoding.DecodeString(resp.Data.Plaintext)
+	if err != nil || len(dek) == 0 {
+		return nil, fmt.Errorf("vault unwrap with %s failed: invalid plaintext in response", keyPath)
+	}
+
+	return dek, nil
+}
+
+// vaultRequest posts a JSON body to a Vault API path and decodes the response.
+// Vault's own error messages are returned so a denied policy or sealed Vault is visible.
+func vaultRequest(cfg VaultConfig, path string, body interface{}, out interface{}) error {
+	address := cfg.Address
+	if address == "" {
+		address = os.Getenv("VAULT_ADDR")
+	}
+	if address == "" {
+		return errors.New("no vault address (set VAULT_ADDR or vault.address in config)")
+	}
+
+	token := os.Getenv("VAULT_TOKEN")
+	if token == "" {
+		return errors.New("no vault token (set VAULT_TOKEN)")
+	}
+
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, strings.TrimRight(address, "/")+"/v1/"+path, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Vault-Token", token)
+
+	namespace := cfg.Namespace
+	if namespace == "" {
+		namespace = os.Getenv("VAULT_NAMESPACE")
+	}
+	if namespace != "" {
+		req.Header.Set("X-Vault-Namespace", namespace)
+	}
+
+	resp, err := vaultHTTPClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		var vaultErr struct {
+			Errors []string `json:"errors"`
+		}
+		if json.NewDecoder(resp.Body).Decode(&vaultErr) == nil && len(vaultErr.Errors) > 0 {
+			return fmt.Errorf("%d: %s", resp.StatusCode, strings.Join(vaultErr.Errors, "; "))
+		}
+		return fmt.Errorf("request failed: %d", resp.StatusCode)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("invalid response: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,160 @@
+package seal
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"seal/internal/testutil"
+	"seal/internal/timeauth"
+)
+
+// fakeTransit is a minimal Vault transit engine. Ciphertexts are only
+// opened by the server that issued them, like a real transit key.
+type fakeTransit struct {
+	keys    map[string][]byte // ciphertext -> plaintext
+	denied  bool
+	lastReq *http.Request
+}
+
+func startFakeTransit(t *testing.T) (*fakeTransit, *httptest.Server) {
+	t.Helper()
+	transit := &fakeTransit{keys: make(map[string][]byte)}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		transit.lastReq = r
+		if transit.denied || r.Header.Get("X-Vault-Token") != "s.test" {
+			w.WriteHeader(http.StatusForbidden)
+			json.NewEncoder(w).Encode(map[string][]string{"errors": {"permission denied"}})
+			return
+		}
+
+		var body map[string]string
+		json.NewDecoder(r.Body).Decode(&body)
+
+		switch r.URL.Path {
+		case "/v1/transit/encrypt/foo":
+			ciphertext := "vault:v1:" + base64.StdEncoding.EncodeToString([]byte(time.Now().String()))
+			plaintext, _ := base64.StdEncoding.DecodeString(body["plaintext"])
+			transit.keys[ciphertext] = plaintext
+			json.NewEncoder(w).Encode(map[string]any{"data": map[string]string{"ciphertext": ciphertext}})
+		case "/v1/transit/decrypt/foo":
+			plaintext, ok := transit.keys[body["ciphertext"]]
+			if !ok {
+				w.WriteHeader(http.StatusBadRequest)
+				json.NewEncoder(w).Encode(map[string][]string{"errors": {"invalid ciphertext"}})
+				return
+			}
+			json.NewEncoder(w).Encode(map[string]any{"data": map[string]string{"plaintext": base64.StdEncoding.EncodeToString(plaintext)}})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+	t.Setenv("VAULT_TOKEN", "s.test")
+
+	return transit, server
+}
+
+func TestParseVaultTransitKey(t *testing.T) {
+	tests := []struct {
+		path    string
+		want    vaultTransitKey
+		wantErr bool
+	}{
+		{"transit/keys/foo", vaultTransitKey{"transit", "foo"}, false},
+		{"team/transit/keys/escrow", vaultTransitKey{"team/transit", "escrow"}, false},
+		{"/transit/keys/foo/", vaultTransitKey{"transit", "foo"}, false},
+		{"transit/foo", vaultTransitKey{}, true},
+		{"transit/keys/", vaultTransitKey{}, true},
+		{"keys/foo", vaultTransitKey{}, true},
+		{"transit/keys/foo/bar", vaultTransitKey{}, true},
+		{"../transit/keys/foo", vaultTransitKey{}, true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseVaultTransitKey(tt.path)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseVaultTransitKey(%q) error = %v, wantErr %v", tt.path, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseVaultTransitKey(%q) = %+v, want %+v", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestVaultWrap_RequiresVaultToUnlock(t *testing.T) {
+	_, cleanup := testutil.SetupTestEnv(t)
+	defer cleanup()
+
+	transit, server := startFakeTransit(t)
+	vault := VaultConfig{Address: server.URL, Namespace: "team"}
+
+	authority := &timeauth.FakeAuthority{DefaultRound: 100, CurrentRound: 200}
+	id, err := CreateSealedItemWithOptions(time.Now().UTC().Add(time.Hour), InputSourceStdin, "", []byte("escrowed"), authority, ItemOptions{
+		VaultWrap: "transit/keys/foo",
+		Vault:     vault,
+	})
+	if err != nil {
+		t.Fatalf("CreateSealedItemWithOptions failed: %v", err)
+	}
+	if got := transit.lastReq.Header.Get("X-Vault-Namespace"); got != "team" {
+		t.Errorf("expected namespace header, got %q", got)
+	}
+
+	item, itemDir, _ := LoadItem(id)
+	if item.VaultWrap != "transit/keys/foo" {
+		t.Errorf("vault key not recorded: %q", item.VaultWrap)
+	}
+	if err := SaveConfig(Config{Vault: vault}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Time condition met but Vault refuses: the item stays sealed and is retried
+	transit.denied = true
+	item, err = TryMaterialize(item, itemDir, authority)
+	if err != nil {
+		t.Fatalf("TryMaterialize failed: %v", err)
+	}
+	if item.State != StateSealed || !strings.Contains(item.LastUnlockError, "permission denied") {
+		t.Fatalf("expected sealed item with vault error, got state %s error %q", item.State, item.LastUnlockError)
+	}
+
+	transit.denied = false
+	item.NextUnlockAttempt = nil
+	item, err = TryMaterialize(item, itemDir, authority)
+	if err != nil {
+		t.Fatalf("TryMaterialize failed: %v", err)
+	}
+	if item.State != StateUnlocked {
+		t.Fatalf("expected unlocked item, got %s", item.State)
+	}
+
+	content, _ := os.ReadFile(UnsealedPath(item, itemDir))
+	if string(content) != "escrowed" {
+		t.Errorf("got %q, want %q", content, "escrowed")
+	}
+}
+
+func TestVaultWrap_LockFailsWithoutToken(t *testing.T) {
+	_, cleanup := testutil.SetupTestEnv(t)
+	defer cleanup()
+
+	_, server := startFakeTransit(t)
+	t.Setenv("VAULT_TOKEN", "")
+
+	authority := &timeauth.FakeAuthority{DefaultRound: 100, CurrentRound: 200}
+	_, err := CreateSealedItemWithOptions(time.Now().UTC().Add(time.Hour), InputSourceStdin, "", []byte("escrowed"), authority, ItemOptions{
+		VaultWrap: "transit/keys/foo",
+		Vault:     VaultConfig{Address: server.URL},
+	})
+	if err == nil || !strings.Contains(err.Error(), "VAULT_TOKEN") {
+		t.Fatalf("expected missing token error, got %v", err)
+	}
+}
@@ -0,0 +1,115 @@
+package seal
+
+import (
+	"encoding/base64"
+	"fmt"
+	"path/filepath"
+
+	"seal/internal/timeauth"
+
+	"github.com/google/uuid"
+)
+
+// VerifyResult is the outcome of verifying one item.
+type VerifyResult struct {
+	ID       string
+	Problems []string // empty if the item passed
+}
+
+// Verify runs the checks of CheckIntegrity on one item, or on every item in the
+// store if id is empty, and parses the time-locked key of sealed drand items.
+// Unlike listings, which skip them, item directories whose metadata cannot be
+// read are reported as failed.
+// Read-only: nothing is unlocked, materialized or repaired.
+func Verify(id string) ([]VerifyResult, error) {
+	if id != "" {
+		item, itemDir, err := LoadItem(id)
+		if err != nil {
+			return nil, err
+		}
+		return []VerifyResult{{ID: id, Problems: verifyItem(item, itemDir)}}, nil
+	}
+
+	var results []VerifyResult
+	err := walkStoreDirs(func(itemDir string) error {
+		// Only item directories are named by a UUID
+		name := filepath.Base(itemDir)
+		if _, err := uuid.Parse(name); err != nil {
+			return nil
+		}
+
+		item, err := loadMetadata(itemDir)
+		if err != nil {
+			results = append(results, VerifyResult{ID: name, Problems: []string{err.Error()}})
+			return nil
+		}
+
+		results = append(results, VerifyResult{ID: item.ID, Problems: verifyItem(item, itemDir)})
+		return nil
+	})
+	return results, err
+}
+
+// verifyItem runs the checks of Verify on a loaded item.
+func verifyItem(item SealedItem, itemDir string) []string {
+	problems := checkItemIntegrity(item, itemDir)
+
+	// A drand time-locked key names its round; other authorities use other formats
+	if item.TimeAuthority == timeauth.DefaultAuthorityName && item.DEKTlockB64 != "" && item.State == StateSealed {
+		if problem := checkTlockKeyRound(item); problem != "" {
+			problems = append(problems, problem)
+		}
+	}
+	return problems
+}
+
+// checkTlockKeyRound parses a drand item's time-locked key and compares its round
+// with the item's key reference.
+func checkTlockKeyRound(item SealedItem) string {
+	data, err := base64.StdEncoding.DecodeString(item.DEKTlockB64)
+	if err != nil {
+		return "time-locked key is not valid base64"
+	}
+	round, _, err := tlockHeader(data)
+	if err != nil {
+		return fmt.Sprintf("time-locked key: %v", err)
+	}
+	targetRound, err := extractTargetRound(item.KeyRef)
+	if err != nil {
+		return err.Error()
+	}
+	if round != targetRound {
+		return fmt.Sprintf("time-locked key targets round %d but metadata records %d", round, targetRound)
+	}
+	return ""
+}
+
+// FormatVerifyOutput formats verification results, one line per item
+// followed by its problems, and a summary.
+func FormatVerifyOutput(results []VerifyResult) string {
+	return formatVerify(results, true)
+}
+
+// FormatVerifyProblems is FormatVerifyOutput without the lines of items that passed.
+func FormatVerifyProblems(results []VerifyResult) string {
+	return formatVerify(results, false)
+}
+
+func formatVerify(results []VerifyResult, showPassed bool) string {
+	result := ""
+	failed := 0
+	for _, r := range results {
+		if len(r.Problems) == 0 {
+			if showPassed {
+				result += fmt.Sprintf("%s: ok\n", r.ID)
+			}
+			continue
+		}
+		failed++
+		result += fmt.Sprintf("%s: failed\n", r.ID)
+		for _, problem := range r.Problems {
+			result += fmt.Sprintf("  %s\n", problem)
+		}
+	}
+	return result + fmt.Sprintf("verified %d items, %d failed\n", len(results), failed)
+}
@@ -0,0 +1,115 @@
+package seal
+
+import (
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"seal/internal/testutil"
+	"seal/internal/timeauth"
+)
+
+func TestVerify(t *testing.T) {
+	_, cleanup := testutil.SetupTestEnv(t)
+	defer cleanup()
+
+	authority := &timeauth.FakeAuthority{DefaultRound: 100}
+	intact, err := CreateSealedItem(time.Now().UTC().Add(time.Hour), InputSourceStdin, "", []byte("intact"), authority)
+	if err != nil {
+		t.Fatalf("CreateSealedItem failed: %v", err)
+	}
+	tampered, err := CreateSealedItem(time.Now().UTC().Add(time.Hour), InputSourceStdin, "", []byte("tampered"), authority)
+	if err != nil {
+		t.Fatalf("CreateSealedItem failed: %v", err)
+	}
+	_, itemDir, _ := LoadItem(tampered)
+	if err := os.WriteFile(filepath.Join(itemDir, "payload.bin"), []byte("flipped bits"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	// An item directory with unreadable metadata is reported, not skipped
+	baseDir, _ := GetSealBaseDir()
+	broken := "a1b2c3d4-5e6f-7890-abcd-ef1234567890"
+	os.MkdirAll(filepath.Join(baseDir, broken), 0700)
+	os.WriteFile(filepath.Join(baseDir, broken, "meta.json"), []byte("{"), 0600)
+
+	results, err := Verify("")
+	if err != nil {
+		t.Fatalf("Verify failed: %v", err)
+	}
+	problems := make(map[string][]string)
+	for _, r := range results {
+		problems[r.ID] = r.Problems
+	}
+	if len(problems) != 3 {
+		t.Fatalf("expected 3 results, got %+v", results)
+	}
+	if len(problems[intact]) != 0 {
+		t.Errorf("intact item failed: %v", problems[intact])
+	}
+	if len(problems[tampered]) != 1 || !strings.Contains(problems[tampered][0], "does not match payload_sha256") {
+		t.Errorf("expected a payload mismatch, got %v", problems[tampered])
+	}
+	if len(problems[broken]) != 1 || !strings.Contains(problems[broken][0], "failed to parse metadata") {
+		t.Errorf("expected unreadable metadata, got %v", problems[broken])
+	}
+
+	output := FormatVerifyOutput(results)
+	if !strings.Contains(output, intact+": ok\n") || !strings.Contains(output, tampered+": failed\n  payload does not match") {
+		t.Errorf("unexpected output:\n%s", output)
+	}
+	if !strings.HasSuffix(output, "verified 3 items, 2 failed\n") {
+		t.Errorf("unexpected summary:\n%s", output)
+	}
+
+	// A single item
+	results, err = Verify(intact)
+	if err != nil || len(results) != 1 || len(results[0].Problems) != 0 {
+		t.Errorf("expected the intact item to pass, got %+v, %v", results, err)
+	}
+	if _, err := Verify("f9e8d7c6-b5a4-3210-fedc-ba0987654321"); err == nil {
+		t.Error("expected an error for an unknown item")
+	}
+}
+
+func TestVerify_TimeLockedKey(t *testing.T) {
+	_, cleanup := testutil.SetupTestEnv(t)
+	defer cleanup()
+
+	authority := &timeauth.FakeAuthority{AuthorityName: timeauth.DefaultAuthorityName, DefaultRound: 100}
+	id, err := CreateSealedItem(time.Now().UTC().Add(time.Hour), InputSourceStdin, "", []byte("data"), authority)
+	if err != nil {
+		t.Fatalf("CreateSealedItem failed: %v", err)
+	}
+	item, itemDir, _ := LoadItem(id)
+
+	for name, tc := range map[string]struct {
+		key     string
+		problem string
+	}{
+		"not base64":  {"FAKE_TLOCK:not a key", "not valid base64"},
+		"not tlock":   {base64.StdEncoding.EncodeToString([]byte("age-encryption.org/v1\n-> X25519 abc\n--- mac\n")), "no tlock stanza"},
+		"wrong round": {base64.StdEncoding.EncodeToString([]byte("age-encryption.org/v1\n-> tlock 101 52db9ba7\n--- mac\n")), "targets round 101 but metadata records 100"},
+		"valid":       {base64.StdEncoding.EncodeToString([]byte("age-encryption.org/v1\n-> tlock 100 52db9ba7\n--- mac\n")), ""},
+	} {
+		item.DEKTlockB64 = tc.key
+		if err := saveMetadata(itemDir, item); err != nil {
+			t.Fatal(err)
+		}
+
+		results, err := Verify(id)
+		if err != nil {
+			t.Fatalf("%s: Verify failed: %v", name, err)
+		}
+		problems := results[0].Problems
+		if tc.problem == "" && len(problems) != 0 {
+			t.Errorf("%s: expected the key to pass, got %v", name, problems)
+		}
+		if tc.problem != "" && (len(problems) != 1 || !strings.Contains(problems[0], tc.problem)) {
+			t.Errorf("%s: expected %q, got %v", name, tc.problem, problems)
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package seal
+
+import (
+	"encoding/json"
+	"fmt"
+	"runtime"
+	"runtime/debug"
+	"sort"
+
+	"seal/internal/timeauth"
+)
+
+// Build information, set by release builds:
+//
+//	go build -ldflags "-X seal/internal/seal.Version=v1.2.0 -X seal/internal/seal.Commit=<sha> -X seal/internal/seal.BuildDate=<RFC3339>"
+//
+// Values left empty are filled from the Go build information where available.
+var (
+	Version   = ""
+	Commit    = ""
+	BuildDate = ""
+)
+
+// BuildInfo describes the running seal build.
+type BuildInfo struct {
+	Version     string            `json:"version"`
+	Commit      string            `json:"commit,omitempty"`
+	BuildDate   string            `json:"build_date,omitempty"`
+	GoVersion   string            `json:"go_version"`
+	DrandChains map[string]string `json:"drand_chains"` // pinned chain hashes by network name
+}
+
+// GetBuildInfo returns information about the running build.
+func GetBuildInfo() BuildInfo {
+	info := BuildInfo{
+		Version:     Version,
+		Commit:      Commit,
+		BuildDate:   BuildDate,
+		GoVersion:   runtime.Version(),
+		DrandChains: timeauth.PinnedDrandChains(),
+	}
+
+	if build, ok := debug.ReadBuildInfo(); ok {
+		// Set by go install seal@<version>
+		if info.Version == "" && build.Main.Version != "" && build.Main.Version != "(devel)" {
+			info.Version = build.Main.Version
+		}
+		for _, setting := range build.Settings {
+			switch {
+			case setting.Key == "vcs.revision" && info.Commit == "":
+				info.Commit = setting.Value
+			case setting.Key == "vcs.time" && info.BuildDate == "":
+				info.BuildDate = setting.Value
+			}
+		}
+	}
+
+	if info.Version == "" {
+		info.Version = "dev"
+	}
+
+	return info
+}
+
+// FormatBuildInfo formats build information for display.
+func FormatBuildInfo(info BuildInfo) string {
+	result := fmt.Sprintf("seal %s\n", info.Version)
+
+	if info.Commit != "" {
+		result += fmt.Sprintf("commit: %s\n", info.Commit)
+	}
+	if info.BuildDate != "" {
+		result += fmt.Sprintf("build_date: %s\n", info.BuildDate)
+	}
+	result += fmt.Sprintf("go_version: %s\n", info.GoVersion)
+
+	networks := make([]string, 0, len(info.DrandChains))
+	for network := range info.DrandChains {
+		networks = append(networks, network)
+	}
+	sort.Strings(networks)
+	for _, network := range networks {
+		result += fmt.Sprintf("drand_chain: %s %s\n", network, info.DrandChains[network])
+	}
+
+	return result
+}
+
+// FormatBuildInfoJSON formats build information as indented JSON.
+func FormatBuildInfoJSON(info BuildInfo) (string, error) {
+	data, err := json.MarshalIndent(info, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("cannot marshal build info: %w", err)
+	}
+	return string(data) + "\n", nil
+}
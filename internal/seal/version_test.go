@@ -0,0 +1,84 @@
+package seal
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"seal/internal/testutil"
+	"seal/internal/timeauth"
+)
+
+func TestGetBuildInfo_UsesLinkTimeVersion(t *testing.T) {
+	original := Version
+	Version = "v1.2.3"
+	defer func() { Version = original }()
+
+	info := GetBuildInfo()
+	if info.Version != "v1.2.3" {
+		t.Errorf("expected link-time version, got %q", info.Version)
+	}
+	if info.GoVersion == "" {
+		t.Error("go version missing")
+	}
+	if info.DrandChains["quicknet"] == "" {
+		t.Error("pinned quicknet chain hash missing")
+	}
+
+	text := FormatBuildInfo(info)
+	for _, want := range []string{"seal v1.2.3\n", "go_version: ", "drand_chain: quicknet "} {
+		if !strings.Contains(text, want) {
+			t.Errorf("expected %q in output, got: %s", want, text)
+		}
+	}
+
+	output, err := FormatBuildInfoJSON(info)
+	if err != nil {
+		t.Fatalf("FormatBuildInfoJSON failed: %v", err)
+	}
+	var decoded BuildInfo
+	if err := json.Unmarshal([]byte(output), &decoded); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if decoded.Version != "v1.2.3" || decoded.DrandChains["quicknet"] != info.DrandChains["quicknet"] {
+		t.Errorf("unexpected JSON build info: %+v", decoded)
+	}
+}
+
+func TestCreateSealedItem_RecordsSealAndSchemaVersion(t *testing.T) {
+	_, cleanup := testutil.SetupTestEnv(t)
+	defer cleanup()
+
+	original := Version
+	Version = "v1.2.3"
+	defer func() { Version = original }()
+
+	authority := &timeauth.FakeAuthority{DefaultRound: 100, CurrentRound: 50}
+	id, err := CreateSealedItem(time.Now().UTC().Add(time.Hour), InputSourceStdin, "", []byte("data"), authority)
+	if err != nil {
+		t.Fatalf("CreateSealedItem failed: %v", err)
+	}
+
+	item, _, _ := LoadItem(id)
+	if item.SealVersion != "v1.2.3" {
+		t.Errorf("expected seal_version v1.2.3, got %q", item.SealVersion)
+	}
+	if item.SchemaVersion != MetadataSchemaVersion {
+		t.Errorf("expected schema_version %d, got %d", MetadataSchemaVersion, item.SchemaVersion)
+	}
+
+	output := FormatInspectOutput(item, nil, false)
+	if !strings.Contains(output, "seal_version: v1.2.3\nschema_version: 1\n") {
+		t.Errorf("inspect should show creating version, got: %s", output)
+	}
+}
+
+func TestFormatInspectOutput_LegacyItemWithoutVersion(t *testing.T) {
+	item := SealedItem{ID: "legacy", State: StateSealed}
+
+	output := FormatInspectOutput(item, nil, false)
+	if !strings.Contains(output, "seal_version: unknown (not recorded)\nschema_version: 0\n") {
+		t.Errorf("expected unknown version for legacy item, got: %s", output)
+	}
+}
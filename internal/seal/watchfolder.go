@@ -0,0 +1,234 @@
+package seal
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"seal/internal/clock"
+	"seal/internal/timeauth"
+)
+
+// DefaultWatchInterval is how often a watched folder is scanned for new files.
+const DefaultWatchInterval = 2 * time.Second
+
+// WatchFolderRequest contains parameters for watching a drop folder.
+type WatchFolderRequest struct {
+	Dir         string
+	UnlockAfter time.Duration // each file unlocks this long after it is sealed
+	Shred       bool
+	Interval    time.Duration
+}
+
+// fileSnapshot records what a file looked like on the previous scan.
+type fileSnapshot struct {
+	size    int64
+	modTime time.Time
+}
+
+// folderWatcher seals files that appear in a directory.
+// Polling is used instead of platform notification APIs so behavior is identical everywhere.
+type folderWatcher struct {
+	dir         string
+	unlockAfter time.Duration
+	shred       bool
+	authority   timeauth.Authority
+	policy      *Policy // lock-time policy checked for each file (nil = none)
+	minSize     int     // files smaller than this are not sealed, from min_input_size (0 = no minimum)
+
+	// pending holds files seen on the previous scan that may still be growing
+	pending map[string]fileSnapshot
+	// ignored holds files that were present at startup or already sealed
+	ignored map[string]bool
+}
+
+// WatchFolder scans a directory and seals every file dropped into it.
+// Files present when watching starts are left untouched.
+// A file is sealed once its size and modification time are stable across two scans.
+// Calls sealed with the item ID and source path of each new item, and warn for
+// files that could not be sealed or shredded. Runs until a fatal error occurs.
+func WatchFolder(req WatchFolderRequest, sealed func(id, path string), warn func(msg string)) error {
+	if req.UnlockAfter <= 0 {
+		return errors.New("unlock duration must be positive")
+	}
+
+	interval := req.Interval
+	if interval <= 0 {
+		interval = DefaultWatchInterval
+	}
+
+	w, err := newFolderWatcher(req.Dir, req.UnlockAfter, req.Shred, timeauth.NewDefaultAuthority())
+	if err != nil {
+		return err
+	}
+
+	w.policy, err = LoadPolicy()
+	if err != nil {
+		return err
+	}
+
+	cfg, err := LoadConfig()
+	if err != nil {
+		return err
+	}
+	w.minSize = cfg.MinInputSize
+
+	for {
+		time.Sleep(interval)
+		if err := w.poll(sealed, warn); err != nil {
+			return err
+		}
+	}
+}
+
+func newFolderWatcher(dir string, unlockAfter time.Duration, shred bool, authority timeauth.Authority) (*folderWatcher, error) {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return nil, fmt.Errorf("cannot stat watch directory: %w", err)
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("not a directory: %s", dir)
+	}
+
+	w := &folderWatcher{
+		dir:         dir,
+		unlockAfter: unlockAfter,
+		shred:       shred,
+		authority:   authority,
+		pending:     make(map[string]fileSnapshot),
+		ignored:     make(map[string]bool),
+	}
+
+	// Only files dropped after startup are sealed
+	entries, err := w.scan()
+	if err != nil {
+		return nil, err
+	}
+	for name := range entries {
+		w.ignored[name] = true
+	}
+
+	return w, nil
+}
+
+// scan returns the regular, non-hidden files currently in the directory.
+func (w *folderWatcher) scan() (map[string]fileSnapshot, error) {
+	entries, err := os.ReadDir(w.dir)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read watch directory: %w", err)
+	}
+
+	files := make(map[string]fileSnapshot)
+	for _, entry := range entries {
+		// Hidden files are typically temporary files of the writing application
+		if strings.HasPrefix(entry.Name(), ".") || !entry.Type().IsRegular() {
+			continue
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			// File vanished between listing and stat
+			continue
+		}
+
+		files[entry.Name()] = fileSnapshot{size: info.Size(), modTime: info.ModTime()}
+	}
+
+	return files, nil
+}
+
+// poll performs one scan and seals files that have stopped changing.
+func (w *folderWatcher) poll(sealed func(id, path string), warn func(msg string)) error {
+	files, err := w.scan()
+	if err != nil {
+		return err
+	}
+
+	// Forget files that were removed so a new file with the same name is sealed
+	for name := range w.ignored {
+		if _, ok := files[name]; !ok {
+			delete(w.ignored, name)
+		}
+	}
+
+	next := make(map[string]fileSnapshot)
+	for name, snap := range files {
+		if w.ignored[name] {
+			continue
+		}
+
+		prev, seen := w.pending[name]
+		if !seen || prev != snap {
+			// New or still changing - check again on the next scan
+			next[name] = snap
+			continue
+		}
+
+		path := filepath.Join(w.dir, name)
+		id, err := w.sealFile(path)
+		if err != nil {
+			warn(fmt.Sprintf("warning: failed to seal %s: %v", path, err))
+		} else {
+			sealed(id, path)
+			if w.shred {
+				for _, warning := range ShredFile(path) {
+					warn(warning)
+				}
+			}
+		}
+
+		// Never retry a file automatically; it is sealed or reported once
+		w.ignored[name] = true
+	}
+
+	w.pending = next
+	return nil
+}
+
+// sealFile seals a single dropped file.
+func (w *folderWatcher) sealFile(path string) (string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", fmt.Errorf("cannot stat file: %w", err)
+	}
+
+	if info.Size() == 0 {
+		return "", errors.New("input is empty")
+	}
+
+	if info.Size() > MaxInputSize {
+		return "", fmt.Errorf("input exceeds maximum size of %d bytes", MaxInputSize)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("cannot read file: %w", err)
+	}
+
+	// A file dropped by mistake would otherwise stay sealed until the unlock time
+	if err := checkInputContent(data, w.minSize); err != nil {
+		return "", err
+	}
+
+	// The file would be shredded after sealing; never shred a time-locked file
+	if w.shred {
+		if err := checkShredSafe(path, data); err != nil {
+			return "", err
+		}
+	}
+
+	unlockTime := clock.UTC().Add(w.unlockAfter)
+	if err := w.policy.Check(PolicyRequest{
+		UnlockTime: unlockTime,
+		InputPath:  path,
+		Shred:      w.shred,
+		Authority:  w.authority.Name(),
+	}, clock.UTC()); err != nil {
+		return "", err
+	}
+
+	return CreateSealedItem(unlockTime, InputSourceFile, path, data, w.authority)
+}
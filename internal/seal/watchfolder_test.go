@@ -0,0 +1,205 @@
+package seal
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"seal/internal/testutil"
+	"seal/internal/timeauth"
+)
+
+type watchRecorder struct {
+	sealed   map[string]string // path -> id
+	warnings []string
+}
+
+func newWatchRecorder() *watchRecorder {
+	return &watchRecorder{sealed: make(map[string]string)}
+}
+
+func (r *watchRecorder) onSealed(id, path string) { r.sealed[path] = id }
+func (r *watchRecorder) onWarn(msg string)        { r.warnings = append(r.warnings, msg) }
+
+func TestFolderWatcher_SealsStableDrops(t *testing.T) {
+	_, cleanup := testutil.SetupTestEnv(t)
+	defer cleanup()
+
+	dropDir := t.TempDir()
+	w, err := newFolderWatcher(dropDir, time.Hour, false, &timeauth.FakeAuthority{DefaultRound: 1000})
+	if err != nil {
+		t.Fatalf("newFolderWatcher failed: %v", err)
+	}
+
+	rec := newWatchRecorder()
+	dropPath := filepath.Join(dropDir, "secret.txt")
+	if err := os.WriteFile(dropPath, []byte("dropped secret"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	// First scan only observes the file
+	if err := w.poll(rec.onSealed, rec.onWarn); err != nil {
+		t.Fatalf("poll failed: %v", err)
+	}
+	if len(rec.sealed) != 0 {
+		t.Fatal("file should not be sealed on first sighting")
+	}
+
+	// Second scan sees the file unchanged and seals it
+	if err := w.poll(rec.onSealed, rec.onWarn); err != nil {
+		t.Fatalf("poll failed: %v", err)
+	}
+
+	id, ok := rec.sealed[dropPath]
+	if !ok {
+		t.Fatalf("expected %s to be sealed, warnings: %v", dropPath, rec.warnings)
+	}
+
+	item, _, err := LoadItem(id)
+	if err != nil {
+		t.Fatalf("LoadItem failed: %v", err)
+	}
+	if item.InputType != "file" || item.OriginalPath != dropPath {
+		t.Errorf("unexpected item metadata: %+v", item)
+	}
+	if item.UnlockTime.Before(time.Now().UTC().Add(59 * time.Minute)) {
+		t.Errorf("unlock time should be ~1h after sealing, got %v", item.UnlockTime)
+	}
+
+	// Without --shred the file stays but is never sealed twice
+	if _, err := os.Stat(dropPath); err != nil {
+		t.Errorf("file should remain without shredding: %v", err)
+	}
+	for i := 0; i < 3; i++ {
+		w.poll(rec.onSealed, rec.onWarn)
+	}
+	items, _ := ListSealedItems(ListOptions{})
+	if len(items) != 1 {
+		t.Errorf("expected exactly 1 sealed item, got %d", len(items))
+	}
+}
+
+func TestFolderWatcher_IgnoresPreexistingAndHiddenFiles(t *testing.T) {
+	_, cleanup := testutil.SetupTestEnv(t)
+	defer cleanup()
+
+	dropDir := t.TempDir()
+	os.WriteFile(filepath.Join(dropDir, "already-here.txt"), []byte("old"), 0600)
+
+	w, err := newFolderWatcher(dropDir, time.Hour, false, &timeauth.FakeAuthority{})
+	if err != nil {
+		t.Fatalf("newFolderWatcher failed: %v", err)
+	}
+
+	os.WriteFile(filepath.Join(dropDir, ".partial"), []byte("tmp"), 0600)
+	os.Mkdir(filepath.Join(dropDir, "subdir"), 0700)
+
+	rec := newWatchRecorder()
+	for i := 0; i < 3; i++ {
+		w.poll(rec.onSealed, rec.onWarn)
+	}
+
+	if len(rec.sealed) != 0 {
+		t.Errorf("no files should be sealed, got: %v", rec.sealed)
+	}
+}
+
+func TestFolderWatcher_ShredsAfterSealing(t *testing.T) {
+	_, cleanup := testutil.SetupTestEnv(t)
+	defer cleanup()
+
+	dropDir := t.TempDir()
+	w, err := newFolderWatcher(dropDir, time.Hour, true, &timeauth.FakeAuthority{})
+	if err != nil {
+		t.Fatalf("newFolderWatcher failed: %v", err)
+	}
+
+	dropPath := filepath.Join(dropDir, "secret.txt")
+	os.WriteFile(dropPath, []byte("shred me"), 0600)
+
+	rec := newWatchRecorder()
+	w.poll(rec.onSealed, rec.onWarn)
+	w.poll(rec.onSealed, rec.onWarn)
+
+	if _, ok := rec.sealed[dropPath]; !ok {
+		t.Fatalf("expected file to be sealed, warnings: %v", rec.warnings)
+	}
+	if _, err := os.Stat(dropPath); !os.IsNotExist(err) {
+		t.Error("file should be removed after shredding")
+	}
+}
+
+func TestFolderWatcher_EmptyFileWarns(t *testing.T) {
+	_, cleanup := testutil.SetupTestEnv(t)
+	defer cleanup()
+
+	dropDir := t.TempDir()
+	w, err := newFolderWatcher(dropDir, time.Hour, true, &timeauth.FakeAuthority{})
+	if err != nil {
+		t.Fatalf("newFolderWatcher failed: %v", err)
+	}
+
+	emptyPath := filepath.Join(dropDir, "empty.txt")
+	os.WriteFile(emptyPath, nil, 0600)
+
+	rec := newWatchRecorder()
+	w.poll(rec.onSealed, rec.onWarn)
+	w.poll(rec.onSealed, rec.onWarn)
+
+	if len(rec.sealed) != 0 {
+		t.Error("empty file should not be sealed")
+	}
+	if len(rec.warnings) != 1 {
+		t.Errorf("expected one warning, got: %v", rec.warnings)
+	}
+
+	// A file that failed to seal must never be shredded
+	if _, err := os.Stat(emptyPath); err != nil {
+		t.Errorf("unsealed file must not be shredded: %v", err)
+	}
+}
+
+func TestFolderWatcher_AccidentalDropsWarn(t *testing.T) {
+	_, cleanup := testutil.SetupTestEnv(t)
+	defer cleanup()
+
+	dropDir := t.TempDir()
+	w, err := newFolderWatcher(dropDir, time.Hour, true, &timeauth.FakeAuthority{DefaultRound: 1000})
+	if err != nil {
+		t.Fatalf("newFolderWatcher failed: %v", err)
+	}
+	w.minSize = 16
+
+	blankPath := filepath.Join(dropDir, "blank.txt")
+	os.WriteFile(blankPath, []byte(" \n\n"), 0600)
+	smallPath := filepath.Join(dropDir, "small.txt")
+	os.WriteFile(smallPath, []byte("short"), 0600)
+
+	rec := newWatchRecorder()
+	w.poll(rec.onSealed, rec.onWarn)
+	w.poll(rec.onSealed, rec.onWarn)
+
+	if len(rec.sealed) != 0 {
+		t.Errorf("whitespace-only and small files should not be sealed, got %v", rec.sealed)
+	}
+	if len(rec.warnings) != 2 {
+		t.Errorf("expected a warning per file, got: %v", rec.warnings)
+	}
+
+	// Neither file was sealed, so neither is shredded
+	for _, path := range []string{blankPath, smallPath} {
+		if _, err := os.Stat(path); err != nil {
+			t.Errorf("unsealed file must not be shredded: %v", err)
+		}
+	}
+}
+
+func TestNewFolderWatcher_RejectsFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "file")
+	os.WriteFile(path, []byte("x"), 0600)
+
+	if _, err := newFolderWatcher(path, time.Hour, false, &timeauth.FakeAuthority{}); err == nil {
+		t.Error("expected error for non-directory")
+	}
+}
@@ -1,9 +1,9 @@
 package testutil
 
 import (
+	"context"
 	"bytes"
 	"encoding/base64"
-	"encoding/json"
 	"io"
 	"net/http"
 	"os"
@@ -12,6 +12,9 @@ import (
 	"strconv"
 	"strings"
 	"testing"
+	"time"
+
+	"seal/internal/timeauth/drandsim"
 )
 
 // FakeHTTPDoer is a mock HTTP client for testing.
@@ -58,39 +61,39 @@ func CloneResponse(resp *http.Response) *http.Response {
 	}
 }
 
+// drandChain is the simulated chain the fake drand responses are signed by.
+// It has the quicknet period and genesis, but its own key and chain hash.
+var drandChain = newDrandChain()
+
+func newDrandChain() *drandsim.Chain {
+	c, err := drandsim.NewChain(drandsim.Config{
+		Period:  3 * time.Second,
+		Genesis: 1677685200, // Fixed genesis time for deterministic tests
+	})
+	if err != nil {
+		panic(err)
+	}
+	return c
+}
+
+// DrandChainHash returns the chain hash of the fake drand responses.
+// Authorities reading them must pin it, since rounds are verified against it.
+func DrandChainHash() string {
+	return drandChain.ChainHash
+}
+
 // MakeDrandInfoResponse creates a fake drand /info response.
 func MakeDrandInfoResponse() *http.Response {
-	info := struct {
-		Period      int    `json:"period"`
-		GenesisTime int64  `json:"genesis_time"`
-		Hash        string `json:"hash"`
-		GroupHash   string `json:"groupHash"`
-		SchemeID    string `json:"schemeID"`
-		BeaconID    string `json:"beaconID"`
-	}{
-		Period:      3,
-		GenesisTime: 1677685200, // Fixed genesis time for deterministic tests
-		Hash:        "52db9ba70e0cc0f6eaf7803dd07447a1f5477735fd3f661792ba94600c84e971",
-		SchemeID:    "bls-unchained-on-g1",
-		BeaconID:    "quicknet",
-	}
-	body, _ := json.Marshal(info)
 	return &http.Response{
 		StatusCode: http.StatusOK,
-		Body:       io.NopCloser(bytes.NewReader(body)),
+		Body:       io.NopCloser(bytes.NewReader(drandChain.InfoJSON())),
 	}
 }
 
-// MakeDrandPublicResponse creates a fake drand /public/latest or /public/<round> response.
+// MakeDrandPublicResponse creates a fake drand /public/latest or /public/<round> response,
+// signed like a real round.
 func MakeDrandPublicResponse(round uint64) *http.Response {
-	resp := struct {
-		Round      uint64 `json:"round"`
-		Randomness string `json:"randomness"`
-	}{
-		Round:      round,
-		Randomness: "a1b2c3d4e5f6a1b2c3d4e5f6a1b2c3d4e5f6a1b2c3d4e5f6a1b2c3d4e5f6a1b2",
-	}
-	body, _ := json.Marshal(resp)
+	body, _ := drandChain.BeaconJSON(round)
 	return &http.Response{
 		StatusCode: http.StatusOK,
 		Body:       io.NopCloser(bytes.NewReader(body)),
@@ -116,7 +119,7 @@ func (f *FakeTimelockBox) Encrypt(dek []byte, targetRound uint64) (string, error
 	return "FAKE_TLOCK:" + base64.StdEncoding.EncodeToString(dek), nil
 }
 
-func (f *FakeTimelockBox) Decrypt(ciphertextB64 string) ([]byte, error) {
+func (f *FakeTimelockBox) Decrypt(ctx context.Context, ciphertextB64 string) ([]byte, error) {
 	if f.DecryptError != nil {
 		return nil, f.DecryptError
 	}
@@ -145,13 +148,16 @@ func SetupTestEnv(t *testing.T) (homeDir string, cleanup func()) {
 	
 	oldHome := os.Getenv("HOME")
 	oldXDGDataHome := os.Getenv("XDG_DATA_HOME")
+	oldDataDir := os.Getenv("SEAL_DATA_DIR")
 	
 	os.Setenv("HOME", tmpHome)
 	os.Setenv("XDG_DATA_HOME", "")
+	os.Setenv("SEAL_DATA_DIR", "") // a developer's own store must never be touched
 	
 	cleanup = func() {
 		os.Setenv("HOME", oldHome)
 		os.Setenv("XDG_DATA_HOME", oldXDGDataHome)
+		os.Setenv("SEAL_DATA_DIR", oldDataDir)
 	}
 	
 	return tmpHome, cleanup
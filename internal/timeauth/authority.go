@@ -2,6 +2,10 @@ package timeauth
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
 	"time"
 )
 
@@ -21,6 +25,17 @@ type Authority interface {
 	// Returns an error if the unlock time is invalid for this authority.
 	RoundAt(unlockTime time.Time) (uint64, error)
 
+	// RoundTime calculates the wall-clock time at which a round is published.
+	// This is the inverse of RoundAt and involves no beacon fetch.
+	// Returns an error if the authority has no fixed round schedule.
+	RoundTime(round uint64) (time.Time, error)
+
+	// EarliestUnlockTime returns the wall-clock time from which a key reference
+	// can be unlocked, e.g. when the target round is published. This can be later than
+	// the requested unlock time, since unlock happens on round boundaries.
+	// Returns an error if the reference is invalid or the authority never unlocks.
+	EarliestUnlockTime(ref KeyReference) (time.Time, error)
+
 	// Lock creates an opaque key reference for the given unlock time.
 	// Used to preserve authority-specific metadata format for backward compatibility.
 	// Returns a KeyReference that can be stored in metadata.
@@ -36,6 +51,10 @@ type Authority interface {
 	// Returns an error if the round is not yet available or decryption fails.
 	TimeLockDecrypt(ctx context.Context, ciphertextB64 string) ([]byte, error)
 
+	// LatestRound returns the most recent round published by the authority.
+	// Returns an error if the authority is unreachable or has no rounds.
+	LatestRound(ctx context.Context) (uint64, error)
+
 	// CanUnlock checks whether the specified round has been reached.
 	// Returns true if randomness for the round is available, false otherwise.
 	CanUnlock(ctx context.Context, targetRound uint64) (bool, error)
@@ -44,3 +63,68 @@ type Authority interface {
 // KeyReference is an opaque reference to authority-specific unlock information.
 // For round-based authorities, this typically encodes the target round number.
 type KeyReference string
+
+// TargetRound extracts the target round from a key reference.
+// Accepts a bare round number or the JSON form {"target_round": N}.
+func TargetRound(ref KeyReference) (uint64, error) {
+	if round, err := strconv.ParseUint(string(ref), 10, 64); err == nil {
+		return round, nil
+	}
+
+	var roundRef struct {
+		TargetRound uint64 `json:"target_round"`
+	}
+	if err := json.Unmarshal([]byte(ref), &roundRef); err != nil {
+		return 0, fmt.Errorf("failed to parse key reference: %w", err)
+	}
+
+	return roundRef.TargetRound, nil
+}
+
+// InstanceID identifies an authority instance. Authorities registered under one
+// name, such as drand on different networks, can publish different rounds, so
+// anything cached per authority must be keyed by instance rather than by name.
+func InstanceID(a Authority) string {
+	switch a := a.(type) {
+	case *DrandAuthority:
+		return a.Name() + " " + a.BaseURL
+	case *MultiAuthority:
+		members := make([]string, len(a.Members))
+		for i, member := range a.Members {
+			if member == nil {
+				members[i] = a.names[i] + " (unavailable)"
+			} else {
+				members[i] = InstanceID(member)
+			}
+		}
+		return fmt.Sprintf("%s %d of [%s]", a.Name(), a.Threshold, strings.Join(members, ", "))
+	case contextAuthority:
+		return InstanceID(a.Authority)
+	}
+	return a.Name()
+}
+
+// WithContext returns an authority whose network requests use ctx instead of the
+// context they are called with, so callers that only have context.Background can
+// still be cancelled from above. Request timeouts apply as before.
+func WithContext(ctx context.Context, a Authority) Authority {
+	return contextAuthority{Authority: a, ctx: ctx}
+}
+
+// contextAuthority is an Authority bound to a context, see WithContext.
+type contextAuthority struct {
+	Authority
+	ctx context.Context
+}
+
+func (a contextAuthority) TimeLockDecrypt(_ context.Context, ciphertextB64 string) ([]byte, error) {
+	return a.Authority.TimeLockDecrypt(a.ctx, ciphertextB64)
+}
+
+func (a contextAuthority) LatestRound(_ context.Context) (uint64, error) {
+	return a.Authority.LatestRound(a.ctx)
+}
+
+func (a contextAuthority) CanUnlock(_ context.Context, targetRound uint64) (bool, error) {
+	return a.Authority.CanUnlock(a.ctx, targetRound)
+}
@@ -0,0 +1,191 @@
+package timeauth
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/drand/drand/v2/common"
+	"github.com/drand/drand/v2/common/chain"
+	"github.com/drand/drand/v2/crypto"
+	"github.com/drand/kyber"
+	"github.com/drand/tlock"
+)
+
+// BeaconAuthority unlocks a drand item offline, from a round fetched elsewhere.
+// It knows the supplied round only and never touches the network, so it can
+// open items on an air-gapped machine but cannot lock new ones.
+type BeaconAuthority struct {
+	network DrandNetwork
+	info    *chain.Info
+	scheme  *crypto.Scheme
+	beacon  *common.Beacon
+}
+
+// NewBeaconAuthority verifies a supplied drand round for the chain a key reference
+// was locked on. data holds the chain info and the round as a relay serves them at
+// /<chain-hash>/info and /<chain-hash>/public/<round>, as consecutive JSON documents
+// in either order. The chain info must hash to the recorded chain hash and the round
+// must be signed by that chain, as for rounds fetched online.
+func NewBeaconAuthority(ref KeyReference, data []byte) (*BeaconAuthority, error) {
+	network, ok := DrandNetworkOf(ref)
+	if !ok {
+		return nil, errors.New("a supplied beacon can only unlock items time-locked to a single drand network")
+	}
+	if network.ChainHash == "" {
+		return nil, fmt.Errorf("unknown chain hash for drand network %q", network.Name)
+	}
+
+	var infoJSON []byte
+	var publicResp *drandPublicResponse
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	for {
+		var raw json.RawMessage
+		if err := decoder.Decode(&raw); err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("invalid beacon: %w", err)
+		}
+
+		var probe struct {
+			PublicKey string `json:"public_key"`
+			Signature string `json:"signature"`
+		}
+		if err := json.Unmarshal(raw, &probe); err != nil {
+			return nil, fmt.Errorf("invalid beacon: %w", err)
+		}
+
+		switch {
+		case probe.PublicKey != "":
+			infoJSON = raw
+		case probe.Signature != "":
+			publicResp = &drandPublicResponse{}
+			if err := json.Unmarshal(raw, publicResp); err != nil {
+				return nil, fmt.Errorf("invalid beacon: %w", err)
+			}
+		default:
+			return nil, errors.New("invalid beacon: expected drand chain info and a signed round")
+		}
+	}
+
+	if infoJSON == nil {
+		return nil, fmt.Errorf("beacon has no chain info; include the relay's /%s/info response", network.ChainHash)
+	}
+	if publicResp == nil {
+		return nil, errors.New("beacon has no signed round")
+	}
+
+	info, scheme, err := verifyChainInfo(infoJSON, network.Name, network.ChainHash)
+	if err != nil {
+		return nil, err
+	}
+	beacon, err := verifyBeacon(scheme, info, *publicResp)
+	if err != nil {
+		return nil, err
+	}
+
+	return &BeaconAuthority{network: network, info: info, scheme: scheme, beacon: beacon}, nil
+}
+
+// Round returns the supplied round.
+func (b *BeaconAuthority) Round() uint64 {
+	return b.beacon.Round
+}
+
+func (b *BeaconAuthority) Name() string {
+	return "drand"
+}
+
+// RoundAt is not supported: an offline authority only unlocks.
+func (b *BeaconAuthority) RoundAt(unlockTime time.Time) (uint64, error) {
+	return 0, errors.New("a supplied beacon cannot lock new items")
+}
+
+// RoundTime calculates the wall-clock time at which a round is published, from the verified chain info.
+func (b *BeaconAuthority) RoundTime(round uint64) (time.Time, error) {
+	return time.Unix(common.TimeOfRound(b.info.Period, b.info.GenesisTime, round), 0).UTC(), nil
+}
+
+// EarliestUnlockTime returns when the key reference's target round is published.
+func (b *BeaconAuthority) EarliestUnlockTime(ref KeyReference) (time.Time, error) {
+	round, err := TargetRound(ref)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return b.RoundTime(round)
+}
+
+// Lock is not supported: an offline authority only unlocks.
+func (b *BeaconAuthority) Lock(unlockTime time.Time) (KeyReference, error) {
+	return "", errors.New("a supplied beacon cannot lock new items")
+}
+
+// TimeLockEncrypt is not supported: an offline authority only unlocks.
+func (b *BeaconAuthority) TimeLockEncrypt(data []byte, targetRound uint64) (string, error) {
+	return "", errors.New("a supplied beacon cannot lock new items")
+}
+
+// TimeLockDecrypt decrypts tlock ciphertext with the supplied round's signature.
+func (b *BeaconAuthority) TimeLockDecrypt(ctx context.Context, ciphertextB64 string) ([]byte, error) {
+	tlockCiphertext, err := base64.StdEncoding.DecodeString(ciphertextB64)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode tlock ciphertext: %w", err)
+	}
+
+	var dekBuffer bytes.Buffer
+	if err := tlock.New(beaconNetwork{b}).Decrypt(&dekBuffer, bytes.NewReader(tlockCiphertext)); err != nil {
+		return nil, err
+	}
+
+	return dekBuffer.Bytes(), nil
+}
+
+// LatestRound returns the supplied round; later rounds are unknown offline.
+func (b *BeaconAuthority) LatestRound(ctx context.Context) (uint64, error) {
+	return b.beacon.Round, nil
+}
+
+// CanUnlock reports whether the supplied round has reached the target round.
+func (b *BeaconAuthority) CanUnlock(ctx context.Context, targetRound uint64) (bool, error) {
+	return b.beacon.Round >= targetRound, nil
+}
+
+// beaconNetwork is the tlock network of a BeaconAuthority: the verified chain,
+// with the supplied round as its only signature.
+type beaconNetwork struct {
+	authority *BeaconAuthority
+}
+
+func (n beaconNetwork) ChainHash() string {
+	return n.authority.network.ChainHash
+}
+
+func (n beaconNetwork) Current(time.Time) uint64 {
+	return n.authority.beacon.Round
+}
+
+func (n beaconNetwork) PublicKey() kyber.Point {
+	return n.authority.info.PublicKey
+}
+
+func (n beaconNetwork) Scheme() crypto.Scheme {
+	return *n.authority.scheme
+}
+
+func (n beaconNetwork) Signature(round uint64) ([]byte, error) {
+	if round != n.authority.beacon.Round {
+		return nil, fmt.Errorf("the supplied beacon is for round %d, not round %d", n.authority.beacon.Round, round)
+	}
+	return n.authority.beacon.Signature, nil
+}
+
+func (n beaconNetwork) SwitchChainHash(string) error {
+	return errors.New("a supplied beacon is for a single chain")
+}
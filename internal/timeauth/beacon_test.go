@@ -0,0 +1,72 @@
+package timeauth
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func testBeaconRef(chainHash string, round uint64) KeyReference {
+	ref, _ := json.Marshal(DrandKeyReference{Network: "custom", TargetRound: round, ChainHash: chainHash, Relay: defaultDrandRelay})
+	return KeyReference(ref)
+}
+
+func TestNewBeaconAuthority_VerifiesChainAndRound(t *testing.T) {
+	signed, _ := testDrandChain.BeaconJSON(1000)
+
+	// Either order, as curl writes them
+	for _, data := range [][]byte{
+		append(testDrandChain.InfoJSON(), signed...),
+		append(append([]byte{}, signed...), testDrandChain.InfoJSON()...),
+	} {
+		authority, err := NewBeaconAuthority(testBeaconRef(testDrandChain.ChainHash, 1000), data)
+		if err != nil {
+			t.Fatalf("NewBeaconAuthority failed: %v", err)
+		}
+		if authority.Round() != 1000 {
+			t.Errorf("expected round 1000, got %d", authority.Round())
+		}
+	}
+
+	// Another chain's info cannot vouch for the recorded one
+	_, err := NewBeaconAuthority(testBeaconRef(drandQuicknetChainHash, 1000), append(testDrandChain.InfoJSON(), signed...))
+	var mismatch *ChainMismatchError
+	if !errors.As(err, &mismatch) {
+		t.Errorf("expected ChainMismatchError, got %v", err)
+	}
+
+	// A round must carry a signature that verifies
+	var forged drandPublicResponse
+	json.Unmarshal(signed, &forged)
+	forged.Round = 2000
+	forgedJSON, _ := json.Marshal(forged)
+	if _, err := NewBeaconAuthority(testBeaconRef(testDrandChain.ChainHash, 2000), append(testDrandChain.InfoJSON(), forgedJSON...)); err == nil || !strings.Contains(err.Error(), "failed signature verification") {
+		t.Errorf("expected forged round to fail verification, got %v", err)
+	}
+}
+
+func TestNewBeaconAuthority_RejectsIncompleteInput(t *testing.T) {
+	signed, _ := testDrandChain.BeaconJSON(1000)
+	ref := testBeaconRef(testDrandChain.ChainHash, 1000)
+
+	testCases := []struct {
+		name    string
+		ref     KeyReference
+		data    []byte
+		wantErr string
+	}{
+		{"round only", ref, signed, "no chain info"},
+		{"chain info only", ref, testDrandChain.InfoJSON(), "no signed round"},
+		{"not json", ref, []byte("round 1000"), "invalid beacon"},
+		{"unrelated json", ref, []byte(`{"round": 1000}`), "expected drand chain info and a signed round"},
+		{"not a drand item", KeyReference("1000"), append(testDrandChain.InfoJSON(), signed...), "single drand network"},
+	}
+
+	for _, tc := range testCases {
+		_, err := NewBeaconAuthority(tc.ref, tc.data)
+		if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+			t.Errorf("%s: expected error containing %q, got %v", tc.name, tc.wantErr, err)
+		}
+	}
+}
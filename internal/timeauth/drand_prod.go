@@ -4,7 +4,15 @@ package timeauth
 
 import "net/http"
 
+// TestBuild reports whether this binary was built with the testmode tag.
+const TestBuild = false
+
 // NewDefaultDrandAuthority creates a DrandAuthority for production use.
 func NewDefaultDrandAuthority() *DrandAuthority {
 	return NewDrandAuthorityWithDeps(http.DefaultClient, nil)
 }
+
+// NewDrandAuthorityForNetwork creates a DrandAuthority for a selected network.
+func NewDrandAuthorityForNetwork(network DrandNetwork) *DrandAuthority {
+	return newDrandAuthorityForChain(network.Name, network.Relay, network.ChainHash, http.DefaultClient, nil)
+}
@@ -2,12 +2,16 @@ package timeauth
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"io"
 	"net/http"
 	"strings"
 	"testing"
 	"time"
+
+	"seal/internal/timeauth/drandsim"
 )
 
 // Test helpers to avoid import cycle with testutil
@@ -20,7 +24,11 @@ func newTestDrandAuthority(currentRound uint64) *DrandAuthority {
 		},
 	}
 
-	return NewDrandAuthorityWithDeps(fakeHTTP, &fakeTimelockBox{})
+	return newTestDrandAuthorityWithHTTP(fakeHTTP)
+}
+
+func newTestDrandAuthorityWithHTTP(httpClient HTTPDoer) *DrandAuthority {
+	return newDrandAuthorityForChain("quicknet", defaultDrandRelay, testDrandChain.ChainHash, httpClient, &fakeTimelockBox{})
 }
 
 type fakeHTTPDoer struct {
@@ -56,37 +64,35 @@ func cloneResponse(resp *http.Response) *http.Response {
 	}
 }
 
-func makeDrandInfoResponse() *http.Response {
-	info := struct {
-		Period      int    `json:"period"`
-		GenesisTime int64  `json:"genesis_time"`
-		Hash        string `json:"hash"`
-		GroupHash   string `json:"groupHash"`
-		SchemeID    string `json:"schemeID"`
-		BeaconID    string `json:"beaconID"`
-	}{
-		Period:      3,
-		GenesisTime: 1677685200,
-		Hash:        "52db9ba70e0cc0f6eaf7803dd07447a1f5477735fd3f661792ba94600c84e971",
-		SchemeID:    "bls-unchained-on-g1",
-		BeaconID:    "quicknet",
+// testDrandChain signs the fake drand responses. It has the quicknet period
+// and genesis, but its own key and chain hash.
+var testDrandChain = newTestDrandChain()
+
+func newTestDrandChain() *drandsim.Chain {
+	c, err := drandsim.NewChain(drandsim.Config{Period: 3 * time.Second, Genesis: 1677685200})
+	if err != nil {
+		panic(err)
 	}
-	body, _ := json.Marshal(info)
+	return c
+}
+
+func makeDrandInfoResponse() *http.Response {
 	return &http.Response{
 		StatusCode: http.StatusOK,
-		Body:       io.NopCloser(bytes.NewReader(body)),
+		Body:       io.NopCloser(bytes.NewReader(testDrandChain.InfoJSON())),
 	}
 }
 
 func makeDrandPublicResponse(round uint64) *http.Response {
-	resp := struct {
-		Round      uint64 `json:"round"`
-		Randomness string `json:"randomness"`
-	}{
-		Round:      round,
-		Randomness: "a1b2c3d4e5f6a1b2c3d4e5f6a1b2c3d4e5f6a1b2c3d4e5f6a1b2c3d4e5f6a1b2",
+	body, _ := testDrandChain.BeaconJSON(round)
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Body:       io.NopCloser(bytes.NewReader(body)),
 	}
-	body, _ := json.Marshal(resp)
+}
+
+func makeJSONResponse(v any) *http.Response {
+	body, _ := json.Marshal(v)
 	return &http.Response{
 		StatusCode: http.StatusOK,
 		Body:       io.NopCloser(bytes.NewReader(body)),
@@ -99,7 +105,7 @@ func (f *fakeTimelockBox) Encrypt(dek []byte, targetRound uint64) (string, error
 	return "FAKE_TLOCK:" + string(dek), nil
 }
 
-func (f *fakeTimelockBox) Decrypt(ciphertextB64 string) ([]byte, error) {
+func (f *fakeTimelockBox) Decrypt(ctx context.Context, ciphertextB64 string) ([]byte, error) {
 	if strings.HasPrefix(ciphertextB64, "FAKE_TLOCK:") {
 		return []byte(strings.TrimPrefix(ciphertextB64, "FAKE_TLOCK:")), nil
 	}
@@ -241,7 +247,7 @@ func TestDrandAuthority_NetworkFailure_DoesNotUnlock(t *testing.T) {
 		},
 	}
 	
-	authority := NewDrandAuthorityWithDeps(fakeHTTP, &fakeTimelockBox{})
+	authority := newTestDrandAuthorityWithHTTP(fakeHTTP)
 	
 	ref := DrandKeyReference{
 		Network:     "quicknet",
@@ -299,13 +305,13 @@ func TestDrandAuthority_RoundCalculation(t *testing.T) {
 	authority := newTestDrandAuthority(1000)
 	
 	// Get info from our fake
-	info, err := authority.FetchInfo()
+	info, err := authority.FetchInfo(context.Background())
 	if err != nil {
 		t.Fatalf("FetchInfo failed: %v", err)
 	}
 
 	// Create a time based on genesis + known rounds
-	// Round N starts at: genesis_time + (N * period)
+	// Round N is published at: genesis_time + ((N-1) * period)
 	testRound := uint64(1000)
 	testTime := time.Unix(info.GenesisTime+int64(testRound)*int64(info.Period), 0)
 	
@@ -329,3 +335,286 @@ func TestDrandAuthority_RoundCalculation(t *testing.T) {
 		t.Errorf("target round should be close to %d, got %d", testRound, drandRef.TargetRound)
 	}
 }
+
+func TestDrandAuthority_RoundTime_InverseOfRoundAt(t *testing.T) {
+	authority := newTestDrandAuthority(1000)
+
+	// Round N is published at genesis_time + (N-1) * period
+	roundTime, err := authority.RoundTime(1000)
+	if err != nil {
+		t.Fatalf("RoundTime failed: %v", err)
+	}
+
+	want := time.Unix(1677685200+999*3, 0).UTC()
+	if !roundTime.Equal(want) {
+		t.Errorf("expected %v, got %v", want, roundTime)
+	}
+
+	round, err := authority.RoundAt(roundTime)
+	if err != nil {
+		t.Fatalf("RoundAt failed: %v", err)
+	}
+	if round != 1000 {
+		t.Errorf("RoundAt(RoundTime(1000)) should be 1000, got %d", round)
+	}
+}
+
+func TestDrandAuthority_RoundTime_MatchesSimulator(t *testing.T) {
+	server, err := drandsim.Start(drandsim.Config{Period: 3 * time.Second, Genesis: time.Now().Add(-time.Hour).Unix()})
+	if err != nil {
+		t.Fatalf("failed to start simulator: %v", err)
+	}
+	defer server.Close()
+	authority := newDrandAuthorityForChain("drandsim", server.URL, server.ChainHash, http.DefaultClient, nil)
+
+	before := time.Now()
+	latest, err := authority.LatestRound(context.Background())
+	if err != nil {
+		t.Fatalf("LatestRound failed: %v", err)
+	}
+	after := time.Now()
+
+	// The latest round is already published; the next one is not
+	if roundTime, err := authority.RoundTime(latest); err != nil || roundTime.After(after) {
+		t.Errorf("round %d is published but RoundTime = %s, %v (now %s)", latest, roundTime, err, after)
+	}
+	if next, err := authority.RoundTime(latest + 1); err != nil || !next.After(before.Add(-time.Second)) {
+		t.Errorf("round %d is not published yet but RoundTime = %s, %v (now %s)", latest+1, next, err, before)
+	}
+
+	// A round is due from the time it is published
+	roundTime, _ := authority.RoundTime(latest)
+	if round, err := authority.RoundAt(roundTime); err != nil || round != latest {
+		t.Errorf("RoundAt(RoundTime(%d)) = %d, %v", latest, round, err)
+	}
+}
+
+func TestDrandAuthority_LatestRound(t *testing.T) {
+	authority := newTestDrandAuthority(4242)
+
+	round, err := authority.LatestRound(context.Background())
+	if err != nil {
+		t.Fatalf("LatestRound failed: %v", err)
+	}
+	if round != 4242 {
+		t.Errorf("expected round 4242, got %d", round)
+	}
+}
+
+func TestDrandAuthority_RejectsUnverifiedRounds(t *testing.T) {
+	valid := struct {
+		Round      uint64 `json:"round"`
+		Randomness string `json:"randomness"`
+		Signature  string `json:"signature"`
+	}{}
+	body, _ := testDrandChain.BeaconJSON(1000)
+	json.Unmarshal(body, &valid)
+
+	forgedRound := valid
+	forgedRound.Round = 5000 // signature is for round 1000
+
+	otherRandomness := valid
+	otherRandomness.Randomness = strings.Repeat("ab", 32)
+
+	unsigned := valid
+	unsigned.Signature = ""
+
+	testCases := []struct {
+		name    string
+		beacon  any
+		wantErr string
+	}{
+		{"round not covered by signature", forgedRound, "failed signature verification"},
+		{"randomness not derived from signature", otherRandomness, "does not match its signature"},
+		{"no signature", unsigned, "no valid signature"},
+	}
+
+	for _, tc := range testCases {
+		authority := newTestDrandAuthorityWithHTTP(&fakeHTTPDoer{
+			Responses: map[string]*http.Response{
+				"/info":          makeDrandInfoResponse(),
+				"/public/latest": makeJSONResponse(tc.beacon),
+			},
+		})
+
+		canUnlock, err := authority.CanUnlock(context.Background(), 1000)
+		if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+			t.Errorf("%s: expected error containing %q, got %v", tc.name, tc.wantErr, err)
+		}
+		if canUnlock {
+			t.Errorf("%s: must not unlock on an unverified round", tc.name)
+		}
+	}
+}
+
+func TestDrandAuthority_RejectsUnpinnedChain(t *testing.T) {
+	fakeHTTP := &fakeHTTPDoer{
+		Responses: map[string]*http.Response{
+			"/info":          makeDrandInfoResponse(),
+			"/public/latest": makeDrandPublicResponse(1000),
+		},
+	}
+	// A relay serving another chain cannot vouch for the pinned one
+	authority := NewDrandAuthorityWithDeps(fakeHTTP, &fakeTimelockBox{})
+
+	_, err := authority.LatestRound(context.Background())
+	var mismatch *ChainMismatchError
+	if !errors.As(err, &mismatch) {
+		t.Fatalf("expected ChainMismatchError, got %v", err)
+	}
+	if mismatch.Served != testDrandChain.ChainHash || mismatch.Pinned != drandQuicknetChainHash {
+		t.Errorf("unexpected mismatch: %+v", mismatch)
+	}
+}
+
+func TestDrandAuthority_FetchRoundRandomness(t *testing.T) {
+	authority := newTestDrandAuthorityWithHTTP(&fakeHTTPDoer{
+		Responses: map[string]*http.Response{
+			"/info":        makeDrandInfoResponse(),
+			"/public/1000": makeDrandPublicResponse(1000),
+			"/public/1001": makeDrandPublicResponse(1000),
+		},
+	})
+
+	randomness, err := authority.fetchRoundRandomness(context.Background(), 1000)
+	if err != nil {
+		t.Fatalf("fetchRoundRandomness failed: %v", err)
+	}
+	if len(randomness) != 32 {
+		t.Errorf("expected 32 bytes of randomness, got %d", len(randomness))
+	}
+
+	// A valid round served in place of the one asked for is rejected
+	if _, err := authority.fetchRoundRandomness(context.Background(), 1001); err == nil || !strings.Contains(err.Error(), "answered round 1001 with round 1000") {
+		t.Errorf("expected round mismatch, got %v", err)
+	}
+}
+
+// fakeRelays routes requests to a fake per relay host.
+type fakeRelays map[string]*fakeHTTPDoer
+
+func (f fakeRelays) Do(req *http.Request) (*http.Response, error) {
+	relay, ok := f[req.URL.Host]
+	if !ok {
+		return nil, io.ErrUnexpectedEOF
+	}
+	return relay.Do(req)
+}
+
+func TestDrandAuthority_FailsOverToNextRelay(t *testing.T) {
+	forged := struct {
+		Round     uint64 `json:"round"`
+		Signature string `json:"signature"`
+	}{Round: 9999, Signature: strings.Repeat("ab", 48)}
+
+	relays := fakeRelays{
+		// Serves the chain, but forges rounds
+		"forged.example": {Responses: map[string]*http.Response{
+			"/info":          makeDrandInfoResponse(),
+			"/public/latest": makeJSONResponse(forged),
+		}},
+		"good.example": {Responses: map[string]*http.Response{
+			"/info":          makeDrandInfoResponse(),
+			"/public/latest": makeDrandPublicResponse(1500),
+		}},
+	}
+
+	authority := newTestDrandAuthorityWithHTTP(relays)
+	authority.BaseURL = "https://down.example/" + authority.ChainHash
+	authority.Fallbacks = []string{
+		"https://forged.example/" + authority.ChainHash,
+		"https://good.example/" + authority.ChainHash,
+	}
+
+	round, err := authority.LatestRound(context.Background())
+	if err != nil {
+		t.Fatalf("LatestRound failed: %v", err)
+	}
+	if round != 1500 {
+		t.Errorf("expected the verified round 1500, got %d", round)
+	}
+
+	// Without a good relay, every relay's failure is reported
+	authority.Fallbacks = authority.Fallbacks[:1]
+	_, err = authority.LatestRound(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "https://down.example") || !strings.Contains(err.Error(), "failed signature verification") {
+		t.Errorf("expected errors from both relays, got %v", err)
+	}
+}
+
+func TestFallbackRelays(t *testing.T) {
+	relays := fallbackRelays(drandQuicknetChainHash, "https://api2.drand.sh")
+	if len(relays) == 0 {
+		t.Fatal("quicknet should have fallback relays")
+	}
+	for _, relay := range relays {
+		if relay == "https://api2.drand.sh" {
+			t.Error("the primary relay should not be its own fallback")
+		}
+	}
+
+	if relays := fallbackRelays(testDrandChain.ChainHash, defaultDrandRelay); len(relays) != 0 {
+		t.Errorf("unknown chains have no fallbacks, got %v", relays)
+	}
+}
+
+// flakyDoer fails the first failures requests for /public/latest with status, then serves the chain.
+type flakyDoer struct {
+	failures int
+	status   int
+	requests int
+}
+
+func (f *flakyDoer) Do(req *http.Request) (*http.Response, error) {
+	if strings.HasSuffix(req.URL.Path, "/info") {
+		return makeDrandInfoResponse(), nil
+	}
+	f.requests++
+	if f.requests <= f.failures {
+		return &http.Response{StatusCode: f.status, Body: io.NopCloser(strings.NewReader("unavailable"))}, nil
+	}
+	return makeDrandPublicResponse(1000), nil
+}
+
+func TestDrandAuthority_RetriesTransientFailures(t *testing.T) {
+	defer func(backoff time.Duration) { drandRetryBackoff = backoff }(drandRetryBackoff)
+	drandRetryBackoff = time.Millisecond
+
+	flaky := &flakyDoer{failures: drandRetries, status: http.StatusServiceUnavailable}
+	if round, err := newTestDrandAuthorityWithHTTP(flaky).LatestRound(context.Background()); err != nil || round != 1000 {
+		t.Fatalf("expected a retry to succeed, got %d, %v", round, err)
+	}
+
+	// A relay that is up but refuses the request fails the same way again
+	missing := &flakyDoer{failures: 10, status: http.StatusNotFound}
+	_, err := newTestDrandAuthorityWithHTTP(missing).LatestRound(context.Background())
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	if missing.requests != 1 {
+		t.Errorf("a 404 should not be retried, got %d requests", missing.requests)
+	}
+	if IsNetworkError(err) {
+		t.Errorf("a 404 is not a network error: %v", err)
+	}
+
+	down := &flakyDoer{failures: 10, status: http.StatusServiceUnavailable}
+	if _, err := newTestDrandAuthorityWithHTTP(down).LatestRound(context.Background()); !IsNetworkError(err) {
+		t.Errorf("an overloaded relay should be a network error, got %v", err)
+	}
+}
+
+func TestDrandAuthority_GivesUpAtNetworkTimeout(t *testing.T) {
+	defer func(timeout time.Duration) { NetworkTimeout = timeout }(NetworkTimeout)
+	NetworkTimeout = 50 * time.Millisecond
+
+	flaky := &flakyDoer{failures: 100, status: http.StatusServiceUnavailable}
+	start := time.Now()
+	_, err := newTestDrandAuthorityWithHTTP(flaky).LatestRound(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "gave up after") {
+		t.Errorf("expected to give up, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("took %s, should stop at the network timeout", elapsed)
+	}
+}
@@ -3,79 +3,88 @@
 package timeauth
 
 import (
-	"bytes"
-	"encoding/base64"
-	"encoding/json"
-	"io"
+	"fmt"
 	"net/http"
-	"strings"
+	"os"
+	"strconv"
+	"sync"
 	"time"
+
+	"seal/internal/timeauth/drandsim"
 )
 
-// testModeHTTPDoer is a mock HTTP client for test mode.
-type testModeHTTPDoer struct{}
+// TestBuild reports whether this binary was built with the testmode tag.
+const TestBuild = true
 
-func (t *testModeHTTPDoer) Do(req *http.Request) (*http.Response, error) {
-	path := req.URL.Path
+// Environment variables configuring the localhost drand simulator in test mode.
+const (
+	testModePeriodEnv  = "SEAL_TESTMODE_DRAND_PERIOD"  // round period in seconds (default 3)
+	testModeGenesisEnv = "SEAL_TESTMODE_DRAND_GENESIS" // unix time of round 1 (default quicknet genesis)
+	testModeSkewEnv    = "SEAL_TESTMODE_DRAND_SKEW"    // Go duration the simulated clock runs ahead
+)
 
-	// Handle /info endpoint
-	if strings.HasSuffix(path, "/info") {
-		info := DrandInfo{
-			Period:      3,
-			GenesisTime: 1677685200,
-			Hash:        drandQuicknetChainHash,
-			SchemeID:    "bls-unchained-on-g1",
-			BeaconID:    "quicknet",
-		}
-		body, _ := json.Marshal(info)
-		return &http.Response{
-			StatusCode: http.StatusOK,
-			Body:       io.NopCloser(bytes.NewReader(body)),
-		}, nil
+var (
+	testModeOnce   sync.Once
+	testModeServer *drandsim.Server
+	testModeErr    error
+)
+
+// testModeConfig reads the simulator configuration from the environment.
+func testModeConfig() (drandsim.Config, error) {
+	config := drandsim.Config{
+		Period:  3 * time.Second,
+		Genesis: 1677685200,
 	}
 
-	// Handle /public/latest endpoint
-	if strings.HasSuffix(path, "/public/latest") {
-		// Calculate current round based on real time
-		// Genesis: 1677685200 (2023-03-01 13:00:00 UTC), Period: 3 seconds
-		genesisTime := int64(1677685200)
-		period := int64(3)
-		now := time.Now().Unix()
-		currentRound := uint64((now - genesisTime) / period)
-		
-		resp := drandPublicResponse{
-			Round:      currentRound,
-			Randomness: "a1b2c3d4e5f6a1b2c3d4e5f6a1b2c3d4e5f6a1b2c3d4e5f6a1b2c3d4e5f6a1b2",
+	if value := os.Getenv(testModePeriodEnv); value != "" {
+		seconds, err := strconv.Atoi(value)
+		if err != nil {
+			return config, fmt.Errorf("invalid %s: %w", testModePeriodEnv, err)
 		}
-		body, _ := json.Marshal(resp)
-		return &http.Response{
-			StatusCode: http.StatusOK,
-			Body:       io.NopCloser(bytes.NewReader(body)),
-		}, nil
+		config.Period = time.Duration(seconds) * time.Second
 	}
 
-	// Default: return 404
-	return &http.Response{
-		StatusCode: http.StatusNotFound,
-		Body:       io.NopCloser(strings.NewReader("not found")),
-	}, nil
-}
+	if value := os.Getenv(testModeGenesisEnv); value != "" {
+		genesis, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return config, fmt.Errorf("invalid %s: %w", testModeGenesisEnv, err)
+		}
+		config.Genesis = genesis
+	}
 
-// testModeTimelockBox is a fake tlock implementation for test mode.
-type testModeTimelockBox struct{}
+	if value := os.Getenv(testModeSkewEnv); value != "" {
+		skew, err := time.ParseDuration(value)
+		if err != nil {
+			return config, fmt.Errorf("invalid %s: %w", testModeSkewEnv, err)
+		}
+		config.Skew = skew
+	}
 
-func (t *testModeTimelockBox) Encrypt(dek []byte, targetRound uint64) (string, error) {
-	return "TESTMODE_TLOCK:" + base64.StdEncoding.EncodeToString(dek), nil
+	return config, nil
 }
 
-func (t *testModeTimelockBox) Decrypt(ciphertextB64 string) ([]byte, error) {
-	if strings.HasPrefix(ciphertextB64, "TESTMODE_TLOCK:") {
-		return base64.StdEncoding.DecodeString(strings.TrimPrefix(ciphertextB64, "TESTMODE_TLOCK:"))
+// NewDefaultDrandAuthority creates a DrandAuthority for test mode.
+// The first call starts an in-process drand simulator on localhost; every
+// authority in the process talks to it over real HTTP and real tlock.
+func NewDefaultDrandAuthority() *DrandAuthority {
+	testModeOnce.Do(func() {
+		config, err := testModeConfig()
+		if err != nil {
+			testModeErr = err
+			return
+		}
+		testModeServer, testModeErr = drandsim.Start(config)
+	})
+
+	if testModeErr != nil {
+		panic(fmt.Sprintf("testmode drand simulator: %v", testModeErr))
 	}
-	return nil, io.ErrUnexpectedEOF
+
+	return newDrandAuthorityForChain("drandsim", testModeServer.URL, testModeServer.ChainHash, http.DefaultClient, nil)
 }
 
-// NewDefaultDrandAuthority creates a DrandAuthority for test mode.
-func NewDefaultDrandAuthority() *DrandAuthority {
-	return NewDrandAuthorityWithDeps(&testModeHTTPDoer{}, &testModeTimelockBox{})
+// NewDrandAuthorityForNetwork creates a DrandAuthority for test mode.
+// A test binary has a single simulated network, so every selection uses it.
+func NewDrandAuthorityForNetwork(network DrandNetwork) *DrandAuthority {
+	return NewDefaultDrandAuthority()
 }
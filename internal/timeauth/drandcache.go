@@ -0,0 +1,165 @@
+package timeauth
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+
+	"seal/internal/clock"
+)
+
+// CacheDir is where drand chain info and the latest round seen are kept between
+// runs, in files named by chain hash. Empty, the default, disables the cache.
+// The CLI sets it to a directory in the store.
+//
+// Nothing read from the cache is trusted more than the network: chain info is
+// verified against the pinned chain hash again, and a round is only a hint, since
+// unlocking still fetches and verifies the round's signature.
+var CacheDir string
+
+// Kinds of cache file, see DrandAuthority.cachePath.
+const (
+	infoCacheKind  = "info"
+	roundCacheKind = "round"
+)
+
+// notDueMargin is how far in the future, by the local clock, a round must be
+// scheduled to be taken as unpublished without asking a relay. It absorbs a
+// local clock running slow; a clock slower than this delays unlocks by the difference.
+const notDueMargin = time.Minute
+
+// cachedRound is the latest round seen on a chain.
+type cachedRound struct {
+	Round  uint64    `json:"round"`
+	SeenAt time.Time `json:"seen_at"` // wall clock
+}
+
+// cachePath returns the cache file of a kind for this chain, or "" without a cache.
+func (d *DrandAuthority) cachePath(kind string) string {
+	if CacheDir == "" {
+		return ""
+	}
+	if _, err := hex.DecodeString(d.ChainHash); err != nil || d.ChainHash == "" {
+		return ""
+	}
+	return filepath.Join(CacheDir, d.ChainHash+"."+kind+".json")
+}
+
+// readCache returns the content of a cache file, if there is one.
+func (d *DrandAuthority) readCache(kind string) ([]byte, bool) {
+	path := d.cachePath(kind)
+	if path == "" {
+		return nil, false
+	}
+	data, err := os.ReadFile(path)
+	return data, err == nil
+}
+
+// writeCache replaces a cache file (best-effort). The cache directory is created
+// only inside an existing store, never the store itself.
+func (d *DrandAuthority) writeCache(kind string, data []byte) {
+	path := d.cachePath(kind)
+	if path == "" {
+		return
+	}
+	if err := os.Mkdir(CacheDir, 0700); err != nil && !os.IsExist(err) {
+		return
+	}
+
+	file, err := os.CreateTemp(CacheDir, "."+filepath.Base(path)+"-*.tmp")
+	if err != nil {
+		return
+	}
+	_, err = file.Write(data)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil || os.Rename(file.Name(), path) != nil {
+		os.Remove(file.Name())
+	}
+}
+
+// loadRound returns the latest round seen on this chain by any run.
+func (d *DrandAuthority) loadRound() (cachedRound, bool) {
+	data, ok := d.readCache(roundCacheKind)
+	if !ok {
+		return cachedRound{}, false
+	}
+	var cached cachedRound
+	if err := json.Unmarshal(data, &cached); err != nil || cached.Round == 0 {
+		return cachedRound{}, false
+	}
+	return cached, true
+}
+
+// saveRound records a round just fetched, unless a later one is already recorded.
+func (d *DrandAuthority) saveRound(round uint64) {
+	if cached, ok := d.loadRound(); ok && cached.Round > round {
+		return
+	}
+	data, err := json.Marshal(cachedRound{Round: round, SeenAt: clock.UTC()})
+	if err == nil {
+		d.writeCache(roundCacheKind, data)
+	}
+}
+
+// knownRound returns the latest round seen on this chain, however long ago.
+// Every round up to it is published: rounds never go back.
+func (d *DrandAuthority) knownRound() (uint64, bool) {
+	cached, ok := d.loadRound()
+	return cached.Round, ok
+}
+
+// freshRound returns the latest round seen on this chain if it was seen less than
+// one period ago, so no later round can have been published since.
+func (d *DrandAuthority) freshRound() (uint64, bool) {
+	cached, ok := d.loadRound()
+	info := d.cachedInfo()
+	if !ok || info == nil {
+		return 0, false
+	}
+	age := clock.Since(cached.SeenAt)
+	if age < 0 || age >= time.Duration(info.Period)*time.Second {
+		return 0, false
+	}
+	return cached.Round, true
+}
+
+// recentRound returns the latest round this instance fetched, if that was less
+// than one period ago by the monotonic clock.
+func (d *DrandAuthority) recentRound() (uint64, bool) {
+	if d.latestAt.Wall.IsZero() || d.info == nil {
+		return 0, false
+	}
+	if d.latestAt.Elapsed() >= time.Duration(d.info.Period)*time.Second {
+		return 0, false
+	}
+	return d.latest, true
+}
+
+// notDueYet reports whether targetRound is scheduled more than notDueMargin
+// after the local time, using cached chain info. Only with a cache: without
+// one, the relay is always asked.
+func (d *DrandAuthority) notDueYet(targetRound uint64) bool {
+	if d.cachePath(infoCacheKind) == "" || d.cachedInfo() == nil {
+		return false
+	}
+	at, err := d.RoundTime(targetRound)
+	if err != nil {
+		return false
+	}
+	return clock.Until(at) > notDueMargin
+}
+
+// cachedInfo returns the chain info, loading it from the cache if this authority
+// has not fetched it yet. Returns nil if neither has it.
+func (d *DrandAuthority) cachedInfo() *DrandInfo {
+	if d.info == nil {
+		if body, ok := d.readCache(infoCacheKind); ok {
+			d.setInfo(body)
+		}
+	}
+	return d.info
+}
@@ -0,0 +1,129 @@
+package timeauth
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"seal/internal/clock"
+)
+
+func useCacheDir(t *testing.T) string {
+	t.Helper()
+	dir := filepath.Join(t.TempDir(), "store", ".cache")
+	prev := CacheDir
+	CacheDir = dir
+	t.Cleanup(func() { CacheDir = prev })
+	return dir
+}
+
+func TestDrandCache_WorksOffline(t *testing.T) {
+	dir := useCacheDir(t)
+
+	// Without an existing parent, nothing is created
+	if _, err := newTestDrandAuthority(1000).LatestRound(context.Background()); err != nil {
+		t.Fatalf("LatestRound failed: %v", err)
+	}
+	if _, err := os.Stat(dir); !os.IsNotExist(err) {
+		t.Fatal("the cache must not create the store")
+	}
+
+	if err := os.MkdirAll(filepath.Dir(dir), 0700); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := newTestDrandAuthority(1000).LatestRound(context.Background()); err != nil {
+		t.Fatalf("LatestRound failed: %v", err)
+	}
+
+	offline := newTestDrandAuthorityWithHTTP(&fakeHTTPDoer{Errors: map[string]error{"/": errors.New("network down")}})
+	ctx := context.Background()
+
+	// Round math from cached chain info
+	if _, err := offline.RoundTime(1200); err != nil {
+		t.Errorf("expected RoundTime from cached chain info, got %v", err)
+	}
+	// The round seen less than a period ago
+	if round, err := offline.LatestRound(ctx); err != nil || round != 1000 {
+		t.Errorf("expected the cached round 1000, got %d, %v", round, err)
+	}
+	// Rounds up to the one seen are published
+	if ok, err := offline.CanUnlock(ctx, 900); err != nil || !ok {
+		t.Errorf("expected round 900 to be published, got %v, %v", ok, err)
+	}
+	// A round an hour away by the local clock is not
+	later, _ := offline.RoundAt(time.Now().Add(time.Hour))
+	if ok, err := offline.CanUnlock(ctx, later); err != nil || ok {
+		t.Errorf("expected a round an hour away to be unpublished without a request, got %v, %v", ok, err)
+	}
+	// A round that may be out needs the relay
+	now, _ := offline.RoundAt(time.Now())
+	if _, err := offline.CanUnlock(ctx, now); err == nil {
+		t.Error("expected a plausibly published round to need the relay")
+	}
+
+	// One period later the cached round is stale
+	defer clock.Set(func() time.Time { return time.Now().Add(10 * time.Second) })()
+	if _, err := offline.LatestRound(ctx); err == nil {
+		t.Error("expected a stale round to need the relay")
+	}
+}
+
+func TestDrandCache_IgnoresUnverifiedInfo(t *testing.T) {
+	dir := useCacheDir(t)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		t.Fatal(err)
+	}
+
+	authority := newTestDrandAuthority(1000)
+	path := filepath.Join(dir, authority.ChainHash+".info.json")
+	if err := os.WriteFile(path, newTestDrandChain().InfoJSON(), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	// Chain info of another chain is refetched, and replaced with the pinned chain's
+	if _, err := authority.FetchInfo(context.Background()); err != nil {
+		t.Fatalf("FetchInfo failed: %v", err)
+	}
+	if authority.info.Hash != authority.ChainHash {
+		t.Errorf("expected the pinned chain, got %s", authority.info.Hash)
+	}
+	offline := newTestDrandAuthorityWithHTTP(&fakeHTTPDoer{Responses: map[string]*http.Response{}})
+	if _, err := offline.FetchInfo(context.Background()); err != nil {
+		t.Errorf("expected the replaced cache to verify, got %v", err)
+	}
+}
+
+func TestDrandAuthority_CanUnlockSharesRecentFetch(t *testing.T) {
+	relay := &countingDoer{fakeHTTPDoer: fakeHTTPDoer{Responses: map[string]*http.Response{
+		"/info":          makeDrandInfoResponse(),
+		"/public/latest": makeDrandPublicResponse(1000),
+	}}}
+	authority := newTestDrandAuthorityWithHTTP(relay)
+
+	for _, round := range []uint64{900, 1000, 1001, 1100} {
+		if ok, err := authority.CanUnlock(context.Background(), round); err != nil || ok != (round <= 1000) {
+			t.Errorf("CanUnlock(%d) = %v, %v", round, ok, err)
+		}
+	}
+	if relay.latest != 1 {
+		t.Errorf("expected one latest round fetch, got %d", relay.latest)
+	}
+}
+
+// countingDoer counts latest round requests.
+type countingDoer struct {
+	fakeHTTPDoer
+	latest int
+}
+
+func (c *countingDoer) Do(req *http.Request) (*http.Response, error) {
+	if strings.HasSuffix(req.URL.Path, "/public/latest") {
+		c.latest++
+	}
+	return c.fakeHTTPDoer.Do(req)
+}
@@ -0,0 +1,191 @@
+// Package drandsim serves a simulated drand network over localhost.
+//
+// The simulator publishes unchained rounds on the quicknet scheme
+// (BLS signatures on G1), so the real drand HTTP client and tlock can
+// encrypt to it and decrypt from it. Rounds are signed with a fixed,
+// publicly known key: anything time-locked to the simulator can be opened
+// by anyone. It exists only for testmode builds and tests.
+package drandsim
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/drand/drand/v2/common"
+	"github.com/drand/drand/v2/common/chain"
+	"github.com/drand/drand/v2/crypto"
+	"github.com/drand/kyber"
+)
+
+// keySeed derives the simulator's signing key. A fixed key keeps the chain
+// hash stable, so separate processes serve the same chain.
+const keySeed = "seal drandsim signing key"
+
+// beaconID is advertised in chain info and mixed into the chain hash.
+const beaconID = "drandsim"
+
+// Config describes the simulated chain.
+type Config struct {
+	Period  time.Duration // round period, a whole number of seconds
+	Genesis int64         // unix time of round 1
+	Skew    time.Duration // how far the simulated clock runs ahead of the wall clock
+}
+
+// Chain is the simulated chain without a server: its info and signed rounds.
+// Tests that fake the drand HTTP API use it to serve rounds that verify.
+type Chain struct {
+	ChainHash string // hex chain hash
+
+	config  Config
+	scheme  *crypto.Scheme
+	private kyber.Scalar
+	info    *chain.Info
+}
+
+// Server is a running simulator.
+type Server struct {
+	*Chain
+	URL string // base URL, without the chain hash
+
+	server *http.Server
+}
+
+type beaconResponse struct {
+	Round      uint64 `json:"round"`
+	Randomness string `json:"randomness"`
+	Signature  string `json:"signature"`
+}
+
+// NewChain creates the simulated chain for config.
+func NewChain(config Config) (*Chain, error) {
+	if config.Period < time.Second || config.Period%time.Second != 0 {
+		return nil, fmt.Errorf("period must be a whole number of seconds, got %s", config.Period)
+	}
+	if config.Genesis <= 0 {
+		return nil, errors.New("genesis must be a positive unix time")
+	}
+
+	scheme := crypto.NewPedersenBLSUnchainedG1()
+	seed := sha256.Sum256([]byte(keySeed))
+	private := scheme.KeyGroup.Scalar().SetBytes(seed[:])
+
+	info := &chain.Info{
+		PublicKey:   scheme.KeyGroup.Point().Mul(private, nil),
+		ID:          beaconID,
+		Period:      config.Period,
+		Scheme:      scheme.Name,
+		GenesisTime: config.Genesis,
+		GenesisSeed: seed[:],
+	}
+
+	return &Chain{
+		ChainHash: info.HashString(),
+		config:    config,
+		scheme:    scheme,
+		private:   private,
+		info:      info,
+	}, nil
+}
+
+// Start serves the simulated chain on a random localhost port.
+func Start(config Config) (*Server, error) {
+	c, err := NewChain(config)
+	if err != nil {
+		return nil, err
+	}
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen on localhost: %w", err)
+	}
+
+	s := &Server{
+		Chain: c,
+		URL:   "http://" + listener.Addr().String(),
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /"+s.ChainHash+"/info", s.handleInfo)
+	mux.HandleFunc("GET /"+s.ChainHash+"/public/{round}", s.handlePublic)
+	s.server = &http.Server{Handler: mux}
+
+	go s.server.Serve(listener)
+
+	return s, nil
+}
+
+// Close stops the simulator.
+func (s *Server) Close() error {
+	return s.server.Close()
+}
+
+// LatestRound returns the most recent round the simulator publishes.
+func (c *Chain) LatestRound() uint64 {
+	now := time.Now().Add(c.config.Skew).Unix()
+	return common.CurrentRound(now, c.config.Period, c.config.Genesis)
+}
+
+// InfoJSON returns the chain info as served at /<hash>/info.
+func (c *Chain) InfoJSON() []byte {
+	var buf bytes.Buffer
+	c.info.ToJSON(&buf, nil)
+	return buf.Bytes()
+}
+
+// BeaconJSON returns a signed round as served at /<hash>/public/<round>,
+// whether or not the simulated clock has reached it.
+func (c *Chain) BeaconJSON(round uint64) ([]byte, error) {
+	signature, err := c.scheme.AuthScheme.Sign(c.private, c.scheme.DigestBeacon(&common.Beacon{Round: round}))
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(beaconResponse{
+		Round:      round,
+		Randomness: hex.EncodeToString(crypto.RandomnessFromSignature(signature)),
+		Signature:  hex.EncodeToString(signature),
+	})
+}
+
+func (s *Server) handleInfo(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(s.InfoJSON())
+}
+
+// handlePublic serves a signed round, or "latest". Rounds past the
+// simulated clock are not yet published.
+func (s *Server) handlePublic(w http.ResponseWriter, r *http.Request) {
+	latest := s.LatestRound()
+
+	round := latest
+	if value := r.PathValue("round"); value != "latest" {
+		parsed, err := strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			http.Error(w, "invalid round", http.StatusBadRequest)
+			return
+		}
+		round = parsed
+	}
+
+	if round == 0 || round > latest {
+		http.Error(w, "round not yet published", http.StatusNotFound)
+		return
+	}
+
+	body, err := s.BeaconJSON(round)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(body)
+}
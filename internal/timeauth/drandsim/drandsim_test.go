@@ -0,0 +1,78 @@
+package drandsim_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"seal/internal/timeauth"
+	"seal/internal/timeauth/drandsim"
+)
+
+func startServer(t *testing.T, config drandsim.Config) *drandsim.Server {
+	t.Helper()
+
+	server, err := drandsim.Start(config)
+	if err != nil {
+		t.Fatalf("failed to start simulator: %v", err)
+	}
+	t.Cleanup(func() { server.Close() })
+
+	return server
+}
+
+func TestStart_RejectsFractionalPeriod(t *testing.T) {
+	if _, err := drandsim.Start(drandsim.Config{Period: 1500 * time.Millisecond, Genesis: 1}); err == nil {
+		t.Error("expected error for fractional period")
+	}
+}
+
+func TestStart_ChainHashIsStable(t *testing.T) {
+	config := drandsim.Config{Period: time.Second, Genesis: 1677685200}
+
+	first := startServer(t, config)
+	second := startServer(t, drandsim.Config{Period: time.Second, Genesis: 1677685200, Skew: time.Hour})
+
+	if first.ChainHash != second.ChainHash {
+		t.Errorf("chain hash should not depend on the process or skew: %s vs %s", first.ChainHash, second.ChainHash)
+	}
+	if first.URL == second.URL {
+		t.Error("each simulator should listen on its own port")
+	}
+
+	other := startServer(t, drandsim.Config{Period: 2 * time.Second, Genesis: 1677685200})
+	if other.ChainHash == first.ChainHash {
+		t.Error("chain hash should change with the period")
+	}
+}
+
+func TestTimelock_RoundTripThroughRealTlock(t *testing.T) {
+	config := drandsim.Config{Period: time.Second, Genesis: time.Now().Add(-time.Minute).Unix()}
+
+	server := startServer(t, config)
+	box := &timeauth.RealTimelockBox{BaseURL: server.URL, ChainHash: server.ChainHash}
+
+	targetRound := server.LatestRound() + 60
+	ciphertext, err := box.Encrypt([]byte("dek"), targetRound)
+	if err != nil {
+		t.Fatalf("Encrypt failed: %v", err)
+	}
+
+	if _, err := box.Decrypt(context.Background(), ciphertext); err == nil {
+		t.Fatal("decrypt should fail before the target round is published")
+	}
+
+	// A simulator running ahead publishes the round without waiting
+	config.Skew = 2 * time.Minute
+	ahead := startServer(t, config)
+	aheadBox := &timeauth.RealTimelockBox{BaseURL: ahead.URL, ChainHash: ahead.ChainHash}
+
+	plaintext, err := aheadBox.Decrypt(context.Background(), ciphertext)
+	if err != nil {
+		t.Fatalf("Decrypt failed once the round is published: %v", err)
+	}
+	if !bytes.Equal(plaintext, []byte("dek")) {
+		t.Errorf("got %q, want %q", plaintext, "dek")
+	}
+}
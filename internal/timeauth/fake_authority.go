@@ -40,6 +40,14 @@ type FakeAuthority struct {
 
 	// CurrentRound is the current round for CanUnlock checks
 	CurrentRound uint64
+
+	// GenesisTime and Period define the round schedule for RoundTime, as for drand:
+	// round 1 at GenesisTime. Period defaults to 3 seconds if zero.
+	GenesisTime time.Time
+	Period      time.Duration
+
+	// RoundTimeError simulates round schedule failures
+	RoundTimeError error
 }
 
 func (f *FakeAuthority) Name() string {
@@ -63,6 +71,31 @@ func (f *FakeAuthority) RoundAt(unlockTime time.Time) (uint64, error) {
 	return f.DefaultRound, nil
 }
 
+func (f *FakeAuthority) RoundTime(round uint64) (time.Time, error) {
+	if f.RoundTimeError != nil {
+		return time.Time{}, f.RoundTimeError
+	}
+
+	period := f.Period
+	if period == 0 {
+		period = 3 * time.Second
+	}
+
+	if round == 0 {
+		return f.GenesisTime.UTC(), nil
+	}
+	return f.GenesisTime.Add(time.Duration(round-1) * period).UTC(), nil
+}
+
+func (f *FakeAuthority) EarliestUnlockTime(ref KeyReference) (time.Time, error) {
+	round, err := TargetRound(ref)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return f.RoundTime(round)
+}
+
 func (f *FakeAuthority) TimeLockEncrypt(data []byte, targetRound uint64) (string, error) {
 	if f.EncryptError != nil {
 		return "", f.EncryptError
@@ -85,6 +118,14 @@ func (f *FakeAuthority) TimeLockDecrypt(ctx context.Context, ciphertextB64 strin
 	return nil, fmt.Errorf("invalid fake tlock ciphertext")
 }
 
+func (f *FakeAuthority) LatestRound(ctx context.Context) (uint64, error) {
+	if f.CanUnlockError != nil {
+		return 0, f.CanUnlockError
+	}
+
+	return f.CurrentRound, nil
+}
+
 func (f *FakeAuthority) CanUnlock(ctx context.Context, targetRound uint64) (bool, error) {
 	if f.CanUnlockError != nil {
 		return false, f.CanUnlockError
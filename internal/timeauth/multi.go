@@ -0,0 +1,418 @@
+package timeauth
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// MultiAuthorityName is the registered name of the threshold authority.
+const MultiAuthorityName = "multi"
+
+// MultiAuthority time-locks a key to k of n member authorities, e.g. drand
+// networks run by different operators. The key is split with Shamir secret sharing
+// and each share is time-locked with one member, so unlocking needs k members to
+// agree the unlock time has passed, and no k-1 of them can unlock early.
+//
+// Members have different round schedules, so a multi authority counts rounds in
+// Unix seconds: the target round of a threshold item is its unlock time.
+type MultiAuthority struct {
+	Threshold int
+
+	// Members are the member authorities, in key reference order.
+	// A member whose authority is not registered in this build is nil and never unlocks.
+	Members []Authority
+
+	names []string // member authority names
+}
+
+// MultiKeyReference is the key reference of a threshold item.
+type MultiKeyReference struct {
+	TargetRound uint64               `json:"target_round"` // unlock time, Unix seconds
+	Threshold   int                  `json:"threshold"`
+	Members     []MemberKeyReference `json:"members"`
+}
+
+// MemberKeyReference records one member authority of a threshold item.
+type MemberKeyReference struct {
+	Authority string       `json:"authority"`
+	KeyRef    KeyReference `json:"key_ref"`
+}
+
+// multiShares is the time-locked key of a threshold item (base64 JSON):
+// one share per member, in member order, each time-locked by that member.
+type multiShares struct {
+	Threshold int          `json:"threshold"`
+	Shares    []multiShare `json:"shares"`
+}
+
+type multiShare struct {
+	X        byte   `json:"x"` // Shamir evaluation point
+	TlockB64 string `json:"tlock_b64"`
+}
+
+// NewMultiAuthority creates a threshold authority unlocking with threshold of members.
+func NewMultiAuthority(threshold int, members ...Authority) (*MultiAuthority, error) {
+	if len(members) < 2 {
+		return nil, errors.New("multi authority needs at least two members")
+	}
+	if len(members) > 255 {
+		return nil, errors.New("multi authority supports at most 255 members")
+	}
+	if threshold < 1 || threshold > len(members) {
+		return nil, fmt.Errorf("threshold must be between 1 and %d (the number of members), got %d", len(members), threshold)
+	}
+
+	names := make([]string, len(members))
+	for i, member := range members {
+		if member == nil {
+			return nil, fmt.Errorf("multi authority member %d is missing", i+1)
+		}
+		names[i] = member.Name()
+	}
+	return &MultiAuthority{Threshold: threshold, Members: members, names: names}, nil
+}
+
+// NewMultiAuthorityForRef returns the threshold authority a key reference was locked with.
+// Members are created from their recorded key references.
+func NewMultiAuthorityForRef(ref KeyReference) (*MultiAuthority, error) {
+	multiRef, err := parseMultiKeyReference(ref)
+	if err != nil {
+		return nil, err
+	}
+
+	m := &MultiAuthority{Threshold: multiRef.Threshold}
+	for _, member := range multiRef.Members {
+		m.Members = append(m.Members, AuthorityForRef(member.Authority, member.KeyRef))
+		m.names = append(m.names, member.Authority)
+	}
+	return m, nil
+}
+
+// MultiKeyReferenceOf returns the threshold key reference recorded in ref.
+// Returns false if ref is not one.
+func MultiKeyReferenceOf(ref KeyReference) (MultiKeyReference, bool) {
+	multiRef, err := parseMultiKeyReference(ref)
+	return multiRef, err == nil
+}
+
+func parseMultiKeyReference(ref KeyReference) (MultiKeyReference, error) {
+	var multiRef MultiKeyReference
+	if err := json.Unmarshal([]byte(ref), &multiRef); err != nil {
+		return MultiKeyReference{}, fmt.Errorf("failed to parse key reference: %w", err)
+	}
+	if len(multiRef.Members) < 2 || multiRef.Threshold < 1 || multiRef.Threshold > len(multiRef.Members) {
+		return MultiKeyReference{}, fmt.Errorf("invalid threshold key reference: %d of %d members", multiRef.Threshold, len(multiRef.Members))
+	}
+	return multiRef, nil
+}
+
+func (m *MultiAuthority) Name() string {
+	return MultiAuthorityName
+}
+
+// RoundAt returns the unlock time in Unix seconds, rounded up.
+func (m *MultiAuthority) RoundAt(unlockTime time.Time) (uint64, error) {
+	if unlockTime.Unix() < 0 {
+		return 0, fmt.Errorf("unlock time %s is before 1970", unlockTime.Format(time.RFC3339))
+	}
+	seconds := uint64(unlockTime.Unix())
+	if unlockTime.Nanosecond() != 0 {
+		seconds++
+	}
+	return seconds, nil
+}
+
+func (m *MultiAuthority) RoundTime(round uint64) (time.Time, error) {
+	return time.Unix(int64(round), 0).UTC(), nil
+}
+
+// EarliestUnlockTime returns when the threshold-th member reaches its target round.
+func (m *MultiAuthority) EarliestUnlockTime(ref KeyReference) (time.Time, error) {
+	multiRef, err := parseMultiKeyReference(ref)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if len(multiRef.Members) != len(m.Members) {
+		return time.Time{}, fmt.Errorf("key reference has %d members, authority has %d", len(multiRef.Members), len(m.Members))
+	}
+
+	var times []time.Time
+	var errs []error
+	for i, member := range multiRef.Members {
+		if m.Members[i] == nil {
+			errs = append(errs, fmt.Errorf("%s: authority not available in this build", m.label(i)))
+			continue
+		}
+		t, err := m.Members[i].EarliestUnlockTime(member.KeyRef)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", m.label(i), err))
+			continue
+		}
+		times = append(times, t)
+	}
+	if len(times) < m.Threshold {
+		return time.Time{}, m.thresholdError("can unlock", len(times), errs)
+	}
+
+	sort.Slice(times, func(i, j int) bool { return times[i].Before(times[j]) })
+	return times[m.Threshold-1], nil
+}
+
+// Lock records each member's key reference for the unlock time.
+func (m *MultiAuthority) Lock(unlockTime time.Time) (KeyReference, error) {
+	targetRound, err := m.RoundAt(unlockTime)
+	if err != nil {
+		return "", err
+	}
+	unlockAt, _ := m.RoundTime(targetRound)
+
+	multiRef := MultiKeyReference{TargetRound: targetRound, Threshold: m.Threshold}
+	for i, member := range m.Members {
+		ref, err := member.Lock(unlockAt)
+		if err != nil {
+			return "", fmt.Errorf("%s: %w", m.label(i), err)
+		}
+		multiRef.Members = append(multiRef.Members, MemberKeyReference{Authority: m.names[i], KeyRef: ref})
+	}
+
+	data, err := json.Marshal(multiRef)
+	if err != nil {
+		return "", err
+	}
+	return KeyReference(data), nil
+}
+
+// TimeLockEncrypt splits data into one share per member and time-locks each share
+// with its member, to the member's round at the unlock time.
+func (m *MultiAuthority) TimeLockEncrypt(data []byte, targetRound uint64) (string, error) {
+	unlockAt, _ := m.RoundTime(targetRound)
+
+	shares, err := splitSecret(data, len(m.Members), m.Threshold)
+	if err != nil {
+		return "", err
+	}
+
+	bundle := multiShares{Threshold: m.Threshold}
+	for i, member := range m.Members {
+		round, err := member.RoundAt(unlockAt)
+		if err != nil {
+			return "", fmt.Errorf("%s: %w", m.label(i), err)
+		}
+		tlockB64, err := member.TimeLockEncrypt(shares[i], round)
+		if err != nil {
+			return "", fmt.Errorf("%s: %w", m.label(i), err)
+		}
+		if tlockB64 == "" {
+			return "", fmt.Errorf("%s cannot time-lock a share", m.label(i))
+		}
+		bundle.Shares = append(bundle.Shares, multiShare{X: byte(i + 1), TlockB64: tlockB64})
+	}
+
+	encoded, err := json.Marshal(bundle)
+	if err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(encoded), nil
+}
+
+// TimeLockDecrypt decrypts shares until the threshold is met and recovers the key.
+// Members that cannot decrypt their share yet are skipped.
+func (m *MultiAuthority) TimeLockDecrypt(ctx context.Context, ciphertextB64 string) ([]byte, error) {
+	if err := m.checkMembers(); err != nil {
+		return nil, err
+	}
+
+	encoded, err := base64.StdEncoding.DecodeString(ciphertextB64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid threshold key encoding: %w", err)
+	}
+	var bundle multiShares
+	if err := json.Unmarshal(encoded, &bundle); err != nil {
+		return nil, fmt.Errorf("invalid threshold key: %w", err)
+	}
+	if len(bundle.Shares) != len(m.Members) || bundle.Threshold != m.Threshold {
+		return nil, fmt.Errorf("threshold key (%d of %d) does not match the authority (%d of %d)", bundle.Threshold, len(bundle.Shares), m.Threshold, len(m.Members))
+	}
+
+	var xs []byte
+	var shares [][]byte
+	var errs []error
+	for i, share := range bundle.Shares {
+		if len(shares) == m.Threshold {
+			break
+		}
+		if m.Members[i] == nil {
+			errs = append(errs, fmt.Errorf("%s: authority not available in this build", m.label(i)))
+			continue
+		}
+		plaintext, err := m.Members[i].TimeLockDecrypt(ctx, share.TlockB64)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", m.label(i), err))
+			continue
+		}
+		xs = append(xs, share.X)
+		shares = append(shares, plaintext)
+	}
+	if len(shares) < m.Threshold {
+		return nil, m.thresholdError("could decrypt their share", len(shares), errs)
+	}
+
+	return combineShares(xs, shares)
+}
+
+// LatestRound returns the latest time, in Unix seconds, reached by at least
+// threshold members: the time the members agree has passed.
+func (m *MultiAuthority) LatestRound(ctx context.Context) (uint64, error) {
+	if err := m.checkMembers(); err != nil {
+		return 0, err
+	}
+
+	var reached []uint64
+	var errs []error
+	for i, member := range m.Members {
+		if member == nil {
+			errs = append(errs, fmt.Errorf("%s: authority not available in this build", m.label(i)))
+			continue
+		}
+		round, err := member.LatestRound(ctx)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", m.label(i), err))
+			continue
+		}
+		roundTime, err := member.RoundTime(round)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", m.label(i), err))
+			continue
+		}
+		reached = append(reached, uint64(max(roundTime.Unix(), 0)))
+	}
+	if len(reached) < m.Threshold {
+		return 0, m.thresholdError("are reachable", len(reached), errs)
+	}
+
+	sort.Slice(reached, func(i, j int) bool { return reached[i] > reached[j] })
+	return reached[m.Threshold-1], nil
+}
+
+// CanUnlock reports whether at least threshold members have reached the unlock time.
+// Returns an error only if unreachable members leave the answer open.
+func (m *MultiAuthority) CanUnlock(ctx context.Context, targetRound uint64) (bool, error) {
+	if err := m.checkMembers(); err != nil {
+		return false, err
+	}
+
+	unlockAt, _ := m.RoundTime(targetRound)
+
+	var reached int
+	var errs []error
+	for i, member := range m.Members {
+		if member == nil {
+			continue
+		}
+		round, err := member.RoundAt(unlockAt)
+		if err == nil {
+			var ok bool
+			if ok, err = member.CanUnlock(ctx, round); ok {
+				reached++
+			}
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", m.label(i), err))
+		}
+	}
+
+	switch {
+	case reached >= m.Threshold:
+		return true, nil
+	case reached+len(errs) < m.Threshold:
+		return false, nil
+	default:
+		return false, errors.Join(errs...)
+	}
+}
+
+// label identifies member i in errors; several members can share an authority name.
+func (m *MultiAuthority) label(i int) string {
+	return fmt.Sprintf("member %d (%s)", i+1, m.names[i])
+}
+
+// checkMembers rejects an authority without members, e.g. from an unreadable key reference.
+func (m *MultiAuthority) checkMembers() error {
+	if len(m.Members) == 0 || m.Threshold < 1 {
+		return errors.New("threshold authority has no members")
+	}
+	return nil
+}
+
+// thresholdError reports that fewer than threshold members could do something.
+func (m *MultiAuthority) thresholdError(what string, count int, errs []error) error {
+	err := fmt.Errorf("only %d of %d time authorities %s, %d required", count, len(m.Members), what, m.Threshold)
+	if len(errs) > 0 {
+		err = fmt.Errorf("%w: %w", err, errors.Join(errs...))
+	}
+	return err
+}
+
+// ParseMemberSpec parses a threshold member of the form <authority>[:<network>|<chain-hash>][@<relay>],
+// e.g. drand, drand:testnet or drand:<chain-hash>@https://relay.example.com.
+func ParseMemberSpec(spec string) (string, Options, error) {
+	name, rest, _ := strings.Cut(spec, ":")
+	network, relay, _ := strings.Cut(rest, "@")
+	if name == "" {
+		return "", Options{}, fmt.Errorf("invalid member %q, expected <authority>[:<network>][@<relay>]", spec)
+	}
+	if name == MultiAuthorityName {
+		return "", Options{}, errors.New("a multi authority cannot be a member of another")
+	}
+
+	opts := Options{Network: network, Relay: relay}
+	if decoded, err := hex.DecodeString(network); err == nil && len(decoded) == 32 {
+		opts = Options{ChainHash: network, Relay: relay}
+	}
+	return name, opts, nil
+}
+
+func init() {
+	Register(MultiAuthorityName, Registration{
+		New: newMultiForOptions,
+		ForRef: func(ref KeyReference) Authority {
+			m, err := NewMultiAuthorityForRef(ref)
+			if err != nil {
+				// An unreadable reference has no members, so it never unlocks
+				return &MultiAuthority{}
+			}
+			return m
+		},
+	})
+}
+
+// newMultiForOptions creates the threshold authority for new items from opts.Members and opts.Threshold.
+func newMultiForOptions(opts Options) (Authority, error) {
+	if opts.Network != "" || opts.ChainHash != "" || opts.Relay != "" {
+		return nil, errors.New("multi authority: networks and relays are selected per member")
+	}
+	if opts.Threshold == 0 {
+		return nil, errors.New("multi authority requires a threshold (k of n members)")
+	}
+
+	members := make([]Authority, 0, len(opts.Members))
+	for _, spec := range opts.Members {
+		name, memberOpts, err := ParseMemberSpec(spec)
+		if err != nil {
+			return nil, err
+		}
+		member, err := NewAuthority(name, memberOpts)
+		if err != nil {
+			return nil, fmt.Errorf("member %s: %w", spec, err)
+		}
+		members = append(members, member)
+	}
+	return NewMultiAuthority(opts.Threshold, members...)
+}
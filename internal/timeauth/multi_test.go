@@ -0,0 +1,187 @@
+package timeauth
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestShamir_AnyThresholdOfSharesRecovers(t *testing.T) {
+	secret := []byte("0123456789abcdef0123456789abcdef")
+
+	shares, err := splitSecret(secret, 5, 3)
+	if err != nil {
+		t.Fatalf("splitSecret failed: %v", err)
+	}
+
+	for a := 0; a < 5; a++ {
+		for b := a + 1; b < 5; b++ {
+			for c := b + 1; c < 5; c++ {
+				got, err := combineShares([]byte{byte(a + 1), byte(b + 1), byte(c + 1)}, [][]byte{shares[a], shares[b], shares[c]})
+				if err != nil || !bytes.Equal(got, secret) {
+					t.Errorf("shares %d,%d,%d: got %x, %v", a+1, b+1, c+1, got, err)
+				}
+			}
+		}
+	}
+
+	// Below the threshold the shares combine to something else
+	if got, _ := combineShares([]byte{1, 2}, shares[:2]); bytes.Equal(got, secret) {
+		t.Error("two of three required shares recovered the secret")
+	}
+
+	if _, err := combineShares([]byte{1, 1}, [][]byte{shares[0], shares[0]}); err == nil {
+		t.Error("expected duplicate share indexes to be rejected")
+	}
+}
+
+// multiFakes returns n fake members at the given current rounds, with target round 100.
+func multiFakes(currentRounds ...uint64) []Authority {
+	members := make([]Authority, len(currentRounds))
+	for i, current := range currentRounds {
+		members[i] = &FakeAuthority{DefaultRound: 100, CurrentRound: current, GenesisTime: time.Unix(1_700_000_000, 0)}
+	}
+	return members
+}
+
+func TestMultiAuthority_UnlocksWithThresholdOfMembers(t *testing.T) {
+	members := multiFakes(200, 200, 50)
+	m, err := NewMultiAuthority(2, members...)
+	if err != nil {
+		t.Fatalf("NewMultiAuthority failed: %v", err)
+	}
+
+	dek := []byte("data encryption key, 32 bytes!!!")
+	unlockAt := time.Unix(1_700_000_300, 0)
+	round, _ := m.RoundAt(unlockAt)
+	tlockB64, err := m.TimeLockEncrypt(dek, round)
+	if err != nil {
+		t.Fatalf("TimeLockEncrypt failed: %v", err)
+	}
+
+	ok, err := m.CanUnlock(context.Background(), round)
+	if err != nil || !ok {
+		t.Errorf("two of three members reached the round: CanUnlock = %v, %v", ok, err)
+	}
+
+	// The member that cannot decrypt is skipped
+	members[0].(*FakeAuthority).DecryptError = errors.New("round not reached")
+	got, err := m.TimeLockDecrypt(context.Background(), tlockB64)
+	if err != nil || !bytes.Equal(got, dek) {
+		t.Fatalf("TimeLockDecrypt = %q, %v", got, err)
+	}
+
+	// Below the threshold, decryption fails and says why
+	members[1].(*FakeAuthority).DecryptError = errors.New("beacon unreachable")
+	if _, err := m.TimeLockDecrypt(context.Background(), tlockB64); err == nil || !strings.Contains(err.Error(), "only 1 of 3") || !strings.Contains(err.Error(), "member 2 (fake): beacon unreachable") {
+		t.Errorf("expected a threshold error naming the members, got %v", err)
+	}
+}
+
+func TestMultiAuthority_CanUnlockAndLatestRound(t *testing.T) {
+	m, err := NewMultiAuthority(2, multiFakes(200, 50, 50)...)
+	if err != nil {
+		t.Fatalf("NewMultiAuthority failed: %v", err)
+	}
+	if ok, err := m.CanUnlock(context.Background(), 1_700_000_300); err != nil || ok {
+		t.Errorf("one of three members reached the round: CanUnlock = %v, %v", ok, err)
+	}
+
+	// The second most advanced member is at round 50, published 147 seconds after genesis
+	if latest, err := m.LatestRound(context.Background()); err != nil || latest != 1_700_000_147 {
+		t.Errorf("LatestRound = %d, %v; want 1700000147", latest, err)
+	}
+
+	// An unreachable member leaves the answer open only if it could make the threshold
+	members := multiFakes(200, 50, 50)
+	members[1].(*FakeAuthority).CanUnlockError = errors.New("offline")
+	m, _ = NewMultiAuthority(2, members...)
+	if _, err := m.CanUnlock(context.Background(), 1_700_000_300); err == nil {
+		t.Error("expected an error while an unreachable member could decide")
+	}
+	m, _ = NewMultiAuthority(3, members...)
+	if ok, err := m.CanUnlock(context.Background(), 1_700_000_300); err != nil || ok {
+		t.Errorf("a member not at the round rules out unlocking: CanUnlock = %v, %v", ok, err)
+	}
+}
+
+func TestMultiAuthority_LockRecordsMembers(t *testing.T) {
+	m, err := NewMultiAuthority(2, multiFakes(0, 0, 0)...)
+	if err != nil {
+		t.Fatalf("NewMultiAuthority failed: %v", err)
+	}
+
+	ref, err := m.Lock(time.Unix(1_700_000_300, 500))
+	if err != nil {
+		t.Fatalf("Lock failed: %v", err)
+	}
+
+	multiRef, ok := MultiKeyReferenceOf(ref)
+	if !ok || multiRef.Threshold != 2 || len(multiRef.Members) != 3 || multiRef.Members[2].Authority != "fake" {
+		t.Fatalf("unexpected key reference: %s", ref)
+	}
+	// The unlock time is rounded up to whole seconds
+	if round, err := TargetRound(ref); err != nil || round != 1_700_000_301 {
+		t.Errorf("TargetRound = %d, %v; want 1700000301", round, err)
+	}
+
+	// The second member to unlock decides when the item can unlock: its round 100
+	if unlockAt, err := m.EarliestUnlockTime(ref); err != nil || !unlockAt.Equal(time.Unix(1_700_000_297, 0)) {
+		t.Errorf("EarliestUnlockTime = %s, %v", unlockAt, err)
+	}
+
+	// Members that are not registered never unlock
+	recorded, err := NewMultiAuthorityForRef(ref)
+	if err != nil {
+		t.Fatalf("NewMultiAuthorityForRef failed: %v", err)
+	}
+	if recorded.Threshold != 2 || len(recorded.Members) != 3 || recorded.Members[0] != nil {
+		t.Errorf("unexpected authority for recorded members: %+v", recorded)
+	}
+	if _, err := recorded.LatestRound(context.Background()); err == nil || !strings.Contains(err.Error(), "not available in this build") {
+		t.Errorf("expected unavailable members, got %v", err)
+	}
+}
+
+func TestNewAuthority_Multi(t *testing.T) {
+	if TestBuild {
+		t.Skip("test builds resolve every drand network to the simulator")
+	}
+	authority, err := NewAuthority(MultiAuthorityName, Options{Members: []string{"drand", "drand:testnet", "drand:" + strings.Repeat("ab", 32) + "@http://localhost:8080"}, Threshold: 2})
+	if err != nil {
+		t.Fatalf("NewAuthority failed: %v", err)
+	}
+	m := authority.(*MultiAuthority)
+	if got := m.Members[2].(*DrandAuthority).BaseURL; got != "http://localhost:8080/"+strings.Repeat("ab", 32) {
+		t.Errorf("custom member fetches from %s", got)
+	}
+	if id := InstanceID(m); !strings.Contains(id, "multi 2 of [drand ") {
+		t.Errorf("unexpected instance id %q", id)
+	}
+
+	testCases := []struct {
+		name    string
+		opts    Options
+		wantErr string
+	}{
+		{"no threshold", Options{Members: []string{"drand", "drand:testnet"}}, "requires a threshold"},
+		{"one member", Options{Members: []string{"drand"}, Threshold: 1}, "at least two members"},
+		{"threshold above members", Options{Members: []string{"drand", "drand:testnet"}, Threshold: 3}, "between 1 and 2"},
+		{"network for all", Options{Network: "testnet", Members: []string{"drand", "drand"}, Threshold: 1}, "per member"},
+		{"nested", Options{Members: []string{"drand", "multi"}, Threshold: 1}, "cannot be a member"},
+		{"unknown member", Options{Members: []string{"drand", "nts"}, Threshold: 1}, `unknown time authority "nts"`},
+		{"bad network", Options{Members: []string{"drand", "drand:mainnet"}, Threshold: 1}, "is chained"},
+	}
+	for _, tc := range testCases {
+		if _, err := NewAuthority(MultiAuthorityName, tc.opts); err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+			t.Errorf("%s: expected error containing %q, got %v", tc.name, tc.wantErr, err)
+		}
+	}
+
+	if _, err := NewAuthority("drand", Options{Members: []string{"drand", "drand:testnet"}, Threshold: 2}); err == nil || !strings.Contains(err.Error(), "takes no members") {
+		t.Errorf("expected drand to reject members, got %v", err)
+	}
+}
@@ -0,0 +1,182 @@
+package timeauth
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// DrandNetwork identifies a drand chain and the relay it is fetched from.
+type DrandNetwork struct {
+	Name      string // network name recorded in key references
+	ChainHash string // hex chain hash
+	Relay     string // HTTP relay URL, without the chain hash
+}
+
+// DefaultDrandNetwork is the network used when none is selected.
+const DefaultDrandNetwork = "quicknet"
+
+// CustomDrandNetwork is the name recorded for a chain selected by its hash.
+const CustomDrandNetwork = "custom"
+
+// defaultDrandRelay serves every public drand chain.
+const defaultDrandRelay = "https://api.drand.sh"
+
+// Chain hashes of the drand networks selectable by name.
+const (
+	drandTestnetChainHash = "cc9c398442737cbd141526600919edd69f1d6f9b4adb67e4d912fbc64341a9a5"
+	drandMainnetChainHash = "8990e7a9aaed2ffed73dbd7092123d6f289930540d7651336225dc172e51b2ce"
+)
+
+// drandNetworks are the networks selectable by name, with their pinned chain hashes.
+var drandNetworks = map[string]DrandNetwork{
+	"quicknet": {Name: "quicknet", ChainHash: drandQuicknetChainHash, Relay: defaultDrandRelay},
+	"testnet":  {Name: "testnet", ChainHash: drandTestnetChainHash, Relay: "https://pl-us.testnet.drand.sh"},
+}
+
+// drandFallbackRelays are further public relays for a chain, by chain hash. Requests
+// fail over to them, in order, when the relay recorded in an item does not answer.
+// Every round is verified against the chain, so any relay is as trustworthy as another.
+var drandFallbackRelays = map[string][]string{
+	drandQuicknetChainHash: {"https://api2.drand.sh", "https://api3.drand.sh", "https://drand.cloudflare.com"},
+}
+
+// fallbackRelays returns the fallback relays for a chain, other than primary.
+func fallbackRelays(chainHash, primary string) []string {
+	var relays []string
+	for _, relay := range drandFallbackRelays[chainHash] {
+		if relay != primary {
+			relays = append(relays, relay)
+		}
+	}
+	return relays
+}
+
+// unusableDrandNetworks are known networks that cannot time-lock, by name.
+var unusableDrandNetworks = map[string]string{
+	"mainnet": "the mainnet default chain (" + drandMainnetChainHash + ") is chained, and time-lock encryption needs an unchained chain such as quicknet",
+}
+
+// DrandNetworkNames returns the names of the networks selectable by name, sorted.
+func DrandNetworkNames() []string {
+	names := make([]string, 0, len(drandNetworks))
+	for name := range drandNetworks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ResolveDrandNetwork selects a drand network by name, or an arbitrary chain by its hash.
+// An empty name selects quicknet. A chain hash selects a custom chain; the name must
+// then be empty or "custom". A relay replaces the network's default relay, e.g. a mirror.
+func ResolveDrandNetwork(name, chainHash, relay string) (DrandNetwork, error) {
+	var network DrandNetwork
+	if chainHash != "" {
+		if name != "" && name != CustomDrandNetwork {
+			return DrandNetwork{}, fmt.Errorf("a chain hash selects a custom network and cannot be combined with network %s", name)
+		}
+		chainHash = strings.ToLower(chainHash)
+		if decoded, err := hex.DecodeString(chainHash); err != nil || len(decoded) != 32 {
+			return DrandNetwork{}, fmt.Errorf("invalid chain hash %q, expected 64 hex characters", chainHash)
+		}
+		network = DrandNetwork{Name: CustomDrandNetwork, ChainHash: chainHash, Relay: defaultDrandRelay}
+	} else {
+		if name == "" {
+			name = DefaultDrandNetwork
+		}
+		if reason, ok := unusableDrandNetworks[name]; ok {
+			return DrandNetwork{}, fmt.Errorf("drand network %s cannot be used: %s", name, reason)
+		}
+		known, ok := drandNetworks[name]
+		if !ok {
+			if name == CustomDrandNetwork {
+				return DrandNetwork{}, fmt.Errorf("network custom requires a chain hash")
+			}
+			return DrandNetwork{}, fmt.Errorf("unknown drand network %q (known: %s, or a chain hash)", name, strings.Join(DrandNetworkNames(), ", "))
+		}
+		network = known
+	}
+
+	if relay != "" {
+		parsed, err := url.Parse(relay)
+		if err != nil || (parsed.Scheme != "https" && parsed.Scheme != "http") || parsed.Host == "" {
+			return DrandNetwork{}, fmt.Errorf("invalid relay %q, expected an http(s) URL", relay)
+		}
+		network.Relay = strings.TrimSuffix(relay, "/")
+	}
+
+	return network, nil
+}
+
+// DrandNetworkOf returns the network recorded in a drand key reference.
+// References from before network selection record only the network name;
+// the chain hash and relay of a known network are filled in for them.
+// Returns false if ref is not a drand key reference.
+func DrandNetworkOf(ref KeyReference) (DrandNetwork, bool) {
+	var drandRef DrandKeyReference
+	if err := json.Unmarshal([]byte(ref), &drandRef); err != nil || drandRef.Network == "" {
+		return DrandNetwork{}, false
+	}
+
+	network := DrandNetwork{Name: drandRef.Network, ChainHash: drandRef.ChainHash, Relay: drandRef.Relay}
+	if known, ok := drandNetworks[drandRef.Network]; ok {
+		if network.ChainHash == "" {
+			network.ChainHash = known.ChainHash
+		}
+		if network.Relay == "" {
+			network.Relay = known.Relay
+		}
+	}
+	return network, true
+}
+
+// NewDrandAuthorityForRef returns the drand authority a key reference was locked with.
+// References without a recorded chain hash predate network selection, and were all
+// locked with the default authority; so are references that cannot be parsed.
+func NewDrandAuthorityForRef(ref KeyReference) *DrandAuthority {
+	var drandRef DrandKeyReference
+	if err := json.Unmarshal([]byte(ref), &drandRef); err != nil || drandRef.ChainHash == "" {
+		return NewDefaultDrandAuthority()
+	}
+
+	relay := drandRef.Relay
+	if relay == "" {
+		relay = defaultDrandRelay
+	}
+	return NewDrandAuthorityForNetwork(DrandNetwork{Name: drandRef.Network, ChainHash: drandRef.ChainHash, Relay: relay})
+}
+
+// relay returns the relay URL the authority fetches from, without the chain hash.
+func (d *DrandAuthority) relay() string {
+	return strings.TrimSuffix(d.BaseURL, "/"+d.ChainHash)
+}
+
+func init() {
+	Register("drand", Registration{
+		New: newDrandForOptions,
+		ForRef: func(ref KeyReference) Authority {
+			return NewDrandAuthorityForRef(ref)
+		},
+	})
+}
+
+// newDrandForOptions creates the drand authority for new items: the default
+// authority, or the network selected by opts.
+func newDrandForOptions(opts Options) (Authority, error) {
+	if opts.empty() {
+		return NewDefaultDrandAuthority(), nil
+	}
+	if len(opts.Members) > 0 || opts.Threshold != 0 {
+		return nil, fmt.Errorf("drand authority takes no members or threshold; threshold sealing uses the %s authority", MultiAuthorityName)
+	}
+
+	network, err := ResolveDrandNetwork(opts.Network, opts.ChainHash, opts.Relay)
+	if err != nil {
+		return nil, err
+	}
+	return NewDrandAuthorityForNetwork(network), nil
+}
@@ -0,0 +1,133 @@
+package timeauth
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestResolveDrandNetwork(t *testing.T) {
+	customHash := strings.Repeat("ab", 32)
+
+	testCases := []struct {
+		name, network, chainHash, relay string
+		want                            DrandNetwork
+	}{
+		{"default", "", "", "", DrandNetwork{"quicknet", drandQuicknetChainHash, "https://api.drand.sh"}},
+		{"quicknet", "quicknet", "", "", DrandNetwork{"quicknet", drandQuicknetChainHash, "https://api.drand.sh"}},
+		{"testnet", "testnet", "", "", DrandNetwork{"testnet", drandTestnetChainHash, "https://pl-us.testnet.drand.sh"}},
+		{"mirror", "quicknet", "", "https://drand.example.com/", DrandNetwork{"quicknet", drandQuicknetChainHash, "https://drand.example.com"}},
+		{"custom chain", "", strings.ToUpper(customHash), "", DrandNetwork{"custom", customHash, "https://api.drand.sh"}},
+		{"custom chain and relay", "custom", customHash, "http://localhost:8080", DrandNetwork{"custom", customHash, "http://localhost:8080"}},
+	}
+
+	for _, tc := range testCases {
+		got, err := ResolveDrandNetwork(tc.network, tc.chainHash, tc.relay)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", tc.name, err)
+			continue
+		}
+		if got != tc.want {
+			t.Errorf("%s: got %+v, want %+v", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestResolveDrandNetwork_Invalid(t *testing.T) {
+	testCases := []struct {
+		name, network, chainHash, relay, wantErr string
+	}{
+		{"unknown network", "fastnet", "", "", "unknown drand network"},
+		{"chained mainnet", "mainnet", "", "", "is chained"},
+		{"custom without hash", "custom", "", "", "requires a chain hash"},
+		{"hash with named network", "quicknet", strings.Repeat("ab", 32), "", "cannot be combined"},
+		{"short hash", "", "abcd", "", "invalid chain hash"},
+		{"non-hex hash", "", strings.Repeat("zz", 32), "", "invalid chain hash"},
+		{"relay without scheme", "", "", "api.drand.sh", "invalid relay"},
+		{"relay with other scheme", "", "", "ftp://api.drand.sh", "invalid relay"},
+	}
+
+	for _, tc := range testCases {
+		_, err := ResolveDrandNetwork(tc.network, tc.chainHash, tc.relay)
+		if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+			t.Errorf("%s: expected error containing %q, got %v", tc.name, tc.wantErr, err)
+		}
+	}
+}
+
+func TestDrandKeyReference_RecordsNetwork(t *testing.T) {
+	if TestBuild {
+		t.Skip("test builds resolve every drand network to the simulator")
+	}
+	network := DrandNetwork{Name: "custom", ChainHash: testDrandChain.ChainHash, Relay: "https://drand.example.com"}
+	authority := newDrandAuthorityForChain(network.Name, network.Relay, network.ChainHash, newTestDrandAuthority(1000).HTTPClient, &fakeTimelockBox{})
+
+	ref, err := authority.Lock(time.Now().Add(time.Hour))
+	if err != nil {
+		t.Fatalf("Lock failed: %v", err)
+	}
+
+	var drandRef DrandKeyReference
+	if err := json.Unmarshal([]byte(ref), &drandRef); err != nil {
+		t.Fatalf("invalid key reference: %v", err)
+	}
+	if drandRef.ChainHash != network.ChainHash || drandRef.Relay != network.Relay {
+		t.Errorf("key reference does not record the network: %s", ref)
+	}
+
+	got, ok := DrandNetworkOf(ref)
+	if !ok || got != network {
+		t.Errorf("DrandNetworkOf = %+v, %v; want %+v", got, ok, network)
+	}
+
+	// Materialization fetches from the recorded relay and chain
+	recorded := NewDrandAuthorityForRef(ref)
+	if recorded.NetworkName != "custom" || recorded.BaseURL != "https://drand.example.com/"+network.ChainHash {
+		t.Errorf("unexpected authority for recorded network: %s at %s", recorded.NetworkName, recorded.BaseURL)
+	}
+}
+
+func TestDrandNetworkOf_LegacyReference(t *testing.T) {
+	if TestBuild {
+		t.Skip("test builds resolve every drand network to the simulator")
+	}
+	// Key references from before network selection record only the name
+	ref := KeyReference(`{"network":"quicknet","target_round":1000}`)
+	got, ok := DrandNetworkOf(ref)
+	if !ok || got != drandNetworks["quicknet"] {
+		t.Errorf("DrandNetworkOf = %+v, %v; want quicknet", got, ok)
+	}
+	if authority := NewDrandAuthorityForRef(ref); authority.ChainHash != drandQuicknetChainHash {
+		t.Errorf("legacy reference should use quicknet, got chain %s", authority.ChainHash)
+	}
+
+	if _, ok := DrandNetworkOf("1000"); ok {
+		t.Error("a bare round is not a drand key reference")
+	}
+}
+
+func TestNewDrandAuthorityForRef_TestBuildUsesSimulator(t *testing.T) {
+	if !TestBuild {
+		t.Skip("only test builds resolve drand networks to the simulator")
+	}
+
+	simulator := NewDefaultDrandAuthority()
+	ref, err := simulator.Lock(time.Now().Add(time.Hour))
+	if err != nil {
+		t.Fatalf("Lock failed: %v", err)
+	}
+
+	network, ok := DrandNetworkOf(ref)
+	if !ok || network.ChainHash != simulator.ChainHash || network.Relay != simulator.relay() {
+		t.Errorf("key reference does not record the simulator network: %s", ref)
+	}
+	if recorded := NewDrandAuthorityForRef(ref); recorded.ChainHash != simulator.ChainHash || recorded.BaseURL != simulator.BaseURL {
+		t.Errorf("recorded network resolves to %s at %s, want the simulator", recorded.ChainHash, recorded.BaseURL)
+	}
+
+	// Any other network a test locks with is the simulator too
+	if recorded := NewDrandAuthorityForRef(`{"network":"testnet","chain_hash":"` + drandTestnetChainHash + `","target_round":1000}`); recorded.ChainHash != simulator.ChainHash {
+		t.Errorf("testnet reference resolves to chain %s, want the simulator", recorded.ChainHash)
+	}
+}
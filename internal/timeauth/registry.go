@@ -0,0 +1,104 @@
+package timeauth
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// DefaultAuthorityName is the authority new items are sealed with unless another is selected.
+const DefaultAuthorityName = "drand"
+
+// Options configures an authority for sealing new items.
+// An authority must reject options it does not support rather than ignore them,
+// so an item is never sealed to something other than what was asked for.
+type Options struct {
+	Network   string // network name, e.g. a drand network
+	ChainHash string // chain identifier, instead of a network name
+	Relay     string // endpoint to fetch from instead of the network's default
+
+	// Threshold authorities only, see MultiAuthority
+	Members   []string // member specs, see ParseMemberSpec
+	Threshold int      // members needed to unlock
+}
+
+// empty reports whether no option is set.
+func (o Options) empty() bool {
+	return o.Network == "" && o.ChainHash == "" && o.Relay == "" && len(o.Members) == 0 && o.Threshold == 0
+}
+
+// Registration is how an authority implementation plugs into Seal.
+// Implementations register themselves by name from an init function; the name
+// is recorded in item metadata and selects the same registration at unlock.
+type Registration struct {
+	// New creates the authority that seals new items.
+	New func(opts Options) (Authority, error)
+
+	// ForRef creates the authority that unlocks an item sealed with ref.
+	ForRef func(ref KeyReference) Authority
+}
+
+var (
+	registryMu sync.RWMutex
+	registry   = map[string]Registration{}
+)
+
+// Register makes an authority available under name.
+// It panics if name is empty, already registered, or the registration is incomplete:
+// registrations happen at init time, where that is a programming error.
+func Register(name string, registration Registration) {
+	if name == "" || registration.New == nil || registration.ForRef == nil {
+		panic("timeauth: incomplete registration for authority " + name)
+	}
+
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	if _, exists := registry[name]; exists {
+		panic("timeauth: authority registered twice: " + name)
+	}
+	registry[name] = registration
+}
+
+// AuthorityNames returns the names of the registered authorities, sorted.
+func AuthorityNames() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// NewAuthority creates the registered authority name for sealing new items.
+// An empty name selects DefaultAuthorityName.
+func NewAuthority(name string, opts Options) (Authority, error) {
+	if name == "" {
+		name = DefaultAuthorityName
+	}
+
+	registryMu.RLock()
+	registration, ok := registry[name]
+	registryMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("unknown time authority %q (available: %s)", name, strings.Join(AuthorityNames(), ", "))
+	}
+
+	return registration.New(opts)
+}
+
+// AuthorityForRef returns the authority that unlocks an item recorded with the
+// authority name and key reference. Returns nil if no such authority is registered.
+func AuthorityForRef(name string, ref KeyReference) Authority {
+	registryMu.RLock()
+	registration, ok := registry[name]
+	registryMu.RUnlock()
+	if !ok {
+		return nil
+	}
+
+	return registration.ForRef(ref)
+}
@@ -0,0 +1,52 @@
+package timeauth
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRegistry_DrandIsDefault(t *testing.T) {
+	if names := AuthorityNames(); len(names) == 0 || names[0] != "drand" {
+		t.Fatalf("expected drand to be registered, got %v", names)
+	}
+
+	authority, err := NewAuthority("", Options{})
+	if err != nil {
+		t.Fatalf("NewAuthority failed: %v", err)
+	}
+	if authority.Name() != DefaultAuthorityName {
+		t.Errorf("expected the default authority, got %s", authority.Name())
+	}
+
+	// Options reach the authority, which rejects those it cannot honor
+	if _, err := NewAuthority("drand", Options{Network: "mainnet"}); err == nil || !strings.Contains(err.Error(), "is chained") {
+		t.Errorf("expected drand to reject mainnet, got %v", err)
+	}
+}
+
+func TestRegistry_UnknownAuthority(t *testing.T) {
+	_, err := NewAuthority("nts", Options{})
+	if err == nil || !strings.Contains(err.Error(), `unknown time authority "nts"`) || !strings.Contains(err.Error(), "drand") {
+		t.Errorf("expected unknown authority error listing drand, got %v", err)
+	}
+
+	if authority := AuthorityForRef("nts", "1000"); authority != nil {
+		t.Errorf("expected no authority for an unregistered name, got %s", authority.Name())
+	}
+}
+
+func TestRegister_RejectsDuplicateAndIncomplete(t *testing.T) {
+	for name, registration := range map[string]Registration{
+		"drand":      {New: newDrandForOptions, ForRef: func(KeyReference) Authority { return nil }},
+		"incomplete": {New: newDrandForOptions},
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%s: expected Register to panic", name)
+				}
+			}()
+			Register(name, registration)
+		}()
+	}
+}
@@ -0,0 +1,106 @@
+package timeauth
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+)
+
+// Shamir secret sharing over GF(2^8), byte by byte.
+// Each byte of the secret is the constant term of a random polynomial of degree k-1;
+// share x holds the polynomials evaluated at x. Any k shares recover the secret
+// by Lagrange interpolation at 0, and fewer than k reveal nothing about it.
+
+// gfMul multiplies in GF(2^8) with the AES polynomial x^8 + x^4 + x^3 + x + 1.
+func gfMul(a, b byte) byte {
+	var product byte
+	for b != 0 {
+		if b&1 != 0 {
+			product ^= a
+		}
+		carry := a & 0x80
+		a <<= 1
+		if carry != 0 {
+			a ^= 0x1b
+		}
+		b >>= 1
+	}
+	return product
+}
+
+// gfInv returns the multiplicative inverse of a non-zero element, a^254.
+func gfInv(a byte) byte {
+	result := byte(1)
+	for i := 0; i < 254; i++ {
+		result = gfMul(result, a)
+	}
+	return result
+}
+
+// splitSecret splits secret into n shares, any k of which recover it.
+// Share i is the evaluation at x = i+1.
+func splitSecret(secret []byte, n, k int) ([][]byte, error) {
+	if k < 1 || k > n || n > 255 {
+		return nil, fmt.Errorf("invalid secret sharing: %d of %d", k, n)
+	}
+	if len(secret) == 0 {
+		return nil, errors.New("cannot share an empty secret")
+	}
+
+	shares := make([][]byte, n)
+	for i := range shares {
+		shares[i] = make([]byte, len(secret))
+	}
+
+	coefficients := make([]byte, k)
+	for b, s := range secret {
+		coefficients[0] = s
+		if _, err := rand.Read(coefficients[1:]); err != nil {
+			return nil, fmt.Errorf("failed to generate share polynomial: %w", err)
+		}
+		for i := range shares {
+			x := byte(i + 1)
+			// Horner's method, highest coefficient first
+			var y byte
+			for c := k - 1; c >= 0; c-- {
+				y = gfMul(y, x) ^ coefficients[c]
+			}
+			shares[i][b] = y
+		}
+	}
+	return shares, nil
+}
+
+// combineShares recovers the secret from shares evaluated at xs.
+// The shares must come from the same split and number at least its threshold;
+// fewer produce a wrong secret, not an error.
+func combineShares(xs []byte, shares [][]byte) ([]byte, error) {
+	if len(xs) == 0 || len(xs) != len(shares) {
+		return nil, errors.New("no shares to combine")
+	}
+	seen := make(map[byte]bool, len(xs))
+	for i, x := range xs {
+		if x == 0 || seen[x] {
+			return nil, fmt.Errorf("invalid share index %d", x)
+		}
+		seen[x] = true
+		if len(shares[i]) != len(shares[0]) {
+			return nil, errors.New("shares have different lengths")
+		}
+	}
+
+	secret := make([]byte, len(shares[0]))
+	for i, xi := range xs {
+		// Lagrange basis polynomial for xi, evaluated at 0
+		basis := byte(1)
+		for j, xj := range xs {
+			if j != i {
+				basis = gfMul(basis, gfMul(xj, gfInv(xj^xi)))
+			}
+		}
+		for b := range secret {
+			secret[b] ^= gfMul(shares[i][b], basis)
+		}
+	}
+	return secret, nil
+}
@@ -6,11 +6,20 @@ import (
 	"encoding/base64"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
+	"strconv"
+	"strings"
 	"time"
 
+	"seal/internal/clock"
+
+	"github.com/drand/drand/v2/common"
+	"github.com/drand/drand/v2/common/chain"
+	"github.com/drand/drand/v2/crypto"
 	"github.com/drand/tlock"
 	thttp "github.com/drand/tlock/networks/http"
 )
@@ -42,6 +51,16 @@ func (p *PlaceholderAuthority) RoundAt(unlockTime time.Time) (uint64, error) {
 	return 0, nil
 }
 
+func (p *PlaceholderAuthority) RoundTime(round uint64) (time.Time, error) {
+	// Placeholder has no round schedule - it never unlocks
+	return time.Time{}, fmt.Errorf("placeholder authority has no round schedule")
+}
+
+func (p *PlaceholderAuthority) EarliestUnlockTime(ref KeyReference) (time.Time, error) {
+	// Placeholder never unlocks
+	return time.Time{}, fmt.Errorf("placeholder authority never unlocks")
+}
+
 func (p *PlaceholderAuthority) TimeLockEncrypt(data []byte, targetRound uint64) (string, error) {
 	// Placeholder doesn't support time-lock encryption
 	// Return empty string to indicate no tlock support (preserves old behavior)
@@ -54,6 +73,11 @@ func (p *PlaceholderAuthority) TimeLockDecrypt(ctx context.Context, ciphertextB6
 	return nil, fmt.Errorf("placeholder authority does not support time-lock decryption")
 }
 
+func (p *PlaceholderAuthority) LatestRound(ctx context.Context) (uint64, error) {
+	// Placeholder doesn't publish rounds
+	return 0, fmt.Errorf("placeholder authority does not publish rounds")
+}
+
 func (p *PlaceholderAuthority) CanUnlock(ctx context.Context, targetRound uint64) (bool, error) {
 	// Always returns false - no unlocking permitted
 	return false, nil
@@ -77,6 +101,8 @@ func (p *PlaceholderAuthority) CanUnlockRef(ref KeyReference, now time.Time) (bo
 type DrandKeyReference struct {
 	Network     string `json:"network"`
 	TargetRound uint64 `json:"target_round"`
+	ChainHash   string `json:"chain_hash,omitempty"` // absent before network selection (quicknet)
+	Relay       string `json:"relay,omitempty"`
 }
 
 // HTTPDoer is an interface for making HTTP requests.
@@ -93,17 +119,23 @@ type TimelockBox interface {
 
 	// Decrypt decrypts the tlock ciphertext.
 	// Ciphertext is base64-encoded.
-	Decrypt(ciphertextB64 string) ([]byte, error)
+	// Gives up when ctx is done.
+	Decrypt(ctx context.Context, ciphertextB64 string) ([]byte, error)
 }
 
 // DrandAuthority is a time authority based on the drand public randomness beacon.
 type DrandAuthority struct {
 	NetworkName string
 	BaseURL     string
+	Fallbacks   []string // further base URLs serving the same chain, tried in order when BaseURL fails
 	ChainHash   string
 	HTTPClient  HTTPDoer    // injectable HTTP client
 	Timelock    TimelockBox // injectable tlock implementation
 	info        *DrandInfo  // cached network info
+	chain       *chain.Info // verified chain info, for checking round signatures
+	scheme      *crypto.Scheme
+	latest      uint64        // latest round fetched by this instance
+	latestAt    clock.Reading // when it was fetched; zero if never
 }
 
 type DrandInfo struct {
@@ -116,8 +148,10 @@ type DrandInfo struct {
 }
 
 type drandPublicResponse struct {
-	Round      uint64 `json:"round"`
-	Randomness string `json:"randomness"`
+	Round             uint64 `json:"round"`
+	Randomness        string `json:"randomness"`
+	Signature         string `json:"signature"`
+	PreviousSignature string `json:"previous_signature,omitempty"` // chained schemes only
 }
 
 func (d *DrandAuthority) Name() string {
@@ -127,13 +161,13 @@ func (d *DrandAuthority) Name() string {
 // RoundAt calculates the drand round number for a given unlock time.
 func (d *DrandAuthority) RoundAt(unlockTime time.Time) (uint64, error) {
 	// Fetch network info to get period and genesis time
-	info, err := d.FetchInfo()
+	info, err := d.FetchInfo(context.Background())
 	if err != nil {
 		return 0, fmt.Errorf("failed to fetch drand info: %w", err)
 	}
 
 	// Calculate target round for the unlock time
-	// Round number = (unix_time - genesis_time) / period
+	// Round 1 is published at genesis_time, round N at genesis_time + (N-1) * period
 	unlockUnix := unlockTime.Unix()
 	elapsedSeconds := unlockUnix - info.GenesisTime
 
@@ -141,7 +175,7 @@ func (d *DrandAuthority) RoundAt(unlockTime time.Time) (uint64, error) {
 		return 0, fmt.Errorf("unlock time is before drand genesis")
 	}
 
-	targetRound := uint64(elapsedSeconds) / uint64(info.Period)
+	targetRound := uint64(elapsedSeconds)/uint64(info.Period) + 1
 
 	// Round up to ensure we're at or after the unlock time
 	if uint64(elapsedSeconds)%uint64(info.Period) != 0 {
@@ -151,6 +185,27 @@ func (d *DrandAuthority) RoundAt(unlockTime time.Time) (uint64, error) {
 	return targetRound, nil
 }
 
+// RoundTime calculates the wall-clock time at which a drand round is published.
+// Round 1 is published at genesis_time, round N at genesis_time + (N-1) * period.
+func (d *DrandAuthority) RoundTime(round uint64) (time.Time, error) {
+	info, err := d.FetchInfo(context.Background())
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to fetch drand info: %w", err)
+	}
+
+	return time.Unix(common.TimeOfRound(time.Duration(info.Period)*time.Second, info.GenesisTime, round), 0).UTC(), nil
+}
+
+// EarliestUnlockTime returns when the key reference's target round is published.
+func (d *DrandAuthority) EarliestUnlockTime(ref KeyReference) (time.Time, error) {
+	round, err := TargetRound(ref)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return d.RoundTime(round)
+}
+
 // TimeLockEncrypt encrypts data using tlock to the specified round.
 func (d *DrandAuthority) TimeLockEncrypt(data []byte, targetRound uint64) (string, error) {
 	return d.Timelock.Encrypt(data, targetRound)
@@ -158,12 +213,45 @@ func (d *DrandAuthority) TimeLockEncrypt(data []byte, targetRound uint64) (strin
 
 // TimeLockDecrypt decrypts time-locked data using drand randomness.
 func (d *DrandAuthority) TimeLockDecrypt(ctx context.Context, ciphertextB64 string) ([]byte, error) {
-	return d.Timelock.Decrypt(ciphertextB64)
+	ctx, cancel := context.WithTimeout(ctx, NetworkTimeout)
+	defer cancel()
+
+	return d.Timelock.Decrypt(ctx, ciphertextB64)
+}
+
+// LatestRound returns the most recent round published by drand.
+// A round fetched less than one period ago, by this or an earlier run, is reused.
+// One from an earlier run is only as recent as the local clock says.
+func (d *DrandAuthority) LatestRound(ctx context.Context) (uint64, error) {
+	if round, ok := d.freshRound(); ok {
+		return round, nil
+	}
+
+	currentRound, err := d.fetchLatestRound(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to fetch latest round: %w", err)
+	}
+
+	return currentRound, nil
 }
 
 // CanUnlock checks if the target round has been reached.
+// With a cache, a round already seen is published, and a round scheduled well in
+// the future by the local clock is not; neither needs a request. Otherwise the
+// round this instance fetched less than one period ago answers, so items checked
+// together share one request.
 func (d *DrandAuthority) CanUnlock(ctx context.Context, targetRound uint64) (bool, error) {
-	currentRound, err := d.fetchLatestRound()
+	if known, ok := d.knownRound(); ok && targetRound <= known {
+		return true, nil
+	}
+	if d.notDueYet(targetRound) {
+		return false, nil
+	}
+	if round, ok := d.recentRound(); ok {
+		return round >= targetRound, nil
+	}
+
+	currentRound, err := d.fetchLatestRound(ctx)
 	if err != nil {
 		return false, fmt.Errorf("failed to fetch latest round: %w", err)
 	}
@@ -183,6 +271,8 @@ func (d *DrandAuthority) Lock(unlockTime time.Time) (KeyReference, error) {
 	ref := DrandKeyReference{
 		Network:     d.NetworkName,
 		TargetRound: targetRound,
+		ChainHash:   d.ChainHash,
+		Relay:       d.relay(),
 	}
 
 	refJSON, err := json.Marshal(ref)
@@ -211,97 +301,258 @@ func (d *DrandAuthority) CanUnlockRef(ref KeyReference, now time.Time) (bool, er
 	return d.CanUnlock(context.Background(), drandRef.TargetRound)
 }
 
-func (d *DrandAuthority) FetchInfo() (*DrandInfo, error) {
+// ChainMismatchError means a relay serves a different chain than the one pinned.
+type ChainMismatchError struct {
+	Network, Pinned, Served string
+}
+
+func (e *ChainMismatchError) Error() string {
+	return fmt.Sprintf("drand %s serves chain %s, but this build pins %s; refusing to continue", e.Network, e.Served, e.Pinned)
+}
+
+// FetchInfo fetches the chain info and checks it against the pinned chain hash.
+// The hash is recomputed from the info rather than taken from the relay, so the
+// public key used to verify rounds is the pinned chain's.
+// Chain info kept in CacheDir by an earlier run is used instead if it verifies.
+func (d *DrandAuthority) FetchInfo(ctx context.Context) (*DrandInfo, error) {
 	// Return cached info if available
 	if d.info != nil {
 		return d.info, nil
 	}
 
-	url := d.BaseURL + "/info"
-	req, err := http.NewRequest("GET", url, nil)
+	if body, ok := d.readCache(infoCacheKind); ok {
+		if err := d.setInfo(body); err == nil {
+			return d.info, nil
+		}
+	}
+
+	var fetched []byte
+	err := d.fetch(ctx, "/info", func(body []byte) error {
+		if err := d.setInfo(body); err != nil {
+			return err
+		}
+		fetched = body
+		return nil
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	resp, err := d.HTTPClient.Do(req)
+	d.writeCache(infoCacheKind, fetched)
+	return d.info, nil
+}
+
+// setInfo verifies chain info as served at /info and keeps it.
+func (d *DrandAuthority) setInfo(body []byte) error {
+	var info DrandInfo
+	if err := json.Unmarshal(body, &info); err != nil {
+		return err
+	}
+
+	chainInfo, scheme, err := verifyChainInfo(body, d.NetworkName, d.ChainHash)
 	if err != nil {
-		return nil, err
+		return err
 	}
-	defer resp.Body.Close()
+	info.Hash = chainInfo.HashString()
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("drand info request failed: %d", resp.StatusCode)
+	d.info = &info
+	d.chain = chainInfo
+	d.scheme = scheme
+	return nil
+}
+
+func (d *DrandAuthority) fetchLatestRound(ctx context.Context) (uint64, error) {
+	beacon, err := d.fetchBeacon(ctx, "latest")
+	if err != nil {
+		return 0, err
 	}
 
-	body, err := io.ReadAll(resp.Body)
+	d.latest, d.latestAt = beacon.Round, clock.Now()
+	d.saveRound(beacon.Round)
+	return beacon.Round, nil
+}
+
+func (d *DrandAuthority) fetchRoundRandomness(ctx context.Context, round uint64) ([]byte, error) {
+	beacon, err := d.fetchBeacon(ctx, strconv.FormatUint(round, 10))
 	if err != nil {
 		return nil, err
 	}
+	if beacon.Round != round {
+		return nil, fmt.Errorf("drand relay answered round %d with round %d", round, beacon.Round)
+	}
 
-	var info DrandInfo
-	if err := json.Unmarshal(body, &info); err != nil {
+	return beacon.GetRandomness(), nil
+}
+
+// fetchBeacon fetches a round ("latest" or a number) and verifies its signature
+// against the chain's public key, so a relay cannot fake round numbers or randomness.
+func (d *DrandAuthority) fetchBeacon(ctx context.Context, round string) (*common.Beacon, error) {
+	if _, err := d.FetchInfo(ctx); err != nil {
+		return nil, fmt.Errorf("failed to fetch drand info: %w", err)
+	}
+
+	// A relay whose round does not verify is skipped like an unreachable one
+	var beacon *common.Beacon
+	err := d.fetch(ctx, "/public/"+round, func(body []byte) error {
+		var publicResp drandPublicResponse
+		if err := json.Unmarshal(body, &publicResp); err != nil {
+			return err
+		}
+
+		var err error
+		beacon, err = verifyBeacon(d.scheme, d.chain, publicResp)
+		return err
+	})
+	if err != nil {
 		return nil, err
 	}
 
-	d.info = &info
-	return &info, nil
+	return beacon, nil
 }
 
-func (d *DrandAuthority) fetchLatestRound() (uint64, error) {
-	url := d.BaseURL + "/public/latest"
-	req, err := http.NewRequest("GET", url, nil)
+// NetworkTimeout bounds each drand operation, such as fetching the latest round or
+// decrypting a key: every relay, retry and wait between retries happens within it.
+// The CLI sets it from --timeout.
+var NetworkTimeout = 30 * time.Second
+
+// drandRequestTimeout bounds each request to a relay, so a stalled relay fails over.
+const drandRequestTimeout = 10 * time.Second
+
+// drandRetries is how many times a round of requests to every relay is retried
+// after a transient failure, waiting drandRetryBackoff and then twice as long each time.
+const drandRetries = 2
+
+var drandRetryBackoff = 500 * time.Millisecond
+
+// transientError is a failure worth retrying: the relay was unreachable or overloaded.
+// Anything else, such as a round that fails verification, fails the same way again.
+type transientError struct {
+	err error
+}
+
+func (e *transientError) Error() string { return e.err.Error() }
+func (e *transientError) Unwrap() error { return e.err }
+
+// IsNetworkError reports whether err means a time authority could not be reached:
+// a relay that was unreachable, overloaded or timed out. Anything else, such as a
+// round that fails verification, is not a network error.
+func IsNetworkError(err error) bool {
+	var transient *transientError
+	var netErr net.Error
+	return errors.As(err, &transient) || errors.As(err, &netErr) || errors.Is(err, context.DeadlineExceeded)
+}
+
+// fetch gets path from the first relay that answers it with a body parse accepts,
+// trying BaseURL and then each fallback in order, and retrying with exponential
+// backoff while failures are transient. Returns every relay's error if none succeeds
+// within NetworkTimeout.
+func (d *DrandAuthority) fetch(ctx context.Context, path string, parse func(body []byte) error) error {
+	ctx, cancel := context.WithTimeout(ctx, NetworkTimeout)
+	defer cancel()
+
+	bases := append([]string{d.BaseURL}, d.Fallbacks...)
+	wait := drandRetryBackoff
+
+	for attempt := 0; ; attempt++ {
+		var errs []error
+		transient := false
+		for _, base := range bases {
+			err := d.fetchFrom(ctx, base+path, parse)
+			if err == nil {
+				return nil
+			}
+
+			var t *transientError
+			transient = transient || errors.As(err, &t)
+			if len(bases) > 1 {
+				err = fmt.Errorf("%s: %w", strings.TrimSuffix(base, "/"+d.ChainHash), err)
+			}
+			errs = append(errs, err)
+		}
+
+		if !transient || attempt == drandRetries {
+			return errors.Join(errs...)
+		}
+
+		select {
+		case <-ctx.Done():
+			return errors.Join(append(errs, fmt.Errorf("gave up after %s", NetworkTimeout))...)
+		case <-time.After(wait):
+		}
+		wait *= 2
+	}
+}
+
+// fetchFrom gets a single URL and hands its body to parse.
+func (d *DrandAuthority) fetchFrom(ctx context.Context, url string, parse func(body []byte) error) error {
+	ctx, cancel := context.WithTimeout(ctx, drandRequestTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
-		return 0, err
+		return err
 	}
 
 	resp, err := d.HTTPClient.Do(req)
 	if err != nil {
-		return 0, err
+		return &transientError{err}
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return 0, fmt.Errorf("drand latest round request failed: %d", resp.StatusCode)
+		err := fmt.Errorf("drand request %s failed: %d", url, resp.StatusCode)
+		if resp.StatusCode >= 500 || resp.StatusCode == http.StatusTooManyRequests {
+			return &transientError{err}
+		}
+		return err
 	}
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return 0, err
-	}
-
-	var publicResp drandPublicResponse
-	if err := json.Unmarshal(body, &publicResp); err != nil {
-		return 0, err
+		return &transientError{err}
 	}
 
-	return publicResp.Round, nil
+	return parse(body)
 }
 
-func (d *DrandAuthority) fetchRoundRandomness(round uint64) ([]byte, error) {
-	url := fmt.Sprintf("%s/public/%d", d.BaseURL, round)
-	req, err := http.NewRequest("GET", url, nil)
+// verifyChainInfo parses chain info as served at /info and checks that it hashes
+// to the pinned chain hash, so its public key can be trusted to verify rounds.
+func verifyChainInfo(body []byte, network, chainHash string) (*chain.Info, *crypto.Scheme, error) {
+	chainInfo, err := chain.InfoFromJSON(bytes.NewReader(body))
 	if err != nil {
-		return nil, err
+		return nil, nil, fmt.Errorf("invalid drand chain info: %w", err)
+	}
+	if served := chainInfo.HashString(); served != chainHash {
+		return nil, nil, &ChainMismatchError{Network: network, Pinned: chainHash, Served: served}
 	}
 
-	resp, err := d.HTTPClient.Do(req)
+	scheme, err := crypto.SchemeFromName(chainInfo.Scheme)
 	if err != nil {
-		return nil, err
+		return nil, nil, fmt.Errorf("unsupported drand scheme %q: %w", chainInfo.Scheme, err)
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("drand round %d request failed: %d", round, resp.StatusCode)
-	}
+	return chainInfo, scheme, nil
+}
 
-	body, err := io.ReadAll(resp.Body)
+// verifyBeacon checks a round's signature against the chain's public key and
+// that its randomness derives from the signature.
+func verifyBeacon(scheme *crypto.Scheme, chainInfo *chain.Info, publicResp drandPublicResponse) (*common.Beacon, error) {
+	signature, err := hex.DecodeString(publicResp.Signature)
+	if err != nil || len(signature) == 0 {
+		return nil, fmt.Errorf("drand round %d has no valid signature", publicResp.Round)
+	}
+	previous, err := hex.DecodeString(publicResp.PreviousSignature)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("drand round %d has a malformed previous signature", publicResp.Round)
 	}
 
-	var publicResp drandPublicResponse
-	if err := json.Unmarshal(body, &publicResp); err != nil {
-		return nil, err
+	beacon := &common.Beacon{
+		Round:       publicResp.Round,
+		Signature:   signature,
+		PreviousSig: previous,
+	}
+	if err := scheme.VerifyBeacon(beacon, chainInfo.PublicKey); err != nil {
+		return nil, fmt.Errorf("drand round %d failed signature verification; the relay may be compromised: %w", publicResp.Round, err)
 	}
 
 	// Decode hex-encoded randomness
@@ -309,58 +560,119 @@ func (d *DrandAuthority) fetchRoundRandomness(round uint64) ([]byte, error) {
 	if err != nil {
 		return nil, fmt.Errorf("failed to decode randomness: %w", err)
 	}
+	if !bytes.Equal(randomness, beacon.GetRandomness()) {
+		return nil, fmt.Errorf("drand round %d randomness does not match its signature", publicResp.Round)
+	}
 
-	return randomness, nil
+	return beacon, nil
 }
 
 // RealTimelockBox implements TimelockBox using the actual tlock library.
 type RealTimelockBox struct {
 	BaseURL   string
+	Fallbacks []string // further relays serving the same chain, tried in order when BaseURL fails
 	ChainHash string
 }
 
 // Encrypt time-locks the DEK using tlock.
 func (r *RealTimelockBox) Encrypt(dek []byte, targetRound uint64) (string, error) {
-	network, err := thttp.NewNetwork(r.BaseURL, r.ChainHash)
-	if err != nil {
-		return "", fmt.Errorf("failed to create tlock network: %w", err)
-	}
-
-	var tlockCiphertext bytes.Buffer
-	dekReader := bytes.NewReader(dek)
+	var ciphertext string
+	err := r.withNetwork(context.Background(), func(network *thttp.Network) error {
+		var tlockCiphertext bytes.Buffer
+		dekReader := bytes.NewReader(dek)
 
-	if err := tlock.New(network).Encrypt(&tlockCiphertext, dekReader, targetRound); err != nil {
-		return "", fmt.Errorf("failed to tlock encrypt DEK: %w", err)
-	}
+		if err := tlock.New(network).Encrypt(&tlockCiphertext, dekReader, targetRound); err != nil {
+			return fmt.Errorf("failed to tlock encrypt DEK: %w", err)
+		}
 
-	return base64.StdEncoding.EncodeToString(tlockCiphertext.Bytes()), nil
+		ciphertext = base64.StdEncoding.EncodeToString(tlockCiphertext.Bytes())
+		return nil
+	})
+	return ciphertext, err
 }
 
-// Decrypt decrypts the tlock ciphertext.
-func (r *RealTimelockBox) Decrypt(ciphertextB64 string) ([]byte, error) {
+// Decrypt decrypts the tlock ciphertext. tlock bounds each of its own requests;
+// ctx bounds the whole decryption, including failover between relays.
+func (r *RealTimelockBox) Decrypt(ctx context.Context, ciphertextB64 string) ([]byte, error) {
 	tlockCiphertext, err := base64.StdEncoding.DecodeString(ciphertextB64)
 	if err != nil {
 		return nil, fmt.Errorf("failed to decode tlock ciphertext: %w", err)
 	}
 
-	network, err := thttp.NewNetwork(r.BaseURL, r.ChainHash)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create tlock network: %w", err)
+	type result struct {
+		dek []byte
+		err error
 	}
+	done := make(chan result, 1)
 
-	var dekBuffer bytes.Buffer
-	tlockReader := bytes.NewReader(tlockCiphertext)
+	go func() {
+		var dek []byte
+		err := r.withNetwork(ctx, func(network *thttp.Network) error {
+			var dekBuffer bytes.Buffer
+			tlockReader := bytes.NewReader(tlockCiphertext)
 
-	if err := tlock.New(network).Decrypt(&dekBuffer, tlockReader); err != nil {
-		return nil, err
+			if err := tlock.New(network).Decrypt(&dekBuffer, tlockReader); err != nil {
+				return err
+			}
+
+			dek = dekBuffer.Bytes()
+			return nil
+		})
+		done <- result{dek, err}
+	}()
+
+	select {
+	case res := <-done:
+		return res.dek, res.err
+	case <-ctx.Done():
+		return nil, fmt.Errorf("tlock decryption gave up: %w", ctx.Err())
 	}
+}
+
+// withNetwork runs fn against the first relay it succeeds with, trying BaseURL and
+// then each fallback in order until ctx is done. Returns every relay's error if none succeeds.
+func (r *RealTimelockBox) withNetwork(ctx context.Context, fn func(network *thttp.Network) error) error {
+	relays := append([]string{r.BaseURL}, r.Fallbacks...)
 
-	return dekBuffer.Bytes(), nil
+	var errs []error
+	for _, relay := range relays {
+		if ctx.Err() != nil {
+			break
+		}
+
+		network, err := thttp.NewNetwork(relay, r.ChainHash)
+		if err != nil {
+			err = fmt.Errorf("failed to create tlock network: %w", err)
+		} else {
+			err = fn(network)
+		}
+		if err == nil {
+			return nil
+		}
+		if len(relays) > 1 {
+			err = fmt.Errorf("%s: %w", relay, err)
+		}
+		errs = append(errs, err)
+	}
+	if ctx.Err() != nil {
+		errs = append(errs, ctx.Err())
+	}
+
+	return errors.Join(errs...)
 }
 
 // drandQuicknetChainHash is the chain hash for drand quicknet.
 const drandQuicknetChainHash = "52db9ba70e0cc0f6eaf7803dd07447a1f5477735fd3f661792ba94600c84e971"
 
+// PinnedDrandChains returns the drand chain hashes compiled into this build, by network name.
+func PinnedDrandChains() map[string]string {
+	chains := make(map[string]string, len(drandNetworks))
+	for name, network := range drandNetworks {
+		chains[name] = network.ChainHash
+	}
+	return chains
+}
+
 // NewDrandAuthority creates a drand authority for the quicknet network.
 func NewDrandAuthority() *DrandAuthority {
 	return NewDrandAuthorityWithDeps(http.DefaultClient, nil)
@@ -368,19 +680,37 @@ func NewDrandAuthority() *DrandAuthority {
 
 // NewDrandAuthorityWithDeps creates a drand authority with injectable dependencies.
 func NewDrandAuthorityWithDeps(httpClient HTTPDoer, timelock TimelockBox) *DrandAuthority {
-	baseURL := "https://api.drand.sh/" + drandQuicknetChainHash
+	return newDrandAuthorityForChain("quicknet", defaultDrandRelay, drandQuicknetChainHash, httpClient, timelock)
+}
 
+// NewDrandAuthorityForNetworkWithDeps creates a drand authority for a network with injectable dependencies.
+func NewDrandAuthorityForNetworkWithDeps(network DrandNetwork, httpClient HTTPDoer, timelock TimelockBox) *DrandAuthority {
+	return newDrandAuthorityForChain(network.Name, network.Relay, network.ChainHash, httpClient, timelock)
+}
+
+// newDrandAuthorityForChain creates a drand authority for the chain served at host,
+// failing over to the chain's public fallback relays. A nil timelock uses real tlock
+// against the same relays and chain.
+func newDrandAuthorityForChain(network, host, chainHash string, httpClient HTTPDoer, timelock TimelockBox) *DrandAuthority {
+	fallbacks := fallbackRelays(chainHash, host)
 	if timelock == nil {
 		timelock = &RealTimelockBox{
-			BaseURL:   "https://api.drand.sh",
-			ChainHash: drandQuicknetChainHash,
+			BaseURL:   host,
+			Fallbacks: fallbacks,
+			ChainHash: chainHash,
 		}
 	}
 
+	fallbackURLs := make([]string, len(fallbacks))
+	for i, relay := range fallbacks {
+		fallbackURLs[i] = relay + "/" + chainHash
+	}
+
 	return &DrandAuthority{
-		NetworkName: "quicknet",
-		BaseURL:     baseURL,
-		ChainHash:   drandQuicknetChainHash,
+		NetworkName: network,
+		BaseURL:     host + "/" + chainHash,
+		Fallbacks:   fallbackURLs,
+		ChainHash:   chainHash,
 		HTTPClient:  httpClient,
 		Timelock:    timelock,
 	}
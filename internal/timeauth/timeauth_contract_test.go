@@ -63,6 +63,32 @@ func TestAuthorityContract_CanUnlock(t *testing.T) {
 	}
 }
 
+// TestAuthorityContract_EarliestUnlockTime verifies that the earliest unlock time
+// is when the key reference's target round is published
+func TestAuthorityContract_EarliestUnlockTime(t *testing.T) {
+	genesis := time.Date(2023, 3, 1, 13, 0, 0, 0, time.UTC)
+	fake := &FakeAuthority{GenesisTime: genesis, Period: 3 * time.Second}
+
+	for _, ref := range []KeyReference{`{"network":"fake","target_round":1000}`, "1000"} {
+		unlockAt, err := fake.EarliestUnlockTime(ref)
+		if err != nil {
+			t.Fatalf("EarliestUnlockTime(%s) failed: %v", ref, err)
+		}
+
+		if want := genesis.Add(999 * 3 * time.Second); !unlockAt.Equal(want) {
+			t.Errorf("EarliestUnlockTime(%s) = %v, want %v", ref, unlockAt, want)
+		}
+	}
+
+	if _, err := fake.EarliestUnlockTime("not-a-reference"); err == nil {
+		t.Error("expected error for invalid key reference")
+	}
+
+	if _, err := (&PlaceholderAuthority{}).EarliestUnlockTime("placeholder-key-ref"); err == nil {
+		t.Error("placeholder authority should never report an unlock time")
+	}
+}
+
 func TestPlaceholderAuthority_Name(t *testing.T) {
 	authority := &PlaceholderAuthority{}
 	
@@ -0,0 +1,256 @@
+// Package seal is the public Go API of Seal: time-locked commitments kept in a
+// local store, the same store the seal command uses.
+//
+// The API is deliberately small: lock data, list and verify items, and open them
+// once their time has come. There is no way to extend, undo or unlock early,
+// here or anywhere else.
+//
+// Functions act on the store the seal command would use: $SEAL_DATA_DIR if set,
+// otherwise the OS-appropriate default (or where seal move-store moved it).
+// Contexts bound requests to the time authority; operations that only touch the
+// store check the context before they start.
+package seal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"time"
+
+	"seal/internal/clock"
+	core "seal/internal/seal"
+	"seal/internal/timeauth"
+)
+
+// Item states.
+const (
+	StateSealed   = core.StateSealed
+	StateUnlocked = core.StateUnlocked
+)
+
+// List orders.
+const (
+	SortCreatedAt  = core.SortCreatedAt
+	SortUnlockTime = core.SortUnlockTime
+)
+
+// ErrStillSealed is returned by Open for an item that has not unlocked yet.
+var ErrStillSealed = core.ErrStillSealed
+
+// TimeAuthority is a source of verifiable time that data is locked to, such as drand.
+type TimeAuthority = timeauth.Authority
+
+// KeyReference is the authority-specific unlock information stored with an item.
+type KeyReference = timeauth.KeyReference
+
+// RegisterAuthority makes authority available under name, for LockOptions.Authority
+// and for opening items it sealed. Names must be unique; drand is built in.
+// Call it from an init function: items sealed by an authority that is not
+// registered can never be opened.
+// Items record authority.Name() and are opened by it, so name must be the same;
+// like a duplicate or empty name, anything else panics.
+func RegisterAuthority(name string, authority TimeAuthority) {
+	if authority == nil {
+		panic("seal: nil authority registered as " + name)
+	}
+	if authority.Name() != name {
+		panic(fmt.Sprintf("seal: authority %q registered as %q; items record and are opened by the authority's own name", authority.Name(), name))
+	}
+	timeauth.Register(name, timeauth.Registration{
+		New:    func(timeauth.Options) (timeauth.Authority, error) { return authority, nil },
+		ForRef: func(timeauth.KeyReference) timeauth.Authority { return authority },
+	})
+}
+
+// Item describes a sealed item. Items never carry their content.
+type Item struct {
+	ID            string
+	State         string // StateSealed or StateUnlocked, as last recorded
+	UnlockTime    time.Time
+	CreatedAt     time.Time
+	TimeAuthority string
+	Tags          map[string]string
+	Note          string
+}
+
+// LockOptions describes how data is sealed. Exactly one of Until and For is required.
+type LockOptions struct {
+	Until     time.Time         // unlock at this time
+	For       time.Duration     // unlock this long from now
+	Tags      map[string]string // stored in plain text in the metadata
+	Note      string            // one-line description, stored in plain text
+	Authority string            // registered time authority (default drand)
+	Network   string            // authority network, e.g. quicknet or testnet for drand
+}
+
+// LockResult is a newly sealed item.
+type LockResult struct {
+	Item     Item
+	Warnings []string // non-fatal problems, as printed by seal lock
+}
+
+// ListOptions selects and orders items. The zero value lists every item, oldest first.
+type ListOptions struct {
+	State  string            // only items in this state; empty for all
+	Before time.Time         // only items unlocking before this (zero = no bound)
+	After  time.Time         // only items unlocking after this (zero = no bound)
+	Tags   map[string]string // only items carrying all these tags
+	Sort   string            // SortCreatedAt (default) or SortUnlockTime
+	Limit  int               // at most this many items (0 = no limit)
+}
+
+// VerifyResult is the outcome of verifying one item.
+type VerifyResult struct {
+	ID       string
+	Problems []string // empty if the item passed
+}
+
+// Store is the set of operations on a store. DefaultStore returns the store the
+// package functions use; programs can depend on Store to substitute a fake in tests.
+type Store interface {
+	Lock(ctx context.Context, data []byte, opts LockOptions) (LockResult, error)
+	Open(ctx context.Context, id string) (io.ReadCloser, error)
+	List(ctx context.Context, opts ListOptions) ([]Item, error)
+	Verify(ctx context.Context, id string) ([]VerifyResult, error)
+}
+
+// DefaultStore returns the store the seal command uses.
+func DefaultStore() Store {
+	return defaultStore{}
+}
+
+type defaultStore struct{}
+
+func (defaultStore) Lock(ctx context.Context, data []byte, opts LockOptions) (LockResult, error) {
+	return Lock(ctx, data, opts)
+}
+
+func (defaultStore) Open(ctx context.Context, id string) (io.ReadCloser, error) {
+	return Open(ctx, id)
+}
+
+func (defaultStore) List(ctx context.Context, opts ListOptions) ([]Item, error) {
+	return List(ctx, opts)
+}
+
+func (defaultStore) Verify(ctx context.Context, id string) ([]VerifyResult, error) {
+	return Verify(ctx, id)
+}
+
+// Lock encrypts data and seals it until the time given in opts.
+// The lock-time policy applies as for seal lock.
+func Lock(ctx context.Context, data []byte, opts LockOptions) (LockResult, error) {
+	if err := ctx.Err(); err != nil {
+		return LockResult{}, err
+	}
+
+	var unlockTime time.Time
+	switch {
+	case opts.Until.IsZero() == (opts.For == 0):
+		return LockResult{}, errors.New("exactly one of Until and For is required")
+	case opts.For < 0:
+		return LockResult{}, fmt.Errorf("invalid lock duration %s", opts.For)
+	case opts.For > 0:
+		unlockTime = clock.UTC().Add(opts.For)
+	default:
+		unlockTime = opts.Until
+	}
+
+	tags := make([]string, 0, len(opts.Tags))
+	for key, value := range opts.Tags {
+		tags = append(tags, key+"="+value)
+	}
+	sort.Strings(tags)
+
+	result, err := core.Lock(core.LockRequest{
+		Data:       data,
+		UnlockTime: unlockTime.UTC().Format(time.RFC3339Nano),
+		Stdin:      core.StdinNever,
+		Tags:       tags,
+		Note:       opts.Note,
+		Authority:  opts.Authority,
+		Network:    opts.Network,
+	})
+	if err != nil {
+		return LockResult{}, err
+	}
+
+	item, _, err := core.LoadItem(result.ID)
+	if err != nil {
+		return LockResult{}, err
+	}
+	return LockResult{Item: newItem(item), Warnings: result.Warnings}, nil
+}
+
+// Open returns the content of an unlocked item. A sealed item is checked and
+// unlocked first if its time has come, exactly as seal open does it.
+// The content is verified against the hash recorded at unlock.
+// Returns an error wrapping ErrStillSealed if the item has not unlocked yet.
+func Open(ctx context.Context, id string) (io.ReadCloser, error) {
+	result, err := core.OpenContext(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return os.Open(result.Path)
+}
+
+// List returns the items in the store matching opts. Nothing is unlocked:
+// states are as last recorded, and Open checks an item again.
+func List(ctx context.Context, opts ListOptions) ([]Item, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	items, err := core.ListSealedItems(core.ListOptions{
+		State:  opts.State,
+		Before: opts.Before,
+		After:  opts.After,
+		Tags:   opts.Tags,
+		Sort:   opts.Sort,
+		Limit:  opts.Limit,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]Item, len(items))
+	for i, item := range items {
+		result[i] = newItem(item)
+	}
+	return result, nil
+}
+
+// Verify checks one item, or every item if id is empty, against the hashes
+// recorded for it, without unlocking anything. See seal verify.
+func Verify(ctx context.Context, id string) ([]VerifyResult, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	results, err := core.Verify(id)
+	if err != nil {
+		return nil, err
+	}
+
+	verified := make([]VerifyResult, len(results))
+	for i, r := range results {
+		verified[i] = VerifyResult{ID: r.ID, Problems: r.Problems}
+	}
+	return verified, nil
+}
+
+// newItem returns the public view of an item's metadata.
+func newItem(item core.SealedItem) Item {
+	return Item{
+		ID:            item.ID,
+		State:         item.State,
+		UnlockTime:    item.UnlockTime,
+		CreatedAt:     item.CreatedAt,
+		TimeAuthority: item.TimeAuthority,
+		Tags:          item.Tags,
+		Note:          item.Note,
+	}
+}
@@ -0,0 +1,94 @@
+package seal
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"seal/internal/testutil"
+	"seal/internal/timeauth"
+)
+
+func init() {
+	// At round 200: items locked to round 100 open, items locked to round 300 do not
+	RegisterAuthority("pkg-fake", &timeauth.FakeAuthority{AuthorityName: "pkg-fake", DefaultRound: 100, CurrentRound: 200})
+	RegisterAuthority("pkg-fake-later", &timeauth.FakeAuthority{AuthorityName: "pkg-fake-later", DefaultRound: 300, CurrentRound: 200})
+}
+
+func TestLockListVerifyOpen(t *testing.T) {
+	_, cleanup := testutil.SetupTestEnv(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	var store Store = DefaultStore()
+	locked, err := store.Lock(ctx, []byte("library data"), LockOptions{For: time.Hour, Authority: "pkg-fake", Tags: map[string]string{"project": "x"}, Note: "from Go"})
+	if err != nil {
+		t.Fatalf("Lock failed: %v", err)
+	}
+	if locked.Item.State != StateSealed || locked.Item.Tags["project"] != "x" || locked.Item.Note != "from Go" {
+		t.Errorf("unexpected item: %+v", locked.Item)
+	}
+	later, err := Lock(ctx, []byte("later data"), LockOptions{Until: time.Now().Add(2 * time.Hour), Authority: "pkg-fake-later"})
+	if err != nil {
+		t.Fatalf("Lock failed: %v", err)
+	}
+
+	items, err := store.List(ctx, ListOptions{Sort: SortUnlockTime, Tags: map[string]string{"project": "x"}})
+	if err != nil || len(items) != 1 || items[0].ID != locked.Item.ID {
+		t.Errorf("expected only the tagged item, got %+v, %v", items, err)
+	}
+
+	results, err := store.Verify(ctx, "")
+	if err != nil || len(results) != 2 || len(results[0].Problems)+len(results[1].Problems) != 0 {
+		t.Errorf("expected both items to verify, got %+v, %v", results, err)
+	}
+
+	content, err := store.Open(ctx, locked.Item.ID)
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	data, _ := io.ReadAll(content)
+	content.Close()
+	if string(data) != "library data" {
+		t.Errorf("unexpected content %q", data)
+	}
+
+	if _, err := Open(ctx, later.Item.ID); !errors.Is(err, ErrStillSealed) {
+		t.Errorf("expected ErrStillSealed, got %v", err)
+	}
+}
+
+func TestLock_Options(t *testing.T) {
+	_, cleanup := testutil.SetupTestEnv(t)
+	defer cleanup()
+
+	for _, opts := range []LockOptions{
+		{Authority: "pkg-fake"},
+		{For: time.Hour, Until: time.Now().Add(time.Hour), Authority: "pkg-fake"},
+		{For: -time.Hour, Authority: "pkg-fake"},
+	} {
+		if _, err := Lock(context.Background(), []byte("data"), opts); err == nil {
+			t.Errorf("expected %+v to be refused", opts)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := Lock(ctx, []byte("data"), LockOptions{For: time.Hour, Authority: "pkg-fake"}); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected a cancelled context to be refused, got %v", err)
+	}
+	if items, _ := List(context.Background(), ListOptions{}); len(items) != 0 {
+		t.Errorf("expected nothing to be sealed, got %+v", items)
+	}
+}
+
+func TestRegisterAuthority_RequiresMatchingName(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("expected a name that differs from the authority's to panic")
+		}
+	}()
+	RegisterAuthority("pkg-renamed", &timeauth.FakeAuthority{AuthorityName: "pkg-fake-original"})
+}